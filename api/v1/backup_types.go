@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -43,6 +44,24 @@ const (
 	BackupPhaseWalArchivingFailing = "walArchivingFailing"
 )
 
+// BackupConditionType defines types of backup conditions
+type BackupConditionType string
+
+const (
+	// BackupConditionCompleted represents whether the backup has been
+	// completed successfully
+	BackupConditionCompleted BackupConditionType = "Completed"
+)
+
+const (
+	// ConditionReasonBackupCompleted means that the backup has been
+	// completed successfully
+	ConditionReasonBackupCompleted ConditionReason = "BackupCompleted"
+
+	// ConditionReasonBackupFailed means that the backup failed
+	ConditionReasonBackupFailed ConditionReason = "BackupFailed"
+)
+
 // BackupSpec defines the desired state of Backup
 type BackupSpec struct {
 	// The cluster to backup
@@ -110,6 +129,33 @@ type BackupStatus struct {
 
 	// Information to identify the instance where the backup has been taken from
 	InstanceID *InstanceID `json:"instanceID,omitempty"`
+
+	// The SHA-256 checksums of the WAL files required by the backup,
+	// computed before uploading them, indexed by the name of the WAL file
+	WALChecksums map[string]string `json:"walChecksums,omitempty"`
+
+	// The result of the last verification of the backup artifacts
+	Verification *BackupVerification `json:"verification,omitempty"`
+
+	// Conditions for the backup object
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// BackupVerification is the result of the verification of the artifacts
+// of a backup in the object store
+type BackupVerification struct {
+	// When the verification has been executed
+	Time metav1.Time `json:"time"`
+
+	// The problems found in the object store, empty when
+	// the backup artifacts are intact
+	Errors []string `json:"errors,omitempty"`
+}
+
+// IsSuccessful checks whether the verification found no problems
+func (verification *BackupVerification) IsSuccessful() bool {
+	return verification != nil && len(verification.Errors) == 0
 }
 
 // InstanceID contains the information to identify an instance
@@ -165,12 +211,29 @@ func (backupStatus *BackupStatus) SetAsFailed(
 	} else {
 		backupStatus.Error = ""
 	}
+
+	message := backupStatus.Error
+	if message == "" {
+		message = "The backup failed"
+	}
+	meta.SetStatusCondition(&backupStatus.Conditions, metav1.Condition{
+		Type:    string(BackupConditionCompleted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(ConditionReasonBackupFailed),
+		Message: message,
+	})
 }
 
 // SetAsCompleted marks a certain backup as completed
 func (backupStatus *BackupStatus) SetAsCompleted() {
 	backupStatus.Phase = BackupPhaseCompleted
 	backupStatus.Error = ""
+	meta.SetStatusCondition(&backupStatus.Conditions, metav1.Condition{
+		Type:    string(BackupConditionCompleted),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonBackupCompleted),
+		Message: "The backup has been completed",
+	})
 }
 
 // IsDone check if a backup is completed or still in progress
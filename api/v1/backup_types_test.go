@@ -0,0 +1,52 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup status", func() {
+	It("reports a completed backup in its conditions", func() {
+		status := BackupStatus{}
+		status.SetAsCompleted()
+
+		Expect(status.Phase).To(BeEquivalentTo(BackupPhaseCompleted))
+		condition := meta.FindStatusCondition(status.Conditions, string(BackupConditionCompleted))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(BeEquivalentTo(ConditionReasonBackupCompleted))
+	})
+
+	It("reports the error of a failed backup in its conditions", func() {
+		status := BackupStatus{}
+		status.SetAsFailed(fmt.Errorf("cannot reach the object store"))
+
+		Expect(status.Phase).To(BeEquivalentTo(BackupPhaseFailed))
+		condition := meta.FindStatusCondition(status.Conditions, string(BackupConditionCompleted))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(BeEquivalentTo(ConditionReasonBackupFailed))
+		Expect(condition.Message).To(Equal("cannot reach the object store"))
+	})
+})
@@ -17,6 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+
+	"k8s.io/utils/strings/slices"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -65,8 +69,15 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 
 // getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
 func (cluster *Cluster) getElectableSyncReplicas() []string {
+	// Standbys streaming from another standby don't send
+	// feedback to the primary and can't be synchronous
+	cascadingUpstreams := cluster.GetCascadingReplicationUpstreams()
+
 	var nonPrimaryInstances []string
 	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
+		if _, isCascading := cascadingUpstreams[instance]; isCascading {
+			continue
+		}
 		if cluster.Status.CurrentPrimary != instance {
 			nonPrimaryInstances = append(nonPrimaryInstances, instance)
 		}
@@ -112,10 +123,129 @@ func (cluster *Cluster) getElectableSyncReplicas() []string {
 			continue
 		}
 
-		if !currentPrimaryTopology.matchesTopology(instanceTopology) {
+		if !currentPrimaryTopology.matchesTopology(
+			instanceTopology,
+			cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.NodeLabelsAntiAffinity,
+		) {
 			electableReplicas = append(electableReplicas, string(name))
 		}
 	}
 
 	return electableReplicas
 }
+
+// GetTopologyLabelNames gets the names of the node labels that need to be
+// extracted into the cluster topology
+func (cluster *Cluster) GetTopologyLabelNames() []string {
+	labelNames := cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.NodeLabelsAntiAffinity
+	if topologyKey := cluster.getCascadingReplicationTopologyKey(); topologyKey != "" &&
+		!slices.Contains(labelNames, topologyKey) {
+		labelNames = append(slices.Clone(labelNames), topologyKey)
+	}
+	return labelNames
+}
+
+func (cluster *Cluster) getCascadingReplicationTopologyKey() string {
+	if cluster.Spec.Replication == nil || cluster.Spec.Replication.Cascading == nil {
+		return ""
+	}
+	return cluster.Spec.Replication.Cascading.TopologyKey
+}
+
+// GetCascadingUpstream gets the name of the standby the passed instance
+// should stream from, or an empty string if it should stream from the primary
+func (cluster *Cluster) GetCascadingUpstream(instanceName string) string {
+	return cluster.GetCascadingReplicationUpstreams()[instanceName]
+}
+
+// GetCascadingReplicationUpstreams computes the cascading replication topology,
+// returning a map from the name of every standby which should stream from
+// another standby to the name of its upstream.
+// Only healthy standbys streaming from the primary, whose IP address is
+// known, can be chosen as upstream,
+// every other standby streams from the primary. This way the topology
+// is automatically rearranged when instances fail or are promoted
+func (cluster *Cluster) GetCascadingReplicationUpstreams() map[string]string {
+	upstreams := make(map[string]string)
+	if cluster.Spec.Replication == nil || cluster.Spec.Replication.Cascading == nil ||
+		cluster.Status.CurrentPrimary == "" {
+		return upstreams
+	}
+	cascading := cluster.Spec.Replication.Cascading
+
+	// During a switchover or a failover both the current and the
+	// target primary need to stream from the primary
+	isPrimary := func(instanceName string) bool {
+		return instanceName == cluster.Status.CurrentPrimary || instanceName == cluster.Status.TargetPrimary
+	}
+	canBeUpstream := func(instanceName string) bool {
+		return !isPrimary(instanceName) &&
+			slices.Contains(cluster.Status.InstancesStatus[utils.PodHealthy], instanceName) &&
+			cluster.Status.InstancesReportedState[PodName(instanceName)].IP != ""
+	}
+
+	requestedUpstreams := make(map[string]string)
+	for _, rule := range cascading.Instances {
+		if isPrimary(rule.Name) || rule.Name == rule.Upstream || !canBeUpstream(rule.Upstream) {
+			continue
+		}
+		requestedUpstreams[rule.Name] = rule.Upstream
+	}
+
+	// We don't allow chains of cascading standbys: a standby whose
+	// upstream is streaming from another standby streams from the primary
+	explicitUpstreams := make(map[string]bool)
+	for instanceName, upstream := range requestedUpstreams {
+		if _, isCascading := requestedUpstreams[upstream]; isCascading {
+			continue
+		}
+		upstreams[instanceName] = upstream
+		explicitUpstreams[upstream] = true
+	}
+
+	topology := cluster.Status.Topology
+	if cascading.TopologyKey == "" || !topology.SuccessfullyExtracted {
+		return upstreams
+	}
+
+	primaryDomain := topology.Instances[PodName(cluster.Status.CurrentPrimary)][cascading.TopologyKey]
+	domains := make(map[string][]string)
+	for _, instanceName := range cluster.Status.InstanceNames {
+		if isPrimary(instanceName) {
+			continue
+		}
+		if _, isCascading := upstreams[instanceName]; isCascading || explicitUpstreams[instanceName] {
+			continue
+		}
+		domain := topology.Instances[PodName(instanceName)][cascading.TopologyKey]
+		if domain == "" || domain == primaryDomain {
+			continue
+		}
+		domains[domain] = append(domains[domain], instanceName)
+	}
+
+	for _, instances := range domains {
+		sort.Strings(instances)
+
+		// The first healthy standby of the domain streams from the
+		// primary, and every other standby streams from it
+		hub := ""
+		for _, instanceName := range instances {
+			if canBeUpstream(instanceName) {
+				hub = instanceName
+				break
+			}
+		}
+		if hub == "" {
+			continue
+		}
+
+		for _, instanceName := range instances {
+			if instanceName != hub {
+				upstreams[instanceName] = hub
+			}
+		}
+	}
+
+	return upstreams
+}
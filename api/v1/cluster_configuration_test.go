@@ -80,3 +80,134 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
 	})
 })
+
+var _ = Describe("cascading replication topology", func() {
+	const (
+		primaryPod = "example-1"
+		zoneBPod1  = "example-2"
+		zoneBPod2  = "example-3"
+		zoneBPod3  = "example-4"
+	)
+
+	var cluster *Cluster
+	BeforeEach(func() {
+		cluster = createFakeCluster("example")
+		cluster.Spec.Instances = 4
+		cluster.Status.TargetPrimary = primaryPod
+		cluster.Status.InstanceNames = []string{primaryPod, zoneBPod1, zoneBPod2, zoneBPod3}
+		cluster.Status.InstancesStatus[utils.PodHealthy] = cluster.Status.InstanceNames
+		cluster.Status.InstancesReportedState = map[PodName]InstanceReportedState{
+			primaryPod: {IsPrimary: true, IP: "10.0.0.1"},
+			zoneBPod1:  {IP: "10.0.0.2"},
+			zoneBPod2:  {IP: "10.0.0.3"},
+			zoneBPod3:  {IP: "10.0.0.4"},
+		}
+		cluster.Status.Topology = Topology{
+			SuccessfullyExtracted: true,
+			Instances: map[PodName]PodTopologyLabels{
+				primaryPod: {"zone": "a"},
+				zoneBPod1:  {"zone": "b"},
+				zoneBPod2:  {"zone": "b"},
+				zoneBPod3:  {"zone": "b"},
+			},
+		}
+	})
+
+	It("streams every standby from the primary when not configured", func() {
+		Expect(cluster.GetCascadingReplicationUpstreams()).To(BeEmpty())
+		Expect(cluster.GetTopologyLabelNames()).To(BeEmpty())
+	})
+
+	It("uses the explicitly requested upstreams", func() {
+		cluster.Spec.Replication = &ReplicationConfiguration{
+			Cascading: &CascadingReplicationConfiguration{
+				Instances: []CascadingReplicationInstance{
+					{Name: zoneBPod2, Upstream: zoneBPod1},
+					{Name: zoneBPod3, Upstream: primaryPod},
+				},
+			},
+		}
+		Expect(cluster.GetCascadingReplicationUpstreams()).To(Equal(map[string]string{
+			zoneBPod2: zoneBPod1,
+		}))
+		Expect(cluster.GetCascadingUpstream(zoneBPod2)).To(Equal(zoneBPod1))
+		Expect(cluster.GetCascadingUpstream(zoneBPod3)).To(BeEmpty())
+	})
+
+	It("doesn't allow chains of cascading standbys", func() {
+		cluster.Spec.Replication = &ReplicationConfiguration{
+			Cascading: &CascadingReplicationConfiguration{
+				Instances: []CascadingReplicationInstance{
+					{Name: zoneBPod3, Upstream: zoneBPod2},
+					{Name: zoneBPod2, Upstream: zoneBPod1},
+				},
+			},
+		}
+		Expect(cluster.GetCascadingReplicationUpstreams()).To(Equal(map[string]string{
+			zoneBPod2: zoneBPod1,
+		}))
+	})
+
+	It("streams from the primary when the upstream is not healthy", func() {
+		cluster.Spec.Replication = &ReplicationConfiguration{
+			Cascading: &CascadingReplicationConfiguration{
+				Instances: []CascadingReplicationInstance{
+					{Name: zoneBPod2, Upstream: zoneBPod1},
+				},
+			},
+		}
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {primaryPod, zoneBPod2, zoneBPod3},
+			utils.PodFailed:  {zoneBPod1},
+		}
+		Expect(cluster.GetCascadingReplicationUpstreams()).To(BeEmpty())
+	})
+
+	It("streams from the primary when promoted", func() {
+		cluster.Spec.Replication = &ReplicationConfiguration{
+			Cascading: &CascadingReplicationConfiguration{
+				Instances: []CascadingReplicationInstance{
+					{Name: zoneBPod2, Upstream: zoneBPod1},
+				},
+			},
+		}
+		cluster.Status.TargetPrimary = zoneBPod1
+		Expect(cluster.GetCascadingReplicationUpstreams()).To(BeEmpty())
+	})
+
+	It("elects an upstream for every topology domain not hosting the primary", func() {
+		cluster.Spec.Replication = &ReplicationConfiguration{
+			Cascading: &CascadingReplicationConfiguration{
+				TopologyKey: "zone",
+			},
+		}
+		Expect(cluster.GetTopologyLabelNames()).To(Equal([]string{"zone"}))
+		Expect(cluster.GetCascadingReplicationUpstreams()).To(Equal(map[string]string{
+			zoneBPod2: zoneBPod1,
+			zoneBPod3: zoneBPod1,
+		}))
+
+		By("re-parenting the standbys when the upstream fails", func() {
+			cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+				utils.PodHealthy: {primaryPod, zoneBPod2, zoneBPod3},
+				utils.PodFailed:  {zoneBPod1},
+			}
+			Expect(cluster.GetCascadingReplicationUpstreams()).To(Equal(map[string]string{
+				zoneBPod1: zoneBPod2,
+				zoneBPod3: zoneBPod2,
+			}))
+		})
+	})
+
+	It("excludes cascading standbys from the electable synchronous replicas", func() {
+		cluster.Spec.Replication = &ReplicationConfiguration{
+			Cascading: &CascadingReplicationConfiguration{
+				Instances: []CascadingReplicationInstance{
+					{Name: zoneBPod2, Upstream: zoneBPod1},
+				},
+			},
+		}
+		_, names := cluster.GetSyncReplicasData()
+		Expect(names).To(Equal([]string{zoneBPod1, zoneBPod3}))
+	})
+})
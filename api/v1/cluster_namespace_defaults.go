@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// DefaultBackupAccessKeyIDKey is the key of the access key ID in the
+	// secret set as the default backup credentials of a namespace
+	DefaultBackupAccessKeyIDKey = "ACCESS_KEY_ID"
+
+	// DefaultBackupSecretAccessKeyKey is the key of the secret access key in
+	// the secret set as the default backup credentials of a namespace
+	DefaultBackupSecretAccessKeyKey = "ACCESS_SECRET_KEY"
+)
+
+// clusterDefaulter is the mutating webhook of the Cluster resource. It
+// applies the defaults set through the annotations of the namespace of a
+// new cluster, before the ones of the operator
+type clusterDefaulter struct {
+	reader client.Reader
+}
+
+var _ admission.CustomDefaulter = &clusterDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *clusterDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*Cluster)
+	if !ok {
+		return fmt.Errorf("expected a Cluster but got a %T", obj)
+	}
+
+	if cluster.CreationTimestamp.IsZero() {
+		namespaceName := cluster.Namespace
+		if request, err := admission.RequestFromContext(ctx); err == nil && request.Namespace != "" {
+			namespaceName = request.Namespace
+		}
+
+		var namespace corev1.Namespace
+		if err := d.reader.Get(ctx, client.ObjectKey{Name: namespaceName}, &namespace); err != nil {
+			clusterLog.Error(err, "unable to read the namespace defaults", "namespace", namespaceName)
+		} else if err := cluster.applyNamespaceDefaults(namespace.Annotations); err != nil {
+			return fmt.Errorf("while applying the defaults of namespace %s: %w", namespaceName, err)
+		}
+	}
+
+	cluster.Default()
+	return nil
+}
+
+// applyNamespaceDefaults sets the fields of the cluster which are not
+// specified using the passed annotations of its namespace
+func (r *Cluster) applyNamespaceDefaults(annotations map[string]string) error {
+	if storageClass, ok := annotations[utils.DefaultStorageClassAnnotationName]; ok && storageClass != "" {
+		r.Spec.StorageConfiguration.defaultStorageClass(storageClass)
+		if r.Spec.WalStorage != nil {
+			r.Spec.WalStorage.defaultStorageClass(storageClass)
+		}
+	}
+
+	if resources, ok := annotations[utils.DefaultResourcesAnnotationName]; ok &&
+		len(r.Spec.Resources.Limits) == 0 && len(r.Spec.Resources.Requests) == 0 {
+		if err := json.Unmarshal([]byte(resources), &r.Spec.Resources); err != nil {
+			return fmt.Errorf("invalid %s annotation: %w", utils.DefaultResourcesAnnotationName, err)
+		}
+	}
+
+	secretName := annotations[utils.DefaultBackupCredentialsSecretAnnotationName]
+	if secretName != "" && r.Spec.Backup != nil && r.Spec.Backup.BarmanObjectStore != nil &&
+		!r.Spec.Backup.BarmanObjectStore.BarmanCredentials.ArePopulated() {
+		r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS = &S3Credentials{
+			AccessKeyIDReference: &SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: secretName},
+				Key:                  DefaultBackupAccessKeyIDKey,
+			},
+			SecretAccessKeyReference: &SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: secretName},
+				Key:                  DefaultBackupSecretAccessKeyKey,
+			},
+		}
+	}
+
+	return nil
+}
+
+// defaultStorageClass sets the storage class when it's not specified,
+// neither directly nor in the PVC template
+func (s *StorageConfiguration) defaultStorageClass(storageClass string) {
+	if s.StorageClass != nil {
+		return
+	}
+	if s.PersistentVolumeClaimTemplate != nil && s.PersistentVolumeClaimTemplate.StorageClassName != nil {
+		return
+	}
+
+	s.StorageClass = &storageClass
+}
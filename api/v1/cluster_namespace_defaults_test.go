@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("namespace defaults", func() {
+	annotations := map[string]string{
+		utils.DefaultStorageClassAnnotationName:            "fast",
+		utils.DefaultResourcesAnnotationName:               `{"requests": {"cpu": "1", "memory": "1Gi"}}`,
+		utils.DefaultBackupCredentialsSecretAnnotationName: "tenant-backup-creds",
+	}
+
+	It("fills the fields which are not set", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{Size: "1Gi"},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"},
+				},
+			},
+		}
+		Expect(cluster.applyNamespaceDefaults(annotations)).To(Succeed())
+
+		Expect(cluster.Spec.StorageConfiguration.StorageClass).To(Equal(pointer.String("fast")))
+		Expect(cluster.Spec.WalStorage.StorageClass).To(Equal(pointer.String("fast")))
+		Expect(cluster.Spec.Resources.Requests.Memory().Equal(resource.MustParse("1Gi"))).To(BeTrue())
+		credentials := cluster.Spec.Backup.BarmanObjectStore.AWS
+		Expect(credentials).ToNot(BeNil())
+		Expect(credentials.AccessKeyIDReference.Name).To(Equal("tenant-backup-creds"))
+		Expect(credentials.AccessKeyIDReference.Key).To(Equal(DefaultBackupAccessKeyIDKey))
+		Expect(credentials.SecretAccessKeyReference.Key).To(Equal(DefaultBackupSecretAccessKeyKey))
+	})
+
+	It("doesn't change the fields which are set", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: pointer.String("standard"),
+					},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath:   "s3://backups/",
+						BarmanCredentials: BarmanCredentials{AWS: &S3Credentials{InheritFromIAMRole: true}},
+					},
+				},
+			},
+		}
+		Expect(cluster.applyNamespaceDefaults(annotations)).To(Succeed())
+
+		Expect(cluster.Spec.StorageConfiguration.StorageClass).To(BeNil())
+		Expect(cluster.Spec.Resources.Requests).To(BeEmpty())
+		Expect(cluster.Spec.Backup.BarmanObjectStore.AWS.AccessKeyIDReference).To(BeNil())
+	})
+
+	It("rejects invalid resource requirements", func() {
+		cluster := &Cluster{}
+		err := cluster.applyNamespaceDefaults(map[string]string{
+			utils.DefaultResourcesAnnotationName: "cpu: 1",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,189 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ClusterTemplateDefaultsKey is the key of the cluster template ConfigMap
+	// containing the partial specification used to fill the fields which are
+	// not set in a new Cluster
+	ClusterTemplateDefaultsKey = "defaults"
+
+	// ClusterTemplateOverridesKey is the key of the cluster template ConfigMap
+	// containing the partial specification replacing the corresponding
+	// fields of a new Cluster
+	ClusterTemplateOverridesKey = "overrides"
+)
+
+// clusterTemplate is the operator-wide partial specification merged
+// into every new Cluster
+var clusterTemplate struct {
+	sync.RWMutex
+	defaults  map[string]interface{}
+	overrides map[string]interface{}
+}
+
+// SetClusterTemplate sets the operator-wide template merged into every new
+// Cluster, given the content of the cluster template ConfigMap. Both the
+// defaults and the overrides are partial Cluster specifications in YAML
+func SetClusterTemplate(data map[string]string) error {
+	defaults, err := parseClusterTemplate(data[ClusterTemplateDefaultsKey])
+	if err != nil {
+		return fmt.Errorf("while parsing the %s of the cluster template: %w", ClusterTemplateDefaultsKey, err)
+	}
+
+	overrides, err := parseClusterTemplate(data[ClusterTemplateOverridesKey])
+	if err != nil {
+		return fmt.Errorf("while parsing the %s of the cluster template: %w", ClusterTemplateOverridesKey, err)
+	}
+
+	clusterTemplate.Lock()
+	defer clusterTemplate.Unlock()
+	clusterTemplate.defaults = defaults
+	clusterTemplate.overrides = overrides
+	return nil
+}
+
+// parseClusterTemplate parses a partial Cluster specification, rejecting
+// the fields which are not part of it
+func parseClusterTemplate(content string) (map[string]interface{}, error) {
+	if content == "" {
+		return nil, nil
+	}
+
+	var spec ClusterSpec
+	if err := yaml.UnmarshalStrict([]byte(content), &spec); err != nil {
+		return nil, err
+	}
+
+	var template map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// applyClusterTemplate merges the operator-wide template into the
+// specification of the Cluster. The defaults are only used for the fields
+// which are not set, while the overrides always replace the corresponding
+// fields. Objects are merged field by field, while lists are replaced
+func (r *Cluster) applyClusterTemplate() error {
+	clusterTemplate.RLock()
+	defer clusterTemplate.RUnlock()
+	if clusterTemplate.defaults == nil && clusterTemplate.overrides == nil {
+		return nil
+	}
+
+	rawSpec, err := json.Marshal(r.Spec)
+	if err != nil {
+		return err
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(rawSpec, &spec); err != nil {
+		return err
+	}
+
+	spec = mergeClusterTemplateDefaults(clusterTemplate.defaults, spec)
+	spec = mergeClusterTemplateOverrides(spec, clusterTemplate.overrides)
+
+	rawSpec, err = json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	var mergedSpec ClusterSpec
+	if err := json.Unmarshal(rawSpec, &mergedSpec); err != nil {
+		return err
+	}
+
+	r.Spec = mergedSpec
+	return nil
+}
+
+// mergeClusterTemplateDefaults fills the fields of the specification
+// which are not set with the ones of the defaults
+func mergeClusterTemplateDefaults(defaults, spec map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(spec))
+	for key, value := range spec {
+		result[key] = value
+	}
+
+	for key, defaultValue := range defaults {
+		value, found := result[key]
+		if !found || isClusterTemplateValueEmpty(value) {
+			result[key] = defaultValue
+			continue
+		}
+
+		defaultMap, isDefaultMap := defaultValue.(map[string]interface{})
+		valueMap, isValueMap := value.(map[string]interface{})
+		if isDefaultMap && isValueMap {
+			result[key] = mergeClusterTemplateDefaults(defaultMap, valueMap)
+		}
+	}
+
+	return result
+}
+
+// mergeClusterTemplateOverrides replaces the fields of the specification
+// with the ones of the overrides
+func mergeClusterTemplateOverrides(spec, overrides map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(spec))
+	for key, value := range spec {
+		result[key] = value
+	}
+
+	for key, overrideValue := range overrides {
+		overrideMap, isOverrideMap := overrideValue.(map[string]interface{})
+		valueMap, isValueMap := result[key].(map[string]interface{})
+		if isOverrideMap && isValueMap {
+			result[key] = mergeClusterTemplateOverrides(valueMap, overrideMap)
+			continue
+		}
+
+		result[key] = overrideValue
+	}
+
+	return result
+}
+
+// isClusterTemplateValueEmpty checks whether a field of the specification
+// is to be considered as not set. Numbers and booleans are always
+// considered as set, as their zero value can be chosen by the user
+func isClusterTemplateValueEmpty(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case nil:
+		return true
+	case string:
+		return typedValue == ""
+	case map[string]interface{}:
+		return len(typedValue) == 0
+	case []interface{}:
+		return len(typedValue) == 0
+	default:
+		return false
+	}
+}
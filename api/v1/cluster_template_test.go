@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster template", func() {
+	AfterEach(func() {
+		Expect(SetClusterTemplate(nil)).To(Succeed())
+	})
+
+	It("rejects the fields which are not part of the cluster specification", func() {
+		err := SetClusterTemplate(map[string]string{
+			ClusterTemplateDefaultsKey: "monitorin:\n  enablePodMonitor: true\n",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fills the fields which are not set with the defaults", func() {
+		Expect(SetClusterTemplate(map[string]string{
+			ClusterTemplateDefaultsKey: `
+imageName: ghcr.io/example/postgresql:15
+monitoring:
+  enablePodMonitor: true
+affinity:
+  topologyKey: topology.kubernetes.io/zone
+  nodeSelector:
+    workload: postgres
+`,
+		})).To(Succeed())
+
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+				ImageName: "ghcr.io/example/postgresql:14",
+				Affinity: AffinityConfiguration{
+					NodeSelector: map[string]string{"disktype": "ssd"},
+				},
+			},
+		}
+		Expect(cluster.applyClusterTemplate()).To(Succeed())
+
+		Expect(cluster.Spec.Instances).To(Equal(3))
+		Expect(cluster.Spec.ImageName).To(Equal("ghcr.io/example/postgresql:14"))
+		Expect(cluster.Spec.Monitoring).ToNot(BeNil())
+		Expect(cluster.Spec.Monitoring.EnablePodMonitor).To(BeTrue())
+		Expect(cluster.Spec.Affinity.TopologyKey).To(Equal("topology.kubernetes.io/zone"))
+		Expect(cluster.Spec.Affinity.NodeSelector).To(Equal(map[string]string{
+			"disktype": "ssd",
+			"workload": "postgres",
+		}))
+	})
+
+	It("replaces the fields of the cluster with the overrides", func() {
+		Expect(SetClusterTemplate(map[string]string{
+			ClusterTemplateDefaultsKey:  "enableSuperuserAccess: true\n",
+			ClusterTemplateOverridesKey: "affinity:\n  topologyKey: topology.kubernetes.io/zone\n",
+		})).To(Succeed())
+
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				EnableSuperuserAccess: pointer.Bool(false),
+				Affinity: AffinityConfiguration{
+					TopologyKey:  "kubernetes.io/hostname",
+					NodeSelector: map[string]string{"disktype": "ssd"},
+				},
+			},
+		}
+		Expect(cluster.applyClusterTemplate()).To(Succeed())
+
+		Expect(cluster.Spec.EnableSuperuserAccess).To(Equal(pointer.Bool(false)))
+		Expect(cluster.Spec.Affinity.TopologyKey).To(Equal("topology.kubernetes.io/zone"))
+		Expect(cluster.Spec.Affinity.NodeSelector).To(Equal(map[string]string{"disktype": "ssd"}))
+	})
+
+	It("is only merged into the clusters being created", func() {
+		Expect(SetClusterTemplate(map[string]string{
+			ClusterTemplateDefaultsKey: "description: managed by the platform team\n",
+		})).To(Succeed())
+
+		newCluster := &Cluster{}
+		newCluster.Default()
+		Expect(newCluster.Spec.Description).To(Equal("managed by the platform team"))
+
+		existingCluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+		}
+		existingCluster.Default()
+		Expect(existingCluster.Spec.Description).To(BeEmpty())
+	})
+})
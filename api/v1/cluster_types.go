@@ -18,15 +18,21 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -82,6 +88,28 @@ const (
 	// get the name of the pull secret
 	ClusterSecretSuffix = "-pull-secret"
 
+	// InventoryConfigMapSuffix is the suffix appended to the cluster name to
+	// get the name of the ConfigMap where the roles and databases are exported
+	// when the cluster is deleted
+	InventoryConfigMapSuffix = "-inventory"
+
+	// DatabaseResourcesInfix is placed between the name of the cluster and
+	// the one of an exposed managed database to get the name of the Service
+	// and of the Secret dedicated to that database
+	DatabaseResourcesInfix = "-db-"
+
+	// DeletionPolicyFinalizerName is the finalizer holding the deletion
+	// of a Cluster until its deletion policy has been applied
+	DeletionPolicyFinalizerName = "cnpg.io/deletionPolicy"
+
+	// DefaultDeletionPolicyTimeout is the default time in seconds
+	// allowed to complete the teardown sequence of a cluster
+	DefaultDeletionPolicyTimeout = 300
+
+	// DefaultWalArchiveHookTimeout is the default time in seconds
+	// allowed to the WAL archive hook to complete
+	DefaultWalArchiveHookTimeout = 30
+
 	// StreamingReplicationUser is the name of the user we'll use for
 	// streaming replication purposes
 	StreamingReplicationUser = "streaming_replica"
@@ -106,6 +134,35 @@ const (
 
 	// PGBouncerPoolerUserName is the name of the role to be used for
 	PGBouncerPoolerUserName = "cnpg_pooler_pgbouncer"
+
+	// DefaultPgStatStatementsTopN is the default number of queries whose
+	// statistics are exported by the pg_stat_statements collector
+	DefaultPgStatStatementsTopN = 10
+
+	// PgStatStatementsExtensionName is the name of the pg_stat_statements extension
+	PgStatStatementsExtensionName = "pg_stat_statements"
+
+	// DefaultWaitEventSamplingPeriod is the default period, in milliseconds,
+	// used to sample the wait events of the active sessions
+	DefaultWaitEventSamplingPeriod = 1000
+
+	// DevelopmentStorageSize is the size of the storage of the clusters
+	// using the development profile, when not specified
+	DevelopmentStorageSize = "1Gi"
+)
+
+// ClusterProfile is the profile of a cluster, tuning its
+// defaults for the environment where it runs
+type ClusterProfile string
+
+const (
+	// ClusterProfileProduction is the default profile, requiring
+	// every aspect of the cluster to be explicitly configured
+	ClusterProfileProduction ClusterProfile = "production"
+
+	// ClusterProfileDevelopment is the profile of the minimal,
+	// single-instance clusters used during development
+	ClusterProfileDevelopment ClusterProfile = "development"
 )
 
 // ClusterSpec defines the desired state of Cluster
@@ -121,6 +178,17 @@ type ClusterSpec struct {
 	// (`<image>:<tag>@sha256:<digestValue>`)
 	ImageName string `json:"imageName,omitempty"`
 
+	// Pins the cluster to an exact PostgreSQL minor version, that must
+	// match the tag of the image, as an explicit exception to the minor
+	// releases published in the catalog of the operator
+	// +optional
+	MinorVersionPinning *MinorVersionPinning `json:"minorVersionPinning,omitempty"`
+
+	// Resolves the image to its digest and optionally verifies its
+	// signature before rolling it out
+	// +optional
+	ImageVerification *ImageVerificationConfiguration `json:"imageVerification,omitempty"`
+
 	// Image pull policy.
 	// One of `Always`, `Never` or `IfNotPresent`.
 	// If not defined, it defaults to `IfNotPresent`.
@@ -137,6 +205,14 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=26
 	PostgresGID int64 `json:"postgresGID,omitempty"`
 
+	// The profile of the cluster, `production` (default) or `development`.
+	// A development cluster has a single instance, a default storage size
+	// of 1Gi, more tolerant probes, no PodDisruptionBudget, and skips the
+	// final backup on deletion unless explicitly requested
+	// +kubebuilder:validation:Enum=production;development
+	// +optional
+	Profile ClusterProfile `json:"profile,omitempty"`
+
 	// Number of instances required in the cluster
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default:=1
@@ -163,6 +239,10 @@ type ClusterSpec struct {
 	// Replication slots management configuration
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// Streaming replication topology configuration
+	// +optional
+	Replication *ReplicationConfiguration `json:"replication,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -185,6 +265,12 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=true
 	EnableSuperuserAccess *bool `json:"enableSuperuserAccess,omitempty"`
 
+	// Files, provided by an external secret store, containing the
+	// passwords of the superuser and of the application user. When set,
+	// they are used instead of the corresponding Kubernetes secrets
+	// +optional
+	CredentialsFiles *CredentialsFilesConfiguration `json:"credentialsFiles,omitempty"`
+
 	// The configuration for the CA and related certificates
 	// +optional
 	Certificates *CertificatesConfiguration `json:"certificates,omitempty"`
@@ -199,6 +285,15 @@ type ClusterSpec struct {
 	// Configuration of the storage for PostgreSQL WAL (Write-Ahead Log)
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// What to do with the PVCs of the instances removed while scaling
+	// down the cluster: they can be deleted (`delete` - default) or
+	// detached from the cluster (`retain`), to be reused by a later
+	// scale up instead of cloning a new replica
+	// +kubebuilder:default:=delete
+	// +kubebuilder:validation:Enum:=delete;retain
+	// +optional
+	ScaleDownPVCReclaimPolicy PVCReclaimPolicy `json:"scaleDownPVCReclaimPolicy,omitempty"`
+
 	// The time in seconds that is allowed for a PostgreSQL instance to
 	// successfully start up (default 30)
 	// +kubebuilder:default:=30
@@ -209,6 +304,15 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=30
 	MaxStopDelay int32 `json:"stopDelay,omitempty"`
 
+	// The time in seconds reserved for the smart shutdown of PostgreSQL,
+	// during which new connections are refused while the existing ones are
+	// allowed to complete, before escalating to a fast shutdown. It must be
+	// lower than `stopDelay`, to leave time for the fast shutdown to
+	// complete. When not set, half of `stopDelay` is used
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SmartShutdownTimeout int32 `json:"smartShutdownTimeout,omitempty"`
+
 	// The time in seconds that is allowed for a primary PostgreSQL instance
 	// to gracefully shutdown during a switchover.
 	// Default value is 40000000, greater than one year in seconds,
@@ -216,6 +320,17 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=40000000
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// The configuration of the connection draining done by the primary
+	// before being shut down during a switchover. When not set, the
+	// primary is shut down without draining the connections
+	// +optional
+	SwitchoverDrain *SwitchoverDrainConfiguration `json:"switchoverDrain,omitempty"`
+
+	// The configuration of the startup, readiness and liveness probes
+	// of the instances
+	// +optional
+	Probes *ProbesConfiguration `json:"probes,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
@@ -226,6 +341,24 @@ type ClusterSpec struct {
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// Resources requirements of the Jobs creating the instances, i.e.
+	// via initdb, recovery or by cloning the primary when joining the
+	// cluster. When not specified, the ones of the instance Pods are used
+	// +optional
+	JobResources *corev1.ResourceRequirements `json:"jobResources,omitempty"`
+
+	// The groups of instances with their own resources, storage class and
+	// node selector. The instances of the groups are part of the ones
+	// requested in `instances`, and those not belonging to any group
+	// use the settings of the whole cluster
+	// +optional
+	InstanceGroups []InstanceGroup `json:"instanceGroups,omitempty"`
+
+	// The size limits of the ephemeral volumes of the instance Pods,
+	// beyond which the Pods are evicted
+	// +optional
+	EphemeralVolumesSizeLimit *EphemeralVolumesSizeLimitConfiguration `json:"ephemeralVolumesSizeLimit,omitempty"`
+
 	// Strategy to follow to upgrade the primary server during a rolling
 	// update procedure, after all replicas have been successfully updated:
 	// it can be automated (`unsupervised` - default) or manual (`supervised`)
@@ -240,15 +373,43 @@ type ClusterSpec struct {
 	// +kubebuilder:validation:Enum:=switchover;restart
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// Method to follow to upgrade the instance manager after an upgrade
+	// of the operator: it can be with a rolling update of the instances
+	// (`rollout`) or by replacing the instance manager executable inside
+	// the running Pods, without restarting PostgreSQL (`inplace`).
+	// When not set, the operator configuration is followed
+	// +kubebuilder:validation:Enum:=rollout;inplace
+	// +optional
+	InstanceManagerUpdateMethod InstanceManagerUpdateMethod `json:"instanceManagerUpdateMethod,omitempty"`
+
 	// The configuration to be used for backups
 	Backup *BackupConfiguration `json:"backup,omitempty"`
 
 	// Define a maintenance window for the Kubernetes nodes
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
+	// The policy to remediate the standbys whose PostgreSQL container
+	// keeps restarting, recreating them from scratch
+	// +optional
+	CrashLoopRemediation *CrashLoopRemediationConfiguration `json:"crashLoopRemediation,omitempty"`
+
+	// The policy to apply to the instances reporting damaged data
+	// +optional
+	DataCorruption *DataCorruptionConfiguration `json:"dataCorruption,omitempty"`
+
+	// How the operator tells a primary whose storage is temporarily
+	// stalled from a failed one, delaying the failover in the former case
+	// +optional
+	StorageStall *StorageStallConfiguration `json:"storageStall,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this cluster
 	Monitoring *MonitoringConfiguration `json:"monitoring,omitempty"`
 
+	// The configuration of the NetworkPolicy restricting the traffic
+	// directed to the instances of this cluster
+	// +optional
+	NetworkPolicy *NetworkPolicyConfiguration `json:"networkPolicy,omitempty"`
+
 	// The list of external clusters which are used in the configuration
 	ExternalClusters []ExternalCluster `json:"externalClusters,omitempty"`
 
@@ -256,6 +417,33 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=info
 	// +kubebuilder:validation:Enum:=error;warning;info;debug;trace
 	LogLevel string `json:"logLevel,omitempty"`
+
+	// The configuration of the logs produced by the instances
+	// +optional
+	Logging *LoggingConfiguration `json:"logging,omitempty"`
+
+	// The steps taken by the operator before the resources of the
+	// cluster are removed, when the Cluster is deleted
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Customizations merged into the Pods running the PostgreSQL instances
+	// +optional
+	PodTemplate *InstancePodTemplate `json:"podTemplate,omitempty"`
+
+	// The service mesh injecting a proxy sidecar in the Pods of the
+	// cluster, whose integration is configured by the operator
+	// +optional
+	ServiceMesh *ServiceMeshConfiguration `json:"serviceMesh,omitempty"`
+
+	// A projected volume mounted, read-only, in the PostgreSQL container
+	// under the /projected directory
+	// +optional
+	ProjectedVolumeTemplate *corev1.ProjectedVolumeSource `json:"projectedVolumeTemplate,omitempty"`
+
+	// The PostgreSQL objects declaratively managed by the instance manager
+	// +optional
+	Managed *ManagedConfiguration `json:"managed,omitempty"`
 }
 
 const (
@@ -292,6 +480,10 @@ const (
 	// PhaseOnlineUpgrading for when the instance manager is being upgraded in place
 	PhaseOnlineUpgrading = "Online upgrade in progress"
 
+	// PhaseStorageClassMigration for when an instance is being recreated
+	// on the storage class requested in the cluster specification
+	PhaseStorageClassMigration = "Migrating to a new storage class"
+
 	// PhaseApplyingConfiguration is set by the instance manager when a configuration
 	// change is being detected
 	PhaseApplyingConfiguration = "Applying configuration"
@@ -301,11 +493,11 @@ const (
 type PodTopologyLabels map[string]string
 
 // matchesTopology checks if the two topologies have
-// the same label values (labels are specified in SyncReplicaElectionConstraints.NodeLabelsAntiAffinity)
-func (topologyLabels PodTopologyLabels) matchesTopology(instanceTopology PodTopologyLabels) bool {
+// the same values for the passed labels
+func (topologyLabels PodTopologyLabels) matchesTopology(instanceTopology PodTopologyLabels, labelNames []string) bool {
 	log.Debug("matching topology", "main", topologyLabels, "second", instanceTopology)
-	for mainLabelName, mainLabelValue := range topologyLabels {
-		if mainLabelValue != instanceTopology[mainLabelName] {
+	for _, labelName := range labelNames {
+		if topologyLabels[labelName] != instanceTopology[labelName] {
 			return false
 		}
 	}
@@ -332,6 +524,10 @@ type ClusterStatus struct {
 	// Total number of ready instances in the cluster
 	ReadyInstances int `json:"readyInstances,omitempty"`
 
+	// The number of ready instances over the requested ones, e.g. "2/3"
+	// +optional
+	ReadyInstancesSummary string `json:"readyInstancesSummary,omitempty"`
+
 	// InstancesStatus indicates in which status the instances are
 	InstancesStatus map[utils.PodStatus][]string `json:"instancesStatus,omitempty"`
 
@@ -341,6 +537,11 @@ type ClusterStatus struct {
 	// The timeline of the Postgres cluster
 	TimelineID int `json:"timelineID,omitempty"`
 
+	// The position of the WAL written by the current primary, as
+	// reported when the status of the cluster has been last updated
+	// +optional
+	CurrentPrimaryLSN string `json:"currentPrimaryLSN,omitempty"`
+
 	// Instances topology.
 	Topology Topology `json:"topology,omitempty"`
 
@@ -354,6 +555,10 @@ type ClusterStatus struct {
 	// during a switchover or a failover
 	TargetPrimary string `json:"targetPrimary,omitempty"`
 
+	// The reason why the target primary has been changed, set while
+	// a switchover or a failover is in progress
+	TargetPrimaryReason PrimaryChangeReason `json:"targetPrimaryReason,omitempty"`
+
 	// How many PVCs have been created by this cluster
 	PVCCount int32 `json:"pvcCount,omitempty"`
 
@@ -415,6 +620,10 @@ type ClusterStatus struct {
 	// The timestamp when the last request for a new primary has occurred
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
 
+	// The timestamp when the last failover has been started
+	// +optional
+	LastFailoverTimestamp string `json:"lastFailoverTimestamp,omitempty"`
+
 	// The integration needed by poolers referencing the cluster
 	PoolerIntegrations *PoolerIntegrations `json:"poolerIntegrations,omitempty"`
 
@@ -432,6 +641,88 @@ type ClusterStatus struct {
 
 	// List of instance names in the cluster
 	InstanceNames []string `json:"instanceNames,omitempty"`
+
+	// The progress of the online import of the application database
+	OnlineImport *OnlineImportStatus `json:"onlineImport,omitempty"`
+
+	// The roles whose password is still hashed with md5, reported while
+	// migrating the passwords to SCRAM-SHA-256
+	MD5PasswordRoles []string `json:"md5PasswordRoles,omitempty"`
+
+	// The secret containing the credentials of the application user,
+	// exposing the cluster as a provisioned service according to the
+	// Service Binding specification (https://servicebinding.io)
+	Binding *LocalObjectReference `json:"binding,omitempty"`
+
+	// The image the operator resolved, and possibly verified, for the
+	// instances when image verification is enabled
+	// +optional
+	ImageDigest *ImageDigestStatus `json:"imageDigest,omitempty"`
+
+	// The configuration parameters that have been reloaded but still
+	// need a restart of at least one instance to be applied
+	// +optional
+	PendingRestartParameters []string `json:"pendingRestartParameters,omitempty"`
+
+	// The instances which reported checksum failures or errors about
+	// damaged data
+	// +optional
+	DataCorruptedInstances []string `json:"dataCorruptedInstances,omitempty"`
+
+	// The fenced instances, as seen when the status of the cluster has
+	// been last updated. The "*" element means that every instance is fenced
+	// +optional
+	FencedInstances []string `json:"fencedInstances,omitempty"`
+
+	// When the storage of the current primary has been detected as
+	// stalled, if it is still stalled
+	// +optional
+	PrimaryStorageStalledSince string `json:"primaryStorageStalledSince,omitempty"`
+
+	// The label selector matching the instances of the cluster, in the
+	// string format, used by the scale subresource for autoscalers
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// The instance group each instance has been assigned to, indexed
+	// by instance name. The instances of the default group are not listed
+	// +optional
+	InstanceGroups map[string]string `json:"instanceGroups,omitempty"`
+}
+
+// OnlineImportPhase is the phase of the online import of a database
+type OnlineImportPhase string
+
+const (
+	// OnlineImportPhaseCopying means that the initial copy of the tables
+	// is in progress
+	OnlineImportPhaseCopying OnlineImportPhase = "Copying"
+
+	// OnlineImportPhaseStreaming means that the changes happening in the
+	// origin database are being applied to the imported one
+	OnlineImportPhaseStreaming OnlineImportPhase = "Streaming"
+
+	// OnlineImportPhaseCuttingOver means that the writes on the origin
+	// database have been stopped, and we are waiting for the imported
+	// database to catch up
+	OnlineImportPhaseCuttingOver OnlineImportPhase = "CuttingOver"
+
+	// OnlineImportPhaseCompleted means that the migration has been completed
+	// and the imported database is not following the origin anymore
+	OnlineImportPhaseCompleted OnlineImportPhase = "Completed"
+)
+
+// OnlineImportStatus is the progress of the online import of a database
+type OnlineImportStatus struct {
+	// The current phase of the online import
+	Phase OnlineImportPhase `json:"phase,omitempty"`
+
+	// The amount of WAL, in bytes, generated by the origin and not
+	// yet applied to the imported database
+	LagBytes int64 `json:"lagBytes,omitempty"`
+
+	// The last error detected while following the origin
+	Error string `json:"error,omitempty"`
 }
 
 // InstanceReportedState describes the last reported state of an instance during a reconciliation loop
@@ -440,6 +731,54 @@ type InstanceReportedState struct {
 	IsPrimary bool `json:"isPrimary"`
 	// indicates on which TimelineId the instance is
 	TimeLineID int `json:"timeLineID,omitempty"`
+	// indicates the IP address of the Pod running the instance
+	IP string `json:"ip,omitempty"`
+}
+
+// PrimaryChangeReason is the reason code of a switchover or a failover
+type PrimaryChangeReason string
+
+const (
+	// PrimaryChangeReasonLiveness means that the primary instance was
+	// not healthy or was missing
+	PrimaryChangeReasonLiveness PrimaryChangeReason = "liveness"
+
+	// PrimaryChangeReasonIsolation means that the operator was not able
+	// to reach the instance manager of the primary instance
+	PrimaryChangeReasonIsolation PrimaryChangeReason = "isolation"
+
+	// PrimaryChangeReasonUnschedulableNode means that the primary instance
+	// was running on a node which has been set as unschedulable, e.g.
+	// while being drained
+	PrimaryChangeReasonUnschedulableNode PrimaryChangeReason = "unschedulableNode"
+
+	// PrimaryChangeReasonRollingUpdate means that the primary instance
+	// needed to be restarted during a rolling update
+	PrimaryChangeReasonRollingUpdate PrimaryChangeReason = "rollingUpdate"
+
+	// PrimaryChangeReasonStorageClassMigration means that the primary
+	// instance needed to be recreated on a new storage class
+	PrimaryChangeReasonStorageClassMigration PrimaryChangeReason = "storageClassMigration"
+
+	// PrimaryChangeReasonManual means that the promotion has been
+	// requested by the user
+	PrimaryChangeReasonManual PrimaryChangeReason = "manual"
+)
+
+// IsFailover is true when the primary has been changed because the
+// previous one was not working correctly, and false when this was
+// a switchover
+func (reason PrimaryChangeReason) IsFailover() bool {
+	return reason == PrimaryChangeReasonLiveness || reason == PrimaryChangeReasonIsolation
+}
+
+// GetKind gets the kind of the primary change having this reason,
+// which is either "failover" or "switchover"
+func (reason PrimaryChangeReason) GetKind() string {
+	if reason.IsFailover() {
+		return "failover"
+	}
+	return "switchover"
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -454,6 +793,18 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionConfigurationInSync represents whether the configuration of
+	// the instances matches the one declared in the cluster specification
+	ConditionConfigurationInSync ClusterConditionType = "ConfigurationInSync"
+	// ConditionOutdatedMinorVersion represents whether the cluster is running
+	// a PostgreSQL minor version older than the latest one in the catalog
+	ConditionOutdatedMinorVersion ClusterConditionType = "OutdatedMinorVersion"
+	// ConditionDataCorruptionDetected represents whether any instance
+	// reported checksum failures or errors about damaged data
+	ConditionDataCorruptionDetected ClusterConditionType = "DataCorruptionDetected"
+	// ConditionDegraded represents whether the cluster is working with
+	// fewer ready instances than the requested ones
+	ConditionDegraded ClusterConditionType = "Degraded"
 )
 
 // ConditionStatus defines conditions of resources
@@ -499,6 +850,44 @@ const (
 
 	// ClusterIsNotReady means that the condition changed because the cluster is not ready
 	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
+
+	// ConditionReasonConfigurationInSync means that no instance reported
+	// configuration parameters changed outside the cluster specification
+	ConditionReasonConfigurationInSync ConditionReason = "ConfigurationInSync"
+
+	// ConditionReasonConfigurationDrift means that at least one instance reported
+	// configuration parameters changed outside the cluster specification
+	ConditionReasonConfigurationDrift ConditionReason = "ConfigurationDrift"
+
+	// ConditionReasonMinorVersionUpToDate means that the cluster is running
+	// the latest minor version of the catalog, or that its major version is
+	// not in the catalog
+	ConditionReasonMinorVersionUpToDate ConditionReason = "MinorVersionUpToDate"
+
+	// ConditionReasonMinorVersionOutdated means that a newer minor version
+	// has been published in the catalog
+	ConditionReasonMinorVersionOutdated ConditionReason = "MinorVersionOutdated"
+
+	// ConditionReasonMinorVersionPinned means that a newer minor version
+	// has been published in the catalog, but the cluster is pinned to its
+	// current one
+	ConditionReasonMinorVersionPinned ConditionReason = "MinorVersionPinned"
+
+	// ConditionReasonNoDataCorruption means that no instance reported
+	// checksum failures or errors about damaged data
+	ConditionReasonNoDataCorruption ConditionReason = "NoDataCorruption"
+
+	// ConditionReasonDataCorruption means that at least one instance
+	// reported checksum failures or errors about damaged data
+	ConditionReasonDataCorruption ConditionReason = "DataCorruption"
+
+	// ConditionReasonAllInstancesReady means that every requested
+	// instance is ready
+	ConditionReasonAllInstancesReady ConditionReason = "AllInstancesReady"
+
+	// ConditionReasonInstancesNotReady means that some of the requested
+	// instances are missing or not ready
+	ConditionReasonInstancesNotReady ConditionReason = "InstancesNotReady"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -530,6 +919,38 @@ type ReplicaClusterConfiguration struct {
 	// The name of the external cluster which is the replication origin
 	// +kubebuilder:validation:MinLength=1
 	Source string `json:"source"`
+
+	// Export the read-only service to the other Kubernetes clusters of the
+	// cluster set, so that the replicas can be discovered through the
+	// Multi-Cluster Services API
+	// +optional
+	ExportReadOnlyService *ServiceExportConfiguration `json:"exportReadOnlyService,omitempty"`
+}
+
+// ServiceExportAPI is the API used to export a service
+// to the other Kubernetes clusters of a cluster set
+type ServiceExportAPI string
+
+const (
+	// ServiceExportAPIMultiCluster exports the service through the
+	// Multi-Cluster Services API (multicluster.x-k8s.io/v1alpha1)
+	ServiceExportAPIMultiCluster ServiceExportAPI = "mcs"
+
+	// ServiceExportAPISubmariner exports the service through the API
+	// of the Submariner Lighthouse versions preceding the adoption of the
+	// Multi-Cluster Services API (lighthouse.submariner.io/v2alpha1)
+	ServiceExportAPISubmariner ServiceExportAPI = "submariner"
+)
+
+// ServiceExportConfiguration is the configuration of the
+// export of a service to the other Kubernetes clusters
+type ServiceExportConfiguration struct {
+	// The API used to export the service, which can be `mcs`
+	// (default) or `submariner`
+	// +kubebuilder:validation:Enum=mcs;submariner
+	// +kubebuilder:default:=mcs
+	// +optional
+	API ServiceExportAPI `json:"api,omitempty"`
 }
 
 // DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
@@ -559,6 +980,147 @@ func (r *ReplicationSlotsConfiguration) GetUpdateInterval() time.Duration {
 	return time.Duration(r.UpdateInterval) * time.Second
 }
 
+// ReplicationConfiguration encapsulates the configuration of the
+// streaming replication topology among the instances of the cluster
+type ReplicationConfiguration struct {
+	// Cascading replication configuration, allowing standbys to
+	// stream from another standby instead of the primary
+	// +optional
+	Cascading *CascadingReplicationConfiguration `json:"cascading,omitempty"`
+
+	// The method used to compress the full page images written to the WAL,
+	// reducing the amount of data streamed to the replicas and archived.
+	// One of: off, pglz, lz4, zstd. The lz4 and zstd methods require
+	// PostgreSQL 15 or later. When not specified, the value of the
+	// `wal_compression` parameter is used
+	// +kubebuilder:validation:Enum:=off;pglz;lz4;zstd
+	// +optional
+	WALCompression WALCompressionMethod `json:"walCompression,omitempty"`
+
+	// Throttling of the pg_basebackup run by a new replica to clone its
+	// upstream, limiting the impact of the clone on the production traffic
+	// +optional
+	CloneThrottling *CloneThrottlingConfiguration `json:"cloneThrottling,omitempty"`
+
+	// The method used to create the data of a new replica: streaming it
+	// from the primary via pg_basebackup (`pg_basebackup` - default) or
+	// cloning the volume of a ready standby through the CSI driver
+	// (`volumeClone`), which requires a storage class supporting volume
+	// cloning. When no standby is available, pg_basebackup is used
+	// +kubebuilder:validation:Enum:=pg_basebackup;volumeClone
+	// +optional
+	CloneMethod ReplicaCloneMethod `json:"cloneMethod,omitempty"`
+
+	// The maximum amount of WAL, i.e. `64Mi`, that a standby can still
+	// have to replay, compared to the last position reported by its
+	// upstream, to be considered ready. A standby exceeding it is removed
+	// from the `-ro` and `-r` services, preventing stale reads, until it
+	// catches up. When not specified, the replay lag is not checked
+	// +optional
+	MaxStandbyLag *resource.Quantity `json:"maxStandbyLag,omitempty"`
+}
+
+// CloneThrottlingConfiguration limits the resources used by a new replica
+// while cloning its upstream via pg_basebackup
+type CloneThrottlingConfiguration struct {
+	// The maximum transfer rate of the clone, as accepted by the
+	// `--max-rate` option of pg_basebackup, i.e. `100M`. The value is
+	// in kilobytes per second, unless followed by `k` or `M`, and must
+	// be between 32 kB/s and 1024 MB/s
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*[kM]?$`
+	// +optional
+	MaxRate string `json:"maxRate,omitempty"`
+
+	// The niceness of the pg_basebackup process, between 0 and 19
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=19
+	// +optional
+	Nice *int32 `json:"nice,omitempty"`
+
+	// The I/O scheduling class of the pg_basebackup process.
+	// One of: best-effort, idle
+	// +kubebuilder:validation:Enum:=best-effort;idle
+	// +optional
+	IOClass IOSchedulingClass `json:"ioClass,omitempty"`
+
+	// The I/O priority in the best-effort class, between 0 (highest)
+	// and 7 (lowest)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=7
+	// +optional
+	IOPriority *int32 `json:"ioPriority,omitempty"`
+}
+
+// IOSchedulingClass is the I/O scheduling class of a process
+type IOSchedulingClass string
+
+const (
+	// IOSchedulingClassBestEffort is the default I/O scheduling class
+	IOSchedulingClassBestEffort IOSchedulingClass = "best-effort"
+
+	// IOSchedulingClassIdle makes a process do I/O only when no other
+	// process needs the disk
+	IOSchedulingClassIdle IOSchedulingClass = "idle"
+)
+
+// WALCompressionMethod is the method used to compress the full page images written to the WAL
+type WALCompressionMethod string
+
+const (
+	// WALCompressionOff disables the compression of the full page images
+	WALCompressionOff WALCompressionMethod = "off"
+
+	// WALCompressionPglz compresses the full page images using the PGLZ method
+	WALCompressionPglz WALCompressionMethod = "pglz"
+
+	// WALCompressionLz4 compresses the full page images using the LZ4 method
+	WALCompressionLz4 WALCompressionMethod = "lz4"
+
+	// WALCompressionZstd compresses the full page images using the Zstandard method
+	WALCompressionZstd WALCompressionMethod = "zstd"
+)
+
+// ReplicaCloneMethod is the method used to create the data of a new replica
+type ReplicaCloneMethod string
+
+const (
+	// ReplicaCloneMethodPgBaseBackup streams the data of the new replica
+	// from the primary via pg_basebackup
+	ReplicaCloneMethodPgBaseBackup ReplicaCloneMethod = "pg_basebackup"
+
+	// ReplicaCloneMethodVolumeClone creates the volume of the new replica
+	// as a CSI clone of the one of a ready standby
+	ReplicaCloneMethodVolumeClone ReplicaCloneMethod = "volumeClone"
+)
+
+// CascadingReplicationConfiguration contains the rules used to choose
+// the upstream of a standby. A standby which is not matched by any
+// rule, or whose upstream is not available, streams from the primary
+type CascadingReplicationConfiguration struct {
+	// Standbys streaming from another standby, chosen explicitly.
+	// These assignments take precedence over the topology based ones
+	// +optional
+	Instances []CascadingReplicationInstance `json:"instances,omitempty"`
+
+	// The name of a node label (i.e. `topology.kubernetes.io/zone`) used to
+	// group the instances in topology domains. In every domain not hosting
+	// the primary, only one standby streams from the primary, while the
+	// other ones stream from it
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+}
+
+// CascadingReplicationInstance assigns an upstream standby to a standby
+type CascadingReplicationInstance struct {
+	// The name of the standby
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The name of the instance the standby should stream from
+	// +kubebuilder:validation:MinLength=1
+	Upstream string `json:"upstream"`
+}
+
 // ReplicationSlotsHAConfiguration encapsulates the configuration
 // of the replication slots that are automatically managed by
 // the operator to control the streaming replication connections
@@ -646,91 +1208,1165 @@ type NodeMaintenanceWindow struct {
 	ReusePVC *bool `json:"reusePVC"`
 }
 
-// PrimaryUpdateStrategy contains the strategy to follow when upgrading
-// the primary server of the cluster as part of rolling updates
-type PrimaryUpdateStrategy string
+// CrashLoopRemediationConfiguration defines when a standby is considered
+// to be crash looping. Such a standby is deleted together with its PVCs,
+// and replaced by a new replica cloned from the primary
+type CrashLoopRemediationConfiguration struct {
+	// Whether the crash looping standbys should be recreated
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled"`
 
-// PrimaryUpdateMethod contains the method to use when upgrading
-// the primary server of the cluster as part of rolling updates
-type PrimaryUpdateMethod string
+	// The number of restarts of the PostgreSQL container, inside the
+	// window, above which the standby is recreated (default 5)
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRestarts int `json:"maxRestarts,omitempty"`
 
-const (
-	// PrimaryUpdateStrategySupervised means that the operator need to wait for the
-	// user to manually issue a switchover request before updating the primary
-	// server (`supervised`)
-	PrimaryUpdateStrategySupervised PrimaryUpdateStrategy = "supervised"
+	// The length of the window in minutes (default 10)
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WindowMinutes int `json:"windowMinutes,omitempty"`
+}
 
-	// PrimaryUpdateStrategyUnsupervised means that the operator will proceed with the
-	// selected PrimaryUpdateMethod to another updated replica and then automatically update
-	// the primary server (`unsupervised`, default)
-	PrimaryUpdateStrategyUnsupervised PrimaryUpdateStrategy = "unsupervised"
+// IsEnabled checks whether the crash looping standbys should be recreated
+func (r *CrashLoopRemediationConfiguration) IsEnabled() bool {
+	return r != nil && r.Enabled
+}
 
-	// PrimaryUpdateMethodSwitchover means that the operator will switchover to another updated
-	// replica when it needs to upgrade the primary instance
-	PrimaryUpdateMethodSwitchover PrimaryUpdateMethod = "switchover"
+// GetMaxRestarts gets the number of restarts above which a standby
+// is recreated
+func (r *CrashLoopRemediationConfiguration) GetMaxRestarts() int {
+	if r.MaxRestarts <= 0 {
+		return 5
+	}
+	return r.MaxRestarts
+}
 
-	// PrimaryUpdateMethodRestart means that the operator will restart the primary instance in-place
-	// when it needs to upgrade it
-	PrimaryUpdateMethodRestart PrimaryUpdateMethod = "restart"
+// GetWindow gets the window where the restarts are counted
+func (r *CrashLoopRemediationConfiguration) GetWindow() time.Duration {
+	if r.WindowMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(r.WindowMinutes) * time.Minute
+}
 
-	// DefaultPgCtlTimeoutForPromotion is the default for the pg_ctl timeout when a promotion is performed.
-	// It is greater than one year in seconds, big enough to simulate an infinite timeout
-	DefaultPgCtlTimeoutForPromotion = 40000000
+// HealthCheck is a check on the state of an instance which can be added
+// to one of its probes
+// +kubebuilder:validation:Enum=walReceiver;archiver;recoveryProgress
+type HealthCheck string
 
-	// DefaultMaxSwitchoverDelay is the default for the pg_ctl timeout in seconds when a primary PostgreSQL instance
-	// is gracefully shutdown during a switchover.
-	// It is greater than one year in seconds, big enough to simulate an infinite timeout
-	DefaultMaxSwitchoverDelay = 40000000
+const (
+	// HealthCheckWALReceiver fails when a standby which should be streaming
+	// from its upstream has no active WAL receiver
+	HealthCheckWALReceiver HealthCheck = "walReceiver"
+
+	// HealthCheckArchiver fails when the last attempt of the primary to
+	// archive a WAL file failed
+	HealthCheckArchiver HealthCheck = "archiver"
+
+	// HealthCheckRecoveryProgress fails when a standby didn't replay any WAL
+	// since the previous probe, while having received WAL still to be replayed
+	HealthCheckRecoveryProgress HealthCheck = "recoveryProgress"
 )
 
-// PostgresConfiguration defines the PostgreSQL configuration
-type PostgresConfiguration struct {
-	// PostgreSQL configuration options (postgresql.conf)
-	Parameters map[string]string `json:"parameters,omitempty"`
+// ProbesConfiguration contains the configuration of the probes of
+// the PostgreSQL container
+type ProbesConfiguration struct {
+	// The startup probe, succeeding by default once the postmaster is
+	// running. By default, the instance has `startDelay` seconds to start up
+	// +optional
+	Startup *StartupProbeConfiguration `json:"startup,omitempty"`
 
-	// PostgreSQL Host Based Authentication rules (lines to be appended
-	// to the pg_hba.conf file)
+	// The readiness probe, succeeding when the instance accepts
+	// connections and, for standbys, when it is streaming
 	// +optional
-	PgHBA []string `json:"pg_hba,omitempty"`
+	Readiness *ProbeConfiguration `json:"readiness,omitempty"`
 
-	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
-	// set up.
-	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
+	// The liveness probe, succeeding while the postmaster is running
+	// +optional
+	Liveness *ProbeConfiguration `json:"liveness,omitempty"`
+}
 
-	// Specifies the maximum number of seconds to wait when promoting an instance to primary.
-	// Default value is 40000000, greater than one year in seconds,
-	// big enough to simulate an infinite timeout
+// ProbeConfiguration contains the parameters of a probe of the PostgreSQL
+// container and the additional checks it runs. The parameters which are
+// not set keep their default value
+type ProbeConfiguration struct {
+	// How often, in seconds, the probe is run
+	// +kubebuilder:validation:Minimum=1
 	// +optional
-	PgCtlTimeoutForPromotion int32 `json:"promotionTimeout,omitempty"`
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
 
-	// Lists of shared preload libraries to add to the default ones
+	// The number of seconds after which the probe times out
+	// +kubebuilder:validation:Minimum=1
 	// +optional
-	AdditionalLibraries []string `json:"shared_preload_libraries,omitempty"`
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 
-	// Options to specify LDAP configuration
+	// The number of consecutive failures after which the probe is
+	// considered failed
+	// +kubebuilder:validation:Minimum=1
 	// +optional
-	LDAP *LDAPConfig `json:"ldap,omitempty"`
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// The checks run by the probe in addition to the default ones.
+	// They are read from the cluster definition, and can be changed
+	// without restarting the instances
+	// +optional
+	Checks []HealthCheck `json:"checks,omitempty"`
 }
 
-// BootstrapConfiguration contains information about how to create the PostgreSQL
-// cluster. Only a single bootstrap method can be defined among the supported
-// ones. `initdb` will be used as the bootstrap method if left
-// unspecified. Refer to the Bootstrap page of the documentation for more
-// information.
-type BootstrapConfiguration struct {
-	// Bootstrap the cluster via initdb
-	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+// StartupProbeStrategy is the condition the startup probe waits for
+// +kubebuilder:validation:Enum=postmaster;pg_isready;streaming
+type StartupProbeStrategy string
 
-	// Bootstrap the cluster from a backup
-	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+const (
+	// StartupProbeStrategyPostmaster waits for the postmaster to be running
+	StartupProbeStrategyPostmaster StartupProbeStrategy = "postmaster"
 
-	// Bootstrap the cluster taking a physical backup of another compatible
-	// PostgreSQL instance
-	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
-}
+	// StartupProbeStrategyPgIsReady waits for the instance to accept
+	// connections, i.e. for the end of the crash recovery of a primary
+	// or for a standby to reach a consistent state
+	StartupProbeStrategyPgIsReady StartupProbeStrategy = "pg_isready"
 
-// LDAPScheme defines the possible schemes for LDAP
-type LDAPScheme string
+	// StartupProbeStrategyStreaming waits for the instance to accept
+	// connections and, for a standby, to be streaming from its upstream
+	StartupProbeStrategyStreaming StartupProbeStrategy = "streaming"
+)
+
+// StartupProbeConfiguration contains the configuration of the startup
+// probe of the PostgreSQL container
+type StartupProbeConfiguration struct {
+	ProbeConfiguration `json:",inline"`
+
+	// The condition the startup probe waits for: the postmaster running
+	// (`postmaster`, default), the instance accepting connections
+	// (`pg_isready`), or the instance accepting connections and, for a
+	// standby, streaming from its upstream (`streaming`). Like the checks,
+	// it can be changed without restarting the instances
+	// +optional
+	Strategy StartupProbeStrategy `json:"strategy,omitempty"`
+}
+
+// GetStartup gets the configuration of the startup probe
+func (p *ProbesConfiguration) GetStartup() *ProbeConfiguration {
+	if p == nil || p.Startup == nil {
+		return nil
+	}
+	return &p.Startup.ProbeConfiguration
+}
+
+// GetStartupStrategy gets the condition the startup probe waits for
+func (p *ProbesConfiguration) GetStartupStrategy() StartupProbeStrategy {
+	if p == nil || p.Startup == nil || p.Startup.Strategy == "" {
+		return StartupProbeStrategyPostmaster
+	}
+	return p.Startup.Strategy
+}
+
+// GetReadiness gets the configuration of the readiness probe
+func (p *ProbesConfiguration) GetReadiness() *ProbeConfiguration {
+	if p == nil {
+		return nil
+	}
+	return p.Readiness
+}
+
+// GetLiveness gets the configuration of the liveness probe
+func (p *ProbesConfiguration) GetLiveness() *ProbeConfiguration {
+	if p == nil {
+		return nil
+	}
+	return p.Liveness
+}
+
+// ApplyTo overrides the parameters of the passed probe with the
+// ones set in this configuration
+func (p *ProbeConfiguration) ApplyTo(probe *corev1.Probe) {
+	if p == nil {
+		return
+	}
+	if p.PeriodSeconds > 0 {
+		probe.PeriodSeconds = p.PeriodSeconds
+	}
+	if p.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = p.TimeoutSeconds
+	}
+	if p.FailureThreshold > 0 {
+		probe.FailureThreshold = p.FailureThreshold
+	}
+}
+
+// HasCheck checks whether the probe runs the passed check
+func (p *ProbeConfiguration) HasCheck(check HealthCheck) bool {
+	if p == nil {
+		return false
+	}
+	for _, item := range p.Checks {
+		if item == check {
+			return true
+		}
+	}
+	return false
+}
+
+// StorageStallConfiguration defines when the storage of the primary is
+// considered stalled, and how long the failover of a primary with stalled
+// storage is delayed, waiting for it to recover
+type StorageStallConfiguration struct {
+	// The write latency of the data volume, in milliseconds, above which
+	// the storage of the primary is considered stalled (default 1000).
+	// The storage is considered working again when the latency drops
+	// below half of this value
+	// +kubebuilder:default:=1000
+	// +kubebuilder:validation:Minimum=200
+	// +optional
+	LatencyThreshold int32 `json:"latencyThreshold,omitempty"`
+
+	// The time in seconds, since the storage of the primary stalled,
+	// after which the failover is not delayed anymore (default 60)
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailoverDelay int32 `json:"failoverDelay,omitempty"`
+}
+
+// GetLatencyThreshold gets the write latency above which the storage
+// of the primary is considered stalled
+func (r *StorageStallConfiguration) GetLatencyThreshold() time.Duration {
+	if r.LatencyThreshold <= 0 {
+		return 1000 * time.Millisecond
+	}
+	return time.Duration(r.LatencyThreshold) * time.Millisecond
+}
+
+// GetFailoverDelay gets the time after which the failover of a primary
+// with stalled storage is not delayed anymore
+func (r *StorageStallConfiguration) GetFailoverDelay() time.Duration {
+	if r.FailoverDelay <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(r.FailoverDelay) * time.Second
+}
+
+// SwitchoverDrainConfiguration defines how the primary drains the
+// connections before being shut down during a switchover
+type SwitchoverDrainConfiguration struct {
+	// The time in seconds during which the primary, having stopped
+	// accepting new connections, waits for the running transactions
+	// to complete before being shut down (default 30)
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// GetTimeout gets how long the primary waits for the running
+// transactions to complete before being shut down
+func (r *SwitchoverDrainConfiguration) GetTimeout() time.Duration {
+	if r.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(r.Timeout) * time.Second
+}
+
+// DataCorruptionConfiguration defines what the operator should do when
+// an instance reports checksum failures or errors about damaged data
+type DataCorruptionConfiguration struct {
+	// Whether the instances reporting damaged data should be fenced,
+	// stopping PostgreSQL until the fence is manually lifted
+	// +kubebuilder:default:=false
+	FenceInstances bool `json:"fenceInstances"`
+}
+
+// IsFencingEnabled checks whether the instances reporting damaged data
+// should be fenced
+func (r *DataCorruptionConfiguration) IsFencingEnabled() bool {
+	return r != nil && r.FenceInstances
+}
+
+// PrimaryUpdateStrategy contains the strategy to follow when upgrading
+// the primary server of the cluster as part of rolling updates
+type PrimaryUpdateStrategy string
+
+// PrimaryUpdateMethod contains the method to use when upgrading
+// the primary server of the cluster as part of rolling updates
+type PrimaryUpdateMethod string
+
+// PVCReclaimPolicy is the policy applied to the PVCs of the instances
+// removed while scaling down
+type PVCReclaimPolicy string
+
+const (
+	// PVCReclaimPolicyDelete means that the PVCs are deleted together
+	// with the instance
+	PVCReclaimPolicyDelete PVCReclaimPolicy = "delete"
+
+	// PVCReclaimPolicyRetain means that the PVCs are detached from the
+	// cluster, and reused by the next scale up
+	PVCReclaimPolicyRetain PVCReclaimPolicy = "retain"
+)
+
+// InstanceManagerUpdateMethod contains the method to use when upgrading
+// the instance manager after an upgrade of the operator
+type InstanceManagerUpdateMethod string
+
+const (
+	// PrimaryUpdateStrategySupervised means that the operator need to wait for the
+	// user to manually issue a switchover request before updating the primary
+	// server (`supervised`)
+	PrimaryUpdateStrategySupervised PrimaryUpdateStrategy = "supervised"
+
+	// PrimaryUpdateStrategyUnsupervised means that the operator will proceed with the
+	// selected PrimaryUpdateMethod to another updated replica and then automatically update
+	// the primary server (`unsupervised`, default)
+	PrimaryUpdateStrategyUnsupervised PrimaryUpdateStrategy = "unsupervised"
+
+	// PrimaryUpdateMethodSwitchover means that the operator will switchover to another updated
+	// replica when it needs to upgrade the primary instance
+	PrimaryUpdateMethodSwitchover PrimaryUpdateMethod = "switchover"
+
+	// PrimaryUpdateMethodRestart means that the operator will restart the primary instance in-place
+	// when it needs to upgrade it
+	PrimaryUpdateMethodRestart PrimaryUpdateMethod = "restart"
+
+	// InstanceManagerUpdateMethodRollout means that the operator will issue a
+	// rolling update of the instances to upgrade the instance manager
+	InstanceManagerUpdateMethodRollout InstanceManagerUpdateMethod = "rollout"
+
+	// InstanceManagerUpdateMethodInPlace means that the operator will replace the
+	// instance manager executable inside the running Pods
+	InstanceManagerUpdateMethodInPlace InstanceManagerUpdateMethod = "inplace"
+
+	// DefaultPgCtlTimeoutForPromotion is the default for the pg_ctl timeout when a promotion is performed.
+	// It is greater than one year in seconds, big enough to simulate an infinite timeout
+	DefaultPgCtlTimeoutForPromotion = 40000000
+
+	// DefaultMaxSwitchoverDelay is the default for the pg_ctl timeout in seconds when a primary PostgreSQL instance
+	// is gracefully shutdown during a switchover.
+	// It is greater than one year in seconds, big enough to simulate an infinite timeout
+	DefaultMaxSwitchoverDelay = 40000000
+)
+
+// PostgresConfiguration defines the PostgreSQL configuration
+type PostgresConfiguration struct {
+	// PostgreSQL configuration options (postgresql.conf)
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// PostgreSQL Host Based Authentication rules (lines to be appended
+	// to the pg_hba.conf file)
+	// +optional
+	PgHBA []string `json:"pg_hba,omitempty"`
+
+	// PostgreSQL User Name Maps rules (lines to be appended
+	// to the pg_ident.conf file)
+	// +optional
+	PgIdent []string `json:"pg_ident,omitempty"`
+
+	// The users allowed to connect through a Pooler authenticating via
+	// their client certificate. For each of them, PgBouncer is allowed to
+	// use its own certificate to connect on their behalf, and no password
+	// authentication is possible anymore over SSL connections
+	// +optional
+	PoolerCertificateUsers []string `json:"poolerCertificateUsers,omitempty"`
+
+	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
+	// set up.
+	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
+
+	// Specifies the maximum number of seconds to wait when promoting an instance to primary.
+	// Default value is 40000000, greater than one year in seconds,
+	// big enough to simulate an infinite timeout
+	// +optional
+	PgCtlTimeoutForPromotion int32 `json:"promotionTimeout,omitempty"`
+
+	// Lists of shared preload libraries to add to the default ones
+	// +optional
+	AdditionalLibraries []string `json:"shared_preload_libraries,omitempty"`
+
+	// Options to specify LDAP configuration
+	// +optional
+	LDAP *LDAPConfig `json:"ldap,omitempty"`
+
+	// The TLS protocol versions and ciphers accepted by PostgreSQL
+	// +optional
+	TLS *PostgresTLSConfiguration `json:"tls,omitempty"`
+
+	// The migration of the passwords hashed with md5 to SCRAM-SHA-256.
+	// When defined, `password_encryption` is set to `scram-sha-256` and
+	// the roles whose password is still hashed with md5 are reported in
+	// the status of the cluster
+	// +optional
+	SCRAMMigration *SCRAMMigrationConfiguration `json:"scramMigration,omitempty"`
+
+	// When this option is disabled, the `postgresql.auto.conf` file is
+	// made read-only, so that `ALTER SYSTEM` cannot be used to change the
+	// configuration of the instances outside the Cluster specification.
+	// Enabled by default.
+	// +kubebuilder:default:=true
+	// +optional
+	EnableAlterSystem *bool `json:"enableAlterSystem,omitempty"`
+
+	// The extensions whose files are shipped in dedicated container
+	// images, copied into the instance Pods by an init container and
+	// made available to PostgreSQL. Requires PostgreSQL 18 or later
+	// +optional
+	Extensions []ExtensionConfiguration `json:"extensions,omitempty"`
+}
+
+// SCRAMMigrationConfiguration contains the settings of the migration of
+// the passwords hashed with md5 to SCRAM-SHA-256
+type SCRAMMigrationConfiguration struct {
+	// Hash again with SCRAM-SHA-256 the passwords of the roles managed by
+	// the operator, i.e. the superuser and the owner of the application
+	// database, reading them from their secrets. The passwords of the
+	// other roles can only be changed by their owners
+	// +optional
+	RehashManagedRoles bool `json:"rehashManagedRoles,omitempty"`
+}
+
+// TLSProtocolVersion is a version of the TLS protocol
+type TLSProtocolVersion string
+
+const (
+	// TLSProtocolVersion10 is the version 1.0 of the TLS protocol
+	TLSProtocolVersion10 TLSProtocolVersion = "TLSv1"
+
+	// TLSProtocolVersion11 is the version 1.1 of the TLS protocol
+	TLSProtocolVersion11 TLSProtocolVersion = "TLSv1.1"
+
+	// TLSProtocolVersion12 is the version 1.2 of the TLS protocol
+	TLSProtocolVersion12 TLSProtocolVersion = "TLSv1.2"
+
+	// TLSProtocolVersion13 is the version 1.3 of the TLS protocol
+	TLSProtocolVersion13 TLSProtocolVersion = "TLSv1.3"
+)
+
+// PostgresTLSConfiguration contains the TLS settings of the PostgreSQL
+// servers, for the organizations enforcing a TLS policy
+type PostgresTLSConfiguration struct {
+	// The minimum TLS protocol version accepted by the server, set as
+	// `ssl_min_protocol_version`. Requires PostgreSQL 12 or later
+	// +kubebuilder:validation:Enum=TLSv1;TLSv1.1;TLSv1.2;TLSv1.3
+	// +optional
+	MinProtocolVersion TLSProtocolVersion `json:"minProtocolVersion,omitempty"`
+
+	// The TLS ciphers allowed in the connections using TLS 1.2 or earlier,
+	// as an OpenSSL cipher list, set as `ssl_ciphers`
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9:!+@=_.,-]+$`
+	// +optional
+	Ciphers string `json:"ciphers,omitempty"`
+
+	// The name of the curve used in the ECDH key exchange, set as
+	// `ssl_ecdh_curve`
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]+$`
+	// +optional
+	ECDHCurve string `json:"ecdhCurve,omitempty"`
+}
+
+// GetParameters gets the PostgreSQL parameters applying the TLS settings
+func (configuration *PostgresTLSConfiguration) GetParameters() map[string]string {
+	if configuration == nil {
+		return nil
+	}
+
+	parameters := make(map[string]string)
+	if configuration.MinProtocolVersion != "" {
+		parameters[postgres.SSLMinProtocolVersion] = string(configuration.MinProtocolVersion)
+	}
+	if configuration.Ciphers != "" {
+		parameters[postgres.SSLCiphers] = configuration.Ciphers
+	}
+	if configuration.ECDHCurve != "" {
+		parameters[postgres.SSLECDHCurve] = configuration.ECDHCurve
+	}
+	return parameters
+}
+
+// MinorVersionPinning pins a cluster to an exact PostgreSQL minor version
+type MinorVersionPinning struct {
+	// The PostgreSQL version, in the `<major>.<minor>` format, the cluster
+	// is pinned to. It must match the tag of the image
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)+$`
+	Version string `json:"version"`
+
+	// The reason of the exception, reported in the `OutdatedMinorVersion`
+	// condition while a newer minor version is available
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImageVerificationConfiguration controls how the operator resolves
+// and verifies the operand image before rolling it out
+type ImageVerificationConfiguration struct {
+	// When enabled, the operator resolves the tag of the image to its
+	// digest and uses the digest in the Pods, so that every instance runs
+	// exactly the same image even if the tag is moved to a new one
+	// +kubebuilder:default:=false
+	// +optional
+	PinDigest bool `json:"pinDigest,omitempty"`
+
+	// The PEM encoded public keys used to verify the cosign signature of
+	// the image. An image is rolled out only when it has been signed by
+	// at least one of these keys. Setting them implies digest pinning
+	// +optional
+	PublicKeys []SecretKeySelector `json:"publicKeys,omitempty"`
+}
+
+// ImageDigestStatus is the image the operator resolved, and possibly
+// verified, for the instances of the cluster
+type ImageDigestStatus struct {
+	// The image requested in the specification of the cluster
+	Image string `json:"image"`
+
+	// The digest the image has been resolved to
+	Digest string `json:"digest"`
+
+	// True when the cosign signature of the image has been verified
+	// +optional
+	SignatureVerified bool `json:"signatureVerified,omitempty"`
+}
+
+// GetPinnedImageName gets the name of the image pinned to the resolved digest
+func (status *ImageDigestStatus) GetPinnedImageName() string {
+	reference := utils.NewReference(status.Image)
+	reference.Digest = strings.TrimPrefix(status.Digest, "sha256:")
+	return reference.GetNormalizedName()
+}
+
+// ExtensionConfiguration is an extension shipped in a container image
+// containing its control, SQL and library files
+type ExtensionConfiguration struct {
+	// The name of the extension image, used to name the init container
+	// and the directory containing its files
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=50
+	Name string `json:"name"`
+
+	// The container image containing the files of the extension
+	Image string `json:"image"`
+
+	// The directories of the image, relative to its root, containing the
+	// `extension` directory with the control and SQL files. They are added
+	// to `extension_control_path`. Defaults to `share`
+	// +optional
+	ExtensionControlPath []string `json:"extension_control_path,omitempty"`
+
+	// The directories of the image, relative to its root, containing the
+	// libraries of the extension. They are added to `dynamic_library_path`.
+	// Defaults to `lib`
+	// +optional
+	DynamicLibraryPath []string `json:"dynamic_library_path,omitempty"`
+}
+
+// LoggingConfiguration contains the configuration of the logs
+// produced by the instance manager, including the ones of PostgreSQL
+type LoggingConfiguration struct {
+	// Static fields added to every log record produced by the instances,
+	// i.e. the team owning the cluster or the environment
+	// +optional
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// The log level of specific loggers, overriding `logLevel`. The keys
+	// are the names of the loggers, i.e. `postgres` or `pgaudit`, and the
+	// values are one of: error, warning, info, debug, trace
+	// +optional
+	Loggers map[string]string `json:"loggers,omitempty"`
+}
+
+// EphemeralVolumesSizeLimitConfiguration contains the size limits
+// of the ephemeral volumes of the instance Pods
+type EphemeralVolumesSizeLimitConfiguration struct {
+	// The size limit of the shared memory volume, mounted in `/dev/shm`.
+	// The volume is backed by memory and counts against the memory
+	// limits of the PostgreSQL container
+	// +optional
+	Shm *resource.Quantity `json:"shm,omitempty"`
+
+	// The size limit of the volume where the temporary data is stored,
+	// i.e. the scratch data used by the instance manager
+	// +optional
+	TemporaryData *resource.Quantity `json:"temporaryData,omitempty"`
+}
+
+// InstancePodTemplate contains the customizations, allowed by the operator,
+// of the Pods running the PostgreSQL instances
+type InstancePodTemplate struct {
+	// Labels and annotations added to the instance Pods. The ones managed
+	// by the operator take precedence
+	// +optional
+	Metadata EmbeddedObjectMetadata `json:"metadata,omitempty"`
+
+	// Environment variables added to the PostgreSQL container. The ones
+	// set by the operator can't be overridden
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Sources of environment variables added to the PostgreSQL container
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Projected volumes mounted, read-only, in the PostgreSQL container
+	// +optional
+	ProjectedVolumes []InstanceProjectedVolume `json:"projectedVolumes,omitempty"`
+
+	// CSI volumes, such as the ones of the Secrets Store CSI driver,
+	// mounted read-only in the PostgreSQL container
+	// +optional
+	CSIVolumes []InstanceCSIVolume `json:"csiVolumes,omitempty"`
+
+	// The name of the scheduler dispatching the instance Pods. When
+	// empty, the default scheduler is used
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// The name of the PriorityClass of the instance Pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Containers running next to PostgreSQL in the instance Pods, like
+	// the agents shipping the logs. The volume where the instance manager
+	// copies the log stream is mounted in each of them under `/logs`
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+}
+
+// ServiceMeshType is a service mesh supported by the operator
+type ServiceMeshType string
+
+const (
+	// ServiceMeshTypeIstio is the Istio service mesh
+	ServiceMeshTypeIstio ServiceMeshType = "istio"
+
+	// ServiceMeshTypeLinkerd is the Linkerd service mesh
+	ServiceMeshTypeLinkerd ServiceMeshType = "linkerd"
+)
+
+// ServiceMeshConfiguration contains the settings needed to run the
+// instances, and the Jobs bootstrapping them, inside a service mesh
+type ServiceMeshConfiguration struct {
+	// The service mesh injecting the proxy sidecar: `istio` or `linkerd`
+	// +kubebuilder:validation:Enum=istio;linkerd
+	Type ServiceMeshType `json:"type"`
+
+	// Annotations added only to the Pods of the Jobs, e.g. to
+	// customize or disable the injection of the proxy sidecar
+	// +optional
+	JobAnnotations map[string]string `json:"jobAnnotations,omitempty"`
+}
+
+// InstanceProjectedVolume is a projected volume mounted in
+// the PostgreSQL container
+type InstanceProjectedVolume struct {
+	// The name of the volume, which must be unique in the Pod
+	Name string `json:"name"`
+
+	// The absolute path where the volume is mounted
+	MountPath string `json:"mountPath"`
+
+	// The sources of the projected volume
+	corev1.ProjectedVolumeSource `json:",inline"`
+}
+
+// InstanceCSIVolume is a CSI volume mounted in the PostgreSQL container
+type InstanceCSIVolume struct {
+	// The name of the volume, which must be unique in the Pod
+	Name string `json:"name"`
+
+	// The absolute path where the volume is mounted
+	MountPath string `json:"mountPath"`
+
+	// The CSI driver providing the volume and its attributes
+	corev1.CSIVolumeSource `json:",inline"`
+}
+
+// CredentialsFilesConfiguration contains the paths, in the PostgreSQL
+// container, of the files holding the passwords of the users managed by
+// the operator. The files are usually mounted from a CSI volume or
+// written by an agent injected in the Pod, like the one of Vault, and
+// contain only the password. They are read again periodically, so the
+// passwords rotated by the external secret store are applied without
+// restarting the instances
+type CredentialsFilesConfiguration struct {
+	// The absolute path of the file containing the password of the
+	// `postgres` user. When set, no superuser secret is generated
+	// +optional
+	SuperuserPasswordFile string `json:"superuserPasswordFile,omitempty"`
+
+	// The absolute path of the file containing the password of the
+	// owner of the application database. When set, no application
+	// secret is generated
+	// +optional
+	ApplicationPasswordFile string `json:"applicationPasswordFile,omitempty"`
+}
+
+// DeletionPolicy defines the teardown sequence executed when the Cluster
+// is deleted. The operator holds the deletion of the resources until
+// every step has been completed or the timeout has expired
+type DeletionPolicy struct {
+	// Take a final base backup of the cluster before its deletion.
+	// Enabled by default when a Barman object store is configured
+	// +optional
+	FinalBackup *FinalBackupConfiguration `json:"finalBackup,omitempty"`
+
+	// Archive the WAL files not yet archived, including the current one,
+	// before the deletion. Enabled by default when a Barman object store is
+	// configured
+	// +optional
+	ArchiveWAL *bool `json:"archiveWAL,omitempty"`
+
+	// Export the list of the roles and the databases of the cluster to a
+	// ConfigMap named `<cluster>-inventory`, which is retained after
+	// the deletion of the cluster
+	// +optional
+	ExportInventory bool `json:"exportInventory,omitempty"`
+
+	// The time in seconds allowed to complete the teardown sequence,
+	// after which the resources of the cluster are removed anyway
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// FinalBackupConfiguration contains the configuration of the backup
+// taken before the deletion of a cluster
+type FinalBackupConfiguration struct {
+	// Set to false to not take the final backup
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// The method used to take the final backup. Only `barmanObjectStore`
+	// is currently supported
+	// +kubebuilder:default:=barmanObjectStore
+	// +kubebuilder:validation:Enum:=barmanObjectStore
+	// +optional
+	Method FinalBackupMethod `json:"method,omitempty"`
+
+	// The time in seconds allowed for the final backup to complete, after
+	// which the deletion proceeds without it. It can't exceed the timeout
+	// of the deletion policy, which is used when not specified
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// FinalBackupMethod is the method used to take the final backup of a cluster
+type FinalBackupMethod string
+
+const (
+	// FinalBackupMethodBarmanObjectStore takes the final backup in the
+	// Barman object store configured for the cluster
+	FinalBackupMethodBarmanObjectStore FinalBackupMethod = "barmanObjectStore"
+)
+
+// ManagedConfiguration contains the PostgreSQL objects whose existence
+// is enforced by the instance manager running on the primary, and the
+// policies enforced by the instance manager on every instance
+type ManagedConfiguration struct {
+	// The databases to be created, when missing, with their owners
+	// +optional
+	Databases []ManagedDatabase `json:"databases,omitempty"`
+
+	// The limits applied to the sessions of the superusers and of
+	// the administrative roles, which are terminated by the instance
+	// manager when exceeded
+	// +optional
+	AdminSessionPolicy *AdminSessionPolicy `json:"adminSessionPolicy,omitempty"`
+
+	// The customizations of the `-rw`, `-ro` and `-r` services generated
+	// by the operator, and the additional services to be created
+	// +optional
+	Services *ManagedServices `json:"services,omitempty"`
+}
+
+// ServiceSelectorType is the set of instances a service points to
+type ServiceSelectorType string
+
+const (
+	// ServiceSelectorTypeRW selects the primary instance
+	ServiceSelectorTypeRW ServiceSelectorType = "rw"
+
+	// ServiceSelectorTypeRO selects the replicas
+	ServiceSelectorTypeRO ServiceSelectorType = "ro"
+
+	// ServiceSelectorTypeR selects every ready instance
+	ServiceSelectorTypeR ServiceSelectorType = "r"
+)
+
+// ManagedServices contains the customizations of the services generated
+// by the operator and the additional services to be created
+type ManagedServices struct {
+	// The customizations of the services generated by the operator,
+	// one for each selector type
+	// +optional
+	Templates []ServiceTemplate `json:"templates,omitempty"`
+
+	// Additional services, pointing to the PostgreSQL port of the
+	// instances, which are created, updated and deleted by the operator
+	// +optional
+	Additional []AdditionalService `json:"additional,omitempty"`
+
+	// When set, the operator creates a service for each instance, named
+	// after it and pointing only to its Pod, customized with this template.
+	// The service is kept when the Pod is recreated, giving the instance a
+	// stable DNS name
+	// +optional
+	PerInstance *ServiceTemplateSpec `json:"perInstance,omitempty"`
+}
+
+// ServiceTemplateSpec contains the customizations, allowed by the
+// operator, of a service pointing to the instances
+type ServiceTemplateSpec struct {
+	// Labels and annotations added to the service, e.g. to be used by
+	// the external DNS providers. The ones managed by the operator take
+	// precedence
+	// +optional
+	Metadata EmbeddedObjectMetadata `json:"metadata,omitempty"`
+
+	// The type of the service (default: `ClusterIP`)
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// The session affinity of the service, `None` or `ClientIP`
+	// (default: `None`)
+	// +kubebuilder:validation:Enum=None;ClientIP
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+
+	// The external traffic policy of the service, `Cluster` or `Local`,
+	// only used by the `NodePort` and `LoadBalancer` types
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+
+	// The client IP ranges allowed to access a `LoadBalancer` service
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+}
+
+// ServiceTemplate is the customization of a service generated by the operator
+type ServiceTemplate struct {
+	// The service to be customized: `rw`, `ro` or `r`
+	// +kubebuilder:validation:Enum=rw;ro;r
+	SelectorType ServiceSelectorType `json:"selectorType"`
+
+	// Removes the primary from the `r` service, which then only points
+	// to the ready standbys. Only valid for the `r` service
+	// +optional
+	ExcludePrimary bool `json:"excludePrimary,omitempty"`
+
+	// Makes the `ro` service point to the primary when no standby is
+	// ready, instead of leaving it without endpoints. Only valid for
+	// the `ro` service
+	// +optional
+	PrimaryFallback bool `json:"primaryFallback,omitempty"`
+
+	ServiceTemplateSpec `json:",inline"`
+}
+
+// AdditionalService is a service, created by the operator,
+// pointing to a subset of the instances
+type AdditionalService struct {
+	// The name of the service, which must not collide with the
+	// ones of the services generated by the operator
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The instances the service points to: `rw`, `ro` or `r`
+	// +kubebuilder:validation:Enum=rw;ro;r
+	SelectorType ServiceSelectorType `json:"selectorType"`
+
+	// Labels, added to the selector of the chosen type, that the instance
+	// Pods must have to be pointed by the service, e.g. the ones set in
+	// the Pod template
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	ServiceTemplateSpec `json:",inline"`
+}
+
+// AdminSessionPolicy limits the lifetime of the privileged sessions. The
+// sessions opened by the instance manager itself are never terminated
+type AdminSessionPolicy struct {
+	// The time in seconds after which an idle session, including the
+	// ones idle in a transaction, is terminated. Zero disables the limit
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IdleTimeout int32 `json:"idleTimeout,omitempty"`
+
+	// The time in seconds after which a session is terminated, whatever
+	// its state is. Zero disables the limit
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDuration int32 `json:"maxDuration,omitempty"`
+
+	// The roles, besides the superusers, whose sessions are subject to
+	// the policy
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+}
+
+// ManagedDatabase is a database managed by the instance manager
+type ManagedDatabase struct {
+	// The name of the database
+	Name string `json:"name"`
+
+	// The name of the role owning the database, created with the LOGIN
+	// attribute when missing. Defaults to the name of the database
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// When enabled, the operator generates a Service and a basic-auth
+	// Secret, both named `<cluster>-db-<name>`, to connect to the primary
+	// as the owner of the database. The password in the Secret is applied
+	// to the owner
+	// +optional
+	Expose bool `json:"expose,omitempty"`
+
+	// The interval after which the operator generates a new password for
+	// the owner of an exposed database, updating its Secret, which is
+	// annotated with the time of the rotation. Requires `expose`
+	// +optional
+	PasswordRotation *metav1.Duration `json:"passwordRotation,omitempty"`
+
+	// The maximum number of concurrent connections to the database, -1
+	// meaning no limit. When not set, the limit is not managed
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	ConnectionLimit *int32 `json:"connectionLimit,omitempty"`
+
+	// The configuration parameters set for the sessions connecting to the
+	// database, as done by `ALTER DATABASE ... SET`, like `search_path`,
+	// `statement_timeout` or `default_transaction_read_only`. The
+	// parameters set on the database and not listed here are reset. When
+	// not set, the parameters of the database are not managed
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The privileges granted to other roles on the database and on the
+	// objects of its schemas. The privileges directly granted to these
+	// roles and not listed here are revoked
+	// +optional
+	Grants []ManagedGrant `json:"grants,omitempty"`
+
+	// The extensions to be installed in the database or, when `ensure`
+	// is `absent`, removed from it
+	// +optional
+	Extensions []ManagedDatabaseExtension `json:"extensions,omitempty"`
+
+	// The foreign data wrappers to be created in the database or, when
+	// `ensure` is `absent`, dropped from it. The wrappers created by an
+	// extension, like `postgres_fdw`, don't need to be listed here
+	// +optional
+	ForeignDataWrappers []ManagedForeignDataWrapper `json:"foreignDataWrappers,omitempty"`
+
+	// The foreign servers to be created in the database, owned by the
+	// owner of the database, together with their user mappings
+	// +optional
+	ForeignServers []ManagedForeignServer `json:"foreignServers,omitempty"`
+}
+
+// ManagedForeignDataWrapper is a foreign data wrapper whose definition
+// in a managed database is enforced by the instance manager
+type ManagedForeignDataWrapper struct {
+	// The name of the foreign data wrapper
+	Name string `json:"name"`
+
+	// The name of the handler function of the foreign data wrapper,
+	// applied when it is created
+	// +optional
+	Handler string `json:"handler,omitempty"`
+
+	// The name of the validator function of the foreign data wrapper,
+	// applied when it is created
+	// +optional
+	Validator string `json:"validator,omitempty"`
+
+	// The options of the foreign data wrapper. The options not listed
+	// here are removed
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+
+	// Whether the foreign data wrapper must exist, `present` (default),
+	// or be dropped, `absent`
+	// +kubebuilder:default:=present
+	// +kubebuilder:validation:Enum:=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// ManagedForeignServer is a foreign server whose definition in a managed
+// database is enforced by the instance manager
+type ManagedForeignServer struct {
+	// The name of the foreign server
+	Name string `json:"name"`
+
+	// The name of the foreign data wrapper used by the server, that
+	// can't be changed once the server is created
+	// +optional
+	ForeignDataWrapper string `json:"foreignDataWrapper,omitempty"`
+
+	// The options of the foreign server, i.e. `host` and `dbname` for
+	// `postgres_fdw`. The options not listed here are removed
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+
+	// The user mappings of the foreign server. The user mappings not
+	// listed here are left untouched
+	// +optional
+	UserMappings []ManagedUserMapping `json:"userMappings,omitempty"`
+
+	// Whether the foreign server must exist, `present` (default), or be
+	// dropped, `absent`, together with its user mappings
+	// +kubebuilder:default:=present
+	// +kubebuilder:validation:Enum:=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// ManagedUserMapping is the mapping of a local role to the credentials
+// used to connect to a foreign server
+type ManagedUserMapping struct {
+	// The name of the local role, or `PUBLIC` for the mapping used
+	// by the roles without a specific one
+	User string `json:"user"`
+
+	// The options of the user mapping. The options not listed here,
+	// nor taken from the credentials Secret, are removed
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+
+	// The name of a basic-auth Secret, in the namespace of the cluster,
+	// whose username and password are set as the `user` and `password`
+	// options of the user mapping
+	// +optional
+	CredentialsSecret *LocalObjectReference `json:"credentialsSecret,omitempty"`
+
+	// Whether the user mapping must exist, `present` (default), or be
+	// dropped, `absent`
+	// +kubebuilder:default:=present
+	// +kubebuilder:validation:Enum:=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// EnsureOption tells whether an object must exist or not
+type EnsureOption string
+
+const (
+	// EnsurePresent means the object must exist
+	EnsurePresent EnsureOption = "present"
+
+	// EnsureAbsent means the object must not exist
+	EnsureAbsent EnsureOption = "absent"
+)
+
+// ManagedDatabaseExtension is an extension whose installation in a
+// managed database is enforced by the instance manager
+type ManagedDatabaseExtension struct {
+	// The name of the extension
+	Name string `json:"name"`
+
+	// The version of the extension. The extension is updated when a
+	// different version is installed. Defaults to the default version
+	// of the extension when it is created
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema containing the objects of the extension. The objects
+	// are moved when they are in a different schema, if the extension
+	// supports it. Defaults to the current schema when the extension
+	// is created
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The libraries required by the extension, which are added to
+	// `shared_preload_libraries`. The extension is created once the
+	// instance has been restarted loading them
+	// +optional
+	SharedPreloadLibraries []string `json:"sharedPreloadLibraries,omitempty"`
+
+	// Whether the extension must be installed, `present` (default),
+	// or dropped, `absent`
+	// +kubebuilder:default:=present
+	// +kubebuilder:validation:Enum:=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// DatabasePrivilege is a privilege that can be granted on a database
+// +kubebuilder:validation:Enum=CONNECT;CREATE;TEMPORARY
+type DatabasePrivilege string
+
+// SchemaPrivilege is a privilege that can be granted on a schema
+// +kubebuilder:validation:Enum=USAGE;CREATE
+type SchemaPrivilege string
+
+// TablePrivilege is a privilege that can be granted on a table
+// +kubebuilder:validation:Enum=SELECT;INSERT;UPDATE;DELETE;TRUNCATE;REFERENCES;TRIGGER
+type TablePrivilege string
+
+// ManagedGrant is a set of privileges granted to a role on a
+// managed database and on the objects of its schemas
+type ManagedGrant struct {
+	// The role receiving the privileges. The privileges of a role
+	// which doesn't exist are applied once it is created
+	Role string `json:"role"`
+
+	// The privileges on the database
+	// +optional
+	Privileges []DatabasePrivilege `json:"privileges,omitempty"`
+
+	// The privileges on the schemas of the database
+	// +optional
+	Schemas []ManagedSchemaGrant `json:"schemas,omitempty"`
+}
+
+// ManagedSchemaGrant is a set of privileges granted on a schema and on its tables
+type ManagedSchemaGrant struct {
+	// The name of the schema. The privileges on a schema which
+	// doesn't exist are applied once it is created
+	Name string `json:"name"`
+
+	// The privileges on the schema
+	// +optional
+	Privileges []SchemaPrivilege `json:"privileges,omitempty"`
+
+	// The privileges on every table and view of the schema
+	// +optional
+	TablePrivileges []TablePrivilege `json:"tablePrivileges,omitempty"`
+
+	// When enabled, the table privileges are also granted by default
+	// on the tables that the owner of the database will create in the schema
+	// +optional
+	DefaultPrivileges bool `json:"defaultPrivileges,omitempty"`
+}
+
+// BootstrapConfiguration contains information about how to create the PostgreSQL
+// cluster. Only a single bootstrap method can be defined among the supported
+// ones. `initdb` will be used as the bootstrap method if left
+// unspecified. Refer to the Bootstrap page of the documentation for more
+// information.
+type BootstrapConfiguration struct {
+	// Bootstrap the cluster via initdb
+	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+
+	// Bootstrap the cluster from a backup
+	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+
+	// Bootstrap the cluster taking a physical backup of another compatible
+	// PostgreSQL instance
+	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
+
+	// Bootstrap the cluster adopting an existing PostgreSQL data directory
+	// stored inside a PersistentVolumeClaim
+	ExistingVolume *BootstrapExistingVolume `json:"existingVolume,omitempty"`
+}
+
+// LDAPScheme defines the possible schemes for LDAP
+type LDAPScheme string
 
 // These are the valid LDAP schemes
 const (
@@ -752,6 +2388,12 @@ type LDAPConfig struct {
 	// Set to 'true' to enable LDAP over TLS. 'false' is default
 	TLS bool `json:"tls,omitempty"`
 
+	// Secret containing the CA certificate used to verify the LDAP
+	// server certificate when using `ldaps` or TLS. The system CA
+	// certificates are used when not specified
+	// +optional
+	CACertificate *SecretKeySelector `json:"caCertificate,omitempty"`
+
 	// Bind as authentication configuration
 	BindAsAuth *LDAPBindAsAuth `json:"bindAsAuth,omitempty"`
 
@@ -822,6 +2464,21 @@ type CertificatesConfiguration struct {
 
 	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
 	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
+
+	// The lifetime, in days, of the certificates generated by the operator
+	// for this cluster. Defaults to the `CERTIFICATE_DURATION` setting of
+	// the operator, which is 90 days unless configured otherwise
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CertificateDuration *int `json:"certificateDuration,omitempty"`
+
+	// How many days before their expiration the certificates generated by the
+	// operator for this cluster are renewed. Defaults to the
+	// `EXPIRING_CHECK_THRESHOLD` setting of the operator, which is 7 days
+	// unless configured otherwise
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ExpiringCheckThreshold *int `json:"expiringCheckThreshold,omitempty"`
 }
 
 // CertificatesStatus contains configuration certificates and related expiration dates.
@@ -871,6 +2528,28 @@ type BootstrapInitDB struct {
 	// The value to be passed as option `--lc-ctype` for initdb (default:`C`)
 	LocaleCType string `json:"localeCType,omitempty"`
 
+	// The value to be passed as option `--locale-provider` for initdb:
+	// `libc`, `icu` (PostgreSQL 15 or later) or `builtin` (PostgreSQL 17
+	// or later). Default: not set, resulting in the PostgreSQL default (`libc`)
+	// +kubebuilder:validation:Enum=libc;icu;builtin
+	// +optional
+	LocaleProvider LocaleProvider `json:"localeProvider,omitempty"`
+
+	// The value to be passed as option `--icu-locale` for initdb, selecting
+	// the ICU locale when the `icu` locale provider is used
+	// +optional
+	IcuLocale string `json:"icuLocale,omitempty"`
+
+	// The value to be passed as option `--icu-rules` for initdb, customizing
+	// the collation rules of the ICU locale (PostgreSQL 16 or later)
+	// +optional
+	IcuRules string `json:"icuRules,omitempty"`
+
+	// The value to be passed as option `--builtin-locale` for initdb, selecting
+	// the locale when the `builtin` locale provider is used (e.g. `C.UTF-8`)
+	// +optional
+	BuiltinLocale string `json:"builtinLocale,omitempty"`
+
 	// The value in megabytes (1 to 1024) to be passed to the `--wal-segsize`
 	// option for initdb (default: empty, resulting in PostgreSQL default: 16MB)
 	// +kubebuilder:validation:Minimum=1
@@ -892,6 +2571,22 @@ type BootstrapInitDB struct {
 	// (by default empty)
 	PostInitTemplateSQL []string `json:"postInitTemplateSQL,omitempty"`
 
+	// PostInitSQLRefs points references to ConfigMaps or Secrets which
+	// contain SQL files to be executed as a superuser immediately after
+	// the cluster has been created, right after `postInitSQL`. The general
+	// implementation order to these references is from all Secrets to all
+	// ConfigMaps, and inside Secrets or ConfigMaps, the implementation order
+	// is same as the order of each array (by default empty)
+	PostInitSQLRefs *PostInitApplicationSQLRefs `json:"postInitSQLRefs,omitempty"`
+
+	// PostInitTemplateSQLRefs points references to ConfigMaps or Secrets which
+	// contain SQL files to be executed as a superuser in the `template1`
+	// database, right after `postInitTemplateSQL`. The general implementation
+	// order to these references is from all Secrets to all ConfigMaps, and
+	// inside Secrets or ConfigMaps, the implementation order is same as the
+	// order of each array (by default empty)
+	PostInitTemplateSQLRefs *PostInitApplicationSQLRefs `json:"postInitTemplateSQLRefs,omitempty"`
+
 	// Bootstraps the new cluster by importing data from an existing PostgreSQL
 	// instance using logical backup (`pg_dump` and `pg_restore`)
 	Import *Import `json:"import,omitempty"`
@@ -904,6 +2599,20 @@ type BootstrapInitDB struct {
 	PostInitApplicationSQLRefs *PostInitApplicationSQLRefs `json:"postInitApplicationSQLRefs,omitempty"`
 }
 
+// LocaleProvider is the provider of the locale of the databases
+type LocaleProvider string
+
+const (
+	// LocaleProviderLibc uses the locales of the operating system
+	LocaleProviderLibc LocaleProvider = "libc"
+
+	// LocaleProviderICU uses the locales of the ICU library
+	LocaleProviderICU LocaleProvider = "icu"
+
+	// LocaleProviderBuiltin uses the locales built into PostgreSQL
+	LocaleProviderBuiltin LocaleProvider = "builtin"
+)
+
 // SnapshotType is a type of allowed import
 type SnapshotType string
 
@@ -934,6 +2643,77 @@ type Import struct {
 	// database right after is imported - to be used with extreme care
 	// (by default empty). Only available in microservice type.
 	PostImportApplicationSQL []string `json:"postImportApplicationSQL,omitempty"`
+
+	// The number of parallel jobs used by pg_dump and pg_restore to export
+	// and import each database (by default 1). Using more than one job
+	// requires the origin to be PostgreSQL 9.6 or later
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Jobs int `json:"jobs,omitempty"`
+
+	// Whether to run ANALYZE on the imported databases, updating the
+	// statistics used by the planner (by default true)
+	// +optional
+	PostImportAnalyze *bool `json:"postImportAnalyze,omitempty"`
+
+	// Keeps the imported database in sync with the origin through logical
+	// replication, until the cutover is requested. Only available in the
+	// microservice type
+	// +optional
+	Online *OnlineImport `json:"online,omitempty"`
+}
+
+// OnlineImport configures the online migration of a database: only its
+// schema is imported, while the data is copied and kept in sync through
+// a subscription to the origin database
+type OnlineImport struct {
+	// The name of the publication, in the origin database, including the
+	// tables to be migrated. When not specified, a publication named
+	// `cnpg_online_import` including all the tables is created
+	// +optional
+	PublicationName string `json:"publicationName,omitempty"`
+
+	// Requests the cutover: the writes on the origin database are stopped,
+	// and the migration is completed as soon as the imported database has
+	// caught up, aligning the sequences and removing the subscription
+	// +optional
+	Cutover bool `json:"cutover,omitempty"`
+}
+
+// OnlineImportDefaultPublicationName is the name of the publication
+// created in the origin database when not specified
+const OnlineImportDefaultPublicationName = "cnpg_online_import"
+
+// OnlineImportSubscriptionName is the name of the subscription
+// following the origin of an online import
+const OnlineImportSubscriptionName = "cnpg_online_import"
+
+// GetPublicationName gets the name of the publication
+// in the origin database
+func (o *OnlineImport) GetPublicationName() string {
+	if o.PublicationName == "" {
+		return OnlineImportDefaultPublicationName
+	}
+	return o.PublicationName
+}
+
+// IsOnline checks whether the import will keep following the origin
+func (s *Import) IsOnline() bool {
+	return s != nil && s.Online != nil
+}
+
+// GetJobs gets the number of parallel jobs to be used to
+// export and import each database
+func (s *Import) GetJobs() int {
+	if s.Jobs < 1 {
+		return 1
+	}
+	return s.Jobs
+}
+
+// ShouldAnalyze checks whether the imported databases should be analyzed
+func (s *Import) ShouldAnalyze() bool {
+	return s.PostImportAnalyze == nil || *s.PostImportAnalyze
 }
 
 // ImportSource describes the source for the logical snapshot
@@ -992,22 +2772,63 @@ type BootstrapRecovery struct {
 	Secret *LocalObjectReference `json:"secret,omitempty"`
 }
 
-// BackupSource contains the backup we need to restore from, plus some
-// information that could be needed to correctly restore it.
-type BackupSource struct {
-	LocalObjectReference `json:",inline"`
-	// EndpointCA store the CA bundle of the barman endpoint.
-	// Useful when using self-signed certificates to avoid
-	// errors with certificate issuer and barman-cloud-wal-archive.
-	EndpointCA *SecretKeySelector `json:"endpointCA,omitempty"`
-}
-
-// BootstrapPgBaseBackup contains the configuration required to take
-// a physical backup of an existing PostgreSQL cluster
-type BootstrapPgBaseBackup struct {
-	// The name of the server of which we need to take a physical backup
+// BackupSource contains the backup we need to restore from, plus some
+// information that could be needed to correctly restore it.
+type BackupSource struct {
+	LocalObjectReference `json:",inline"`
+	// EndpointCA store the CA bundle of the barman endpoint.
+	// Useful when using self-signed certificates to avoid
+	// errors with certificate issuer and barman-cloud-wal-archive.
+	EndpointCA *SecretKeySelector `json:"endpointCA,omitempty"`
+}
+
+// BootstrapPgBaseBackup contains the configuration required to take
+// a physical backup of an existing PostgreSQL cluster
+type BootstrapPgBaseBackup struct {
+	// The name of the server of which we need to take a physical backup
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Name of the database used by the application. Default: `app`.
+	// +optional
+	Database string `json:"database"`
+
+	// Name of the owner of the database in the instance to be used
+	// by applications. Defaults to the value of the `database` key.
+	// +optional
+	Owner string `json:"owner"`
+
+	// Name of the secret containing the initial credentials for the
+	// owner of the user database. If empty a new secret will be
+	// created from scratch
+	// +optional
+	Secret *LocalObjectReference `json:"secret,omitempty"`
+}
+
+// BootstrapExistingVolume contains the configuration required to adopt
+// a pre-populated PersistentVolumeClaim containing a valid PostgreSQL
+// data directory (e.g. migrated from a virtual machine), converting it
+// into the first instance of the cluster.
+// After the data directory has been verified, the volume is adopted as the
+// PGDATA storage of the first instance, without copying it.
+type BootstrapExistingVolume struct {
+	// The name of the PersistentVolumeClaim, in the same namespace of the
+	// cluster, containing the data directory to be adopted. As it becomes
+	// the storage of the first instance, it must be named `<cluster>-1`
 	// +kubebuilder:validation:MinLength=1
-	Source string `json:"source"`
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+
+	// The path of the data directory relative to the root of the volume.
+	// Defaults to the root of the volume
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
+	// The expected database system identifier of the data directory,
+	// as reported by `pg_controldata`. When specified, the bootstrap
+	// process fails if the adopted data directory has a different one
+	// +kubebuilder:validation:Pattern=^[0-9]+$
+	// +optional
+	SystemIdentifier string `json:"systemIdentifier,omitempty"`
 
 	// Name of the database used by the application. Default: `app`.
 	// +optional
@@ -1079,6 +2900,12 @@ type StorageConfiguration struct {
 	// Template to be used to generate the Persistent Volume Claim
 	// +optional
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
+
+	// Recreate, one at a time, the instances whose PVCs are not using the
+	// requested storage class, finishing with a switchover to move the
+	// primary. Defaults to false
+	// +optional
+	MigrateStorageClass bool `json:"migrateStorageClass,omitempty"`
 }
 
 // SyncReplicaElectionConstraints contains the constraints for sync replicas election.
@@ -1283,8 +3110,53 @@ type WalBackupConfiguration struct {
 	// value - with 1 being the minimum accepted value.
 	// +kubebuilder:validation:Minimum=1
 	MaxParallel int `json:"maxParallel,omitempty"`
+
+	// A command executed on every WAL file before it is sent to the
+	// object store, e.g. to copy it to a compliance store or to record
+	// its checksum in an external ledger
+	// +optional
+	ArchiveHook *WalArchiveHook `json:"archiveHook,omitempty"`
+}
+
+// WalArchiveHook is a command executed by the instance manager on every
+// WAL file being archived. The path of the WAL file, relative to the
+// PGDATA directory, is appended to the arguments of the command
+type WalArchiveHook struct {
+	// The command to be executed, followed by its arguments. The executable
+	// must be available in the PostgreSQL container image
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// What to do when the command fails or times out: `Fail` (default)
+	// doesn't archive the WAL file, which will be retried by PostgreSQL,
+	// while `BestEffort` archives the WAL file anyway
+	// +kubebuilder:default:=Fail
+	// +kubebuilder:validation:Enum:=Fail;BestEffort
+	// +optional
+	FailurePolicy WalArchiveHookFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// The time in seconds allowed to the command to complete, after
+	// which it is killed and considered failed
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
 }
 
+// WalArchiveHookFailurePolicy is the behavior of the WAL archiver
+// when the archive hook fails
+type WalArchiveHookFailurePolicy string
+
+const (
+	// WalArchiveHookFailurePolicyFail prevents the WAL file from being
+	// archived when the hook fails
+	WalArchiveHookFailurePolicyFail WalArchiveHookFailurePolicy = "Fail"
+
+	// WalArchiveHookFailurePolicyBestEffort archives the WAL file
+	// even when the hook fails
+	WalArchiveHookFailurePolicyBestEffort WalArchiveHookFailurePolicy = "BestEffort"
+)
+
 // DataBackupConfiguration is the configuration of the backup of
 // the data directory
 type DataBackupConfiguration struct {
@@ -1397,6 +3269,108 @@ type MonitoringConfiguration struct {
 	// Enable or disable the `PodMonitor`
 	// +kubebuilder:default:=false
 	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// The configuration of the `pg_stat_statements` collector
+	// +optional
+	PgStatStatements *PgStatStatementsConfiguration `json:"pgStatStatements,omitempty"`
+
+	// The configuration of the wait event sampling collector
+	// +optional
+	WaitEventSampling *WaitEventSamplingConfiguration `json:"waitEventSampling,omitempty"`
+}
+
+// NetworkPolicyConfiguration is the type containing the configuration
+// of the NetworkPolicy generated by the operator for a certain cluster
+type NetworkPolicyConfiguration struct {
+	// When enabled, the operator creates a NetworkPolicy allowing only the
+	// replication traffic between the instances, the requests coming from
+	// the operator and the PostgreSQL connections coming from the namespace
+	// of the cluster and from the allowed namespaces
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The namespaces, other than the one of the cluster, whose Pods are
+	// allowed to connect to PostgreSQL
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// InstanceGroup is a group of instances sharing the same resources,
+// storage class and node selector
+type InstanceGroup struct {
+	// The name of the group, which must be unique inside the cluster
+	Name string `json:"name"`
+
+	// Number of instances belonging to this group
+	// +kubebuilder:validation:Minimum=1
+	Instances int `json:"instances"`
+
+	// Resources requirements of the instances of this group, replacing
+	// the ones of the cluster
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The storage class used for the volumes of the instances of this
+	// group, replacing the one of the cluster
+	// +optional
+	StorageClass *string `json:"storageClass,omitempty"`
+
+	// The node selector of the instances of this group, merged with the
+	// one of the cluster
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// When true, the instances of this group are never promoted to primary,
+	// neither during a failover nor during a switchover
+	// +kubebuilder:default:=false
+	// +optional
+	ExcludedFromFailover bool `json:"excludedFromFailover,omitempty"`
+}
+
+// InstanceOverride contains the settings of a single instance replacing
+// the ones of the cluster and of the instance group. It is not part of
+// the specification, being read from the `cnpg.io/instanceOverrides`
+// annotation of the cluster
+type InstanceOverride struct {
+	// Resources requirements of the instance
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The node selector of the instance, merged with the other ones
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+// PgStatStatementsConfiguration controls the collector exporting the
+// statistics gathered by the `pg_stat_statements` extension
+type PgStatStatementsConfiguration struct {
+	// Load the `pg_stat_statements` extension in every database and export
+	// the statistics of the most time-consuming queries as metrics.
+	// Default: false.
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The number of queries, ranked by total execution time, whose
+	// statistics are exported. Default: 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	TopN int `json:"topN,omitempty"`
+}
+
+// WaitEventSamplingConfiguration controls the collector sampling the
+// wait events of the sessions connected to the instance
+type WaitEventSamplingConfiguration struct {
+	// Periodically sample the wait events of the active sessions from
+	// `pg_stat_activity`, exporting the estimated time spent waiting
+	// per database and wait event. Default: false.
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The sampling period, in milliseconds. Default: 1000.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=60000
+	// +optional
+	Period int `json:"period,omitempty"`
 }
 
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
@@ -1404,6 +3378,440 @@ func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
 	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
 }
 
+// IsPgStatStatementsEnabled checks whether the pg_stat_statements collector is enabled
+func (m *MonitoringConfiguration) IsPgStatStatementsEnabled() bool {
+	return m != nil && m.PgStatStatements != nil && m.PgStatStatements.Enabled
+}
+
+// GetPgStatStatementsTopN gets the number of queries whose statistics
+// are exported by the pg_stat_statements collector
+func (m *MonitoringConfiguration) GetPgStatStatementsTopN() int {
+	if m == nil || m.PgStatStatements == nil || m.PgStatStatements.TopN == 0 {
+		return DefaultPgStatStatementsTopN
+	}
+	return m.PgStatStatements.TopN
+}
+
+// IsWaitEventSamplingEnabled checks whether the wait event sampling collector is enabled
+func (m *MonitoringConfiguration) IsWaitEventSamplingEnabled() bool {
+	return m != nil && m.WaitEventSampling != nil && m.WaitEventSampling.Enabled
+}
+
+// GetWaitEventSamplingPeriod gets the period used to sample the wait
+// events, defaulting to DefaultWaitEventSamplingPeriod
+func (m *MonitoringConfiguration) GetWaitEventSamplingPeriod() time.Duration {
+	if m == nil || m.WaitEventSampling == nil || m.WaitEventSampling.Period <= 0 {
+		return DefaultWaitEventSamplingPeriod * time.Millisecond
+	}
+	return time.Duration(m.WaitEventSampling.Period) * time.Millisecond
+}
+
+// GetWALCompression gets the method used to compress the full page
+// images written to the WAL, if specified
+func (r *ReplicationConfiguration) GetWALCompression() WALCompressionMethod {
+	if r == nil {
+		return ""
+	}
+	return r.WALCompression
+}
+
+// GetCloneThrottling gets the throttling of the clones run by the
+// new replicas, if any
+func (r *ReplicationConfiguration) GetCloneThrottling() *CloneThrottlingConfiguration {
+	if r == nil {
+		return nil
+	}
+	return r.CloneThrottling
+}
+
+// IsVolumeCloneEnabled checks whether the new replicas are created by
+// cloning the volume of a standby
+func (r *ReplicationConfiguration) IsVolumeCloneEnabled() bool {
+	return r != nil && r.CloneMethod == ReplicaCloneMethodVolumeClone
+}
+
+// GetMaxStandbyLag gets the maximum replay lag, in bytes, of a ready
+// standby, zero meaning that the lag is not checked
+func (r *ReplicationConfiguration) GetMaxStandbyLag() int64 {
+	if r == nil || r.MaxStandbyLag == nil {
+		return 0
+	}
+	return r.MaxStandbyLag.Value()
+}
+
+// GetEnv gets the environment variables added to the PostgreSQL container
+func (t *InstancePodTemplate) GetEnv() []corev1.EnvVar {
+	if t == nil {
+		return nil
+	}
+	return t.Env
+}
+
+// GetEnvFrom gets the sources of the environment variables
+// added to the PostgreSQL container
+func (t *InstancePodTemplate) GetEnvFrom() []corev1.EnvFromSource {
+	if t == nil {
+		return nil
+	}
+	return t.EnvFrom
+}
+
+// GetCSIVolumes gets the CSI volumes mounted in the PostgreSQL container
+func (t *InstancePodTemplate) GetCSIVolumes() []InstanceCSIVolume {
+	if t == nil {
+		return nil
+	}
+	return t.CSIVolumes
+}
+
+// GetProjectedVolumes gets the projected volumes mounted in the PostgreSQL container
+func (t *InstancePodTemplate) GetProjectedVolumes() []InstanceProjectedVolume {
+	if t == nil {
+		return nil
+	}
+	return t.ProjectedVolumes
+}
+
+// GetSchedulerName gets the name of the scheduler of the instance Pods
+func (t *InstancePodTemplate) GetSchedulerName() string {
+	if t == nil {
+		return ""
+	}
+	return t.SchedulerName
+}
+
+// GetPriorityClassName gets the name of the PriorityClass of the instance Pods
+func (t *InstancePodTemplate) GetPriorityClassName() string {
+	if t == nil {
+		return ""
+	}
+	return t.PriorityClassName
+}
+
+// GetSidecars gets the containers running next to PostgreSQL in the instance Pods
+func (t *InstancePodTemplate) GetSidecars() []corev1.Container {
+	if t == nil {
+		return nil
+	}
+	return t.Sidecars
+}
+
+// IsFinalBackupEnabled checks whether a final backup should be taken
+// before deleting a cluster whose backups are configured
+func (d *DeletionPolicy) IsFinalBackupEnabled() bool {
+	return d == nil || d.FinalBackup == nil || d.FinalBackup.Enabled == nil || *d.FinalBackup.Enabled
+}
+
+// IsFinalBackupRequested checks whether the final backup has been
+// explicitly enabled, rather than being enabled by default
+func (d *DeletionPolicy) IsFinalBackupRequested() bool {
+	return d != nil && d.FinalBackup != nil && d.FinalBackup.Enabled != nil && *d.FinalBackup.Enabled
+}
+
+// IsArchiveWALEnabled checks whether the remaining WAL files should be archived
+// before deleting a cluster whose WAL archiving is configured
+func (d *DeletionPolicy) IsArchiveWALEnabled() bool {
+	return d == nil || d.ArchiveWAL == nil || *d.ArchiveWAL
+}
+
+// IsArchiveWALRequested checks whether the archiving of the remaining WAL
+// files has been explicitly enabled, rather than being enabled by default
+func (d *DeletionPolicy) IsArchiveWALRequested() bool {
+	return d != nil && d.ArchiveWAL != nil && *d.ArchiveWAL
+}
+
+// IsExportInventoryEnabled checks whether the roles and databases should
+// be exported before deleting a cluster
+func (d *DeletionPolicy) IsExportInventoryEnabled() bool {
+	return d != nil && d.ExportInventory
+}
+
+// GetTimeout gets the time allowed to complete the teardown sequence
+func (d *DeletionPolicy) GetTimeout() time.Duration {
+	if d == nil || d.Timeout == 0 {
+		return DefaultDeletionPolicyTimeout * time.Second
+	}
+	return time.Duration(d.Timeout) * time.Second
+}
+
+// GetFinalBackupMethod gets the method used to take the final backup
+func (d *DeletionPolicy) GetFinalBackupMethod() FinalBackupMethod {
+	if d == nil || d.FinalBackup == nil || d.FinalBackup.Method == "" {
+		return FinalBackupMethodBarmanObjectStore
+	}
+	return d.FinalBackup.Method
+}
+
+// GetFinalBackupTimeout gets the time allowed for the final backup
+// to complete, which is never longer than the one of the whole
+// teardown sequence
+func (d *DeletionPolicy) GetFinalBackupTimeout() time.Duration {
+	timeout := d.GetTimeout()
+	if d == nil || d.FinalBackup == nil || d.FinalBackup.Timeout == 0 {
+		return timeout
+	}
+
+	if finalBackupTimeout := time.Duration(d.FinalBackup.Timeout) * time.Second; finalBackupTimeout < timeout {
+		return finalBackupTimeout
+	}
+	return timeout
+}
+
+// GetTimeout gets the time allowed to the archive hook to complete
+func (hook *WalArchiveHook) GetTimeout() time.Duration {
+	if hook.Timeout <= 0 {
+		return DefaultWalArchiveHookTimeout * time.Second
+	}
+	return time.Duration(hook.Timeout) * time.Second
+}
+
+// IsBestEffort checks if the failures of the archive hook
+// must not prevent the WAL files from being archived
+func (hook *WalArchiveHook) IsBestEffort() bool {
+	return hook.FailurePolicy == WalArchiveHookFailurePolicyBestEffort
+}
+
+// GetOwner gets the name of the role owning the database
+func (d ManagedDatabase) GetOwner() string {
+	if d.Owner != "" {
+		return d.Owner
+	}
+	return d.Name
+}
+
+// GetManagedDatabases gets the databases managed by the instance manager
+func (cluster *Cluster) GetManagedDatabases() []ManagedDatabase {
+	if cluster.Spec.Managed == nil {
+		return nil
+	}
+	return cluster.Spec.Managed.Databases
+}
+
+// IsPresent checks if the extension must be installed
+func (e ManagedDatabaseExtension) IsPresent() bool {
+	return e.Ensure != EnsureAbsent
+}
+
+// IsPresent checks if the foreign data wrapper must exist
+func (w ManagedForeignDataWrapper) IsPresent() bool {
+	return w.Ensure != EnsureAbsent
+}
+
+// IsPresent checks if the foreign server must exist
+func (s ManagedForeignServer) IsPresent() bool {
+	return s.Ensure != EnsureAbsent
+}
+
+// IsPresent checks if the user mapping must exist
+func (m ManagedUserMapping) IsPresent() bool {
+	return m.Ensure != EnsureAbsent
+}
+
+// IsPublic checks if the user mapping applies to the roles without
+// a specific one
+func (m ManagedUserMapping) IsPublic() bool {
+	return strings.EqualFold(m.User, "public")
+}
+
+// GetUserMappingSecretNames gets the names of the Secrets containing the
+// credentials of the user mappings of the managed databases
+func (cluster *Cluster) GetUserMappingSecretNames() []string {
+	var result []string
+	for _, database := range cluster.GetManagedDatabases() {
+		for _, server := range database.ForeignServers {
+			for _, mapping := range server.UserMappings {
+				if mapping.CredentialsSecret != nil && !slices.Contains(result, mapping.CredentialsSecret.Name) {
+					result = append(result, mapping.CredentialsSecret.Name)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// GetSharedPreloadLibraries gets the libraries to be added to
+// shared_preload_libraries, the ones explicitly requested followed
+// by the ones required by the extensions of the managed databases
+func (cluster *Cluster) GetSharedPreloadLibraries() []string {
+	libraries := make([]string, 0, len(cluster.Spec.PostgresConfiguration.AdditionalLibraries))
+	libraries = append(libraries, cluster.Spec.PostgresConfiguration.AdditionalLibraries...)
+	for _, database := range cluster.GetManagedDatabases() {
+		for _, extension := range database.Extensions {
+			if !extension.IsPresent() {
+				continue
+			}
+			for _, library := range extension.SharedPreloadLibraries {
+				if !slices.Contains(libraries, library) {
+					libraries = append(libraries, library)
+				}
+			}
+		}
+	}
+
+	return libraries
+}
+
+// GetExtensionControlPath gets the directories of the extension image
+// containing the control files
+func (e ExtensionConfiguration) GetExtensionControlPath() []string {
+	if len(e.ExtensionControlPath) == 0 {
+		return []string{"share"}
+	}
+	return e.ExtensionControlPath
+}
+
+// GetDynamicLibraryPath gets the directories of the extension image
+// containing the libraries
+func (e ExtensionConfiguration) GetDynamicLibraryPath() []string {
+	if len(e.DynamicLibraryPath) == 0 {
+		return []string{"lib"}
+	}
+	return e.DynamicLibraryPath
+}
+
+// GetExtensionsPaths gets the absolute paths, inside the instance Pods, of
+// the directories of the extension images containing the control files and
+// the libraries, in the order the extensions are declared
+func (cluster *Cluster) GetExtensionsPaths() (extensionControlPath []string, dynamicLibraryPath []string) {
+	for _, extension := range cluster.Spec.PostgresConfiguration.Extensions {
+		baseDirectory := path.Join(postgres.ExtensionsBaseDirectory, extension.Name)
+		for _, directory := range extension.GetExtensionControlPath() {
+			extensionControlPath = append(extensionControlPath, path.Join(baseDirectory, directory))
+		}
+		for _, directory := range extension.GetDynamicLibraryPath() {
+			dynamicLibraryPath = append(dynamicLibraryPath, path.Join(baseDirectory, directory))
+		}
+	}
+
+	return extensionControlPath, dynamicLibraryPath
+}
+
+// GetAdminSessionPolicy gets the policy applied to the privileged
+// sessions, or nil when no limit has been set
+func (cluster *Cluster) GetAdminSessionPolicy() *AdminSessionPolicy {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.AdminSessionPolicy == nil {
+		return nil
+	}
+
+	policy := cluster.Spec.Managed.AdminSessionPolicy
+	if policy.IdleTimeout <= 0 && policy.MaxDuration <= 0 {
+		return nil
+	}
+	return policy
+}
+
+// GetIdleTimeout gets the time after which an idle privileged
+// session is terminated, zero meaning never
+func (policy *AdminSessionPolicy) GetIdleTimeout() time.Duration {
+	if policy.IdleTimeout <= 0 {
+		return 0
+	}
+	return time.Duration(policy.IdleTimeout) * time.Second
+}
+
+// GetMaxDuration gets the time after which a privileged session
+// is terminated, zero meaning never
+func (policy *AdminSessionPolicy) GetMaxDuration() time.Duration {
+	if policy.MaxDuration <= 0 {
+		return 0
+	}
+	return time.Duration(policy.MaxDuration) * time.Second
+}
+
+// GetServiceTemplate gets the customization of the service generated
+// by the operator for the passed selector type, if any
+func (cluster *Cluster) GetServiceTemplate(selectorType ServiceSelectorType) *ServiceTemplate {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
+	}
+
+	for idx := range cluster.Spec.Managed.Services.Templates {
+		template := &cluster.Spec.Managed.Services.Templates[idx]
+		if template.SelectorType == selectorType {
+			return template
+		}
+	}
+	return nil
+}
+
+// GetAdditionalServices gets the additional services to be created
+func (cluster *Cluster) GetAdditionalServices() []AdditionalService {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
+	}
+	return cluster.Spec.Managed.Services.Additional
+}
+
+// GetPerInstanceServiceTemplate gets the template of the services
+// dedicated to each instance, nil meaning they are not created
+func (cluster *Cluster) GetPerInstanceServiceTemplate() *ServiceTemplateSpec {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
+	}
+	return cluster.Spec.Managed.Services.PerInstance
+}
+
+// GetExposedDatabases gets the managed databases having a dedicated
+// Service and Secret
+func (cluster *Cluster) GetExposedDatabases() []ManagedDatabase {
+	var result []ManagedDatabase
+	for _, database := range cluster.GetManagedDatabases() {
+		if database.Expose {
+			result = append(result, database)
+		}
+	}
+	return result
+}
+
+// HasPasswordRotation checks if the password of the owner of any exposed
+// managed database is periodically rotated
+func (cluster *Cluster) HasPasswordRotation() bool {
+	for _, database := range cluster.GetExposedDatabases() {
+		if database.PasswordRotation != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDatabaseResourcesName gets the name of the Service and of the Secret
+// dedicated to an exposed managed database
+func (cluster *Cluster) GetDatabaseResourcesName(databaseName string) string {
+	return fmt.Sprintf("%v%v%v", cluster.Name, DatabaseResourcesInfix, databaseName)
+}
+
+// GetShmLimit gets the size limit of the shared memory volume, if any
+func (e *EphemeralVolumesSizeLimitConfiguration) GetShmLimit() *resource.Quantity {
+	if e == nil {
+		return nil
+	}
+	return e.Shm
+}
+
+// GetTemporaryDataLimit gets the size limit of the temporary data volume, if any
+func (e *EphemeralVolumesSizeLimitConfiguration) GetTemporaryDataLimit() *resource.Quantity {
+	if e == nil {
+		return nil
+	}
+	return e.TemporaryData
+}
+
+// GetFields gets the static fields to be added to the log records
+func (l *LoggingConfiguration) GetFields() map[string]string {
+	if l == nil {
+		return nil
+	}
+	return l.Fields
+}
+
+// GetLoggerLevels gets the log level of the loggers overriding the default one
+func (l *LoggingConfiguration) GetLoggerLevels() map[string]string {
+	if l == nil {
+		return nil
+	}
+	return l.Loggers
+}
+
 // ExternalCluster represents the connection parameters to an
 // external cluster which is used in the other sections of the configuration
 type ExternalCluster struct {
@@ -1444,12 +3852,18 @@ func (in ExternalCluster) GetServerName() string {
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +kubebuilder:subresource:status
-// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances
+// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances,selectorpath=.status.selector
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Instances",type="integer",JSONPath=".status.instances",description="Number of instances"
 // +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyInstances",description="Number of ready instances"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="Cluster current status"
 // +kubebuilder:printcolumn:name="Primary",type="string",JSONPath=".status.currentPrimary",description="Primary pod"
+// +kubebuilder:printcolumn:name="Timeline",type="integer",JSONPath=".status.timelineID",description="Timeline of the primary"
+// +kubebuilder:printcolumn:name="Ready/Desired",type="string",JSONPath=".status.readyInstancesSummary",description="Ready instances over the requested ones",priority=1
+// +kubebuilder:printcolumn:name="Target Primary",type="string",JSONPath=".status.targetPrimary",description="Target primary pod",priority=1
+// +kubebuilder:printcolumn:name="LSN",type="string",JSONPath=".status.currentPrimaryLSN",description="WAL position of the primary",priority=1
+// +kubebuilder:printcolumn:name="Last Failover",type="date",JSONPath=".status.lastFailoverTimestamp",description="When the last failover has been started",priority=1
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.phaseReason",description="Reason for the current status",priority=1
 
 // Cluster is the Schema for the PostgreSQL API
 type Cluster struct {
@@ -1507,6 +3921,10 @@ type SecretsResourceVersion struct {
 	// A map with the versions of all the secrets used to pass metrics.
 	// Map keys are the secret names, map values are the versions
 	Metrics map[string]string `json:"metrics,omitempty"`
+
+	// A map with the versions of the secrets of the exposed managed
+	// databases. Map keys are the secret names, map values are the versions
+	Databases map[string]string `json:"databases,omitempty"`
 }
 
 // ConfigMapResourceVersion is the resource versions of the secrets
@@ -1517,9 +3935,78 @@ type ConfigMapResourceVersion struct {
 	Metrics map[string]string `json:"metrics,omitempty"`
 }
 
+// GetJobResources gets the resources requirements of the Jobs
+// creating the instances, defaulting to the ones of the Pods
+func (cluster *Cluster) GetJobResources() corev1.ResourceRequirements {
+	if cluster.Spec.JobResources != nil {
+		return *cluster.Spec.JobResources
+	}
+
+	return cluster.Spec.Resources
+}
+
+// getHugePagesResourceNames gets the sorted names of the huge pages
+// resources, like "hugepages-2Mi", found in the passed requirements
+func getHugePagesResourceNames(resources corev1.ResourceRequirements) []corev1.ResourceName {
+	names := make(map[corev1.ResourceName]bool)
+	for _, list := range []corev1.ResourceList{resources.Limits, resources.Requests} {
+		for name, quantity := range list {
+			if strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) && !quantity.IsZero() {
+				names[name] = true
+			}
+		}
+	}
+
+	result := make([]corev1.ResourceName, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// GetHugePagesResourceName gets the name of the huge pages resource
+// requested by the instances, or an empty string if the instances
+// are not using huge pages
+func (cluster *Cluster) GetHugePagesResourceName() corev1.ResourceName {
+	names := getHugePagesResourceNames(cluster.Spec.Resources)
+	if len(names) == 0 {
+		return ""
+	}
+
+	return names[0]
+}
+
+// GetHugePageSize gets the size in bytes of the huge pages used by the
+// instances, or zero if the instances are not using huge pages
+func (cluster *Cluster) GetHugePageSize() int64 {
+	name := cluster.GetHugePagesResourceName()
+	if name == "" {
+		return 0
+	}
+
+	size, err := resource.ParseQuantity(strings.TrimPrefix(string(name), corev1.ResourceHugePagesPrefix))
+	if err != nil {
+		return 0
+	}
+
+	return size.Value()
+}
+
 // GetImageName get the name of the image that should be used
-// to create the pods
+// to create the pods. When image verification is enabled, this is the
+// last image resolved and verified by the operator
 func (cluster *Cluster) GetImageName() string {
+	if cluster.IsImageDigestPinned() && cluster.Status.ImageDigest != nil {
+		return cluster.Status.ImageDigest.GetPinnedImageName()
+	}
+
+	return cluster.GetRequestedImageName()
+}
+
+// GetRequestedImageName get the name of the image requested for the
+// cluster, before any digest resolution
+func (cluster *Cluster) GetRequestedImageName() string {
 	if len(cluster.Spec.ImageName) > 0 {
 		return cluster.Spec.ImageName
 	}
@@ -1527,6 +4014,19 @@ func (cluster *Cluster) GetImageName() string {
 	return configuration.Current.PostgresImageName
 }
 
+// IsImageDigestPinned checks whether the operator needs to resolve the
+// image to its digest before using it
+func (cluster *Cluster) IsImageDigestPinned() bool {
+	verification := cluster.Spec.ImageVerification
+	return verification != nil && (verification.PinDigest || len(verification.PublicKeys) > 0)
+}
+
+// IsImageSignatureVerificationEnabled checks whether the operator needs to verify
+// the signature of the image before using it
+func (cluster *Cluster) IsImageSignatureVerificationEnabled() bool {
+	return cluster.Spec.ImageVerification != nil && len(cluster.Spec.ImageVerification.PublicKeys) > 0
+}
+
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
 // image name.
 // Example:
@@ -1540,6 +4040,52 @@ func (cluster *Cluster) GetPostgresqlVersion() (int, error) {
 	return postgres.GetPostgresVersionFromTag(tag)
 }
 
+// IsMinorVersionPinned checks whether the cluster is pinned to an
+// exact PostgreSQL minor version
+func (cluster *Cluster) IsMinorVersionPinned() bool {
+	return cluster.Spec.MinorVersionPinning != nil
+}
+
+// GetEnabledManagedExtensions gets the names of the managed extensions that
+// the cluster requires even if their configuration parameters are not set.
+// This happens when the user explicitly adds the libraries of the extension
+// to shared_preload_libraries, i.e. to use pgaudit with its default settings,
+// and only for the extensions supporting it: the other ones, like
+// pg_stat_statements, could have been created by the user in some databases
+func (cluster *Cluster) GetEnabledManagedExtensions() []string {
+	var extensions []string
+	if cluster.Spec.Monitoring.IsPgStatStatementsEnabled() {
+		extensions = append(extensions, PgStatStatementsExtensionName)
+	}
+
+	for _, extension := range postgres.ManagedExtensions {
+		if slices.Contains(extensions, extension.Name) || !extension.EnabledBySharedPreloadLibraries {
+			continue
+		}
+
+		isPreloaded := true
+		for _, library := range extension.SharedPreloadLibraries {
+			if !slices.Contains(cluster.Spec.PostgresConfiguration.AdditionalLibraries, library) {
+				isPreloaded = false
+				break
+			}
+		}
+		if isPreloaded {
+			extensions = append(extensions, extension.Name)
+		}
+	}
+
+	return extensions
+}
+
+// IsManagedExtensionUsed checks whether a managed extension is needed by
+// this cluster, either because its configuration parameters are set or
+// because it has been explicitly enabled
+func (cluster *Cluster) IsManagedExtensionUsed(extension postgres.ManagedExtension) bool {
+	return extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters) ||
+		slices.Contains(cluster.GetEnabledManagedExtensions(), extension.Name)
+}
+
 // GetImagePullSecret get the name of the pull secret to use
 // to download the PostgreSQL image
 func (cluster *Cluster) GetImagePullSecret() string {
@@ -1556,6 +4102,42 @@ func (cluster *Cluster) GetSuperuserSecretName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, SuperUserSecretSuffix)
 }
 
+// GetSuperuserPasswordFile gets the path of the file containing the
+// password of the superuser, if an external secret store is used
+func (cluster *Cluster) GetSuperuserPasswordFile() string {
+	if cluster.Spec.CredentialsFiles == nil {
+		return ""
+	}
+	return cluster.Spec.CredentialsFiles.SuperuserPasswordFile
+}
+
+// GetApplicationPasswordFile gets the path of the file containing the
+// password of the application user, if an external secret store is used
+func (cluster *Cluster) GetApplicationPasswordFile() string {
+	if cluster.Spec.CredentialsFiles == nil {
+		return ""
+	}
+	return cluster.Spec.CredentialsFiles.ApplicationPasswordFile
+}
+
+// UsesCredentialsFiles checks if any password is provided
+// by an external secret store through a file
+func (cluster *Cluster) UsesCredentialsFiles() bool {
+	return cluster.GetSuperuserPasswordFile() != "" || cluster.GetApplicationPasswordFile() != ""
+}
+
+// UsesSuperuserSecret checks if the password of the superuser
+// is stored in a Kubernetes secret
+func (cluster *Cluster) UsesSuperuserSecret() bool {
+	return cluster.GetEnableSuperuserAccess() && cluster.GetSuperuserPasswordFile() == ""
+}
+
+// UsesApplicationSecret checks if the password of the application
+// user is stored in a Kubernetes secret
+func (cluster *Cluster) UsesApplicationSecret() bool {
+	return cluster.ShouldCreateApplicationDatabase() && cluster.GetApplicationPasswordFile() == ""
+}
+
 // GetEnableLDAPAuth return true if bind or bind+search method are
 // configured in the cluster configuration
 func (cluster *Cluster) GetEnableLDAPAuth() bool {
@@ -1577,6 +4159,24 @@ func (cluster *Cluster) GetLDAPSecretName() string {
 	return ""
 }
 
+// GetLDAPCACertificate gets the secret containing the CA certificate
+// of the LDAP server, if specified
+func (cluster *Cluster) GetLDAPCACertificate() *SecretKeySelector {
+	if cluster.Spec.PostgresConfiguration.LDAP != nil {
+		return cluster.Spec.PostgresConfiguration.LDAP.CACertificate
+	}
+	return nil
+}
+
+// IsAlterSystemEnabled returns if ALTER SYSTEM is allowed on the instances or not
+func (cluster *Cluster) IsAlterSystemEnabled() bool {
+	if cluster.Spec.PostgresConfiguration.EnableAlterSystem != nil {
+		return *cluster.Spec.PostgresConfiguration.EnableAlterSystem
+	}
+
+	return true
+}
+
 // GetEnableSuperuserAccess returns if the superuser access is enabled or not
 func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	if cluster.Spec.EnableSuperuserAccess != nil {
@@ -1602,6 +4202,11 @@ func (cluster *Cluster) GetApplicationSecretName() string {
 		return pgBaseBackup.Secret.Name
 	}
 
+	existingVolume := bootstrap.ExistingVolume
+	if existingVolume != nil && existingVolume.Secret != nil && existingVolume.Secret.Name != "" {
+		return existingVolume.Secret.Name
+	}
+
 	initDB := bootstrap.InitDB
 	if initDB != nil && initDB.Secret != nil && initDB.Secret.Name != "" {
 		return initDB.Secret.Name
@@ -1625,6 +4230,10 @@ func (cluster *Cluster) GetApplicationDatabaseName() string {
 		return bootstrap.PgBaseBackup.Database
 	}
 
+	if bootstrap.ExistingVolume != nil && bootstrap.ExistingVolume.Database != "" {
+		return bootstrap.ExistingVolume.Database
+	}
+
 	if bootstrap.InitDB != nil && bootstrap.InitDB.Database != "" {
 		return bootstrap.InitDB.Database
 	}
@@ -1647,6 +4256,10 @@ func (cluster *Cluster) GetApplicationDatabaseOwner() string {
 		return bootstrap.PgBaseBackup.Owner
 	}
 
+	if bootstrap.ExistingVolume != nil && bootstrap.ExistingVolume.Owner != "" {
+		return bootstrap.ExistingVolume.Owner
+	}
+
 	if bootstrap.InitDB != nil && bootstrap.InitDB.Owner != "" {
 		return bootstrap.InitDB.Owner
 	}
@@ -1654,6 +4267,36 @@ func (cluster *Cluster) GetApplicationDatabaseOwner() string {
 	return ""
 }
 
+// GetPasswordEncryption gets the password hashing method enforced by the
+// operator, if any
+func (cluster *Cluster) GetPasswordEncryption() string {
+	if cluster.Spec.PostgresConfiguration.SCRAMMigration != nil {
+		return postgres.PasswordEncryptionSCRAM
+	}
+	return ""
+}
+
+// GetCertificatesValidity gets the lifetime of the certificates generated by
+// the operator for the cluster, and when they should be renewed
+func (cluster *Cluster) GetCertificatesValidity() certs.Validity {
+	duration := configuration.Current.CertificateDuration
+	threshold := configuration.Current.ExpiringCheckThreshold
+	if certificates := cluster.Spec.Certificates; certificates != nil {
+		if certificates.CertificateDuration != nil {
+			duration = *certificates.CertificateDuration
+		}
+		if certificates.ExpiringCheckThreshold != nil {
+			threshold = *certificates.ExpiringCheckThreshold
+		}
+	}
+
+	const day = 24 * time.Hour
+	return certs.Validity{
+		Duration:               time.Duration(duration) * day,
+		ExpiringCheckThreshold: time.Duration(threshold) * day,
+	}
+}
+
 // GetServerCASecretName get the name of the secret containing the CA
 // of the cluster
 func (cluster *Cluster) GetServerCASecretName() string {
@@ -1699,6 +4342,40 @@ func (cluster *Cluster) GetFixedInheritedLabels() map[string]string {
 	return cluster.Spec.InheritedMetadata.Labels
 }
 
+// GetFixedInheritedPodAnnotations gets the annotations that should be
+// inherited by the instance Pods, including the ones in the Pod template
+func (cluster *Cluster) GetFixedInheritedPodAnnotations() map[string]string {
+	if cluster.Spec.PodTemplate == nil || len(cluster.Spec.PodTemplate.Metadata.Annotations) == 0 {
+		return cluster.GetFixedInheritedAnnotations()
+	}
+
+	result := make(map[string]string)
+	for key, value := range cluster.GetFixedInheritedAnnotations() {
+		result[key] = value
+	}
+	for key, value := range cluster.Spec.PodTemplate.Metadata.Annotations {
+		result[key] = value
+	}
+	return result
+}
+
+// GetFixedInheritedPodLabels gets the labels that should be
+// inherited by the instance Pods, including the ones in the Pod template
+func (cluster *Cluster) GetFixedInheritedPodLabels() map[string]string {
+	if cluster.Spec.PodTemplate == nil || len(cluster.Spec.PodTemplate.Metadata.Labels) == 0 {
+		return cluster.GetFixedInheritedLabels()
+	}
+
+	result := make(map[string]string)
+	for key, value := range cluster.GetFixedInheritedLabels() {
+		result[key] = value
+	}
+	for key, value := range cluster.Spec.PodTemplate.Metadata.Labels {
+		result[key] = value
+	}
+	return result
+}
+
 // GetReplicationSecretName get the name of the secret for the replication user
 func (cluster *Cluster) GetReplicationSecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ReplicationTLSSecret != "" {
@@ -1747,6 +4424,15 @@ func (cluster *Cluster) GetMaxStopDelay() int32 {
 	return 30
 }
 
+// GetSmartShutdownTimeout gets the amount of time reserved for the smart
+// shutdown of PostgreSQL, before escalating to a fast shutdown
+func (cluster *Cluster) GetSmartShutdownTimeout() int32 {
+	if cluster.Spec.SmartShutdownTimeout > 0 && cluster.Spec.SmartShutdownTimeout < cluster.GetMaxStopDelay() {
+		return cluster.Spec.SmartShutdownTimeout
+	}
+	return cluster.GetMaxStopDelay() / 2
+}
+
 // GetMaxSwitchoverDelay get the amount of time PostgreSQL has to stop before switchover
 func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	if cluster.Spec.MaxSwitchoverDelay > 0 {
@@ -1777,6 +4463,187 @@ func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
 	return strategy
 }
 
+// GetScaleDownPVCReclaimPolicy gets the policy applied to the PVCs
+// of the instances removed while scaling down, defaulting to `delete`
+func (cluster *Cluster) GetScaleDownPVCReclaimPolicy() PVCReclaimPolicy {
+	if cluster.Spec.ScaleDownPVCReclaimPolicy == "" {
+		return PVCReclaimPolicyDelete
+	}
+
+	return cluster.Spec.ScaleDownPVCReclaimPolicy
+}
+
+// IsInstanceManagerInplaceUpdateEnabled checks whether the instance manager
+// needs to be upgraded in-place, defaulting to the operator configuration
+func (cluster *Cluster) IsInstanceManagerInplaceUpdateEnabled() bool {
+	switch cluster.Spec.InstanceManagerUpdateMethod {
+	case InstanceManagerUpdateMethodInPlace:
+		return true
+	case InstanceManagerUpdateMethodRollout:
+		return false
+	default:
+		return configuration.Current.EnableInstanceManagerInplaceUpdates
+	}
+}
+
+// GetInstanceGroup gets the group the passed instance has been assigned
+// to, or nil when it belongs to the default group. Instances assigned to
+// a group which has been removed from the specification are considered
+// part of the default group
+func (cluster *Cluster) GetInstanceGroup(instanceName string) *InstanceGroup {
+	groupName, ok := cluster.Status.InstanceGroups[instanceName]
+	if !ok {
+		return nil
+	}
+
+	for idx := range cluster.Spec.InstanceGroups {
+		if cluster.Spec.InstanceGroups[idx].Name == groupName {
+			return &cluster.Spec.InstanceGroups[idx]
+		}
+	}
+
+	return nil
+}
+
+// GetInstanceOverrides parses the settings overridden for single instances
+// via the `cnpg.io/instanceOverrides` annotation, keyed by serial
+func (cluster *Cluster) GetInstanceOverrides() (map[int]InstanceOverride, error) {
+	content, ok := cluster.Annotations[utils.InstanceOverridesAnnotationName]
+	if !ok {
+		return nil, nil
+	}
+
+	var overrides map[int]InstanceOverride
+	if err := json.Unmarshal([]byte(content), &overrides); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+// GetInstanceOverride gets the settings overridden for the passed instance,
+// or nil if there are none. An invalid annotation, which is refused by the
+// webhook, is ignored
+func (cluster *Cluster) GetInstanceOverride(instanceName string) *InstanceOverride {
+	overrides, err := cluster.GetInstanceOverrides()
+	if err != nil {
+		return nil
+	}
+
+	for serial, override := range overrides {
+		if fmt.Sprintf("%s-%d", cluster.Name, serial) == instanceName {
+			return &override
+		}
+	}
+
+	return nil
+}
+
+// GetInstanceResources gets the resources requirements of the passed
+// instance, taking into account its overrides and the group it belongs to
+func (cluster *Cluster) GetInstanceResources(instanceName string) corev1.ResourceRequirements {
+	if override := cluster.GetInstanceOverride(instanceName); override != nil && override.Resources != nil {
+		return *override.Resources
+	}
+
+	if group := cluster.GetInstanceGroup(instanceName); group != nil && group.Resources != nil {
+		return *group.Resources
+	}
+
+	return cluster.Spec.Resources
+}
+
+// GetInstanceNodeSelector gets the node selector of the passed instance,
+// merging the one of the cluster with the ones of its group and of its
+// overrides
+func (cluster *Cluster) GetInstanceNodeSelector(instanceName string) map[string]string {
+	var selectors []map[string]string
+	if group := cluster.GetInstanceGroup(instanceName); group != nil && len(group.NodeSelector) > 0 {
+		selectors = append(selectors, group.NodeSelector)
+	}
+	if override := cluster.GetInstanceOverride(instanceName); override != nil && len(override.NodeSelector) > 0 {
+		selectors = append(selectors, override.NodeSelector)
+	}
+	if len(selectors) == 0 {
+		return cluster.Spec.Affinity.NodeSelector
+	}
+
+	nodeSelector := make(map[string]string, len(cluster.Spec.Affinity.NodeSelector))
+	for _, selector := range append([]map[string]string{cluster.Spec.Affinity.NodeSelector}, selectors...) {
+		for key, value := range selector {
+			nodeSelector[key] = value
+		}
+	}
+
+	return nodeSelector
+}
+
+// IsInstanceExcludedFromFailover checks whether the passed instance
+// belongs to a group whose instances can't be promoted
+func (cluster *Cluster) IsInstanceExcludedFromFailover(instanceName string) bool {
+	group := cluster.GetInstanceGroup(instanceName)
+	return group != nil && group.ExcludedFromFailover
+}
+
+// GetInstanceGroupForNewInstance gets the name of the group a new instance
+// should be assigned to, which is the first one having less instances than
+// requested. The empty string is returned for the default group.
+// The primary instance is never assigned to a group excluded from failover
+func (cluster *Cluster) GetInstanceGroupForNewInstance(primary bool) string {
+	members := make(map[string]int, len(cluster.Spec.InstanceGroups))
+	for _, groupName := range cluster.Status.InstanceGroups {
+		members[groupName]++
+	}
+
+	for _, group := range cluster.Spec.InstanceGroups {
+		if primary && group.ExcludedFromFailover {
+			continue
+		}
+		if members[group.Name] < group.Instances {
+			return group.Name
+		}
+	}
+
+	return ""
+}
+
+// GetInstancesOverGroupQuota gets the instances, among the passed ones,
+// belonging to a group having more instances than requested
+func (cluster *Cluster) GetInstancesOverGroupQuota(instanceNames []string) []string {
+	members := make(map[string][]string)
+	for _, instanceName := range instanceNames {
+		groupName := ""
+		if group := cluster.GetInstanceGroup(instanceName); group != nil {
+			groupName = group.Name
+		}
+		members[groupName] = append(members[groupName], instanceName)
+	}
+
+	var result []string
+	for groupName, groupMembers := range members {
+		if len(groupMembers) > cluster.getInstanceGroupQuota(groupName) {
+			result = append(result, groupMembers...)
+		}
+	}
+
+	return result
+}
+
+// getInstanceGroupQuota gets the number of instances requested for the
+// passed group. The default group, identified by the empty string, gets
+// the instances not requested by any other group
+func (cluster *Cluster) getInstanceGroupQuota(groupName string) int {
+	quota := cluster.Spec.Instances
+	for _, group := range cluster.Spec.InstanceGroups {
+		if group.Name == groupName {
+			return group.Instances
+		}
+		quota -= group.Instances
+	}
+
+	return quota
+}
+
 // IsNodeMaintenanceWindowInProgress check if the upgrade mode is active or not
 func (cluster *Cluster) IsNodeMaintenanceWindowInProgress() bool {
 	return cluster.Spec.NodeMaintenanceWindow != nil && cluster.Spec.NodeMaintenanceWindow.InProgress
@@ -1829,7 +4696,8 @@ func (cluster *Cluster) ShouldResizeInUseVolumes() bool {
 func (cluster *Cluster) ShouldCreateApplicationSecret() bool {
 	return cluster.ShouldInitDBCreateApplicationSecret() ||
 		cluster.ShouldPgBaseBackupCreateApplicationSecret() ||
-		cluster.ShouldRecoveryCreateApplicationSecret()
+		cluster.ShouldRecoveryCreateApplicationSecret() ||
+		cluster.ShouldExistingVolumeCreateApplicationSecret()
 }
 
 // ShouldInitDBCreateApplicationSecret returns true if for this cluster,
@@ -1856,32 +4724,64 @@ func (cluster *Cluster) ShouldRecoveryCreateApplicationSecret() bool {
 			cluster.Spec.Bootstrap.Recovery.Secret.Name == "")
 }
 
+// ShouldExistingVolumeCreateApplicationSecret returns true if for this cluster,
+// during the bootstrap phase using an existing volume, we need to create an application secret
+func (cluster *Cluster) ShouldExistingVolumeCreateApplicationSecret() bool {
+	return cluster.ShouldExistingVolumeCreateApplicationDatabase() &&
+		(cluster.Spec.Bootstrap.ExistingVolume.Secret == nil ||
+			cluster.Spec.Bootstrap.ExistingVolume.Secret.Name == "")
+}
+
 // ShouldCreateApplicationDatabase returns true if for this cluster,
 // during the bootstrap phase, we need to create an application database
 func (cluster *Cluster) ShouldCreateApplicationDatabase() bool {
 	return cluster.ShouldInitDBCreateApplicationDatabase() ||
 		cluster.ShouldRecoveryCreateApplicationDatabase() ||
-		cluster.ShouldPgBaseBackupCreateApplicationDatabase()
+		cluster.ShouldPgBaseBackupCreateApplicationDatabase() ||
+		cluster.ShouldExistingVolumeCreateApplicationDatabase()
+}
+
+// ShouldInitDBRunPostInitSQLRefs returns true if for this cluster,
+// during the bootstrap phase using initDB, we need to run post init
+// SQL files from provided references.
+func (cluster *Cluster) ShouldInitDBRunPostInitSQLRefs() bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
+		return false
+	}
+
+	return cluster.Spec.Bootstrap.InitDB.PostInitSQLRefs.HasReferences()
+}
+
+// ShouldInitDBRunPostInitTemplateSQLRefs returns true if for this cluster,
+// during the bootstrap phase using initDB, we need to run post init
+// template SQL files from provided references.
+func (cluster *Cluster) ShouldInitDBRunPostInitTemplateSQLRefs() bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
+		return false
+	}
+
+	return cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQLRefs.HasReferences()
 }
 
 // ShouldInitDBRunPostInitApplicationSQLRefs returns true if for this cluster,
 // during the bootstrap phase using initDB, we need to run post application
 // SQL files from provided references.
 func (cluster *Cluster) ShouldInitDBRunPostInitApplicationSQLRefs() bool {
-	if cluster.Spec.Bootstrap == nil {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
 		return false
 	}
 
-	if cluster.Spec.Bootstrap.InitDB == nil {
-		return false
-	}
+	return cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.HasReferences()
+}
 
-	if cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs == nil {
+// HasReferences checks if there is at least a Secret or a ConfigMap
+// containing SQL files
+func (refs *PostInitApplicationSQLRefs) HasReferences() bool {
+	if refs == nil {
 		return false
 	}
 
-	return (len(cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.ConfigMapRefs) != 0 ||
-		len(cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.SecretRefs) != 0)
+	return len(refs.ConfigMapRefs) != 0 || len(refs.SecretRefs) != 0
 }
 
 // ShouldInitDBCreateApplicationDatabase returns true if the application database needs to be created during initdb
@@ -1938,6 +4838,26 @@ func (cluster *Cluster) ShouldRecoveryCreateApplicationDatabase() bool {
 	return recoveryParameters.Owner != "" && recoveryParameters.Database != ""
 }
 
+// ShouldExistingVolumeCreateApplicationDatabase returns true if the application database needs to be
+// created during the job adopting an existing volume
+func (cluster *Cluster) ShouldExistingVolumeCreateApplicationDatabase() bool {
+	// we skip creating the application database if cluster is a replica
+	if cluster.IsReplica() {
+		return false
+	}
+
+	if cluster.Spec.Bootstrap == nil {
+		return false
+	}
+
+	if cluster.Spec.Bootstrap.ExistingVolume == nil {
+		return false
+	}
+
+	existingVolumeParameters := cluster.Spec.Bootstrap.ExistingVolume
+	return existingVolumeParameters.Owner != "" && existingVolumeParameters.Database != ""
+}
+
 // ShouldCreateWalArchiveVolume returns whether we should create the wal archive volume
 func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
@@ -1948,6 +4868,58 @@ func (cluster *Cluster) GetWalArchiveVolumeSuffix() string {
 	return "-wal"
 }
 
+// ShouldTakeFinalBackup checks whether a backup should be taken before
+// the cluster is deleted. The designated primary of a replica cluster
+// is never backed up, as the backups belong to the source cluster, while
+// development clusters are only backed up when explicitly requested
+func (cluster *Cluster) ShouldTakeFinalBackup() bool {
+	if cluster.IsDevelopmentProfile() && !cluster.Spec.DeletionPolicy.IsFinalBackupRequested() {
+		return false
+	}
+
+	return cluster.Spec.Backup.IsBarmanBackupConfigured() &&
+		!cluster.IsReplica() &&
+		cluster.Spec.DeletionPolicy.IsFinalBackupEnabled()
+}
+
+// ShouldArchiveWALOnDeletion checks whether the WAL files not yet
+// archived should be sent to the object store before the cluster is deleted.
+// For development clusters this only happens when explicitly requested
+func (cluster *Cluster) ShouldArchiveWALOnDeletion() bool {
+	if cluster.IsDevelopmentProfile() && !cluster.Spec.DeletionPolicy.IsArchiveWALRequested() {
+		return false
+	}
+
+	return cluster.Spec.Backup.IsBarmanBackupConfigured() &&
+		!cluster.IsReplica() &&
+		cluster.Spec.DeletionPolicy.IsArchiveWALEnabled()
+}
+
+// IsDevelopmentProfile checks whether the cluster uses the development profile
+func (cluster *Cluster) IsDevelopmentProfile() bool {
+	return cluster.Spec.Profile == ClusterProfileDevelopment
+}
+
+// ShouldExportInventoryOnDeletion checks whether the roles and the databases
+// should be exported to a ConfigMap before the cluster is deleted
+func (cluster *Cluster) ShouldExportInventoryOnDeletion() bool {
+	return cluster.Spec.DeletionPolicy.IsExportInventoryEnabled()
+}
+
+// NeedsDeletionPolicyFinalizer checks whether the deletion of the cluster
+// must be held until the deletion policy is applied
+func (cluster *Cluster) NeedsDeletionPolicyFinalizer() bool {
+	return cluster.ShouldTakeFinalBackup() ||
+		cluster.ShouldArchiveWALOnDeletion() ||
+		cluster.ShouldExportInventoryOnDeletion()
+}
+
+// GetInventoryConfigMapName gets the name of the ConfigMap where the
+// roles and the databases are exported when the cluster is deleted
+func (cluster *Cluster) GetInventoryConfigMapName() string {
+	return cluster.Name + InventoryConfigMapSuffix
+}
+
 // GetPostgresUID returns the UID that is being used for the "postgres"
 // user
 func (cluster Cluster) GetPostgresUID() int64 {
@@ -1983,6 +4955,21 @@ func (cluster Cluster) IsReplica() bool {
 	return cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled
 }
 
+// GetReadOnlyServiceExportAPI gets the API used to export the read-only
+// service to the other Kubernetes clusters, or an empty string if the
+// service is not exported
+func (cluster Cluster) GetReadOnlyServiceExportAPI() ServiceExportAPI {
+	if cluster.Spec.ReplicaCluster == nil || cluster.Spec.ReplicaCluster.ExportReadOnlyService == nil {
+		return ""
+	}
+
+	if cluster.Spec.ReplicaCluster.ExportReadOnlyService.API == "" {
+		return ServiceExportAPIMultiCluster
+	}
+
+	return cluster.Spec.ReplicaCluster.ExportReadOnlyService.API
+}
+
 var slotNameNegativeRegex = regexp.MustCompile("[^a-z0-9_]+")
 
 // GetSlotNameFromInstanceName returns the slot name, given the instance name.
@@ -2024,6 +5011,8 @@ func (cluster *Cluster) GetClusterAltDNSNames() []string {
 		fmt.Sprintf("%v.%v.svc", cluster.GetServiceReadOnlyName(), cluster.Namespace),
 	}
 
+	defaultAltDNSNames = append(defaultAltDNSNames, cluster.GetExternalDNSHostnames()...)
+
 	if cluster.Spec.Certificates == nil {
 		return defaultAltDNSNames
 	}
@@ -2031,6 +5020,41 @@ func (cluster *Cluster) GetClusterAltDNSNames() []string {
 	return append(defaultAltDNSNames, cluster.Spec.Certificates.ServerAltDNSNames...)
 }
 
+// GetExternalDNSHostnames gets the host names published by ExternalDNS
+// for the services of the cluster, as requested in their templates
+func (cluster *Cluster) GetExternalDNSHostnames() []string {
+	var result []string
+	for _, selectorType := range []ServiceSelectorType{
+		ServiceSelectorTypeRW, ServiceSelectorTypeRO, ServiceSelectorTypeR,
+	} {
+		if template := cluster.GetServiceTemplate(selectorType); template != nil {
+			result = append(result, template.ServiceTemplateSpec.GetExternalDNSHostnames()...)
+		}
+	}
+	for _, additional := range cluster.GetAdditionalServices() {
+		result = append(result, additional.ServiceTemplateSpec.GetExternalDNSHostnames()...)
+	}
+	return result
+}
+
+// GetExternalDNSHostnames gets the host names that ExternalDNS
+// is requested to publish for the service
+func (spec *ServiceTemplateSpec) GetExternalDNSHostnames() []string {
+	value := spec.Metadata.Annotations[utils.ExternalDNSHostnameAnnotationName]
+	if value == "" {
+		return nil
+	}
+
+	var result []string
+	for _, hostname := range strings.Split(value, ",") {
+		hostname = strings.TrimSuffix(strings.TrimSpace(hostname), ".")
+		if hostname != "" {
+			result = append(result, hostname)
+		}
+	}
+	return result
+}
+
 // UsesSecret checks whether a given secret is used by a Cluster.
 //
 // This function is also used to discover the set of clusters that
@@ -2039,6 +5063,9 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 	if _, ok := cluster.Status.SecretsResourceVersion.Metrics[secret]; ok {
 		return true
 	}
+	if _, ok := cluster.Status.SecretsResourceVersion.Databases[secret]; ok {
+		return true
+	}
 	certificates := cluster.Status.Certificates
 	switch secret {
 	case cluster.GetSuperuserSecretName(),
@@ -2077,6 +5104,15 @@ func (cluster *Cluster) UsesConfigMap(config string) (ok bool) {
 	return false
 }
 
+// IsNetworkPolicyEnabled checks if the NetworkPolicy object needs to be created
+func (cluster *Cluster) IsNetworkPolicyEnabled() bool {
+	if cluster.Spec.NetworkPolicy != nil {
+		return cluster.Spec.NetworkPolicy.Enabled
+	}
+
+	return false
+}
+
 // IsPodMonitorEnabled checks if the PodMonitor object needs to be created
 func (cluster *Cluster) IsPodMonitorEnabled() bool {
 	if cluster.Spec.Monitoring != nil {
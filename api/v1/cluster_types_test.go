@@ -17,8 +17,15 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -241,6 +248,31 @@ var _ = Describe("Bootstrap via initdb", func() {
 		Expect(cluster.ShouldInitDBRunPostInitApplicationSQLRefs()).To(BeFalse())
 	})
 
+	It("will run post init and post init template sql refs if specified", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						PostInitSQLRefs: &PostInitApplicationSQLRefs{
+							SecretRefs: []SecretKeySelector{
+								{
+									Key:                  "secretKey",
+									LocalObjectReference: LocalObjectReference{Name: "secretName"},
+								},
+							},
+						},
+						PostInitTemplateSQLRefs: &PostInitApplicationSQLRefs{},
+					},
+				},
+			},
+		}
+
+		Expect(cluster.ShouldInitDBRunPostInitSQLRefs()).To(BeTrue())
+		Expect(cluster.ShouldInitDBRunPostInitTemplateSQLRefs()).To(BeFalse())
+		Expect(cluster.ShouldInitDBRunPostInitApplicationSQLRefs()).To(BeFalse())
+		Expect((&Cluster{}).ShouldInitDBRunPostInitSQLRefs()).To(BeFalse())
+	})
+
 	It("will not create an application database if not requested", func() {
 		cluster := Cluster{
 			ObjectMeta: v1.ObjectMeta{
@@ -395,6 +427,93 @@ var _ = Describe("look up for secrets", func() {
 	It("retrieves all names needed to build a server CA certificate are 9", func() {
 		Expect(len(cluster.GetClusterAltDNSNames())).To(Equal(9))
 	})
+
+	It("adds the host names published by ExternalDNS to the server certificate", func() {
+		clusterWithDNS := cluster.DeepCopy()
+		clusterWithDNS.Spec.Managed = &ManagedConfiguration{
+			Services: &ManagedServices{
+				Templates: []ServiceTemplate{
+					{
+						SelectorType: ServiceSelectorTypeRW,
+						ServiceTemplateSpec: ServiceTemplateSpec{
+							Metadata: EmbeddedObjectMetadata{
+								Annotations: map[string]string{
+									"external-dns.alpha.kubernetes.io/hostname": "db.example.com, rw.example.com.",
+								},
+							},
+						},
+					},
+				},
+				Additional: []AdditionalService{
+					{
+						Name:         "clustername-reporting",
+						SelectorType: ServiceSelectorTypeRO,
+						ServiceTemplateSpec: ServiceTemplateSpec{
+							Metadata: EmbeddedObjectMetadata{
+								Annotations: map[string]string{
+									"external-dns.alpha.kubernetes.io/hostname": "reporting.example.com",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(clusterWithDNS.GetExternalDNSHostnames()).To(Equal([]string{
+			"db.example.com", "rw.example.com", "reporting.example.com",
+		}))
+		Expect(clusterWithDNS.GetClusterAltDNSNames()).To(HaveLen(12))
+	})
+})
+
+var _ = Describe("credentials files", func() {
+	It("uses the Kubernetes secrets by default", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{InitDB: &BootstrapInitDB{Database: "app", Owner: "app"}},
+			},
+		}
+		Expect(cluster.UsesCredentialsFiles()).To(BeFalse())
+		Expect(cluster.UsesSuperuserSecret()).To(BeTrue())
+		Expect(cluster.UsesApplicationSecret()).To(BeTrue())
+	})
+
+	It("replaces the Kubernetes secrets with the credentials files", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{InitDB: &BootstrapInitDB{Database: "app", Owner: "app"}},
+				CredentialsFiles: &CredentialsFilesConfiguration{
+					SuperuserPasswordFile: "/vault/secrets/postgres",
+				},
+			},
+		}
+		Expect(cluster.UsesCredentialsFiles()).To(BeTrue())
+		Expect(cluster.GetSuperuserPasswordFile()).To(Equal("/vault/secrets/postgres"))
+		Expect(cluster.UsesSuperuserSecret()).To(BeFalse())
+		Expect(cluster.UsesApplicationSecret()).To(BeTrue())
+	})
+})
+
+var _ = Describe("certificates validity", func() {
+	It("uses the operator configuration by default", func() {
+		validity := (&Cluster{}).GetCertificatesValidity()
+		Expect(validity.Duration).To(Equal(90 * 24 * time.Hour))
+		Expect(validity.ExpiringCheckThreshold).To(Equal(7 * 24 * time.Hour))
+	})
+
+	It("can be overridden in the cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					CertificateDuration:    pointer.Int(365),
+					ExpiringCheckThreshold: pointer.Int(30),
+				},
+			},
+		}
+		validity := cluster.GetCertificatesValidity()
+		Expect(validity.Duration).To(Equal(365 * 24 * time.Hour))
+		Expect(validity.ExpiringCheckThreshold).To(Equal(30 * 24 * time.Hour))
+	})
 })
 
 var _ = Describe("A secret resource version", func() {
@@ -441,6 +560,21 @@ var _ = Describe("A secret resource version", func() {
 		Expect(found).To(BeTrue())
 	})
 
+	It("contains the secret of an exposed database", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "clustername",
+			},
+			Status: ClusterStatus{
+				SecretsResourceVersion: SecretsResourceVersion{
+					Databases: map[string]string{"clustername-orders": "test-version"},
+				},
+			},
+		}
+		Expect(cluster.UsesSecret("clustername-orders")).To(BeTrue())
+		Expect(cluster.UsesSecret("clustername-billing")).To(BeFalse())
+	})
+
 	It("contains the superuser secret", func() {
 		cluster := Cluster{
 			ObjectMeta: v1.ObjectMeta{
@@ -688,6 +822,117 @@ var _ = Describe("Default Metrics", func() {
 	})
 })
 
+var _ = Describe("pg_stat_statements collector", func() {
+	It("is disabled when no monitoring is passed", func() {
+		cluster := Cluster{}
+		Expect(cluster.Spec.Monitoring.IsPgStatStatementsEnabled()).To(BeFalse())
+		Expect(cluster.Spec.Monitoring.GetPgStatStatementsTopN()).To(Equal(DefaultPgStatStatementsTopN))
+		Expect(cluster.GetEnabledManagedExtensions()).To(BeEmpty())
+	})
+
+	It("uses the configured number of queries", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					PgStatStatements: &PgStatStatementsConfiguration{TopN: 25},
+				},
+			},
+		}
+		Expect(cluster.Spec.Monitoring.IsPgStatStatementsEnabled()).To(BeFalse())
+		Expect(cluster.Spec.Monitoring.GetPgStatStatementsTopN()).To(Equal(25))
+	})
+
+	It("requires the extension when enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					PgStatStatements: &PgStatStatementsConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.GetEnabledManagedExtensions()).To(ConsistOf(PgStatStatementsExtensionName))
+		for _, extension := range postgres.ManagedExtensions {
+			Expect(cluster.IsManagedExtensionUsed(extension)).
+				To(Equal(extension.Name == PgStatStatementsExtensionName))
+		}
+	})
+})
+
+var _ = Describe("managed extensions", func() {
+	It("are enabled when their libraries are in shared_preload_libraries", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AdditionalLibraries: []string{"pgaudit", "timescaledb"},
+				},
+			},
+		}
+		Expect(cluster.GetEnabledManagedExtensions()).To(ConsistOf("pgaudit"))
+		for _, extension := range postgres.ManagedExtensions {
+			Expect(cluster.IsManagedExtensionUsed(extension)).To(Equal(extension.Name == "pgaudit"))
+		}
+	})
+
+	It("leave alone pg_stat_statements when only its library is in shared_preload_libraries", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AdditionalLibraries: []string{"pg_stat_statements", "auto_explain"},
+				},
+			},
+		}
+		Expect(cluster.GetEnabledManagedExtensions()).To(BeEmpty())
+		for _, extension := range postgres.ManagedExtensions {
+			Expect(cluster.IsManagedExtensionUsed(extension)).To(BeFalse())
+		}
+	})
+
+	It("are reported once if enabled in more than one way", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AdditionalLibraries: []string{"pg_stat_statements"},
+				},
+				Monitoring: &MonitoringConfiguration{
+					PgStatStatements: &PgStatStatementsConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.GetEnabledManagedExtensions()).To(ConsistOf(PgStatStatementsExtensionName))
+	})
+
+	It("are not enabled by their configuration parameters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"pgaudit.log": "all"},
+				},
+			},
+		}
+		Expect(cluster.GetEnabledManagedExtensions()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("wait event sampling collector", func() {
+	It("is disabled when no monitoring is passed", func() {
+		cluster := Cluster{}
+		Expect(cluster.Spec.Monitoring.IsWaitEventSamplingEnabled()).To(BeFalse())
+		Expect(cluster.Spec.Monitoring.GetWaitEventSamplingPeriod()).To(Equal(time.Second))
+	})
+
+	It("uses the configured sampling period", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					WaitEventSampling: &WaitEventSamplingConfiguration{Enabled: true, Period: 250},
+				},
+			},
+		}
+		Expect(cluster.Spec.Monitoring.IsWaitEventSamplingEnabled()).To(BeTrue())
+		Expect(cluster.Spec.Monitoring.GetWaitEventSamplingPeriod()).To(Equal(250 * time.Millisecond))
+	})
+})
+
 var _ = Describe("Barman Endpoint CA for replica cluster", func() {
 	cluster1 := Cluster{}
 	It("is empty if cluster is not replica", func() {
@@ -843,3 +1088,611 @@ var _ = Describe("Replication slots names for instances", func() {
 			"_232_test_cluster_example_1"))
 	})
 })
+
+var _ = Describe("deletion policy", func() {
+	backupConfiguration := &BackupConfiguration{
+		BarmanObjectStore: &BarmanObjectStoreConfiguration{
+			BarmanCredentials: BarmanCredentials{
+				AWS: &S3Credentials{InheritFromIAMRole: true},
+			},
+		},
+	}
+
+	It("doesn't hold the deletion of clusters without backups by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.ShouldTakeFinalBackup()).To(BeFalse())
+		Expect(cluster.ShouldArchiveWALOnDeletion()).To(BeFalse())
+		Expect(cluster.ShouldExportInventoryOnDeletion()).To(BeFalse())
+		Expect(cluster.NeedsDeletionPolicyFinalizer()).To(BeFalse())
+		Expect(cluster.Spec.DeletionPolicy.GetTimeout()).To(Equal(DefaultDeletionPolicyTimeout * time.Second))
+	})
+
+	It("takes a final backup and archives the WAL files by default when backups are configured", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: backupConfiguration,
+			},
+		}
+		Expect(cluster.ShouldTakeFinalBackup()).To(BeTrue())
+		Expect(cluster.ShouldArchiveWALOnDeletion()).To(BeTrue())
+		Expect(cluster.NeedsDeletionPolicyFinalizer()).To(BeTrue())
+	})
+
+	It("allows disabling the final backup and the WAL archiving", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: backupConfiguration,
+				DeletionPolicy: &DeletionPolicy{
+					FinalBackup: &FinalBackupConfiguration{
+						Enabled: pointer.Bool(false),
+					},
+					ArchiveWAL: pointer.Bool(false),
+					Timeout:    60,
+				},
+			},
+		}
+		Expect(cluster.ShouldTakeFinalBackup()).To(BeFalse())
+		Expect(cluster.ShouldArchiveWALOnDeletion()).To(BeFalse())
+		Expect(cluster.NeedsDeletionPolicyFinalizer()).To(BeFalse())
+		Expect(cluster.Spec.DeletionPolicy.GetTimeout()).To(Equal(60 * time.Second))
+	})
+
+	It("doesn't hold the deletion of development clusters unless explicitly requested", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Profile: ClusterProfileDevelopment,
+				Backup:  backupConfiguration,
+			},
+		}
+		Expect(cluster.ShouldTakeFinalBackup()).To(BeFalse())
+		Expect(cluster.ShouldArchiveWALOnDeletion()).To(BeFalse())
+		Expect(cluster.NeedsDeletionPolicyFinalizer()).To(BeFalse())
+
+		cluster.Spec.DeletionPolicy = &DeletionPolicy{
+			FinalBackup: &FinalBackupConfiguration{
+				Enabled: pointer.Bool(true),
+			},
+		}
+		Expect(cluster.ShouldTakeFinalBackup()).To(BeTrue())
+		Expect(cluster.ShouldArchiveWALOnDeletion()).To(BeFalse())
+
+		cluster.Spec.DeletionPolicy.ArchiveWAL = pointer.Bool(true)
+		Expect(cluster.ShouldArchiveWALOnDeletion()).To(BeTrue())
+	})
+
+	It("limits the final backup timeout to the one of the deletion policy", func() {
+		var deletionPolicy *DeletionPolicy
+		Expect(deletionPolicy.GetFinalBackupMethod()).To(Equal(FinalBackupMethodBarmanObjectStore))
+		Expect(deletionPolicy.GetFinalBackupTimeout()).To(Equal(DefaultDeletionPolicyTimeout * time.Second))
+
+		deletionPolicy = &DeletionPolicy{
+			Timeout: 600,
+			FinalBackup: &FinalBackupConfiguration{
+				Timeout: 120,
+			},
+		}
+		Expect(deletionPolicy.GetFinalBackupTimeout()).To(Equal(120 * time.Second))
+
+		deletionPolicy.FinalBackup.Timeout = 900
+		Expect(deletionPolicy.GetFinalBackupTimeout()).To(Equal(600 * time.Second))
+	})
+
+	It("never takes a final backup of a replica cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: backupConfiguration,
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+				},
+			},
+		}
+		Expect(cluster.ShouldTakeFinalBackup()).To(BeFalse())
+		Expect(cluster.ShouldArchiveWALOnDeletion()).To(BeFalse())
+	})
+
+	It("holds the deletion when the inventory should be exported", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				DeletionPolicy: &DeletionPolicy{
+					ExportInventory: true,
+				},
+			},
+		}
+		Expect(cluster.NeedsDeletionPolicyFinalizer()).To(BeTrue())
+		Expect(cluster.GetInventoryConfigMapName()).To(Equal("cluster-example-inventory"))
+	})
+})
+
+var _ = Describe("instance Pod metadata", func() {
+	It("merges the inherited metadata with the one of the Pod template", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels:      map[string]string{"environment": "production", "team": "app"},
+					Annotations: map[string]string{"example.com/inherited": "true"},
+				},
+				PodTemplate: &InstancePodTemplate{
+					Metadata: EmbeddedObjectMetadata{
+						Labels:      map[string]string{"team": "dba"},
+						Annotations: map[string]string{"example.com/pod": "true"},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetFixedInheritedPodLabels()).To(Equal(map[string]string{
+			"environment": "production",
+			"team":        "dba",
+		}))
+		Expect(cluster.GetFixedInheritedPodAnnotations()).To(Equal(map[string]string{
+			"example.com/inherited": "true",
+			"example.com/pod":       "true",
+		}))
+		Expect(cluster.GetFixedInheritedLabels()).To(HaveKeyWithValue("team", "app"))
+	})
+
+	It("uses the inherited metadata without a Pod template", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels: map[string]string{"environment": "production"},
+				},
+			},
+		}
+		Expect(cluster.GetFixedInheritedPodLabels()).To(Equal(cluster.GetFixedInheritedLabels()))
+		Expect(cluster.GetFixedInheritedPodAnnotations()).To(BeNil())
+	})
+})
+
+var _ = Describe("managed databases", func() {
+	cluster := &Cluster{
+		ObjectMeta: v1.ObjectMeta{Name: "cluster-example"},
+		Spec: ClusterSpec{
+			Managed: &ManagedConfiguration{
+				Databases: []ManagedDatabase{
+					{Name: "orders", Owner: "orders_owner", Expose: true},
+					{Name: "reporting"},
+				},
+			},
+		},
+	}
+
+	It("defaults the owner to the name of the database", func() {
+		Expect(cluster.GetManagedDatabases()[0].GetOwner()).To(Equal("orders_owner"))
+		Expect(cluster.GetManagedDatabases()[1].GetOwner()).To(Equal("reporting"))
+	})
+
+	It("gets only the exposed databases", func() {
+		exposed := cluster.GetExposedDatabases()
+		Expect(exposed).To(HaveLen(1))
+		Expect(exposed[0].Name).To(Equal("orders"))
+		Expect(cluster.GetDatabaseResourcesName("orders")).To(Equal("cluster-example-db-orders"))
+	})
+
+	It("has no managed databases by default", func() {
+		Expect((&Cluster{}).GetManagedDatabases()).To(BeEmpty())
+		Expect((&Cluster{}).GetExposedDatabases()).To(BeEmpty())
+	})
+
+	It("gets the secrets of the user mappings", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							ForeignServers: []ManagedForeignServer{
+								{
+									Name:               "warehouse",
+									ForeignDataWrapper: "postgres_fdw",
+									UserMappings: []ManagedUserMapping{
+										{User: "orders_owner", CredentialsSecret: &LocalObjectReference{Name: "warehouse"}},
+										{User: "PUBLIC"},
+									},
+								},
+							},
+						},
+						{
+							Name: "reporting",
+							ForeignServers: []ManagedForeignServer{
+								{
+									Name:               "warehouse",
+									ForeignDataWrapper: "postgres_fdw",
+									UserMappings: []ManagedUserMapping{
+										{User: "reporting", CredentialsSecret: &LocalObjectReference{Name: "warehouse"}},
+										{User: "analyst", CredentialsSecret: &LocalObjectReference{Name: "analyst"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetUserMappingSecretNames()).To(Equal([]string{"warehouse", "analyst"}))
+		Expect((&Cluster{}).GetUserMappingSecretNames()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("shared preload libraries", func() {
+	It("adds the libraries required by the managed extensions", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AdditionalLibraries: []string{"pg_partman_bgw"},
+				},
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							Extensions: []ManagedDatabaseExtension{
+								{Name: "pg_partman", SharedPreloadLibraries: []string{"pg_partman_bgw"}},
+								{Name: "timescaledb", SharedPreloadLibraries: []string{"timescaledb"}},
+								{Name: "pg_squeeze", SharedPreloadLibraries: []string{"pg_squeeze"}, Ensure: EnsureAbsent},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetSharedPreloadLibraries()).To(Equal([]string{"pg_partman_bgw", "timescaledb"}))
+		Expect((&Cluster{}).GetSharedPreloadLibraries()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("extension images", func() {
+	It("gets the paths of the extension directories", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ExtensionConfiguration{
+						{Name: "pgvector", Image: "pgvector:0.8.0"},
+						{
+							Name:                 "postgis",
+							Image:                "postgis:3.5.2",
+							ExtensionControlPath: []string{"share"},
+							DynamicLibraryPath:   []string{"lib", "system/lib"},
+						},
+					},
+				},
+			},
+		}
+		extensionControlPath, dynamicLibraryPath := cluster.GetExtensionsPaths()
+		Expect(extensionControlPath).To(Equal([]string{"/extensions/pgvector/share", "/extensions/postgis/share"}))
+		Expect(dynamicLibraryPath).To(Equal([]string{
+			"/extensions/pgvector/lib", "/extensions/postgis/lib", "/extensions/postgis/system/lib",
+		}))
+	})
+})
+
+var _ = Describe("admin session policy", func() {
+	It("is disabled when no limit is set", func() {
+		Expect((&Cluster{}).GetAdminSessionPolicy()).To(BeNil())
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					AdminSessionPolicy: &AdminSessionPolicy{Roles: []string{"dba"}},
+				},
+			},
+		}
+		Expect(cluster.GetAdminSessionPolicy()).To(BeNil())
+	})
+
+	It("converts the limits to durations", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					AdminSessionPolicy: &AdminSessionPolicy{IdleTimeout: 300},
+				},
+			},
+		}
+		policy := cluster.GetAdminSessionPolicy()
+		Expect(policy).ToNot(BeNil())
+		Expect(policy.GetIdleTimeout()).To(Equal(5 * time.Minute))
+		Expect(policy.GetMaxDuration()).To(BeZero())
+	})
+})
+
+var _ = Describe("primary change reasons", func() {
+	It("considers a failover the change of an unhealthy or unreachable primary", func() {
+		Expect(PrimaryChangeReasonLiveness.IsFailover()).To(BeTrue())
+		Expect(PrimaryChangeReasonIsolation.IsFailover()).To(BeTrue())
+		Expect(PrimaryChangeReasonLiveness.GetKind()).To(Equal("failover"))
+	})
+
+	It("considers a switchover every other change", func() {
+		Expect(PrimaryChangeReasonUnschedulableNode.IsFailover()).To(BeFalse())
+		Expect(PrimaryChangeReasonRollingUpdate.IsFailover()).To(BeFalse())
+		Expect(PrimaryChangeReasonManual.IsFailover()).To(BeFalse())
+		Expect(PrimaryChangeReasonManual.GetKind()).To(Equal("switchover"))
+	})
+})
+
+var _ = Describe("huge pages", func() {
+	It("doesn't use huge pages by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetHugePagesResourceName()).To(BeEmpty())
+		Expect(cluster.GetHugePageSize()).To(BeZero())
+	})
+
+	It("ignores the huge pages resources set to zero", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						"hugepages-2Mi": resource.MustParse("0"),
+					},
+				},
+			},
+		}
+		Expect(cluster.GetHugePagesResourceName()).To(BeEmpty())
+	})
+
+	It("gets the size of the requested huge pages", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+						"hugepages-1Gi":       resource.MustParse("2Gi"),
+					},
+				},
+			},
+		}
+		Expect(cluster.GetHugePagesResourceName()).To(Equal(corev1.ResourceName("hugepages-1Gi")))
+		Expect(cluster.GetHugePageSize()).To(Equal(int64(1024 * 1024 * 1024)))
+	})
+})
+
+var _ = Describe("read-only service export", func() {
+	It("doesn't export the service by default", func() {
+		Expect(Cluster{}.GetReadOnlyServiceExportAPI()).To(BeEmpty())
+		cluster := Cluster{Spec: ClusterSpec{ReplicaCluster: &ReplicaClusterConfiguration{Enabled: true}}}
+		Expect(cluster.GetReadOnlyServiceExportAPI()).To(BeEmpty())
+	})
+
+	It("defaults to the Multi-Cluster Services API", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled:               true,
+					ExportReadOnlyService: &ServiceExportConfiguration{},
+				},
+			},
+		}
+		Expect(cluster.GetReadOnlyServiceExportAPI()).To(Equal(ServiceExportAPIMultiCluster))
+
+		cluster.Spec.ReplicaCluster.ExportReadOnlyService.API = ServiceExportAPISubmariner
+		Expect(cluster.GetReadOnlyServiceExportAPI()).To(Equal(ServiceExportAPISubmariner))
+	})
+})
+
+var _ = Describe("import options", func() {
+	It("uses a single job and analyzes the databases by default", func() {
+		importSpec := Import{}
+		Expect(importSpec.GetJobs()).To(Equal(1))
+		Expect(importSpec.ShouldAnalyze()).To(BeTrue())
+	})
+
+	It("allows parallel jobs and skipping the analyze", func() {
+		importSpec := Import{
+			Jobs:              4,
+			PostImportAnalyze: pointer.Bool(false),
+		}
+		Expect(importSpec.GetJobs()).To(Equal(4))
+		Expect(importSpec.ShouldAnalyze()).To(BeFalse())
+	})
+
+	It("detects the online mode and its publication", func() {
+		var nilImport *Import
+		Expect(nilImport.IsOnline()).To(BeFalse())
+		Expect((&Import{}).IsOnline()).To(BeFalse())
+
+		importSpec := Import{Online: &OnlineImport{}}
+		Expect(importSpec.IsOnline()).To(BeTrue())
+		Expect(importSpec.Online.GetPublicationName()).To(Equal(OnlineImportDefaultPublicationName))
+
+		importSpec.Online.PublicationName = "app_pub"
+		Expect(importSpec.Online.GetPublicationName()).To(Equal("app_pub"))
+	})
+})
+
+var _ = Describe("image verification", func() {
+	const digest = "sha256:6cfd6d2c9b6a9a4d0fd8ec1ffd0d0c4d1bfa1b7e4e1f1ac1e5f2d5e1e2b3c4d5"
+
+	It("uses the requested image when verification is not enabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1"},
+			Status: ClusterStatus{
+				ImageDigest: &ImageDigestStatus{Image: "ghcr.io/cloudnative-pg/postgresql:15.0", Digest: digest},
+			},
+		}
+		Expect(cluster.IsImageDigestPinned()).To(BeFalse())
+		Expect(cluster.GetImageName()).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.1"))
+	})
+
+	It("uses the resolved image when digest pinning is enabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName:         "ghcr.io/cloudnative-pg/postgresql:15.1",
+				ImageVerification: &ImageVerificationConfiguration{PinDigest: true},
+			},
+			Status: ClusterStatus{
+				ImageDigest: &ImageDigestStatus{Image: "ghcr.io/cloudnative-pg/postgresql:15.0", Digest: digest},
+			},
+		}
+		Expect(cluster.GetImageName()).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.0@" + digest))
+		Expect(cluster.GetRequestedImageName()).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.1"))
+	})
+
+	It("pins the digest when signatures are verified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageVerification: &ImageVerificationConfiguration{
+					PublicKeys: []SecretKeySelector{
+						{LocalObjectReference: LocalObjectReference{Name: "cosign"}, Key: "cosign.pub"},
+					},
+				},
+			},
+		}
+		Expect(cluster.IsImageDigestPinned()).To(BeTrue())
+		Expect(cluster.IsImageSignatureVerificationEnabled()).To(BeTrue())
+	})
+})
+
+var _ = Describe("instance manager update method", func() {
+	AfterEach(func() {
+		configuration.Current = configuration.NewConfiguration()
+	})
+
+	It("follows the operator configuration by default", func() {
+		cluster := &Cluster{}
+		configuration.Current.EnableInstanceManagerInplaceUpdates = false
+		Expect(cluster.IsInstanceManagerInplaceUpdateEnabled()).To(BeFalse())
+		configuration.Current.EnableInstanceManagerInplaceUpdates = true
+		Expect(cluster.IsInstanceManagerInplaceUpdateEnabled()).To(BeTrue())
+	})
+
+	It("overrides the operator configuration when set", func() {
+		cluster := &Cluster{Spec: ClusterSpec{InstanceManagerUpdateMethod: InstanceManagerUpdateMethodInPlace}}
+		configuration.Current.EnableInstanceManagerInplaceUpdates = false
+		Expect(cluster.IsInstanceManagerInplaceUpdateEnabled()).To(BeTrue())
+
+		cluster.Spec.InstanceManagerUpdateMethod = InstanceManagerUpdateMethodRollout
+		configuration.Current.EnableInstanceManagerInplaceUpdates = true
+		Expect(cluster.IsInstanceManagerInplaceUpdateEnabled()).To(BeFalse())
+	})
+})
+
+var _ = Describe("instance groups", func() {
+	reportingResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+
+	cluster := &Cluster{
+		Spec: ClusterSpec{
+			Instances: 4,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+			InstanceGroups: []InstanceGroup{
+				{Name: "reporting", Instances: 1, ExcludedFromFailover: true, Resources: &reportingResources},
+				{Name: "fast", Instances: 2},
+			},
+		},
+	}
+
+	It("finds the group of the instances", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Status.InstanceGroups = map[string]string{
+			"cluster-example-1": "fast",
+			"cluster-example-2": "reporting",
+			"cluster-example-3": "removed",
+		}
+
+		Expect(cluster.GetInstanceGroup("cluster-example-1").Name).To(Equal("fast"))
+		Expect(cluster.GetInstanceGroup("cluster-example-3")).To(BeNil())
+		Expect(cluster.GetInstanceGroup("cluster-example-4")).To(BeNil())
+
+		Expect(cluster.IsInstanceExcludedFromFailover("cluster-example-2")).To(BeTrue())
+		Expect(cluster.IsInstanceExcludedFromFailover("cluster-example-1")).To(BeFalse())
+
+		Expect(cluster.GetInstanceResources("cluster-example-2")).To(Equal(reportingResources))
+		Expect(cluster.GetInstanceResources("cluster-example-1")).To(Equal(cluster.Spec.Resources))
+	})
+
+	It("assigns the new instances to the groups with free slots", func() {
+		cluster := cluster.DeepCopy()
+		Expect(cluster.GetInstanceGroupForNewInstance(true)).To(Equal("fast"))
+		Expect(cluster.GetInstanceGroupForNewInstance(false)).To(Equal("reporting"))
+
+		cluster.Status.InstanceGroups = map[string]string{
+			"cluster-example-1": "fast",
+			"cluster-example-2": "reporting",
+			"cluster-example-3": "fast",
+		}
+		Expect(cluster.GetInstanceGroupForNewInstance(false)).To(BeEmpty())
+	})
+
+	It("detects the instances of the groups over their quota", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Instances = 3
+		cluster.Status.InstanceGroups = map[string]string{
+			"cluster-example-1": "fast",
+			"cluster-example-2": "reporting",
+			"cluster-example-3": "fast",
+		}
+		instances := []string{"cluster-example-1", "cluster-example-2", "cluster-example-3", "cluster-example-4"}
+		Expect(cluster.GetInstancesOverGroupQuota(instances)).To(ConsistOf("cluster-example-4"))
+
+		cluster.Spec.InstanceGroups[1].Instances = 1
+		cluster.Spec.Instances = 4
+		Expect(cluster.GetInstancesOverGroupQuota(instances)).To(ConsistOf("cluster-example-1", "cluster-example-3"))
+	})
+})
+
+var _ = Describe("instance overrides", func() {
+	cluster := &Cluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "cluster-example",
+			Annotations: map[string]string{
+				utils.InstanceOverridesAnnotationName: `{
+					"2": {"resources": {"requests": {"cpu": "4"}}, "nodeSelector": {"cpu": "fast"}},
+					"3": {"nodeSelector": {"disk": "ssd"}}
+				}`,
+			},
+		},
+		Spec: ClusterSpec{
+			Instances: 3,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1"),
+				},
+			},
+			Affinity: AffinityConfiguration{
+				NodeSelector: map[string]string{"workload": "postgres", "disk": "hdd"},
+			},
+			InstanceGroups: []InstanceGroup{
+				{Name: "reporting", Instances: 1, NodeSelector: map[string]string{"workload": "reporting"}},
+			},
+		},
+		Status: ClusterStatus{
+			InstanceGroups: map[string]string{"cluster-example-3": "reporting"},
+		},
+	}
+
+	It("finds the overrides of the instances", func() {
+		Expect(cluster.GetInstanceOverride("cluster-example-1")).To(BeNil())
+		Expect(cluster.GetInstanceOverride("cluster-example-2")).ToNot(BeNil())
+		Expect(cluster.GetInstanceOverride("other-cluster-2")).To(BeNil())
+	})
+
+	It("overrides the resources of the instance", func() {
+		Expect(cluster.GetInstanceResources("cluster-example-1")).To(Equal(cluster.Spec.Resources))
+		resources := cluster.GetInstanceResources("cluster-example-2")
+		Expect(resources.Requests.Cpu().String()).To(Equal("4"))
+	})
+
+	It("merges the node selectors of the cluster, of the group and of the overrides", func() {
+		Expect(cluster.GetInstanceNodeSelector("cluster-example-1")).To(Equal(cluster.Spec.Affinity.NodeSelector))
+		Expect(cluster.GetInstanceNodeSelector("cluster-example-2")).To(Equal(map[string]string{
+			"workload": "postgres", "disk": "hdd", "cpu": "fast",
+		}))
+		Expect(cluster.GetInstanceNodeSelector("cluster-example-3")).To(Equal(map[string]string{
+			"workload": "reporting", "disk": "ssd",
+		}))
+	})
+
+	It("ignores an invalid annotation", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Annotations[utils.InstanceOverridesAnnotationName] = "{"
+		Expect(cluster.GetInstanceOverride("cluster-example-2")).To(BeNil())
+		Expect(cluster.GetInstanceResources("cluster-example-2")).To(Equal(cluster.Spec.Resources))
+	})
+})
@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// objectWithWarnings is a resource which can report the deprecated
+// settings it uses
+type objectWithWarnings interface {
+	runtime.Object
+	getDeprecationWarnings() []string
+}
+
+// warningsHandler wraps the validating webhook of a resource, adding to its
+// responses the warnings about the resource being admitted. Warnings don't
+// prevent the resource from being admitted, and are shown by kubectl
+type warningsHandler struct {
+	handler admission.Handler
+	object  objectWithWarnings
+	decoder *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &warningsHandler{}
+
+// newValidatingWebhookWithWarnings creates the validating webhook of the
+// passed resource, which also reports its deprecated settings
+func newValidatingWebhookWithWarnings(validator interface {
+	admission.Validator
+	objectWithWarnings
+},
+) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &warningsHandler{
+			handler: admission.ValidatingWebhookFor(validator).Handler,
+			object:  validator,
+		},
+	}
+}
+
+// InjectDecoder implements admission.DecoderInjector, injecting the
+// decoder in the wrapped handler too
+func (h *warningsHandler) InjectDecoder(decoder *admission.Decoder) error {
+	h.decoder = decoder
+	_, err := admission.InjectDecoderInto(decoder, h.handler)
+	return err
+}
+
+// Handle implements admission.Handler
+func (h *warningsHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	response := h.handler.Handle(ctx, req)
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return response
+	}
+
+	object := h.object.DeepCopyObject().(objectWithWarnings)
+	if err := h.decoder.DecodeRaw(req.Object, object); err != nil {
+		return response
+	}
+
+	response.Warnings = append(response.Warnings, object.getDeprecationWarnings()...)
+	return response
+}
+
+// getDeprecationWarnings gets the warnings about the deprecated settings
+// used by the cluster, and about the ones going to change
+func (r *Cluster) getDeprecationWarnings() []string {
+	var warnings []string
+
+	if r.Spec.Bootstrap != nil && r.Spec.Bootstrap.InitDB != nil && len(r.Spec.Bootstrap.InitDB.Options) > 0 {
+		warnings = append(warnings,
+			"spec.bootstrap.initdb.options is deprecated and will be removed in a future version of the API: "+
+				"use the corresponding fields of spec.bootstrap.initdb instead")
+	}
+
+	if psqlVersion, err := r.GetPostgresqlVersion(); err == nil {
+		names := make([]string, 0, len(r.Spec.PostgresConfiguration.Parameters))
+		for name := range r.Spec.PostgresConfiguration.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			removedIn := postgres.ParametersCatalog[name].Versions.Max
+			if removedIn == postgres.MajorVersionRangeUnlimited || psqlVersion >= removedIn {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf(
+				"spec.postgresql.parameters.%s: the parameter is removed in PostgreSQL %d, "+
+					"it must be removed before upgrading", name, removedIn/10000))
+		}
+	}
+
+	return warnings
+}
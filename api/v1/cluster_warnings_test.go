@@ -0,0 +1,100 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("deprecation warnings", func() {
+	It("doesn't warn about a cluster without deprecated settings", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "256MB"},
+				},
+			},
+		}
+		Expect(cluster.getDeprecationWarnings()).To(BeEmpty())
+	})
+
+	It("warns about the initdb options", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Options: []string{"--data-checksums"}},
+				},
+			},
+		}
+		warnings := cluster.getDeprecationWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("spec.bootstrap.initdb.options"))
+	})
+
+	It("warns about the parameters removed by the next PostgreSQL versions", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.1",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"vacuum_defer_cleanup_age": "0"},
+				},
+			},
+		}
+		warnings := cluster.getDeprecationWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("removed in PostgreSQL 16"))
+	})
+
+	It("adds the warnings to the responses of the validating webhook", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).ToNot(HaveOccurred())
+
+		webhook := newValidatingWebhookWithWarnings(&Cluster{})
+		Expect(webhook.Handler.(admission.DecoderInjector).InjectDecoder(decoder)).To(Succeed())
+
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 1,
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Options: []string{"--data-checksums"}},
+				},
+			},
+		}
+		cluster.SetGroupVersionKind(GroupVersion.WithKind("Cluster"))
+		raw, err := json.Marshal(cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		response := webhook.Handler.Handle(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		Expect(response.Warnings).To(HaveLen(1))
+	})
+})
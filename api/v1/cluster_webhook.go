@@ -19,9 +19,13 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"path"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -62,8 +66,15 @@ var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
 
 // SetupWebhookWithManager setup the webhook inside the controller manager
 func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// The validating webhook is registered before the other ones, so
+	// that its responses include the deprecation warnings
+	mgr.GetWebhookServer().Register(
+		"/validate-postgresql-cnpg-io-v1-cluster",
+		newValidatingWebhookWithWarnings(r))
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(&clusterDefaulter{reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
@@ -75,6 +86,14 @@ var _ webhook.Defaulter = &Cluster{}
 func (r *Cluster) Default() {
 	clusterLog.Info("default", "name", r.Name, "namespace", r.Namespace)
 
+	// The operator-wide template is only merged into new clusters,
+	// which have not been persisted yet
+	if r.CreationTimestamp.IsZero() {
+		if err := r.applyClusterTemplate(); err != nil {
+			clusterLog.Error(err, "unable to apply the cluster template", "name", r.Name, "namespace", r.Namespace)
+		}
+	}
+
 	r.setDefaults(true)
 }
 
@@ -100,10 +119,19 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 		r.defaultRecovery()
 	case r.Spec.Bootstrap.PgBaseBackup != nil:
 		r.defaultPgBaseBackup()
+	case r.Spec.Bootstrap.ExistingVolume != nil:
+		r.defaultExistingVolume()
 	default:
 		r.defaultInitDB()
 	}
 
+	// Development clusters get a minimal storage
+	// when it's not specified
+	if r.IsDevelopmentProfile() && r.Spec.StorageConfiguration.Size == "" &&
+		r.Spec.StorageConfiguration.PersistentVolumeClaimTemplate == nil {
+		r.Spec.StorageConfiguration.Size = DevelopmentStorageSize
+	}
+
 	// Defaulting the pod anti-affinity type if podAntiAffinity
 	if (r.Spec.Affinity.EnablePodAntiAffinity == nil || *r.Spec.Affinity.EnablePodAntiAffinity) &&
 		r.Spec.Affinity.PodAntiAffinityType == "" {
@@ -245,6 +273,23 @@ func (r *Cluster) defaultPgBaseBackup() {
 	}
 }
 
+// defaultExistingVolume enriches the existingVolume bootstrap with defaults if not all
+// the required arguments were passed
+func (r *Cluster) defaultExistingVolume() {
+	// if none area is provided, will ignore the application database configuration
+	if r.Spec.Bootstrap.ExistingVolume.Database == "" &&
+		r.Spec.Bootstrap.ExistingVolume.Owner == "" &&
+		r.Spec.Bootstrap.ExistingVolume.Secret == nil {
+		return
+	}
+	if r.Spec.Bootstrap.ExistingVolume.Database == "" {
+		r.Spec.Bootstrap.ExistingVolume.Database = DefaultApplicationDatabaseName
+	}
+	if r.Spec.Bootstrap.ExistingVolume.Owner == "" {
+		r.Spec.Bootstrap.ExistingVolume.Owner = r.Spec.Bootstrap.ExistingVolume.Database
+	}
+}
+
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-cluster,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters,versions=v1,name=vcluster.kb.io,sideEffects=None
 
@@ -270,20 +315,26 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateInitDB,
 		r.validateRecoveryApplicationDatabase,
 		r.validatePgBaseBackupApplicationDatabase,
+		r.validateExistingVolumeApplicationDatabase,
 		r.validateImport,
 		r.validateSuperuserSecret,
 		r.validateCerts,
 		r.validateBootstrapMethod,
 		r.validateImageName,
+		r.validateMinorVersionPinning,
 		r.validateImagePullPolicy,
 		r.validateRecoveryTarget,
 		r.validatePrimaryUpdateStrategy,
 		r.validateMinSyncReplicas,
 		r.validateMaxSyncReplicas,
+		r.validateProfile,
 		r.validateWalStorageSize,
+		r.validateWalStorageCapacity,
+		r.validateSharedBuffers,
 		r.validateName,
 		r.validateBootstrapPgBaseBackupSource,
 		r.validateBootstrapRecoverySource,
+		r.validateBootstrapExistingVolume,
 		r.validateExternalClusters,
 		r.validateTolerations,
 		r.validateAntiAffinity,
@@ -291,7 +342,30 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateBackupConfiguration,
 		r.validateConfiguration,
 		r.validateLDAP,
+		r.validatePgHBA,
+		r.validatePgIdent,
+		r.validatePoolerCertificateUsers,
+		r.validateManagedDatabases,
+		r.validateManagedServices,
+		r.validateEphemeralVolumesSizeLimit,
+		r.validateHugePages,
+		r.validateExtensions,
 		r.validateReplicationSlots,
+		r.validateCascadingReplication,
+		r.validateLogging,
+		r.validateWALCompression,
+		r.validatePostgresTLS,
+		r.validateSCRAMMigration,
+		r.validateMaxStandbyLag,
+		r.validateSmartShutdownTimeout,
+		r.validateCloneThrottling,
+		r.validateCloneMethod,
+		r.validateDeletionPolicy,
+		r.validatePodTemplate,
+		r.validateCredentialsFiles,
+		r.validateNetworkPolicy,
+		r.validateInstanceGroups,
+		r.validateInstanceOverrides,
 	}
 
 	for _, validate := range validations {
@@ -373,6 +447,547 @@ func (r *Cluster) validateLDAP() field.ErrorList {
 				"only bind+search or bind method can be specified"))
 	}
 
+	if ldapConfig.CACertificate != nil && ldapConfig.Scheme != LDAPSchemeLDAPS && !ldapConfig.TLS {
+		result = append(
+			result,
+			field.Invalid(field.NewPath("spec", "postgresql", "ldap", "caCertificate"),
+				ldapConfig.CACertificate.Name,
+				"the CA certificate can be specified only when using the ldaps scheme or TLS"))
+	}
+
+	return result
+}
+
+// validatePoolerCertificateUsers validates the users authenticated
+// via certificate through the pooler
+func (r *Cluster) validatePoolerCertificateUsers() field.ErrorList {
+	return validateCertificateUsers(
+		field.NewPath("spec", "postgresql", "poolerCertificateUsers"),
+		r.Spec.PostgresConfiguration.PoolerCertificateUsers)
+}
+
+// validateCertificateUsers checks that a list of users to be authenticated
+// via certificate can be written in the HBA rules and doesn't include
+// any user managed by the operator
+func validateCertificateUsers(path *field.Path, users []string) field.ErrorList {
+	var result field.ErrorList
+
+	reservedUsers := stringset.From([]string{
+		"postgres",
+		StreamingReplicationUser,
+		PGBouncerPoolerUserName,
+	})
+	seenUsers := stringset.New()
+	for idx, user := range users {
+		switch {
+		case user == "" || strings.ContainsAny(user, "\"\n"):
+			result = append(result, field.Invalid(path.Index(idx), user,
+				"the user name must not be empty and can't contain double quotes or new lines"))
+		case reservedUsers.Has(user):
+			result = append(result, field.Invalid(path.Index(idx), user,
+				"the user is managed by the operator and can't be authenticated via the pooler"))
+		case seenUsers.Has(user):
+			result = append(result, field.Duplicate(path.Index(idx), user))
+		}
+		seenUsers.Put(user)
+	}
+
+	return result
+}
+
+// minPasswordRotation is the shortest interval allowed between the
+// rotations of the password of the owner of an exposed database
+const minPasswordRotation = time.Hour
+
+// databaseParameterNameRegex matches the names of the configuration
+// parameters which can be set on a managed database, including the
+// custom ones with a prefix
+var databaseParameterNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
+// validateManagedDatabases validates the databases managed
+// by the instance manager
+func (r *Cluster) validateManagedDatabases() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "managed", "databases")
+	reservedDatabases := stringset.From([]string{"postgres", "template0", "template1"})
+	reservedOwners := stringset.From([]string{"postgres", StreamingReplicationUser, PGBouncerPoolerUserName})
+	seenDatabases := stringset.New()
+	for idx, database := range r.GetManagedDatabases() {
+		databasePath := basePath.Index(idx)
+		switch {
+		case database.Name == "":
+			result = append(result, field.Required(databasePath.Child("name"), "the database name is required"))
+		case reservedDatabases.Has(database.Name):
+			result = append(result, field.Invalid(databasePath.Child("name"), database.Name,
+				"the database can't be managed"))
+		case seenDatabases.Has(database.Name):
+			result = append(result, field.Duplicate(databasePath.Child("name"), database.Name))
+		}
+		seenDatabases.Put(database.Name)
+
+		if owner := database.GetOwner(); reservedOwners.Has(owner) {
+			result = append(result, field.Invalid(databasePath.Child("owner"), owner,
+				"the role is managed by the operator and can't own a managed database"))
+		}
+
+		for _, parameter := range sortedParameterNames(database.Parameters) {
+			if !databaseParameterNameRegex.MatchString(parameter) {
+				result = append(result, field.Invalid(databasePath.Child("parameters"), parameter,
+					"invalid configuration parameter name"))
+			}
+		}
+
+		result = append(result, validateManagedGrants(databasePath.Child("grants"), database.Grants)...)
+		result = append(result, validateManagedDatabaseExtensions(databasePath.Child("extensions"),
+			database.Extensions)...)
+		result = append(result, validateManagedForeignDataWrappers(databasePath.Child("foreignDataWrappers"),
+			database.ForeignDataWrappers)...)
+		result = append(result, validateManagedForeignServers(databasePath.Child("foreignServers"),
+			database.ForeignServers)...)
+
+		if rotation := database.PasswordRotation; rotation != nil {
+			switch {
+			case !database.Expose:
+				result = append(result, field.Invalid(databasePath.Child("passwordRotation"), rotation.String(),
+					"the password can only be rotated for exposed databases"))
+			case rotation.Duration < minPasswordRotation:
+				result = append(result, field.Invalid(databasePath.Child("passwordRotation"), rotation.String(),
+					fmt.Sprintf("the password can't be rotated more often than every %v", minPasswordRotation)))
+			}
+		}
+
+		if !database.Expose || database.Name == "" {
+			continue
+		}
+		for _, msg := range validationutil.IsDNS1035Label(r.GetDatabaseResourcesName(database.Name)) {
+			result = append(result, field.Invalid(databasePath.Child("name"), database.Name,
+				fmt.Sprintf("the database can't be exposed as %q: %s",
+					r.GetDatabaseResourcesName(database.Name), msg)))
+		}
+	}
+
+	return result
+}
+
+// sortedParameterNames gets the names of the passed parameters in a
+// stable order
+func sortedParameterNames(parameters map[string]string) []string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateManagedServices validates the customizations of the services
+// generated by the operator and the additional services
+func (r *Cluster) validateManagedServices() field.ErrorList {
+	if r.Spec.Managed == nil || r.Spec.Managed.Services == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "managed", "services")
+
+	seenSelectorTypes := stringset.New()
+	for idx, template := range r.Spec.Managed.Services.Templates {
+		templatePath := basePath.Child("templates").Index(idx)
+		if seenSelectorTypes.Has(string(template.SelectorType)) {
+			result = append(result, field.Duplicate(templatePath.Child("selectorType"), template.SelectorType))
+		}
+		seenSelectorTypes.Put(string(template.SelectorType))
+		if template.ExcludePrimary && template.SelectorType != ServiceSelectorTypeR {
+			result = append(result, field.Invalid(templatePath.Child("excludePrimary"), template.ExcludePrimary,
+				"the primary can only be excluded from the `r` service"))
+		}
+		if template.PrimaryFallback && template.SelectorType != ServiceSelectorTypeRO {
+			result = append(result, field.Invalid(templatePath.Child("primaryFallback"), template.PrimaryFallback,
+				"the fallback to the primary is only available for the `ro` service"))
+		}
+		result = append(result, validateServiceTemplateSpec(templatePath, template.ServiceTemplateSpec)...)
+	}
+
+	reservedNames := stringset.From([]string{
+		r.GetServiceAnyName(),
+		r.GetServiceReadName(),
+		r.GetServiceReadOnlyName(),
+		r.GetServiceReadWriteName(),
+	})
+	for _, database := range r.GetExposedDatabases() {
+		reservedNames.Put(r.GetDatabaseResourcesName(database.Name))
+	}
+
+	perInstance := r.Spec.Managed.Services.PerInstance
+	if perInstance != nil {
+		result = append(result, validateServiceTemplateSpec(basePath.Child("perInstance"), *perInstance)...)
+	}
+
+	seenNames := stringset.New()
+	for idx, service := range r.Spec.Managed.Services.Additional {
+		servicePath := basePath.Child("additional").Index(idx)
+		switch {
+		case service.Name == "":
+			result = append(result, field.Required(servicePath.Child("name"), "the service name is required"))
+		case reservedNames.Has(service.Name):
+			result = append(result, field.Invalid(servicePath.Child("name"), service.Name,
+				"the name is used by a service generated by the operator"))
+		case perInstance != nil && r.isInstanceName(service.Name):
+			result = append(result, field.Invalid(servicePath.Child("name"), service.Name,
+				"the name is used by the service of an instance"))
+		case seenNames.Has(service.Name):
+			result = append(result, field.Duplicate(servicePath.Child("name"), service.Name))
+		default:
+			for _, msg := range validationutil.IsDNS1035Label(service.Name) {
+				result = append(result, field.Invalid(servicePath.Child("name"), service.Name, msg))
+			}
+		}
+		seenNames.Put(service.Name)
+		result = append(result, validateServiceTemplateSpec(servicePath, service.ServiceTemplateSpec)...)
+	}
+
+	return result
+}
+
+// isInstanceName checks whether the passed name can be the one of an
+// instance of the cluster, i.e. `<cluster>-<serial>`
+func (r *Cluster) isInstanceName(name string) bool {
+	serial := strings.TrimPrefix(name, r.Name+"-")
+	if serial == name {
+		return false
+	}
+	_, err := strconv.Atoi(serial)
+	return err == nil
+}
+
+// validateServiceTemplateSpec checks that the options of a service
+// are compatible with its type
+func validateServiceTemplateSpec(basePath *field.Path, spec ServiceTemplateSpec) field.ErrorList {
+	var result field.ErrorList
+
+	if spec.ExternalTrafficPolicy != "" && spec.Type != v1.ServiceTypeNodePort &&
+		spec.Type != v1.ServiceTypeLoadBalancer {
+		result = append(result, field.Invalid(basePath.Child("externalTrafficPolicy"), spec.ExternalTrafficPolicy,
+			"the external traffic policy requires the `NodePort` or `LoadBalancer` service type"))
+	}
+	if len(spec.LoadBalancerSourceRanges) > 0 && spec.Type != v1.ServiceTypeLoadBalancer {
+		result = append(result, field.Invalid(basePath.Child("loadBalancerSourceRanges"),
+			spec.LoadBalancerSourceRanges, "the source ranges require the `LoadBalancer` service type"))
+	}
+
+	return result
+}
+
+// validateManagedForeignDataWrappers validates the foreign data
+// wrappers of a managed database
+func validateManagedForeignDataWrappers(
+	basePath *field.Path,
+	wrappers []ManagedForeignDataWrapper,
+) field.ErrorList {
+	var result field.ErrorList
+
+	seenWrappers := stringset.New()
+	for idx, wrapper := range wrappers {
+		wrapperPath := basePath.Index(idx)
+		switch {
+		case wrapper.Name == "":
+			result = append(result, field.Required(wrapperPath.Child("name"),
+				"the foreign data wrapper name is required"))
+		case seenWrappers.Has(wrapper.Name):
+			result = append(result, field.Duplicate(wrapperPath.Child("name"), wrapper.Name))
+		}
+		seenWrappers.Put(wrapper.Name)
+
+		result = append(result, validateObjectOptions(wrapperPath.Child("options"), wrapper.Options)...)
+	}
+
+	return result
+}
+
+// validateManagedForeignServers validates the foreign servers of a
+// managed database and their user mappings
+func validateManagedForeignServers(basePath *field.Path, servers []ManagedForeignServer) field.ErrorList {
+	var result field.ErrorList
+
+	seenServers := stringset.New()
+	for idx, server := range servers {
+		serverPath := basePath.Index(idx)
+		switch {
+		case server.Name == "":
+			result = append(result, field.Required(serverPath.Child("name"), "the foreign server name is required"))
+		case seenServers.Has(server.Name):
+			result = append(result, field.Duplicate(serverPath.Child("name"), server.Name))
+		}
+		seenServers.Put(server.Name)
+
+		if server.IsPresent() && server.ForeignDataWrapper == "" {
+			result = append(result, field.Required(serverPath.Child("foreignDataWrapper"),
+				"the foreign data wrapper of the server is required"))
+		}
+
+		result = append(result, validateObjectOptions(serverPath.Child("options"), server.Options)...)
+
+		seenUsers := stringset.New()
+		for mappingIdx, mapping := range server.UserMappings {
+			mappingPath := serverPath.Child("userMappings").Index(mappingIdx)
+			user := mapping.User
+			if mapping.IsPublic() {
+				user = "public"
+			}
+			switch {
+			case mapping.User == "":
+				result = append(result, field.Required(mappingPath.Child("user"), "the user is required"))
+			case seenUsers.Has(user):
+				result = append(result, field.Duplicate(mappingPath.Child("user"), mapping.User))
+			}
+			seenUsers.Put(user)
+
+			result = append(result, validateObjectOptions(mappingPath.Child("options"), mapping.Options)...)
+			if mapping.CredentialsSecret == nil {
+				continue
+			}
+			for _, option := range []string{"user", "password"} {
+				if _, ok := mapping.Options[option]; ok {
+					result = append(result, field.Invalid(mappingPath.Child("options"), option,
+						"the option is taken from the credentials Secret"))
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// validateObjectOptions validates the names of the options of a
+// foreign data wrapper, foreign server or user mapping
+func validateObjectOptions(basePath *field.Path, options map[string]string) field.ErrorList {
+	var result field.ErrorList
+	for name := range options {
+		if name == "" {
+			result = append(result, field.Invalid(basePath, name, "the option name can't be empty"))
+		}
+	}
+	return result
+}
+
+// validateManagedDatabaseExtensions validates the extensions
+// installed in a managed database
+func validateManagedDatabaseExtensions(basePath *field.Path, extensions []ManagedDatabaseExtension) field.ErrorList {
+	var result field.ErrorList
+
+	seenExtensions := stringset.New()
+	for idx, extension := range extensions {
+		extensionPath := basePath.Index(idx)
+		switch {
+		case extension.Name == "":
+			result = append(result, field.Required(extensionPath.Child("name"), "the extension name is required"))
+		case seenExtensions.Has(extension.Name):
+			result = append(result, field.Duplicate(extensionPath.Child("name"), extension.Name))
+		}
+		seenExtensions.Put(extension.Name)
+
+		for _, managedExtension := range postgres.ManagedExtensions {
+			if extension.Name == managedExtension.Name {
+				result = append(result, field.Invalid(extensionPath.Child("name"), extension.Name,
+					"the extension is managed by the operator through the PostgreSQL configuration"))
+			}
+		}
+
+		for libraryIdx, library := range extension.SharedPreloadLibraries {
+			if library == "" || strings.ContainsAny(library, ", ") {
+				result = append(result, field.Invalid(
+					extensionPath.Child("sharedPreloadLibraries").Index(libraryIdx), library,
+					"not a valid library name"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validateManagedGrants validates the privileges granted
+// on a managed database
+func validateManagedGrants(basePath *field.Path, grants []ManagedGrant) field.ErrorList {
+	var result field.ErrorList
+
+	reservedRoles := stringset.From([]string{"postgres", StreamingReplicationUser, PGBouncerPoolerUserName})
+	seenRoles := stringset.New()
+	for idx, grant := range grants {
+		grantPath := basePath.Index(idx)
+		switch {
+		case grant.Role == "":
+			result = append(result, field.Required(grantPath.Child("role"), "the role is required"))
+		case reservedRoles.Has(grant.Role):
+			result = append(result, field.Invalid(grantPath.Child("role"), grant.Role,
+				"the privileges of the role are managed by the operator"))
+		case seenRoles.Has(grant.Role):
+			result = append(result, field.Duplicate(grantPath.Child("role"), grant.Role))
+		}
+		seenRoles.Put(grant.Role)
+
+		seenSchemas := stringset.New()
+		for schemaIdx, schema := range grant.Schemas {
+			schemaPath := grantPath.Child("schemas").Index(schemaIdx)
+			switch {
+			case schema.Name == "":
+				result = append(result, field.Required(schemaPath.Child("name"), "the schema name is required"))
+			case seenSchemas.Has(schema.Name):
+				result = append(result, field.Duplicate(schemaPath.Child("name"), schema.Name))
+			}
+			seenSchemas.Put(schema.Name)
+
+			if schema.DefaultPrivileges && len(schema.TablePrivileges) == 0 {
+				result = append(result, field.Invalid(schemaPath.Child("defaultPrivileges"), schema.DefaultPrivileges,
+					"the default privileges require some table privileges"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validatePgHBA checks the syntax of the user-defined pg_hba.conf rules
+func (r *Cluster) validatePgHBA() field.ErrorList {
+	var result field.ErrorList
+
+	for idx, rule := range r.Spec.PostgresConfiguration.PgHBA {
+		if err := postgres.ValidateHBARule(rule); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "pg_hba").Index(idx), rule, err.Error()))
+		}
+	}
+
+	return result
+}
+
+// validatePgIdent checks the syntax of the user-defined pg_ident.conf maps
+func (r *Cluster) validatePgIdent() field.ErrorList {
+	var result field.ErrorList
+
+	for idx, userMap := range r.Spec.PostgresConfiguration.PgIdent {
+		if err := postgres.ValidateUserMap(userMap); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "pg_ident").Index(idx), userMap, err.Error()))
+		}
+	}
+
+	return result
+}
+
+// validateEphemeralVolumesSizeLimit validates the size limits
+// of the ephemeral volumes
+func (r *Cluster) validateEphemeralVolumesSizeLimit() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "ephemeralVolumesSizeLimit")
+	if limit := r.Spec.EphemeralVolumesSizeLimit.GetShmLimit(); limit != nil && limit.Sign() <= 0 {
+		result = append(result, field.Invalid(basePath.Child("shm"), limit.String(),
+			"the size limit must be greater than zero"))
+	}
+	if limit := r.Spec.EphemeralVolumesSizeLimit.GetTemporaryDataLimit(); limit != nil && limit.Sign() <= 0 {
+		result = append(result, field.Invalid(basePath.Child("temporaryData"), limit.String(),
+			"the size limit must be greater than zero"))
+	}
+
+	return result
+}
+
+// validateHugePages checks that the instances use a single huge page size,
+// which needs to be available to the Jobs creating them too
+func (r *Cluster) validateHugePages() field.ErrorList {
+	var result field.ErrorList
+
+	names := getHugePagesResourceNames(r.Spec.Resources)
+	if len(names) > 1 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "resources"),
+			names,
+			"only one huge page size can be used by the instances"))
+	}
+
+	if r.Spec.JobResources != nil {
+		for _, name := range names {
+			expected := r.Spec.Resources.Limits[name]
+			if actual := r.Spec.JobResources.Limits[name]; actual.Cmp(expected) != 0 {
+				result = append(result, field.Invalid(
+					field.NewPath("spec", "jobResources", "limits", string(name)),
+					actual.String(),
+					fmt.Sprintf("the Jobs creating the instances need the same huge pages limit "+
+						"of the instances, which is %s", expected.String())))
+			}
+		}
+	}
+
+	if value := r.Spec.PostgresConfiguration.Parameters[postgres.HugePages]; value == "on" && len(names) == 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", postgres.HugePages),
+			value,
+			"huge pages need to be requested in the resources of the instances"))
+	}
+
+	return result
+}
+
+// validateExtensions validates the extension images
+func (r *Cluster) validateExtensions() field.ErrorList {
+	extensions := r.Spec.PostgresConfiguration.Extensions
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "extensions")
+
+	if version, err := r.GetPostgresqlVersion(); err == nil && version < 180000 {
+		result = append(result, field.Invalid(basePath, len(extensions),
+			"extension images require PostgreSQL 18 or later"))
+	}
+
+	for _, parameter := range []string{postgres.ExtensionControlPath, postgres.DynamicLibraryPath} {
+		if value, ok := r.Spec.PostgresConfiguration.Parameters[parameter]; ok {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", parameter), value,
+				"the parameter is managed by the operator when extension images are used"))
+		}
+	}
+
+	seenNames := stringset.New()
+	for idx, extension := range extensions {
+		extensionPath := basePath.Index(idx)
+		switch {
+		case extension.Name == "":
+			result = append(result, field.Required(extensionPath.Child("name"), "the extension name is required"))
+		case seenNames.Has(extension.Name):
+			result = append(result, field.Duplicate(extensionPath.Child("name"), extension.Name))
+		}
+		seenNames.Put(extension.Name)
+
+		if extension.Image == "" {
+			result = append(result, field.Required(extensionPath.Child("image"), "the extension image is required"))
+		}
+
+		result = append(result, validateExtensionDirectories(
+			extensionPath.Child("extension_control_path"), extension.ExtensionControlPath)...)
+		result = append(result, validateExtensionDirectories(
+			extensionPath.Child("dynamic_library_path"), extension.DynamicLibraryPath)...)
+	}
+
+	return result
+}
+
+// validateExtensionDirectories checks that the directories of an extension
+// image are relative paths not escaping from the root of the image
+func validateExtensionDirectories(basePath *field.Path, directories []string) field.ErrorList {
+	var result field.ErrorList
+	for idx, directory := range directories {
+		if directory == "" || directory == "." || path.IsAbs(directory) ||
+			path.Clean(directory) != directory || strings.HasPrefix(directory, "..") {
+			result = append(result, field.Invalid(basePath.Index(idx), directory,
+				"the directory must be a relative path inside the extension image"))
+		}
+	}
+
 	return result
 }
 
@@ -405,27 +1020,103 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 				"WAL segment size must be a power of 2"))
 	}
 
-	if initDBOptions.PostInitApplicationSQLRefs != nil {
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.SecretRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "secretRefs"),
-						item,
-						"key and name must be specified"))
-			}
+	basePath := field.NewPath("spec", "bootstrap", "initdb")
+	result = append(result, r.validateInitDBLocaleProvider(basePath)...)
+	result = append(result, validateSQLRefs(basePath.Child("postInitSQLRefs"), initDBOptions.PostInitSQLRefs)...)
+	result = append(result, validateSQLRefs(basePath.Child("postInitTemplateSQLRefs"),
+		initDBOptions.PostInitTemplateSQLRefs)...)
+	result = append(result, validateSQLRefs(basePath.Child("postInitApplicationSQLRefs"),
+		initDBOptions.PostInitApplicationSQLRefs)...)
+
+	return result
+}
+
+// validateInitDBLocaleProvider checks that the ICU and builtin locale
+// options are used together with the matching locale provider, and
+// that the provider is supported by the PostgreSQL version in use
+func (r *Cluster) validateInitDBLocaleProvider(basePath *field.Path) field.ErrorList {
+	var result field.ErrorList
+	initDBOptions := r.Spec.Bootstrap.InitDB
+	provider := initDBOptions.LocaleProvider
+
+	if initDBOptions.IcuLocale != "" && provider != LocaleProviderICU {
+		result = append(result, field.Invalid(
+			basePath.Child("icuLocale"), initDBOptions.IcuLocale,
+			"icuLocale requires the `icu` locale provider"))
+	}
+	if initDBOptions.IcuRules != "" && provider != LocaleProviderICU {
+		result = append(result, field.Invalid(
+			basePath.Child("icuRules"), initDBOptions.IcuRules,
+			"icuRules requires the `icu` locale provider"))
+	}
+	if initDBOptions.BuiltinLocale != "" && provider != LocaleProviderBuiltin {
+		result = append(result, field.Invalid(
+			basePath.Child("builtinLocale"), initDBOptions.BuiltinLocale,
+			"builtinLocale requires the `builtin` locale provider"))
+	}
+
+	switch {
+	case provider == LocaleProviderICU && initDBOptions.IcuLocale == "":
+		result = append(result, field.Required(
+			basePath.Child("icuLocale"), "the `icu` locale provider requires icuLocale"))
+	case provider == LocaleProviderBuiltin && initDBOptions.BuiltinLocale == "":
+		result = append(result, field.Required(
+			basePath.Child("builtinLocale"), "the `builtin` locale provider requires builtinLocale"))
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
+	}
+
+	switch {
+	case provider == LocaleProviderICU && psqlVersion < 150000:
+		result = append(result, field.Invalid(
+			basePath.Child("localeProvider"), provider,
+			"the `icu` locale provider requires PostgreSQL 15 or later"))
+	case provider == LocaleProviderBuiltin && psqlVersion < 170000:
+		result = append(result, field.Invalid(
+			basePath.Child("localeProvider"), provider,
+			"the `builtin` locale provider requires PostgreSQL 17 or later"))
+	}
+	if initDBOptions.IcuRules != "" && psqlVersion < 160000 {
+		result = append(result, field.Invalid(
+			basePath.Child("icuRules"), initDBOptions.IcuRules,
+			"icuRules requires PostgreSQL 16 or later"))
+	}
+
+	return result
+}
+
+// validateSQLRefs validates the references to the Secrets and
+// ConfigMaps containing SQL files
+func validateSQLRefs(basePath *field.Path, refs *PostInitApplicationSQLRefs) field.ErrorList {
+	if refs == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	for _, item := range refs.SecretRefs {
+		if item.Name == "" || item.Key == "" {
+			result = append(
+				result,
+				field.Invalid(
+					basePath.Child("secretRefs"),
+					item,
+					"key and name must be specified"))
 		}
+	}
 
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.ConfigMapRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "configMapRefs"),
-						item,
-						"key and name must be specified"))
-			}
+	for _, item := range refs.ConfigMapRefs {
+		if item.Name == "" || item.Key == "" {
+			result = append(
+				result,
+				field.Invalid(
+					basePath.Child("configMapRefs"),
+					item,
+					"key and name must be specified"))
 		}
 	}
 
@@ -541,9 +1232,19 @@ func (s Import) validateMonolith() field.ErrorList {
 		)
 	}
 
-	return result
-}
-
+	if s.Online != nil {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "online"),
+				s.Online,
+				"online import is not allowed for the `monolith` import type"),
+		)
+	}
+
+	return result
+}
+
 // validateRecovery validate the bootstrapping options when Recovery
 // method is used
 func (r *Cluster) validateRecoveryApplicationDatabase() field.ErrorList {
@@ -582,6 +1283,25 @@ func (r *Cluster) validatePgBaseBackupApplicationDatabase() field.ErrorList {
 		"pg_basebackup")
 }
 
+// validateExistingVolumeApplicationDatabase validate the bootstrapping options
+// when the existingVolume method is used
+func (r *Cluster) validateExistingVolumeApplicationDatabase() field.ErrorList {
+	var result field.ErrorList
+
+	// If it's not configured, everything is ok
+	if r.Spec.Bootstrap == nil {
+		return result
+	}
+
+	if r.Spec.Bootstrap.ExistingVolume == nil {
+		return result
+	}
+
+	existingVolumeOptions := r.Spec.Bootstrap.ExistingVolume
+	return r.validateApplicationDatabase(existingVolumeOptions.Database, existingVolumeOptions.Owner,
+		"existingVolume")
+}
+
 // validateApplicationDatabase validate the configuration for application database
 func (r *Cluster) validateApplicationDatabase(database string, owner string, command string,
 ) field.ErrorList {
@@ -648,6 +1368,19 @@ func (r *Cluster) validateCerts() field.ErrorList {
 				"Client CA secret can't be empty when client replication secret is provided"))
 	}
 
+	if certificates.CertificateDuration != nil || certificates.ExpiringCheckThreshold != nil {
+		validity := r.GetCertificatesValidity()
+		if validity.ExpiringCheckThreshold >= validity.Duration {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "certificates", "expiringCheckThreshold"),
+					validity.ExpiringCheckThreshold.String(),
+					fmt.Sprintf("The expiring check threshold must be shorter than the certificate duration (%s)",
+						validity.Duration.String())))
+		}
+	}
+
 	return result
 }
 
@@ -693,6 +1426,9 @@ func (r *Cluster) validateBootstrapMethod() field.ErrorList {
 	if r.Spec.Bootstrap.PgBaseBackup != nil {
 		bootstrapMethods++
 	}
+	if r.Spec.Bootstrap.ExistingVolume != nil {
+		bootstrapMethods++
+	}
 
 	if bootstrapMethods > 1 {
 		result = append(
@@ -730,24 +1466,87 @@ func (r *Cluster) validateBootstrapPgBaseBackupSource() field.ErrorList {
 	return result
 }
 
+// validateBootstrapExistingVolume is used to ensure that the volume to be
+// adopted is correctly referenced
+func (r *Cluster) validateBootstrapExistingVolume() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.ExistingVolume == nil {
+		return result
+	}
+
+	existingVolume := r.Spec.Bootstrap.ExistingVolume
+	if existingVolume.PersistentVolumeClaimName == "" {
+		result = append(
+			result,
+			field.Required(
+				field.NewPath("spec", "bootstrap", "existingVolume", "persistentVolumeClaimName"),
+				"The name of the PVC to be adopted is required"))
+	} else if expectedName := fmt.Sprintf("%v-1", r.Name); existingVolume.PersistentVolumeClaimName != expectedName {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "existingVolume", "persistentVolumeClaimName"),
+				existingVolume.PersistentVolumeClaimName,
+				fmt.Sprintf("The PVC to be adopted becomes the storage of the first instance "+
+					"and must be named %q", expectedName)))
+	}
+
+	if existingVolume.SubPath != "" {
+		cleanSubPath := path.Clean(existingVolume.SubPath)
+		if path.IsAbs(cleanSubPath) || cleanSubPath == ".." || strings.HasPrefix(cleanSubPath, "../") {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "bootstrap", "existingVolume", "subPath"),
+					existingVolume.SubPath,
+					"The sub path must be a relative path inside the volume"))
+		}
+	}
+
+	return result
+}
+
 // validateBootstrapRecoverySource is used to ensure that the source
 // server is correctly defined
 func (r *Cluster) validateBootstrapRecoverySource() field.ErrorList {
 	var result field.ErrorList
 
 	// This validation is only applicable for recovery based bootstrap
-	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil || r.Spec.Bootstrap.Recovery.Source == "" {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil {
 		return result
 	}
 
-	_, found := r.ExternalCluster(r.Spec.Bootstrap.Recovery.Source)
-	if !found {
+	recovery := r.Spec.Bootstrap.Recovery
+	if recovery.Source == "" {
+		if recovery.Backup == nil {
+			result = append(
+				result,
+				field.Required(
+					field.NewPath("spec", "bootstrap", "recovery"),
+					"The recovery needs either the name of a Backup in the backup section, "+
+						"or the name of an external cluster with a barmanObjectStore section in the source field"))
+		}
+		return result
+	}
+
+	externalCluster, found := r.ExternalCluster(recovery.Source)
+	switch {
+	case !found:
 		result = append(
 			result,
 			field.Invalid(
 				field.NewPath("spec", "bootstrap", "recovery", "source"),
-				r.Spec.Bootstrap.Recovery.Source,
-				fmt.Sprintf("External cluster %v not found", r.Spec.Bootstrap.Recovery.Source)))
+				recovery.Source,
+				fmt.Sprintf("External cluster %v not found: add it to the externalClusters section", recovery.Source)))
+	case recovery.Backup == nil && externalCluster.BarmanObjectStore == nil:
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "recovery", "source"),
+				recovery.Source,
+				fmt.Sprintf("External cluster %v has no barmanObjectStore section, "+
+					"which is needed to recover from its backups", recovery.Source)))
 	}
 
 	return result
@@ -793,6 +1592,43 @@ func (r *Cluster) validateImageName() field.ErrorList {
 	return result
 }
 
+// validateMinorVersionPinning validates the minor version the cluster is
+// pinned to, that must be the one of an explicitly set image
+func (r *Cluster) validateMinorVersionPinning() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.MinorVersionPinning == nil {
+		return result
+	}
+
+	versionPath := field.NewPath("spec", "minorVersionPinning", "version")
+	pinnedVersion, err := postgres.GetPostgresVersionFromTag(r.Spec.MinorVersionPinning.Version)
+	if err != nil {
+		return append(result, field.Invalid(versionPath, r.Spec.MinorVersionPinning.Version, err.Error()))
+	}
+
+	if r.Spec.ImageName == "" {
+		return append(result, field.Required(
+			field.NewPath("spec", "imageName"),
+			"the image must be set explicitly to pin the minor version"))
+	}
+
+	imageVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The image name is validated elsewhere
+		return result
+	}
+
+	if imageVersion != pinnedVersion {
+		result = append(result, field.Invalid(
+			versionPath,
+			r.Spec.MinorVersionPinning.Version,
+			fmt.Sprintf("the pinned version doesn't match the tag of the image %s", r.Spec.ImageName)))
+	}
+
+	return result
+}
+
 // validateImagePullPolicy validates the image pull policy,
 // ensuring it is one of "Always", "Never" or "IfNotPresent" when defined
 func (r *Cluster) validateImagePullPolicy() field.ErrorList {
@@ -840,6 +1676,16 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 					field.NewPath("spec", "postgresql", "parameters", key),
 					value,
 					"Can't set fixed configuration parameter"))
+			continue
+		}
+
+		if err := postgres.ValidateParameter(key, value, psqlVersion); err != nil {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "postgresql", "parameters", key),
+					value,
+					err.Error()))
 		}
 	}
 
@@ -857,6 +1703,24 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 func (r *Cluster) validateConfigurationChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
 
+	var restartParameters []string
+	for key, value := range r.Spec.PostgresConfiguration.Parameters {
+		if old.Spec.PostgresConfiguration.Parameters[key] != value && postgres.IsParameterRequiringRestart(key) {
+			restartParameters = append(restartParameters, key)
+		}
+	}
+	for key := range old.Spec.PostgresConfiguration.Parameters {
+		_, isPresent := r.Spec.PostgresConfiguration.Parameters[key]
+		if !isPresent && postgres.IsParameterRequiringRestart(key) {
+			restartParameters = append(restartParameters, key)
+		}
+	}
+	if len(restartParameters) > 0 {
+		sort.Strings(restartParameters)
+		clusterLog.Info("The configuration change requires a restart of the instances",
+			"name", r.Name, "namespace", r.Namespace, "parameters", restartParameters)
+	}
+
 	if old.Spec.ImageName != r.Spec.ImageName {
 		diff := utils.CollectDifferencesFromMaps(old.Spec.PostgresConfiguration.Parameters,
 			r.Spec.PostgresConfiguration.Parameters)
@@ -1063,7 +1927,9 @@ func (r *Cluster) validateMaxSyncReplicas() field.ErrorList {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "maxSyncReplicas"),
 			r.Spec.MaxSyncReplicas,
-			"maxSyncReplicas must be lower than the number of instances"))
+			fmt.Sprintf("maxSyncReplicas must be lower than the number of instances (%d), "+
+				"as the primary can't be a synchronous replica: "+
+				"lower maxSyncReplicas or increase the instances", r.Spec.Instances)))
 	}
 
 	return result
@@ -1084,7 +1950,22 @@ func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "minSyncReplicas"),
 			r.Spec.MinSyncReplicas,
-			"minSyncReplicas cannot be greater than maxSyncReplicas"))
+			fmt.Sprintf("minSyncReplicas cannot be greater than maxSyncReplicas (%d): "+
+				"lower minSyncReplicas or increase maxSyncReplicas", r.Spec.MaxSyncReplicas)))
+	}
+
+	return result
+}
+
+// validateProfile validates the constraints of the profile of the cluster
+func (r *Cluster) validateProfile() field.ErrorList {
+	var result field.ErrorList
+
+	if r.IsDevelopmentProfile() && r.Spec.Instances > 1 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "instances"),
+			r.Spec.Instances,
+			"development clusters have a single instance"))
 	}
 
 	return result
@@ -1100,6 +1981,73 @@ func (r *Cluster) validateWalStorageSize() field.ErrorList {
 	return result
 }
 
+// validateWalStorageCapacity checks that the WAL volume can contain
+// the WAL files which PostgreSQL keeps between checkpoints
+func (r *Cluster) validateWalStorageCapacity() field.ErrorList {
+	var result field.ErrorList
+
+	maxWalSize, ok := r.Spec.PostgresConfiguration.Parameters["max_wal_size"]
+	if !ok || !r.ShouldCreateWalArchiveVolume() {
+		return result
+	}
+
+	walStorageSize, err := resource.ParseQuantity(r.Spec.WalStorage.Size)
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateWalStorageSize function
+		return result
+	}
+
+	maxWalSizeBytes, err := postgres.ParseMemoryParameter("max_wal_size", maxWalSize)
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateConfiguration function
+		return result
+	}
+
+	if walStorageSize.Value() < maxWalSizeBytes {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "walStorage", "size"),
+			r.Spec.WalStorage.Size,
+			fmt.Sprintf("the WAL storage is smaller than max_wal_size (%s), so it would fill up "+
+				"before a checkpoint: increase the size of the WAL storage or lower max_wal_size",
+				maxWalSize)))
+	}
+
+	return result
+}
+
+// validateSharedBuffers checks that the shared buffers fit in the
+// memory limit of the instances. Huge pages are not accounted in the
+// memory limit, so the check is skipped when they are requested
+func (r *Cluster) validateSharedBuffers() field.ErrorList {
+	var result field.ErrorList
+
+	sharedBuffers, ok := r.Spec.PostgresConfiguration.Parameters["shared_buffers"]
+	memoryLimit := r.Spec.Resources.Limits.Memory()
+	if !ok || memoryLimit.IsZero() || len(getHugePagesResourceNames(r.Spec.Resources)) > 0 {
+		return result
+	}
+
+	sharedBuffersBytes, err := postgres.ParseMemoryParameter("shared_buffers", sharedBuffers)
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateConfiguration function
+		return result
+	}
+
+	if sharedBuffersBytes >= memoryLimit.Value() {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", "shared_buffers"),
+			sharedBuffers,
+			fmt.Sprintf("shared_buffers must be lower than the memory limit of the instances (%s), "+
+				"or PostgreSQL would be killed when using them: lower shared_buffers or "+
+				"increase the memory limit", memoryLimit.String())))
+	}
+
+	return result
+}
+
 func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
 	var result field.ErrorList
 
@@ -1561,6 +2509,614 @@ func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
 	return errs
 }
 
+// validateCascadingReplication checks that every standby is assigned
+// at most one upstream, which must be another instance
+func (r *Cluster) validateCascadingReplication() field.ErrorList {
+	if r.Spec.Replication == nil || r.Spec.Replication.Cascading == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "replication", "cascading", "instances")
+	assignedInstances := stringset.New()
+	for idx, rule := range r.Spec.Replication.Cascading.Instances {
+		if assignedInstances.Has(rule.Name) {
+			result = append(result, field.Duplicate(
+				basePath.Index(idx).Child("name"),
+				rule.Name))
+		}
+		assignedInstances.Put(rule.Name)
+
+		if rule.Name == rule.Upstream {
+			result = append(result, field.Invalid(
+				basePath.Index(idx).Child("upstream"),
+				rule.Upstream,
+				"an instance cannot stream from itself"))
+		}
+	}
+
+	return result
+}
+
+// validateCloneThrottling validates the throttling of the clones run by
+// the new replicas, checking the transfer rate is in the range accepted
+// by pg_basebackup
+func (r *Cluster) validateCloneThrottling() field.ErrorList {
+	throttling := r.Spec.Replication.GetCloneThrottling()
+	if throttling == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "replication", "cloneThrottling")
+	if throttling.MaxRate != "" {
+		rate, err := parseCloneMaxRate(throttling.MaxRate)
+		if err != nil {
+			result = append(result, field.Invalid(basePath.Child("maxRate"), throttling.MaxRate, err.Error()))
+		} else if rate < 32 || rate > 1024*1024 {
+			result = append(result, field.Invalid(
+				basePath.Child("maxRate"),
+				throttling.MaxRate,
+				"the transfer rate must be between 32 kB/s and 1024 MB/s"))
+		}
+	}
+
+	if throttling.IOPriority != nil && throttling.IOClass == IOSchedulingClassIdle {
+		result = append(result, field.Invalid(
+			basePath.Child("ioPriority"),
+			*throttling.IOPriority,
+			"the I/O priority can't be set with the idle scheduling class"))
+	}
+
+	return result
+}
+
+// validateCloneMethod checks that the volumes of the standbys can be cloned
+// consistently, which is not possible when the WALs are stored in a
+// separate volume
+func (r *Cluster) validateCloneMethod() field.ErrorList {
+	if !r.Spec.Replication.IsVolumeCloneEnabled() || r.Spec.WalStorage == nil {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(
+		field.NewPath("spec", "replication", "cloneMethod"),
+		r.Spec.Replication.CloneMethod,
+		"the volumes can't be cloned when the WALs are stored in a separate volume")}
+}
+
+// parseCloneMaxRate parses a pg_basebackup transfer rate, returning it
+// in kilobytes per second
+func parseCloneMaxRate(maxRate string) (int64, error) {
+	multiplier := int64(1)
+	value := maxRate
+	switch {
+	case strings.HasSuffix(maxRate, "k"):
+		value = strings.TrimSuffix(maxRate, "k")
+	case strings.HasSuffix(maxRate, "M"):
+		value = strings.TrimSuffix(maxRate, "M")
+		multiplier = 1024
+	}
+
+	rate, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transfer rate: %w", err)
+	}
+
+	return rate * multiplier, nil
+}
+
+// validateMaxStandbyLag checks that the maximum replay lag of a ready standby is positive
+func (r *Cluster) validateMaxStandbyLag() field.ErrorList {
+	if r.Spec.Replication == nil || r.Spec.Replication.MaxStandbyLag == nil {
+		return nil
+	}
+
+	if r.Spec.Replication.MaxStandbyLag.Sign() <= 0 {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "replication", "maxStandbyLag"),
+			r.Spec.Replication.MaxStandbyLag.String(),
+			"the maximum standby lag must be greater than zero")}
+	}
+
+	return nil
+}
+
+// validateSmartShutdownTimeout checks that the smart shutdown leaves
+// some of the stop delay to the fast shutdown
+func (r *Cluster) validateSmartShutdownTimeout() field.ErrorList {
+	if r.Spec.SmartShutdownTimeout == 0 {
+		return nil
+	}
+
+	if r.Spec.SmartShutdownTimeout >= r.GetMaxStopDelay() {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "smartShutdownTimeout"),
+			r.Spec.SmartShutdownTimeout,
+			fmt.Sprintf("the smart shutdown timeout must be lower than the stop delay (%d seconds), "+
+				"leaving time for the fast shutdown", r.GetMaxStopDelay()))}
+	}
+
+	return nil
+}
+
+// validatePostgresTLS checks that the TLS settings are supported by the
+// PostgreSQL version in use
+func (r *Cluster) validatePostgresTLS() field.ErrorList {
+	tls := r.Spec.PostgresConfiguration.TLS
+	if tls == nil || tls.MinProtocolVersion == "" {
+		return nil
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return nil
+	}
+
+	if psqlVersion < 120000 {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "postgresql", "tls", "minProtocolVersion"),
+			tls.MinProtocolVersion,
+			"the minimum TLS protocol version requires PostgreSQL 12 or later")}
+	}
+
+	return nil
+}
+
+// validateSCRAMMigration checks that the password hashing method is not
+// set to a different value while migrating to SCRAM-SHA-256
+func (r *Cluster) validateSCRAMMigration() field.ErrorList {
+	if r.Spec.PostgresConfiguration.SCRAMMigration == nil {
+		return nil
+	}
+
+	value, ok := r.Spec.PostgresConfiguration.Parameters[postgres.PasswordEncryption]
+	if !ok || value == postgres.PasswordEncryptionSCRAM {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(
+		field.NewPath("spec", "postgresql", "parameters", postgres.PasswordEncryption),
+		value,
+		"conflicts with the SCRAM migration, please remove it")}
+}
+
+// validateWALCompression checks that the WAL compression method is supported
+// by the PostgreSQL version in use and is not specified twice
+func (r *Cluster) validateWALCompression() field.ErrorList {
+	method := r.Spec.Replication.GetWALCompression()
+	if method == "" {
+		return nil
+	}
+
+	var result field.ErrorList
+	fieldPath := field.NewPath("spec", "replication", "walCompression")
+	if _, ok := r.Spec.PostgresConfiguration.Parameters[postgres.WALCompression]; ok {
+		result = append(result, field.Invalid(
+			fieldPath,
+			method,
+			fmt.Sprintf("conflicts with the %s parameter, please remove it", postgres.WALCompression)))
+	}
+
+	if method != WALCompressionLz4 && method != WALCompressionZstd {
+		return result
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
+	}
+	if psqlVersion < 150000 {
+		result = append(result, field.Invalid(
+			fieldPath,
+			method,
+			"this compression method requires PostgreSQL 15 or later"))
+	}
+
+	return result
+}
+
+// reservedLogFields are the keys used by the instance manager
+// in the log records, that cannot be overridden by static fields
+var reservedLogFields = []string{
+	"level", "ts", "logger", "msg", "error", "caller", "stacktrace",
+	"logging_pod", "logging_cluster", "record",
+}
+
+// validateLogging checks that the static fields don't overwrite the ones
+// set by the instance manager and that the logger levels are valid
+func (r *Cluster) validateLogging() field.ErrorList {
+	if r.Spec.Logging == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "logging")
+	for key := range r.Spec.Logging.Fields {
+		if slices.Contains(reservedLogFields, key) {
+			result = append(result, field.Invalid(
+				basePath.Child("fields").Key(key),
+				key,
+				"this field is reserved for the instance manager"))
+		}
+	}
+
+	for name, level := range r.Spec.Logging.Loggers {
+		if !log.IsValidLevel(level) {
+			result = append(result, field.NotSupported(
+				basePath.Child("loggers").Key(name),
+				level,
+				[]string{
+					log.ErrorLevelString,
+					log.WarningLevelString,
+					log.InfoLevelString,
+					log.DebugLevelString,
+					log.TraceLevelString,
+				}))
+		}
+	}
+
+	return result
+}
+
+// validateDeletionPolicy checks that the steps explicitly
+// requested in the deletion policy can be executed
+func (r *Cluster) validateDeletionPolicy() field.ErrorList {
+	deletionPolicy := r.Spec.DeletionPolicy
+	if deletionPolicy == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "deletionPolicy")
+	if deletionPolicy.FinalBackup != nil && deletionPolicy.FinalBackup.Timeout != 0 &&
+		time.Duration(deletionPolicy.FinalBackup.Timeout)*time.Second > deletionPolicy.GetTimeout() {
+		result = append(result, field.Invalid(
+			basePath.Child("finalBackup", "timeout"),
+			deletionPolicy.FinalBackup.Timeout,
+			"the final backup timeout can't exceed the timeout of the deletion policy"))
+	}
+
+	if r.Spec.Backup.IsBarmanBackupConfigured() {
+		return result
+	}
+
+	if deletionPolicy.FinalBackup != nil && deletionPolicy.FinalBackup.Enabled != nil &&
+		*deletionPolicy.FinalBackup.Enabled {
+		result = append(result, field.Invalid(
+			basePath.Child("finalBackup", "enabled"),
+			true,
+			"a final backup requires a Barman object store to be configured"))
+	}
+
+	if deletionPolicy.ArchiveWAL != nil && *deletionPolicy.ArchiveWAL {
+		result = append(result, field.Invalid(
+			basePath.Child("archiveWAL"),
+			true,
+			"archiving the WAL files requires a Barman object store to be configured"))
+	}
+
+	return result
+}
+
+var (
+	// reservedPodTemplateEnvVars are the environment variables
+	// set by the operator in the PostgreSQL container
+	reservedPodTemplateEnvVars = []string{
+		"PGDATA", "POD_NAME", "NAMESPACE", "CLUSTER_NAME", "PGPORT", "PGHOST",
+		"AWS_CA_BUNDLE", "REQUESTS_CA_BUNDLE", "LDAPTLS_CACERT",
+	}
+
+	// reservedPodTemplateVolumes are the names of the volumes
+	// defined by the operator in the instance Pods
+	reservedPodTemplateVolumes = []string{
+		"pgdata", "pg-wal", "scratch-data", "shm", "hugepages", "superuser-secret", "app-secret",
+		"barman-endpoint-ca", "projected", "ldap-server-ca", "logs",
+	}
+
+	// reservedPodTemplateMountPaths are the directories used by the operator
+	// in the PostgreSQL container, which can't be shadowed by other volumes
+	reservedPodTemplateMountPaths = []string{
+		"/var/lib/postgresql", "/run", "/controller", "/dev/shm", "/dev/hugepages", "/etc/superuser-secret",
+		"/etc/app-secret", "/projected", "/etc/ldap-server-ca", "/logs",
+	}
+
+	// reservedPodTemplateContainers are the names of the containers
+	// defined by the operator in the instance Pods
+	reservedPodTemplateContainers = []string{"postgres", "bootstrap-controller"}
+
+	// reservedPodTemplateLabels are the labels used by the operator to
+	// select the instance Pods, beside the ones in its own namespace
+	reservedPodTemplateLabels = []string{"role", "postgresql"}
+)
+
+// isReservedPodTemplateMetadata checks if the passed label
+// or annotation is managed by the operator
+func isReservedPodTemplateMetadata(key string) bool {
+	return strings.HasPrefix(key, "cnpg.io/") || strings.HasPrefix(key, "kubectl.kubernetes.io/")
+}
+
+// validatePodTemplate checks that the customizations of the instance
+// Pods don't interfere with the resources managed by the operator
+func (r *Cluster) validatePodTemplate() field.ErrorList {
+	podTemplate := r.Spec.PodTemplate
+	if podTemplate == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "podTemplate")
+
+	for key := range podTemplate.Metadata.Labels {
+		if isReservedPodTemplateMetadata(key) || slices.Contains(reservedPodTemplateLabels, key) {
+			result = append(result, field.Invalid(
+				basePath.Child("metadata", "labels").Key(key), key, "this label is managed by the operator"))
+		}
+	}
+	result = append(result,
+		validation.ValidateLabels(podTemplate.Metadata.Labels, basePath.Child("metadata", "labels"))...)
+
+	for key := range podTemplate.Metadata.Annotations {
+		if isReservedPodTemplateMetadata(key) {
+			result = append(result, field.Invalid(
+				basePath.Child("metadata", "annotations").Key(key), key, "this annotation is managed by the operator"))
+		}
+	}
+
+	for idx, env := range podTemplate.Env {
+		if slices.Contains(reservedPodTemplateEnvVars, env.Name) {
+			result = append(result, field.Invalid(
+				basePath.Child("env").Index(idx).Child("name"),
+				env.Name,
+				"this environment variable is set by the operator"))
+		}
+	}
+
+	volumeNames := stringset.New()
+	for idx, volume := range podTemplate.ProjectedVolumes {
+		result = append(result, validatePodTemplateVolume(
+			basePath.Child("projectedVolumes").Index(idx), volume.Name, volume.MountPath, volumeNames)...)
+	}
+	for idx, volume := range podTemplate.CSIVolumes {
+		result = append(result, validatePodTemplateVolume(
+			basePath.Child("csiVolumes").Index(idx), volume.Name, volume.MountPath, volumeNames)...)
+	}
+
+	containerNames := stringset.New()
+	for idx := range podTemplate.Sidecars {
+		result = append(result, validatePodTemplateSidecar(
+			basePath.Child("sidecars").Index(idx), &podTemplate.Sidecars[idx], containerNames, volumeNames)...)
+	}
+
+	return result
+}
+
+// validatePodTemplateSidecar checks that a sidecar container of the Pod
+// template has a unique name and only mounts the volumes of the template
+func validatePodTemplateSidecar(
+	containerPath *field.Path,
+	sidecar *v1.Container,
+	containerNames *stringset.Data,
+	volumeNames *stringset.Data,
+) field.ErrorList {
+	var result field.ErrorList
+
+	switch {
+	case slices.Contains(reservedPodTemplateContainers, sidecar.Name) ||
+		strings.HasPrefix(sidecar.Name, "extension-"):
+		result = append(result, field.Invalid(
+			containerPath.Child("name"), sidecar.Name, "this container name is used by the operator"))
+	case containerNames.Has(sidecar.Name):
+		result = append(result, field.Duplicate(containerPath.Child("name"), sidecar.Name))
+	}
+	containerNames.Put(sidecar.Name)
+
+	for _, msg := range validationutil.IsDNS1123Label(sidecar.Name) {
+		result = append(result, field.Invalid(containerPath.Child("name"), sidecar.Name, msg))
+	}
+
+	if sidecar.Image == "" {
+		result = append(result, field.Required(containerPath.Child("image"), "the image of the sidecar is required"))
+	}
+
+	for idx, volumeMount := range sidecar.VolumeMounts {
+		volumeMountPath := containerPath.Child("volumeMounts").Index(idx)
+		if !volumeNames.Has(volumeMount.Name) {
+			result = append(result, field.Invalid(
+				volumeMountPath.Child("name"),
+				volumeMount.Name,
+				"only the projected and CSI volumes of the Pod template can be mounted"))
+		}
+
+		mountPath := path.Clean(volumeMount.MountPath)
+		if isSameOrSubPath(mountPath, "/logs") || isSameOrSubPath("/logs", mountPath) {
+			result = append(result, field.Invalid(
+				volumeMountPath.Child("mountPath"),
+				volumeMount.MountPath,
+				"the mount path overlaps with /logs, where the log stream is copied"))
+		}
+	}
+
+	return result
+}
+
+// validatePodTemplateVolume checks that a volume of the Pod template has
+// a unique name and doesn't shadow the directories used by the operator
+func validatePodTemplateVolume(
+	volumePath *field.Path,
+	name string,
+	volumeMountPath string,
+	volumeNames *stringset.Data,
+) field.ErrorList {
+	var result field.ErrorList
+
+	switch {
+	case slices.Contains(reservedPodTemplateVolumes, name) ||
+		strings.HasSuffix(name, "-post-init-application-sql"):
+		result = append(result, field.Invalid(
+			volumePath.Child("name"), name, "this volume name is used by the operator"))
+	case volumeNames.Has(name):
+		result = append(result, field.Duplicate(volumePath.Child("name"), name))
+	}
+	volumeNames.Put(name)
+
+	for _, msg := range validationutil.IsDNS1123Label(name) {
+		result = append(result, field.Invalid(volumePath.Child("name"), name, msg))
+	}
+
+	if !path.IsAbs(volumeMountPath) {
+		return append(result, field.Invalid(
+			volumePath.Child("mountPath"), volumeMountPath, "the mount path must be absolute"))
+	}
+
+	mountPath := path.Clean(volumeMountPath)
+	for _, reservedPath := range reservedPodTemplateMountPaths {
+		if isSameOrSubPath(mountPath, reservedPath) || isSameOrSubPath(reservedPath, mountPath) {
+			result = append(result, field.Invalid(
+				volumePath.Child("mountPath"),
+				volumeMountPath,
+				fmt.Sprintf("the mount path overlaps with %s, which is used by the operator", reservedPath)))
+			break
+		}
+	}
+
+	return result
+}
+
+// validateCredentialsFiles checks the paths of the files containing
+// the passwords provided by an external secret store
+func (r *Cluster) validateCredentialsFiles() field.ErrorList {
+	credentialsFiles := r.Spec.CredentialsFiles
+	if credentialsFiles == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "credentialsFiles")
+
+	if credentialsFiles.SuperuserPasswordFile != "" && !path.IsAbs(credentialsFiles.SuperuserPasswordFile) {
+		result = append(result, field.Invalid(
+			basePath.Child("superuserPasswordFile"),
+			credentialsFiles.SuperuserPasswordFile,
+			"the path must be absolute"))
+	}
+
+	if credentialsFiles.ApplicationPasswordFile != "" && !path.IsAbs(credentialsFiles.ApplicationPasswordFile) {
+		result = append(result, field.Invalid(
+			basePath.Child("applicationPasswordFile"),
+			credentialsFiles.ApplicationPasswordFile,
+			"the path must be absolute"))
+	}
+
+	return result
+}
+
+// validateNetworkPolicy checks that the allowed namespaces
+// of the NetworkPolicy are valid namespace names
+func (r *Cluster) validateNetworkPolicy() field.ErrorList {
+	if r.Spec.NetworkPolicy == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "networkPolicy", "allowedNamespaces")
+
+	for idx, namespace := range r.Spec.NetworkPolicy.AllowedNamespaces {
+		for _, msg := range validationutil.IsDNS1123Label(namespace) {
+			result = append(result, field.Invalid(basePath.Index(idx), namespace, msg))
+		}
+	}
+
+	return result
+}
+
+// validateInstanceGroups checks that the instance groups have unique
+// names, fit in the requested instances and leave at least one instance
+// that can be promoted to primary
+func (r *Cluster) validateInstanceGroups() field.ErrorList {
+	if len(r.Spec.InstanceGroups) == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "instanceGroups")
+
+	seenNames := stringset.New()
+	groupedInstances := 0
+	excludedInstances := 0
+	for idx, group := range r.Spec.InstanceGroups {
+		groupPath := basePath.Index(idx)
+		switch {
+		case group.Name == "":
+			result = append(result, field.Required(groupPath.Child("name"), "the group name is required"))
+		case seenNames.Has(group.Name):
+			result = append(result, field.Duplicate(groupPath.Child("name"), group.Name))
+		default:
+			for _, msg := range validationutil.IsDNS1123Label(group.Name) {
+				result = append(result, field.Invalid(groupPath.Child("name"), group.Name, msg))
+			}
+		}
+		seenNames.Put(group.Name)
+
+		if group.Instances < 1 {
+			result = append(result, field.Invalid(groupPath.Child("instances"), group.Instances,
+				"the group must contain at least one instance"))
+		}
+		groupedInstances += group.Instances
+		if group.ExcludedFromFailover {
+			excludedInstances += group.Instances
+		}
+	}
+
+	if groupedInstances > r.Spec.Instances {
+		result = append(result, field.Invalid(basePath, groupedInstances,
+			fmt.Sprintf("the instance groups contain more instances than the cluster (%d)", r.Spec.Instances)))
+	}
+
+	if excludedInstances >= r.Spec.Instances {
+		result = append(result, field.Invalid(basePath, excludedInstances,
+			"at least one instance must be eligible for the promotion to primary"))
+	}
+
+	return result
+}
+
+// validateInstanceOverrides checks that the annotation overriding the
+// settings of single instances can be parsed
+func (r *Cluster) validateInstanceOverrides() field.ErrorList {
+	content, ok := r.Annotations[utils.InstanceOverridesAnnotationName]
+	if !ok {
+		return nil
+	}
+
+	annotationPath := field.NewPath("metadata", "annotations").Key(utils.InstanceOverridesAnnotationName)
+	overrides, err := r.GetInstanceOverrides()
+	if err != nil {
+		return field.ErrorList{field.Invalid(annotationPath, content,
+			fmt.Sprintf("the instance overrides can't be parsed: %v", err))}
+	}
+
+	var result field.ErrorList
+	for serial := range overrides {
+		if serial < 1 {
+			result = append(result, field.Invalid(annotationPath, content,
+				fmt.Sprintf("invalid instance serial: %d", serial)))
+		}
+	}
+
+	return result
+}
+
+// isSameOrSubPath checks if the passed cleaned path
+// is equal to the other one or is contained in it
+func isSameOrSubPath(subPath, parentPath string) bool {
+	return subPath == parentPath || strings.HasPrefix(subPath, strings.TrimSuffix(parentPath, "/")+"/")
+}
+
 // validateAzureCredentials checks and validates the azure credentials
 func (azure *AzureCredentials) validateAzureCredentials(path *field.Path) field.ErrorList {
 	allErrors := field.ErrorList{}
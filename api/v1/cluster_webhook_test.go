@@ -18,12 +18,16 @@ package v1
 
 import (
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -225,6 +229,20 @@ var _ = Describe("certificates options validation", func() {
 		result := cluster.validateCerts()
 		Expect(len(result)).To(Equal(1))
 	})
+
+	It("complains if the certificates would be renewed as soon as they are generated", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					CertificateDuration: pointer.Int(5),
+				},
+			},
+		}
+		Expect(cluster.validateCerts()).To(HaveLen(1))
+
+		cluster.Spec.Certificates.ExpiringCheckThreshold = pointer.Int(2)
+		Expect(cluster.validateCerts()).To(BeEmpty())
+	})
 })
 
 var _ = Describe("initdb options validation", func() {
@@ -280,6 +298,86 @@ var _ = Describe("initdb options validation", func() {
 		Expect(result).To(BeEmpty())
 	})
 
+	It("accepts the ICU locale provider with its locale", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database:       "app",
+						Owner:          "app",
+						LocaleProvider: LocaleProviderICU,
+						IcuLocale:      "en-US",
+						IcuRules:       "&V << w <<< W",
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateInitDB()).To(BeEmpty())
+	})
+
+	It("complains if the ICU locale is used without the ICU locale provider", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database:  "app",
+						Owner:     "app",
+						IcuLocale: "en-US",
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
+
+	It("complains if the locale of the chosen provider is missing", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:17",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database:       "app",
+						Owner:          "app",
+						LocaleProvider: LocaleProviderBuiltin,
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
+
+	It("complains if the PostgreSQL version doesn't support the locale options", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database:       "app",
+						Owner:          "app",
+						LocaleProvider: LocaleProviderICU,
+						IcuLocale:      "en-US",
+						IcuRules:       "&V << w <<< W",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+
+		cluster.Spec.ImageName = "postgres:16"
+		cluster.Spec.Bootstrap.InitDB = &BootstrapInitDB{
+			Database:       "app",
+			Owner:          "app",
+			LocaleProvider: LocaleProviderBuiltin,
+			BuiltinLocale:  "C.UTF-8",
+		}
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
+
 	It("complain if key is missing in the secretRefs", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
@@ -410,6 +508,38 @@ var _ = Describe("initdb options validation", func() {
 		Expect(result).To(BeEmpty())
 	})
 
+	It("complain about invalid post init and post init template SQL refs", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						PostInitSQLRefs: &PostInitApplicationSQLRefs{
+							SecretRefs: []SecretKeySelector{
+								{
+									LocalObjectReference: LocalObjectReference{Name: "secret1"},
+								},
+							},
+						},
+						PostInitTemplateSQLRefs: &PostInitApplicationSQLRefs{
+							ConfigMapRefs: []ConfigMapKeySelector{
+								{
+									Key: "key",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.postInitSQLRefs.secretRefs"))
+		Expect(result[1].Field).To(Equal("spec.bootstrap.initdb.postInitTemplateSQLRefs.configMapRefs"))
+	})
+
 	It("doesn't complain if superuser secret it's empty", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{},
@@ -517,6 +647,24 @@ var _ = Describe("cluster configuration", func() {
 		Expect(cluster.Spec.Bootstrap.PgBaseBackup.Owner).To(Equal("appdb"))
 	})
 
+	It("defaults the owner user with the database name for existingVolume", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					ExistingVolume: &BootstrapExistingVolume{
+						PersistentVolumeClaimName: "legacy",
+						Database:                  "appdb",
+					},
+				},
+			},
+		}
+
+		cluster.Default()
+		Expect(cluster.Spec.Bootstrap.ExistingVolume.Owner).To(Equal("appdb"))
+		Expect(cluster.ShouldExistingVolumeCreateApplicationDatabase()).To(BeTrue())
+		Expect(cluster.ShouldExistingVolumeCreateApplicationSecret()).To(BeTrue())
+	})
+
 	It("defaults the PostgreSQL configuration with parameters from the operator", func() {
 		cluster := Cluster{}
 		cluster.Default()
@@ -612,6 +760,27 @@ var _ = Describe("Defaulting webhook", func() {
 		Expect(cluster.Spec.Bootstrap.InitDB.Database).To(Equal("testdb"))
 		Expect(cluster.Spec.Bootstrap.InitDB.Owner).To(Equal("testuser"))
 	})
+
+	It("should set a minimal storage size for development clusters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Profile: ClusterProfileDevelopment,
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.StorageConfiguration.Size).To(Equal(DevelopmentStorageSize))
+
+		cluster = Cluster{
+			Spec: ClusterSpec{
+				Profile: ClusterProfileDevelopment,
+				StorageConfiguration: StorageConfiguration{
+					Size: "10Gi",
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.StorageConfiguration.Size).To(Equal("10Gi"))
+	})
 })
 
 var _ = Describe("Image name validation", func() {
@@ -756,6 +925,41 @@ var _ = Describe("configuration change validation", func() {
 	})
 })
 
+var _ = Describe("configuration parameters validation", func() {
+	It("accepts valid parameters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.5",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"shared_buffers": "1GB",
+						"log_statement":  "ddl",
+						"wal_keep_size":  "512MB",
+						"pgaudit.log":    "all",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+	})
+
+	It("complains about wrong values and parameters not supported by the version", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:12.10",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"shared_buffers": "4G",
+						"log_statement":  "everything",
+						"wal_keep_size":  "512MB",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(HaveLen(3))
+	})
+})
+
 var _ = Describe("validate image name change", func() {
 	It("doesn't complain with no changes", func() {
 		clusterNew := Cluster{
@@ -1169,6 +1373,37 @@ var _ = Describe("Number of synchronous replicas", func() {
 	})
 })
 
+var _ = Describe("cluster profile validation", func() {
+	It("allows a single instance in development clusters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Profile:   ClusterProfileDevelopment,
+				Instances: 1,
+			},
+		}
+		Expect(cluster.validateProfile()).To(BeEmpty())
+	})
+
+	It("complains if development clusters have more than one instance", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Profile:   ClusterProfileDevelopment,
+				Instances: 3,
+			},
+		}
+		Expect(cluster.validateProfile()).To(HaveLen(1))
+	})
+
+	It("doesn't limit the instances of production clusters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+			},
+		}
+		Expect(cluster.validateProfile()).To(BeEmpty())
+	})
+})
+
 var _ = Describe("storage configuration validation", func() {
 	It("complains if the size is being reduced", func() {
 		clusterOld := Cluster{
@@ -1440,6 +1675,112 @@ var _ = Describe("bootstrap base backup validation", func() {
 	})
 })
 
+var _ = Describe("bootstrap existing volume validation", func() {
+	It("complains if you specify the database name but not the owner", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					ExistingVolume: &BootstrapExistingVolume{
+						PersistentVolumeClaimName: "legacy",
+						Database:                  "app",
+					},
+				},
+			},
+		}
+
+		result := cluster.validateExistingVolumeApplicationDatabase()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("doesn't complain if you specify both database name and owner user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					ExistingVolume: &BootstrapExistingVolume{
+						PersistentVolumeClaimName: "legacy",
+						Database:                  "app",
+						Owner:                     "app",
+					},
+				},
+			},
+		}
+
+		result := cluster.validateExistingVolumeApplicationDatabase()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains if the name of the PVC is missing", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					ExistingVolume: &BootstrapExistingVolume{},
+				},
+			},
+		}
+
+		result := cluster.validateBootstrapExistingVolume()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("complains if the PVC is not named after the first instance", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "adopted",
+			},
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					ExistingVolume: &BootstrapExistingVolume{
+						PersistentVolumeClaimName: "legacy",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBootstrapExistingVolume()).To(HaveLen(1))
+
+		cluster.Spec.Bootstrap.ExistingVolume.PersistentVolumeClaimName = "adopted-1"
+		Expect(cluster.validateBootstrapExistingVolume()).To(BeEmpty())
+	})
+
+	It("complains if the sub path points outside of the volume", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "legacy",
+			},
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					ExistingVolume: &BootstrapExistingVolume{
+						PersistentVolumeClaimName: "legacy-1",
+						SubPath:                   "data/../../etc",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBootstrapExistingVolume()).To(HaveLen(1))
+
+		cluster.Spec.Bootstrap.ExistingVolume.SubPath = "/var/lib/pgsql"
+		Expect(cluster.validateBootstrapExistingVolume()).To(HaveLen(1))
+
+		cluster.Spec.Bootstrap.ExistingVolume.SubPath = "pgsql/14/data"
+		Expect(cluster.validateBootstrapExistingVolume()).To(BeEmpty())
+	})
+
+	It("complains if it is used together with another bootstrap method", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
+					ExistingVolume: &BootstrapExistingVolume{
+						PersistentVolumeClaimName: "legacy",
+					},
+				},
+			},
+		}
+
+		result := cluster.validateBootstrapMethod()
+		Expect(result).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("bootstrap recovery validation", func() {
 	It("complains if you specify the database name but not the owner for recovery", func() {
 		cluster := Cluster{
@@ -1498,6 +1839,9 @@ var _ = Describe("bootstrap recovery validation", func() {
 				ExternalClusters: []ExternalCluster{
 					{
 						Name: "test",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://backups/",
+						},
 					},
 				},
 			},
@@ -1506,6 +1850,39 @@ var _ = Describe("bootstrap recovery validation", func() {
 		Expect(errorsList).To(BeEmpty())
 	})
 
+	It("complains when the external cluster used for the recovery has no object store", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).To(HaveLen(1))
+		Expect(errorsList[0].Detail).To(ContainSubstring("barmanObjectStore"))
+	})
+
+	It("complains when the recovery has neither a backup nor a source", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{},
+				},
+			},
+		}
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).To(HaveLen(1))
+		Expect(errorsList[0].Type).To(Equal(field.ErrorTypeRequired))
+	})
+
 	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
 		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
@@ -2193,7 +2570,7 @@ var _ = Describe("validation of imports", func() {
 		Expect(result).To(HaveLen(1))
 	})
 
-	It("rejects monolith import with wildcards alongside specific values", func() {
+	It("rejects monolith import in online mode", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
 				Bootstrap: &BootstrapConfiguration{
@@ -2202,7 +2579,8 @@ var _ = Describe("validation of imports", func() {
 						Owner:    "app",
 						Import: &Import{
 							Type:      MonolithSnapshotType,
-							Databases: []string{"bar", "*"},
+							Databases: []string{"foo"},
+							Online:    &OnlineImport{},
 						},
 					},
 				},
@@ -2211,8 +2589,10 @@ var _ = Describe("validation of imports", func() {
 
 		result := cluster.validateImport()
 		Expect(result).To(HaveLen(1))
+	})
 
-		cluster = &Cluster{
+	It("rejects monolith import with wildcards alongside specific values", func() {
+		cluster := &Cluster{
 			Spec: ClusterSpec{
 				Bootstrap: &BootstrapConfiguration{
 					InitDB: &BootstrapInitDB{
@@ -2220,8 +2600,26 @@ var _ = Describe("validation of imports", func() {
 						Owner:    "app",
 						Import: &Import{
 							Type:      MonolithSnapshotType,
-							Databases: []string{"foo"},
-							Roles:     []string{"baz", "*"},
+							Databases: []string{"bar", "*"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"foo"},
+							Roles:     []string{"baz", "*"},
 						},
 					},
 				},
@@ -2387,3 +2785,1492 @@ var _ = Describe("validation of replication slots configuration", func() {
 		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
 	})
 })
+
+var _ = Describe("validation of cascading replication configuration", func() {
+	It("allows standbys streaming from different instances", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Replication: &ReplicationConfiguration{
+					Cascading: &CascadingReplicationConfiguration{
+						Instances: []CascadingReplicationInstance{
+							{Name: "cluster-example-2", Upstream: "cluster-example-3"},
+							{Name: "cluster-example-4", Upstream: "cluster-example-3"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCascadingReplication()).To(BeEmpty())
+	})
+
+	It("complains if a standby has more than one upstream", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Replication: &ReplicationConfiguration{
+					Cascading: &CascadingReplicationConfiguration{
+						Instances: []CascadingReplicationInstance{
+							{Name: "cluster-example-2", Upstream: "cluster-example-3"},
+							{Name: "cluster-example-2", Upstream: "cluster-example-4"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCascadingReplication()).To(HaveLen(1))
+	})
+
+	It("complains if a standby streams from itself", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Replication: &ReplicationConfiguration{
+					Cascading: &CascadingReplicationConfiguration{
+						Instances: []CascadingReplicationInstance{
+							{Name: "cluster-example-2", Upstream: "cluster-example-2"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCascadingReplication()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the logging configuration", func() {
+	It("doesn't complain if there is no logging configuration", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateLogging()).To(BeEmpty())
+	})
+
+	It("accepts static fields and valid logger levels", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Logging: &LoggingConfiguration{
+					Fields: map[string]string{
+						"team":        "payments",
+						"environment": "production",
+					},
+					Loggers: map[string]string{
+						"postgres": "error",
+						"pgaudit":  "debug",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateLogging()).To(BeEmpty())
+	})
+
+	It("complains if a static field overrides a reserved one", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Logging: &LoggingConfiguration{
+					Fields: map[string]string{
+						"team":  "payments",
+						"level": "info",
+						"msg":   "hello",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateLogging()).To(HaveLen(2))
+	})
+
+	It("complains if a logger level is not valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Logging: &LoggingConfiguration{
+					Loggers: map[string]string{
+						"postgres": "verbose",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateLogging()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the clone throttling", func() {
+	newCluster := func(throttling *CloneThrottlingConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Replication: &ReplicationConfiguration{CloneThrottling: throttling},
+			},
+		}
+	}
+
+	It("doesn't complain without throttling", func() {
+		Expect(newCluster(nil).validateCloneThrottling()).To(BeEmpty())
+	})
+
+	It("accepts a valid configuration", func() {
+		cluster := newCluster(&CloneThrottlingConfiguration{
+			MaxRate:    "100M",
+			Nice:       pointer.Int32(10),
+			IOClass:    IOSchedulingClassBestEffort,
+			IOPriority: pointer.Int32(7),
+		})
+		Expect(cluster.validateCloneThrottling()).To(BeEmpty())
+	})
+
+	DescribeTable("checks the range of the transfer rate",
+		func(maxRate string, valid bool) {
+			result := newCluster(&CloneThrottlingConfiguration{MaxRate: maxRate}).validateCloneThrottling()
+			if valid {
+				Expect(result).To(BeEmpty())
+			} else {
+				Expect(result).To(HaveLen(1))
+			}
+		},
+		Entry("kilobytes without suffix", "32", true),
+		Entry("too low", "31k", false),
+		Entry("megabytes", "1024M", true),
+		Entry("too high", "1025M", false),
+		Entry("not a number", "fastM", false),
+	)
+
+	It("complains about the I/O priority in the idle class", func() {
+		cluster := newCluster(&CloneThrottlingConfiguration{
+			IOClass:    IOSchedulingClassIdle,
+			IOPriority: pointer.Int32(0),
+		})
+		Expect(cluster.validateCloneThrottling()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the clone method", func() {
+	It("accepts the volume clone without a WAL storage", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Replication: &ReplicationConfiguration{CloneMethod: ReplicaCloneMethodVolumeClone},
+			},
+		}
+		Expect(cluster.validateCloneMethod()).To(BeEmpty())
+	})
+
+	It("refuses the volume clone with a WAL storage", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Replication: &ReplicationConfiguration{CloneMethod: ReplicaCloneMethodVolumeClone},
+				WalStorage:  &StorageConfiguration{Size: "1Gi"},
+			},
+		}
+		Expect(cluster.validateCloneMethod()).To(HaveLen(1))
+
+		cluster.Spec.Replication.CloneMethod = ReplicaCloneMethodPgBaseBackup
+		Expect(cluster.validateCloneMethod()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the maximum standby lag", func() {
+	newCluster := func(maxStandbyLag string) *Cluster {
+		quantity := resource.MustParse(maxStandbyLag)
+		return &Cluster{
+			Spec: ClusterSpec{
+				Replication: &ReplicationConfiguration{MaxStandbyLag: &quantity},
+			},
+		}
+	}
+
+	It("doesn't complain without a limit", func() {
+		Expect((&Cluster{}).validateMaxStandbyLag()).To(BeEmpty())
+	})
+
+	It("accepts a positive limit", func() {
+		cluster := newCluster("64Mi")
+		Expect(cluster.validateMaxStandbyLag()).To(BeEmpty())
+		Expect(cluster.Spec.Replication.GetMaxStandbyLag()).To(BeEquivalentTo(64 * 1024 * 1024))
+	})
+
+	It("complains about a limit which is not positive", func() {
+		Expect(newCluster("0").validateMaxStandbyLag()).To(HaveLen(1))
+		Expect(newCluster("-1Mi").validateMaxStandbyLag()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the smart shutdown timeout", func() {
+	newCluster := func(stopDelay, smartShutdownTimeout int32) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				MaxStopDelay:         stopDelay,
+				SmartShutdownTimeout: smartShutdownTimeout,
+			},
+		}
+	}
+
+	It("uses half of the stop delay when not set", func() {
+		cluster := newCluster(60, 0)
+		Expect(cluster.validateSmartShutdownTimeout()).To(BeEmpty())
+		Expect(cluster.GetSmartShutdownTimeout()).To(BeEquivalentTo(30))
+	})
+
+	It("accepts a timeout lower than the stop delay", func() {
+		cluster := newCluster(60, 50)
+		Expect(cluster.validateSmartShutdownTimeout()).To(BeEmpty())
+		Expect(cluster.GetSmartShutdownTimeout()).To(BeEquivalentTo(50))
+	})
+
+	It("complains about a timeout leaving no time for the fast shutdown", func() {
+		Expect(newCluster(60, 60).validateSmartShutdownTimeout()).To(HaveLen(1))
+		Expect(newCluster(0, 30).validateSmartShutdownTimeout()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the WAL compression method", func() {
+	It("doesn't complain if the method is not specified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14",
+			},
+		}
+		Expect(cluster.validateWALCompression()).To(BeEmpty())
+	})
+
+	It("accepts pglz on every PostgreSQL version", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:12",
+				Replication: &ReplicationConfiguration{
+					WALCompression: WALCompressionPglz,
+				},
+			},
+		}
+		Expect(cluster.validateWALCompression()).To(BeEmpty())
+	})
+
+	It("requires PostgreSQL 15 for lz4 and zstd", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14",
+				Replication: &ReplicationConfiguration{
+					WALCompression: WALCompressionZstd,
+				},
+			},
+		}
+		Expect(cluster.validateWALCompression()).To(HaveLen(1))
+
+		cluster.Spec.ImageName = "postgres:15"
+		Expect(cluster.validateWALCompression()).To(BeEmpty())
+	})
+
+	It("complains if the wal_compression parameter is set too", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"wal_compression": "on",
+					},
+				},
+				Replication: &ReplicationConfiguration{
+					WALCompression: WALCompressionLz4,
+				},
+			},
+		}
+		Expect(cluster.validateWALCompression()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("SCRAM migration validation", func() {
+	It("complains if md5 is requested as the password hashing method", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"password_encryption": "md5",
+					},
+					SCRAMMigration: &SCRAMMigrationConfiguration{},
+				},
+			},
+		}
+		Expect(cluster.validateSCRAMMigration()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.Parameters["password_encryption"] = "scram-sha-256"
+		Expect(cluster.validateSCRAMMigration()).To(BeEmpty())
+
+		cluster.Spec.PostgresConfiguration.SCRAMMigration = nil
+		cluster.Spec.PostgresConfiguration.Parameters["password_encryption"] = "md5"
+		Expect(cluster.validateSCRAMMigration()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("PostgreSQL TLS settings validation", func() {
+	It("requires PostgreSQL 12 for the minimum protocol version", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:11",
+				PostgresConfiguration: PostgresConfiguration{
+					TLS: &PostgresTLSConfiguration{
+						MinProtocolVersion: TLSProtocolVersion13,
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePostgresTLS()).To(HaveLen(1))
+
+		cluster.Spec.ImageName = "postgres:12"
+		Expect(cluster.validatePostgresTLS()).To(BeEmpty())
+	})
+
+	It("doesn't allow setting the TLS parameters directly", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"ssl_min_protocol_version": "TLSv1.3",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the deletion policy", func() {
+	It("doesn't complain if the deletion policy is not specified", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateDeletionPolicy()).To(BeEmpty())
+	})
+
+	It("doesn't complain about the inventory export without backups", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				DeletionPolicy: &DeletionPolicy{
+					ExportInventory: true,
+				},
+			},
+		}
+		Expect(cluster.validateDeletionPolicy()).To(BeEmpty())
+	})
+
+	It("complains if the final backup or the WAL archiving are requested without backups", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				DeletionPolicy: &DeletionPolicy{
+					FinalBackup: &FinalBackupConfiguration{
+						Enabled: pointer.Bool(true),
+					},
+					ArchiveWAL: pointer.Bool(true),
+				},
+			},
+		}
+		Expect(cluster.validateDeletionPolicy()).To(HaveLen(2))
+
+		cluster.Spec.Backup = &BackupConfiguration{
+			BarmanObjectStore: &BarmanObjectStoreConfiguration{
+				BarmanCredentials: BarmanCredentials{
+					AWS: &S3Credentials{InheritFromIAMRole: true},
+				},
+			},
+		}
+		Expect(cluster.validateDeletionPolicy()).To(BeEmpty())
+	})
+
+	It("complains if the final backup timeout exceeds the one of the deletion policy", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				DeletionPolicy: &DeletionPolicy{
+					FinalBackup: &FinalBackupConfiguration{
+						Timeout: 400,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateDeletionPolicy()).To(HaveLen(1))
+
+		cluster.Spec.DeletionPolicy.Timeout = 600
+		Expect(cluster.validateDeletionPolicy()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the instance Pod template", func() {
+	It("doesn't complain if the Pod template is not specified", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validatePodTemplate()).To(BeEmpty())
+	})
+
+	It("accepts a valid Pod template", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					Metadata: EmbeddedObjectMetadata{
+						Labels:      map[string]string{"team": "dba"},
+						Annotations: map[string]string{"example.com/owner": "dba"},
+					},
+					Env: []v1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+					ProjectedVolumes: []InstanceProjectedVolume{
+						{Name: "ldap-ca", MountPath: "/etc/ldap-ca"},
+						{Name: "keytab", MountPath: "/etc/krb5"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(BeEmpty())
+	})
+
+	It("complains about the metadata managed by the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					Metadata: EmbeddedObjectMetadata{
+						Labels:      map[string]string{"role": "primary", "cnpg.io/cluster": "other"},
+						Annotations: map[string]string{"kubectl.kubernetes.io/restartedAt": "now"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(3))
+	})
+
+	It("complains about the environment variables set by the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					Env: []v1.EnvVar{{Name: "PGDATA", Value: "/tmp"}},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(1))
+	})
+
+	It("complains about volumes interfering with the ones of the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					ProjectedVolumes: []InstanceProjectedVolume{
+						{Name: "pgdata", MountPath: "/etc/pgdata"},
+						{Name: "duplicated", MountPath: "/etc/one"},
+						{Name: "duplicated", MountPath: "/etc/two"},
+						{Name: "relative", MountPath: "etc/relative"},
+						{Name: "nested", MountPath: "/var/lib/postgresql/data/extra"},
+						{Name: "root", MountPath: "/"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(5))
+	})
+
+	It("complains about the huge pages volume", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					ProjectedVolumes: []InstanceProjectedVolume{
+						{Name: "hugepages", MountPath: "/etc/hugepages"},
+						{Name: "pages", MountPath: "/dev/hugepages"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(2))
+	})
+
+	It("complains about the projected volume template", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					ProjectedVolumes: []InstanceProjectedVolume{
+						{Name: "projected", MountPath: "/etc/projected"},
+						{Name: "certificates", MountPath: "/projected/certificates"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(2))
+	})
+
+	It("checks the CSI volumes together with the projected ones", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					ProjectedVolumes: []InstanceProjectedVolume{
+						{Name: "certificates", MountPath: "/etc/certificates"},
+					},
+					CSIVolumes: []InstanceCSIVolume{
+						{Name: "secrets-store", MountPath: "/etc/secrets-store"},
+						{Name: "certificates", MountPath: "/etc/other"},
+						{Name: "app-secret", MountPath: "/etc/app-secret"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(3))
+	})
+
+	It("accepts sidecars mounting the volumes of the Pod template", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					ProjectedVolumes: []InstanceProjectedVolume{
+						{Name: "fluent-bit-config", MountPath: "/etc/fluent-bit-config"},
+					},
+					Sidecars: []v1.Container{
+						{
+							Name:  "fluent-bit",
+							Image: "fluent/fluent-bit:2.0",
+							VolumeMounts: []v1.VolumeMount{
+								{Name: "fluent-bit-config", MountPath: "/fluent-bit/etc"},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(BeEmpty())
+	})
+
+	It("complains about sidecars interfering with the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					Sidecars: []v1.Container{
+						{Name: "postgres", Image: "example/agent"},
+						{Name: "extension-postgis", Image: "example/agent"},
+						{Name: "agent", Image: "example/agent"},
+						{Name: "agent", Image: "example/agent"},
+						{Name: "no-image"},
+						{
+							Name:  "reader",
+							Image: "example/agent",
+							VolumeMounts: []v1.VolumeMount{
+								{Name: "pgdata", MountPath: "/pgdata"},
+								{Name: "logs", MountPath: "/logs/archive"},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(7))
+	})
+})
+
+var _ = Describe("credentials files validation", func() {
+	It("accepts clusters without credentials files", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateCredentialsFiles()).To(BeEmpty())
+	})
+
+	It("accepts absolute paths", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				CredentialsFiles: &CredentialsFilesConfiguration{
+					SuperuserPasswordFile:   "/vault/secrets/postgres",
+					ApplicationPasswordFile: "/etc/secrets-store/app",
+				},
+			},
+		}
+		Expect(cluster.validateCredentialsFiles()).To(BeEmpty())
+	})
+
+	It("complains about relative paths", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				CredentialsFiles: &CredentialsFilesConfiguration{
+					SuperuserPasswordFile:   "secrets/postgres",
+					ApplicationPasswordFile: "app",
+				},
+			},
+		}
+		Expect(cluster.validateCredentialsFiles()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("network policy validation", func() {
+	It("accepts clusters without a network policy", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateNetworkPolicy()).To(BeEmpty())
+	})
+
+	It("accepts valid namespace names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				NetworkPolicy: &NetworkPolicyConfiguration{
+					Enabled:           true,
+					AllowedNamespaces: []string{"app", "app-staging"},
+				},
+			},
+		}
+		Expect(cluster.validateNetworkPolicy()).To(BeEmpty())
+	})
+
+	It("complains about invalid namespace names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				NetworkPolicy: &NetworkPolicyConfiguration{
+					Enabled:           true,
+					AllowedNamespaces: []string{"app", "App_Staging", ""},
+				},
+			},
+		}
+		Expect(cluster.validateNetworkPolicy()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("instance groups validation", func() {
+	It("accepts clusters without instance groups", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.validateInstanceGroups()).To(BeEmpty())
+	})
+
+	It("accepts groups fitting in the cluster", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+				InstanceGroups: []InstanceGroup{
+					{Name: "fast", Instances: 2},
+					{Name: "reporting", Instances: 1, ExcludedFromFailover: true},
+				},
+			},
+		}
+		Expect(cluster.validateInstanceGroups()).To(BeEmpty())
+	})
+
+	It("complains about invalid, empty and duplicate group names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+				InstanceGroups: []InstanceGroup{
+					{Name: "Fast_Storage", Instances: 1},
+					{Name: "", Instances: 1},
+					{Name: "Fast_Storage", Instances: 1},
+				},
+			},
+		}
+		Expect(cluster.validateInstanceGroups()).To(HaveLen(3))
+	})
+
+	It("complains when the groups contain more instances than the cluster", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 2,
+				InstanceGroups: []InstanceGroup{
+					{Name: "fast", Instances: 2},
+					{Name: "slow", Instances: 1},
+				},
+			},
+		}
+		Expect(cluster.validateInstanceGroups()).To(HaveLen(1))
+	})
+
+	It("complains when no instance can be promoted", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 2,
+				InstanceGroups: []InstanceGroup{
+					{Name: "reporting", Instances: 2, ExcludedFromFailover: true},
+				},
+			},
+		}
+		Expect(cluster.validateInstanceGroups()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("instance overrides validation", func() {
+	It("accepts clusters without overrides", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateInstanceOverrides()).To(BeEmpty())
+	})
+
+	It("accepts valid overrides", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.InstanceOverridesAnnotationName: `{"2": {"resources": {"requests": {"cpu": "4"}}}}`,
+				},
+			},
+		}
+		Expect(cluster.validateInstanceOverrides()).To(BeEmpty())
+	})
+
+	It("complains about invalid content and serials", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.InstanceOverridesAnnotationName: `{"two": {}}`,
+				},
+			},
+		}
+		Expect(cluster.validateInstanceOverrides()).To(HaveLen(1))
+
+		cluster.Annotations[utils.InstanceOverridesAnnotationName] = `{"0": {}}`
+		Expect(cluster.validateInstanceOverrides()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("pooler certificate users validation", func() {
+	It("accepts an empty list", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validatePoolerCertificateUsers()).To(BeEmpty())
+	})
+
+	It("accepts valid user names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PoolerCertificateUsers: []string{"app", "reporting"},
+				},
+			},
+		}
+		Expect(cluster.validatePoolerCertificateUsers()).To(BeEmpty())
+	})
+
+	It("complains about users managed by the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PoolerCertificateUsers: []string{"postgres", StreamingReplicationUser, PGBouncerPoolerUserName},
+				},
+			},
+		}
+		Expect(cluster.validatePoolerCertificateUsers()).To(HaveLen(3))
+	})
+
+	It("complains about empty, quoted and duplicate user names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PoolerCertificateUsers: []string{"", "a\"pp", "app", "app"},
+				},
+			},
+		}
+		Expect(cluster.validatePoolerCertificateUsers()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("managed databases validation", func() {
+	It("accepts exposed databases with valid names", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{Name: "orders", Owner: "orders_owner", Expose: true},
+						{Name: "reporting_db"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
+	})
+
+	It("complains about reserved and duplicate databases", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{Name: "template1", Owner: "app"},
+						{Name: "orders"},
+						{Name: "orders"},
+						{Name: "", Owner: "app"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(3))
+	})
+
+	It("complains about owners managed by the operator", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{Name: "orders", Owner: "postgres"},
+						{Name: StreamingReplicationUser},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(2))
+	})
+
+	It("complains about invalid configuration parameter names", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							Parameters: map[string]string{
+								"statement_timeout":    "30s",
+								"myapp.tenant":         "acme",
+								"work_mem; DROP TABLE": "4MB",
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(1))
+	})
+
+	It("complains about exposed databases not usable as service names", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{Name: "reporting_db", Expose: true},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).ToNot(BeEmpty())
+	})
+
+	It("complains about password rotation on databases not exposed", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{Name: "orders", PasswordRotation: &metav1.Duration{Duration: 24 * time.Hour}},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(1))
+	})
+
+	It("complains about password rotation intervals shorter than one hour", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{Name: "orders", Expose: true, PasswordRotation: &metav1.Duration{Duration: time.Minute}},
+						{Name: "billing", Expose: true, PasswordRotation: &metav1.Duration{Duration: 720 * time.Hour}},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(1))
+	})
+
+	It("accepts valid grants", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							Grants: []ManagedGrant{
+								{
+									Role:       "reporting",
+									Privileges: []DatabasePrivilege{"CONNECT"},
+									Schemas: []ManagedSchemaGrant{
+										{
+											Name:              "public",
+											Privileges:        []SchemaPrivilege{"USAGE"},
+											TablePrivileges:   []TablePrivilege{"SELECT"},
+											DefaultPrivileges: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
+	})
+
+	It("complains about invalid grants", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							Grants: []ManagedGrant{
+								{Role: "postgres"},
+								{Role: ""},
+								{
+									Role: "reporting",
+									Schemas: []ManagedSchemaGrant{
+										{Name: "public", DefaultPrivileges: true},
+										{Name: "public"},
+										{Name: ""},
+									},
+								},
+								{Role: "reporting"},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(6))
+	})
+
+	It("accepts valid extensions", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							Extensions: []ManagedDatabaseExtension{
+								{Name: "postgis", Version: "3.3.2", Schema: "gis"},
+								{Name: "pg_partman", SharedPreloadLibraries: []string{"pg_partman_bgw"}},
+								{Name: "hstore", Ensure: EnsureAbsent},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
+	})
+
+	It("complains about invalid extensions", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							Extensions: []ManagedDatabaseExtension{
+								{Name: ""},
+								{Name: "postgis"},
+								{Name: "postgis"},
+								{Name: "pg_stat_statements"},
+								{Name: "pg_partman", SharedPreloadLibraries: []string{"pg_partman_bgw,pgaudit"}},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(4))
+	})
+
+	It("accepts valid foreign data wrappers and servers", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							ForeignDataWrappers: []ManagedForeignDataWrapper{
+								{Name: "postgres_fdw", Handler: "postgres_fdw_handler"},
+								{Name: "legacy_fdw", Ensure: EnsureAbsent},
+							},
+							ForeignServers: []ManagedForeignServer{
+								{
+									Name:               "warehouse",
+									ForeignDataWrapper: "postgres_fdw",
+									Options:            map[string]string{"host": "warehouse-rw", "dbname": "app"},
+									UserMappings: []ManagedUserMapping{
+										{User: "orders_owner", CredentialsSecret: &LocalObjectReference{Name: "warehouse"}},
+										{User: "PUBLIC", Options: map[string]string{"user": "guest"}},
+									},
+								},
+								{Name: "legacy", Ensure: EnsureAbsent},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
+	})
+
+	It("complains about invalid foreign data wrappers and servers", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{
+						{
+							Name: "orders",
+							ForeignDataWrappers: []ManagedForeignDataWrapper{
+								{Name: ""},
+								{Name: "postgres_fdw", Options: map[string]string{"": "value"}},
+								{Name: "postgres_fdw"},
+							},
+							ForeignServers: []ManagedForeignServer{
+								{Name: "warehouse"},
+								{
+									Name:               "warehouse",
+									ForeignDataWrapper: "postgres_fdw",
+									UserMappings: []ManagedUserMapping{
+										{User: ""},
+										{User: "public"},
+										{User: "PUBLIC"},
+										{
+											User:              "orders_owner",
+											Options:           map[string]string{"password": "secret"},
+											CredentialsSecret: &LocalObjectReference{Name: "warehouse"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(8))
+	})
+})
+
+var _ = Describe("managed services validation", func() {
+	It("doesn't complain if there are no managed services", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateManagedServices()).To(BeEmpty())
+	})
+
+	It("accepts valid templates and additional services", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						Templates: []ServiceTemplate{
+							{
+								SelectorType: ServiceSelectorTypeRW,
+								ServiceTemplateSpec: ServiceTemplateSpec{
+									Type:                     v1.ServiceTypeLoadBalancer,
+									ExternalTrafficPolicy:    v1.ServiceExternalTrafficPolicyTypeLocal,
+									LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+								},
+							},
+							{SelectorType: ServiceSelectorTypeRO},
+						},
+						Additional: []AdditionalService{
+							{Name: "cluster-example-reporting", SelectorType: ServiceSelectorTypeRO},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedServices()).To(BeEmpty())
+	})
+
+	It("complains about invalid templates and additional services", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Databases: []ManagedDatabase{{Name: "orders", Expose: true}},
+					Services: &ManagedServices{
+						Templates: []ServiceTemplate{
+							{SelectorType: ServiceSelectorTypeRW},
+							{
+								SelectorType: ServiceSelectorTypeRW,
+								ServiceTemplateSpec: ServiceTemplateSpec{
+									ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+								},
+							},
+						},
+						Additional: []AdditionalService{
+							{Name: "", SelectorType: ServiceSelectorTypeR},
+							{Name: "cluster-example-rw", SelectorType: ServiceSelectorTypeR},
+							{Name: "cluster-example-db-orders", SelectorType: ServiceSelectorTypeR},
+							{Name: "Reporting", SelectorType: ServiceSelectorTypeR},
+							{Name: "reporting", SelectorType: ServiceSelectorTypeR},
+							{
+								Name:         "reporting",
+								SelectorType: ServiceSelectorTypeR,
+								ServiceTemplateSpec: ServiceTemplateSpec{
+									Type:                     v1.ServiceTypeNodePort,
+									LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedServices()).To(HaveLen(8))
+	})
+
+	It("complains about role options used with the wrong service", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						Templates: []ServiceTemplate{
+							{SelectorType: ServiceSelectorTypeR, ExcludePrimary: true},
+							{SelectorType: ServiceSelectorTypeRO, PrimaryFallback: true, ExcludePrimary: true},
+							{SelectorType: ServiceSelectorTypeRW, PrimaryFallback: true},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedServices()).To(HaveLen(2))
+	})
+
+	It("complains if an additional service collides with the service of an instance", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						PerInstance: &ServiceTemplateSpec{},
+						Additional: []AdditionalService{
+							{Name: "cluster-example-2", SelectorType: ServiceSelectorTypeR},
+							{Name: "cluster-example-reporting", SelectorType: ServiceSelectorTypeR},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedServices()).To(HaveLen(1))
+
+		cluster.Spec.Managed.Services.PerInstance = nil
+		Expect(cluster.validateManagedServices()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ephemeral volumes size limits validation", func() {
+	It("accepts positive size limits", func() {
+		shm := resource.MustParse("256Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				EphemeralVolumesSizeLimit: &EphemeralVolumesSizeLimitConfiguration{Shm: &shm},
+			},
+		}
+		Expect(cluster.validateEphemeralVolumesSizeLimit()).To(BeEmpty())
+	})
+
+	It("complains about size limits that aren't positive", func() {
+		zero := resource.MustParse("0")
+		negative := resource.MustParse("-1Gi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				EphemeralVolumesSizeLimit: &EphemeralVolumesSizeLimitConfiguration{
+					Shm:           &zero,
+					TemporaryData: &negative,
+				},
+			},
+		}
+		Expect(cluster.validateEphemeralVolumesSizeLimit()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("minor version pinning validation", func() {
+	It("accepts a version matching the image", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName:           "ghcr.io/cloudnative-pg/postgresql:15.0",
+				MinorVersionPinning: &MinorVersionPinning{Version: "15.0"},
+			},
+		}
+		Expect(cluster.validateMinorVersionPinning()).To(BeEmpty())
+	})
+
+	It("complains when the version doesn't match the image", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName:           "ghcr.io/cloudnative-pg/postgresql:15.1",
+				MinorVersionPinning: &MinorVersionPinning{Version: "15.0"},
+			},
+		}
+		Expect(cluster.validateMinorVersionPinning()).To(HaveLen(1))
+	})
+
+	It("requires an explicit image", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MinorVersionPinning: &MinorVersionPinning{Version: "15.0"},
+			},
+		}
+		Expect(cluster.validateMinorVersionPinning()).To(HaveLen(1))
+	})
+
+	It("doesn't complain without pinning", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateMinorVersionPinning()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("extension images validation", func() {
+	It("accepts extension images on PostgreSQL 18", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:18.0",
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ExtensionConfiguration{
+						{Name: "pgvector", Image: "pgvector:0.8.0"},
+						{Name: "postgis", Image: "postgis:3.5.2", DynamicLibraryPath: []string{"lib", "system/lib"}},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(BeEmpty())
+	})
+
+	It("requires PostgreSQL 18", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ExtensionConfiguration{{Name: "pgvector", Image: "pgvector:0.8.0"}},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(HaveLen(1))
+	})
+
+	It("complains about invalid extension images", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:18.0",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"dynamic_library_path": "$libdir:/opt/lib",
+					},
+					Extensions: []ExtensionConfiguration{
+						{Name: "pgvector", Image: "pgvector:0.8.0"},
+						{Name: "pgvector", Image: "pgvector:0.8.1"},
+						{Name: "postgis"},
+						{
+							Name:                 "timescaledb",
+							Image:                "timescaledb:2.19",
+							ExtensionControlPath: []string{"/share", "../share"},
+							DynamicLibraryPath:   []string{"lib/../lib"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(HaveLen(6))
+	})
+})
+
+var _ = Describe("huge pages validation", func() {
+	hugePages := v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			"hugepages-2Mi": resource.MustParse("1Gi"),
+		},
+		Requests: v1.ResourceList{
+			"hugepages-2Mi": resource.MustParse("1Gi"),
+		},
+	}
+
+	It("accepts a single huge page size", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Resources: hugePages}}
+		Expect(cluster.validateHugePages()).To(BeEmpty())
+	})
+
+	It("complains about multiple huge page sizes", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{
+						"hugepages-2Mi": resource.MustParse("1Gi"),
+						"hugepages-1Gi": resource.MustParse("2Gi"),
+					},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+	})
+
+	It("requires the Jobs to use the same huge pages", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources:    hugePages,
+				JobResources: &v1.ResourceRequirements{},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+
+		cluster.Spec.JobResources = hugePages.DeepCopy()
+		Expect(cluster.validateHugePages()).To(BeEmpty())
+	})
+
+	It("complains if huge pages are required without being requested", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"huge_pages": "on",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+
+		cluster.Spec.Resources = hugePages
+		Expect(cluster.validateHugePages()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("pg_hba and pg_ident validation", func() {
+	It("accepts valid rules and maps", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA:   []string{"hostssl app all all cert", "host all all 10.244.0.0/16 md5"},
+					PgIdent: []string{"kerberos alice@EXAMPLE.COM alice"},
+				},
+			},
+		}
+		Expect(cluster.validatePgHBA()).To(BeEmpty())
+		Expect(cluster.validatePgIdent()).To(BeEmpty())
+	})
+
+	It("complains about invalid rules", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{"hostssl app all all cert", "host all all all", "remote all all all md5"},
+				},
+			},
+		}
+		errors := cluster.validatePgHBA()
+		Expect(errors).To(HaveLen(2))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.pg_hba[1]"))
+	})
+
+	It("complains about invalid maps", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{"local root postgres", "kerberos alice"},
+				},
+			},
+		}
+		Expect(cluster.validatePgIdent()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("LDAP validation", func() {
+	caCertificate := &SecretKeySelector{
+		LocalObjectReference: LocalObjectReference{Name: "ldap-ca"},
+		Key:                  "ca.crt",
+	}
+
+	It("complains if the server is missing", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					LDAP: &LDAPConfig{BindAsAuth: &LDAPBindAsAuth{Prefix: "cn="}},
+				},
+			},
+		}
+		Expect(cluster.validateLDAP()).To(HaveLen(1))
+	})
+
+	It("accepts a CA certificate with the ldaps scheme or TLS", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					LDAP: &LDAPConfig{
+						Server:        "ldap.example.com",
+						Scheme:        LDAPSchemeLDAPS,
+						CACertificate: caCertificate,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateLDAP()).To(BeEmpty())
+
+		cluster.Spec.PostgresConfiguration.LDAP.Scheme = LDAPSchemeLDAP
+		cluster.Spec.PostgresConfiguration.LDAP.TLS = true
+		Expect(cluster.validateLDAP()).To(BeEmpty())
+	})
+
+	It("complains about a CA certificate on an unencrypted connection", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					LDAP: &LDAPConfig{
+						Server:        "ldap.example.com",
+						CACertificate: caCertificate,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateLDAP()).To(HaveLen(1))
+	})
+
+	It("complains about the volumes used by the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PodTemplate: &InstancePodTemplate{
+					Env: []v1.EnvVar{{Name: "LDAPTLS_CACERT", Value: "/etc/ldap/ca.crt"}},
+					ProjectedVolumes: []InstanceProjectedVolume{
+						{Name: "ldap-server-ca", MountPath: "/etc/ldap-server-ca"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePodTemplate()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("capacity validation", func() {
+	It("complains when the WAL storage is smaller than max_wal_size", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{Size: "1Gi"},
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"max_wal_size": "2GB"},
+				},
+			},
+		}
+		Expect(cluster.validateWalStorageCapacity()).To(HaveLen(1))
+
+		cluster.Spec.WalStorage.Size = "4Gi"
+		Expect(cluster.validateWalStorageCapacity()).To(BeEmpty())
+	})
+
+	It("doesn't complain about max_wal_size without a WAL storage", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"max_wal_size": "64GB"},
+				},
+			},
+		}
+		Expect(cluster.validateWalStorageCapacity()).To(BeEmpty())
+	})
+
+	It("complains when shared_buffers doesn't fit in the memory limit", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "1GB"},
+				},
+			},
+		}
+		Expect(cluster.validateSharedBuffers()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.Parameters["shared_buffers"] = "256MB"
+		Expect(cluster.validateSharedBuffers()).To(BeEmpty())
+	})
+
+	It("doesn't complain about shared_buffers when using huge pages", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("1Gi"),
+						"hugepages-2Mi":   resource.MustParse("2Gi"),
+					},
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "1GB"},
+				},
+			},
+		}
+		Expect(cluster.validateSharedBuffers()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,149 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultClusterHistoryMaxEntries is the number of entries kept
+// in a ClusterHistory when no limit has been specified
+const DefaultClusterHistoryMaxEntries = 100
+
+// ClusterHistoryEntryType is the kind of state transition
+// recorded in a ClusterHistory entry
+type ClusterHistoryEntryType string
+
+const (
+	// ClusterHistoryPrimaryChange is recorded when a switchover
+	// or a failover has been completed
+	ClusterHistoryPrimaryChange ClusterHistoryEntryType = "PrimaryChange"
+
+	// ClusterHistoryPhaseChange is recorded when the phase of the cluster changes
+	ClusterHistoryPhaseChange ClusterHistoryEntryType = "PhaseChange"
+
+	// ClusterHistoryImageChange is recorded when the PostgreSQL
+	// image used by the cluster changes
+	ClusterHistoryImageChange ClusterHistoryEntryType = "ImageChange"
+)
+
+// ClusterHistorySpec defines the desired state of ClusterHistory
+type ClusterHistorySpec struct {
+	// The cluster whose state transitions are recorded
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The maximum number of entries to be kept, the oldest ones
+	// being removed first
+	// +kubebuilder:default:=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxEntries int `json:"maxEntries,omitempty"`
+}
+
+// ClusterHistoryStatus defines the observed state of ClusterHistory
+type ClusterHistoryStatus struct {
+	// The state transitions of the cluster, from the oldest to the newest
+	// +optional
+	Entries []ClusterHistoryEntry `json:"entries,omitempty"`
+
+	// The PostgreSQL image used by the cluster when the last
+	// ImageChange entry has been recorded
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// ClusterHistoryEntry is a significant state transition of a cluster
+type ClusterHistoryEntry struct {
+	// When the transition has been recorded
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// The kind of transition
+	// +kubebuilder:validation:Enum=PrimaryChange;PhaseChange;ImageChange
+	Type ClusterHistoryEntryType `json:"type"`
+
+	// The previous value, e.g. the previous phase or image
+	// +optional
+	From string `json:"from,omitempty"`
+
+	// The new value, e.g. the new primary, phase or image
+	// +optional
+	To string `json:"to,omitempty"`
+
+	// The reason of the transition, e.g. the reason code of a
+	// failover or the reason of a phase change
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+
+// ClusterHistory is the Schema for the clusterhistories API
+type ClusterHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the cluster history.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ClusterHistorySpec `json:"spec,omitempty"`
+	// Most recently observed status of the cluster history. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Status ClusterHistoryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterHistoryList contains a list of ClusterHistory
+type ClusterHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of cluster histories
+	Items []ClusterHistory `json:"items"`
+}
+
+// GetMaxEntries gets the number of entries to be kept in the history
+func (history *ClusterHistory) GetMaxEntries() int {
+	if history.Spec.MaxEntries > 0 {
+		return history.Spec.MaxEntries
+	}
+
+	return DefaultClusterHistoryMaxEntries
+}
+
+// AddEntry appends an entry to the history, removing the
+// oldest ones when the maximum number of entries is exceeded
+func (history *ClusterHistory) AddEntry(entry ClusterHistoryEntry) {
+	history.Status.Entries = append(history.Status.Entries, entry)
+
+	if excess := len(history.Status.Entries) - history.GetMaxEntries(); excess > 0 {
+		history.Status.Entries = history.Status.Entries[excess:]
+	}
+
+	if entry.Type == ClusterHistoryImageChange {
+		history.Status.Image = entry.To
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterHistory{}, &ClusterHistoryList{})
+}
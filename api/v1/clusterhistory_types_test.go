@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster history", func() {
+	It("uses the default number of entries when not specified", func() {
+		history := ClusterHistory{}
+		Expect(history.GetMaxEntries()).To(Equal(DefaultClusterHistoryMaxEntries))
+	})
+
+	It("removes the oldest entries when the limit is exceeded", func() {
+		history := ClusterHistory{Spec: ClusterHistorySpec{MaxEntries: 2}}
+		history.AddEntry(ClusterHistoryEntry{Type: ClusterHistoryPhaseChange, To: "one"})
+		history.AddEntry(ClusterHistoryEntry{Type: ClusterHistoryPhaseChange, To: "two"})
+		history.AddEntry(ClusterHistoryEntry{Type: ClusterHistoryPhaseChange, To: "three"})
+
+		Expect(history.Status.Entries).To(HaveLen(2))
+		Expect(history.Status.Entries[0].To).To(Equal("two"))
+		Expect(history.Status.Entries[1].To).To(Equal("three"))
+	})
+
+	It("keeps track of the last recorded image", func() {
+		history := ClusterHistory{}
+		history.AddEntry(ClusterHistoryEntry{Type: ClusterHistoryImageChange, To: "postgres:15"})
+		history.AddEntry(ClusterHistoryEntry{Type: ClusterHistoryPhaseChange, To: "Cluster in healthy state"})
+		Expect(history.Status.Image).To(Equal("postgres:15"))
+	})
+})
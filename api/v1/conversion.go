@@ -0,0 +1,40 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// The v1 version is the hub of the conversions of the resources of the
+// operator: the other versions, implementing conversion.Convertible, are
+// converted from and to it by the conversion webhook
+
+var (
+	_ conversion.Hub = &Cluster{}
+	_ conversion.Hub = &Backup{}
+	_ conversion.Hub = &Pooler{}
+)
+
+// Hub marks this type as a conversion hub.
+func (*Cluster) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Backup) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Pooler) Hub() {}
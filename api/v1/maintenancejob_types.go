@@ -0,0 +1,194 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaintenanceOperation is the maintenance operation run by a MaintenanceJob
+type MaintenanceOperation string
+
+const (
+	// MaintenanceOperationVacuum vacuums and analyzes the tables, using vacuumdb
+	MaintenanceOperationVacuum MaintenanceOperation = "vacuum"
+
+	// MaintenanceOperationAnalyze only analyzes the tables, using vacuumdb
+	MaintenanceOperationAnalyze MaintenanceOperation = "analyze"
+
+	// MaintenanceOperationReindex rebuilds the indexes, using reindexdb
+	MaintenanceOperationReindex MaintenanceOperation = "reindex"
+
+	// MaintenanceOperationRepack removes the bloat from the tables and
+	// the indexes without holding exclusive locks, using pg_repack
+	MaintenanceOperationRepack MaintenanceOperation = "repack"
+)
+
+// MaintenanceJobSpec defines the desired state of MaintenanceJob
+type MaintenanceJobSpec struct {
+	// The cluster where the maintenance is run
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The schedule follows the same format used in Kubernetes CronJobs,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The maintenance operation: `vacuum`, `analyze`, `reindex` or `repack`
+	// +kubebuilder:validation:Enum=vacuum;analyze;reindex;repack
+	Operation MaintenanceOperation `json:"operation"`
+
+	// The database where the maintenance is run. Defaults to every
+	// database when the superuser access is enabled, to the application
+	// database otherwise
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The tables the maintenance is restricted to. Requires `database`
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+
+	// The number of concurrent connections used to run the maintenance
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+
+	// The image running the maintenance, which must contain the client
+	// programs of PostgreSQL and, for the `repack` operation, pg_repack.
+	// Defaults to the image of the cluster
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// If the job is suspended or not
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// The number of runs kept in the status, together with their Jobs
+	// (default 5)
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+}
+
+// MaintenanceJobRunPhase is the phase of a run of a MaintenanceJob
+type MaintenanceJobRunPhase string
+
+const (
+	// MaintenanceJobRunPhaseRunning means that the maintenance is running
+	MaintenanceJobRunPhaseRunning MaintenanceJobRunPhase = "running"
+
+	// MaintenanceJobRunPhaseSucceeded means that the maintenance succeeded
+	MaintenanceJobRunPhaseSucceeded MaintenanceJobRunPhase = "succeeded"
+
+	// MaintenanceJobRunPhaseFailed means that the maintenance failed
+	MaintenanceJobRunPhaseFailed MaintenanceJobRunPhase = "failed"
+)
+
+// MaintenanceJobRun is a run of a MaintenanceJob
+type MaintenanceJobRun struct {
+	// The name of the Job running the maintenance
+	JobName string `json:"jobName"`
+
+	// The phase of the run, i.e. `succeeded` or `failed`
+	Phase MaintenanceJobRunPhase `json:"phase"`
+
+	// When the run started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// When the run ended
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// MaintenanceJobStatus defines the observed state of MaintenanceJob
+type MaintenanceJobStatus struct {
+	// The latest time the schedule was checked
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// The latest time a run was scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// The next time a run will be scheduled
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// The latest runs, the most recent first
+	// +optional
+	Runs []MaintenanceJobRun `json:"runs,omitempty"`
+
+	// The error preventing the maintenance from being run, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Operation",type="string",JSONPath=".spec.operation"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Last Run",type="string",JSONPath=".status.runs[0].phase"
+// +kubebuilder:printcolumn:name="Last Run Time",type="date",JSONPath=".status.runs[0].startTime"
+
+// MaintenanceJob is the Schema for the maintenancejobs API
+type MaintenanceJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the MaintenanceJob.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec MaintenanceJobSpec `json:"spec"`
+	// Most recently observed status of the MaintenanceJob. This data may not be up
+	// to date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Status MaintenanceJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MaintenanceJobList contains a list of MaintenanceJob
+type MaintenanceJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of maintenance jobs
+	Items []MaintenanceJob `json:"items"`
+}
+
+// GetHistoryLimit gets the number of runs kept in the status
+func (job *MaintenanceJob) GetHistoryLimit() int {
+	if job.Spec.HistoryLimit > 0 {
+		return int(job.Spec.HistoryLimit)
+	}
+	return 5
+}
+
+// IsRunning checks whether the latest run of the maintenance is
+// still in progress
+func (job *MaintenanceJob) IsRunning() bool {
+	return len(job.Status.Runs) > 0 && job.Status.Runs[0].Phase == MaintenanceJobRunPhaseRunning
+}
+
+func init() {
+	SchemeBuilder.Register(&MaintenanceJob{}, &MaintenanceJobList{})
+}
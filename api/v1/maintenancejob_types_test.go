@@ -0,0 +1,41 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("maintenance jobs", func() {
+	It("keeps five runs by default", func() {
+		job := MaintenanceJob{}
+		Expect(job.GetHistoryLimit()).To(Equal(5))
+		job.Spec.HistoryLimit = 10
+		Expect(job.GetHistoryLimit()).To(Equal(10))
+	})
+
+	It("detects when the latest run is in progress", func() {
+		job := MaintenanceJob{}
+		Expect(job.IsRunning()).To(BeFalse())
+		job.Status.Runs = []MaintenanceJobRun{
+			{JobName: "vacuum-2", Phase: MaintenanceJobRunPhaseRunning},
+			{JobName: "vacuum-1", Phase: MaintenanceJobRunPhaseFailed},
+		}
+		Expect(job.IsRunning()).To(BeTrue())
+	})
+})
@@ -0,0 +1,163 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PgCronJobNamePrefix is the prefix of the names of the jobs that the
+// operator schedules in pg_cron, distinguishing them from the ones
+// created by the users
+const PgCronJobNamePrefix = "cnpg_"
+
+// PgCronJobSpec defines the desired state of PgCronJob
+type PgCronJobSpec struct {
+	// The cluster where the job is scheduled
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The schedule of the job, in the syntax accepted by pg_cron: a cron
+	// expression in the time zone set by `cron.timezone`, i.e.
+	// `0 3 * * *`, or an interval between 1 and 59 seconds, i.e. `30 seconds`
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The SQL command run by the job
+	// +kubebuilder:validation:MinLength=1
+	Command string `json:"command"`
+
+	// The database where the command is run. Defaults to the database
+	// where pg_cron is installed, set by `cron.database_name`
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The role running the command. Superuser roles are refused, unless
+	// `allowSuperuser` is set
+	// +kubebuilder:validation:MinLength=1
+	Username string `json:"username"`
+
+	// Allow the command to be run by a superuser role, like `postgres`
+	// +optional
+	AllowSuperuser bool `json:"allowSuperuser,omitempty"`
+
+	// If the job is suspended or not
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// PgCronJobRunStatus is the status of a run of a pg_cron job, as
+// reported by the `cron.job_run_details` table
+type PgCronJobRunStatus string
+
+const (
+	// PgCronJobRunStatusStarting means that the job is being started
+	PgCronJobRunStatusStarting PgCronJobRunStatus = "starting"
+
+	// PgCronJobRunStatusRunning means that the command of the job is running
+	PgCronJobRunStatusRunning PgCronJobRunStatus = "running"
+
+	// PgCronJobRunStatusSucceeded means that the command of the job succeeded
+	PgCronJobRunStatusSucceeded PgCronJobRunStatus = "succeeded"
+
+	// PgCronJobRunStatusFailed means that the command of the job failed
+	PgCronJobRunStatusFailed PgCronJobRunStatus = "failed"
+)
+
+// PgCronJobRun is a run of a pg_cron job
+type PgCronJobRun struct {
+	// The identifier of the run in pg_cron
+	RunID int64 `json:"runID"`
+
+	// The status of the run, i.e. `succeeded` or `failed`
+	Status PgCronJobRunStatus `json:"status"`
+
+	// The message returned by the command, containing the error
+	// when the run failed
+	// +optional
+	ReturnMessage string `json:"returnMessage,omitempty"`
+
+	// When the run started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// When the run ended
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// PgCronJobStatus defines the observed state of PgCronJob
+type PgCronJobStatus struct {
+	// The identifier of the job in pg_cron
+	// +optional
+	JobID int64 `json:"jobID,omitempty"`
+
+	// The name of the job in pg_cron
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// The latest run of the job
+	// +optional
+	LastRun *PgCronJobRun `json:"lastRun,omitempty"`
+
+	// The error preventing the job from being scheduled, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Last Run",type="string",JSONPath=".status.lastRun.status"
+// +kubebuilder:printcolumn:name="Last Run Time",type="date",JSONPath=".status.lastRun.startTime"
+
+// PgCronJob is the Schema for the pgcronjobs API
+type PgCronJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the PgCronJob.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec PgCronJobSpec `json:"spec"`
+	// Most recently observed status of the PgCronJob. This data may not be up
+	// to date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Status PgCronJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PgCronJobList contains a list of PgCronJob
+type PgCronJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of pg_cron jobs
+	Items []PgCronJob `json:"items"`
+}
+
+// GetJobName gets the name of the job in pg_cron
+func (job *PgCronJob) GetJobName() string {
+	return PgCronJobNamePrefix + job.Name
+}
+
+func init() {
+	SchemeBuilder.Register(&PgCronJob{}, &PgCronJobList{})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// pgCronJobLog is for logging in this package.
+var pgCronJobLog = log.WithName("pgcronjob-resource").WithValues("version", "v1")
+
+// SetupWebhookWithManager setup the webhook inside the controller manager
+func (r *PgCronJob) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-pgcronjob,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=pgcronjobs,versions=v1,name=vpgcronjob.kb.io,sideEffects=None
+
+var _ webhook.Validator = &PgCronJob{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *PgCronJob) ValidateCreate() error {
+	pgCronJobLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+
+	allErrs := r.Validate()
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "PgCronJob"},
+		r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *PgCronJob) ValidateUpdate(old runtime.Object) error {
+	pgCronJobLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+
+	allErrs := r.Validate()
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "PgCronJob"},
+		r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *PgCronJob) ValidateDelete() error {
+	pgCronJobLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+	return nil
+}
+
+// Validate validates the configuration of a PgCronJob, returning
+// a list of errors
+func (r *PgCronJob) Validate() (allErrs field.ErrorList) {
+	allErrs = append(allErrs, r.validateUsername()...)
+	return allErrs
+}
+
+// validateUsername ensures that the role running the command is set, and
+// that the job doesn't run as the superuser unless explicitly allowed.
+// The other superuser roles are refused by the instance manager, which
+// can look them up in the database
+func (r *PgCronJob) validateUsername() field.ErrorList {
+	var result field.ErrorList
+
+	switch {
+	case r.Spec.Username == "":
+		result = append(result,
+			field.Required(
+				field.NewPath("spec", "username"),
+				"the role running the command is required"))
+	case r.Spec.Username == "postgres" && !r.Spec.AllowSuperuser:
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "username"),
+				r.Spec.Username,
+				"the command can be run by a superuser only if allowSuperuser is set"))
+	}
+
+	return result
+}
@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_cron job validation", func() {
+	newJob := func(username string) *PgCronJob {
+		return &PgCronJob{
+			Spec: PgCronJobSpec{
+				Cluster:  LocalObjectReference{Name: "cluster-example"},
+				Schedule: "0 3 * * *",
+				Command:  "VACUUM orders",
+				Username: username,
+			},
+		}
+	}
+
+	It("doesn't complain about a job run by a regular role", func() {
+		Expect(newJob("app").Validate()).To(BeEmpty())
+	})
+
+	It("complains if the role running the command is missing", func() {
+		Expect(newJob("").Validate()).To(HaveLen(1))
+	})
+
+	It("complains if the job runs as the superuser without being allowed to", func() {
+		job := newJob("postgres")
+		Expect(job.Validate()).To(HaveLen(1))
+		Expect(job.ValidateCreate()).ToNot(Succeed())
+
+		job.Spec.AllowSuperuser = true
+		Expect(job.Validate()).To(BeEmpty())
+		Expect(job.ValidateCreate()).To(Succeed())
+	})
+})
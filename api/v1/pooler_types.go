@@ -135,6 +135,15 @@ type PgBouncerSpec struct {
 	// the CNPG documentation for a list of options you can configure
 	Parameters map[string]string `json:"parameters,omitempty"`
 
+	// The users to be authenticated via their client certificate, whose
+	// common name must match the user name. The certificates must be
+	// signed by the client CA of the cluster, and the users must be
+	// listed in the `poolerCertificateUsers` of the cluster too. This
+	// requires the authentication query user to be authenticated via
+	// a TLS certificate, as in the automatic CNPG Cluster integration
+	// +optional
+	ClientCertificateUsers []string `json:"clientCertificateUsers,omitempty"`
+
 	// When set to `true`, PgBouncer will disconnect from the PostgreSQL
 	// server, first waiting for all queries to complete, and pause all new
 	// client connections until this value is set to `false` (default). Internally,
@@ -154,8 +163,30 @@ type PoolerStatus struct {
 	Secrets *PoolerSecrets `json:"secrets,omitempty"`
 	// The number of pods trying to be scheduled
 	Instances int32 `json:"instances,omitempty"`
+	// Conditions for the pooler object
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// PoolerConditionType defines types of pooler conditions
+type PoolerConditionType string
+
+const (
+	// PoolerConditionReady represents whether every requested
+	// instance of the pooler is available
+	PoolerConditionReady PoolerConditionType = "Ready"
+)
+
+const (
+	// ConditionReasonPoolerReady means that every requested instance
+	// of the pooler is available
+	ConditionReasonPoolerReady ConditionReason = "PoolerIsReady"
+
+	// ConditionReasonPoolerNotReady means that some requested instances
+	// of the pooler are not available yet
+	ConditionReasonPoolerNotReady ConditionReason = "PoolerIsNotReady"
+)
+
 // PoolerSecrets contains the versions of all the secrets used
 type PoolerSecrets struct {
 	// The server TLS secret version
@@ -144,7 +144,12 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 				"", "must specify an existing auth query secret when providing an auth query secret"))
 	}
 
-	result = append(result, r.validatePgbouncerGenericParameters()...)
+	if r.Spec.PgBouncer != nil {
+		result = append(result, r.validatePgbouncerGenericParameters()...)
+		result = append(result, validateCertificateUsers(
+			field.NewPath("spec", "pgbouncer", "clientCertificateUsers"),
+			r.Spec.PgBouncer.ClientCertificateUsers)...)
+	}
 
 	return result
 }
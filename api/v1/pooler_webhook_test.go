@@ -128,4 +128,15 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("complains when a user authenticated via certificate is reserved", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					ClientCertificateUsers: []string{"app", PGBouncerPoolerUserName},
+				},
+			},
+		}
+		Expect(pooler.validatePgBouncer()).To(HaveLen(1))
+	})
 })
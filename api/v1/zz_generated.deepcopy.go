@@ -28,6 +28,49 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalService) DeepCopyInto(out *AdditionalService) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.ServiceTemplateSpec.DeepCopyInto(&out.ServiceTemplateSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalService.
+func (in *AdditionalService) DeepCopy() *AdditionalService {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminSessionPolicy) DeepCopyInto(out *AdminSessionPolicy) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminSessionPolicy.
+func (in *AdminSessionPolicy) DeepCopy() *AdminSessionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminSessionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AffinityConfiguration) DeepCopyInto(out *AffinityConfiguration) {
 	*out = *in
@@ -245,6 +288,25 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(InstanceID)
 		**out = **in
 	}
+	if in.WALChecksums != nil {
+		in, out := &in.WALChecksums, &out.WALChecksums
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -257,6 +319,27 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerification) DeepCopyInto(out *BackupVerification) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerification.
+func (in *BackupVerification) DeepCopy() *BackupVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BarmanCredentials) DeepCopyInto(out *BarmanCredentials) {
 	*out = *in
@@ -299,7 +382,7 @@ func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreCon
 	if in.Wal != nil {
 		in, out := &in.Wal, &out.Wal
 		*out = new(WalBackupConfiguration)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
@@ -350,6 +433,11 @@ func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 		*out = new(BootstrapPgBaseBackup)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExistingVolume != nil {
+		in, out := &in.ExistingVolume, &out.ExistingVolume
+		*out = new(BootstrapExistingVolume)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapConfiguration.
@@ -362,6 +450,26 @@ func (in *BootstrapConfiguration) DeepCopy() *BootstrapConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapExistingVolume) DeepCopyInto(out *BootstrapExistingVolume) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapExistingVolume.
+func (in *BootstrapExistingVolume) DeepCopy() *BootstrapExistingVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapExistingVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapInitDB) DeepCopyInto(out *BootstrapInitDB) {
 	*out = *in
@@ -395,6 +503,16 @@ func (in *BootstrapInitDB) DeepCopyInto(out *BootstrapInitDB) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PostInitSQLRefs != nil {
+		in, out := &in.PostInitSQLRefs, &out.PostInitSQLRefs
+		*out = new(PostInitApplicationSQLRefs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostInitTemplateSQLRefs != nil {
+		in, out := &in.PostInitTemplateSQLRefs, &out.PostInitTemplateSQLRefs
+		*out = new(PostInitApplicationSQLRefs)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Import != nil {
 		in, out := &in.Import, &out.Import
 		*out = new(Import)
@@ -467,6 +585,41 @@ func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CascadingReplicationConfiguration) DeepCopyInto(out *CascadingReplicationConfiguration) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]CascadingReplicationInstance, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CascadingReplicationConfiguration.
+func (in *CascadingReplicationConfiguration) DeepCopy() *CascadingReplicationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CascadingReplicationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CascadingReplicationInstance) DeepCopyInto(out *CascadingReplicationInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CascadingReplicationInstance.
+func (in *CascadingReplicationInstance) DeepCopy() *CascadingReplicationInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(CascadingReplicationInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
 	*out = *in
@@ -475,6 +628,16 @@ func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CertificateDuration != nil {
+		in, out := &in.CertificateDuration, &out.CertificateDuration
+		*out = new(int)
+		**out = **in
+	}
+	if in.ExpiringCheckThreshold != nil {
+		in, out := &in.ExpiringCheckThreshold, &out.ExpiringCheckThreshold
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesConfiguration.
@@ -510,6 +673,31 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneThrottlingConfiguration) DeepCopyInto(out *CloneThrottlingConfiguration) {
+	*out = *in
+	if in.Nice != nil {
+		in, out := &in.Nice, &out.Nice
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IOPriority != nil {
+		in, out := &in.IOPriority, &out.IOPriority
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneThrottlingConfiguration.
+func (in *CloneThrottlingConfiguration) DeepCopy() *CloneThrottlingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneThrottlingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -537,6 +725,119 @@ func (in *Cluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHistory) DeepCopyInto(out *ClusterHistory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHistory.
+func (in *ClusterHistory) DeepCopy() *ClusterHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterHistory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHistoryEntry) DeepCopyInto(out *ClusterHistoryEntry) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHistoryEntry.
+func (in *ClusterHistoryEntry) DeepCopy() *ClusterHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHistoryList) DeepCopyInto(out *ClusterHistoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHistoryList.
+func (in *ClusterHistoryList) DeepCopy() *ClusterHistoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHistoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterHistoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHistorySpec) DeepCopyInto(out *ClusterHistorySpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHistorySpec.
+func (in *ClusterHistorySpec) DeepCopy() *ClusterHistorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHistorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHistoryStatus) DeepCopyInto(out *ClusterHistoryStatus) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]ClusterHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHistoryStatus.
+func (in *ClusterHistoryStatus) DeepCopy() *ClusterHistoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHistoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
@@ -577,12 +878,27 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EmbeddedObjectMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MinorVersionPinning != nil {
+		in, out := &in.MinorVersionPinning, &out.MinorVersionPinning
+		*out = new(MinorVersionPinning)
+		**out = **in
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -591,7 +907,7 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	if in.ReplicaCluster != nil {
 		in, out := &in.ReplicaCluster, &out.ReplicaCluster
 		*out = new(ReplicaClusterConfiguration)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.SuperuserSecret != nil {
 		in, out := &in.SuperuserSecret, &out.SuperuserSecret
@@ -603,6 +919,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CredentialsFiles != nil {
+		in, out := &in.CredentialsFiles, &out.CredentialsFiles
+		*out = new(CredentialsFilesConfiguration)
+		**out = **in
+	}
 	if in.Certificates != nil {
 		in, out := &in.Certificates, &out.Certificates
 		*out = new(CertificatesConfiguration)
@@ -619,8 +940,35 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SwitchoverDrain != nil {
+		in, out := &in.SwitchoverDrain, &out.SwitchoverDrain
+		*out = new(SwitchoverDrainConfiguration)
+		**out = **in
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.JobResources != nil {
+		in, out := &in.JobResources, &out.JobResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceGroups != nil {
+		in, out := &in.InstanceGroups, &out.InstanceGroups
+		*out = make([]InstanceGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EphemeralVolumesSizeLimit != nil {
+		in, out := &in.EphemeralVolumesSizeLimit, &out.EphemeralVolumesSizeLimit
+		*out = new(EphemeralVolumesSizeLimitConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
@@ -631,11 +979,31 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(NodeMaintenanceWindow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CrashLoopRemediation != nil {
+		in, out := &in.CrashLoopRemediation, &out.CrashLoopRemediation
+		*out = new(CrashLoopRemediationConfiguration)
+		**out = **in
+	}
+	if in.DataCorruption != nil {
+		in, out := &in.DataCorruption, &out.DataCorruption
+		*out = new(DataCorruptionConfiguration)
+		**out = **in
+	}
+	if in.StorageStall != nil {
+		in, out := &in.StorageStall, &out.StorageStall
+		*out = new(StorageStallConfiguration)
+		**out = **in
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExternalClusters != nil {
 		in, out := &in.ExternalClusters, &out.ExternalClusters
 		*out = make([]ExternalCluster, len(*in))
@@ -643,12 +1011,42 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
-func (in *ClusterSpec) DeepCopy() *ClusterSpec {
-	if in == nil {
-		return nil
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(InstancePodTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMesh != nil {
+		in, out := &in.ServiceMesh, &out.ServiceMesh
+		*out = new(ServiceMeshConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectedVolumeTemplate != nil {
+		in, out := &in.ProjectedVolumeTemplate, &out.ProjectedVolumeTemplate
+		*out = new(corev1.ProjectedVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(ManagedConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
+func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+	if in == nil {
+		return nil
 	}
 	out := new(ClusterSpec)
 	in.DeepCopyInto(out)
@@ -726,6 +1124,48 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.OnlineImport != nil {
+		in, out := &in.OnlineImport, &out.OnlineImport
+		*out = new(OnlineImportStatus)
+		**out = **in
+	}
+	if in.MD5PasswordRoles != nil {
+		in, out := &in.MD5PasswordRoles, &out.MD5PasswordRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.ImageDigest != nil {
+		in, out := &in.ImageDigest, &out.ImageDigest
+		*out = new(ImageDigestStatus)
+		**out = **in
+	}
+	if in.PendingRestartParameters != nil {
+		in, out := &in.PendingRestartParameters, &out.PendingRestartParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataCorruptedInstances != nil {
+		in, out := &in.DataCorruptedInstances, &out.DataCorruptedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FencedInstances != nil {
+		in, out := &in.FencedInstances, &out.FencedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceGroups != nil {
+		in, out := &in.InstanceGroups, &out.InstanceGroups
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -776,6 +1216,36 @@ func (in *ConfigMapResourceVersion) DeepCopy() *ConfigMapResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashLoopRemediationConfiguration) DeepCopyInto(out *CrashLoopRemediationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashLoopRemediationConfiguration.
+func (in *CrashLoopRemediationConfiguration) DeepCopy() *CrashLoopRemediationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashLoopRemediationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsFilesConfiguration) DeepCopyInto(out *CredentialsFilesConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsFilesConfiguration.
+func (in *CredentialsFilesConfiguration) DeepCopy() *CredentialsFilesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsFilesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 	*out = *in
@@ -796,6 +1266,46 @@ func (in *DataBackupConfiguration) DeepCopy() *DataBackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataCorruptionConfiguration) DeepCopyInto(out *DataCorruptionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataCorruptionConfiguration.
+func (in *DataCorruptionConfiguration) DeepCopy() *DataCorruptionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DataCorruptionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPolicy) DeepCopyInto(out *DeletionPolicy) {
+	*out = *in
+	if in.FinalBackup != nil {
+		in, out := &in.FinalBackup, &out.FinalBackup
+		*out = new(FinalBackupConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArchiveWAL != nil {
+		in, out := &in.ArchiveWAL, &out.ArchiveWAL
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicy.
+func (in *DeletionPolicy) DeepCopy() *DeletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -825,6 +1335,56 @@ func (in *EmbeddedObjectMetadata) DeepCopy() *EmbeddedObjectMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralVolumesSizeLimitConfiguration) DeepCopyInto(out *EphemeralVolumesSizeLimitConfiguration) {
+	*out = *in
+	if in.Shm != nil {
+		in, out := &in.Shm, &out.Shm
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TemporaryData != nil {
+		in, out := &in.TemporaryData, &out.TemporaryData
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralVolumesSizeLimitConfiguration.
+func (in *EphemeralVolumesSizeLimitConfiguration) DeepCopy() *EphemeralVolumesSizeLimitConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralVolumesSizeLimitConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionConfiguration) DeepCopyInto(out *ExtensionConfiguration) {
+	*out = *in
+	if in.ExtensionControlPath != nil {
+		in, out := &in.ExtensionControlPath, &out.ExtensionControlPath
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DynamicLibraryPath != nil {
+		in, out := &in.DynamicLibraryPath, &out.DynamicLibraryPath
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionConfiguration.
+func (in *ExtensionConfiguration) DeepCopy() *ExtensionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
@@ -872,6 +1432,26 @@ func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalBackupConfiguration) DeepCopyInto(out *FinalBackupConfiguration) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalBackupConfiguration.
+func (in *FinalBackupConfiguration) DeepCopy() *FinalBackupConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalBackupConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
 	*out = *in
@@ -892,6 +1472,41 @@ func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageDigestStatus) DeepCopyInto(out *ImageDigestStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDigestStatus.
+func (in *ImageDigestStatus) DeepCopy() *ImageDigestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageDigestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerificationConfiguration) DeepCopyInto(out *ImageVerificationConfiguration) {
+	*out = *in
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerificationConfiguration.
+func (in *ImageVerificationConfiguration) DeepCopy() *ImageVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Import) DeepCopyInto(out *Import) {
 	*out = *in
@@ -911,6 +1526,16 @@ func (in *Import) DeepCopyInto(out *Import) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PostImportAnalyze != nil {
+		in, out := &in.PostImportAnalyze, &out.PostImportAnalyze
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Online != nil {
+		in, out := &in.Online, &out.Online
+		*out = new(OnlineImport)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
@@ -939,120 +1564,724 @@ func (in *ImportSource) DeepCopy() *ImportSource {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceID) DeepCopyInto(out *InstanceID) {
+func (in *InstanceCSIVolume) DeepCopyInto(out *InstanceCSIVolume) {
 	*out = *in
+	in.CSIVolumeSource.DeepCopyInto(&out.CSIVolumeSource)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
-func (in *InstanceID) DeepCopy() *InstanceID {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceCSIVolume.
+func (in *InstanceCSIVolume) DeepCopy() *InstanceCSIVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceID)
+	out := new(InstanceCSIVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
+func (in *InstanceGroup) DeepCopyInto(out *InstanceGroup) {
 	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
-func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceGroup.
+func (in *InstanceGroup) DeepCopy() *InstanceGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceReportedState)
+	out := new(InstanceGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+func (in *InstanceID) DeepCopyInto(out *InstanceID) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
-func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
+func (in *InstanceID) DeepCopy() *InstanceID {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindAsAuth)
+	out := new(InstanceID)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+func (in *InstanceOverride) DeepCopyInto(out *InstanceOverride) {
 	*out = *in
-	if in.BindPassword != nil {
-		in, out := &in.BindPassword, &out.BindPassword
-		*out = new(corev1.SecretKeySelector)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
-func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceOverride.
+func (in *InstanceOverride) DeepCopy() *InstanceOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindSearchAuth)
+	out := new(InstanceOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+func (in *InstancePodTemplate) DeepCopyInto(out *InstancePodTemplate) {
 	*out = *in
-	if in.BindAsAuth != nil {
-		in, out := &in.BindAsAuth, &out.BindAsAuth
-		*out = new(LDAPBindAsAuth)
-		**out = **in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.BindSearchAuth != nil {
-		in, out := &in.BindSearchAuth, &out.BindSearchAuth
-		*out = new(LDAPBindSearchAuth)
-		(*in).DeepCopyInto(*out)
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProjectedVolumes != nil {
+		in, out := &in.ProjectedVolumes, &out.ProjectedVolumes
+		*out = make([]InstanceProjectedVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CSIVolumes != nil {
+		in, out := &in.CSIVolumes, &out.CSIVolumes
+		*out = make([]InstanceCSIVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
-func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancePodTemplate.
+func (in *InstancePodTemplate) DeepCopy() *InstancePodTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPConfig)
+	out := new(InstancePodTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+func (in *InstanceProjectedVolume) DeepCopyInto(out *InstanceProjectedVolume) {
 	*out = *in
+	in.ProjectedVolumeSource.DeepCopyInto(&out.ProjectedVolumeSource)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
-func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceProjectedVolume.
+func (in *InstanceProjectedVolume) DeepCopy() *InstanceProjectedVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalObjectReference)
+	out := new(InstanceProjectedVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
 	*out = *in
-	if in.DisableDefaultQueries != nil {
-		in, out := &in.DisableDefaultQueries, &out.DisableDefaultQueries
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
+func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+	if in == nil {
+		return nil
 	}
-	if in.CustomQueriesConfigMap != nil {
-		in, out := &in.CustomQueriesConfigMap, &out.CustomQueriesConfigMap
+	out := new(InstanceReportedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
+func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindAsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+	*out = *in
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
+func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindSearchAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+	*out = *in
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.BindAsAuth != nil {
+		in, out := &in.BindAsAuth, &out.BindAsAuth
+		*out = new(LDAPBindAsAuth)
+		**out = **in
+	}
+	if in.BindSearchAuth != nil {
+		in, out := &in.BindSearchAuth, &out.BindSearchAuth
+		*out = new(LDAPBindSearchAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
+func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
+func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingConfiguration) DeepCopyInto(out *LoggingConfiguration) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Loggers != nil {
+		in, out := &in.Loggers, &out.Loggers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingConfiguration.
+func (in *LoggingConfiguration) DeepCopy() *LoggingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceJob) DeepCopyInto(out *MaintenanceJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceJob.
+func (in *MaintenanceJob) DeepCopy() *MaintenanceJob {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceJobList) DeepCopyInto(out *MaintenanceJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaintenanceJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceJobList.
+func (in *MaintenanceJobList) DeepCopy() *MaintenanceJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceJobRun) DeepCopyInto(out *MaintenanceJobRun) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceJobRun.
+func (in *MaintenanceJobRun) DeepCopy() *MaintenanceJobRun {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceJobRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceJobSpec) DeepCopyInto(out *MaintenanceJobSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceJobSpec.
+func (in *MaintenanceJobSpec) DeepCopy() *MaintenanceJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceJobStatus) DeepCopyInto(out *MaintenanceJobStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]MaintenanceJobRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceJobStatus.
+func (in *MaintenanceJobStatus) DeepCopy() *MaintenanceJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]ManagedDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdminSessionPolicy != nil {
+		in, out := &in.AdminSessionPolicy, &out.AdminSessionPolicy
+		*out = new(AdminSessionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ManagedServices)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
+func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedDatabase) DeepCopyInto(out *ManagedDatabase) {
+	*out = *in
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]ManagedGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ManagedDatabaseExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ForeignDataWrappers != nil {
+		in, out := &in.ForeignDataWrappers, &out.ForeignDataWrappers
+		*out = make([]ManagedForeignDataWrapper, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ForeignServers != nil {
+		in, out := &in.ForeignServers, &out.ForeignServers
+		*out = make([]ManagedForeignServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedDatabase.
+func (in *ManagedDatabase) DeepCopy() *ManagedDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedDatabaseExtension) DeepCopyInto(out *ManagedDatabaseExtension) {
+	*out = *in
+	if in.SharedPreloadLibraries != nil {
+		in, out := &in.SharedPreloadLibraries, &out.SharedPreloadLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedDatabaseExtension.
+func (in *ManagedDatabaseExtension) DeepCopy() *ManagedDatabaseExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedDatabaseExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedForeignDataWrapper) DeepCopyInto(out *ManagedForeignDataWrapper) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedForeignDataWrapper.
+func (in *ManagedForeignDataWrapper) DeepCopy() *ManagedForeignDataWrapper {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedForeignDataWrapper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedForeignServer) DeepCopyInto(out *ManagedForeignServer) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]ManagedUserMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedForeignServer.
+func (in *ManagedForeignServer) DeepCopy() *ManagedForeignServer {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedForeignServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedGrant) DeepCopyInto(out *ManagedGrant) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]DatabasePrivilege, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schemas != nil {
+		in, out := &in.Schemas, &out.Schemas
+		*out = make([]ManagedSchemaGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedGrant.
+func (in *ManagedGrant) DeepCopy() *ManagedGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedSchemaGrant) DeepCopyInto(out *ManagedSchemaGrant) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]SchemaPrivilege, len(*in))
+		copy(*out, *in)
+	}
+	if in.TablePrivileges != nil {
+		in, out := &in.TablePrivileges, &out.TablePrivileges
+		*out = make([]TablePrivilege, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedSchemaGrant.
+func (in *ManagedSchemaGrant) DeepCopy() *ManagedSchemaGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedSchemaGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]ServiceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make([]AdditionalService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PerInstance != nil {
+		in, out := &in.PerInstance, &out.PerInstance
+		*out = new(ServiceTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedServices.
+func (in *ManagedServices) DeepCopy() *ManagedServices {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedUserMapping) DeepCopyInto(out *ManagedUserMapping) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedUserMapping.
+func (in *ManagedUserMapping) DeepCopy() *ManagedUserMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedUserMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinorVersionPinning) DeepCopyInto(out *MinorVersionPinning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinorVersionPinning.
+func (in *MinorVersionPinning) DeepCopy() *MinorVersionPinning {
+	if in == nil {
+		return nil
+	}
+	out := new(MinorVersionPinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+	*out = *in
+	if in.DisableDefaultQueries != nil {
+		in, out := &in.DisableDefaultQueries, &out.DisableDefaultQueries
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CustomQueriesConfigMap != nil {
+		in, out := &in.CustomQueriesConfigMap, &out.CustomQueriesConfigMap
 		*out = make([]ConfigMapKeySelector, len(*in))
 		copy(*out, *in)
 	}
@@ -1061,6 +2290,16 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 		*out = make([]SecretKeySelector, len(*in))
 		copy(*out, *in)
 	}
+	if in.PgStatStatements != nil {
+		in, out := &in.PgStatStatements, &out.PgStatStatements
+		*out = new(PgStatStatementsConfiguration)
+		**out = **in
+	}
+	if in.WaitEventSampling != nil {
+		in, out := &in.WaitEventSampling, &out.WaitEventSampling
+		*out = new(WaitEventSamplingConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -1073,6 +2312,26 @@ func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfiguration) DeepCopyInto(out *NetworkPolicyConfiguration) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfiguration.
+func (in *NetworkPolicyConfiguration) DeepCopy() *NetworkPolicyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
 	*out = *in
@@ -1083,80 +2342,248 @@ func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMaintenanceWindow.
-func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMaintenanceWindow.
+func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnlineImport) DeepCopyInto(out *OnlineImport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineImport.
+func (in *OnlineImport) DeepCopy() *OnlineImport {
+	if in == nil {
+		return nil
+	}
+	out := new(OnlineImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnlineImportStatus) DeepCopyInto(out *OnlineImportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineImportStatus.
+func (in *OnlineImportStatus) DeepCopy() *OnlineImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OnlineImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
+func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerIntegrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+	*out = *in
+	out.AuthQuery = in.AuthQuery
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
+func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+	*out = *in
+	if in.AuthQuerySecret != nil {
+		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClientCertificateUsers != nil {
+		in, out := &in.ClientCertificateUsers, &out.ClientCertificateUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
+func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCronJob) DeepCopyInto(out *PgCronJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCronJob.
+func (in *PgCronJob) DeepCopy() *PgCronJob {
+	if in == nil {
+		return nil
+	}
+	out := new(PgCronJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PgCronJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCronJobList) DeepCopyInto(out *PgCronJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PgCronJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCronJobList.
+func (in *PgCronJobList) DeepCopy() *PgCronJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(PgCronJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PgCronJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCronJobRun) DeepCopyInto(out *PgCronJobRun) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCronJobRun.
+func (in *PgCronJobRun) DeepCopy() *PgCronJobRun {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeMaintenanceWindow)
+	out := new(PgCronJobRun)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+func (in *PgCronJobSpec) DeepCopyInto(out *PgCronJobSpec) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.Cluster = in.Cluster
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
-func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCronJobSpec.
+func (in *PgCronJobSpec) DeepCopy() *PgCronJobSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerIntegrationStatus)
+	out := new(PgCronJobSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+func (in *PgCronJobStatus) DeepCopyInto(out *PgCronJobStatus) {
 	*out = *in
-	out.AuthQuery = in.AuthQuery
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = new(PgCronJobRun)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
-func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCronJobStatus.
+func (in *PgCronJobStatus) DeepCopy() *PgCronJobStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerSecrets)
+	out := new(PgCronJobStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+func (in *PgStatStatementsConfiguration) DeepCopyInto(out *PgStatStatementsConfiguration) {
 	*out = *in
-	if in.AuthQuerySecret != nil {
-		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
-		*out = new(LocalObjectReference)
-		**out = **in
-	}
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Paused != nil {
-		in, out := &in.Paused, &out.Paused
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
-func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgStatStatementsConfiguration.
+func (in *PgStatStatementsConfiguration) DeepCopy() *PgStatStatementsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerSpec)
+	out := new(PgStatStatementsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1360,6 +2787,13 @@ func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
 		*out = new(PoolerSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
@@ -1412,6 +2846,16 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PgIdent != nil {
+		in, out := &in.PgIdent, &out.PgIdent
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PoolerCertificateUsers != nil {
+		in, out := &in.PoolerCertificateUsers, &out.PoolerCertificateUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
 	if in.AdditionalLibraries != nil {
 		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
@@ -1423,6 +2867,28 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = new(LDAPConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(PostgresTLSConfiguration)
+		**out = **in
+	}
+	if in.SCRAMMigration != nil {
+		in, out := &in.SCRAMMigration, &out.SCRAMMigration
+		*out = new(SCRAMMigrationConfiguration)
+		**out = **in
+	}
+	if in.EnableAlterSystem != nil {
+		in, out := &in.EnableAlterSystem, &out.EnableAlterSystem
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
@@ -1435,6 +2901,71 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresTLSConfiguration) DeepCopyInto(out *PostgresTLSConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresTLSConfiguration.
+func (in *PostgresTLSConfiguration) DeepCopy() *PostgresTLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresTLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeConfiguration) DeepCopyInto(out *ProbeConfiguration) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]HealthCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeConfiguration.
+func (in *ProbeConfiguration) DeepCopy() *ProbeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbesConfiguration) DeepCopyInto(out *ProbesConfiguration) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(StartupProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfiguration.
+func (in *ProbesConfiguration) DeepCopy() *ProbesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	*out = *in
@@ -1463,6 +2994,11 @@ func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
 	*out = *in
+	if in.ExportReadOnlyService != nil {
+		in, out := &in.ExportReadOnlyService, &out.ExportReadOnlyService
+		*out = new(ServiceExportConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaClusterConfiguration.
@@ -1475,6 +3011,36 @@ func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationConfiguration) DeepCopyInto(out *ReplicationConfiguration) {
+	*out = *in
+	if in.Cascading != nil {
+		in, out := &in.Cascading, &out.Cascading
+		*out = new(CascadingReplicationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloneThrottling != nil {
+		in, out := &in.CloneThrottling, &out.CloneThrottling
+		*out = new(CloneThrottlingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxStandbyLag != nil {
+		in, out := &in.MaxStandbyLag, &out.MaxStandbyLag
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConfiguration.
+func (in *ReplicationConfiguration) DeepCopy() *ReplicationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
@@ -1561,6 +3127,21 @@ func (in *S3Credentials) DeepCopy() *S3Credentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCRAMMigrationConfiguration) DeepCopyInto(out *SCRAMMigrationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCRAMMigrationConfiguration.
+func (in *SCRAMMigrationConfiguration) DeepCopy() *SCRAMMigrationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SCRAMMigrationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	*out = *in
@@ -1714,6 +3295,13 @@ func (in *SecretsResourceVersion) DeepCopyInto(out *SecretsResourceVersion) {
 			(*out)[key] = val
 		}
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsResourceVersion.
@@ -1726,6 +3314,96 @@ func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceExportConfiguration) DeepCopyInto(out *ServiceExportConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExportConfiguration.
+func (in *ServiceExportConfiguration) DeepCopy() *ServiceExportConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceExportConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshConfiguration) DeepCopyInto(out *ServiceMeshConfiguration) {
+	*out = *in
+	if in.JobAnnotations != nil {
+		in, out := &in.JobAnnotations, &out.JobAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshConfiguration.
+func (in *ServiceMeshConfiguration) DeepCopy() *ServiceMeshConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
+	*out = *in
+	in.ServiceTemplateSpec.DeepCopyInto(&out.ServiceTemplateSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplate.
+func (in *ServiceTemplate) DeepCopy() *ServiceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTemplateSpec) DeepCopyInto(out *ServiceTemplateSpec) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplateSpec.
+func (in *ServiceTemplateSpec) DeepCopy() *ServiceTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupProbeConfiguration) DeepCopyInto(out *StartupProbeConfiguration) {
+	*out = *in
+	in.ProbeConfiguration.DeepCopyInto(&out.ProbeConfiguration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupProbeConfiguration.
+func (in *StartupProbeConfiguration) DeepCopy() *StartupProbeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupProbeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -1756,6 +3434,36 @@ func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageStallConfiguration) DeepCopyInto(out *StorageStallConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStallConfiguration.
+func (in *StorageStallConfiguration) DeepCopy() *StorageStallConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageStallConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverDrainConfiguration) DeepCopyInto(out *SwitchoverDrainConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverDrainConfiguration.
+func (in *SwitchoverDrainConfiguration) DeepCopy() *SwitchoverDrainConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverDrainConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
 	*out = *in
@@ -1808,9 +3516,49 @@ func (in *Topology) DeepCopy() *Topology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitEventSamplingConfiguration) DeepCopyInto(out *WaitEventSamplingConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitEventSamplingConfiguration.
+func (in *WaitEventSamplingConfiguration) DeepCopy() *WaitEventSamplingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitEventSamplingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalArchiveHook) DeepCopyInto(out *WalArchiveHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalArchiveHook.
+func (in *WalArchiveHook) DeepCopy() *WalArchiveHook {
+	if in == nil {
+		return nil
+	}
+	out := new(WalArchiveHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WalBackupConfiguration) DeepCopyInto(out *WalBackupConfiguration) {
 	*out = *in
+	if in.ArchiveHook != nil {
+		in, out := &in.ArchiveHook, &out.ArchiveHook
+		*out = new(WalArchiveHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalBackupConfiguration.
@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// legacyFieldManager is the field manager of the changes made by the older
+// versions of the operator, which was taken from the name of its executable
+const legacyFieldManager = "manager"
+
+// applyObject creates or updates the passed object via server-side apply,
+// taking the ownership of every field it sets. The fields set by other
+// controllers are kept, and the ones not set anymore by the operator are
+// removed. The passed object is updated with the one stored in Kubernetes
+func applyObject(ctx context.Context, cli client.Client, obj client.Object) error {
+	// The apply request must carry the type of the object
+	gvk, err := apiutil.GVKForObject(obj, cli.Scheme())
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	if err := upgradeManagedFields(ctx, cli, obj); err != nil {
+		return err
+	}
+
+	return cli.Patch(ctx, obj, client.Apply, client.FieldOwner(utils.FieldManager), client.ForceOwnership)
+}
+
+// upgradeManagedFields moves the ownership of the fields set via update
+// requests by the older versions of the operator to its server-side apply
+// entry, like the csaupgrade package of client-go does. Otherwise, being
+// still owned by another field manager, the fields not set anymore by the
+// operator would never be removed. This only happens the first time an
+// existing object is applied, as the older entry is removed
+func upgradeManagedFields(ctx context.Context, cli client.Client, obj client.Object) error {
+	liveObject, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(obj), liveObject); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	managedFields, upgraded, err := getUpgradedManagedFields(liveObject.GetManagedFields())
+	if err != nil || !upgraded {
+		return err
+	}
+
+	// The resource version is tested not to overwrite the changes made
+	// to the object after it was read
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": liveObject.GetResourceVersion()},
+		{"op": "replace", "path": "/metadata/managedFields", "value": managedFields},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Moving the fields set by an older version of the operator to server-side apply",
+		"kind", obj.GetObjectKind().GroupVersionKind().Kind,
+		"name", obj.GetName())
+	return cli.Patch(ctx, liveObject, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// getUpgradedManagedFields merges the fields owned by the update entries of
+// the older versions of the operator into the server-side apply entry of
+// the operator, creating it if needed, and reports whether any was found
+func getUpgradedManagedFields(
+	managedFields []metav1.ManagedFieldsEntry,
+) ([]metav1.ManagedFieldsEntry, bool, error) {
+	var result []metav1.ManagedFieldsEntry
+	var legacyEntry *metav1.ManagedFieldsEntry
+	ownedFields := fieldpath.NewSet()
+	applyEntryIdx := -1
+
+	for idx := range managedFields {
+		entry := managedFields[idx]
+		switch {
+		case entry.Manager == legacyFieldManager &&
+			entry.Operation == metav1.ManagedFieldsOperationUpdate &&
+			entry.Subresource == "":
+			fields, err := decodeManagedFields(entry)
+			if err != nil {
+				return nil, false, err
+			}
+			ownedFields = ownedFields.Union(fields)
+			legacyEntry = &entry
+
+		case entry.Manager == utils.FieldManager &&
+			entry.Operation == metav1.ManagedFieldsOperationApply &&
+			entry.Subresource == "":
+			fields, err := decodeManagedFields(entry)
+			if err != nil {
+				return nil, false, err
+			}
+			ownedFields = ownedFields.Union(fields)
+			applyEntryIdx = len(result)
+			result = append(result, entry)
+
+		default:
+			result = append(result, entry)
+		}
+	}
+
+	if legacyEntry == nil {
+		return managedFields, false, nil
+	}
+
+	if applyEntryIdx == -1 {
+		applyEntryIdx = len(result)
+		result = append(result, metav1.ManagedFieldsEntry{
+			Manager:    utils.FieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: legacyEntry.APIVersion,
+			Time:       legacyEntry.Time,
+			FieldsType: "FieldsV1",
+		})
+	}
+
+	encodedFields, err := ownedFields.ToJSON()
+	if err != nil {
+		return nil, false, err
+	}
+	result[applyEntryIdx].FieldsV1 = &metav1.FieldsV1{Raw: encodedFields}
+
+	return result, true, nil
+}
+
+// decodeManagedFields decodes the set of fields owned by the passed entry
+func decodeManagedFields(entry metav1.ManagedFieldsEntry) (*fieldpath.Set, error) {
+	fields := fieldpath.NewSet()
+	if entry.FieldsV1 == nil {
+		return fields, nil
+	}
+
+	if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
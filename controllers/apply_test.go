@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("managed fields upgrade", func() {
+	newEntry := func(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  operation,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+		}
+	}
+
+	legacyEntry := newEntry(legacyFieldManager, metav1.ManagedFieldsOperationUpdate,
+		`{"f:spec":{"f:selector":{},"f:type":{}}}`)
+	otherEntry := newEntry("kube-controller-manager", metav1.ManagedFieldsOperationUpdate,
+		`{"f:spec":{"f:clusterIP":{}}}`)
+
+	It("moves the fields set by the older versions of the operator to a new apply entry", func() {
+		managedFields, upgraded, err := getUpgradedManagedFields(
+			[]metav1.ManagedFieldsEntry{legacyEntry, otherEntry})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(upgraded).To(BeTrue())
+		Expect(managedFields).To(HaveLen(2))
+		Expect(managedFields[0]).To(Equal(otherEntry))
+		Expect(managedFields[1].Manager).To(Equal(utils.FieldManager))
+		Expect(managedFields[1].Operation).To(Equal(metav1.ManagedFieldsOperationApply))
+		Expect(managedFields[1].FieldsV1.Raw).To(MatchJSON(`{"f:spec":{"f:selector":{},"f:type":{}}}`))
+	})
+
+	It("merges the fields set by the older versions of the operator into the apply entry", func() {
+		applyEntry := newEntry(utils.FieldManager, metav1.ManagedFieldsOperationApply,
+			`{"f:spec":{"f:ports":{}}}`)
+		managedFields, upgraded, err := getUpgradedManagedFields(
+			[]metav1.ManagedFieldsEntry{applyEntry, legacyEntry, otherEntry})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(upgraded).To(BeTrue())
+		Expect(managedFields).To(HaveLen(2))
+		Expect(managedFields[0].Manager).To(Equal(utils.FieldManager))
+		Expect(managedFields[0].FieldsV1.Raw).To(MatchJSON(`{"f:spec":{"f:ports":{},"f:selector":{},"f:type":{}}}`))
+		Expect(managedFields[1]).To(Equal(otherEntry))
+	})
+
+	It("leaves alone the managed fields without entries of the older versions of the operator", func() {
+		managedFields := []metav1.ManagedFieldsEntry{otherEntry}
+		upgradedFields, upgraded, err := getUpgradedManagedFields(managedFields)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(upgraded).To(BeFalse())
+		Expect(upgradedFields).To(Equal(managedFields))
+	})
+
+	It("upgrades the managed fields of an existing object only once", func() {
+		ctx := context.Background()
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:     "default",
+				Name:          "cluster-example-rw",
+				ManagedFields: []metav1.ManagedFieldsEntry{legacyEntry, otherEntry},
+			},
+		}
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(service).Build()
+
+		Expect(upgradeManagedFields(ctx, cli, service.DeepCopy())).To(Succeed())
+
+		var upgradedService corev1.Service
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(service), &upgradedService)).To(Succeed())
+		Expect(upgradedService.ManagedFields).To(HaveLen(2))
+		Expect(upgradedService.ManagedFields).ToNot(ContainElement(HaveField("Manager", legacyFieldManager)))
+		Expect(upgradedService.ManagedFields).To(ContainElement(SatisfyAll(
+			HaveField("Manager", utils.FieldManager),
+			HaveField("Operation", metav1.ManagedFieldsOperationApply),
+		)))
+
+		Expect(upgradeManagedFields(ctx, cli, service.DeepCopy())).To(Succeed())
+		var unchangedService corev1.Service
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(service), &unchangedService)).To(Succeed())
+		Expect(unchangedService.ResourceVersion).To(Equal(upgradedService.ResourceVersion))
+	})
+
+	It("doesn't upgrade the objects not existing yet", func() {
+		cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "missing"}}
+		Expect(upgradeManagedFields(context.Background(), cli, service)).To(Succeed())
+	})
+})
@@ -0,0 +1,387 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// referencedSecretsKey is the index of the Clusters and Poolers
+	// by the names of the Secrets they reference
+	referencedSecretsKey = ".referencedSecrets"
+
+	// referencedConfigMapsKey is the index of the Clusters and Poolers
+	// by the names of the ConfigMaps they reference
+	referencedConfigMapsKey = ".referencedConfigMaps"
+
+	// referencedPVCsKey is the index of the Clusters and Poolers
+	// by the names of the PVCs they reference
+	referencedPVCsKey = ".referencedPersistentVolumeClaims"
+)
+
+// getReferencedObjectsKey gets the index of the Clusters and Poolers by the
+// names of the objects they reference having the kind of the passed one.
+// The Pods are always created by the operator with the cluster label, so
+// they are never referenced
+func getReferencedObjectsKey(obj client.Object) (string, bool) {
+	switch obj.(type) {
+	case *corev1.Secret:
+		return referencedSecretsKey, true
+	case *corev1.ConfigMap:
+		return referencedConfigMapsKey, true
+	case *corev1.PersistentVolumeClaim:
+		return referencedPVCsKey, true
+	default:
+		return "", false
+	}
+}
+
+// getReferencedNames gets the names of the objects referenced by the passed
+// Cluster or Pooler, for the passed index
+func getReferencedNames(key string, obj client.Object) []string {
+	switch obj := obj.(type) {
+	case *apiv1.Cluster:
+		switch key {
+		case referencedSecretsKey:
+			return getClusterReferencedSecrets(obj)
+		case referencedConfigMapsKey:
+			return getClusterReferencedConfigMaps(obj)
+		case referencedPVCsKey:
+			if obj.Spec.Bootstrap != nil && obj.Spec.Bootstrap.ExistingVolume != nil {
+				return []string{obj.Spec.Bootstrap.ExistingVolume.PersistentVolumeClaimName}
+			}
+		}
+
+	case *apiv1.Pooler:
+		if key == referencedSecretsKey {
+			// The pull secret is listed as it lacks the cluster
+			// label when created by an older version of the operator
+			result := []string{fmt.Sprintf("%s-pull", obj.Name)}
+			if obj.Spec.PgBouncer != nil {
+				result = append(result, obj.GetAuthQuerySecretName())
+			}
+			return result
+		}
+	}
+
+	return nil
+}
+
+// getClusterReferencedSecrets gets the names of the Secrets referenced by
+// the passed cluster. The ones generated by the operator are listed too, as
+// they lack the cluster label when created by an older version of it
+func getClusterReferencedSecrets(cluster *apiv1.Cluster) []string {
+	result := stringset.From([]string{
+		cluster.GetSuperuserSecretName(),
+		cluster.GetApplicationSecretName(),
+		cluster.GetReplicationSecretName(),
+		cluster.GetServerCASecretName(),
+		cluster.GetServerTLSSecretName(),
+		cluster.GetClientCASecretName(),
+		cluster.GetLDAPSecretName(),
+		fmt.Sprintf("%s-pull", cluster.Name),
+		apiv1.DefaultMonitoringSecretName,
+	})
+	for _, name := range cluster.GetUserMappingSecretNames() {
+		result.Put(name)
+	}
+	putSecretKeySelector := func(selector *apiv1.SecretKeySelector) {
+		if selector != nil {
+			result.Put(selector.Name)
+		}
+	}
+	putCoreSecretKeySelector := func(selector *corev1.SecretKeySelector) {
+		if selector != nil {
+			result.Put(selector.Name)
+		}
+	}
+	putLocalObjectReference := func(reference *apiv1.LocalObjectReference) {
+		if reference != nil {
+			result.Put(reference.Name)
+		}
+	}
+	putBarmanObjectStore := func(barmanObjectStore *apiv1.BarmanObjectStoreConfiguration) {
+		if barmanObjectStore == nil {
+			return
+		}
+		putSecretKeySelector(barmanObjectStore.EndpointCA)
+		if credentials := barmanObjectStore.AWS; credentials != nil {
+			putSecretKeySelector(credentials.AccessKeyIDReference)
+			putSecretKeySelector(credentials.SecretAccessKeyReference)
+			putSecretKeySelector(credentials.RegionReference)
+			putSecretKeySelector(credentials.SessionToken)
+		}
+		if credentials := barmanObjectStore.Azure; credentials != nil {
+			putSecretKeySelector(credentials.ConnectionString)
+			putSecretKeySelector(credentials.StorageAccount)
+			putSecretKeySelector(credentials.StorageKey)
+			putSecretKeySelector(credentials.StorageSasToken)
+		}
+		if credentials := barmanObjectStore.Google; credentials != nil {
+			putSecretKeySelector(credentials.ApplicationCredentials)
+		}
+	}
+
+	for idx := range cluster.Spec.ImagePullSecrets {
+		putLocalObjectReference(&cluster.Spec.ImagePullSecrets[idx])
+	}
+	putSecretKeySelector(cluster.GetLDAPCACertificate())
+	if cluster.Spec.ImageVerification != nil {
+		for idx := range cluster.Spec.ImageVerification.PublicKeys {
+			putSecretKeySelector(&cluster.Spec.ImageVerification.PublicKeys[idx])
+		}
+	}
+	if cluster.Spec.Monitoring != nil {
+		for idx := range cluster.Spec.Monitoring.CustomQueriesSecret {
+			putSecretKeySelector(&cluster.Spec.Monitoring.CustomQueriesSecret[idx])
+		}
+	}
+	if cluster.Spec.Backup != nil {
+		putBarmanObjectStore(cluster.Spec.Backup.BarmanObjectStore)
+	}
+	for idx := range cluster.Spec.ExternalClusters {
+		externalCluster := &cluster.Spec.ExternalClusters[idx]
+		putCoreSecretKeySelector(externalCluster.SSLCert)
+		putCoreSecretKeySelector(externalCluster.SSLKey)
+		putCoreSecretKeySelector(externalCluster.SSLRootCert)
+		putCoreSecretKeySelector(externalCluster.Password)
+		putBarmanObjectStore(externalCluster.BarmanObjectStore)
+	}
+
+	if bootstrap := cluster.Spec.Bootstrap; bootstrap != nil {
+		if bootstrap.InitDB != nil {
+			putLocalObjectReference(bootstrap.InitDB.Secret)
+			if refs := bootstrap.InitDB.PostInitApplicationSQLRefs; refs != nil {
+				for idx := range refs.SecretRefs {
+					putSecretKeySelector(&refs.SecretRefs[idx])
+				}
+			}
+		}
+		if bootstrap.Recovery != nil {
+			putLocalObjectReference(bootstrap.Recovery.Secret)
+			if bootstrap.Recovery.Backup != nil {
+				putSecretKeySelector(bootstrap.Recovery.Backup.EndpointCA)
+			}
+		}
+		if bootstrap.PgBaseBackup != nil {
+			putLocalObjectReference(bootstrap.PgBaseBackup.Secret)
+		}
+		if bootstrap.ExistingVolume != nil {
+			putLocalObjectReference(bootstrap.ExistingVolume.Secret)
+		}
+	}
+
+	if cluster.Status.PoolerIntegrations != nil {
+		for _, name := range cluster.Status.PoolerIntegrations.PgBouncerIntegration.Secrets {
+			result.Put(name)
+		}
+	}
+
+	result.Delete("")
+	return result.ToList()
+}
+
+// getClusterReferencedConfigMaps gets the names of the ConfigMaps
+// referenced by the passed cluster
+func getClusterReferencedConfigMaps(cluster *apiv1.Cluster) []string {
+	result := stringset.From([]string{apiv1.DefaultMonitoringConfigMapName})
+	if cluster.Spec.Monitoring != nil {
+		for _, selector := range cluster.Spec.Monitoring.CustomQueriesConfigMap {
+			result.Put(selector.Name)
+		}
+	}
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil &&
+		cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs != nil {
+		for _, selector := range cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.ConfigMapRefs {
+			result.Put(selector.Name)
+		}
+	}
+
+	result.Delete("")
+	return result.ToList()
+}
+
+// CreateReferencedObjectsIndexes creates the indexes of the Clusters and
+// Poolers by the names of the Secrets, ConfigMaps and PVCs they reference,
+// which are used to read from the API server the ones not in the cache
+func CreateReferencedObjectsIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, key := range []string{referencedSecretsKey, referencedConfigMapsKey, referencedPVCsKey} {
+		key := key
+		for _, obj := range []client.Object{&apiv1.Cluster{}, &apiv1.Pooler{}} {
+			if err := indexer.IndexField(ctx, obj, key, func(rawObj client.Object) []string {
+				return getReferencedNames(key, rawObj)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// withLabelSelector wraps the passed function creating a cache, restricting
+// the passed kinds of objects to the ones carrying the passed label
+func withLabelSelector(newCache cache.NewCacheFunc, label string, objects ...client.Object) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		requirement, err := labels.NewRequirement(label, selection.Exists, nil)
+		if err != nil {
+			return nil, err
+		}
+		selector := cache.ObjectSelector{Label: labels.NewSelector().Add(*requirement)}
+
+		opts.SelectorsByObject = make(cache.SelectorsByObject, len(objects))
+		for _, obj := range objects {
+			opts.SelectorsByObject[obj] = selector
+		}
+		return newCache(config, opts)
+	}
+}
+
+// NewFilteredCacheFunc wraps the passed function creating the cache of the
+// operator, keeping in it only the Secrets, ConfigMaps, PVCs and Pods
+// carrying the cluster label. Every object generated by the operator for
+// a cluster carries it, while the unrelated ones are not cached at all
+func NewFilteredCacheFunc(newCache cache.NewCacheFunc) cache.NewCacheFunc {
+	return withLabelSelector(newCache, utils.ClusterLabelName,
+		&corev1.Secret{}, &corev1.ConfigMap{}, &corev1.PersistentVolumeClaim{}, &corev1.Pod{})
+}
+
+// NewReloadCache creates, with the passed function, the cache of the
+// Secrets and ConfigMaps labeled to be reloaded by the instances. These
+// objects are usually created by the users, so they don't carry the
+// cluster label and need to be watched through this additional cache
+func NewReloadCache(mgr manager.Manager, newCache cache.NewCacheFunc) (cache.Cache, error) {
+	reloadCache, err := withLabelSelector(newCache, specs.WatchedLabelName, &corev1.Secret{}, &corev1.ConfigMap{})(
+		mgr.GetConfig(),
+		cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mgr.Add(reloadCache); err != nil {
+		return nil, err
+	}
+	return reloadCache, nil
+}
+
+// NewClient creates the client of the operator. The Secrets, ConfigMaps
+// and PVCs not found in the cache are read from the API server when they
+// are in the namespace of the operator or referenced by a Cluster or a
+// Pooler, like the ones created by the users, see CreateReferencedObjectsIndexes
+func NewClient(
+	cache cache.Cache,
+	config *rest.Config,
+	options client.Options,
+	uncachedObjects ...client.Object,
+) (client.Client, error) {
+	cachedClient, err := cluster.DefaultNewClient(cache, config, options, uncachedObjects...)
+	if err != nil {
+		return nil, err
+	}
+
+	apiReader, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fallbackClient{Client: cachedClient, apiReader: apiReader}, nil
+}
+
+// fallbackClient is a client reading from the API server the
+// objects not kept in the cache because of their labels.
+//
+// List is not overridden: the operator only lists the Secrets, ConfigMaps,
+// PVCs and Pods belonging to a cluster, selecting them by the cluster label
+// or by owner, and these objects always carry the cluster label, so the
+// results from the cache are complete
+type fallbackClient struct {
+	client.Client
+	apiReader client.Reader
+}
+
+// Get implements the client.Reader interface
+func (c *fallbackClient) Get(
+	ctx context.Context,
+	key client.ObjectKey,
+	obj client.Object,
+	opts ...client.GetOption,
+) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	referenced, referencedErr := c.isReferenced(ctx, key, obj)
+	if referencedErr != nil {
+		return referencedErr
+	}
+	if !referenced {
+		return err
+	}
+
+	return c.apiReader.Get(ctx, key, obj, opts...)
+}
+
+// isReferenced checks whether the passed object, not found in the cache,
+// is one the operator may need even without the cluster label: because
+// it's in the namespace of the operator, like the pull secret, or because
+// it's referenced by a Cluster or a Pooler
+func (c *fallbackClient) isReferenced(ctx context.Context, key client.ObjectKey, obj client.Object) (bool, error) {
+	indexKey, ok := getReferencedObjectsKey(obj)
+	if !ok {
+		return false, nil
+	}
+
+	if key.Namespace == configuration.Current.OperatorNamespace && indexKey != referencedPVCsKey {
+		return true, nil
+	}
+
+	for _, list := range []client.ObjectList{&apiv1.ClusterList{}, &apiv1.PoolerList{}} {
+		if err := c.Client.List(ctx, list,
+			client.InNamespace(key.Namespace),
+			client.MatchingFields{indexKey: key.Name},
+		); err != nil {
+			return false, err
+		}
+
+		if meta.LenList(list) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
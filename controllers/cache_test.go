@@ -0,0 +1,242 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// indexedClient filters the Clusters and Poolers listed by the indexes of
+// the referenced objects, as the fake client ignores the field selectors
+type indexedClient struct {
+	client.Client
+}
+
+func (c indexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+
+	listOptions := (&client.ListOptions{}).ApplyOptions(opts)
+	if listOptions.FieldSelector == nil {
+		return nil
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	var filteredItems []runtime.Object
+	for _, item := range items {
+		matches := true
+		for _, key := range []string{referencedSecretsKey, referencedConfigMapsKey, referencedPVCsKey} {
+			if name, found := listOptions.FieldSelector.RequiresExactMatch(key); found {
+				matches = matches && slices.Contains(getReferencedNames(key, item.(client.Object)), name)
+			}
+		}
+		if matches {
+			filteredItems = append(filteredItems, item)
+		}
+	}
+	return meta.SetList(list, filteredItems)
+}
+
+var _ = Describe("fallback client", func() {
+	const operatorNamespace = "operator"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "user-secret"}
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: "cluster-example"},
+		Spec: apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{
+							AccessKeyIDReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: key.Name},
+								Key:                  "ACCESS_KEY_ID",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	pooler := &apiv1.Pooler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: "pooler-example"},
+		Spec: apiv1.PoolerSpec{
+			Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+			PgBouncer: &apiv1.PgBouncerSpec{
+				AuthQuerySecret: &apiv1.LocalObjectReference{Name: "pooler-auth"},
+			},
+		},
+	}
+
+	newClient := func() *fallbackClient {
+		namedObjects := func(namespace string, names ...string) []client.Object {
+			var result []client.Object
+			for _, name := range names {
+				objectMeta := metav1.ObjectMeta{Namespace: namespace, Name: name}
+				result = append(result,
+					&corev1.Secret{ObjectMeta: objectMeta},
+					&corev1.ConfigMap{ObjectMeta: objectMeta},
+					&corev1.Pod{ObjectMeta: objectMeta},
+					&appsv1.Deployment{ObjectMeta: objectMeta},
+				)
+			}
+			return result
+		}
+
+		apiObjects := append(
+			namedObjects(key.Namespace, key.Name, "pooler-auth", "unrelated"),
+			namedObjects(operatorNamespace, "cnpg-pull")...)
+		return &fallbackClient{
+			Client: indexedClient{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, pooler).Build(),
+			},
+			apiReader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(apiObjects...).Build(),
+		}
+	}
+
+	BeforeEach(func() {
+		configuration.Current = configuration.NewConfiguration()
+		configuration.Current.OperatorNamespace = operatorNamespace
+	})
+
+	AfterEach(func() {
+		configuration.Current = configuration.NewConfiguration()
+	})
+
+	It("reads from the API server the secrets referenced by a cluster", func() {
+		var secret corev1.Secret
+		Expect(newClient().Get(ctx, key, &secret)).To(Succeed())
+		Expect(secret.Name).To(Equal(key.Name))
+	})
+
+	It("reads from the API server the secrets referenced by a pooler", func() {
+		var secret corev1.Secret
+		Expect(newClient().Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: "pooler-auth"}, &secret)).
+			To(Succeed())
+		Expect(secret.Name).To(Equal("pooler-auth"))
+	})
+
+	It("reads from the API server the objects in the namespace of the operator", func() {
+		var secret corev1.Secret
+		Expect(newClient().Get(ctx, types.NamespacedName{Namespace: operatorNamespace, Name: "cnpg-pull"}, &secret)).
+			To(Succeed())
+		Expect(secret.Name).To(Equal("cnpg-pull"))
+	})
+
+	It("doesn't read from the API server the objects not referenced", func() {
+		var secret corev1.Secret
+		err := newClient().Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: "unrelated"}, &secret)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		var configMap corev1.ConfigMap
+		err = newClient().Get(ctx, key, &configMap)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("doesn't read from the API server the pods and the kinds cached without filters", func() {
+		var pod corev1.Pod
+		err := newClient().Get(ctx, key, &pod)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		var deployment appsv1.Deployment
+		err = newClient().Get(ctx, key, &deployment)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("reports the referenced objects missing from the API server too", func() {
+		var secret corev1.Secret
+		err := newClient().Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: "cluster-example-app"}, &secret)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("referenced objects", func() {
+	It("lists the secrets provided by the users and the generated ones", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{ServerCASecret: "custom-ca"},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						PostInitApplicationSQLRefs: &apiv1.PostInitApplicationSQLRefs{
+							SecretRefs: []apiv1.SecretKeySelector{
+								{LocalObjectReference: apiv1.LocalObjectReference{Name: "sql-secret"}, Key: "sql"},
+							},
+							ConfigMapRefs: []apiv1.ConfigMapKeySelector{
+								{LocalObjectReference: apiv1.LocalObjectReference{Name: "sql-configmap"}, Key: "sql"},
+							},
+						},
+					},
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name: "source",
+						Password: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "source-password"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+		}
+
+		Expect(getReferencedNames(referencedSecretsKey, cluster)).To(ContainElements(
+			"custom-ca", "sql-secret", "source-password",
+			"cluster-example-superuser", "cluster-example-app", "cluster-example-server",
+		))
+		Expect(getReferencedNames(referencedSecretsKey, cluster)).ToNot(ContainElement(""))
+		Expect(getReferencedNames(referencedConfigMapsKey, cluster)).To(ConsistOf(
+			"sql-configmap", apiv1.DefaultMonitoringConfigMapName,
+		))
+		Expect(getReferencedNames(referencedPVCsKey, cluster)).To(BeEmpty())
+	})
+
+	It("lists the volume adopted by a cluster", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					ExistingVolume: &apiv1.BootstrapExistingVolume{PersistentVolumeClaimName: "migrated-data"},
+				},
+			},
+		}
+		Expect(getReferencedNames(referencedPVCsKey, cluster)).To(ConsistOf("migrated-data"))
+	})
+})
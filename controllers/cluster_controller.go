@@ -27,6 +27,7 @@ import (
 	goruntime "runtime"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -39,7 +40,9 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -47,7 +50,9 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/instancestatus"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -72,6 +77,15 @@ type ClusterReconciler struct {
 	Recorder        record.EventRecorder
 
 	timeoutHTTPClient *http.Client
+
+	// statusWatcher caches the status streamed by the instance
+	// managers, and is nil when the status is only polled
+	statusWatcher *instancestatus.Watcher
+
+	// ReloadCache caches the Secrets and ConfigMaps labeled to be
+	// reloaded, which are not kept in the cache of the manager when
+	// they don't belong to a cluster
+	ReloadCache cache.Cache
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
@@ -82,16 +96,22 @@ func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.Discov
 	// We want a connection timeout to prevent waiting for the default
 	// TCP connection timeout (30 seconds) on lost SYN packets
 	timeoutClient := &http.Client{
-		Transport: &http.Transport{
+		Transport: tracing.NewTransport(&http.Transport{
 			DialContext: (&net.Dialer{
 				Timeout: connectionTimeout,
 			}).DialContext,
-		},
+		}),
 		Timeout: requestTimeout,
 	}
 
+	var statusWatcher *instancestatus.Watcher
+	if configuration.Current.EnableInstanceStatusWatch {
+		statusWatcher = instancestatus.NewWatcher()
+	}
+
 	return &ClusterReconciler{
 		timeoutHTTPClient: timeoutClient,
+		statusWatcher:     statusWatcher,
 
 		DiscoveryClient: discoveryClient,
 		Client:          mgr.GetClient(),
@@ -108,13 +128,21 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;update;list
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update;list
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update;list
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
+// +kubebuilder:rbac:groups=lighthouse.submariner.io,resources=serviceexports,verbs=get;create;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceexports,verbs=get;create;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterhistories,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterhistories/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/status,verbs=get;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=pgcronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=pgcronjobs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;watch;delete;patch
@@ -122,6 +150,7 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;create;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
@@ -131,7 +160,16 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 
 // Reconcile is the operator reconcile loop
-func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartSpan(ctx, "Reconcile Cluster",
+		attribute.String("cluster.namespace", req.Namespace),
+		attribute.String("cluster.name", req.Name))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	reconcileStart := time.Now()
+
 	contextLogger, ctx := log.SetupLogger(ctx)
 
 	contextLogger.Debug(fmt.Sprintf("reconciling object %#q", req.NamespacedName))
@@ -146,6 +184,8 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil {
+		r.statusWatcher.Sync(req.NamespacedName, nil)
+		deleteClusterMetrics(req.NamespacedName)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -157,6 +197,11 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
+	defer func() {
+		observeReconcile(req.NamespacedName, time.Since(reconcileStart), err)
+		setClusterPhaseMetric(cluster)
+	}()
+
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
 	if errors.Is(err, ErrNextLoop) {
@@ -175,6 +220,12 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, nil
 	}
 
+	// The resources of a cluster being deleted are left untouched,
+	// waiting for the deletion policy to be applied
+	if !cluster.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, cluster)
+	}
+
 	// IMPORTANT: the following call will delete conditions using
 	// invalid condition reasons.
 	//
@@ -195,6 +246,10 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileDeletionPolicyFinalizer(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the deletion policy finalizer: %w", err)
+	}
+
 	// Ensure we reconcile the orphan resources if present when we reconcile for the first time a cluster
 	if err := r.reconcileRestoredCluster(ctx, cluster); err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot reconcile restored Cluster: %w", err)
@@ -205,6 +260,11 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot create Cluster auxiliary objects: %w", err)
 	}
 
+	// Resolve and verify the image before using it for the instances
+	if err := r.reconcileImageDigest(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot verify the image: %w", err)
+	}
+
 	// Update the status of this resource
 	resources, err := r.getManagedResources(ctx, cluster)
 	if err != nil {
@@ -212,19 +272,20 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
-	// Update the status section of this Cluster resource
-	if err = r.updateResourceStatus(ctx, cluster, resources); err != nil {
-		if apierrs.IsConflict(err) {
-			// Requeue a new reconciliation cycle, as in this point we need
-			// to quickly react the changes
-			return ctrl.Result{Requeue: true}, nil
-		}
-
-		return ctrl.Result{}, fmt.Errorf("cannot update the resource status: %w", err)
+	// The status is written with a single patch, once all the fields
+	// depending on the managed resources and on the instances are set
+	origCluster := cluster.DeepCopy()
+	statusChanges, err := r.setResourceStatus(ctx, cluster, resources)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot set the resource status: %w", err)
 	}
 
 	if cluster.Status.CurrentPrimary != "" &&
 		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		if err := r.writeClusterStatus(ctx, cluster, origCluster, statusChanges); err != nil {
+			return handleStatusWriteError(err)
+		}
+
 		contextLogger.Info("There is a switchover or a failover "+
 			"in progress, waiting for the operation to complete",
 			"currentPrimary", cluster.Status.CurrentPrimary,
@@ -234,41 +295,35 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 	}
 
 	// Get the replication status
-	instancesStatus := r.getStatusFromInstances(ctx, resources.instances)
+	instancesStatus := r.getStatusFromInstances(ctx, cluster, resources.instances)
+	setPendingRestartsMetric(cluster, instancesStatus)
 
-	// we update all the cluster status fields that require the instances status
-	if err := r.updateClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus); err != nil {
-		if apierrs.IsConflict(err) {
-			return ctrl.Result{Requeue: true}, nil
-		}
-		return ctrl.Result{}, fmt.Errorf("cannot update the instances status on the cluster: %w", err)
-	}
+	// we set all the cluster status fields that require the instances status
+	r.setClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus)
 
 	// Verify the architecture of all the instances and update the OnlineUpdateEnabled
 	// field in the status
-	onlineUpdateEnabled := configuration.Current.EnableInstanceManagerInplaceUpdates
+	onlineUpdateEnabled := cluster.IsInstanceManagerInplaceUpdateEnabled()
 	isArchitectureConsistent := r.checkPodsArchitecture(ctx, &instancesStatus)
 	if !isArchitectureConsistent && onlineUpdateEnabled {
 		contextLogger.Info("Architecture mismatch detected, disabling instance manager online updates")
 		onlineUpdateEnabled = false
 	}
+	cluster.Status.OnlineUpdateEnabled = onlineUpdateEnabled
 
-	// We have already updated the status in updateResourceStatus call,
-	// so we need to issue an extra update when the OnlineUpdateEnabled changes.
-	// It's okay because it should not change often.
-	//
-	// We cannot merge this code with updateResourceStatus because
-	// it needs to run after retrieving the status from the pods,
-	// which is a time-expensive operation.
-	if err = r.updateOnlineUpdateEnabled(ctx, cluster, onlineUpdateEnabled); err != nil {
+	setCurrentPrimaryLSN(cluster, &origCluster.Status, instancesStatus)
+	if err := r.writeClusterStatus(ctx, cluster, origCluster, statusChanges); err != nil {
+		return handleStatusWriteError(err)
+	}
+
+	// Fence the instances reporting damaged data, if requested
+	if err := r.fenceDataCorruptedInstances(ctx, cluster); err != nil {
 		if apierrs.IsConflict(err) {
-			// Requeue a new reconciliation cycle, as in this point we need
-			// to quickly react the changes
 			return ctrl.Result{Requeue: true}, nil
 		}
-
-		return ctrl.Result{}, fmt.Errorf("cannot update the resource status: %w", err)
+		return ctrl.Result{}, err
 	}
+
 	result, err := r.handleSwitchover(ctx, cluster, resources, instancesStatus)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -281,12 +336,45 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 	return r.reconcileResources(ctx, cluster, resources, instancesStatus)
 }
 
+// writeClusterStatus writes the changes made to the status of the
+// cluster, then records the changes detected while setting it
+func (r *ClusterReconciler) writeClusterStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	origCluster *apiv1.Cluster,
+	changes resourceStatusChanges,
+) error {
+	if err := r.patchClusterStatus(ctx, cluster, origCluster); err != nil {
+		return err
+	}
+
+	r.recordResourceStatusChanges(ctx, cluster, changes)
+	return nil
+}
+
+// handleStatusWriteError gets the result of the reconciliation loop
+// when the status of the cluster cannot be written
+func handleStatusWriteError(err error) (ctrl.Result, error) {
+	if apierrs.IsConflict(err) {
+		// Requeue a new reconciliation cycle, as in this point we need
+		// to quickly react the changes
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	return ctrl.Result{}, fmt.Errorf("cannot update the cluster status: %w", err)
+}
+
 func (r *ClusterReconciler) handleSwitchover(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	resources *managedResources,
 	instancesStatus postgres.PostgresqlStatusList,
-) (*ctrl.Result, error) {
+) (result *ctrl.Result, err error) {
+	ctx, span := tracing.StartSpan(ctx, "handleSwitchover")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	contextLogger := log.FromContext(ctx)
 	if cluster.IsInstanceFenced(cluster.Status.CurrentPrimary) ||
 		instancesStatus.ReportingMightBeUnavailable(cluster.Status.CurrentPrimary) {
@@ -316,6 +404,14 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if err == ErrPrimaryStorageStalled {
+			contextLogger.Info("Waiting for the storage of the primary to recover before failing over")
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+		if err == ErrNoPromotableInstance {
+			contextLogger.Info("Waiting for an instance which is not excluded from failover to elect a new primary")
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"cluster", cluster.Name)
@@ -383,7 +479,12 @@ func (r *ClusterReconciler) setDefaults(ctx context.Context, cluster *apiv1.Clus
 func (r *ClusterReconciler) reconcileResources(
 	ctx context.Context, cluster *apiv1.Cluster,
 	resources *managedResources, instancesStatus postgres.PostgresqlStatusList,
-) (ctrl.Result, error) {
+) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reconcileResources")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	contextLogger, ctx := log.SetupLogger(ctx)
 
 	// TODO: refactor how we handle label and annotation reconciliation.
@@ -453,6 +554,15 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{}, err
 	}
 
+	// Recreate the standbys that keep restarting, if requested
+	result, err = r.remediateCrashLoopingInstances(ctx, cluster, resources)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result != nil {
+		return *result, nil
+	}
+
 	// Reconcile Pods
 	if res, err := r.ReconcilePods(ctx, cluster, resources, instancesStatus); err != nil {
 		return res, err
@@ -475,6 +585,12 @@ func (r *ClusterReconciler) reconcileResources(
 
 	r.cleanupCompletedJobs(ctx, resources.jobs)
 
+	// The rotation of the passwords is not triggered by any change,
+	// so we need to periodically check whether it's due
+	if cluster.HasPasswordRotation() {
+		return ctrl.Result{RequeueAfter: passwordRotationCheckPeriod}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -599,6 +715,16 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
+	// If the cluster has been scaled down while joining a node,
+	// we don't need to wait for the process to finish
+	aborted, err := r.abortUnneededJoins(ctx, cluster, resources)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if aborted {
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+	}
+
 	// If we are joining a node, we should wait for the process to finish
 	if resources.countRunningJobs() > 0 {
 		contextLogger.Debug("Waiting for jobs to finish",
@@ -647,10 +773,29 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 	// Are there missing nodes? Let's create one
 	if cluster.Status.Instances < cluster.Spec.Instances &&
 		instancesStatus.InstancesReportingStatus() == cluster.Status.Instances {
+		// The PVCs retained while scaling down are reused before creating new ones
+		if cluster.GetScaleDownPVCReclaimPolicy() == apiv1.PVCReclaimPolicyRetain {
+			adopted, err := r.adoptDetachedPVCs(ctx, cluster)
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("cannot adopt detached PVCs: %w", err)
+			}
+			if adopted {
+				return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+			}
+		}
+
+		var cloneSource *corev1.PersistentVolumeClaim
+		if cluster.Spec.Replication.IsVolumeCloneEnabled() {
+			cloneSource = getVolumeCloneSource(resources.pvcs.Items, instancesStatus)
+		}
+
 		newNodeSerial, err := r.generateNodeSerial(ctx, cluster)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
 		}
+		if cloneSource != nil {
+			return r.joinReplicaInstanceFromVolumeClone(ctx, newNodeSerial, cluster, *cloneSource)
+		}
 		return r.joinReplicaInstance(ctx, newNodeSerial, cluster)
 	}
 
@@ -672,7 +817,11 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
-	return r.handleRollingUpdate(ctx, cluster, instancesStatus)
+	if result, err := r.handleRollingUpdate(ctx, cluster, instancesStatus); err != nil || !result.IsZero() {
+		return result, err
+	}
+
+	return r.migrateStorageClass(ctx, cluster, resources, instancesStatus)
 }
 
 func (r *ClusterReconciler) ensureHealthyPVCsAnnotation(
@@ -759,7 +908,8 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{RateLimiter: newClusterRateLimiter(configuration.Current)}).
 		For(&apiv1.Cluster{}).
 		Owns(&corev1.Pod{}).
 		Owns(&batchv1.Job{}).
@@ -784,8 +934,33 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&source.Kind{Type: &corev1.Node{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters(ctx)),
 			builder.WithPredicates(nodesPredicate),
-		).
-		Complete(r)
+		)
+
+	if r.ReloadCache != nil {
+		controllerBuilder = controllerBuilder.
+			Watches(
+				source.NewKindWithCache(&corev1.ConfigMap{}, r.ReloadCache),
+				handler.EnqueueRequestsFromMapFunc(r.mapConfigMapsToClusters(ctx)),
+				builder.WithPredicates(configMapsPredicate),
+			).
+			Watches(
+				source.NewKindWithCache(&corev1.Secret{}, r.ReloadCache),
+				handler.EnqueueRequestsFromMapFunc(r.mapSecretsToClusters(ctx)),
+				builder.WithPredicates(secretsPredicate),
+			)
+	}
+
+	if r.statusWatcher != nil {
+		if err := mgr.Add(r.statusWatcher); err != nil {
+			return err
+		}
+		controllerBuilder = controllerBuilder.Watches(
+			&source.Channel{Source: r.statusWatcher.Events()},
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+
+	return controllerBuilder.Complete(r)
 }
 
 // createFieldIndexes creates the indexes needed by this controller
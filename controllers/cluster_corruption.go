@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// setDataCorruptedInstances records the instances reporting damaged data,
+// and sets the corresponding condition. The instances whose status is
+// unknown, as the fenced ones, keep being reported until they are removed
+// from the cluster. The newly affected instances are returned
+func setDataCorruptedInstances(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) []string {
+	previous := stringset.From(cluster.Status.DataCorruptedInstances)
+	instanceNames := stringset.From(cluster.Status.InstanceNames)
+
+	corrupted := stringset.New()
+	for _, item := range statuses.Items {
+		if item.Error == nil && item.IsDataCorruptionDetected() {
+			corrupted.Put(item.Pod.Name)
+		}
+	}
+	for _, instanceName := range previous.ToList() {
+		if instanceNames.Has(instanceName) && !statuses.IsPodReporting(instanceName) {
+			corrupted.Put(instanceName)
+		}
+	}
+
+	var newlyCorrupted []string
+	for _, instanceName := range corrupted.ToList() {
+		if !previous.Has(instanceName) {
+			newlyCorrupted = append(newlyCorrupted, instanceName)
+		}
+	}
+	sort.Strings(newlyCorrupted)
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionDataCorruptionDetected),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonNoDataCorruption),
+		Message: "No instance reported damaged data",
+	}
+	cluster.Status.DataCorruptedInstances = nil
+	if corrupted.Len() > 0 {
+		corruptedInstances := corrupted.ToList()
+		sort.Strings(corruptedInstances)
+		cluster.Status.DataCorruptedInstances = corruptedInstances
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionDataCorruptionDetected),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonDataCorruption),
+			Message: "Checksum failures or errors about damaged data reported by: " +
+				strings.Join(corruptedInstances, ", "),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	return newlyCorrupted
+}
+
+// fenceDataCorruptedInstances fences the instances which reported damaged
+// data, when requested by the data corruption policy of the cluster
+func (r *ClusterReconciler) fenceDataCorruptedInstances(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.Spec.DataCorruption.IsFencingEnabled() {
+		return nil
+	}
+
+	var toBeFenced []string
+	for _, instanceName := range cluster.Status.DataCorruptedInstances {
+		if !cluster.IsInstanceFenced(instanceName) {
+			toBeFenced = append(toBeFenced, instanceName)
+		}
+	}
+	if len(toBeFenced) == 0 {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	for _, instanceName := range toBeFenced {
+		if err := utils.AddFencedInstance(instanceName, &cluster.ObjectMeta); err != nil {
+			return err
+		}
+	}
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return fmt.Errorf("while fencing the instances reporting damaged data: %w", err)
+	}
+
+	for _, instanceName := range toBeFenced {
+		log.FromContext(ctx).Warning("Fenced instance reporting damaged data", "instance", instanceName)
+		r.Recorder.Eventf(cluster, "Warning", "FencingOnDataCorruption",
+			"Fenced instance %s, which reported damaged data", instanceName)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setDataCorruptedInstances", func() {
+	newStatus := func(podName string, checksumFailures int64) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			ChecksumFailures: checksumFailures,
+		}
+	}
+
+	It("reports no corruption when every instance is healthy", func() {
+		cluster := &v1.Cluster{}
+		newlyCorrupted := setDataCorruptedInstances(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newStatus("cluster-example-1", 0)},
+		})
+
+		Expect(newlyCorrupted).To(BeEmpty())
+		Expect(cluster.Status.DataCorruptedInstances).To(BeEmpty())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionDataCorruptionDetected))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("reports the instances with damaged data only once as new", func() {
+		cluster := &v1.Cluster{}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", 0),
+				newStatus("cluster-example-2", 3),
+			},
+		}
+
+		Expect(setDataCorruptedInstances(cluster, statuses)).To(Equal([]string{"cluster-example-2"}))
+		Expect(setDataCorruptedInstances(cluster, statuses)).To(BeEmpty())
+		Expect(cluster.Status.DataCorruptedInstances).To(Equal([]string{"cluster-example-2"}))
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionDataCorruptionDetected))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-2"))
+	})
+
+	It("keeps reporting the affected instances whose status is unknown", func() {
+		cluster := &v1.Cluster{}
+		cluster.Status.InstanceNames = []string{"cluster-example-1", "cluster-example-2"}
+		cluster.Status.DataCorruptedInstances = []string{"cluster-example-2", "cluster-example-3"}
+		fenced := newStatus("cluster-example-2", 0)
+		fenced.Error = fmt.Errorf("connection refused")
+
+		newlyCorrupted := setDataCorruptedInstances(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newStatus("cluster-example-1", 0), fenced},
+		})
+
+		Expect(newlyCorrupted).To(BeEmpty())
+		Expect(cluster.Status.DataCorruptedInstances).To(Equal([]string{"cluster-example-2"}))
+	})
+})
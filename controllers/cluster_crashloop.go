@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// restartHistory is the content of the restart history annotation
+type restartHistory struct {
+	// The restart count of the PostgreSQL container last seen by the operator
+	RestartCount int32 `json:"restartCount"`
+
+	// When the restarts happened inside the remediation window
+	Restarts []time.Time `json:"restarts,omitempty"`
+}
+
+// remediateCrashLoopingInstances recreates from scratch the standbys whose
+// PostgreSQL container restarted more times than allowed in the configured
+// window, keeping track of the restarts in an annotation of their Pods
+func (r *ClusterReconciler) remediateCrashLoopingInstances(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	remediation := cluster.Spec.CrashLoopRemediation
+	if !remediation.IsEnabled() {
+		return nil, nil
+	}
+
+	now := time.Now()
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if pod.Name == cluster.Status.CurrentPrimary || pod.Name == cluster.Status.TargetPrimary ||
+			cluster.IsInstanceFenced(pod.Name) {
+			continue
+		}
+
+		history, err := r.updateRestartHistory(ctx, pod, now, remediation.GetWindow())
+		if err != nil {
+			return nil, err
+		}
+		if len(history.Restarts) <= remediation.GetMaxRestarts() {
+			continue
+		}
+
+		contextLogger.Warning("Recreating crash looping standby",
+			"pod", pod.Name,
+			"restarts", len(history.Restarts),
+			"window", remediation.GetWindow())
+		r.Recorder.Eventf(cluster, "Warning", "CrashLoopRemediation",
+			"Recreating instance %s, restarted %d times in the last %v",
+			pod.Name, len(history.Restarts), remediation.GetWindow())
+		if err := r.deleteInstanceWithStorage(ctx, cluster, resources, pod.Name); err != nil {
+			return nil, err
+		}
+
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	return nil, nil
+}
+
+// updateRestartHistory updates the restart history of the passed Pod,
+// patching its annotation when it changes
+func (r *ClusterReconciler) updateRestartHistory(
+	ctx context.Context,
+	pod *corev1.Pod,
+	now time.Time,
+	window time.Duration,
+) (restartHistory, error) {
+	restartCount := getPostgresRestartCount(*pod)
+
+	var history restartHistory
+	annotation, found := pod.Annotations[utils.RestartHistoryAnnotationName]
+	if !found {
+		// The restarts that happened before the Pod was tracked can't be placed
+		// in time, and are not counted
+		history.RestartCount = restartCount
+	} else if err := json.Unmarshal([]byte(annotation), &history); err != nil {
+		log.FromContext(ctx).Info("Ignoring the invalid restart history of the Pod",
+			"pod", pod.Name, "restartHistory", annotation)
+		history = restartHistory{RestartCount: restartCount}
+	}
+
+	updatedHistory := addRestarts(history, restartCount, now, window)
+	updatedAnnotation, err := json.Marshal(updatedHistory)
+	if err != nil {
+		return restartHistory{}, err
+	}
+	if found && string(updatedAnnotation) == annotation {
+		return updatedHistory, nil
+	}
+
+	origPod := pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[utils.RestartHistoryAnnotationName] = string(updatedAnnotation)
+	if err := r.Patch(ctx, pod, client.MergeFrom(origPod)); err != nil {
+		return restartHistory{}, fmt.Errorf("while updating the restart history of Pod %s: %w", pod.Name, err)
+	}
+
+	return updatedHistory, nil
+}
+
+// addRestarts records the restarts happened since the history has been
+// last updated, and forgets the ones that are now outside the window
+func addRestarts(history restartHistory, restartCount int32, now time.Time, window time.Duration) restartHistory {
+	result := restartHistory{RestartCount: restartCount}
+	for _, restart := range history.Restarts {
+		if now.Sub(restart) <= window {
+			result.Restarts = append(result.Restarts, restart)
+		}
+	}
+
+	for count := history.RestartCount; count < restartCount; count++ {
+		result.Restarts = append(result.Restarts, now)
+	}
+
+	return result
+}
+
+// getPostgresRestartCount gets how many times the PostgreSQL container
+// of the passed Pod has been restarted
+func getPostgresRestartCount(pod corev1.Pod) int32 {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == specs.PostgresContainerName {
+			return containerStatus.RestartCount
+		}
+	}
+
+	return 0
+}
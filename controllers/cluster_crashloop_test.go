@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("crash loop remediation", func() {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	window := 10 * time.Minute
+
+	It("records the new restarts", func() {
+		history := addRestarts(restartHistory{RestartCount: 2}, 4, now, window)
+		Expect(history.RestartCount).To(BeEquivalentTo(4))
+		Expect(history.Restarts).To(Equal([]time.Time{now, now}))
+	})
+
+	It("forgets the restarts outside the window", func() {
+		history := restartHistory{
+			RestartCount: 2,
+			Restarts:     []time.Time{now.Add(-20 * time.Minute), now.Add(-5 * time.Minute)},
+		}
+		history = addRestarts(history, 3, now, window)
+		Expect(history.RestartCount).To(BeEquivalentTo(3))
+		Expect(history.Restarts).To(Equal([]time.Time{now.Add(-5 * time.Minute), now}))
+	})
+
+	It("gets the restart count of the PostgreSQL container", func() {
+		pod := corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "sidecar", RestartCount: 7},
+					{Name: specs.PostgresContainerName, RestartCount: 3},
+				},
+			},
+		}
+		Expect(getPostgresRestartCount(pod)).To(BeEquivalentTo(3))
+		Expect(getPostgresRestartCount(corev1.Pod{})).To(BeZero())
+	})
+})
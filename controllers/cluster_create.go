@@ -26,10 +26,13 @@ import (
 	"github.com/sethvargo/go-password/password"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +41,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -45,8 +49,13 @@ import (
 )
 
 // createPostgresClusterObjects ensures that we have the required global objects
-func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cluster *apiv1.Cluster) error {
-	err := r.setupPostgresPKI(ctx, cluster)
+func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cluster *apiv1.Cluster) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "createPostgresClusterObjects")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	err = r.setupPostgresPKI(ctx, cluster)
 	if err != nil {
 		return err
 	}
@@ -61,6 +70,21 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.reconcileExposedDatabases(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileReadOnlyServiceExport(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileClusterHistory(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.reconcilePodDisruptionBudget(ctx, cluster)
 	if err != nil {
 		return err
@@ -93,6 +117,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.reconcileNetworkPolicy(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	// TODO: only required to cleanup custom monitoring queries configmaps from older versions (v1.10 and v1.11)
 	// 		 that could have been copied with the source configmap name instead of the new default one.
 	// 		 Should be removed in future releases.
@@ -103,6 +132,15 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 }
 
 func (r *ClusterReconciler) reconcilePodDisruptionBudget(ctx context.Context, cluster *apiv1.Cluster) error {
+	// Development clusters must never prevent the
+	// underlying nodes from being drained
+	if cluster.IsDevelopmentProfile() {
+		if err := r.deleteReplicasPodDisruptionBudget(ctx, cluster); err != nil {
+			return err
+		}
+		return r.deletePrimaryPodDisruptionBudget(ctx, cluster)
+	}
+
 	// The PDB should not be enforced if we are inside a maintenance
 	// window, and we chose to avoid allocating more storage space.
 	if cluster.IsNodeMaintenanceWindowInProgress() && cluster.IsReusePVCEnabled() {
@@ -163,7 +201,7 @@ func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluste
 func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluster *apiv1.Cluster) error {
 	// We need to create a secret for the 'postgres' user when superuser
 	// access is enabled and the user haven't specified his own
-	if cluster.GetEnableSuperuserAccess() &&
+	if cluster.UsesSuperuserSecret() &&
 		(cluster.Spec.SuperuserSecret == nil || cluster.Spec.SuperuserSecret.Name == "") {
 		postgresPassword, err := password.Generate(64, 10, 0, false, true)
 		if err != nil {
@@ -186,8 +224,9 @@ func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluste
 		}
 	}
 
-	// If we don't have Superuser enabled we make sure the automatically generated secret doesn't exist
-	if !cluster.GetEnableSuperuserAccess() {
+	// If we don't have Superuser enabled, or its password is provided by an
+	// external secret store, we make sure the automatically generated secret doesn't exist
+	if !cluster.UsesSuperuserSecret() {
 		var secret corev1.Secret
 		err := r.Get(
 			ctx,
@@ -209,7 +248,7 @@ func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluste
 }
 
 func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster *apiv1.Cluster) error {
-	if cluster.ShouldCreateApplicationSecret() {
+	if cluster.ShouldCreateApplicationSecret() && cluster.GetApplicationPasswordFile() == "" {
 		appPassword, err := password.Generate(64, 10, 0, false, true)
 		if err != nil {
 			return err
@@ -227,11 +266,44 @@ func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster
 			if !apierrs.IsAlreadyExists(err) {
 				return err
 			}
+			return r.addServiceBindingData(ctx, cluster)
 		}
 	}
 	return nil
 }
 
+// addServiceBindingData adds the entries required by the Service Binding
+// specification to the application secret, when it has been generated
+// before their introduction
+func (r *ClusterReconciler) addServiceBindingData(ctx context.Context, cluster *apiv1.Cluster) error {
+	var secret corev1.Secret
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetApplicationSecretName()},
+		&secret,
+	); err != nil {
+		return err
+	}
+
+	if _, owned := IsOwnedByCluster(&secret); !owned {
+		return nil
+	}
+
+	missingData := make(map[string]string)
+	for key, value := range specs.GetServiceBindingData(cluster.GetApplicationDatabaseName()) {
+		if _, ok := secret.Data[key]; !ok {
+			missingData[key] = value
+		}
+	}
+	if len(missingData) == 0 {
+		return nil
+	}
+
+	origSecret := secret.DeepCopy()
+	secret.StringData = missingData
+	return r.Patch(ctx, &secret, client.MergeFrom(origSecret))
+}
+
 func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
 	if cluster.Status.PoolerIntegrations == nil {
 		return nil
@@ -271,42 +343,26 @@ func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster
 
 func (r *ClusterReconciler) createPostgresServices(ctx context.Context, cluster *apiv1.Cluster) error {
 	anyService := specs.CreateClusterAnyService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&anyService.ObjectMeta, cluster)
-
-	if err := r.Create(ctx, anyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
-	}
-
-	readService := specs.CreateClusterReadService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readService.ObjectMeta, cluster)
-
-	if err := r.Create(ctx, readService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
+	if err := r.createOrPatchService(ctx, cluster, anyService); err != nil {
+		return err
 	}
 
-	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readOnlyService.ObjectMeta, cluster)
-
-	if err := r.Create(ctx, readOnlyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
+	for _, selectorType := range []apiv1.ServiceSelectorType{
+		apiv1.ServiceSelectorTypeR,
+		apiv1.ServiceSelectorTypeRO,
+		apiv1.ServiceSelectorTypeRW,
+	} {
+		service := specs.CreateClusterDefaultService(*cluster, selectorType)
+		if err := r.createOrPatchService(ctx, cluster, service); err != nil {
 			return err
 		}
 	}
 
-	readWriteService := specs.CreateClusterReadWriteService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readWriteService.ObjectMeta, cluster)
-
-	if err := r.Create(ctx, readWriteService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
+	if err := r.reconcileAdditionalServices(ctx, cluster); err != nil {
+		return err
 	}
 
-	return nil
+	return r.reconcileInstanceServices(ctx, cluster)
 }
 
 // createOrPatchOwnedPodDisruptionBudget ensures that we have a PDB requiring to remove one node at a time
@@ -320,34 +376,24 @@ func (r *ClusterReconciler) createOrPatchOwnedPodDisruptionBudget(
 	}
 
 	var oldPdb policyv1.PodDisruptionBudget
-
-	if err := r.Get(ctx, client.ObjectKey{Name: pdb.Name, Namespace: pdb.Namespace}, &oldPdb); err != nil {
-		if !apierrs.IsNotFound(err) {
-			return fmt.Errorf("while getting PodDisruptionBudget: %w", err)
-		}
-		SetClusterOwnerAnnotationsAndLabels(&pdb.ObjectMeta, cluster)
-
-		r.Recorder.Event(cluster, "Normal", "CreatingPodDisruptionBudget",
-			fmt.Sprintf("Creating PodDisruptionBudget %s", pdb.Name))
-		if err = r.Create(ctx, pdb); err != nil {
-			return fmt.Errorf("while creating PodDisruptionBudget: %w", err)
-		}
-		return nil
+	err := r.Get(ctx, client.ObjectKey{Name: pdb.Name, Namespace: pdb.Namespace}, &oldPdb)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting PodDisruptionBudget: %w", err)
 	}
+	exists := err == nil
 
-	if reflect.DeepEqual(pdb.Spec, oldPdb.Spec) {
-		// Everything fine, the two pdbs are the same for us
-		return nil
+	SetClusterOwnerAnnotationsAndLabels(&pdb.ObjectMeta, cluster)
+	if err := applyObject(ctx, r.Client, pdb); err != nil {
+		return fmt.Errorf("while applying PodDisruptionBudget: %w", err)
 	}
 
-	r.Recorder.Event(cluster, "Normal", "UpdatingPodDisruptionBudget",
-		fmt.Sprintf("Updating PodDisruptionBudget %s", pdb.Name))
-
-	patchedPdb := oldPdb
-	patchedPdb.Spec = pdb.Spec
-
-	if err := r.Patch(ctx, &patchedPdb, client.MergeFrom(&oldPdb)); err != nil {
-		return fmt.Errorf("while patching PodDisruptionBudget: %w", err)
+	switch {
+	case !exists:
+		r.Recorder.Event(cluster, "Normal", "CreatingPodDisruptionBudget",
+			fmt.Sprintf("Creating PodDisruptionBudget %s", pdb.Name))
+	case pdb.ResourceVersion != oldPdb.ResourceVersion:
+		r.Recorder.Event(cluster, "Normal", "UpdatingPodDisruptionBudget",
+			fmt.Sprintf("Updating PodDisruptionBudget %s", pdb.Name))
 	}
 
 	return nil
@@ -504,6 +550,11 @@ func (r *ClusterReconciler) copyPullSecretFromOperator(ctx context.Context, clus
 		return "", nil
 	}
 
+	// no pull secret name, there is nothing to do
+	if configuration.Current.OperatorPullSecretName == "" {
+		return "", nil
+	}
+
 	// Let's find the operator secret
 	var operatorSecret corev1.Secret
 	if err := r.Get(ctx, client.ObjectKey{
@@ -530,14 +581,39 @@ func (r *ClusterReconciler) copyPullSecretFromOperator(ctx context.Context, clus
 	}
 	SetClusterOwnerAnnotationsAndLabels(&secret.ObjectMeta, cluster)
 
-	// Another sync loop may have already created the service. Let's check that
-	if err := r.Create(ctx, &secret); err != nil && !apierrs.IsAlreadyExists(err) {
+	if err := createOrPatchPullSecret(ctx, r.Client, &secret); err != nil {
 		return "", err
 	}
 
 	return clusterSecretName, nil
 }
 
+// createOrPatchPullSecret applies the passed copy of the operator pull secret,
+// so that a rotation of the operator pull secret is propagated to every
+// namespace using it. The pull secrets copied before the cluster label was
+// added to them are labeled too, as only the labeled secrets are cached
+func createOrPatchPullSecret(ctx context.Context, cli client.Client, secret *corev1.Secret) error {
+	var oldSecret corev1.Secret
+	err := cli.Get(ctx, client.ObjectKeyFromObject(secret), &oldSecret)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting the pull secret: %w", err)
+	}
+
+	// The type of a secret is immutable, so we need to recreate it
+	if err == nil && oldSecret.Type != secret.Type {
+		log.FromContext(ctx).Info("Recreating the pull secret copied from the operator", "secretName", secret.Name)
+		if err := cli.Delete(ctx, &oldSecret); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting the pull secret: %w", err)
+		}
+	}
+
+	if err := applyObject(ctx, cli, secret); err != nil {
+		return fmt.Errorf("while applying the pull secret: %w", err)
+	}
+
+	return nil
+}
+
 // createOrPatchRole ensures that the required role for the instance manager exists and
 // contains the right rules
 func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv1.Cluster) error {
@@ -546,30 +622,24 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		return err
 	}
 
-	var role rbacv1.Role
-	if err := r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &role); err != nil {
-		if !apierrs.IsNotFound(err) {
-			return fmt.Errorf("while getting role: %w", err)
-		}
-
-		r.Recorder.Event(cluster, "Normal", "CreatingRole", "Creating Cluster Role")
-		return r.createRole(ctx, cluster, originBackup)
+	var oldRole rbacv1.Role
+	err = r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &oldRole)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting role: %w", err)
 	}
+	exists := err == nil
 
-	generatedRole := specs.CreateRole(*cluster, originBackup)
-	if reflect.DeepEqual(generatedRole.Rules, role.Rules) {
-		// Everything fine, the two config maps are exactly the same
-		return nil
+	role := specs.CreateRole(*cluster, originBackup)
+	SetClusterOwnerAnnotationsAndLabels(&role.ObjectMeta, cluster)
+	if err := applyObject(ctx, r.Client, &role); err != nil {
+		return fmt.Errorf("while applying role: %w", err)
 	}
 
-	r.Recorder.Event(cluster, "Normal", "UpdatingRole", "Updating Cluster Role")
-
-	// The configuration changed, and we need the patch the
-	// configMap we have
-	patchedRole := role
-	patchedRole.Rules = generatedRole.Rules
-	if err := r.Patch(ctx, &patchedRole, client.MergeFrom(&role)); err != nil {
-		return fmt.Errorf("while patching role: %w", err)
+	switch {
+	case !exists:
+		r.Recorder.Event(cluster, "Normal", "CreatingRole", "Creating Cluster Role")
+	case role.ResourceVersion != oldRole.ResourceVersion:
+		r.Recorder.Event(cluster, "Normal", "UpdatingRole", "Updating Cluster Role")
 	}
 
 	return nil
@@ -827,15 +897,59 @@ func (r *ClusterReconciler) createOrPatchPodMonitor(ctx context.Context, cluster
 	}
 }
 
-// createRole creates the role
-func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Cluster, backupOrigin *apiv1.Backup) error {
-	role := specs.CreateRole(*cluster, backupOrigin)
-	SetClusterOwnerAnnotationsAndLabels(&role.ObjectMeta, cluster)
+// reconcileReadOnlyServiceExport exports the read-only service to the other
+// Kubernetes clusters using the requested API, and removes the exports made
+// with the other APIs. The APIs whose resource is not defined in the
+// Kubernetes cluster are skipped
+func (r *ClusterReconciler) reconcileReadOnlyServiceExport(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+	desiredAPI := cluster.GetReadOnlyServiceExportAPI()
 
-	err := r.Create(ctx, &role)
-	if err != nil && !apierrs.IsAlreadyExists(err) {
-		log.FromContext(ctx).Error(err, "Unable to create the Role", "object", role)
-		return err
+	for _, api := range specs.ServiceExportAPIs {
+		serviceExport := &unstructured.Unstructured{}
+		serviceExport.SetGroupVersionKind(specs.GetServiceExportGroupVersionKind(api))
+		err := r.Get(
+			ctx,
+			client.ObjectKey{Name: cluster.GetServiceReadOnlyName(), Namespace: cluster.Namespace},
+			serviceExport)
+
+		switch {
+		case meta.IsNoMatchError(err):
+			if api == desiredAPI {
+				r.Recorder.Eventf(cluster, "Warning", "ServiceExportNotAvailable",
+					"Cannot export the read-only service, the %s API is not available", api)
+			}
+			continue
+
+		case apierrs.IsNotFound(err):
+			if api != desiredAPI {
+				continue
+			}
+
+			objectMeta := metav1.ObjectMeta{
+				Name:      cluster.GetServiceReadOnlyName(),
+				Namespace: cluster.Namespace,
+			}
+			SetClusterOwnerAnnotationsAndLabels(&objectMeta, cluster)
+			contextLogger.Info("Exporting the read-only service", "api", api)
+			if err := r.Create(ctx, specs.CreateServiceExport(objectMeta, api)); err != nil &&
+				!apierrs.IsAlreadyExists(err) {
+				return fmt.Errorf("while exporting the read-only service: %w", err)
+			}
+
+		case err != nil:
+			return fmt.Errorf("while getting the export of the read-only service: %w", err)
+
+		case api != desiredAPI:
+			if _, owned := IsOwnedByCluster(serviceExport); !owned {
+				continue
+			}
+
+			contextLogger.Info("Removing the export of the read-only service", "api", api)
+			if err := r.Delete(ctx, serviceExport); err != nil && !apierrs.IsNotFound(err) {
+				return fmt.Errorf("while removing the export of the read-only service: %w", err)
+			}
+		}
 	}
 
 	return nil
@@ -855,9 +969,19 @@ func (r *ClusterReconciler) createRoleBinding(ctx context.Context, cluster *apiv
 	return nil
 }
 
-// generateNodeSerial extracts the first free node serial in this pods
+// generateNodeSerial extracts the first free node serial in this pods,
+// assigning the new instance to its group
 func (r *ClusterReconciler) generateNodeSerial(ctx context.Context, cluster *apiv1.Cluster) (int, error) {
+	// The first generated instance is the primary
+	isPrimary := cluster.Status.LatestGeneratedNode == 0
 	cluster.Status.LatestGeneratedNode++
+	if groupName := cluster.GetInstanceGroupForNewInstance(isPrimary); groupName != "" {
+		if cluster.Status.InstanceGroups == nil {
+			cluster.Status.InstanceGroups = make(map[string]string)
+		}
+		instanceName := specs.GetInstanceName(cluster.Name, cluster.Status.LatestGeneratedNode)
+		cluster.Status.InstanceGroups[instanceName] = groupName
+	}
 	if err := r.Status().Update(ctx, cluster); err != nil {
 		return 0, err
 	}
@@ -891,13 +1015,38 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, nil
 	}
 
+	var existingVolume *corev1.PersistentVolumeClaim
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.ExistingVolume != nil {
+		var err error
+		existingVolume, err = getExistingVolume(ctx, r.Client, cluster)
+		if err != nil {
+			r.Recorder.Event(cluster, "Warning", "ExistingVolumeRefused", err.Error())
+			return ctrl.Result{}, err
+		}
+		if existingVolume == nil {
+			contextLogger.Info("Missing PVC to be adopted, can't continue the bootstrap",
+				"pvcName", cluster.Spec.Bootstrap.ExistingVolume.PersistentVolumeClaimName)
+			return ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: time.Minute,
+			}, nil
+		}
+	}
+
 	// Generate a new node serial
 	nodeSerial, err := r.generateNodeSerial(ctx, cluster)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
 	}
 
-	if err := r.createPVC(
+	if existingVolume != nil {
+		// The adopted volume becomes the PGDATA of the first instance,
+		// and the bootstrap job will verify and initialize it in place
+		if err := adoptExistingVolume(ctx, r.Client, cluster, existingVolume, nodeSerial); err != nil {
+			return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("unable to adopt PVC %s: %w",
+				existingVolume.Name, err)
+		}
+	} else if err := r.createPVC(
 		ctx,
 		cluster,
 		cluster.Spec.StorageConfiguration,
@@ -945,6 +1094,9 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.PgBaseBackup != nil:
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from physical backup)")
 		job = specs.CreatePrimaryJobViaPgBaseBackup(*cluster, nodeSerial)
+	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.ExistingVolume != nil:
+		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from existing volume)")
+		job = specs.CreatePrimaryJobViaExistingVolume(*cluster, nodeSerial)
 	default:
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (initdb)")
 		job = specs.CreatePrimaryJobViaInitdb(*cluster, nodeSerial)
@@ -956,7 +1108,7 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	}
 
 	podName := fmt.Sprintf("%v-%v", cluster.Name, nodeSerial)
-	if err = r.setPrimaryInstance(ctx, cluster, podName); err != nil {
+	if err = r.setPrimaryInstance(ctx, cluster, podName, ""); err != nil {
 		contextLogger.Error(err, "Unable to set the primary instance name")
 		return ctrl.Result{}, err
 	}
@@ -975,11 +1127,11 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
 		cluster.GetFixedInheritedAnnotations(), configuration.Current)
 	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedPodAnnotations(), configuration.Current)
 	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedPodLabels(), configuration.Current)
 
 	if err = r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
@@ -1028,19 +1180,103 @@ func (r *ClusterReconciler) joinReplicaInstance(
 	nodeSerial int,
 	cluster *apiv1.Cluster,
 ) (ctrl.Result, error) {
-	contextLogger := log.FromContext(ctx)
+	job := specs.JoinReplicaInstance(*cluster, nodeSerial)
+	if result, err := r.createJoinJob(ctx, cluster, job); err != nil {
+		return result, err
+	}
 
-	var job *batchv1.Job
-	var err error
+	if err := r.createPVC(
+		ctx,
+		cluster,
+		cluster.Spec.StorageConfiguration,
+		nodeSerial,
+		utils.PVCRolePgData,
+	); err != nil {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
+	if cluster.ShouldCreateWalArchiveVolume() {
+		if err := r.createPVC(
+			ctx,
+			cluster,
+			*cluster.Spec.WalStorage,
+			nodeSerial,
+			utils.PVCRolePgWal,
+		); err != nil {
+			return ctrl.Result{RequeueAfter: time.Minute}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
+}
+
+// joinReplicaInstanceFromVolumeClone creates a new replica whose data
+// volume is a CSI clone of the passed one, belonging to a ready standby
+func (r *ClusterReconciler) joinReplicaInstanceFromVolumeClone(
+	ctx context.Context,
+	nodeSerial int,
+	cluster *apiv1.Cluster,
+	source corev1.PersistentVolumeClaim,
+) (ctrl.Result, error) {
+	job := specs.JoinReplicaInstanceFromVolumeClone(*cluster, nodeSerial)
+	if result, err := r.createJoinJob(ctx, cluster, job); err != nil {
+		return result, err
+	}
+
+	// The new replica may use a different storage class, because of its
+	// instance group or of a storage class migration, which needs to be
+	// served by the same CSI driver
+	storageClass := specs.GetExpectedStorageClass(
+		cluster.Spec.StorageConfiguration,
+		*cluster,
+		specs.GetInstanceName(cluster.Name, nodeSerial))
+
+	pvc := specs.ClonePVC(*cluster, source, nodeSerial, storageClass)
+	r.Recorder.Eventf(cluster, "Normal", "CloningVolume",
+		"Cloning the volume %v for the new instance", source.Name)
+	if err := r.Create(ctx, pvc); err != nil && !apierrs.IsAlreadyExists(err) {
+		return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("while cloning PVC %s: %w", source.Name, err)
+	}
+
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
+}
+
+// getVolumeCloneSource gets the data PVC of the most advanced ready standby,
+// which can be cloned for a new replica, or nil if there's none
+func getVolumeCloneSource(
+	pvcs []corev1.PersistentVolumeClaim,
+	instancesStatus postgres.PostgresqlStatusList,
+) *corev1.PersistentVolumeClaim {
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || status.IsPrimary || !status.IsReady {
+			continue
+		}
+
+		for idx := range pvcs {
+			if pvcs[idx].Name == status.Pod.Name &&
+				pvcs[idx].Annotations[specs.PVCStatusAnnotationName] == specs.PVCStatusReady {
+				return &pvcs[idx]
+			}
+		}
+	}
 
-	job = specs.JoinReplicaInstance(*cluster, nodeSerial)
+	return nil
+}
+
+// createJoinJob creates the Job adding a new replica to the cluster
+func (r *ClusterReconciler) createJoinJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	job *batchv1.Job,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
 
 	contextLogger.Info("Creating new Job",
 		"job", job.Name,
 		"primary", false)
 
 	r.Recorder.Eventf(cluster, "Normal", "CreatingInstance",
-		"Creating instance %v-%v", cluster.Name, nodeSerial)
+		"Creating instance %v", job.Labels[utils.InstanceNameLabelName])
 
 	if err := r.RegisterPhase(ctx, cluster,
 		apiv1.PhaseCreatingReplica,
@@ -1057,13 +1293,13 @@ func (r *ClusterReconciler) joinReplicaInstance(
 	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
 		cluster.GetFixedInheritedAnnotations(), configuration.Current)
 	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedPodAnnotations(), configuration.Current)
 	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedPodLabels(), configuration.Current)
 
-	if err = r.Create(ctx, job); err != nil {
+	if err := r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
 			// This Job was already created, maybe the cache is stale.
 			contextLogger.Info("Job already exist, maybe the cache is stale", "pod", job.Name)
@@ -1074,29 +1310,7 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		return ctrl.Result{}, err
 	}
 
-	if err := r.createPVC(
-		ctx,
-		cluster,
-		cluster.Spec.StorageConfiguration,
-		nodeSerial,
-		utils.PVCRolePgData,
-	); err != nil {
-		return ctrl.Result{RequeueAfter: time.Minute}, err
-	}
-
-	if cluster.ShouldCreateWalArchiveVolume() {
-		if err := r.createPVC(
-			ctx,
-			cluster,
-			*cluster.Spec.WalStorage,
-			nodeSerial,
-			utils.PVCRolePgWal,
-		); err != nil {
-			return ctrl.Result{RequeueAfter: time.Minute}, err
-		}
-	}
-
-	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
+	return ctrl.Result{}, nil
 }
 
 // reconcilePVCs reattaches a dangling PVC
@@ -1163,6 +1377,22 @@ func (r *ClusterReconciler) reconcilePVCs(
 
 	pod := specs.PodWithExistingStorage(*cluster, nodeSerial)
 
+	// An instance using a local volume can only run on the node owning it
+	hostname, err := r.getLocalVolumeHostname(ctx, *pvc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if hostname != "" {
+		available, err := r.isLocalVolumeNodeAvailable(ctx, cluster, *pvc, hostname)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !available {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
+		}
+		specs.PinPodToHostname(pod, hostname)
+	}
+
 	if configuration.Current.EnableAzurePVCUpdates {
 		for _, pvcName := range cluster.Status.ResizingPVC {
 			// if the pvc is in resizing state we requeue and wait
@@ -1191,9 +1421,9 @@ func (r *ClusterReconciler) reconcilePVCs(
 
 	utils.SetOperatorVersion(&pod.ObjectMeta, versions.Version)
 	utils.InheritAnnotations(&pod.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedPodAnnotations(), configuration.Current)
 	utils.InheritLabels(&pod.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedPodLabels(), configuration.Current)
 
 	if err := r.Create(ctx, pod); err != nil {
 		if apierrs.IsAlreadyExists(err) {
@@ -1292,3 +1522,60 @@ func (r *ClusterReconciler) createPVC(
 
 	return nil
 }
+
+// reconcileNetworkPolicy creates, patches or deletes the NetworkPolicy
+// restricting the traffic directed to the instances
+func (r *ClusterReconciler) reconcileNetworkPolicy(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+		networkPolicy,
+	); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the network policy: %w", err)
+		}
+		networkPolicy = nil
+	}
+
+	switch {
+	case !cluster.IsNetworkPolicyEnabled() && networkPolicy == nil:
+		return nil
+	case !cluster.IsNetworkPolicyEnabled() && networkPolicy != nil:
+		// We only delete the network policies we own
+		if owner, owned := IsOwnedByCluster(networkPolicy); !owned || owner != cluster.Name {
+			return nil
+		}
+		contextLogger.Info("Deleting NetworkPolicy")
+		if err := r.Delete(ctx, networkPolicy); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting the network policy: %w", err)
+		}
+		return nil
+	case networkPolicy == nil:
+		newNetworkPolicy := specs.CreateNetworkPolicy(cluster, configuration.Current.OperatorNamespace)
+		SetClusterOwnerAnnotationsAndLabels(&newNetworkPolicy.ObjectMeta, cluster)
+		r.Recorder.Event(cluster, "Normal", "CreatingNetworkPolicy",
+			fmt.Sprintf("Creating NetworkPolicy %s", newNetworkPolicy.Name))
+		if err := r.Create(ctx, newNetworkPolicy); err != nil {
+			return fmt.Errorf("while creating the network policy: %w", err)
+		}
+		return nil
+	default:
+		origNetworkPolicy := networkPolicy.DeepCopy()
+		networkPolicy.Spec = specs.CreateNetworkPolicy(cluster, configuration.Current.OperatorNamespace).Spec
+		if reflect.DeepEqual(origNetworkPolicy.Spec, networkPolicy.Spec) {
+			return nil
+		}
+
+		contextLogger.Debug("Patching NetworkPolicy")
+		if err := r.Patch(ctx, networkPolicy, client.MergeFrom(origNetworkPolicy)); err != nil {
+			return fmt.Errorf("while patching the network policy: %w", err)
+		}
+		return nil
+	}
+}
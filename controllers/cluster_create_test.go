@@ -21,9 +21,11 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -101,6 +103,89 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 
+	It("should make sure that the service templates and the additional services are applied", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+
+		By("creating the services with a template and an additional service", func() {
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Services: &apiv1.ManagedServices{
+					Templates: []apiv1.ServiceTemplate{
+						{
+							SelectorType: apiv1.ServiceSelectorTypeRW,
+							ServiceTemplateSpec: apiv1.ServiceTemplateSpec{
+								Metadata: apiv1.EmbeddedObjectMetadata{
+									Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "db.example.com"},
+								},
+								Type: corev1.ServiceTypeNodePort,
+							},
+						},
+					},
+					Additional: []apiv1.AdditionalService{
+						{
+							Name:         cluster.Name + "-reporting",
+							SelectorType: apiv1.ServiceSelectorTypeRO,
+							Selector:     map[string]string{"workload": "reporting"},
+						},
+					},
+				},
+			}
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the services are customized", func() {
+			var readWriteService corev1.Service
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadWriteName(), namespace, &readWriteService)
+			Expect(readWriteService.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			Expect(readWriteService.Annotations).To(HaveKeyWithValue(
+				"external-dns.alpha.kubernetes.io/hostname", "db.example.com"))
+
+			var additionalService corev1.Service
+			expectResourceExistsWithDefaultClient(cluster.Name+"-reporting", namespace, &additionalService)
+			Expect(additionalService.Spec.Selector).To(HaveKeyWithValue("workload", "reporting"))
+		})
+
+		By("removing the template and the additional service", func() {
+			cluster.Spec.Managed = nil
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the services are restored", func() {
+			var readWriteService corev1.Service
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadWriteName(), namespace, &readWriteService)
+			Expect(readWriteService.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+			Expect(readWriteService.Spec.Ports[0].NodePort).To(BeZero())
+		})
+	})
+
+	It("should make sure that the services of the instances are created and removed", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		cluster.Status.InstanceNames = []string{cluster.Name + "-1", cluster.Name + "-2"}
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{PerInstance: &apiv1.ServiceTemplateSpec{}},
+		}
+
+		By("creating a service for each instance", func() {
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			expectResourceExistsWithDefaultClient(cluster.Name+"-1", namespace, &corev1.Service{})
+			expectResourceExistsWithDefaultClient(cluster.Name+"-2", namespace, &corev1.Service{})
+		})
+
+		By("removing the service of a deleted instance", func() {
+			cluster.Status.InstanceNames = []string{cluster.Name + "-1"}
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			expectResourceExistsWithDefaultClient(cluster.Name+"-1", namespace, &corev1.Service{})
+			expectResourceDoesntExistWithDefaultClient(cluster.Name+"-2", namespace, &corev1.Service{})
+		})
+	})
+
 	It("should make sure that createOrPatchServiceAccount works correctly", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -236,3 +321,53 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("volume clone source", func() {
+	newPVC := func(name, status string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{specs.PVCStatusAnnotationName: status},
+			},
+		}
+	}
+	newStatus := func(name string, isPrimary, isReady bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary: isPrimary,
+			IsReady:   isReady,
+		}
+	}
+	pvcs := []corev1.PersistentVolumeClaim{
+		newPVC("cluster-example-1", specs.PVCStatusReady),
+		newPVC("cluster-example-2", specs.PVCStatusReady),
+		newPVC("cluster-example-3", specs.PVCStatusReady),
+	}
+
+	It("chooses the first ready standby", func() {
+		source := getVolumeCloneSource(pvcs, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true),
+			newStatus("cluster-example-3", false, true),
+			newStatus("cluster-example-2", false, true),
+		}})
+		Expect(source).ToNot(BeNil())
+		Expect(source.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("never clones the primary nor the standbys which are not ready", func() {
+		source := getVolumeCloneSource(pvcs, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true),
+			newStatus("cluster-example-2", false, false),
+		}})
+		Expect(source).To(BeNil())
+	})
+
+	It("doesn't clone a volume which is still initializing", func() {
+		source := getVolumeCloneSource(
+			[]corev1.PersistentVolumeClaim{newPVC("cluster-example-2", specs.PVCStatusInitializing)},
+			postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-2", false, true),
+			}})
+		Expect(source).To(BeNil())
+	})
+})
@@ -0,0 +1,226 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// passwordRotationCheckPeriod is how often the clusters rotating the
+// passwords of the owners of the exposed databases are reconciled
+const passwordRotationCheckPeriod = 5 * time.Minute
+
+// reconcileExposedDatabases ensures that every exposed managed database has
+// its own Service and Secret, and removes the ones of the databases which
+// are not exposed anymore
+func (r *ClusterReconciler) reconcileExposedDatabases(ctx context.Context, cluster *apiv1.Cluster) error {
+	exposedDatabases := stringset.New()
+	for _, database := range cluster.GetExposedDatabases() {
+		exposedDatabases.Put(database.Name)
+
+		if err := r.ensureDatabaseSecret(ctx, cluster, database); err != nil {
+			return fmt.Errorf("while reconciling the secret of database %s: %w", database.Name, err)
+		}
+
+		service := specs.CreateClusterDatabaseService(*cluster, database)
+		SetClusterOwnerAnnotationsAndLabels(&service.ObjectMeta, cluster)
+		if err := r.Create(ctx, service); err != nil && !apierrs.IsAlreadyExists(err) {
+			return fmt.Errorf("while creating the service of database %s: %w", database.Name, err)
+		}
+	}
+
+	return r.deleteUnexposedDatabaseResources(ctx, cluster, exposedDatabases)
+}
+
+// ensureDatabaseSecret creates the Secret with the credentials of the owner
+// of an exposed database, unless it already exists, and generates a new
+// password when the rotation interval has elapsed
+func (r *ClusterReconciler) ensureDatabaseSecret(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	database apiv1.ManagedDatabase,
+) error {
+	resourcesName := cluster.GetDatabaseResourcesName(database.Name)
+
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: resourcesName}, &secret)
+	if err == nil {
+		return r.rotateDatabasePassword(ctx, cluster, database, &secret)
+	}
+	if !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	databaseSecret, err := createDatabaseSecret(cluster, database)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, databaseSecret); err != nil && !apierrs.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// createDatabaseSecret generates the Secret, with a new password, of the
+// owner of an exposed database
+func createDatabaseSecret(cluster *apiv1.Cluster, database apiv1.ManagedDatabase) (*corev1.Secret, error) {
+	resourcesName := cluster.GetDatabaseResourcesName(database.Name)
+
+	ownerPassword, err := password.Generate(64, 10, 0, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	databaseSecret := specs.CreateSecret(
+		resourcesName,
+		cluster.Namespace,
+		resourcesName,
+		database.Name,
+		database.GetOwner(),
+		ownerPassword)
+	databaseSecret.Labels[utils.DatabaseNameLabelName] = database.Name
+	databaseSecret.Annotations = map[string]string{
+		utils.PasswordRotatedAtAnnotationName: time.Now().UTC().Format(time.RFC3339),
+	}
+	SetClusterOwnerAnnotationsAndLabels(&databaseSecret.ObjectMeta, cluster)
+
+	return databaseSecret, nil
+}
+
+// rotateDatabasePassword stores a new password in the Secret of an exposed
+// database when its rotation interval has elapsed. The instance manager of
+// the primary will apply it to the owner as soon as the Secret changes
+func (r *ClusterReconciler) rotateDatabasePassword(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	database apiv1.ManagedDatabase,
+	secret *corev1.Secret,
+) error {
+	if database.PasswordRotation == nil {
+		return nil
+	}
+	if _, owned := IsOwnedByCluster(secret); !owned {
+		return nil
+	}
+	if !isPasswordRotationDue(secret, database.PasswordRotation.Duration, time.Now()) {
+		return nil
+	}
+
+	generatedSecret, err := createDatabaseSecret(cluster, database)
+	if err != nil {
+		return err
+	}
+
+	origSecret := secret.DeepCopy()
+	secret.StringData = generatedSecret.StringData
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[utils.PasswordRotatedAtAnnotationName] =
+		generatedSecret.Annotations[utils.PasswordRotatedAtAnnotationName]
+	if err := r.Patch(ctx, secret, client.MergeFrom(origSecret)); err != nil {
+		return err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "PasswordRotated",
+		"Generated a new password for the owner of database %s", database.Name)
+	return nil
+}
+
+// isPasswordRotationDue checks if the password stored in a Secret is older
+// than the rotation interval. The creation time of the Secret is used when
+// it has never been rotated
+func isPasswordRotationDue(secret *corev1.Secret, interval time.Duration, now time.Time) bool {
+	rotatedAt := secret.CreationTimestamp.Time
+	if value, ok := secret.Annotations[utils.PasswordRotatedAtAnnotationName]; ok {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			rotatedAt = parsed
+		}
+	}
+
+	return !now.Before(rotatedAt.Add(interval))
+}
+
+// deleteUnexposedDatabaseResources deletes the Services and the Secrets
+// generated for the databases not in the passed set
+func (r *ClusterReconciler) deleteUnexposedDatabaseResources(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	exposedDatabases *stringset.Data,
+) error {
+	listOptions := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.DatabaseNameLabelName},
+	}
+
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, listOptions...); err != nil {
+		return fmt.Errorf("while listing the services of the databases: %w", err)
+	}
+	for idx := range services.Items {
+		if err := r.deleteUnexposedDatabaseResource(ctx, &services.Items[idx], exposedDatabases); err != nil {
+			return err
+		}
+	}
+
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, listOptions...); err != nil {
+		return fmt.Errorf("while listing the secrets of the databases: %w", err)
+	}
+	for idx := range secrets.Items {
+		if err := r.deleteUnexposedDatabaseResource(ctx, &secrets.Items[idx], exposedDatabases); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteUnexposedDatabaseResource deletes an object generated by the
+// operator for a database which is not exposed anymore
+func (r *ClusterReconciler) deleteUnexposedDatabaseResource(
+	ctx context.Context,
+	object client.Object,
+	exposedDatabases *stringset.Data,
+) error {
+	if exposedDatabases.Has(object.GetLabels()[utils.DatabaseNameLabelName]) {
+		return nil
+	}
+
+	if _, owned := IsOwnedByCluster(object); !owned {
+		return nil
+	}
+
+	if err := r.Delete(ctx, object); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting %s: %w", object.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("password rotation of the exposed databases", func() {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	It("uses the creation time of secrets never rotated", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+			},
+		}
+		Expect(isPasswordRotationDue(secret, time.Hour, now)).To(BeTrue())
+		Expect(isPasswordRotationDue(secret, 3*time.Hour, now)).To(BeFalse())
+	})
+
+	It("uses the time of the last rotation", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+				Annotations: map[string]string{
+					utils.PasswordRotatedAtAnnotationName: now.Add(-30 * time.Minute).Format(time.RFC3339),
+				},
+			},
+		}
+		Expect(isPasswordRotationDue(secret, time.Hour, now)).To(BeFalse())
+		Expect(isPasswordRotationDue(secret, 30*time.Minute, now)).To(BeTrue())
+	})
+})
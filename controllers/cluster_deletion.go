@@ -0,0 +1,409 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// deletionPolicyRequeueDelay is how often the progress of
+	// the deletion policy is checked
+	deletionPolicyRequeueDelay = 10 * time.Second
+
+	// inventoryConfigMapKey is the key of the inventory ConfigMap
+	// containing the list of the roles and of the databases
+	inventoryConfigMapKey = "inventory.json"
+)
+
+// reconcileDeletionPolicyFinalizer adds the finalizer holding the deletion
+// of the cluster when the deletion policy requires it, and removes it otherwise
+func (r *ClusterReconciler) reconcileDeletionPolicyFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	needsFinalizer := cluster.NeedsDeletionPolicyFinalizer()
+	if needsFinalizer == controllerutil.ContainsFinalizer(cluster, apiv1.DeletionPolicyFinalizerName) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if needsFinalizer {
+		controllerutil.AddFinalizer(cluster, apiv1.DeletionPolicyFinalizerName)
+	} else {
+		controllerutil.RemoveFinalizer(cluster, apiv1.DeletionPolicyFinalizerName)
+	}
+
+	return r.Patch(ctx, cluster, client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}))
+}
+
+// reconcileDeletion applies the deletion policy of a cluster being deleted,
+// releasing its resources when every step has been completed or when the
+// deletion policy timeout has expired
+func (r *ClusterReconciler) reconcileDeletion(ctx context.Context, cluster *apiv1.Cluster) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(cluster, apiv1.DeletionPolicyFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	deadline := cluster.DeletionTimestamp.Add(cluster.Spec.DeletionPolicy.GetTimeout())
+	if time.Now().After(deadline) {
+		contextLogger.Warning("Deletion policy timeout expired, proceeding with the deletion")
+		r.Recorder.Event(cluster, "Warning", "DeletionPolicyTimeout",
+			"The deletion policy has not been completed in time, proceeding with the deletion")
+		return ctrl.Result{}, r.removeDeletionPolicyFinalizer(ctx, cluster)
+	}
+
+	var finalBackupResult *finalBackupOutcome
+	if cluster.ShouldTakeFinalBackup() {
+		outcome, err := r.reconcileFinalBackup(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if outcome == nil {
+			return ctrl.Result{RequeueAfter: deletionPolicyRequeueDelay}, nil
+		}
+		finalBackupResult = outcome
+	}
+
+	if cluster.ShouldArchiveWALOnDeletion() || cluster.ShouldExportInventoryOnDeletion() {
+		primaryPod, err := r.getDeletionPolicyPrimaryPod(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if primaryPod == nil {
+			r.Recorder.Event(cluster, "Warning", "DeletionPolicy",
+				"No ready primary instance, skipping the WAL archiving and the inventory export")
+		} else if err := r.applyDeletionPolicyOnPrimary(ctx, cluster, *primaryPod); err != nil {
+			contextLogger.Info("Cannot apply the deletion policy, retrying", "error", err.Error())
+			return ctrl.Result{RequeueAfter: deletionPolicyRequeueDelay}, nil
+		}
+	}
+
+	contextLogger.Info("Deletion policy applied, proceeding with the deletion")
+	if err := r.removeDeletionPolicyFinalizer(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The outcome of the final backup is reported only once, as the
+	// cluster is not reconciled again after the finalizer is removed
+	if finalBackupResult != nil {
+		r.Recorder.Event(cluster, finalBackupResult.eventType, "FinalBackup", finalBackupResult.message)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// applyDeletionPolicyOnPrimary runs the steps of the deletion
+// policy requiring a running primary instance
+func (r *ClusterReconciler) applyDeletionPolicyOnPrimary(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	primaryPod corev1.Pod,
+) error {
+	if cluster.ShouldArchiveWALOnDeletion() {
+		walName, err := r.archiveWALOnPod(ctx, primaryPod)
+		if err != nil {
+			return fmt.Errorf("while archiving WAL: %w", err)
+		}
+		r.Recorder.Eventf(cluster, "Normal", "DeletionPolicy",
+			"WAL archived up to %s before the deletion", walName)
+	}
+
+	if cluster.ShouldExportInventoryOnDeletion() {
+		if err := r.exportInventory(ctx, cluster, primaryPod); err != nil {
+			return fmt.Errorf("while exporting inventory: %w", err)
+		}
+		r.Recorder.Eventf(cluster, "Normal", "DeletionPolicy",
+			"Roles and databases exported to ConfigMap %s", cluster.GetInventoryConfigMapName())
+	}
+
+	return nil
+}
+
+// removeDeletionPolicyFinalizer lets Kubernetes delete the cluster
+func (r *ClusterReconciler) removeDeletionPolicyFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	origCluster := cluster.DeepCopy()
+	controllerutil.RemoveFinalizer(cluster, apiv1.DeletionPolicyFinalizerName)
+	err := r.Patch(ctx, cluster, client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}))
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// getDeletionPolicyPrimaryPod gets the primary Pod, if it is running and ready
+func (r *ClusterReconciler) getDeletionPolicyPrimaryPod(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*corev1.Pod, error) {
+	if cluster.Status.CurrentPrimary == "" {
+		return nil, nil
+	}
+
+	var pod corev1.Pod
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Status.CurrentPrimary}, &pod)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !pod.DeletionTimestamp.IsZero() || !utils.IsPodReady(pod) {
+		return nil, nil
+	}
+
+	return &pod, nil
+}
+
+// getFinalBackupName gets the name of the backup taken before deleting the
+// cluster. It depends on the deletion time to not conflict with the final
+// backups of other clusters having had the same name
+func getFinalBackupName(cluster *apiv1.Cluster) string {
+	return fmt.Sprintf("%s-final-%s", cluster.Name, cluster.DeletionTimestamp.UTC().Format("20060102150405"))
+}
+
+// finalBackupOutcome is the event reporting how the final backup ended
+type finalBackupOutcome struct {
+	eventType string
+	message   string
+}
+
+// reconcileFinalBackup starts the final backup of the cluster, returning
+// its outcome when it is done, whether it succeeded or not, or when the
+// time allowed to take it has expired, and nil while it is running
+func (r *ClusterReconciler) reconcileFinalBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*finalBackupOutcome, error) {
+	contextLogger := log.FromContext(ctx)
+
+	backupName := getFinalBackupName(cluster)
+	var backup apiv1.Backup
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: backupName}, &backup)
+	if apierrs.IsNotFound(err) {
+		if err := r.startFinalBackup(ctx, cluster, backupName); err != nil {
+			return nil, err
+		}
+		r.Recorder.Eventf(cluster, "Normal", "FinalBackup", "Started the final backup %s", backupName)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := getFinalBackupOutcome(cluster, &backup, time.Now())
+	if outcome == nil {
+		contextLogger.Info("Waiting for the final backup to complete",
+			"backupName", backupName, "phase", backup.Status.Phase)
+	}
+	return outcome, nil
+}
+
+// getFinalBackupOutcome gets the outcome of the final backup at the passed
+// time, or nil if the backup is still running and there's time to wait for it
+func getFinalBackupOutcome(cluster *apiv1.Cluster, backup *apiv1.Backup, now time.Time) *finalBackupOutcome {
+	switch backup.Status.Phase {
+	case apiv1.BackupPhaseCompleted:
+		return &finalBackupOutcome{eventType: "Normal", message: describeFinalBackup(backup)}
+
+	case apiv1.BackupPhaseFailed:
+		return &finalBackupOutcome{
+			eventType: "Warning",
+			message:   fmt.Sprintf("The final backup %s failed: %s", backup.Name, backup.Status.Error),
+		}
+	}
+
+	deadline := cluster.DeletionTimestamp.Add(cluster.Spec.DeletionPolicy.GetFinalBackupTimeout())
+	if now.After(deadline) {
+		return &finalBackupOutcome{
+			eventType: "Warning",
+			message: fmt.Sprintf("The final backup %s has not been completed in time, proceeding without it",
+				backup.Name),
+		}
+	}
+
+	return nil
+}
+
+// startFinalBackup creates the final backup of the cluster
+// with the method requested by the deletion policy
+func (r *ClusterReconciler) startFinalBackup(ctx context.Context, cluster *apiv1.Cluster, backupName string) error {
+	method := cluster.Spec.DeletionPolicy.GetFinalBackupMethod()
+	if method != apiv1.FinalBackupMethodBarmanObjectStore {
+		return fmt.Errorf("unknown final backup method: %s", method)
+	}
+
+	// The backup has no owner, as it must survive the cluster
+	backup := apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupName,
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+			},
+		},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+		},
+	}
+
+	log.FromContext(ctx).Info("Creating the final backup", "backupName", backupName, "method", method)
+	if err := r.Create(ctx, &backup); err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating the final backup: %w", err)
+	}
+
+	return nil
+}
+
+// describeFinalBackup describes the coordinates of the final
+// backup that are needed to restore it
+func describeFinalBackup(backup *apiv1.Backup) string {
+	stoppedAt := ""
+	if backup.Status.StoppedAt != nil {
+		stoppedAt = backup.Status.StoppedAt.UTC().Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf(
+		"The final backup %s completed: destinationPath=%s serverName=%s backupId=%s "+
+			"endWal=%s endLSN=%s stoppedAt=%s",
+		backup.Name,
+		backup.Status.DestinationPath,
+		backup.Status.ServerName,
+		backup.Status.BackupID,
+		backup.Status.EndWal,
+		backup.Status.EndLSN,
+		stoppedAt,
+	)
+}
+
+// archiveWALOnPod asks the instance manager to archive the current WAL file,
+// returning its name
+func (r *ClusterReconciler) archiveWALOnPod(ctx context.Context, pod corev1.Pod) (string, error) {
+	body, err := r.instanceRequest(ctx, pod, http.MethodPost, url.PathPgArchiveWAL)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+// exportInventory stores the roles and the databases of the cluster in a
+// ConfigMap without owners, which is retained after the cluster is deleted
+func (r *ClusterReconciler) exportInventory(ctx context.Context, cluster *apiv1.Cluster, pod corev1.Pod) error {
+	body, err := r.instanceRequest(ctx, pod, http.MethodGet, url.PathPgInventory)
+	if err != nil {
+		return err
+	}
+
+	var inventory postgres.Inventory
+	if err := json.Unmarshal(body, &inventory); err != nil {
+		return err
+	}
+
+	configMap, err := createInventoryConfigMap(cluster, inventory)
+	if err != nil {
+		return err
+	}
+
+	err = r.Create(ctx, configMap)
+	if apierrs.IsAlreadyExists(err) {
+		var existingConfigMap corev1.ConfigMap
+		if err := r.Get(ctx, client.ObjectKeyFromObject(configMap), &existingConfigMap); err != nil {
+			return err
+		}
+		existingConfigMap.Data = configMap.Data
+		return r.Update(ctx, &existingConfigMap)
+	}
+
+	return err
+}
+
+// createInventoryConfigMap creates the ConfigMap containing the passed inventory
+func createInventoryConfigMap(cluster *apiv1.Cluster, inventory postgres.Inventory) (*corev1.ConfigMap, error) {
+	content, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetInventoryConfigMapName(),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+			},
+		},
+		Data: map[string]string{
+			inventoryConfigMapKey: string(content),
+		},
+	}, nil
+}
+
+// instanceRequest makes a request to the instance manager running in the passed Pod
+func (r *ClusterReconciler) instanceRequest(
+	ctx context.Context,
+	pod corev1.Pod,
+	method string,
+	path string,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url.Build(pod.Status.PodIP, path, url.StatusPort), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.timeoutHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, InstanceStatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return body, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("deletion policy", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster-example",
+			Namespace:         "default",
+			DeletionTimestamp: &metav1.Time{Time: time.Date(2022, 11, 3, 14, 5, 9, 0, time.UTC)},
+		},
+	}
+
+	It("names the final backup after the deletion time", func() {
+		Expect(getFinalBackupName(cluster)).To(Equal("cluster-example-final-20221103140509"))
+	})
+
+	It("creates the inventory ConfigMap without owners", func() {
+		inventory := postgres.Inventory{
+			Roles: []postgres.RoleInventory{
+				{Name: "app", Login: true},
+			},
+			Databases: []postgres.DatabaseInventory{
+				{Name: "app", Owner: "app", Encoding: "UTF8"},
+			},
+		}
+
+		configMap, err := createInventoryConfigMap(cluster, inventory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configMap.Name).To(Equal("cluster-example-inventory"))
+		Expect(configMap.Namespace).To(Equal("default"))
+		Expect(configMap.OwnerReferences).To(BeEmpty())
+		Expect(configMap.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+
+		var exported postgres.Inventory
+		Expect(json.Unmarshal([]byte(configMap.Data[inventoryConfigMapKey]), &exported)).To(Succeed())
+		Expect(exported).To(Equal(inventory))
+	})
+
+	It("describes the coordinates of the final backup", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example-final-20221103140509",
+			},
+			Status: apiv1.BackupStatus{
+				DestinationPath: "s3://backups/",
+				ServerName:      "cluster-example",
+				BackupID:        "20221103T140512",
+				EndWal:          "000000010000000000000004",
+				EndLSN:          "0/4000100",
+				StoppedAt:       &metav1.Time{Time: time.Date(2022, 11, 3, 14, 6, 0, 0, time.UTC)},
+			},
+		}
+
+		Expect(describeFinalBackup(backup)).To(Equal(
+			"The final backup cluster-example-final-20221103140509 completed: " +
+				"destinationPath=s3://backups/ serverName=cluster-example backupId=20221103T140512 " +
+				"endWal=000000010000000000000004 endLSN=0/4000100 stoppedAt=2022-11-03T14:06:00Z"))
+	})
+
+	It("reports the outcome of the final backup only when it is done", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example-final-20221103140509",
+			},
+			Status: apiv1.BackupStatus{
+				Phase: apiv1.BackupPhaseRunning,
+			},
+		}
+		now := cluster.DeletionTimestamp.Add(time.Minute)
+		Expect(getFinalBackupOutcome(cluster, backup, now)).To(BeNil())
+
+		expired := cluster.DeletionTimestamp.Add(cluster.Spec.DeletionPolicy.GetFinalBackupTimeout() + time.Second)
+		outcome := getFinalBackupOutcome(cluster, backup, expired)
+		Expect(outcome).ToNot(BeNil())
+		Expect(outcome.eventType).To(Equal("Warning"))
+
+		backup.Status.Phase = apiv1.BackupPhaseFailed
+		backup.Status.Error = "no space left on device"
+		outcome = getFinalBackupOutcome(cluster, backup, now)
+		Expect(outcome).ToNot(BeNil())
+		Expect(outcome.eventType).To(Equal("Warning"))
+		Expect(outcome.message).To(ContainSubstring("no space left on device"))
+
+		backup.Status.Phase = apiv1.BackupPhaseCompleted
+		outcome = getFinalBackupOutcome(cluster, backup, now)
+		Expect(outcome).ToNot(BeNil())
+		Expect(outcome.eventType).To(Equal("Normal"))
+		Expect(outcome.message).To(Equal(describeFinalBackup(backup)))
+	})
+})
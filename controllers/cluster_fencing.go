@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// fencingChanges are the instances fenced and unfenced since the
+// status of the cluster has been last updated
+type fencingChanges struct {
+	fenced   []string
+	unfenced []string
+}
+
+// setFencedInstances records the fenced instances in the status of the
+// cluster, returning what changed since the status has been last updated.
+// Nothing is recorded while the fencing annotation is not valid
+func setFencedInstances(cluster *apiv1.Cluster) fencingChanges {
+	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
+	if err != nil {
+		return fencingChanges{}
+	}
+
+	var changes fencingChanges
+	previous := stringset.From(cluster.Status.FencedInstances)
+	current := fencedInstances.ToList()
+	sort.Strings(current)
+	for _, instanceName := range current {
+		if !previous.Has(instanceName) {
+			changes.fenced = append(changes.fenced, instanceName)
+		}
+	}
+	for _, instanceName := range cluster.Status.FencedInstances {
+		if !fencedInstances.Has(instanceName) {
+			changes.unfenced = append(changes.unfenced, instanceName)
+		}
+	}
+
+	cluster.Status.FencedInstances = nil
+	if len(current) > 0 {
+		cluster.Status.FencedInstances = current
+	}
+
+	return changes
+}
+
+// recordFencingChanges emits an event for every instance fenced or
+// unfenced since the status of the cluster has been last updated
+func (r *ClusterReconciler) recordFencingChanges(cluster *apiv1.Cluster, changes fencingChanges) {
+	for _, instanceName := range changes.fenced {
+		r.Recorder.Eventf(cluster, "Normal", "Fenced", "%s has been fenced", describeFencedInstance(instanceName))
+	}
+	for _, instanceName := range changes.unfenced {
+		r.Recorder.Eventf(cluster, "Normal", "Unfenced", "%s has been unfenced", describeFencedInstance(instanceName))
+	}
+}
+
+// describeFencedInstance describes an element of the fenced instances list
+func describeFencedInstance(instanceName string) string {
+	if instanceName == utils.FenceAllServers {
+		return "Every instance"
+	}
+
+	return "Instance " + instanceName
+}
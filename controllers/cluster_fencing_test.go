@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setFencedInstances", func() {
+	It("reports the instances fenced and unfenced since the last status update", func() {
+		cluster := &v1.Cluster{}
+		cluster.Annotations = map[string]string{
+			utils.FencedInstanceAnnotation: `["cluster-example-2","cluster-example-1"]`,
+		}
+
+		changes := setFencedInstances(cluster)
+		Expect(changes.fenced).To(Equal([]string{"cluster-example-1", "cluster-example-2"}))
+		Expect(changes.unfenced).To(BeEmpty())
+		Expect(cluster.Status.FencedInstances).To(Equal([]string{"cluster-example-1", "cluster-example-2"}))
+
+		cluster.Annotations[utils.FencedInstanceAnnotation] = `["cluster-example-2"]`
+		changes = setFencedInstances(cluster)
+		Expect(changes.fenced).To(BeEmpty())
+		Expect(changes.unfenced).To(Equal([]string{"cluster-example-1"}))
+
+		delete(cluster.Annotations, utils.FencedInstanceAnnotation)
+		changes = setFencedInstances(cluster)
+		Expect(changes.unfenced).To(Equal([]string{"cluster-example-2"}))
+		Expect(cluster.Status.FencedInstances).To(BeNil())
+	})
+
+	It("keeps the recorded instances while the annotation is not valid", func() {
+		cluster := &v1.Cluster{}
+		cluster.Annotations = map[string]string{utils.FencedInstanceAnnotation: "not-a-list"}
+		cluster.Status.FencedInstances = []string{"*"}
+
+		changes := setFencedInstances(cluster)
+		Expect(changes.fenced).To(BeEmpty())
+		Expect(changes.unfenced).To(BeEmpty())
+		Expect(cluster.Status.FencedInstances).To(Equal([]string{"*"}))
+	})
+
+	It("describes the fencing of the whole cluster", func() {
+		Expect(describeFencedInstance("*")).To(Equal("Every instance"))
+		Expect(describeFencedInstance("cluster-example-1")).To(Equal("Instance cluster-example-1"))
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileClusterHistory ensures that the ClusterHistory of the cluster
+// exists, recording a change of the PostgreSQL image in use
+func (r *ClusterReconciler) reconcileClusterHistory(ctx context.Context, cluster *apiv1.Cluster) error {
+	image := cluster.GetImageName()
+	err := r.updateClusterHistory(ctx, cluster, func(history *apiv1.ClusterHistory) bool {
+		if history.Status.Image == image {
+			return false
+		}
+
+		history.AddEntry(apiv1.ClusterHistoryEntry{
+			Timestamp: metav1.Now(),
+			Type:      apiv1.ClusterHistoryImageChange,
+			From:      history.Status.Image,
+			To:        image,
+		})
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("while reconciling the cluster history: %w", err)
+	}
+
+	return nil
+}
+
+// addClusterHistoryEntry records a state transition in the ClusterHistory of
+// the cluster. Errors are only logged, given that a failure in keeping the
+// history shouldn't stop the operations on the cluster
+func (r *ClusterReconciler) addClusterHistoryEntry(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	entry apiv1.ClusterHistoryEntry,
+) {
+	entry.Timestamp = metav1.Now()
+	err := r.updateClusterHistory(ctx, cluster, func(history *apiv1.ClusterHistory) bool {
+		history.AddEntry(entry)
+		return true
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "while recording the cluster history",
+			"type", entry.Type, "from", entry.From, "to", entry.To)
+	}
+}
+
+// updateClusterHistory applies the passed function to the ClusterHistory of
+// the cluster, creating it when missing. The history is stored only if the
+// function reports a change
+func (r *ClusterReconciler) updateClusterHistory(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	update func(history *apiv1.ClusterHistory) bool,
+) error {
+	isRetriable := func(err error) bool {
+		return apierrs.IsConflict(err) || apierrs.IsAlreadyExists(err)
+	}
+
+	return retry.OnError(retry.DefaultRetry, isRetriable, func() error {
+		var history apiv1.ClusterHistory
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name}, &history)
+		switch {
+		case apierrs.IsNotFound(err):
+			history = apiv1.ClusterHistory{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      cluster.Name,
+					Namespace: cluster.Namespace,
+				},
+				Spec: apiv1.ClusterHistorySpec{
+					Cluster:    apiv1.LocalObjectReference{Name: cluster.Name},
+					MaxEntries: apiv1.DefaultClusterHistoryMaxEntries,
+				},
+			}
+			SetClusterOwnerAnnotationsAndLabels(&history.ObjectMeta, cluster)
+			if err := r.Create(ctx, &history); err != nil {
+				return err
+			}
+
+		case err != nil:
+			return err
+		}
+
+		if !update(&history) {
+			return nil
+		}
+
+		return r.Status().Update(ctx, &history)
+	})
+}
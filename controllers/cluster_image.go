@@ -0,0 +1,182 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/registry"
+)
+
+// reconcileImageDigest resolves the requested image to its digest, verifying
+// its signature when required, and records the result in the status of
+// the cluster. The instances keep using the last verified image until a
+// new one passes the verification.
+// An error is returned only when the cluster has no verified image yet,
+// as we can't create any instance in that case
+func (r *ClusterReconciler) reconcileImageDigest(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	if !cluster.IsImageDigestPinned() {
+		if cluster.Status.ImageDigest == nil {
+			return nil
+		}
+		origCluster := cluster.DeepCopy()
+		cluster.Status.ImageDigest = nil
+		return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	requestedImage := cluster.GetRequestedImageName()
+	verifySignature := cluster.IsImageSignatureVerificationEnabled()
+	if current := cluster.Status.ImageDigest; current != nil && current.Image == requestedImage &&
+		(current.SignatureVerified || !verifySignature) {
+		return nil
+	}
+
+	imageDigest, err := r.resolveImageDigest(ctx, cluster, requestedImage, verifySignature)
+	if err != nil {
+		r.Recorder.Eventf(cluster, "Warning", "ImageVerificationFailed",
+			"Cannot verify image %s: %v", requestedImage, err)
+		if cluster.Status.ImageDigest == nil {
+			return err
+		}
+		contextLogger.Warning("Cannot verify the requested image, keeping the current one",
+			"requestedImage", requestedImage,
+			"currentImage", cluster.GetImageName(),
+			"err", err)
+		return nil
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "ImageVerified",
+		"Image %s resolved to %s", requestedImage, imageDigest.Digest)
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.ImageDigest = imageDigest
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return fmt.Errorf("while recording the image digest: %w", err)
+	}
+
+	return nil
+}
+
+// resolveImageDigest resolves the passed image to its digest, using the pull
+// secrets of the cluster, and verifies its signature if requested
+func (r *ClusterReconciler) resolveImageDigest(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	image string,
+	verifySignature bool,
+) (*apiv1.ImageDigestStatus, error) {
+	credentials, err := r.getRegistryCredentials(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	registryClient := registry.NewClient(credentials)
+	digest, err := registryClient.ResolveDigest(ctx, image)
+	if err != nil {
+		return nil, fmt.Errorf("while resolving the image digest: %w", err)
+	}
+
+	if verifySignature {
+		keys, err := r.getImageVerificationKeys(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := registryClient.VerifySignature(ctx, image, digest, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiv1.ImageDigestStatus{
+		Image:             image,
+		Digest:            digest,
+		SignatureVerified: verifySignature,
+	}, nil
+}
+
+// getRegistryCredentials gets the registry credentials contained in the
+// pull secrets used by the instances of the cluster
+func (r *ClusterReconciler) getRegistryCredentials(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (registry.Credentials, error) {
+	secretNames := []string{fmt.Sprintf("%s-pull", cluster.Name)}
+	for _, secretReference := range cluster.Spec.ImagePullSecrets {
+		secretNames = append(secretNames, secretReference.Name)
+	}
+
+	credentials := registry.Credentials{}
+	for _, secretName := range secretNames {
+		var secret corev1.Secret
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &secret)
+		if apierrs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while getting pull secret %s: %w", secretName, err)
+		}
+
+		config, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			continue
+		}
+		if err := credentials.AddDockerConfig(config); err != nil {
+			return nil, fmt.Errorf("while reading pull secret %s: %w", secretName, err)
+		}
+	}
+
+	return credentials, nil
+}
+
+// getImageVerificationKeys gets the public keys used to verify
+// the signature of the image
+func (r *ClusterReconciler) getImageVerificationKeys(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for _, selector := range cluster.Spec.ImageVerification.PublicKeys {
+		var secret corev1.Secret
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: selector.Name}, &secret)
+		if err != nil {
+			return nil, fmt.Errorf("while getting public key secret %s: %w", selector.Name, err)
+		}
+
+		data, ok := secret.Data[selector.Key]
+		if !ok {
+			return nil, fmt.Errorf("missing key %s in secret %s", selector.Key, selector.Name)
+		}
+
+		secretKeys, err := registry.ParsePublicKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("while reading public key secret %s: %w", selector.Name, err)
+		}
+		keys = append(keys, secretKeys...)
+	}
+
+	return keys, nil
+}
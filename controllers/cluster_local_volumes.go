@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// getLocalVolumeHostname gets the hostname of the node owning the volume
+// bound to the passed PVC, or an empty string if that volume is not local
+// to a single node
+func (r *ClusterReconciler) getLocalVolumeHostname(
+	ctx context.Context,
+	pvc corev1.PersistentVolumeClaim,
+) (string, error) {
+	if pvc.Spec.VolumeName == "" {
+		return "", nil
+	}
+
+	var pv corev1.PersistentVolume
+	if err := r.Get(ctx, client.ObjectKey{Name: pvc.Spec.VolumeName}, &pv); err != nil {
+		if apierrs.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("while getting the volume bound to PVC %s: %w", pvc.Name, err)
+	}
+
+	return specs.GetLocalVolumeHostname(pv), nil
+}
+
+// isLocalVolumeNodeAvailable checks whether the node owning a local volume
+// exists and accepts new Pods. The instance using that volume can't run
+// anywhere else, so its Pod is not created until the node is available again
+// (e.g. at the end of a node maintenance)
+func (r *ClusterReconciler) isLocalVolumeNodeAvailable(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvc corev1.PersistentVolumeClaim,
+	hostname string,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels{corev1.LabelHostname: hostname}); err != nil {
+		return false, fmt.Errorf("while getting the node owning the volume of PVC %s: %w", pvc.Name, err)
+	}
+
+	if len(nodes.Items) == 0 {
+		contextLogger.Info("The node owning the local volume of the PVC doesn't exist, waiting for it",
+			"pvc", pvc.Name,
+			"hostname", hostname)
+		r.Recorder.Eventf(cluster, "Warning", "LocalVolumeNodeNotFound",
+			"Node %s, owning the local volume of PVC %s, doesn't exist. "+
+				"Delete the PVCs of this instance to recreate it on a different node",
+			hostname, pvc.Name)
+		return false, nil
+	}
+
+	for _, node := range nodes.Items {
+		if !node.Spec.Unschedulable {
+			return true, nil
+		}
+	}
+
+	contextLogger.Info("The node owning the local volume of the PVC is unschedulable, waiting for it",
+		"pvc", pvc.Name,
+		"hostname", hostname)
+	return false, nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -175,7 +176,7 @@ func (r *ClusterReconciler) verifyCAValidity(secret v1.Secret, cluster *apiv1.Cl
 		Certificate: publicKey,
 	}
 
-	isExpiring, _, err := caPair.IsExpiring()
+	isExpiring, _, err := caPair.IsExpiringWithin(cluster.GetCertificatesValidity().ExpiringCheckThreshold)
 	if err != nil {
 		return err
 	} else if isExpiring {
@@ -193,8 +194,12 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	var secret v1.Secret
 	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: secretName}, &secret)
 	if err == nil {
+		if err := r.ensureClusterLabel(ctx, cluster, &secret); err != nil {
+			return nil, err
+		}
+
 		// Verify the validity of this CA and renew it if needed
-		err = r.renewCASecret(ctx, &secret)
+		err = r.renewCASecret(ctx, cluster, &secret)
 		if err != nil {
 			return nil, err
 		}
@@ -204,26 +209,46 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 		return nil, err
 	}
 
-	caPair, err := certs.CreateRootCA(cluster.Name, cluster.Namespace)
+	caPair, err := certs.CreateRootCAWithDuration(
+		cluster.Name, cluster.Namespace, cluster.GetCertificatesValidity().Duration)
 	if err != nil {
 		return nil, fmt.Errorf("while creating the CA of the cluster: %w", err)
 	}
 
 	derivedCaSecret := caPair.GenerateCASecret(cluster.Namespace, secretName)
 	utils.SetAsOwnedBy(&derivedCaSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	setInheritedAnnotationsAndLabels(&derivedCaSecret.ObjectMeta, cluster)
+	utils.LabelClusterName(&derivedCaSecret.ObjectMeta, cluster.Name)
 	err = r.Create(ctx, derivedCaSecret)
 
 	return derivedCaSecret, err
 }
 
+// ensureClusterLabel adds the cluster label to a secret generated for the
+// cluster before the label was added to the certificates, as only the
+// labeled secrets are kept in the cache of the operator
+func (r *ClusterReconciler) ensureClusterLabel(ctx context.Context, cluster *apiv1.Cluster, secret *v1.Secret) error {
+	if _, owned := IsOwnedByCluster(secret); !owned {
+		return nil
+	}
+	if _, labeled := secret.Labels[utils.ClusterLabelName]; labeled {
+		return nil
+	}
+
+	origSecret := secret.DeepCopy()
+	utils.LabelClusterName(&secret.ObjectMeta, cluster.Name)
+	return r.Patch(ctx, secret, client.MergeFrom(origSecret))
+}
+
 // renewCASecret check if this CA secret is valid and renew it if needed
-func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret) error {
+func (r *ClusterReconciler) renewCASecret(ctx context.Context, cluster *apiv1.Cluster, secret *v1.Secret) error {
 	pair, err := certs.ParseCASecret(secret)
 	if err != nil {
 		return err
 	}
 
-	expiring, _, err := pair.IsExpiring()
+	validity := cluster.GetCertificatesValidity()
+	expiring, _, err := pair.IsExpiringWithin(validity.ExpiringCheckThreshold)
 	if err != nil {
 		return err
 	}
@@ -236,13 +261,19 @@ func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret
 		return err
 	}
 
-	err = pair.RenewCertificate(privateKey, nil)
+	err = pair.RenewCertificateWithDuration(privateKey, nil, validity.Duration)
 	if err != nil {
 		return err
 	}
 
 	secret.Data[certs.CACertKey] = pair.Certificate
-	return r.Update(ctx, secret)
+	if err = r.Update(ctx, secret); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(cluster, "Normal", "CertificateRenewed",
+		"Renewed the CA certificate in secret "+secret.Name)
+	return nil
 }
 
 // ensureServerLeafCertificate checks if we have a certificate for PostgreSQL and generate/renew it
@@ -300,17 +331,29 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	var secret v1.Secret
 	err := r.Get(ctx, secretName, &secret)
 	if err == nil {
-		return r.renewAndUpdateCertificate(ctx, caSecret, &secret)
+		if err := r.ensureClusterLabel(ctx, cluster, &secret); err != nil {
+			return err
+		}
+
+		regenerated, err := r.regenerateCertificateOnDNSNamesChange(
+			ctx, cluster, caSecret, &secret, commonName, usage, altDNSNames)
+		if err != nil || regenerated {
+			return err
+		}
+		return r.renewAndUpdateCertificate(ctx, cluster, caSecret, &secret)
 	}
 
-	serverSecret, err := generateCertificateFromCA(caSecret, commonName, usage, altDNSNames, secretName)
+	serverSecret, err := generateCertificateFromCA(
+		caSecret, commonName, usage, altDNSNames, secretName, cluster.GetCertificatesValidity().Duration)
 	if err != nil {
 		return err
 	}
 
 	utils.SetAsOwnedBy(&serverSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	setInheritedAnnotationsAndLabels(&serverSecret.ObjectMeta, cluster)
+	utils.LabelClusterName(&serverSecret.ObjectMeta, cluster.Name)
 	for k, v := range additionalLabels {
-		if serverSecret.Annotations == nil {
+		if serverSecret.Labels == nil {
 			serverSecret.Labels = make(map[string]string)
 		}
 		serverSecret.Labels[k] = v
@@ -318,6 +361,54 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	return r.Create(ctx, serverSecret)
 }
 
+// regenerateCertificateOnDNSNamesChange generates a new certificate in the
+// passed secret when the existing one doesn't cover all the requested DNS
+// names, as it happens when the user adds some alternative DNS names to the
+// cluster. Returns true if the certificate has been regenerated
+func (r *ClusterReconciler) regenerateCertificateOnDNSNamesChange(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	caSecret *v1.Secret,
+	secret *v1.Secret,
+	commonName string,
+	usage certs.CertType,
+	altDNSNames []string,
+) (bool, error) {
+	if len(altDNSNames) == 0 {
+		return false, nil
+	}
+
+	pair, err := certs.ParseServerSecret(secret)
+	if err != nil {
+		return false, err
+	}
+
+	hasDNSNames, err := pair.HasDNSNames(altDNSNames)
+	if err != nil || hasDNSNames {
+		return false, err
+	}
+
+	newSecret, err := generateCertificateFromCA(
+		caSecret,
+		commonName,
+		usage,
+		altDNSNames,
+		client.ObjectKeyFromObject(secret),
+		cluster.GetCertificatesValidity().Duration)
+	if err != nil {
+		return false, err
+	}
+
+	secret.Data = newSecret.Data
+	if err = r.Update(ctx, secret); err != nil {
+		return false, err
+	}
+
+	r.Recorder.Event(cluster, "Normal", "CertificateRegenerated",
+		"Regenerated the certificate in secret "+secret.Name+" to cover the new alternative DNS names")
+	return true, nil
+}
+
 // generateCertificateFromCA create a certificate secret using the provided CA secret
 func generateCertificateFromCA(
 	caSecret *v1.Secret,
@@ -325,13 +416,14 @@ func generateCertificateFromCA(
 	usage certs.CertType,
 	altDNSNames []string,
 	secretName client.ObjectKey,
+	duration time.Duration,
 ) (*v1.Secret, error) {
 	caPair, err := certs.ParseCASecret(caSecret)
 	if err != nil {
 		return nil, err
 	}
 
-	serverPair, err := caPair.CreateAndSignPair(commonName, usage, altDNSNames)
+	serverPair, err := caPair.CreateAndSignPairWithDuration(commonName, usage, altDNSNames, duration)
 	if err != nil {
 		return nil, err
 	}
@@ -344,16 +436,23 @@ func generateCertificateFromCA(
 // the secret
 func (r *ClusterReconciler) renewAndUpdateCertificate(
 	ctx context.Context,
+	cluster *apiv1.Cluster,
 	caSecret *v1.Secret,
 	secret *v1.Secret,
 ) error {
-	hasBeenRenewed, err := certs.RenewLeafCertificate(caSecret, secret)
+	hasBeenRenewed, err := certs.RenewLeafCertificateWithValidity(caSecret, secret, cluster.GetCertificatesValidity())
 	if err != nil {
 		return err
 	}
-	if hasBeenRenewed {
-		return r.Update(ctx, secret)
+	if !hasBeenRenewed {
+		return nil
+	}
+
+	if err = r.Update(ctx, secret); err != nil {
+		return err
 	}
 
+	r.Recorder.Event(cluster, "Normal", "CertificateRenewed",
+		"Renewed the certificate in secret "+secret.Name)
 	return nil
 }
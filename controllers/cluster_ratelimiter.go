@@ -0,0 +1,38 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+)
+
+// newClusterRateLimiter creates the rate limiter of the requeues of the
+// clusters. Every cluster is requeued with an exponential delay, reset
+// when its reconciliation completes, while the overall number of requeues
+// is limited too, so that a large number of clusters doesn't overwhelm
+// the API server
+func newClusterRateLimiter(config *configuration.Data) ratelimiter.RateLimiter {
+	requeueRate := config.GetRequeueRate()
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(config.GetRequeueBaseDelay(), config.GetRequeueMaxDelay()),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(requeueRate), 10*requeueRate)},
+	)
+}
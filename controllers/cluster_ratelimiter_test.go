@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster rate limiter", func() {
+	config := &configuration.Data{RequeueBaseDelay: 100, RequeueMaxDelay: 1, RequeueRate: 1000}
+	first := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "first"}}
+	second := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "second"}}
+
+	It("increases the delay of the requeues of a cluster up to the maximum", func() {
+		limiter := newClusterRateLimiter(config)
+		Expect(limiter.When(first)).To(Equal(100 * time.Millisecond))
+		Expect(limiter.When(first)).To(Equal(200 * time.Millisecond))
+		Expect(limiter.When(first)).To(Equal(400 * time.Millisecond))
+		Expect(limiter.When(first)).To(Equal(800 * time.Millisecond))
+		Expect(limiter.When(first)).To(Equal(time.Second))
+		Expect(limiter.NumRequeues(first)).To(Equal(5))
+	})
+
+	It("delays the requeues of every cluster independently", func() {
+		limiter := newClusterRateLimiter(config)
+		Expect(limiter.When(first)).To(Equal(100 * time.Millisecond))
+		Expect(limiter.When(first)).To(Equal(200 * time.Millisecond))
+		Expect(limiter.When(second)).To(Equal(100 * time.Millisecond))
+	})
+
+	It("resets the delay once the reconciliation of the cluster completes", func() {
+		limiter := newClusterRateLimiter(config)
+		Expect(limiter.When(first)).To(Equal(100 * time.Millisecond))
+		Expect(limiter.When(first)).To(Equal(200 * time.Millisecond))
+		limiter.Forget(first)
+		Expect(limiter.When(first)).To(Equal(100 * time.Millisecond))
+	})
+})
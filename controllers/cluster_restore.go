@@ -18,8 +18,11 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -94,6 +97,7 @@ func getOrphanPVCs(
 	if err := c.List(
 		ctx,
 		&pvcList,
+		client.InNamespace(cluster.Namespace),
 		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
 	); err != nil {
 		return nil, err
@@ -149,16 +153,8 @@ func restoreOrphanPVCs(
 ) error {
 	for i := range pvcs {
 		pvc := &pvcs[i]
-		if pvc.Annotations == nil {
-			pvc.Annotations = map[string]string{}
-		}
-
 		pvcOrig := pvc.DeepCopy()
-		SetClusterOwnerAnnotationsAndLabels(&pvc.ObjectMeta, cluster)
-		pvc.Annotations[specs.PVCStatusAnnotationName] = specs.PVCStatusReady
-		// we clean hibernation metadata if it exists
-		delete(pvc.Annotations, utils.HibernateClusterManifestAnnotationName)
-		delete(pvc.Annotations, utils.HibernatePgControlDataAnnotationName)
+		setAdoptedPVCMetadata(pvc, cluster, specs.PVCStatusReady)
 
 		if err := c.Patch(ctx, pvc, client.MergeFrom(pvcOrig)); err != nil {
 			return err
@@ -167,3 +163,75 @@ func restoreOrphanPVCs(
 
 	return nil
 }
+
+// adoptExistingVolume turns the PVC requested by the existingVolume bootstrap
+// method into the PGDATA volume of the instance with the passed serial, which
+// will be initialized by the bootstrap job
+func adoptExistingVolume(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+	nodeSerial int,
+) error {
+	pvcOrig := pvc.DeepCopy()
+	if pvc.Labels == nil {
+		pvc.Labels = map[string]string{}
+	}
+	setAdoptedPVCMetadata(pvc, cluster, specs.PVCStatusInitializing)
+	pvc.Labels[utils.InstanceNameLabelName] = specs.GetInstanceName(cluster.Name, nodeSerial)
+	pvc.Labels[utils.PvcRoleLabelName] = string(utils.PVCRolePgData)
+	pvc.Annotations[specs.ClusterSerialAnnotationName] = strconv.Itoa(nodeSerial)
+
+	return c.Patch(ctx, pvc, client.MergeFrom(pvcOrig))
+}
+
+// setAdoptedPVCMetadata sets the owner metadata of a PVC being adopted by
+// the cluster, marking it with the passed status
+func setAdoptedPVCMetadata(pvc *corev1.PersistentVolumeClaim, cluster *apiv1.Cluster, status string) {
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+
+	SetClusterOwnerAnnotationsAndLabels(&pvc.ObjectMeta, cluster)
+	pvc.Annotations[specs.PVCStatusAnnotationName] = status
+	// we clean hibernation metadata if it exists
+	delete(pvc.Annotations, utils.HibernateClusterManifestAnnotationName)
+	delete(pvc.Annotations, utils.HibernatePgControlDataAnnotationName)
+}
+
+// getExistingVolume gets the PVC that the cluster has been asked to adopt via
+// the existingVolume bootstrap method, returning nil when it doesn't exist.
+// The claim must be named after the first instance, whose storage it becomes.
+// Like for orphan PVCs, a claim having owner metadata is refused, as it
+// is already managed by somebody else
+func getExistingVolume(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+) (*corev1.PersistentVolumeClaim, error) {
+	pvcName := cluster.Spec.Bootstrap.ExistingVolume.PersistentVolumeClaimName
+	expectedPVCName := specs.GetPVCName(*cluster, specs.GetInstanceName(cluster.Name, 1), utils.PVCRolePgData)
+	if pvcName != expectedPVCName {
+		// This error should have been caught by the validating webhook
+		return nil, fmt.Errorf("pvc %s can't be adopted, as it must be named %s", pvcName, expectedPVCName)
+	}
+
+	var pvc corev1.PersistentVolumeClaim
+	err := c.Get(ctx, client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      pvcName,
+	}, &pvc)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pvc.OwnerReferences) != 0 {
+		return nil, fmt.Errorf("pvc %s has owner metadata and can't be adopted", pvc.Name)
+	}
+
+	return &pvc, nil
+}
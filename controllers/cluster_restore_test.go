@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("existingVolume bootstrap", func() {
+	newCluster := func(pvcName string) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-adopted",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					ExistingVolume: &apiv1.BootstrapExistingVolume{
+						PersistentVolumeClaimName: pvcName,
+					},
+				},
+			},
+		}
+	}
+
+	newPVC := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+
+	It("refuses a PVC not named after the first instance", func() {
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPVC("legacy-pgdata")).Build()
+		_, err := getExistingVolume(context.Background(), cli, newCluster("legacy-pgdata"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("refuses a PVC having owner metadata", func() {
+		pvc := newPVC("cluster-adopted-1")
+		pvc.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+			Name:       "another-cluster",
+			UID:        "f1bd1bdc-6d4b-4c53-a4b5-5e0e5c3f4b3a",
+		}}
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pvc).Build()
+		_, err := getExistingVolume(context.Background(), cli, newCluster("cluster-adopted-1"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("waits for a missing PVC", func() {
+		cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+		pvc, err := getExistingVolume(context.Background(), cli, newCluster("cluster-adopted-1"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc).To(BeNil())
+	})
+
+	It("adopts the PVC as the PGDATA volume of the first instance", func() {
+		ctx := context.Background()
+		cluster := newCluster("cluster-adopted-1")
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPVC("cluster-adopted-1")).Build()
+
+		pvc, err := getExistingVolume(ctx, cli, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc).ToNot(BeNil())
+		Expect(adoptExistingVolume(ctx, cli, cluster, pvc, 1)).To(Succeed())
+
+		var adoptedPVC corev1.PersistentVolumeClaim
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(pvc), &adoptedPVC)).To(Succeed())
+		Expect(adoptedPVC.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-adopted"))
+		Expect(adoptedPVC.Labels).To(HaveKeyWithValue(utils.InstanceNameLabelName, "cluster-adopted-1"))
+		Expect(adoptedPVC.Labels).To(HaveKeyWithValue(utils.PvcRoleLabelName, string(utils.PVCRolePgData)))
+		Expect(adoptedPVC.Annotations).To(HaveKeyWithValue(specs.ClusterSerialAnnotationName, "1"))
+		Expect(adoptedPVC.Annotations).To(HaveKeyWithValue(specs.PVCStatusAnnotationName, specs.PVCStatusInitializing))
+		Expect(adoptedPVC.OwnerReferences).To(HaveLen(1))
+		Expect(adoptedPVC.OwnerReferences[0].Name).To(Equal("cluster-adopted"))
+	})
+})
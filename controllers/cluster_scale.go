@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,6 +29,9 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // scaleDownCluster handles the scaling down operations of a PostgreSQL cluster.
@@ -52,8 +56,12 @@ func (r *ClusterReconciler) scaleDownCluster(
 		return nil
 	}
 
-	// Is there one pod to be deleted?
-	sacrificialInstance := getSacrificialInstance(resources.instances.Items)
+	// Is there one pod to be deleted? We prefer the ones belonging
+	// to the instance groups having more instances than requested
+	sacrificialInstance := getSacrificialInstance(getInstancesOverGroupQuota(cluster, resources.instances.Items))
+	if sacrificialInstance == nil {
+		sacrificialInstance = getSacrificialInstance(resources.instances.Items)
+	}
 	if sacrificialInstance == nil {
 		contextLogger.Info("There are no instances to be sacrificed. Wait for the next sync loop")
 		return nil
@@ -71,19 +79,25 @@ func (r *ClusterReconciler) scaleDownCluster(
 		}
 	}
 
-	// Let's drop the PVC too
-	pvc := v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      sacrificialInstance.Name,
-			Namespace: sacrificialInstance.Namespace,
-		},
-	}
+	// Let's drop the PVC too, unless it should be kept for a later scale up
+	if cluster.GetScaleDownPVCReclaimPolicy() == apiv1.PVCReclaimPolicyRetain {
+		if err := r.detachInstancePVCs(ctx, cluster, resources, sacrificialInstance.Name); err != nil {
+			return fmt.Errorf("scaling down node (pvc) %v: %w", sacrificialInstance.Name, err)
+		}
+	} else {
+		pvc := v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sacrificialInstance.Name,
+				Namespace: sacrificialInstance.Namespace,
+			},
+		}
 
-	err := r.Delete(ctx, &pvc)
-	if err != nil {
-		// Ignore if NotFound, otherwise report the error
-		if !apierrs.IsNotFound(err) {
-			return fmt.Errorf("scaling down node (pvc) %v: %v", sacrificialInstance.Name, err)
+		err := r.Delete(ctx, &pvc)
+		if err != nil {
+			// Ignore if NotFound, otherwise report the error
+			if !apierrs.IsNotFound(err) {
+				return fmt.Errorf("scaling down node (pvc) %v: %v", sacrificialInstance.Name, err)
+			}
 		}
 	}
 
@@ -93,7 +107,7 @@ func (r *ClusterReconciler) scaleDownCluster(
 			// This job was working against the PVC of this Pod,
 			// let's remove it
 			foreground := metav1.DeletePropagationForeground
-			err = r.Delete(
+			err := r.Delete(
 				ctx,
 				&resources.jobs.Items[idx],
 				&client.DeleteOptions{
@@ -111,3 +125,163 @@ func (r *ClusterReconciler) scaleDownCluster(
 
 	return nil
 }
+
+// detachInstancePVCs removes the owner reference from the PVCs of the
+// passed instance, keeping them after the instance has been removed.
+// Detached PVCs are adopted again by a later scale up
+func (r *ClusterReconciler) detachInstancePVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instanceName string,
+) error {
+	for idx := range resources.pvcs.Items {
+		pvc := &resources.pvcs.Items[idx]
+		if !specs.DoesPVCBelongToInstance(cluster, instanceName, pvc.Name) {
+			continue
+		}
+
+		pvcOrig := pvc.DeepCopy()
+		pvc.OwnerReferences = nil
+		if err := r.Patch(ctx, pvc, client.MergeFrom(pvcOrig)); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// adoptDetachedPVCs adopts the PVCs of one of the instances removed
+// while scaling down with the `retain` reclaim policy, returning
+// true when they have been found. The operator will then recreate
+// that instance on top of them, instead of cloning a new replica
+func (r *ClusterReconciler) adoptDetachedPVCs(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	pvcs, err := getOrphanPVCs(ctx, r.Client, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	pvcs = getDetachedInstancePVCs(cluster, pvcs)
+	if len(pvcs) == 0 {
+		return false, nil
+	}
+
+	instanceName := pvcs[0].Labels[utils.InstanceNameLabelName]
+	r.Recorder.Eventf(cluster, "Normal", "ScaleUp",
+		"Scaling up: reusing the PVCs of instance %v", instanceName)
+	log.FromContext(ctx).Info("Adopting the PVCs retained while scaling down",
+		"instance", instanceName)
+
+	return true, restoreOrphanPVCs(ctx, r.Client, cluster, pvcs)
+}
+
+// getDetachedInstancePVCs gets the PVCs of the instance having the lowest
+// serial among the ones having every expected PVC in the passed list
+func getDetachedInstancePVCs(
+	cluster *apiv1.Cluster,
+	pvcs []v1.PersistentVolumeClaim,
+) []v1.PersistentVolumeClaim {
+	var result []v1.PersistentVolumeClaim
+	lowestSerial := 0
+	for _, pvc := range pvcs {
+		serial, err := specs.GetNodeSerial(pvc.ObjectMeta)
+		if err != nil || (lowestSerial != 0 && serial >= lowestSerial) {
+			continue
+		}
+
+		instanceName := specs.GetInstanceName(cluster.Name, serial)
+		var instancePVCs []v1.PersistentVolumeClaim
+		for _, instancePVC := range pvcs {
+			if specs.DoesPVCBelongToInstance(cluster, instanceName, instancePVC.Name) {
+				instancePVCs = append(instancePVCs, instancePVC)
+			}
+		}
+
+		expectedPVCs := 1
+		if cluster.ShouldCreateWalArchiveVolume() {
+			expectedPVCs++
+		}
+		if len(instancePVCs) == expectedPVCs {
+			result = instancePVCs
+			lowestSerial = serial
+		}
+	}
+
+	return result
+}
+
+// getInstancesOverGroupQuota gets the active Pods belonging to an instance
+// group having more instances than requested
+func getInstancesOverGroupQuota(cluster *apiv1.Cluster, pods []v1.Pod) []v1.Pod {
+	activePods := utils.FilterActivePods(pods)
+	instanceNames := make([]string, len(activePods))
+	for idx := range activePods {
+		instanceNames[idx] = activePods[idx].Name
+	}
+	overQuota := stringset.From(cluster.GetInstancesOverGroupQuota(instanceNames))
+
+	var result []v1.Pod
+	for idx := range activePods {
+		if overQuota.Has(activePods[idx].Name) {
+			result = append(result, activePods[idx])
+		}
+	}
+
+	return result
+}
+
+// getUnneededJoinJobs gets the running jobs cloning a new replica that is no
+// longer needed, because the cluster has been scaled down in the meantime
+func getUnneededJoinJobs(cluster *apiv1.Cluster, jobs []batchv1.Job) []batchv1.Job {
+	if cluster.Status.Instances == 0 || cluster.Status.Instances < cluster.Spec.Instances {
+		return nil
+	}
+
+	var result []batchv1.Job
+	for _, job := range jobs {
+		if job.Labels[utils.JobRoleLabelName] == specs.JobRoleJoin && !utils.IsJobComplete(job) {
+			result = append(result, job)
+		}
+	}
+
+	return result
+}
+
+// abortUnneededJoins deletes the jobs, and the PVCs, of the replicas being
+// created that are no longer needed, so that a scale down requested while
+// scaling up doesn't have to wait for the new replicas to be cloned.
+// It returns true if at least one job has been deleted
+func (r *ClusterReconciler) abortUnneededJoins(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (bool, error) {
+	unneededJobs := getUnneededJoinJobs(cluster, resources.jobs.Items)
+	for idx := range unneededJobs {
+		job := &unneededJobs[idx]
+		instanceName := job.Labels[utils.InstanceNameLabelName]
+
+		r.Recorder.Eventf(cluster, "Normal", "ScaleDown",
+			"Scaling down: aborting the creation of instance %v", instanceName)
+		log.FromContext(ctx).Info("Aborting the creation of an instance no longer needed",
+			"job", job.Name, "instance", instanceName)
+
+		foreground := metav1.DeletePropagationForeground
+		err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &foreground})
+		if err != nil && !apierrs.IsNotFound(err) {
+			return false, fmt.Errorf("aborting the creation of %v (job): %w", instanceName, err)
+		}
+
+		for pvcIdx := range resources.pvcs.Items {
+			pvc := &resources.pvcs.Items[pvcIdx]
+			if instanceName == "" || !specs.DoesPVCBelongToInstance(cluster, instanceName, pvc.Name) {
+				continue
+			}
+			if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+				return false, fmt.Errorf("aborting the creation of %v (pvc): %w", instanceName, err)
+			}
+		}
+	}
+
+	return len(unneededJobs) > 0, nil
+}
@@ -22,8 +22,13 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -64,3 +69,117 @@ var _ = Describe("cluster_scale unit tests", func() {
 		Expect(apierrors.IsNotFound(err)).To(BeTrue())
 	})
 })
+
+var _ = Describe("unneeded join jobs", func() {
+	newJob := func(role string, complete bool) batchv1.Job {
+		job := batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-example-4-" + role,
+				Labels: map[string]string{utils.JobRoleLabelName: role},
+			},
+		}
+		if complete {
+			job.Status.Succeeded = 1
+		}
+		return job
+	}
+
+	jobs := []batchv1.Job{
+		newJob(specs.JobRoleJoin, false),
+		newJob(specs.JobRoleJoin, true),
+		newJob("initdb", false),
+	}
+
+	It("keeps the jobs while scaling up", func() {
+		cluster := &apiv1.Cluster{
+			Spec:   apiv1.ClusterSpec{Instances: 4},
+			Status: apiv1.ClusterStatus{Instances: 3},
+		}
+		Expect(getUnneededJoinJobs(cluster, jobs)).To(BeEmpty())
+	})
+
+	It("keeps the jobs while creating the first instance", func() {
+		cluster := &apiv1.Cluster{Spec: apiv1.ClusterSpec{Instances: 0}}
+		Expect(getUnneededJoinJobs(cluster, jobs)).To(BeEmpty())
+	})
+
+	It("finds the running join jobs after a scale down", func() {
+		cluster := &apiv1.Cluster{
+			Spec:   apiv1.ClusterSpec{Instances: 3},
+			Status: apiv1.ClusterStatus{Instances: 3},
+		}
+		unneededJobs := getUnneededJoinJobs(cluster, jobs)
+		Expect(unneededJobs).To(HaveLen(1))
+		Expect(unneededJobs[0].Name).To(Equal("cluster-example-4-join"))
+	})
+})
+
+var _ = Describe("instances over the group quota", func() {
+	newPod := func(name string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	It("selects the instances of the groups having too many instances", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Instances: 2,
+				InstanceGroups: []apiv1.InstanceGroup{
+					{Name: "reporting", Instances: 1},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				InstanceGroups: map[string]string{
+					"cluster-example-2": "reporting",
+					"cluster-example-3": "reporting",
+				},
+			},
+		}
+		pods := []corev1.Pod{
+			newPod("cluster-example-1"),
+			newPod("cluster-example-2"),
+			newPod("cluster-example-3"),
+		}
+
+		result := getInstancesOverGroupQuota(cluster, pods)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Name).To(Equal("cluster-example-2"))
+		Expect(result[1].Name).To(Equal("cluster-example-3"))
+	})
+})
+
+var _ = Describe("detached PVCs", func() {
+	newPVC := func(name string, serial string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{specs.ClusterSerialAnnotationName: serial},
+			},
+		}
+	}
+
+	It("selects the instance with the lowest serial having every PVC", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				WalStorage: &apiv1.StorageConfiguration{Size: "1Gi"},
+			},
+		}
+		pvcs := []corev1.PersistentVolumeClaim{
+			newPVC("cluster-example-5", "5"),
+			newPVC("cluster-example-5-wal", "5"),
+			newPVC("cluster-example-3", "3"),
+			newPVC("cluster-example-4", "4"),
+			newPVC("cluster-example-4-wal", "4"),
+		}
+
+		result := getDetachedInstancePVCs(cluster, pvcs)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Name).To(Equal("cluster-example-4"))
+		Expect(result[1].Name).To(Equal("cluster-example-4-wal"))
+	})
+
+	It("returns nothing when there are no detached PVCs", func() {
+		cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		Expect(getDetachedInstancePVCs(cluster, nil)).To(BeEmpty())
+	})
+})
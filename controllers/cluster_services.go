@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileAdditionalServices ensures that the additional services requested
+// in the cluster specification exist and are aligned with it, and removes
+// the ones which are not requested anymore
+func (r *ClusterReconciler) reconcileAdditionalServices(ctx context.Context, cluster *apiv1.Cluster) error {
+	requestedServices := stringset.New()
+	for _, additional := range cluster.GetAdditionalServices() {
+		requestedServices.Put(additional.Name)
+
+		service := specs.CreateClusterAdditionalService(*cluster, additional)
+		if err := r.createOrPatchService(ctx, cluster, service); err != nil {
+			return err
+		}
+	}
+
+	var services corev1.ServiceList
+	if err := r.List(
+		ctx,
+		&services,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.AdditionalServiceLabelName},
+	); err != nil {
+		return fmt.Errorf("while listing the additional services: %w", err)
+	}
+
+	for idx := range services.Items {
+		service := &services.Items[idx]
+		if requestedServices.Has(service.Name) {
+			continue
+		}
+		if _, owned := IsOwnedByCluster(service); !owned {
+			continue
+		}
+
+		r.Recorder.Event(cluster, "Normal", "DeletingService", "Deleting Service "+service.Name)
+		if err := r.Delete(ctx, service); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting service %s: %w", service.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileInstanceServices ensures that, when requested, every instance
+// has its own service, and removes the services of the instances which
+// don't exist anymore
+func (r *ClusterReconciler) reconcileInstanceServices(ctx context.Context, cluster *apiv1.Cluster) error {
+	instances := stringset.New()
+	if cluster.GetPerInstanceServiceTemplate() != nil {
+		for _, instanceName := range cluster.Status.InstanceNames {
+			instances.Put(instanceName)
+
+			service := specs.CreateInstanceService(*cluster, instanceName)
+			if err := r.createOrPatchService(ctx, cluster, service); err != nil {
+				return err
+			}
+		}
+	}
+
+	var services corev1.ServiceList
+	if err := r.List(
+		ctx,
+		&services,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.InstanceNameLabelName},
+	); err != nil {
+		return fmt.Errorf("while listing the services of the instances: %w", err)
+	}
+
+	for idx := range services.Items {
+		service := &services.Items[idx]
+		if instances.Has(service.Labels[utils.InstanceNameLabelName]) {
+			continue
+		}
+		if _, owned := IsOwnedByCluster(service); !owned {
+			continue
+		}
+
+		r.Recorder.Event(cluster, "Normal", "DeletingService", "Deleting Service "+service.Name)
+		if err := r.Delete(ctx, service); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting service %s: %w", service.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// createOrPatchService creates the passed service or, if it already
+// exists and is owned by the cluster, aligns it with the passed one.
+// The service is server-side applied, so the fields assigned by
+// Kubernetes, like the cluster IP and the node ports, and the ones
+// set by other tools are kept
+func (r *ClusterReconciler) createOrPatchService(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	service *corev1.Service,
+) error {
+	contextLogger := log.FromContext(ctx)
+	SetClusterOwnerAnnotationsAndLabels(&service.ObjectMeta, cluster)
+
+	var currentService corev1.Service
+	err := r.Get(ctx, client.ObjectKeyFromObject(service), &currentService)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting service %s: %w", service.Name, err)
+	}
+
+	exists := err == nil
+	if exists {
+		if _, owned := IsOwnedByCluster(&currentService); !owned {
+			contextLogger.Info("Service not owned by the cluster, skipping its update", "service", service.Name)
+			return nil
+		}
+	}
+
+	if err := applyObject(ctx, r.Client, service); err != nil {
+		return fmt.Errorf("while applying service %s: %w", service.Name, err)
+	}
+
+	if exists && service.ResourceVersion != currentService.ResourceVersion {
+		r.Recorder.Event(cluster, "Normal", "UpdatingService", "Updating Service "+service.Name)
+	}
+
+	return nil
+}
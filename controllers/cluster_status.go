@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -33,6 +34,7 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/strings/slices"
@@ -43,9 +45,11 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
@@ -121,7 +125,12 @@ func (i InstanceStatusError) Error() string {
 func (r *ClusterReconciler) getManagedResources(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
-) (*managedResources, error) {
+) (resources *managedResources, err error) {
+	ctx, span := tracing.StartSpan(ctx, "getManagedResources")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// Update the status of this resource
 	instances, err := r.getManagedInstances(ctx, cluster)
 	if err != nil {
@@ -249,14 +258,25 @@ func (r *ClusterReconciler) setPVCStatusReady(
 	return r.Patch(ctx, pvc, client.MergeFrom(oldPvc))
 }
 
-func (r *ClusterReconciler) updateResourceStatus(
+// resourceStatusChanges are the changes detected while setting the
+// status from the managed resources, to be recorded once it is written
+type resourceStatusChanges struct {
+	fencing                fencingChanges
+	completedPrimaryChange apiv1.PrimaryChangeReason
+}
+
+// setResourceStatus sets the status fields depending on the managed
+// resources. The status is not written, to let the caller update it
+// together with the other fields with a single patch
+func (r *ClusterReconciler) setResourceStatus(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	resources *managedResources,
-) error {
-	// Retrieve the cluster key
-
-	existingClusterStatus := cluster.Status
+) (changes resourceStatusChanges, err error) {
+	ctx, span := tracing.StartSpan(ctx, "setResourceStatus")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
 
 	newPVCCount := int32(len(resources.pvcs.Items))
 	cluster.Status.PVCCount = newPVCCount
@@ -268,6 +288,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 		resources.pvcs.Items,
 	)
 	cluster.Status.InstanceNames = pvcClassification.InstanceNames
+	pruneInstanceGroups(cluster)
 	cluster.Status.DanglingPVC = pvcClassification.Dangling
 	cluster.Status.HealthyPVC = pvcClassification.Healthy
 	cluster.Status.InitializingPVC = pvcClassification.Initializing
@@ -282,6 +303,12 @@ func (r *ClusterReconciler) updateResourceStatus(
 	newInstances := len(filteredPods)
 	cluster.Status.Instances = newInstances
 	cluster.Status.ReadyInstances = utils.CountReadyPods(filteredPods)
+	cluster.Status.ReadyInstancesSummary = fmt.Sprintf("%d/%d", cluster.Status.ReadyInstances, cluster.Spec.Instances)
+	setDegradedCondition(cluster)
+	cluster.Status.Selector = labels.SelectorFromSet(map[string]string{
+		utils.ClusterLabelName: cluster.Name,
+		utils.PodRoleLabelName: string(utils.PodRoleInstance),
+	}).String()
 
 	// Count jobs
 	newJobs := int32(len(resources.jobs.Items))
@@ -294,7 +321,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 		ctx,
 		resources.instances.Items,
 		resources.nodes,
-		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint,
+		cluster.GetTopologyLabelNames(),
 	)
 
 	// Services
@@ -324,10 +351,23 @@ func (r *ClusterReconciler) updateResourceStatus(
 				"targetPrimary", cluster.Status.TargetPrimary,
 				"instances", resources.instances)
 			cluster.Status.TargetPrimary = cluster.Status.CurrentPrimary
+			cluster.Status.TargetPrimaryReason = ""
 			cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
 		}
 	}
 
+	// If the target primary has been promoted, the switchover or the
+	// failover is completed and will be recorded once the status is updated
+	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary &&
+		cluster.Status.TargetPrimaryReason != "" {
+		changes.completedPrimaryChange = cluster.Status.TargetPrimaryReason
+		cluster.Status.TargetPrimaryReason = ""
+	}
+
+	// The changes to the fenced instances are recorded once the
+	// status is updated
+	changes.fencing = setFencedInstances(cluster)
+
 	// set server CA secret,TLS secret and alternative DNS names with default values
 	cluster.Status.Certificates.ServerCASecret = cluster.GetServerCASecretName()
 	cluster.Status.Certificates.ServerTLSSecret = cluster.GetServerTLSSecretName()
@@ -340,6 +380,13 @@ func (r *ClusterReconciler) updateResourceStatus(
 	// on this cluster
 	cluster.Status.CommitHash = versions.Info.Commit
 
+	// Expose the application credentials to the Service Binding
+	// implementations, when they are stored in a Kubernetes secret
+	cluster.Status.Binding = nil
+	if cluster.UsesApplicationSecret() {
+		cluster.Status.Binding = &apiv1.LocalObjectReference{Name: cluster.GetApplicationSecretName()}
+	}
+
 	if poolerIntegrations, err := r.getPoolerIntegrationsNeeded(ctx, cluster); err == nil {
 		cluster.Status.PoolerIntegrations = poolerIntegrations
 	} else {
@@ -349,29 +396,144 @@ func (r *ClusterReconciler) updateResourceStatus(
 	// Set the current hash code of the operator binary inside the status.
 	// This is used by the instance manager to validate if a certain binary is
 	// valid or not
-	var err error
 	cluster.Status.OperatorHash, err = executablehash.Get()
 	if err != nil {
-		return err
+		return changes, err
 	}
 
 	// refresh expiration dates of certifications
 	if err := r.refreshCertsExpirations(ctx, cluster); err != nil {
-		return err
+		return changes, err
 	}
 
 	if err := r.refreshSecretResourceVersions(ctx, cluster); err != nil {
-		return err
+		return changes, err
 	}
 
 	if err := r.refreshConfigMapResourceVersions(ctx, cluster); err != nil {
-		return err
+		return changes, err
 	}
 
-	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
-		return r.Status().Update(ctx, cluster)
+	return changes, nil
+}
+
+// recordResourceStatusChanges records the changes detected while
+// setting the status from the managed resources
+func (r *ClusterReconciler) recordResourceStatusChanges(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	changes resourceStatusChanges,
+) {
+	r.recordFencingChanges(cluster, changes.fencing)
+	if changes.completedPrimaryChange != "" {
+		r.recordPrimaryChange(cluster, changes.completedPrimaryChange)
+		r.addClusterHistoryEntry(ctx, cluster, apiv1.ClusterHistoryEntry{
+			Type:   apiv1.ClusterHistoryPrimaryChange,
+			To:     cluster.Status.CurrentPrimary,
+			Reason: string(changes.completedPrimaryChange),
+		})
 	}
-	return nil
+}
+
+// statusPatchAttempts is the number of times the changes to the status
+// of a cluster are written before giving up because of the conflicts
+const statusPatchAttempts = 5
+
+// patchClusterStatus writes, with a single patch, the changes made to the
+// status of the cluster since the passed copy has been taken. Nothing is
+// written when the status didn't change.
+// When the cluster has been written by somebody else in the meantime, as
+// the instance manager or the backup controller do, the changes are applied
+// again to the latest version of the cluster, unless the other writer
+// changed the same fields: in that case the conflict is returned, and the
+// reconciliation needs to evaluate the cluster again
+func (r *ClusterReconciler) patchClusterStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	origCluster *apiv1.Cluster,
+) error {
+	if reflect.DeepEqual(origCluster.Status, cluster.Status) {
+		return nil
+	}
+
+	err := r.Status().Patch(ctx, cluster,
+		client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}))
+	for attempt := 1; attempt < statusPatchAttempts && apierrs.IsConflict(err); attempt++ {
+		var latestCluster apiv1.Cluster
+		if getErr := r.Get(ctx, client.ObjectKeyFromObject(cluster), &latestCluster); getErr != nil {
+			return getErr
+		}
+
+		mergedCluster := latestCluster.DeepCopy()
+		if !mergeClusterStatusChanges(&origCluster.Status, &cluster.Status, &mergedCluster.Status) {
+			return err
+		}
+
+		log.FromContext(ctx).Debug("Applying the status changes again after a conflict",
+			"resourceVersion", latestCluster.ResourceVersion)
+		origCluster = &latestCluster
+		*cluster = *mergedCluster
+		err = r.Status().Patch(ctx, cluster,
+			client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}))
+	}
+
+	return err
+}
+
+// mergeClusterStatusChanges applies to the latest status of a cluster the
+// fields changed from the original status. The conditions are merged by
+// type. It returns false when any of the changed fields has been changed
+// in the latest status too
+func mergeClusterStatusChanges(origStatus, changedStatus, latestStatus *apiv1.ClusterStatus) bool {
+	origValue := reflect.ValueOf(origStatus).Elem()
+	changedValue := reflect.ValueOf(changedStatus).Elem()
+	latestValue := reflect.ValueOf(latestStatus).Elem()
+
+	for idx := 0; idx < origValue.NumField(); idx++ {
+		if origValue.Type().Field(idx).Name == "Conditions" {
+			continue
+		}
+
+		origField := origValue.Field(idx).Interface()
+		if reflect.DeepEqual(origField, changedValue.Field(idx).Interface()) {
+			continue
+		}
+		if !reflect.DeepEqual(origField, latestValue.Field(idx).Interface()) {
+			return false
+		}
+		latestValue.Field(idx).Set(changedValue.Field(idx))
+	}
+
+	return mergeConditionChanges(origStatus.Conditions, changedStatus.Conditions, &latestStatus.Conditions)
+}
+
+// mergeConditionChanges applies to the latest conditions the ones changed,
+// added or removed from the original conditions. It returns false when any
+// of them has been changed in the latest conditions too
+func mergeConditionChanges(origConditions, changedConditions []metav1.Condition, latestConditions *[]metav1.Condition) bool {
+	for _, condition := range changedConditions {
+		origCondition := meta.FindStatusCondition(origConditions, condition.Type)
+		if origCondition != nil && reflect.DeepEqual(*origCondition, condition) {
+			continue
+		}
+		if !reflect.DeepEqual(origCondition, meta.FindStatusCondition(*latestConditions, condition.Type)) {
+			return false
+		}
+		meta.SetStatusCondition(latestConditions, condition)
+	}
+
+	for _, condition := range origConditions {
+		if meta.FindStatusCondition(changedConditions, condition.Type) != nil {
+			continue
+		}
+		latestCondition := meta.FindStatusCondition(*latestConditions, condition.Type)
+		if latestCondition != nil && !reflect.DeepEqual(*latestCondition, condition) {
+			return false
+		}
+		meta.RemoveStatusCondition(latestConditions, condition.Type)
+	}
+
+	return true
 }
 
 // removeConditionsWithInvalidReason will remove every condition which has a not valid
@@ -404,29 +566,23 @@ func (r *ClusterReconciler) removeConditionsWithInvalidReason(ctx context.Contex
 	return nil
 }
 
-// updateOnlineUpdateEnabled updates the `OnlineUpdateEnabled` value in the cluster status
-func (r *ClusterReconciler) updateOnlineUpdateEnabled(
-	ctx context.Context, cluster *apiv1.Cluster, onlineUpdateEnabled bool,
-) error {
-	// do nothing if onlineUpdateEnabled have not changed
-	if cluster.Status.OnlineUpdateEnabled == onlineUpdateEnabled {
-		return nil
-	}
-
-	cluster.Status.OnlineUpdateEnabled = onlineUpdateEnabled
-	return r.Status().Update(ctx, cluster)
-}
-
 // SetClusterOwnerAnnotationsAndLabels sets the cluster as owner of the passed object and then
 // sets all the needed annotations and labels
 func SetClusterOwnerAnnotationsAndLabels(obj *metav1.ObjectMeta, cluster *apiv1.Cluster) {
-	utils.InheritAnnotations(obj, cluster.Annotations, cluster.GetFixedInheritedAnnotations(), configuration.Current)
-	utils.InheritLabels(obj, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current)
+	setInheritedAnnotationsAndLabels(obj, cluster)
 	utils.LabelClusterName(obj, cluster.GetName())
 	utils.SetAsOwnedBy(obj, cluster.ObjectMeta, cluster.TypeMeta)
 	utils.SetOperatorVersion(obj, versions.Version)
 }
 
+// setInheritedAnnotationsAndLabels sets the annotations and the labels the
+// passed object inherits from the cluster, through the `inheritedMetadata`
+// section and the operator configuration
+func setInheritedAnnotationsAndLabels(obj *metav1.ObjectMeta, cluster *apiv1.Cluster) {
+	utils.InheritAnnotations(obj, cluster.Annotations, cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritLabels(obj, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current)
+}
+
 // getPoolerIntegrationsNeeded returns a struct with all the pooler integrations needed
 func (r *ClusterReconciler) getPoolerIntegrationsNeeded(ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -615,7 +771,7 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 	var version string
 	var err error
 
-	if cluster.GetEnableSuperuserAccess() {
+	if cluster.UsesSuperuserSecret() {
 		version, err = r.getSecretResourceVersion(ctx, cluster, cluster.GetSuperuserSecretName())
 		if err != nil {
 			return err
@@ -623,11 +779,13 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 		versions.SuperuserSecretVersion = version
 	}
 
-	version, err = r.getSecretResourceVersion(ctx, cluster, cluster.GetApplicationSecretName())
-	if err != nil {
-		return err
+	if cluster.GetApplicationPasswordFile() == "" {
+		version, err = r.getSecretResourceVersion(ctx, cluster, cluster.GetApplicationSecretName())
+		if err != nil {
+			return err
+		}
+		versions.ApplicationSecretVersion = version
 	}
-	versions.ApplicationSecretVersion = version
 
 	certificates := cluster.Status.Certificates
 
@@ -678,6 +836,18 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 		}
 	}
 
+	for _, database := range cluster.GetExposedDatabases() {
+		secretName := cluster.GetDatabaseResourcesName(database.Name)
+		version, err = r.getSecretResourceVersion(ctx, cluster, secretName)
+		if err != nil {
+			return err
+		}
+		if versions.Databases == nil {
+			versions.Databases = make(map[string]string)
+		}
+		versions.Databases[secretName] = version
+	}
+
 	cluster.Status.SecretsResourceVersion = versions
 
 	return nil
@@ -721,12 +891,19 @@ func (r *ClusterReconciler) getObjectResourceVersion(
 	return object.GetResourceVersion(), nil
 }
 
+// setPrimaryInstance sets the target primary, together with the reason
+// why it is being changed, which is empty while bootstrapping the cluster
 func (r *ClusterReconciler) setPrimaryInstance(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	podName string,
+	reason apiv1.PrimaryChangeReason,
 ) error {
+	if reason.IsFailover() && !cluster.Status.TargetPrimaryReason.IsFailover() {
+		cluster.Status.LastFailoverTimestamp = utils.GetCurrentTimestamp()
+	}
 	cluster.Status.TargetPrimary = podName
+	cluster.Status.TargetPrimaryReason = reason
 	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
 	return r.Status().Update(ctx, cluster)
 }
@@ -771,16 +948,24 @@ func (r *ClusterReconciler) RegisterPhase(ctx context.Context,
 		}
 	}
 
+	if existingClusterStatus.Phase != phase {
+		r.addClusterHistoryEntry(ctx, cluster, apiv1.ClusterHistoryEntry{
+			Type:   apiv1.ClusterHistoryPhaseChange,
+			From:   existingClusterStatus.Phase,
+			To:     phase,
+			Reason: reason,
+		})
+	}
+
 	return nil
 }
 
-// updateClusterStatusThatRequiresInstancesState updates all the cluster status fields that require the instances status
-func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
+// setClusterStatusThatRequiresInstancesState sets all the cluster status fields that require the instances status
+func (r *ClusterReconciler) setClusterStatusThatRequiresInstancesState(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	statuses postgres.PostgresqlStatusList,
-) error {
-	existingClusterStatus := cluster.Status
+) {
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
 	// we extract the instances reported state
@@ -788,6 +973,7 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
 			IsPrimary:  item.IsPrimary,
 			TimeLineID: item.TimeLineID,
+			IP:         item.Pod.Status.PodIP,
 		}
 	}
 
@@ -800,14 +986,226 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		}
 	}
 
-	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
-		return r.Status().Update(ctx, cluster)
+	setConfigurationInSyncCondition(cluster, statuses)
+	setPendingRestartParameters(cluster, statuses)
+	if setPrimaryStorageStall(cluster, statuses, time.Now()) {
+		log.FromContext(ctx).Warning("The storage of the primary is stalled",
+			"primary", cluster.Status.CurrentPrimary)
+		r.Recorder.Eventf(cluster, "Warning", "PrimaryStorageStalled",
+			"The storage of the primary %s is stalled, a failover will be delayed by up to %v",
+			cluster.Status.CurrentPrimary, cluster.Spec.StorageStall.GetFailoverDelay())
 	}
-	return nil
+	for _, instanceName := range setDataCorruptedInstances(cluster, statuses) {
+		log.FromContext(ctx).Warning("Instance reporting damaged data", "instance", instanceName)
+		r.Recorder.Eventf(cluster, "Warning", "DataCorruptionDetected",
+			"Instance %s reported checksum failures or errors about damaged data", instanceName)
+	}
+
+	if releases, err := postgres.ParseMinorReleases(configuration.Current.PostgresMinorReleases); err != nil {
+		log.FromContext(ctx).Error(err, "Invalid catalog of the PostgreSQL minor releases, skipping the check")
+	} else {
+		setOutdatedMinorVersionCondition(cluster, releases, time.Now())
+	}
+}
+
+// setCurrentPrimaryLSN sets the position of the WAL written by the
+// primary. As it moves at every write, it is refreshed only together
+// with the other fields changed since the passed status, to avoid
+// updating the status at every reconciliation loop
+func setCurrentPrimaryLSN(
+	cluster *apiv1.Cluster,
+	existingClusterStatus *apiv1.ClusterStatus,
+	statuses postgres.PostgresqlStatusList,
+) {
+	primaryLSN := getCurrentPrimaryLSN(statuses)
+	if primaryLSN == "" {
+		return
+	}
+
+	if !reflect.DeepEqual(*existingClusterStatus, cluster.Status) || cluster.Status.CurrentPrimaryLSN == "" {
+		cluster.Status.CurrentPrimaryLSN = primaryLSN
+	}
+}
+
+// getCurrentPrimaryLSN gets the position of the WAL written by the
+// primary instance, if it has been reported
+func getCurrentPrimaryLSN(statuses postgres.PostgresqlStatusList) string {
+	for _, item := range statuses.Items {
+		if item.IsPrimary && item.Error == nil {
+			return string(item.CurrentLsn)
+		}
+	}
+
+	return ""
+}
+
+// setConfigurationInSyncCondition sets the condition reporting whether the
+// instances have configuration parameters changed outside the cluster
+// specification. The instances whose status is unknown are not considered
+func setConfigurationInSyncCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	var drifts []string
+	reported := false
+	for _, item := range statuses.Items {
+		if item.Error != nil {
+			continue
+		}
+		reported = true
+		if len(item.DriftedParameters) > 0 {
+			drifts = append(drifts,
+				fmt.Sprintf("%s (%s)", item.Pod.Name, strings.Join(item.DriftedParameters, ", ")))
+		}
+	}
+	if !reported {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionConfigurationInSync),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonConfigurationInSync),
+		Message: "The configuration of the instances matches the cluster specification",
+	}
+	if len(drifts) > 0 {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionConfigurationInSync),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ConditionReasonConfigurationDrift),
+			Message: "Parameters changed outside the cluster specification: " +
+				strings.Join(drifts, "; "),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setDegradedCondition reports whether the cluster is working with fewer
+// ready instances than the requested ones. Nothing is reported until the
+// cluster has been bootstrapped
+func setDegradedCondition(cluster *apiv1.Cluster) {
+	if cluster.Status.CurrentPrimary == "" {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionDegraded),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonAllInstancesReady),
+		Message: "Every requested instance is ready",
+	}
+	if cluster.Status.ReadyInstances < cluster.Spec.Instances {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionDegraded),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonInstancesNotReady),
+			Message: fmt.Sprintf("%d of the %d requested instances are ready",
+				cluster.Status.ReadyInstances, cluster.Spec.Instances),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// pruneInstanceGroups forgets the group assignments of the instances which
+// don't exist anymore. The latest generated instance is kept, since its
+// volumes may not have been created yet
+func pruneInstanceGroups(cluster *apiv1.Cluster) {
+	if len(cluster.Status.InstanceGroups) == 0 {
+		return
+	}
+
+	// A new map is built since the previous status shares the current one
+	instanceNames := stringset.From(cluster.Status.InstanceNames)
+	instanceNames.Put(specs.GetInstanceName(cluster.Name, cluster.Status.LatestGeneratedNode))
+	instanceGroups := make(map[string]string, len(cluster.Status.InstanceGroups))
+	for instanceName, groupName := range cluster.Status.InstanceGroups {
+		if instanceNames.Has(instanceName) {
+			instanceGroups[instanceName] = groupName
+		}
+	}
+
+	if len(instanceGroups) == 0 {
+		instanceGroups = nil
+	}
+	cluster.Status.InstanceGroups = instanceGroups
+}
+
+// setPendingRestartParameters records the configuration parameters that
+// at least one instance needs to be restarted to apply. The instances
+// whose status is unknown are not considered
+func setPendingRestartParameters(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	parameters := stringset.New()
+	reported := false
+	for _, item := range statuses.Items {
+		if item.Error != nil {
+			continue
+		}
+		reported = true
+		for _, parameter := range item.PendingRestartParameters {
+			parameters.Put(parameter)
+		}
+	}
+	if !reported {
+		return
+	}
+
+	if parameters.Len() == 0 {
+		cluster.Status.PendingRestartParameters = nil
+		return
+	}
+
+	pendingRestartParameters := parameters.ToList()
+	sort.Strings(pendingRestartParameters)
+	cluster.Status.PendingRestartParameters = pendingRestartParameters
+}
+
+// setOutdatedMinorVersionCondition sets the condition reporting whether the
+// cluster is running an older minor version than the latest one published
+// in the catalog, and how long ago the newer one was released. The condition
+// is removed when the catalog is empty
+func setOutdatedMinorVersionCondition(cluster *apiv1.Cluster, releases []postgres.MinorRelease, now time.Time) {
+	if len(releases) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionOutdatedMinorVersion))
+		return
+	}
+
+	version, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionOutdatedMinorVersion),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonMinorVersionUpToDate),
+		Message: "No newer minor release is available for " + cluster.GetImageName(),
+	}
+
+	latest, found := postgres.GetLatestMinorRelease(releases, version)
+	if found && latest.Version > version {
+		age := int(now.Sub(latest.ReleaseDate).Hours() / 24)
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionOutdatedMinorVersion),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonMinorVersionOutdated),
+			Message: fmt.Sprintf("%s is older than PostgreSQL %s, released on %s (%d days ago)",
+				cluster.GetImageName(), latest.Name, latest.ReleaseDate.Format("2006-01-02"), age),
+		}
+		if cluster.IsMinorVersionPinned() {
+			condition.Reason = string(apiv1.ConditionReasonMinorVersionPinned)
+			condition.Message += fmt.Sprintf(". The cluster is pinned to version %s",
+				cluster.Spec.MinorVersionPinning.Version)
+			if cluster.Spec.MinorVersionPinning.Reason != "" {
+				condition.Message += ": " + cluster.Spec.MinorVersionPinning.Reason
+			}
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
 }
 
 // extractInstancesStatus extracts the status of the underlying PostgreSQL instance from
-// the requested Pod, via the instance manager. In case of failure, errors are passed
+// the requested Pod, via the instance manager. The status streamed by the instance
+// manager is used when available. In case of failure, errors are passed
 // in the result list
 func (r *ClusterReconciler) extractInstancesStatus(
 	ctx context.Context,
@@ -816,7 +1214,10 @@ func (r *ClusterReconciler) extractInstancesStatus(
 	var result postgres.PostgresqlStatusList
 
 	for idx := range activePods {
-		instanceStatus := r.getReplicaStatusFromPodViaHTTP(ctx, activePods[idx])
+		instanceStatus, found := r.statusWatcher.Get(activePods[idx])
+		if !found {
+			instanceStatus = r.getReplicaStatusFromPodViaHTTP(ctx, activePods[idx])
+		}
 
 		// IsReady is not populated by the instance manager, so we detect it from the
 		// Pod status
@@ -918,7 +1319,7 @@ func getPodsTopology(
 	ctx context.Context,
 	pods []corev1.Pod,
 	nodes map[string]corev1.Node,
-	topology apiv1.SyncReplicaElectionConstraints,
+	labelNames []string,
 ) apiv1.Topology {
 	contextLogger := log.FromContext(ctx)
 	data := make(map[apiv1.PodName]apiv1.PodTopologyLabels)
@@ -933,7 +1334,7 @@ func getPodsTopology(
 			contextLogger.Debug("node not found, skipping pod topology matching")
 			return apiv1.Topology{}
 		}
-		for _, labelName := range topology.NodeLabelsAntiAffinity {
+		for _, labelName := range labelNames {
 			data[podName][labelName] = node.Labels[labelName]
 		}
 	}
@@ -18,15 +18,23 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -91,10 +99,18 @@ var _ = Describe("cluster_status unit tests", func() {
 		Expect(cluster.Status.TargetPrimaryTimestamp).To(BeEmpty())
 
 		By("setting the primaryInstance and making sure the passed object is updated", func() {
-			err := clusterReconciler.setPrimaryInstance(ctx, cluster, podName)
+			err := clusterReconciler.setPrimaryInstance(ctx, cluster, podName, v1.PrimaryChangeReasonManual)
 			Expect(err).To(BeNil())
 			Expect(cluster.Status.TargetPrimaryTimestamp).ToNot(BeEmpty())
 			Expect(cluster.Status.TargetPrimary).To(Equal(podName))
+			Expect(cluster.Status.TargetPrimaryReason).To(Equal(v1.PrimaryChangeReasonManual))
+			Expect(cluster.Status.LastFailoverTimestamp).To(BeEmpty())
+		})
+
+		By("recording when a failover is started", func() {
+			err := clusterReconciler.setPrimaryInstance(ctx, cluster, podName, v1.PrimaryChangeReasonLiveness)
+			Expect(err).To(BeNil())
+			Expect(cluster.Status.LastFailoverTimestamp).ToNot(BeEmpty())
 		})
 
 		By("making sure the remote resource is updated", func() {
@@ -162,3 +178,367 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("setConfigurationInSyncCondition", func() {
+	newStatus := func(podName string, parameters ...string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:               corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			DriftedParameters: parameters,
+		}
+	}
+
+	It("reports the configuration as in sync when no parameter drifted", func() {
+		cluster := &v1.Cluster{}
+		setConfigurationInSyncCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newStatus("cluster-example-1"), newStatus("cluster-example-2")},
+		})
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionConfigurationInSync))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonConfigurationInSync)))
+	})
+
+	It("reports the drifted parameters of every instance", func() {
+		cluster := &v1.Cluster{}
+		setConfigurationInSyncCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", "work_mem"),
+				newStatus("cluster-example-2"),
+				newStatus("cluster-example-3", "log_statement", "work_mem"),
+			},
+		})
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionConfigurationInSync))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonConfigurationDrift)))
+		Expect(condition.Message).To(ContainSubstring(
+			"cluster-example-1 (work_mem); cluster-example-3 (log_statement, work_mem)"))
+	})
+
+	It("ignores the instances whose status is unknown", func() {
+		cluster := &v1.Cluster{}
+		unknown := newStatus("cluster-example-1")
+		unknown.Error = fmt.Errorf("connection refused")
+		setConfigurationInSyncCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{unknown},
+		})
+
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("setPendingRestartParameters", func() {
+	newStatus := func(podName string, parameters ...string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                      corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			PendingRestart:           len(parameters) > 0,
+			PendingRestartParameters: parameters,
+		}
+	}
+
+	It("lists the parameters pending a restart in any instance", func() {
+		cluster := &v1.Cluster{}
+		setPendingRestartParameters(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", "shared_buffers"),
+				newStatus("cluster-example-2"),
+				newStatus("cluster-example-3", "max_connections", "shared_buffers"),
+			},
+		})
+		Expect(cluster.Status.PendingRestartParameters).To(Equal([]string{"max_connections", "shared_buffers"}))
+	})
+
+	It("clears the list when every instance has been restarted", func() {
+		cluster := &v1.Cluster{Status: v1.ClusterStatus{PendingRestartParameters: []string{"shared_buffers"}}}
+		setPendingRestartParameters(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newStatus("cluster-example-1"), newStatus("cluster-example-2")},
+		})
+		Expect(cluster.Status.PendingRestartParameters).To(BeEmpty())
+	})
+
+	It("keeps the list when no instance reported its status", func() {
+		cluster := &v1.Cluster{Status: v1.ClusterStatus{PendingRestartParameters: []string{"shared_buffers"}}}
+		unknown := newStatus("cluster-example-1")
+		unknown.Error = fmt.Errorf("connection refused")
+		setPendingRestartParameters(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{unknown},
+		})
+		Expect(cluster.Status.PendingRestartParameters).To(Equal([]string{"shared_buffers"}))
+	})
+})
+
+var _ = Describe("pruneInstanceGroups", func() {
+	It("forgets the instances which don't exist anymore", func() {
+		cluster := &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Status: v1.ClusterStatus{
+				LatestGeneratedNode: 4,
+				InstanceNames:       []string{"cluster-example-1", "cluster-example-3"},
+				InstanceGroups: map[string]string{
+					"cluster-example-2": "reporting",
+					"cluster-example-3": "reporting",
+					"cluster-example-4": "fast",
+				},
+			},
+		}
+		previous := cluster.Status.InstanceGroups
+
+		pruneInstanceGroups(cluster)
+		Expect(cluster.Status.InstanceGroups).To(Equal(map[string]string{
+			"cluster-example-3": "reporting",
+			"cluster-example-4": "fast",
+		}))
+		Expect(previous).To(HaveLen(3))
+	})
+
+	It("clears the assignments when no instance is left", func() {
+		cluster := &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Status: v1.ClusterStatus{
+				LatestGeneratedNode: 2,
+				InstanceGroups:      map[string]string{"cluster-example-1": "reporting"},
+			},
+		}
+		pruneInstanceGroups(cluster)
+		Expect(cluster.Status.InstanceGroups).To(BeNil())
+	})
+})
+
+var _ = Describe("outdated minor version condition", func() {
+	releaseDate := time.Date(2022, 11, 10, 0, 0, 0, 0, time.UTC)
+	now := releaseDate.Add(36 * 24 * time.Hour)
+	releases := []postgres.MinorRelease{
+		{Name: "14.6", Version: 140006, ReleaseDate: releaseDate},
+		{Name: "15.1", Version: 150001, ReleaseDate: releaseDate},
+	}
+
+	newCluster := func(imageName string) *v1.Cluster {
+		return &v1.Cluster{Spec: v1.ClusterSpec{ImageName: imageName}}
+	}
+
+	It("reports the clusters running the latest minor version", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:15.1")
+		setOutdatedMinorVersionCondition(cluster, releases, now)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionOutdatedMinorVersion))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonMinorVersionUpToDate)))
+	})
+
+	It("reports the age of the newer minor release", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:15.0")
+		setOutdatedMinorVersionCondition(cluster, releases, now)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionOutdatedMinorVersion))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonMinorVersionOutdated)))
+		Expect(condition.Message).To(ContainSubstring("PostgreSQL 15.1, released on 2022-11-10 (36 days ago)"))
+	})
+
+	It("reports the pinned clusters as exceptions", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:15.0")
+		cluster.Spec.MinorVersionPinning = &v1.MinorVersionPinning{
+			Version: "15.0",
+			Reason:  "waiting for the application certification",
+		}
+		setOutdatedMinorVersionCondition(cluster, releases, now)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionOutdatedMinorVersion))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonMinorVersionPinned)))
+		Expect(condition.Message).To(HaveSuffix(
+			"The cluster is pinned to version 15.0: waiting for the application certification"))
+	})
+
+	It("removes the condition when the catalog is empty", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:15.0")
+		setOutdatedMinorVersionCondition(cluster, releases, now)
+		Expect(cluster.Status.Conditions).To(HaveLen(1))
+
+		setOutdatedMinorVersionCondition(cluster, nil, now)
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("getCurrentPrimaryLSN", func() {
+	It("gets the WAL position of the primary", func() {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}}, ReceivedLsn: "0/5000000"},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}, IsPrimary: true, CurrentLsn: "0/6000000"},
+			},
+		}
+
+		Expect(getCurrentPrimaryLSN(statuses)).To(Equal("0/6000000"))
+	})
+
+	It("returns an empty position when the primary didn't report its status", func() {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}, Error: fmt.Errorf("timeout")},
+			},
+		}
+
+		Expect(getCurrentPrimaryLSN(statuses)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("setCurrentPrimaryLSN", func() {
+	statuses := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}, IsPrimary: true, CurrentLsn: "0/6000000"},
+		},
+	}
+
+	It("doesn't refresh the WAL position when nothing else changed", func() {
+		cluster := &v1.Cluster{Status: v1.ClusterStatus{CurrentPrimaryLSN: "0/5000000"}}
+		existingStatus := cluster.Status.DeepCopy()
+
+		setCurrentPrimaryLSN(cluster, existingStatus, statuses)
+		Expect(cluster.Status.CurrentPrimaryLSN).To(Equal("0/5000000"))
+	})
+
+	It("refreshes the WAL position together with the other changes", func() {
+		cluster := &v1.Cluster{Status: v1.ClusterStatus{CurrentPrimaryLSN: "0/5000000"}}
+		existingStatus := cluster.Status.DeepCopy()
+		cluster.Status.ReadyInstances = 3
+
+		setCurrentPrimaryLSN(cluster, existingStatus, statuses)
+		Expect(cluster.Status.CurrentPrimaryLSN).To(Equal("0/6000000"))
+	})
+
+	It("sets the WAL position when it is not known yet", func() {
+		cluster := &v1.Cluster{}
+		existingStatus := cluster.Status.DeepCopy()
+
+		setCurrentPrimaryLSN(cluster, existingStatus, statuses)
+		Expect(cluster.Status.CurrentPrimaryLSN).To(Equal("0/6000000"))
+	})
+})
+
+var _ = Describe("setDegradedCondition", func() {
+	It("reports nothing while the cluster is being bootstrapped", func() {
+		cluster := &v1.Cluster{Spec: v1.ClusterSpec{Instances: 3}}
+		setDegradedCondition(cluster)
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+
+	It("reports the cluster as degraded when some instances are not ready", func() {
+		cluster := &v1.Cluster{Spec: v1.ClusterSpec{Instances: 3}}
+		cluster.Status.CurrentPrimary = "cluster-example-1"
+		cluster.Status.ReadyInstances = 2
+
+		setDegradedCondition(cluster)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionDegraded))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(Equal("2 of the 3 requested instances are ready"))
+
+		cluster.Status.ReadyInstances = 3
+		setDegradedCondition(cluster)
+		condition = meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionDegraded))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(BeEquivalentTo(v1.ConditionReasonAllInstancesReady))
+	})
+})
+
+// racingStatusClient is a client where, before each of the first status
+// patches, somebody else writes the cluster using the passed function
+type racingStatusClient struct {
+	client.Client
+	races int
+	race  func(cluster *v1.Cluster)
+}
+
+func (c *racingStatusClient) Status() client.StatusWriter {
+	return &racingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type racingStatusWriter struct {
+	client.StatusWriter
+	client *racingStatusClient
+}
+
+func (w *racingStatusWriter) Patch(
+	ctx context.Context,
+	obj client.Object,
+	patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	if w.client.races > 0 {
+		w.client.races--
+
+		var cluster v1.Cluster
+		Expect(w.client.Client.Get(ctx, client.ObjectKeyFromObject(obj), &cluster)).To(Succeed())
+		w.client.race(&cluster)
+		Expect(w.client.Client.Status().Update(ctx, &cluster)).To(Succeed())
+	}
+
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+var _ = Describe("patchClusterStatus", func() {
+	ctx := context.Background()
+
+	newReconciler := func(races int, race func(cluster *v1.Cluster)) (*ClusterReconciler, *v1.Cluster) {
+		cluster := &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster-example"},
+			Status: v1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+
+		return &ClusterReconciler{
+			Client: &racingStatusClient{Client: fakeClient, races: races, race: race},
+		}, cluster
+	}
+	archivingCondition := metav1.Condition{
+		Type:   string(v1.ConditionContinuousArchiving),
+		Status: metav1.ConditionTrue,
+		Reason: string(v1.ConditionReasonContinuousArchivingSuccess),
+	}
+
+	It("applies the changes again when other fields are written concurrently", func() {
+		r, cluster := newReconciler(statusPatchAttempts-1, func(cluster *v1.Cluster) {
+			cluster.Status.FirstRecoverabilityPoint = "2022-11-08T10:00:00Z"
+			meta.SetStatusCondition(&cluster.Status.Conditions, archivingCondition)
+		})
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.ReadyInstances = 3
+		setDegradedCondition(cluster)
+		Expect(r.patchClusterStatus(ctx, cluster, origCluster)).To(Succeed())
+
+		var remoteCluster v1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &remoteCluster)).To(Succeed())
+		Expect(remoteCluster.Status.ReadyInstances).To(Equal(3))
+		Expect(remoteCluster.Status.FirstRecoverabilityPoint).To(Equal("2022-11-08T10:00:00Z"))
+		Expect(meta.FindStatusCondition(remoteCluster.Status.Conditions, archivingCondition.Type)).ToNot(BeNil())
+		Expect(meta.FindStatusCondition(remoteCluster.Status.Conditions, string(v1.ConditionDegraded))).ToNot(BeNil())
+		Expect(cluster.ResourceVersion).To(Equal(remoteCluster.ResourceVersion))
+	})
+
+	It("reports a conflict when the same fields are written concurrently", func() {
+		r, cluster := newReconciler(1, func(cluster *v1.Cluster) {
+			cluster.Status.CurrentPrimary = "cluster-example-2"
+			cluster.Status.TargetPrimary = "cluster-example-2"
+		})
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.TargetPrimary = "cluster-example-3"
+		err := r.patchClusterStatus(ctx, cluster, origCluster)
+		Expect(apierrs.IsConflict(err)).To(BeTrue())
+
+		var remoteCluster v1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &remoteCluster)).To(Succeed())
+		Expect(remoteCluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+	})
+})
@@ -0,0 +1,191 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/strings/slices"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// migrateStorageClass recreates the instances whose PVCs are not using the
+// requested storage class, when the migration has been enabled. This is
+// done one instance at a time, starting from the replicas, and the primary
+// is moved with a switchover to an already migrated replica
+func (r *ClusterReconciler) migrateStorageClass(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instancesStatus postgres.PostgresqlStatusList,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	instanceNames := getInstancesNeedingStorageClassMigration(cluster, resources.pvcs.Items)
+	if len(instanceNames) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	for _, instanceName := range instanceNames {
+		if instanceName == cluster.Status.CurrentPrimary || cluster.IsInstanceFenced(instanceName) {
+			continue
+		}
+
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseStorageClassMigration,
+			fmt.Sprintf("Recreating instance %s on the new storage class", instanceName)); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", "StorageClassMigration",
+			"Recreating instance %s on the new storage class", instanceName)
+		if err := r.deleteInstanceWithStorage(ctx, cluster, resources, instanceName); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+	}
+
+	if !slices.Contains(instanceNames, cluster.Status.CurrentPrimary) ||
+		cluster.IsInstanceFenced(cluster.Status.CurrentPrimary) {
+		return ctrl.Result{}, nil
+	}
+
+	var primaryPod *corev1.Pod
+	for idx := range instancesStatus.Items {
+		if instancesStatus.Items[idx].Pod.Name == cluster.Status.CurrentPrimary {
+			primaryPod = &instancesStatus.Items[idx].Pod
+		}
+	}
+	if primaryPod == nil {
+		return ctrl.Result{}, nil
+	}
+
+	targetPrimary := getSwitchoverTarget(cluster, &instancesStatus, *primaryPod)
+	if targetPrimary == "" {
+		contextLogger.Info("No replica can be promoted, the primary can't be moved to the new storage class",
+			"primary", primaryPod.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategySupervised {
+		contextLogger.Info("Waiting for the user to request a switchover to migrate the primary storage class")
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser,
+			"User must issue a supervised switchover"); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, ErrNextLoop
+	}
+
+	contextLogger.Info("The primary needs to be moved to the new storage class, we'll trigger a switchover to do that",
+		"currentPrimary", primaryPod.Name,
+		"targetPrimary", targetPrimary)
+	r.Recorder.Eventf(cluster, "Normal", "Switchover",
+		"Initiating switchover to %s to migrate the storage class of %s",
+		targetPrimary, primaryPod.Name)
+	if err := r.setPrimaryInstance(
+		ctx, cluster, targetPrimary, apiv1.PrimaryChangeReasonStorageClassMigration); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, ErrNextLoop
+}
+
+// getInstancesNeedingStorageClassMigration gets the sorted names of the
+// instances having a PVC not using the storage class requested for it,
+// when the migration has been enabled for that kind of volume
+func getInstancesNeedingStorageClassMigration(
+	cluster *apiv1.Cluster,
+	pvcs []corev1.PersistentVolumeClaim,
+) []string {
+	var result []string
+	for _, pvc := range pvcs {
+		instanceName := pvc.Labels[utils.InstanceNameLabelName]
+		if instanceName == "" || slices.Contains(result, instanceName) {
+			continue
+		}
+
+		var storageConfiguration *apiv1.StorageConfiguration
+		switch utils.PVCRole(pvc.Labels[utils.PvcRoleLabelName]) {
+		case utils.PVCRolePgData:
+			storageConfiguration = &cluster.Spec.StorageConfiguration
+		case utils.PVCRolePgWal:
+			storageConfiguration = cluster.Spec.WalStorage
+		}
+		if storageConfiguration == nil || !storageConfiguration.MigrateStorageClass {
+			continue
+		}
+
+		// When no storage class is requested, the default one is used
+		// and there's nothing to compare with
+		expectedStorageClass := specs.GetExpectedStorageClass(*storageConfiguration, *cluster, instanceName)
+		if expectedStorageClass == nil {
+			continue
+		}
+
+		if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != *expectedStorageClass {
+			result = append(result, instanceName)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// deleteInstanceWithStorage deletes the Pod and the PVCs of an instance,
+// letting the operator create a new replica in its place
+func (r *ClusterReconciler) deleteInstanceWithStorage(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instanceName string,
+) error {
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if pod.Name != instanceName {
+			continue
+		}
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting Pod %s: %w", pod.Name, err)
+		}
+	}
+
+	for idx := range resources.pvcs.Items {
+		pvc := &resources.pvcs.Items[idx]
+		if !specs.DoesPVCBelongToInstance(cluster, instanceName, pvc.Name) {
+			continue
+		}
+		if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting PVC %s: %w", pvc.Name, err)
+		}
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "ReplicaReclone",
+		"Deleted instance %s together with its storage, a new replica will be cloned from the primary",
+		instanceName)
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage class migration", func() {
+	oldStorageClass := "standard"
+	newStorageClass := "encrypted"
+
+	makePVC := func(name, instanceName string, role utils.PVCRole, storageClass string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					utils.InstanceNameLabelName: instanceName,
+					utils.PvcRoleLabelName:      string(role),
+				},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+		}
+	}
+
+	pvcs := []corev1.PersistentVolumeClaim{
+		makePVC("cluster-example-1", "cluster-example-1", utils.PVCRolePgData, oldStorageClass),
+		makePVC("cluster-example-1-wal", "cluster-example-1", utils.PVCRolePgWal, oldStorageClass),
+		makePVC("cluster-example-2", "cluster-example-2", utils.PVCRolePgData, newStorageClass),
+		makePVC("cluster-example-2-wal", "cluster-example-2", utils.PVCRolePgWal, oldStorageClass),
+	}
+
+	It("finds the instances whose PVCs are not using the requested storage class", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					StorageClass:        &newStorageClass,
+					MigrateStorageClass: true,
+				},
+				WalStorage: &apiv1.StorageConfiguration{StorageClass: &oldStorageClass},
+			},
+		}
+		Expect(getInstancesNeedingStorageClassMigration(cluster, pvcs)).To(ConsistOf("cluster-example-1"))
+
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{
+			StorageClass:        &newStorageClass,
+			MigrateStorageClass: true,
+		}
+		Expect(getInstancesNeedingStorageClassMigration(cluster, pvcs)).
+			To(Equal([]string{"cluster-example-1", "cluster-example-2"}))
+	})
+
+	It("doesn't migrate anything unless requested", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{StorageClass: &newStorageClass},
+			},
+		}
+		Expect(getInstancesNeedingStorageClassMigration(cluster, pvcs)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// ErrPrimaryStorageStalled is raised when the failover of the current
+// primary is delayed because its storage is stalled
+var ErrPrimaryStorageStalled = fmt.Errorf("the storage of the primary is stalled")
+
+// setPrimaryStorageStall records since when the storage of the current
+// primary is stalled. The storage is considered stalled when its write
+// latency reaches the threshold, and working again only when the latency
+// drops below half of it. The stall is kept while the status of the primary
+// is unknown. It returns true when a new stall has been detected
+func setPrimaryStorageStall(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList, now time.Time) bool {
+	config := cluster.Spec.StorageStall
+	if config == nil {
+		cluster.Status.PrimaryStorageStalledSince = ""
+		return false
+	}
+
+	for _, item := range statuses.Items {
+		if item.Pod.Name != cluster.Status.CurrentPrimary || item.Error != nil {
+			continue
+		}
+
+		latency := time.Duration(item.StorageWriteLatencyMs) * time.Millisecond
+		switch {
+		case latency >= config.GetLatencyThreshold() && cluster.Status.PrimaryStorageStalledSince == "":
+			cluster.Status.PrimaryStorageStalledSince = now.Format(metav1.RFC3339Micro)
+			return true
+		case latency < config.GetLatencyThreshold()/2:
+			cluster.Status.PrimaryStorageStalledSince = ""
+		}
+	}
+
+	return false
+}
+
+// getStorageStallFailoverDelay gets how long the failover of the current
+// primary should still be delayed because its storage is stalled. The
+// failover is not delayed when the PostgreSQL container of the primary is
+// not running anymore, since the primary is down rather than slow
+func getStorageStallFailoverDelay(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+	now time.Time,
+) time.Duration {
+	config := cluster.Spec.StorageStall
+	if config == nil || cluster.Status.PrimaryStorageStalledSince == "" {
+		return 0
+	}
+
+	stalledSince, err := time.Parse(metav1.RFC3339Micro, cluster.Status.PrimaryStorageStalledSince)
+	if err != nil {
+		return 0
+	}
+
+	for _, item := range statuses.Items {
+		if item.Pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+		if !isPostgresContainerRunning(item.Pod) {
+			return 0
+		}
+
+		if delay := config.GetFailoverDelay() - now.Sub(stalledSince); delay > 0 {
+			return delay
+		}
+		return 0
+	}
+
+	return 0
+}
+
+// isPostgresContainerRunning checks whether the PostgreSQL container
+// of the passed Pod is running
+func isPostgresContainerRunning(pod corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == specs.PostgresContainerName {
+			return containerStatus.State.Running != nil
+		}
+	}
+
+	return false
+}
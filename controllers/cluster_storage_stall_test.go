@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage stall of the primary", func() {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	newCluster := func() *v1.Cluster {
+		return &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				StorageStall: &v1.StorageStallConfiguration{LatencyThreshold: 1000, FailoverDelay: 60},
+			},
+			Status: v1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+	}
+	newStatus := func(latencyMs int64) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  specs.PostgresContainerName,
+						State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+					}},
+				},
+			},
+			StorageWriteLatencyMs: latencyMs,
+		}
+	}
+	statusList := func(items ...postgres.PostgresqlStatus) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{Items: items}
+	}
+
+	It("detects the stall with hysteresis", func() {
+		cluster := newCluster()
+		Expect(setPrimaryStorageStall(cluster, statusList(newStatus(300)), now)).To(BeFalse())
+		Expect(cluster.Status.PrimaryStorageStalledSince).To(BeEmpty())
+
+		Expect(setPrimaryStorageStall(cluster, statusList(newStatus(1200)), now)).To(BeTrue())
+		Expect(cluster.Status.PrimaryStorageStalledSince).ToNot(BeEmpty())
+		stalledSince := cluster.Status.PrimaryStorageStalledSince
+
+		Expect(setPrimaryStorageStall(cluster, statusList(newStatus(700)), now.Add(time.Second))).To(BeFalse())
+		Expect(cluster.Status.PrimaryStorageStalledSince).To(Equal(stalledSince))
+
+		unknown := newStatus(0)
+		unknown.Error = fmt.Errorf("timeout")
+		Expect(setPrimaryStorageStall(cluster, statusList(unknown), now.Add(time.Second))).To(BeFalse())
+		Expect(cluster.Status.PrimaryStorageStalledSince).To(Equal(stalledSince))
+
+		Expect(setPrimaryStorageStall(cluster, statusList(newStatus(400)), now.Add(time.Second))).To(BeFalse())
+		Expect(cluster.Status.PrimaryStorageStalledSince).To(BeEmpty())
+	})
+
+	It("delays the failover while the primary container is running", func() {
+		cluster := newCluster()
+		setPrimaryStorageStall(cluster, statusList(newStatus(1500)), now)
+
+		unknown := newStatus(0)
+		unknown.Error = fmt.Errorf("timeout")
+		Expect(getStorageStallFailoverDelay(cluster, statusList(unknown), now.Add(20*time.Second))).
+			To(Equal(40 * time.Second))
+		Expect(getStorageStallFailoverDelay(cluster, statusList(unknown), now.Add(61*time.Second))).
+			To(BeZero())
+
+		unknown.Pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+		}
+		Expect(getStorageStallFailoverDelay(cluster, statusList(unknown), now.Add(20*time.Second))).
+			To(BeZero())
+	})
+
+	It("doesn't delay the failover when the policy is not set", func() {
+		cluster := newCluster()
+		setPrimaryStorageStall(cluster, statusList(newStatus(1500)), now)
+		cluster.Spec.StorageStall = nil
+		Expect(getStorageStallFailoverDelay(cluster, statusList(newStatus(1500)), now)).To(BeZero())
+	})
+})
@@ -149,31 +149,20 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	}
 
 	// if the cluster has more than one instance, we should trigger a switchover before upgrading
-	if cluster.Status.Instances > 1 && len(podList.Items) > 1 {
-		// If this is not a replica cluster, podList.Items[1] is the first replica,
-		// as the pod list is sorted in the same order we use for switchover / failover.
-		// This may not be true for replica clusters, where every instance is a replica
-		// from the PostgreSQL point-of-view.
-		targetPrimary := podList.Items[1].Pod.Name
-
-		// If this is a replica cluster, the target primary we chose may be
-		// the one we're trying to upgrade, as the list isn't sorted. In
-		// this case, we promote the first instance of the list
-		if targetPrimary == primaryPod.Name {
-			targetPrimary = podList.Items[0].Pod.Name
-		}
-
+	if targetPrimary := getSwitchoverTarget(cluster, podList, primaryPod); targetPrimary != "" {
 		contextLogger.Info("The primary needs to be restarted, we'll trigger a switchover to do that",
 			"reason", reason,
 			"currentPrimary", primaryPod.Name,
 			"targetPrimary", targetPrimary,
 			"podList", podList)
 		r.Recorder.Eventf(cluster, "Normal", "Switchover",
-			"Initiating switchover to %s to upgrade %s", targetPrimary, primaryPod.Name)
-		return true, r.setPrimaryInstance(ctx, cluster, targetPrimary)
+			"Initiating switchover to %s to upgrade %s (reason: %s)",
+			targetPrimary, primaryPod.Name, apiv1.PrimaryChangeReasonRollingUpdate)
+		return true, r.setPrimaryInstance(ctx, cluster, targetPrimary, apiv1.PrimaryChangeReasonRollingUpdate)
 	}
 
-	// if there is only one instance in the cluster, we should upgrade it even if it's a primary
+	// if there is only one instance in the cluster, or no replica can be
+	// promoted, we should upgrade it even if it's a primary
 	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade,
 		fmt.Sprintf("The primary instance needs to be restarted: %s, reason: %s",
 			primaryPod.Name, reason),
@@ -184,6 +173,39 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	return true, r.upgradePod(ctx, cluster, &primaryPod)
 }
 
+// getSwitchoverTarget gets the replica to promote before upgrading the
+// primary, or an empty string when there's none
+func getSwitchoverTarget(
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+	primaryPod v1.Pod,
+) string {
+	if cluster.Status.Instances <= 1 || len(podList.Items) <= 1 {
+		return ""
+	}
+
+	// If this is not a replica cluster, podList.Items[1] is the first replica,
+	// as the pod list is sorted in the same order we use for switchover / failover.
+	// This may not be true for replica clusters, where every instance is a replica
+	// from the PostgreSQL point-of-view.
+	targetPrimary := podList.Items[1]
+
+	// If this is a replica cluster, the target primary we chose may be
+	// the one we're trying to upgrade, as the list isn't sorted. In
+	// this case, we promote the first instance of the list
+	if targetPrimary.Pod.Name == primaryPod.Name {
+		targetPrimary = podList.Items[0]
+	}
+
+	// The replicas excluded from failover are sorted after the other
+	// ones, and can't be promoted
+	if targetPrimary.IsExcludedFromFailover {
+		return ""
+	}
+
+	return targetPrimary.Pod.Name
+}
+
 func (r *ClusterReconciler) updateRestartAnnotation(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -257,7 +279,7 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 			oldImage, newImage)
 	}
 
-	if !configuration.Current.EnableInstanceManagerInplaceUpdates {
+	if !cluster.IsInstanceManagerInplaceUpdateEnabled() {
 		oldImage, newImage, err = isPodNeedingUpgradedInitContainerImage(status.Pod)
 		if err != nil {
 			log.Error(err, "while checking if init container image could be upgraded")
@@ -277,14 +299,38 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 			continue
 		}
 
-		// Check if there is a change in the resource requirements
-		if !utils.IsResourceSubset(container.Resources, cluster.Spec.Resources) {
+		// Check if there is a change in the resource requirements,
+		// which depend on the group of the instance
+		resources := cluster.GetInstanceResources(status.Pod.Name)
+		if !utils.IsResourceSubset(container.Resources, resources) {
 			return true, false, fmt.Sprintf("resources changed, old: %+v, new: %+v",
-				cluster.Spec.Resources,
+				resources,
 				container.Resources)
 		}
 	}
 
+	if !specs.AreEphemeralVolumesSizeLimitsUpToDate(*cluster, status.Pod) {
+		return true, false, "the size limits of the ephemeral volumes changed"
+	}
+
+	if !specs.IsHugePagesVolumeUpToDate(*cluster, status.Pod) {
+		return true, false, "the huge pages of the instances changed"
+	}
+
+	if !specs.IsLDAPServerCAVolumeUpToDate(*cluster, status.Pod) {
+		return true, false, "the CA certificate of the LDAP server changed"
+	}
+
+	if !specs.AreExtensionContainersUpToDate(*cluster, status.Pod) {
+		return true, false, "the extension images changed"
+	}
+
+	// Detect changes in the customizations of the instance Pods
+	podTemplateHash := specs.GetPodTemplateHash(*cluster)
+	if status.Pod.Annotations[specs.PodTemplateHashAnnotationName] != podTemplateHash {
+		return true, false, "the instance Pod template changed"
+	}
+
 	// check if pod needs to be restarted because of some config requiring it
 	return isPodNeedingRestart(cluster, status),
 		true, "configuration needs a restart to apply some configuration changes"
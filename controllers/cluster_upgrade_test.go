@@ -17,6 +17,10 @@ limitations under the License.
 package controllers
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -90,4 +94,90 @@ var _ = Describe("Pod upgrade", func() {
 		Expect(inplacePossible).To(BeTrue())
 		Expect(reason).To(BeEquivalentTo("configuration needs a restart to apply some configuration changes"))
 	})
+
+	It("checks when the instance Pod template changed", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+
+		clusterWithTemplate := cluster
+		clusterWithTemplate.Spec.PodTemplate = &apiv1.InstancePodTemplate{
+			PriorityClassName: "high-priority",
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, &clusterWithTemplate)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the instance Pod template changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(clusterWithTemplate, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, &clusterWithTemplate)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("checks when the size limits of the ephemeral volumes changed", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+
+		shmLimit := resource.MustParse("1Gi")
+		clusterWithLimits := cluster
+		clusterWithLimits.Spec.EphemeralVolumesSizeLimit = &apiv1.EphemeralVolumesSizeLimitConfiguration{
+			Shm: &shmLimit,
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, &clusterWithLimits)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the size limits of the ephemeral volumes changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(clusterWithLimits, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, &clusterWithLimits)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("checks when the huge pages of the instances changed", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+
+		clusterWithHugePages := cluster
+		clusterWithHugePages.Spec.Resources = corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				"hugepages-2Mi": resource.MustParse("1Gi"),
+			},
+		}
+		clusterWithHugePages.Spec.Resources.Limits.DeepCopyInto(&pod.Spec.Containers[0].Resources.Limits)
+		status.Pod = *pod
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, &clusterWithHugePages)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the huge pages of the instances changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(clusterWithHugePages, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, &clusterWithHugePages)
+		Expect(needRollout).To(BeFalse())
+	})
+})
+
+var _ = Describe("switchover target before upgrading the primary", func() {
+	newStatus := func(name string, excluded bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                    corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsExcludedFromFailover: excluded,
+		}
+	}
+	cluster := &apiv1.Cluster{Status: apiv1.ClusterStatus{Instances: 2}}
+	primaryPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}
+
+	It("promotes the first replica", func() {
+		podList := &postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", false),
+			newStatus("cluster-example-2", false),
+		}}
+		Expect(getSwitchoverTarget(cluster, podList, primaryPod)).To(Equal("cluster-example-2"))
+	})
+
+	It("doesn't promote a replica excluded from failover", func() {
+		podList := &postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", false),
+			newStatus("cluster-example-2", true),
+		}}
+		Expect(getSwitchoverTarget(cluster, podList, primaryPod)).To(BeEmpty())
+	})
 })
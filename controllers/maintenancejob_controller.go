@@ -0,0 +1,291 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// MaintenanceJobReconciler reconciles a MaintenanceJob object
+type MaintenanceJobReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=maintenancejobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=maintenancejobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is the main reconciler logic
+func (r *MaintenanceJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	contextLogger.Debug(fmt.Sprintf("reconciling object %#q", req.NamespacedName))
+
+	var maintenanceJob apiv1.MaintenanceJob
+	if err := r.Get(ctx, req.NamespacedName, &maintenanceJob); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	origMaintenanceJob := maintenanceJob.DeepCopy()
+	result, err := r.reconcileMaintenanceJob(ctx, &maintenanceJob, time.Now())
+	if !reflect.DeepEqual(origMaintenanceJob.Status, maintenanceJob.Status) {
+		if patchErr := r.Status().Patch(ctx, &maintenanceJob, client.MergeFrom(origMaintenanceJob)); patchErr != nil {
+			if apierrs.IsConflict(patchErr) {
+				// Retry later, the cache is stale
+				return ctrl.Result{RequeueAfter: time.Second}, nil
+			}
+			return ctrl.Result{}, patchErr
+		}
+	}
+
+	return result, err
+}
+
+// reconcileMaintenanceJob updates the history of the runs of the
+// maintenance and, when scheduled, creates the Job of a new run
+func (r *MaintenanceJobReconciler) reconcileMaintenanceJob(
+	ctx context.Context,
+	maintenanceJob *apiv1.MaintenanceJob,
+	now time.Time,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if err := r.updateMaintenanceJobRuns(ctx, maintenanceJob); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	schedule, err := cron.Parse(maintenanceJob.Spec.Schedule)
+	if err != nil {
+		maintenanceJob.Status.Error = fmt.Sprintf("invalid schedule %q: %v", maintenanceJob.Spec.Schedule, err)
+		return ctrl.Result{}, nil
+	}
+	if len(maintenanceJob.Spec.Tables) > 0 && maintenanceJob.Spec.Database == "" {
+		maintenanceJob.Status.Error = "the tables can only be set together with the database"
+		return ctrl.Result{}, nil
+	}
+	maintenanceJob.Status.Error = ""
+
+	if maintenanceJob.Spec.Suspend {
+		contextLogger.Info("Skipping as the maintenance job is suspended")
+		maintenanceJob.Status.NextScheduleTime = nil
+		return ctrl.Result{}, nil
+	}
+
+	if maintenanceJob.Status.LastCheckTime == nil {
+		// This is the first time we check this schedule,
+		// let's wait until the first run will be actually
+		// scheduled
+		maintenanceJob.Status.LastCheckTime = &metav1.Time{Time: now}
+		nextTime := schedule.Next(now)
+		maintenanceJob.Status.NextScheduleTime = &metav1.Time{Time: nextTime}
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	}
+
+	runTime := schedule.Next(maintenanceJob.Status.LastCheckTime.Time)
+	if now.Before(runTime) {
+		maintenanceJob.Status.NextScheduleTime = &metav1.Time{Time: runTime}
+		return ctrl.Result{RequeueAfter: runTime.Sub(now)}, nil
+	}
+
+	nextTime := schedule.Next(now)
+	maintenanceJob.Status.LastCheckTime = &metav1.Time{Time: now}
+	maintenanceJob.Status.NextScheduleTime = &metav1.Time{Time: nextTime}
+
+	if maintenanceJob.IsRunning() {
+		contextLogger.Info("Skipping the scheduled run, as the previous one is still in progress",
+			"jobName", maintenanceJob.Status.Runs[0].JobName)
+		r.Recorder.Eventf(maintenanceJob, "Warning", "MaintenanceSkipped",
+			"Skipped the run scheduled at %v, as %s is still in progress",
+			runTime, maintenanceJob.Status.Runs[0].JobName)
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	}
+
+	if err := r.createMaintenanceJobRun(ctx, maintenanceJob, runTime, now); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+}
+
+// createMaintenanceJobRun creates the Job running the maintenance
+// scheduled at the passed time
+func (r *MaintenanceJobReconciler) createMaintenanceJobRun(
+	ctx context.Context,
+	maintenanceJob *apiv1.MaintenanceJob,
+	runTime time.Time,
+	now time.Time,
+) error {
+	var cluster apiv1.Cluster
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      maintenanceJob.Spec.Cluster.Name,
+		Namespace: maintenanceJob.Namespace,
+	}, &cluster); err != nil {
+		if apierrs.IsNotFound(err) {
+			maintenanceJob.Status.Error = fmt.Sprintf("unknown cluster %s", maintenanceJob.Spec.Cluster.Name)
+			return nil
+		}
+		return err
+	}
+
+	// Let's have deterministic names to avoid creating the job two times
+	job := specs.CreateMaintenanceJob(*maintenanceJob, cluster,
+		fmt.Sprintf("%s-%d", maintenanceJob.Name, runTime.Unix()))
+	if err := ctrl.SetControllerReference(maintenanceJob, job, r.Scheme); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Creating the job running the maintenance", "jobName", job.Name)
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		r.Recorder.Eventf(maintenanceJob, "Warning", "MaintenanceJobCreation",
+			"Error while creating the job %s: %v", job.Name, err)
+		return err
+	}
+
+	maintenanceJob.Status.LastScheduleTime = &metav1.Time{Time: runTime}
+	maintenanceJob.Status.Runs = append([]apiv1.MaintenanceJobRun{{
+		JobName:   job.Name,
+		Phase:     apiv1.MaintenanceJobRunPhaseRunning,
+		StartTime: &metav1.Time{Time: now},
+	}}, maintenanceJob.Status.Runs...)
+	r.Recorder.Eventf(maintenanceJob, "Normal", "MaintenanceStarted",
+		"Started the %s of cluster %s with job %s", maintenanceJob.Spec.Operation, cluster.Name, job.Name)
+
+	return nil
+}
+
+// updateMaintenanceJobRuns records the latest runs of the maintenance
+// in its status, deleting the Jobs exceeding the history limit
+func (r *MaintenanceJobReconciler) updateMaintenanceJobRuns(
+	ctx context.Context,
+	maintenanceJob *apiv1.MaintenanceJob,
+) error {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs,
+		client.InNamespace(maintenanceJob.Namespace),
+		client.MatchingLabels{utils.MaintenanceJobLabelName: maintenanceJob.Name},
+	); err != nil {
+		return err
+	}
+
+	sortMaintenanceJobs(jobs.Items)
+	historyLimit := maintenanceJob.GetHistoryLimit()
+	for idx := historyLimit; idx < len(jobs.Items); idx++ {
+		log.FromContext(ctx).Info("Deleting the job of an old maintenance run", "jobName", jobs.Items[idx].Name)
+		if err := r.Delete(ctx, &jobs.Items[idx],
+			client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	if len(jobs.Items) > historyLimit {
+		jobs.Items = jobs.Items[:historyLimit]
+	}
+
+	runs := make([]apiv1.MaintenanceJobRun, 0, len(jobs.Items))
+	for idx := range jobs.Items {
+		run := getMaintenanceJobRun(jobs.Items[idx])
+		if previous := findMaintenanceJobRun(maintenanceJob.Status.Runs, run.JobName); previous != nil &&
+			run.StartTime == nil {
+			run.StartTime = previous.StartTime
+		}
+		runs = append(runs, run)
+	}
+	maintenanceJob.Status.Runs = runs
+
+	return nil
+}
+
+// sortMaintenanceJobs sorts the Jobs of a maintenance, the most recent first
+func sortMaintenanceJobs(jobs []batchv1.Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreationTimestamp.Equal(&jobs[j].CreationTimestamp) {
+			return jobs[j].CreationTimestamp.Before(&jobs[i].CreationTimestamp)
+		}
+		return jobs[i].Name > jobs[j].Name
+	})
+}
+
+// getMaintenanceJobRun gets the run of a maintenance from its Job
+func getMaintenanceJobRun(job batchv1.Job) apiv1.MaintenanceJobRun {
+	run := apiv1.MaintenanceJobRun{
+		JobName:        job.Name,
+		Phase:          apiv1.MaintenanceJobRunPhaseRunning,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: job.Status.CompletionTime,
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			run.Phase = apiv1.MaintenanceJobRunPhaseSucceeded
+		case batchv1.JobFailed:
+			run.Phase = apiv1.MaintenanceJobRunPhaseFailed
+			if run.CompletionTime == nil {
+				completionTime := condition.LastTransitionTime
+				run.CompletionTime = &completionTime
+			}
+		}
+	}
+
+	return run
+}
+
+// findMaintenanceJobRun finds the run executed by the passed Job
+func findMaintenanceJobRun(runs []apiv1.MaintenanceJobRun, jobName string) *apiv1.MaintenanceJobRun {
+	for idx := range runs {
+		if runs[idx].JobName == jobName {
+			return &runs[idx]
+		}
+	}
+	return nil
+}
+
+// SetupWithManager install this controller in the controller manager
+func (r *MaintenanceJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.MaintenanceJob{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
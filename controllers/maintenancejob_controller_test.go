@@ -0,0 +1,75 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maintenance job runs", func() {
+	now := time.Now()
+	newJob := func(name string, created time.Time, conditions ...batchv1.JobCondition) batchv1.Job {
+		return batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Status: batchv1.JobStatus{
+				StartTime:  &metav1.Time{Time: created},
+				Conditions: conditions,
+			},
+		}
+	}
+
+	It("gets the phase of a run from its job", func() {
+		Expect(getMaintenanceJobRun(newJob("vacuum-1", now)).Phase).
+			To(Equal(apiv1.MaintenanceJobRunPhaseRunning))
+
+		succeeded := newJob("vacuum-1", now, batchv1.JobCondition{
+			Type:   batchv1.JobComplete,
+			Status: corev1.ConditionTrue,
+		})
+		Expect(getMaintenanceJobRun(succeeded).Phase).To(Equal(apiv1.MaintenanceJobRunPhaseSucceeded))
+
+		failed := newJob("vacuum-1", now, batchv1.JobCondition{
+			Type:               batchv1.JobFailed,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(now.Add(time.Minute)),
+		})
+		run := getMaintenanceJobRun(failed)
+		Expect(run.Phase).To(Equal(apiv1.MaintenanceJobRunPhaseFailed))
+		Expect(run.CompletionTime).ToNot(BeNil())
+	})
+
+	It("sorts the jobs with the most recent first", func() {
+		jobs := []batchv1.Job{
+			newJob("vacuum-1", now.Add(-2*time.Hour)),
+			newJob("vacuum-3", now),
+			newJob("vacuum-2", now.Add(-time.Hour)),
+		}
+		sortMaintenanceJobs(jobs)
+		Expect(jobs[0].Name).To(Equal("vacuum-3"))
+		Expect(jobs[1].Name).To(Equal("vacuum-2"))
+		Expect(jobs[2].Name).To(Equal("vacuum-1"))
+	})
+})
@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// primaryChangesTotal counts the completed switchovers and failovers,
+// broken down by their reason code
+var primaryChangesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "cnpg",
+		Subsystem: "operator",
+		Name:      "primary_changes_total",
+		Help:      "Number of completed switchovers and failovers, by type and reason",
+	},
+	[]string{"namespace", "cluster", "type", "reason"},
+)
+
+// reconcileDurationSeconds measures how long the reconciliation
+// of each cluster takes
+var reconcileDurationSeconds = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "cnpg",
+		Subsystem: "operator",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration in seconds of the reconciliation of a cluster",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	},
+	[]string{"namespace", "cluster"},
+)
+
+// reconcileErrorsTotal counts the reconciliations of each cluster
+// ended with an error
+var reconcileErrorsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "cnpg",
+		Subsystem: "operator",
+		Name:      "reconcile_errors_total",
+		Help:      "Number of reconciliations of a cluster ended with an error",
+	},
+	[]string{"namespace", "cluster"},
+)
+
+// pendingRestarts reports the number of instances of each cluster
+// waiting to be restarted
+var pendingRestarts = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "operator",
+		Name:      "pending_restarts",
+		Help:      "Number of instances of a cluster waiting to be restarted",
+	},
+	[]string{"namespace", "cluster"},
+)
+
+// clusterPhase reports the current phase of each cluster, as a
+// series set to 1 labeled with the phase
+var clusterPhase = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "operator",
+		Name:      "cluster_phase",
+		Help:      "1 for the current phase of a cluster",
+	},
+	[]string{"namespace", "cluster", "phase"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		primaryChangesTotal,
+		reconcileDurationSeconds,
+		reconcileErrorsTotal,
+		pendingRestarts,
+		clusterPhase,
+	)
+}
+
+// observeReconcile records the duration and the outcome of
+// a reconciliation of a cluster
+func observeReconcile(name types.NamespacedName, duration time.Duration, err error) {
+	reconcileDurationSeconds.WithLabelValues(name.Namespace, name.Name).Observe(duration.Seconds())
+	if err != nil {
+		reconcileErrorsTotal.WithLabelValues(name.Namespace, name.Name).Inc()
+	}
+}
+
+// setPendingRestartsMetric records the number of instances
+// needing a restart, as reported by their status
+func setPendingRestartsMetric(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	count := 0
+	for _, item := range statuses.Items {
+		if item.Error == nil && isPodNeedingRestart(cluster, item) {
+			count++
+		}
+	}
+	pendingRestarts.WithLabelValues(cluster.Namespace, cluster.Name).Set(float64(count))
+}
+
+// setClusterPhaseMetric records the current phase of the cluster,
+// dropping the series of the previous one
+func setClusterPhaseMetric(cluster *apiv1.Cluster) {
+	clusterPhase.DeletePartialMatch(prometheus.Labels{"namespace": cluster.Namespace, "cluster": cluster.Name})
+	if cluster.Status.Phase != "" {
+		clusterPhase.WithLabelValues(cluster.Namespace, cluster.Name, cluster.Status.Phase).Set(1)
+	}
+}
+
+// deleteClusterMetrics drops every series of a deleted cluster
+func deleteClusterMetrics(name types.NamespacedName) {
+	labels := prometheus.Labels{"namespace": name.Namespace, "cluster": name.Name}
+	primaryChangesTotal.DeletePartialMatch(labels)
+	reconcileDurationSeconds.DeletePartialMatch(labels)
+	reconcileErrorsTotal.DeletePartialMatch(labels)
+	pendingRestarts.DeletePartialMatch(labels)
+	clusterPhase.DeletePartialMatch(labels)
+}
+
+// recordPrimaryChange records a completed switchover or failover,
+// emitting an event and incrementing the corresponding counter.
+// The name of the promoted instance is attached to the counter
+// as an exemplar
+func (r *ClusterReconciler) recordPrimaryChange(cluster *apiv1.Cluster, reason apiv1.PrimaryChangeReason) {
+	kind := reason.GetKind()
+
+	eventReason := "SwitchoverCompleted"
+	if reason.IsFailover() {
+		eventReason = "FailoverCompleted"
+	}
+	r.Recorder.Eventf(cluster, "Normal", eventReason,
+		"The %s to %v has been completed (reason: %s)",
+		kind, cluster.Status.CurrentPrimary, reason)
+
+	counter := primaryChangesTotal.WithLabelValues(cluster.Namespace, cluster.Name, kind, string(reason))
+	if exemplarAdder, ok := counter.(prometheus.ExemplarAdder); ok {
+		exemplarAdder.AddWithExemplar(1, prometheus.Labels{"instance": cluster.Status.CurrentPrimary})
+		return
+	}
+	counter.Inc()
+}
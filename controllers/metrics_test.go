@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster metrics", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{}
+		cluster.Namespace = "metrics"
+		cluster.Name = "cluster-example"
+		DeferCleanup(deleteClusterMetrics, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name})
+	})
+
+	It("counts the reconciliations ended with an error", func() {
+		name := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+		observeReconcile(name, time.Second, nil)
+		observeReconcile(name, time.Second, fmt.Errorf("boom"))
+
+		Expect(testutil.ToFloat64(reconcileErrorsTotal.WithLabelValues(name.Namespace, name.Name))).To(Equal(1.0))
+	})
+
+	It("counts the instances waiting to be restarted", func() {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{PendingRestart: true},
+				{},
+				{PendingRestart: true, Error: fmt.Errorf("unreachable")},
+			},
+		}
+		setPendingRestartsMetric(cluster, statuses)
+
+		Expect(testutil.ToFloat64(pendingRestarts.WithLabelValues(cluster.Namespace, cluster.Name))).To(Equal(1.0))
+	})
+
+	It("keeps only the series of the current phase", func() {
+		cluster.Status.Phase = apiv1.PhaseFirstPrimary
+		setClusterPhaseMetric(cluster)
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		setClusterPhaseMetric(cluster)
+
+		Expect(testutil.CollectAndCount(clusterPhase)).To(Equal(1))
+		Expect(testutil.ToFloat64(
+			clusterPhase.WithLabelValues(cluster.Namespace, cluster.Name, apiv1.PhaseHealthy))).To(Equal(1.0))
+	})
+
+	It("drops the series of a deleted cluster", func() {
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		setClusterPhaseMetric(cluster)
+		setPendingRestartsMetric(cluster, postgres.PostgresqlStatusList{})
+
+		deleteClusterMetrics(types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name})
+		Expect(testutil.CollectAndCount(clusterPhase)).To(BeZero())
+		Expect(testutil.CollectAndCount(pendingRestarts)).To(BeZero())
+	})
+})
@@ -31,6 +31,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -45,6 +46,11 @@ type PoolerReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// ReloadCache caches the Secrets labeled to be reloaded, which are
+	// not kept in the cache of the manager when they don't belong to
+	// a cluster
+	ReloadCache cache.Cache
 }
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=poolers,verbs=get;list;watch;create;update;patch;delete
@@ -120,7 +126,7 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 // SetupWithManager setup this controller inside the controller manager
 func (r *PoolerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Pooler{}).
 		Owns(&v1.Deployment{}).
 		Owns(&corev1.Service{}).
@@ -131,8 +137,17 @@ func (r *PoolerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 			&source.Kind{Type: &corev1.Secret{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretToPooler(ctx)),
 			builder.WithPredicates(secretsPoolerPredicate),
-		).
-		Complete(r)
+		)
+
+	if r.ReloadCache != nil {
+		controllerBuilder = controllerBuilder.Watches(
+			source.NewKindWithCache(&corev1.Secret{}, r.ReloadCache),
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToPooler(ctx)),
+			builder.WithPredicates(secretsPoolerPredicate),
+		)
+	}
+
+	return controllerBuilder.Complete(r)
 }
 
 // isOwnedByPooler checks that an object is owned by a pooler and returns
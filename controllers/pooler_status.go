@@ -18,8 +18,13 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
@@ -64,6 +69,7 @@ func (r *PoolerReconciler) updatePoolerStatus(
 	if resources.Deployment != nil {
 		updatedStatus.Instances = resources.Deployment.Status.Replicas
 	}
+	setPoolerReadyCondition(pooler, resources.Deployment, updatedStatus)
 
 	// then update the status if anything changed
 	if !reflect.DeepEqual(pooler.Status, updatedStatus) {
@@ -73,3 +79,30 @@ func (r *PoolerReconciler) updatePoolerStatus(
 
 	return nil
 }
+
+// setPoolerReadyCondition reports whether every requested instance
+// of the pooler is available
+func setPoolerReadyCondition(
+	pooler *apiv1.Pooler,
+	deployment *appsv1.Deployment,
+	status *apiv1.PoolerStatus,
+) {
+	condition := metav1.Condition{
+		Type:    string(apiv1.PoolerConditionReady),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonPoolerNotReady),
+		Message: "The deployment of the pooler has not been created yet",
+	}
+
+	if deployment != nil {
+		available := deployment.Status.AvailableReplicas
+		condition.Message = fmt.Sprintf("%d of the %d requested instances are available",
+			available, pooler.Spec.Instances)
+		if available >= pooler.Spec.Instances {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = string(apiv1.ConditionReasonPoolerReady)
+		}
+	}
+
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
@@ -19,7 +19,9 @@ package controllers
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -139,3 +141,32 @@ var _ = Describe("pooler_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("setPoolerReadyCondition", func() {
+	pooler := &v1.Pooler{Spec: v1.PoolerSpec{Instances: 2}}
+
+	It("reports the pooler as not ready until its deployment exists", func() {
+		status := &v1.PoolerStatus{}
+		setPoolerReadyCondition(pooler, nil, status)
+
+		condition := meta.FindStatusCondition(status.Conditions, string(v1.PoolerConditionReady))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("reports the pooler as ready when every instance is available", func() {
+		deployment := &appsv1.Deployment{Status: appsv1.DeploymentStatus{AvailableReplicas: 1}}
+		status := &v1.PoolerStatus{}
+
+		setPoolerReadyCondition(pooler, deployment, status)
+		condition := meta.FindStatusCondition(status.Conditions, string(v1.PoolerConditionReady))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(Equal("1 of the 2 requested instances are available"))
+
+		deployment.Status.AvailableReplicas = 2
+		setPoolerReadyCondition(pooler, deployment, status)
+		condition = meta.FindStatusCondition(status.Conditions, string(v1.PoolerConditionReady))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(BeEquivalentTo(v1.ConditionReasonPoolerReady))
+	})
+})
@@ -31,7 +31,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // updateOwnedObjects ensure that we have the required objects
@@ -55,9 +55,7 @@ func (r *PoolerReconciler) updateOwnedObjects(
 	return r.updateService(ctx, pooler, resources)
 }
 
-// updateDeployment update the deployment or create it when needed
-//
-//nolint:dupl
+// updateDeployment applies the deployment of the pooler, creating it when needed
 func (r *PoolerReconciler) updateDeployment(
 	ctx context.Context,
 	pooler *apiv1.Pooler,
@@ -69,57 +67,26 @@ func (r *PoolerReconciler) updateDeployment(
 	if err != nil {
 		return err
 	}
+	if err := ctrl.SetControllerReference(pooler, deployment, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := applyObject(ctx, r.Client, deployment); err != nil {
+		return err
+	}
 
 	switch {
 	case resources.Deployment == nil:
-		// Create a new deployment
-		if err := ctrl.SetControllerReference(pooler, deployment, r.Scheme); err != nil {
-			return err
-		}
-
-		contextLog.Info("Creating deployment")
-		err := r.Create(ctx, deployment)
-		if err != nil && !apierrs.IsAlreadyExists(err) {
-			return err
-		}
-		resources.Deployment = deployment
-		return nil
-
-	case resources.Deployment != nil:
-		currentVersion := resources.Deployment.Annotations[pgbouncer.PgbouncerPoolerSpecHash]
-		updatedVersion, err := hash.ComputeHash(pooler.Spec)
-		if err != nil {
-			return err
-		}
-
-		if currentVersion == updatedVersion {
-			// Everything fine, the two deployments are using the
-			// same specifications
-			return nil
-		}
-
-		updatedDeployment := resources.Deployment.DeepCopy()
-		updatedDeployment.Spec = deployment.Spec
-		if updatedDeployment.Annotations == nil {
-			updatedDeployment.Annotations = make(map[string]string)
-		}
-		updatedDeployment.Annotations[pgbouncer.PgbouncerPoolerSpecHash] = updatedVersion
-
-		contextLog.Info("Updating deployment")
-		err = r.Patch(ctx, updatedDeployment, client.MergeFrom(resources.Deployment))
-		if err != nil {
-			return err
-		}
-
-		resources.Deployment = updatedDeployment
+		contextLog.Info("Created deployment")
+	case resources.Deployment.ResourceVersion != deployment.ResourceVersion:
+		contextLog.Info("Updated deployment")
 	}
+	resources.Deployment = deployment
 
 	return nil
 }
 
-// updateService update or create the pgbouncer service as needed
-//
-//nolint:dupl
+// updateService applies the pgbouncer service, creating it when needed
 func (r *PoolerReconciler) updateService(
 	ctx context.Context,
 	pooler *apiv1.Pooler,
@@ -127,25 +94,27 @@ func (r *PoolerReconciler) updateService(
 ) error {
 	contextLog := log.FromContext(ctx)
 
-	if resources.Service == nil {
-		service := pgbouncer.Service(pooler)
-		if err := ctrl.SetControllerReference(pooler, service, r.Scheme); err != nil {
-			return err
-		}
+	service := pgbouncer.Service(pooler)
+	if err := ctrl.SetControllerReference(pooler, service, r.Scheme); err != nil {
+		return err
+	}
 
-		contextLog.Info("Creating service")
-		err := r.Create(ctx, service)
-		if err != nil && !apierrs.IsAlreadyExists(err) {
-			return err
-		}
-		resources.Service = service
-		return nil
+	if err := applyObject(ctx, r.Client, service); err != nil {
+		return err
 	}
 
+	switch {
+	case resources.Service == nil:
+		contextLog.Info("Created service")
+	case resources.Service.ResourceVersion != service.ResourceVersion:
+		contextLog.Info("Updated service")
+	}
+	resources.Service = service
+
 	return nil
 }
 
-// updateRBAC update or create the pgbouncer RBAC
+// updateRBAC applies the pgbouncer role and role binding, creating them when needed
 func (r *PoolerReconciler) updateRBAC(
 	ctx context.Context,
 	pooler *apiv1.Pooler,
@@ -154,38 +123,34 @@ func (r *PoolerReconciler) updateRBAC(
 	contextLog := log.FromContext(ctx)
 
 	role := pgbouncer.Role(pooler)
-	if resources.Role == nil {
-		if err := ctrl.SetControllerReference(pooler, role, r.Scheme); err != nil {
-			return err
-		}
-
-		contextLog.Info("Creating role")
-		err := r.Create(ctx, role)
-		if err != nil && !apierrs.IsAlreadyExists(err) {
-			return err
-		}
-		resources.Role = role
-	} else if !reflect.DeepEqual(role.Rules, resources.Role.Rules) {
-		contextLog.Info("Updating role")
-		resources.Role.Rules = role.Rules
-		if err := r.Update(ctx, resources.Role); err != nil {
-			return err
-		}
+	if err := ctrl.SetControllerReference(pooler, role, r.Scheme); err != nil {
+		return err
 	}
+	if err := applyObject(ctx, r.Client, role); err != nil {
+		return err
+	}
+	switch {
+	case resources.Role == nil:
+		contextLog.Info("Created role")
+	case resources.Role.ResourceVersion != role.ResourceVersion:
+		contextLog.Info("Updated role")
+	}
+	resources.Role = role
 
-	if resources.RoleBinding == nil {
-		roleBinding := pgbouncer.RoleBinding(pooler)
-		if err := ctrl.SetControllerReference(pooler, &roleBinding, r.Scheme); err != nil {
-			return err
-		}
-
-		contextLog.Info("Creating role binding")
-		err := r.Create(ctx, &roleBinding)
-		if err != nil && !apierrs.IsAlreadyExists(err) {
-			return err
-		}
-		resources.RoleBinding = &roleBinding
+	roleBinding := pgbouncer.RoleBinding(pooler)
+	if err := ctrl.SetControllerReference(pooler, &roleBinding, r.Scheme); err != nil {
+		return err
+	}
+	if err := applyObject(ctx, r.Client, &roleBinding); err != nil {
+		return err
+	}
+	switch {
+	case resources.RoleBinding == nil:
+		contextLog.Info("Created role binding")
+	case resources.RoleBinding.ResourceVersion != roleBinding.ResourceVersion:
+		contextLog.Info("Updated role binding")
 	}
+	resources.RoleBinding = &roleBinding
 
 	return nil
 }
@@ -278,13 +243,13 @@ func (r *PoolerReconciler) ensureServiceAccountPullSecret(
 		Type: operatorSecret.Type,
 	}
 
+	utils.LabelClusterName(&secret.ObjectMeta, pooler.Spec.Cluster.Name)
 	if err = ctrl.SetControllerReference(pooler, &secret, r.Scheme); err != nil {
 		return "", err
 	}
 
-	contextLog.Debug("creating image pull secret for service account")
-	// Another sync loop may have already created the service. Let's check that
-	if err = r.Create(ctx, &secret); err != nil && !apierrs.IsAlreadyExists(err) {
+	contextLog.Debug("ensuring image pull secret for service account")
+	if err = createOrPatchPullSecret(ctx, r.Client, &secret); err != nil {
 		return "", err
 	}
 
@@ -25,9 +25,11 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -76,8 +78,6 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 
 			afterDep := getPoolerDeployment(ctx, pooler)
 			Expect(beforeDep.ResourceVersion).To(Equal(afterDep.ResourceVersion))
-			Expect(beforeDep.Annotations[pgbouncer.PgbouncerPoolerSpecHash]).
-				To(Equal(afterDep.Annotations[pgbouncer.PgbouncerPoolerSpecHash]))
 		})
 
 		By("making sure that the deployments gets updated if the pooler.spec changes", func() {
@@ -93,10 +93,41 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 			afterDep := getPoolerDeployment(ctx, poolerUpdate)
 
 			Expect(beforeDep.ResourceVersion).ToNot(Equal(afterDep.ResourceVersion))
-			Expect(beforeDep.Annotations[pgbouncer.PgbouncerPoolerSpecHash]).
-				ToNot(Equal(afterDep.Annotations[pgbouncer.PgbouncerPoolerSpecHash]))
 			Expect(*afterDep.Spec.Replicas).To(Equal(instancesNumber))
 		})
+
+		By("making sure that the fields set by other controllers are kept", func() {
+			deployment := getPoolerDeployment(ctx, pooler)
+			origDeployment := deployment.DeepCopy()
+			deployment.Annotations = map[string]string{"added-by": "someone-else"}
+			Expect(k8sClient.Patch(ctx, deployment, client.MergeFrom(origDeployment))).To(Succeed())
+
+			err := poolerReconciler.updateDeployment(ctx, pooler, res)
+			Expect(err).To(BeNil())
+
+			afterDep := getPoolerDeployment(ctx, pooler)
+			Expect(afterDep.Annotations).To(HaveKeyWithValue("added-by", "someone-else"))
+			Expect(afterDep.ManagedFields).To(ContainElement(
+				HaveField("Manager", utils.FieldManager)))
+		})
+
+		By("making sure that a drifted deployment converges to the pooler spec", func() {
+			deployment := getPoolerDeployment(ctx, pooler)
+			origDeployment := deployment.DeepCopy()
+			driftedReplicas := pooler.Spec.Instances + 5
+			deployment.Spec.Replicas = &driftedReplicas
+			deployment.Spec.Template.Spec.Containers[0].Image = "drifted:latest"
+			Expect(k8sClient.Patch(ctx, deployment, client.MergeFrom(origDeployment))).To(Succeed())
+
+			err := poolerReconciler.updateDeployment(ctx, pooler, res)
+			Expect(err).To(BeNil())
+
+			afterDep := getPoolerDeployment(ctx, pooler)
+			Expect(*afterDep.Spec.Replicas).To(Equal(pooler.Spec.Instances))
+			Expect(afterDep.Spec.Template.Spec.Containers[0].Image).
+				To(Equal(origDeployment.Spec.Template.Spec.Containers[0].Image))
+			Expect(afterDep.Annotations).To(HaveKeyWithValue("added-by", "someone-else"))
+		})
 	})
 
 	It("should test the ServiceAccount and RBAC update logic", func() {
@@ -182,6 +213,31 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 			Expect(beforeResourceVersion).ToNot(Equal(afterSa.ResourceVersion))
 		})
 
+		By("making sure a rotation of the operator pull secret is propagated", func() {
+			pullSecret := &corev1.Secret{}
+			err := k8sClient.Get(ctx, types.NamespacedName{
+				Name:      configuration.Current.OperatorPullSecretName,
+				Namespace: configuration.Current.OperatorNamespace,
+			}, pullSecret)
+			Expect(err).To(BeNil())
+
+			pullSecret.Data[corev1.TLSCertKey] = []byte("rotated-cert")
+			err = k8sClient.Update(ctx, pullSecret)
+			Expect(err).To(BeNil())
+
+			err = poolerReconciler.updateServiceAccount(ctx, pooler, res)
+			Expect(err).To(BeNil())
+
+			poolerPullSecret := &corev1.Secret{}
+			err = k8sClient.Get(
+				ctx,
+				types.NamespacedName{Name: pooler.Name + "-pull", Namespace: pooler.Namespace},
+				poolerPullSecret,
+			)
+			Expect(err).To(BeNil())
+			Expect(poolerPullSecret.Data[corev1.TLSCertKey]).To(Equal([]byte("rotated-cert")))
+		})
+
 		By("making sure RBAC doesn't exist", func() {
 			role := &rbacv1.Role{}
 			err := k8sClient.Get(ctx, types.NamespacedName{Name: pooler.Name, Namespace: pooler.Namespace}, role)
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,6 +28,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -36,6 +38,10 @@ import (
 // because there is a WAL receiver running in our Pod list
 var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 
+// ErrNoPromotableInstance is raised when a new primary server can't be
+// elected because every candidate is excluded from failover
+var ErrNoPromotableInstance = fmt.Errorf("no instance can be promoted to primary")
+
 // updateTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will returns the name of the new primary selected for promotion
 func (r *ClusterReconciler) updateTargetPrimaryFromPods(
@@ -43,7 +49,12 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPods(
 	cluster *apiv1.Cluster,
 	status postgres.PostgresqlStatusList,
 	resources *managedResources,
-) (string, error) {
+) (targetPrimary string, err error) {
+	ctx, span := tracing.StartSpan(ctx, "updateTargetPrimaryFromPods")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	contextLogger := log.FromContext(ctx)
 
 	if len(status.Items) == 0 {
@@ -99,22 +110,45 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		return "", nil
 	}
 
+	// The replicas excluded from failover are sorted after the other ones,
+	// so there's no candidate left when the first one is excluded
+	if status.Items[0].IsExcludedFromFailover {
+		return "", ErrNoPromotableInstance
+	}
+
 	// The current primary is not correctly working, and we need to elect a new one
 	// but before doing that we need to wait for all the WAL receivers to be
 	// terminated. To make sure they eventually terminate we signal the old primary
 	// (if is still alive) to shut down by setting the apiv1.PendingFailoverMarker as
 	// target primary.
 	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
-		contextLogger.Info("Current primary isn't healthy, initiating a failover")
+		// A primary whose storage is stalled may just be slow, and
+		// is given some time to recover
+		if delay := getStorageStallFailoverDelay(cluster, status, time.Now()); delay > 0 {
+			contextLogger.Info("Current primary isn't healthy, but its storage is stalled: delaying the failover",
+				"primary", cluster.Status.CurrentPrimary,
+				"stalledSince", cluster.Status.PrimaryStorageStalledSince,
+				"remainingDelay", delay)
+			return "", ErrPrimaryStorageStalled
+		}
+
+		reason := getFailoverReason(cluster, status)
+		if isFailoverDryRun(cluster) {
+			r.reportPrimaryChangeDryRun(ctx, cluster, status, status.Items[0].Pod.Name, reason)
+			return "", nil
+		}
+
+		contextLogger.Info("Current primary isn't healthy, initiating a failover", "reason", reason)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before initiating the failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
-			"Current primary isn't healthy, initiating a failover from %v", cluster.Status.CurrentPrimary)
+			"Current primary isn't healthy, initiating a failover from %v (reason: %s)",
+			cluster.Status.CurrentPrimary, reason)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
 			fmt.Sprintf("Initiating a failover from %v", cluster.Status.CurrentPrimary)); err != nil {
 			return "", err
 		}
-		err := r.setPrimaryInstance(ctx, cluster, apiv1.PendingFailoverMarker)
+		err := r.setPrimaryInstance(ctx, cluster, apiv1.PendingFailoverMarker, reason)
 		if err != nil {
 			return "", err
 		}
@@ -126,27 +160,34 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		return "", ErrWalReceiversRunning
 	}
 
+	// The reason of the primary change in progress is kept while
+	// choosing a different target
+	reason := cluster.Status.TargetPrimaryReason
+	if reason == "" {
+		reason = apiv1.PrimaryChangeReasonLiveness
+	}
+
 	// This may be tha last step of a failover if target primary is set to apiv1.PendingFailoverMarker
 	// or change the target primary if the current one is not valid anymore.
 	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
-		contextLogger.Info("Failing over", "newPrimary", status.Items[0].Pod.Name)
+		contextLogger.Info("Failing over", "newPrimary", status.Items[0].Pod.Name, "reason", reason)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailoverTarget",
-			"Failing over from %v to %v",
-			cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)
+			"Failing over from %v to %v (reason: %s)",
+			cluster.Status.CurrentPrimary, status.Items[0].Pod.Name, reason)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
 			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)); err != nil {
 			return "", err
 		}
 	} else {
 		contextLogger.Info("Target primary isn't healthy, switching target",
-			"newPrimary", status.Items[0].Pod.Name)
+			"newPrimary", status.Items[0].Pod.Name, "reason", reason)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before switching target", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
-			"Target primary isn't healthy, switching target from %v to %v",
-			cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
+			"Target primary isn't healthy, switching target from %v to %v (reason: %s)",
+			cluster.Status.TargetPrimary, status.Items[0].Pod.Name, reason)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
 			fmt.Sprintf("Switching over to %v", status.Items[0].Pod.Name)); err != nil {
 			return "", err
@@ -154,7 +195,48 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	}
 
 	// Set the first pod in the sorted list as the new targetPrimary
-	return status.Items[0].Pod.Name, r.setPrimaryInstance(ctx, cluster, status.Items[0].Pod.Name)
+	return status.Items[0].Pod.Name, r.setPrimaryInstance(ctx, cluster, status.Items[0].Pod.Name, reason)
+}
+
+// getFailoverReason gets the reason code of a failover of the current primary,
+// given the status of the instances
+func getFailoverReason(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) apiv1.PrimaryChangeReason {
+	for _, item := range status.Items {
+		if item.Pod.Name == cluster.Status.CurrentPrimary && item.Error != nil {
+			return apiv1.PrimaryChangeReasonIsolation
+		}
+	}
+
+	return apiv1.PrimaryChangeReasonLiveness
+}
+
+// isFailoverDryRun checks if the primary changes decided by the operator
+// for the cluster should only be reported without being executed
+func isFailoverDryRun(cluster *apiv1.Cluster) bool {
+	return utils.IsFailoverDryRunEnabled(&cluster.ObjectMeta, configuration.Current.FailoverDryRun)
+}
+
+// reportPrimaryChangeDryRun logs and records an event for a primary change
+// that has not been executed because of the failover dry-run mode
+func (r *ClusterReconciler) reportPrimaryChangeDryRun(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	newPrimary string,
+	reason apiv1.PrimaryChangeReason,
+) {
+	log.FromContext(ctx).Info("Failover dry-run mode is enabled, skipping the primary change",
+		"kind", reason.GetKind(),
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"newPrimary", newPrimary,
+		"reason", reason)
+	status.LogStatus(ctx)
+	r.Recorder.Eventf(cluster, "Normal", "PrimaryChangeDryRun",
+		"Dry run: a %s from %v to %v would have been triggered (reason: %s)",
+		reason.GetKind(), cluster.Status.CurrentPrimary, newPrimary, reason)
 }
 
 // isNodeUnschedulable checks whether a node is set to unschedulable
@@ -212,18 +294,25 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 			continue
 		}
 
-		if !utils.IsPodReady(candidate.Pod) {
+		if !utils.IsPodReady(candidate.Pod) || candidate.IsExcludedFromFailover {
 			continue
 		}
 
+		if isFailoverDryRun(cluster) {
+			r.reportPrimaryChangeDryRun(ctx, cluster, status, candidate.Pod.Name,
+				apiv1.PrimaryChangeReasonUnschedulableNode)
+			return "", nil
+		}
+
 		// Set the current candidate as targetPrimary
 		contextLogger.Info("Current primary is running on unschedulable node, triggering a switchover",
 			"currentPrimary", primaryPod.Pod.Name, "currentPrimaryNode", primaryPod.Node,
 			"targetPrimary", candidate.Pod.Name, "targetPrimaryNode", candidate.Node)
 		status.LogStatus(ctx)
 		r.Recorder.Eventf(cluster, "Normal", "SwitchingOver",
-			"Current primary is running on unschedulable node %v, switching over from %v to %v",
-			primaryPod.Node, cluster.Status.TargetPrimary, candidate.Pod.Name)
+			"Current primary is running on unschedulable node %v, switching over from %v to %v (reason: %s)",
+			primaryPod.Node, cluster.Status.TargetPrimary, candidate.Pod.Name,
+			apiv1.PrimaryChangeReasonUnschedulableNode)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
 			fmt.Sprintf("Switching over to %v, because primary instance "+
 				"was running on unschedulable node %v",
@@ -231,7 +320,8 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 				primaryPod.Node)); err != nil {
 			return "", err
 		}
-		return candidate.Pod.Name, r.setPrimaryInstance(ctx, cluster, candidate.Pod.Name)
+		return candidate.Pod.Name, r.setPrimaryInstance(
+			ctx, cluster, candidate.Pod.Name, apiv1.PrimaryChangeReasonUnschedulableNode)
 	}
 
 	// if we are here this means no new primary has been chosen
@@ -264,6 +354,18 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 		}
 	}
 
+	if status.Items[0].IsExcludedFromFailover {
+		return "", ErrNoPromotableInstance
+	}
+
+	// The primary changes already in progress are completed even in dry-run mode,
+	// otherwise the cluster would be left without a designated primary
+	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary && isFailoverDryRun(cluster) {
+		r.reportPrimaryChangeDryRun(ctx, cluster, status, status.Items[0].Pod.Name,
+			apiv1.PrimaryChangeReasonLiveness)
+		return "", nil
+	}
+
 	// The designated primary is not correctly working, and we need to elect a new one
 	// but before doing that we need to wait for all the WAL receivers to be
 	// terminated. This is needed to avoid losing the WAL data that is being received
@@ -277,14 +379,15 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 	status.LogStatus(ctx)
 	contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 	r.Recorder.Eventf(cluster, "Normal", "FailingOver",
-		"Current target primary isn't healthy, failing over from %v to %v",
-		cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
+		"Current target primary isn't healthy, failing over from %v to %v (reason: %s)",
+		cluster.Status.TargetPrimary, status.Items[0].Pod.Name, apiv1.PrimaryChangeReasonLiveness)
 	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
 		fmt.Sprintf("Failing over to %v", status.Items[0].Pod.Name)); err != nil {
 		return "", err
 	}
 
-	return status.Items[0].Pod.Name, r.setPrimaryInstance(ctx, cluster, status.Items[0].Pod.Name)
+	return status.Items[0].Pod.Name, r.setPrimaryInstance(
+		ctx, cluster, status.Items[0].Pod.Name, apiv1.PrimaryChangeReasonLiveness)
 }
 
 // GetPodsNotOnPrimaryNode filters out only pods that are not on the same node as the primary one
@@ -309,16 +412,24 @@ func GetPodsNotOnPrimaryNode(
 // and the other instances in their election order
 func (r *ClusterReconciler) getStatusFromInstances(
 	ctx context.Context,
+	cluster *apiv1.Cluster,
 	pods corev1.PodList,
 ) postgres.PostgresqlStatusList {
+	ctx, span := tracing.StartSpan(ctx, "getStatusFromInstances")
+	defer span.End()
+
 	// Only work on Pods which can still become active in the future
 	filteredPods := utils.FilterActivePods(pods.Items)
+	r.statusWatcher.Sync(client.ObjectKeyFromObject(cluster), filteredPods)
 	if len(filteredPods) == 0 {
 		// No instances to control
 		return postgres.PostgresqlStatusList{}
 	}
 
 	status := r.extractInstancesStatus(ctx, filteredPods)
+	for idx := range status.Items {
+		status.Items[idx].IsExcludedFromFailover = cluster.IsInstanceExcludedFromFailover(status.Items[idx].Pod.Name)
+	}
 	sort.Sort(&status)
 	for idx := range status.Items {
 		if status.Items[idx].Error != nil {
@@ -344,7 +455,7 @@ func (r *ClusterReconciler) updateClusterAnnotationsOnPods(
 
 		// if all the required annotations are already set and with the correct value,
 		// we proceed to the next item
-		if utils.IsAnnotationSubset(pod.Annotations, cluster.Annotations, cluster.GetFixedInheritedAnnotations(),
+		if utils.IsAnnotationSubset(pod.Annotations, cluster.Annotations, cluster.GetFixedInheritedPodAnnotations(),
 			configuration.Current) &&
 			utils.IsAnnotationAppArmorPresentInObject(&pod.ObjectMeta, cluster.Annotations) {
 			contextLogger.Debug(
@@ -359,7 +470,7 @@ func (r *ClusterReconciler) updateClusterAnnotationsOnPods(
 		// otherwise, we add the modified/new annotations to the pod
 		patch := client.MergeFrom(pod.DeepCopy())
 		utils.InheritAnnotations(&pod.ObjectMeta, cluster.Annotations,
-			cluster.GetFixedInheritedAnnotations(), configuration.Current)
+			cluster.GetFixedInheritedPodAnnotations(), configuration.Current)
 		if utils.IsAnnotationAppArmorPresent(cluster.Annotations) {
 			utils.AnnotateAppArmor(&pod.ObjectMeta, cluster.Annotations)
 		}
@@ -387,7 +498,7 @@ func (r *ClusterReconciler) updateClusterLabelsOnPods(
 
 		// if all the required labels are already set and with the correct value,
 		// we proceed to the next item
-		if utils.IsLabelSubset(pod.Labels, cluster.Labels, cluster.GetFixedInheritedLabels(),
+		if utils.IsLabelSubset(pod.Labels, cluster.Labels, cluster.GetFixedInheritedPodLabels(),
 			configuration.Current) {
 			contextLogger.Debug(
 				"Skipping cluster label reconciliation, because they are already present on pod",
@@ -400,7 +511,7 @@ func (r *ClusterReconciler) updateClusterLabelsOnPods(
 
 		// otherwise, we add the modified/new labels to the pod
 		patch := client.MergeFrom(pod.DeepCopy())
-		utils.InheritLabels(&pod.ObjectMeta, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current)
+		utils.InheritLabels(&pod.ObjectMeta, cluster.Labels, cluster.GetFixedInheritedPodLabels(), configuration.Current)
 
 		contextLogger.Info("Updating cluster labels on pod", "pod", pod.Name)
 		if err := r.Patch(ctx, pod, patch); err != nil {
@@ -17,11 +17,17 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -150,3 +156,65 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Failover reason detection", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "pod-1",
+		},
+	}
+
+	It("detects an unreachable primary", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}}, IsPrimary: false},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}}, Error: fmt.Errorf("timeout")},
+		}}
+		Expect(getFailoverReason(cluster, statusList)).To(Equal(apiv1.PrimaryChangeReasonIsolation))
+	})
+
+	It("detects an unhealthy primary", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}}, IsPrimary: false},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}}, IsPrimary: false},
+		}}
+		Expect(getFailoverReason(cluster, statusList)).To(Equal(apiv1.PrimaryChangeReasonLiveness))
+	})
+
+	It("detects a missing primary", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}}, IsPrimary: false},
+		}}
+		Expect(getFailoverReason(cluster, statusList)).To(Equal(apiv1.PrimaryChangeReasonLiveness))
+	})
+})
+
+var _ = Describe("Failover dry-run mode", func() {
+	It("reports the failover without changing the target primary", func() {
+		ctx := context.Background()
+		recorder := record.NewFakeRecorder(10)
+		r := &ClusterReconciler{Recorder: recorder}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.FailoverDryRunAnnotationName: "enabled"},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "pod-1",
+				TargetPrimary:  "pod-1",
+			},
+		}
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}}, IsPrimary: false},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}}, Error: fmt.Errorf("timeout")},
+		}}
+
+		newPrimary, err := r.updateTargetPrimaryFromPodsPrimaryCluster(ctx, cluster, statusList, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal("pod-1"))
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring("PrimaryChangeDryRun"),
+			ContainSubstring("failover from pod-1 to pod-2"),
+			ContainSubstring(string(apiv1.PrimaryChangeReasonIsolation)),
+		)))
+	})
+})
@@ -31,10 +31,18 @@ import (
 
 // NewCmd create a new cobra command
 func NewCmd() *cobra.Command {
+	var verify bool
+
 	cmd := cobra.Command{
 		Use: "backup [backup_name]",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			backupURL := url.Local(url.PathPgBackup, url.LocalPort)
+			output := os.Stderr
+			if verify {
+				backupURL = url.Local(url.PathPgBackupVerify, url.LocalPort)
+				output = os.Stdout
+			}
+
 			resp, err := http.Get(backupURL + "?name=" + args[0])
 			if err != nil {
 				log.Error(err, "Error while requesting backup")
@@ -70,7 +78,7 @@ func NewCmd() *cobra.Command {
 				return fmt.Errorf("invalid status code: %v", resp.StatusCode)
 			}
 
-			_, err = os.Stderr.Write(body)
+			_, err = output.Write(body)
 			if err != nil {
 				log.Error(err, "Error while starting a backup")
 				return err
@@ -81,5 +89,8 @@ func NewCmd() *cobra.Command {
 		Args: cobra.ExactArgs(1),
 	}
 
+	cmd.Flags().BoolVar(&verify, "verify", false,
+		"Verify the artifacts of an existing backup in the object store, instead of taking a new one")
+
 	return &cmd
 }
@@ -32,6 +32,7 @@ func NewCmd() *cobra.Command {
 	var pprofHTTPServer bool
 	var leaderLeaseDuration int
 	var leaderRenewDeadline int
+	var leaderRetryPeriod int
 
 	cmd := cobra.Command{
 		Use: "controller [flags]",
@@ -44,6 +45,7 @@ func NewCmd() *cobra.Command {
 					enable:        leaderElectionEnable,
 					leaseDuration: time.Duration(leaderLeaseDuration) * time.Second,
 					renewDeadline: time.Duration(leaderRenewDeadline) * time.Second,
+					retryPeriod:   time.Duration(leaderRetryPeriod) * time.Second,
 				},
 				pprofHTTPServer,
 				port,
@@ -60,6 +62,8 @@ func NewCmd() *cobra.Command {
 		"the leader lease duration expressed in seconds")
 	cmd.Flags().IntVar(&leaderRenewDeadline, "leader-renew-deadline", 10,
 		"the leader renew deadline expressed in seconds")
+	cmd.Flags().IntVar(&leaderRetryPeriod, "leader-retry-period", 2,
+		"the interval, expressed in seconds, between the attempts to acquire or renew the leadership")
 
 	cmd.Flags().StringVar(&configMapName, "config-map-name", "", "The name of the ConfigMap containing "+
 		"the operator configuration")
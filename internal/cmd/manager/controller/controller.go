@@ -30,17 +30,23 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/storageversions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/multicache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -85,6 +91,9 @@ const (
 	// CaSecretName is the name of the secret which is hosting the Operator CA
 	CaSecretName = "cnpg-ca-secret" // #nosec
 
+	// watchedNamespacesCheckPeriod is how often the operator configuration
+	// is read again to detect the changes of the watched namespaces
+	watchedNamespacesCheckPeriod = 30 * time.Second
 )
 
 func init() {
@@ -99,6 +108,7 @@ type leaderElectionConfiguration struct {
 	enable        bool
 	leaseDuration time.Duration
 	renewDeadline time.Duration
+	retryPeriod   time.Duration
 }
 
 // RunController is the main procedure of the operator, and is used as the
@@ -125,6 +135,17 @@ func RunController(
 		startPprofDebugServer(ctx)
 	}
 
+	shutdownTracing, err := tracing.Setup(ctx, "cnpg-operator")
+	if err != nil {
+		setupLog.Error(err, "unable to set up the tracing")
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(ctx); err != nil {
+			setupLog.Error(err, "unable to flush the pending spans")
+		}
+	}()
+
 	managerOptions := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
@@ -132,6 +153,7 @@ func RunController(
 		LeaderElection:     leaderConfig.enable,
 		LeaseDuration:      &leaderConfig.leaseDuration,
 		RenewDeadline:      &leaderConfig.renewDeadline,
+		RetryPeriod:        &leaderConfig.retryPeriod,
 		LeaderElectionID:   LeaderElectionID,
 		CertDir:            defaultWebhookCertDir,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
@@ -147,9 +169,29 @@ func RunController(
 		LeaderElectionReleaseOnCancel: true,
 	}
 
+	// The configuration is loaded before creating the manager, as
+	// the namespaces to be watched can be set in the ConfigMap too
+	restConfig := ctrl.GetConfigOrDie()
+	// The field manager of the changes not server-side applied is
+	// taken from the user agent
+	restConfig.UserAgent = fmt.Sprintf("%s/%s", utils.FieldManager, versions.Version)
+	err = createKubernetesClient(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create Kubernetes clients")
+		return err
+	}
+
+	err = loadConfiguration(ctx, configMapName, secretName)
+	if err != nil {
+		return err
+	}
+
+	setupLog.Info("Operator configuration loaded", "configuration", configuration.Current)
+
+	newCache := cache.New
 	if configuration.Current.WatchNamespace != "" {
 		namespaces := configuration.Current.WatchedNamespaces()
-		managerOptions.NewCache = multicache.DelegatingMultiNamespacedCacheBuilder(
+		newCache = multicache.DelegatingMultiNamespacedCacheBuilder(
 			namespaces,
 			configuration.Current.OperatorNamespace)
 		setupLog.Info("Listening for changes", "watchNamespaces", namespaces)
@@ -157,13 +199,18 @@ func RunController(
 		setupLog.Info("Listening for changes on all namespaces")
 	}
 
+	// Only the Secrets, ConfigMaps, PVCs and Pods belonging to a cluster
+	// are cached, the other ones are read from the API server when needed
+	managerOptions.NewCache = controllers.NewFilteredCacheFunc(newCache)
+	managerOptions.NewClient = controllers.NewClient
+
 	if configuration.Current.WebhookCertDir != "" {
 		// If OLM will generate certificates for us, let's just
 		// use those
 		managerOptions.CertDir = configuration.Current.WebhookCertDir
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions)
+	mgr, err := ctrl.NewManager(restConfig, managerOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		return err
@@ -178,19 +225,6 @@ func RunController(
 		mgr.GetWebhookServer().KeyName = "tls.key"
 	}
 
-	err = createKubernetesClient(mgr.GetConfig())
-	if err != nil {
-		setupLog.Error(err, "unable to create Kubernetes clients")
-		return err
-	}
-
-	err = loadConfiguration(ctx, configMapName, secretName)
-	if err != nil {
-		return err
-	}
-
-	setupLog.Info("Operator configuration loaded", "configuration", configuration.Current)
-
 	discoveryClient, err := utils.GetDiscoveryClient()
 	if err != nil {
 		return err
@@ -216,7 +250,20 @@ func RunController(
 		return err
 	}
 
-	if err = controllers.NewClusterReconciler(mgr, discoveryClient).SetupWithManager(ctx, mgr); err != nil {
+	if err := controllers.CreateReferencedObjectsIndexes(ctx, mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to create the indexes of the referenced objects")
+		return err
+	}
+
+	reloadCache, err := controllers.NewReloadCache(mgr, newCache)
+	if err != nil {
+		setupLog.Error(err, "unable to create the cache of the objects to be reloaded")
+		return err
+	}
+
+	clusterReconciler := controllers.NewClusterReconciler(mgr, discoveryClient)
+	clusterReconciler.ReloadCache = reloadCache
+	if err = clusterReconciler.SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		return err
 	}
@@ -239,15 +286,47 @@ func RunController(
 		return err
 	}
 
-	if err = (&controllers.PoolerReconciler{
+	if err = (&controllers.MaintenanceJobReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-pooler"),
+		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-maintenancejob"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MaintenanceJob")
+		return err
+	}
+
+	if err = (&controllers.PoolerReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Recorder:    mgr.GetEventRecorderFor("cloudnative-pg-pooler"),
+		ReloadCache: reloadCache,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pooler")
 		return err
 	}
 
+	// The conversion webhook is used by the custom resource definitions
+	// defining more than one version, converting them from and to v1
+	mgr.GetWebhookServer().Register("/convert", &conversion.Webhook{})
+
+	// The objects stored with an old version are migrated to the storage
+	// version, so that the old version can be later dropped. A failure
+	// doesn't stop the operator, and the migration is retried at the
+	// next start
+	if err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := storageversions.Migrate(ctx, mgr.GetClient(), apiClientSet, []string{
+			"clusters.postgresql.cnpg.io",
+			"backups.postgresql.cnpg.io",
+			"poolers.postgresql.cnpg.io",
+		}); err != nil {
+			setupLog.Error(err, "unable to migrate the storage version of the custom resources")
+		}
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to add the storage version migration")
+		return err
+	}
+
 	if err = (&apiv1.Cluster{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Cluster", "version", "v1")
 		return err
@@ -268,6 +347,11 @@ func RunController(
 		return err
 	}
 
+	if err = (&apiv1.PgCronJob{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "PgCronJob", "version", "v1")
+		return err
+	}
+
 	// Setup the handler used by the readiness and liveliness probe.
 	//
 	// Unfortunately the readiness of the probe is not sufficient for the operator to be
@@ -283,8 +367,15 @@ func RunController(
 
 	// +kubebuilder:scaffold:builder
 
+	// The informers can't be moved to other namespaces while running,
+	// so the manager is stopped, and the operator restarted, when the
+	// watched namespaces are changed in the configuration
+	managerCtx, stopManager := context.WithCancel(ctrl.SetupSignalHandler())
+	defer stopManager()
+	go stopOnWatchedNamespacesChange(managerCtx, stopManager, configMapName, secretName)
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(managerCtx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		return err
 	}
@@ -294,6 +385,49 @@ func RunController(
 
 // loadConfiguration reads the configuration from the provided configmap and secret
 func loadConfiguration(ctx context.Context, configMapName string, secretName string) error {
+	configData, err := readConfigurationData(ctx, configMapName, secretName)
+	if err != nil {
+		return err
+	}
+
+	// Finally, read the config if it was provided
+	if len(configData) > 0 {
+		configuration.Current.ReadConfigMap(configData)
+	}
+
+	return loadClusterTemplate(ctx)
+}
+
+// loadClusterTemplate reads the template merged into every new cluster
+// from the configured configmap
+func loadClusterTemplate(ctx context.Context) error {
+	templateData, err := readConfigMap(ctx,
+		configuration.Current.OperatorNamespace,
+		configuration.Current.ClusterTemplateConfigmap)
+	if err != nil {
+		setupLog.Error(err, "unable to read the cluster template ConfigMap",
+			"namespace", configuration.Current.OperatorNamespace,
+			"name", configuration.Current.ClusterTemplateConfigmap)
+		return err
+	}
+
+	if err := apiv1.SetClusterTemplate(templateData); err != nil {
+		setupLog.Error(err, "invalid cluster template",
+			"namespace", configuration.Current.OperatorNamespace,
+			"name", configuration.Current.ClusterTemplateConfigmap)
+		return err
+	}
+
+	return nil
+}
+
+// readConfigurationData reads the content of the provided configmap and
+// secret, with the values of the secret taking precedence
+func readConfigurationData(
+	ctx context.Context,
+	configMapName string,
+	secretName string,
+) (map[string]string, error) {
 	configData := make(map[string]string)
 
 	// First read the configmap if provided and store it in configData
@@ -303,7 +437,7 @@ func loadConfiguration(ctx context.Context, configMapName string, secretName str
 			setupLog.Error(err, "unable to read ConfigMap",
 				"namespace", configuration.Current.OperatorNamespace,
 				"name", configMapName)
-			return err
+			return nil, err
 		}
 		for k, v := range configMapData {
 			configData[k] = v
@@ -317,19 +451,48 @@ func loadConfiguration(ctx context.Context, configMapName string, secretName str
 			setupLog.Error(err, "unable to read Secret",
 				"namespace", configuration.Current.OperatorNamespace,
 				"name", secretName)
-			return err
+			return nil, err
 		}
 		for k, v := range secretData {
 			configData[k] = v
 		}
 	}
 
-	// Finally, read the config if it was provided
-	if len(configData) > 0 {
-		configuration.Current.ReadConfigMap(configData)
+	return configData, nil
+}
+
+// stopOnWatchedNamespacesChange periodically reads the operator
+// configuration, and stops the manager when the list of the watched
+// namespaces changes. The operator Pod is then restarted, creating the
+// informers for the new namespaces
+func stopOnWatchedNamespacesChange(
+	ctx context.Context,
+	stopManager context.CancelFunc,
+	configMapName string,
+	secretName string,
+) {
+	if configMapName == "" && secretName == "" {
+		return
 	}
 
-	return nil
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		configData, err := readConfigurationData(ctx, configMapName, secretName)
+		if err != nil {
+			setupLog.Error(err, "while checking the watched namespaces, will retry")
+			return
+		}
+
+		newConfiguration := configuration.NewConfiguration()
+		newConfiguration.ReadConfigMap(configData)
+		if configuration.Current.HasSameWatchedNamespaces(newConfiguration) {
+			return
+		}
+
+		setupLog.Info("The watched namespaces changed, restarting the operator",
+			"currentNamespaces", configuration.Current.WatchedNamespaces(),
+			"newNamespaces", newConfiguration.WatchedNamespaces())
+		stopManager()
+	}, watchedNamespacesCheckPeriod)
 }
 
 // readinessProbeHandler is used to implement the readiness probe handler
@@ -376,6 +539,7 @@ func ensurePKI(ctx context.Context, mgrCertDir string) error {
 			"backups.postgresql.cnpg.io",
 			"clusters.postgresql.cnpg.io",
 			"scheduledbackups.postgresql.cnpg.io",
+			"poolers.postgresql.cnpg.io",
 		},
 		OperatorDeploymentLabelSelector: "app.kubernetes.io/name=cloudnative-pg",
 	}
@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package extension implements the "extension" command, installing
+// the files of an extension image inside the instance Pods
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:           "extension [cmd]",
+		Short:         "Extension images management",
+		SilenceErrors: true,
+	}
+
+	cmd.AddCommand(newInstallCmd())
+
+	return &cmd
+}
+
+// newInstallCmd creates the command copying the files of the extension
+// image, running in an init container, to the volume shared with PostgreSQL
+func newInstallCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "install [destination] [directory...]",
+		Short: "Copy the directories of the extension image to the destination",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return install("/", args[0], args[1:])
+		},
+	}
+
+	return &cmd
+}
+
+// install copies the passed directories of the root filesystem
+// to the destination, replacing the previous copies
+func install(root string, destination string, directories []string) error {
+	for _, directory := range directories {
+		source := filepath.Join(root, directory)
+		target := filepath.Join(destination, directory)
+
+		log.Info("Copying the extension files", "source", source, "destination", target)
+		if err := os.RemoveAll(target); err != nil {
+			return fmt.Errorf("while removing the previous copy of %s: %w", source, err)
+		}
+		if err := fileutils.CopyDirectory(source, target); err != nil {
+			return fmt.Errorf("while copying %s: %w", source, err)
+		}
+	}
+
+	log.Info("Extension installed", "destination", destination)
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extension install", func() {
+	It("replaces the previous copy of the directories", func() {
+		root := GinkgoT().TempDir()
+		destination := GinkgoT().TempDir()
+
+		_, err := fileutils.WriteStringToFile(filepath.Join(root, "share", "extension", "vector.control"), "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fileutils.WriteStringToFile(filepath.Join(root, "lib", "vector.so"), "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fileutils.WriteStringToFile(filepath.Join(destination, "lib", "stale.so"), "")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(install(root, destination, []string{"share", "lib"})).To(Succeed())
+		Expect(filepath.Join(destination, "share", "extension", "vector.control")).To(BeARegularFile())
+		Expect(filepath.Join(destination, "lib", "vector.so")).To(BeARegularFile())
+		_, err = os.Stat(filepath.Join(destination, "lib", "stale.so"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("fails when a directory is missing from the image", func() {
+		Expect(install(GinkgoT().TempDir(), GinkgoT().TempDir(), []string{"share"})).ToNot(Succeed())
+	})
+})
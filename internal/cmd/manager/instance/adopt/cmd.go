@@ -0,0 +1,129 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adopt implement the existingVolume bootstrap method
+package adopt
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// AdoptInfo is the structure containing all the information needed
+// to adopt an existing data directory
+type AdoptInfo struct {
+	info             *postgres.InitInfo
+	client           ctrl.Client
+	sourcePgData     string
+	systemIdentifier string
+}
+
+// NewCmd creates the "adopt" subcommand
+func NewCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+	var pgData string
+	var pgWal string
+	var sourcePgData string
+	var systemIdentifier string
+
+	cmd := &cobra.Command{
+		Use: "adopt",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				return err
+			}
+
+			env := AdoptInfo{
+				info: &postgres.InitInfo{
+					ClusterName: clusterName,
+					Namespace:   namespace,
+					PgData:      pgData,
+					PgWal:       pgWal,
+				},
+				client:           client,
+				sourcePgData:     sourcePgData,
+				systemIdentifier: systemIdentifier,
+			}
+
+			ctx := context.Background()
+
+			if err = env.bootstrapUsingExistingVolume(ctx); err != nil {
+				log.Error(err, "Unable to boostrap cluster")
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"current cluster in k8s, used to coordinate switchover and failover")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and of the Pod in k8s")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be created")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be created")
+	cmd.Flags().StringVar(&sourcePgData, "source-pg-data", "", "The existing data "+
+		"directory to be adopted, stored inside the PGDATA volume")
+	cmd.Flags().StringVar(&systemIdentifier, "system-identifier", "", "The system identifier "+
+		"the data directory to be adopted is expected to have")
+
+	return cmd
+}
+
+// bootstrapUsingExistingVolume creates a new data dir moving in place
+// the one contained in the adopted PGDATA volume
+func (env *AdoptInfo) bootstrapUsingExistingVolume(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	err := env.client.Get(ctx, ctrl.ObjectKey{Namespace: env.info.Namespace, Name: env.info.ClusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	if cluster.ShouldExistingVolumeCreateApplicationDatabase() {
+		env.info.ApplicationUser = cluster.GetApplicationDatabaseOwner()
+		env.info.ApplicationDatabase = cluster.GetApplicationDatabaseName()
+	}
+
+	if err = env.info.AdoptExistingPgData(ctx, &cluster, env.sourcePgData, env.systemIdentifier); err != nil {
+		return err
+	}
+
+	return env.configureInstanceAsNewPrimary(&cluster)
+}
+
+// configureInstanceAsNewPrimary sets up this instance as a new primary server, using
+// the configuration created by the user and setting up the global objects as needed
+func (env *AdoptInfo) configureInstanceAsNewPrimary(cluster *apiv1.Cluster) error {
+	if err := env.info.WriteInitialPostgresqlConf(cluster); err != nil {
+		return err
+	}
+
+	if err := env.info.WriteRestoreHbaConf(); err != nil {
+		return err
+	}
+
+	// The adopted data directory has been cleanly shut down,
+	// so there are no WAL files to be recovered
+	return env.info.ConfigureInstanceAfterRestore(cluster, nil)
+}
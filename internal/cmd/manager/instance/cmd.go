@@ -22,12 +22,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/adopt"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/initdb"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/join"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/pgbasebackup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/servicemesh"
 )
 
 // NewCmd creates the "instance" command
@@ -40,12 +42,24 @@ func NewCmd() *cobra.Command {
 		},
 	}
 
-	cmd.AddCommand(initdb.NewCmd())
-	cmd.AddCommand(join.NewCmd())
+	cmd.AddCommand(withServiceMeshShutdown(initdb.NewCmd()))
+	cmd.AddCommand(withServiceMeshShutdown(join.NewCmd()))
 	cmd.AddCommand(run.NewCmd())
 	cmd.AddCommand(status.NewCmd())
-	cmd.AddCommand(pgbasebackup.NewCmd())
-	cmd.AddCommand(restore.NewCmd())
+	cmd.AddCommand(withServiceMeshShutdown(pgbasebackup.NewCmd()))
+	cmd.AddCommand(withServiceMeshShutdown(restore.NewCmd()))
+	cmd.AddCommand(withServiceMeshShutdown(adopt.NewCmd()))
 
 	return cmd
 }
+
+// withServiceMeshShutdown wraps a command executed inside a Job, stopping
+// the proxy sidecar of the service mesh, if any, when it terminates
+func withServiceMeshShutdown(cmd *cobra.Command) *cobra.Command {
+	runE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		defer servicemesh.QuitSidecar(cmd.Context())
+		return runE(cmd, args)
+	}
+	return cmd
+}
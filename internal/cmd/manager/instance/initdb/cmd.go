@@ -42,6 +42,8 @@ func NewCmd() *cobra.Command {
 	var postInitSQLStr string
 	var postInitApplicationSQLStr string
 	var postInitTemplateSQLStr string
+	var postInitSQLRefsFolder string
+	var postInitTemplateSQLRefsFolder string
 	var postInitApplicationSQLRefsFolder string
 
 	cmd := &cobra.Command{
@@ -86,8 +88,10 @@ func NewCmd() *cobra.Command {
 				PostInitSQL:            postInitSQL,
 				PostInitApplicationSQL: postInitApplicationSQL,
 				PostInitTemplateSQL:    postInitTemplateSQL,
-				// if the value to postInitApplicationSQLRefsFolder is empty,
-				// bootstrap will do nothing for post init application SQL refs.
+				// if the value of any of the SQL refs folders is empty,
+				// bootstrap will do nothing for the corresponding SQL refs.
+				PostInitSQLRefsFolder:            postInitSQLRefsFolder,
+				PostInitTemplateSQLRefsFolder:    postInitTemplateSQLRefsFolder,
 				PostInitApplicationSQLRefsFolder: postInitApplicationSQLRefsFolder,
 			}
 
@@ -116,6 +120,12 @@ func NewCmd() *cobra.Command {
 		"executed inside application database right after the database is created")
 	cmd.Flags().StringVar(&postInitTemplateSQLStr, "post-init-template-sql", "", "The list of SQL queries to be "+
 		"executed inside template1 database to configure the new instance")
+	cmd.Flags().StringVar(&postInitSQLRefsFolder, "post-init-sql-refs-folder",
+		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
+			"to configure the new instance")
+	cmd.Flags().StringVar(&postInitTemplateSQLRefsFolder, "post-init-template-sql-refs-folder",
+		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
+			"inside template1 database to configure the new instance")
 	cmd.Flags().StringVar(&postInitApplicationSQLRefsFolder, "post-init-application-sql-refs-folder",
 		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
 			"against the application database immediately after its creationd")
@@ -40,6 +40,7 @@ func NewCmd() *cobra.Command {
 	var podName string
 	var clusterName string
 	var namespace string
+	var volumeClone bool
 
 	cmd := &cobra.Command{
 		Use: "join [options]",
@@ -61,7 +62,7 @@ func NewCmd() *cobra.Command {
 				PodName:    podName,
 			}
 
-			return joinSubCommand(ctx, instance, info)
+			return joinSubCommand(ctx, instance, info, volumeClone)
 		},
 	}
 
@@ -74,14 +75,23 @@ func NewCmd() *cobra.Command {
 		"the cluster and of the Pod in k8s")
 	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of "+
 		"the current cluster in k8s, used to download TLS certificates")
+	cmd.Flags().BoolVar(&volumeClone, "volume-clone", false, "The PGDATA has been cloned "+
+		"from the volume of another standby, so it only needs to be configured")
 
 	return cmd
 }
 
-func joinSubCommand(ctx context.Context, instance *postgres.Instance, info postgres.InitInfo) error {
-	err := info.VerifyPGData()
-	if err != nil {
-		return err
+func joinSubCommand(
+	ctx context.Context,
+	instance *postgres.Instance,
+	info postgres.InitInfo,
+	volumeClone bool,
+) error {
+	// A cloned volume already contains the PGDATA
+	if !volumeClone {
+		if err := info.VerifyPGData(); err != nil {
+			return err
+		}
 	}
 
 	client, err := management.NewControllerRuntimeClient()
@@ -113,7 +123,11 @@ func joinSubCommand(ctx context.Context, instance *postgres.Instance, info postg
 
 	reconciler.RefreshSecrets(ctx, &cluster)
 
-	err = info.Join(&cluster)
+	if volumeClone {
+		err = info.JoinFromVolumeClone(&cluster)
+	} else {
+		err = info.Join(&cluster)
+	}
 	if err != nil {
 		log.Error(err, "Error joining node")
 		return err
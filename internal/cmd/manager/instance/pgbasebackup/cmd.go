@@ -115,7 +115,7 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 			return err
 		}
 	}
-	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal)
+	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal, nil)
 	if err != nil {
 		return err
 	}
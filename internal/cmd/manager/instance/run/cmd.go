@@ -36,6 +36,8 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/adminsessions"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/cronjobs"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -43,6 +45,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
@@ -72,6 +75,16 @@ func NewCmd() *cobra.Command {
 			instance.PodName = podName
 			instance.ClusterName = clusterName
 
+			shutdownTracing, err := tracing.Setup(ctx, "cnpg-instance-manager")
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := shutdownTracing(context.Background()); err != nil {
+					log.Error(err, "unable to flush the pending spans")
+				}
+			}()
+
 			return retry.OnError(retry.DefaultRetry, isRunSubCommandRetryable, func() error {
 				return runSubCommand(ctx, instance)
 			})
@@ -174,11 +187,26 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	if err = mgr.Add(metricserver.NewWaitEventsSampler(metricsServer.GetExporter())); err != nil {
+		setupLog.Error(err, "unable to create wait events sampler")
+		return err
+	}
+
 	if err = mgr.Add(lifecycle.NewPostgresOrphansReaper(instance)); err != nil {
 		setupLog.Error(err, "unable to create zombie reaper")
 		return err
 	}
 
+	if err = mgr.Add(adminsessions.NewTerminator(instance)); err != nil {
+		setupLog.Error(err, "unable to create admin sessions terminator")
+		return err
+	}
+
+	if err = mgr.Add(cronjobs.NewReconciler(instance, mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create pg_cron jobs reconciler")
+		return err
+	}
+
 	slotReplicator := runner.NewReplicator(instance)
 	if err = mgr.Add(slotReplicator); err != nil {
 		setupLog.Error(err, "unable to create slot replicator")
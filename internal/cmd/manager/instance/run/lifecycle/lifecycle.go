@@ -125,10 +125,12 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 				// otherwise we'll receive a SIGKILL by the Kubelet, possibly
 				// resulting in a data corruption.
 				//
-				// This is why we are trying a smart shutdown for half-time
-				// of our stop delay, and then we proceed.
-				log.Info("Received termination signal", "signal", sig)
-				if err := tryShuttingDownSmartFast(i.instance.MaxStopDelay/2, i.instance); err != nil {
+				// This is why we are trying a smart shutdown for the time
+				// reserved to it, which is less than our stop delay, and
+				// then we proceed.
+				log.Info("Received termination signal", "signal", sig,
+					"smartShutdownTimeout", i.getSmartShutdownTimeout())
+				if err := tryShuttingDownSmartFast(i.getSmartShutdownTimeout(), i.instance); err != nil {
 					log.Error(err, "error while shutting down instance, proceeding")
 				}
 				return nil
@@ -156,6 +158,16 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 	}
 }
 
+// getSmartShutdownTimeout gets the time reserved for the smart shutdown
+// of the instance, falling back to half of the stop delay when the
+// configuration of the cluster has not been applied yet
+func (i *PostgresLifecycle) getSmartShutdownTimeout() int32 {
+	if i.instance.SmartShutdownTimeout > 0 {
+		return i.instance.SmartShutdownTimeout
+	}
+	return i.instance.MaxStopDelay / 2
+}
+
 // handleInstanceCommandRequests execute a command requested by the reconciliation
 // loop.
 func (i *PostgresLifecycle) handleInstanceCommandRequests(
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -41,6 +42,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -49,6 +51,10 @@ const (
 	// SpoolDirectory is the directory where we spool the WAL files that
 	// were pre-archived in parallel
 	SpoolDirectory = postgres.ScratchDataDirectory + "/wal-archive-spool"
+
+	// ChecksumDirectory is the directory where we keep the checksums
+	// of the WAL files that have been archived
+	ChecksumDirectory = postgres.ScratchDataDirectory + "/wal-archive-checksums"
 )
 
 // NewCmd creates the new cobra command
@@ -66,6 +72,17 @@ func NewCmd() *cobra.Command {
 			contextLog := log.WithName("wal-archive")
 			ctx := log.IntoContext(cobraCmd.Context(), contextLog)
 
+			shutdownTracing, err := tracing.Setup(ctx, "cnpg-wal-archive")
+			if err != nil {
+				contextLog.Error(err, "unable to set up the tracing")
+				return err
+			}
+			defer func() {
+				if err := shutdownTracing(ctx); err != nil {
+					contextLog.Error(err, "unable to flush the pending spans")
+				}
+			}()
+
 			if podName == "" {
 				err := fmt.Errorf("no pod-name value passed and failed to extract it from POD_NAME env variable")
 				contextLog.Error(err, logErrorMessage)
@@ -78,7 +95,9 @@ func NewCmd() *cobra.Command {
 				return err
 			}
 
+			ctx, span := tracing.StartSpan(ctx, "wal-archive", attribute.String("wal.name", args[0]))
 			err = run(ctx, podName, pgData, args, typedClient)
+			tracing.EndSpan(span, err)
 			if err != nil {
 				contextLog.Error(err, logErrorMessage)
 				return err
@@ -143,7 +162,7 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 
 	// Create the archiver
 	var walArchiver *archiver.WALArchiver
-	if walArchiver, err = archiver.New(ctx, cluster, env, SpoolDirectory, pgData); err != nil {
+	if walArchiver, err = archiver.New(ctx, cluster, env, SpoolDirectory, ChecksumDirectory, pgData); err != nil {
 		return fmt.Errorf("while creating the archiver: %w", err)
 	}
 
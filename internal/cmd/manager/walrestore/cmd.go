@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
@@ -33,6 +34,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -66,7 +68,21 @@ func NewCmd() *cobra.Command {
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			contextLog := log.WithName("wal-restore")
 			ctx := log.IntoContext(cobraCmd.Context(), contextLog)
-			err := run(ctx, podName, args)
+
+			shutdownTracing, err := tracing.Setup(ctx, "cnpg-wal-restore")
+			if err != nil {
+				contextLog.Error(err, "unable to set up the tracing")
+				return err
+			}
+			defer func() {
+				if err := shutdownTracing(ctx); err != nil {
+					contextLog.Error(err, "unable to flush the pending spans")
+				}
+			}()
+
+			ctx, span := tracing.StartSpan(ctx, "wal-restore", attribute.String("wal.name", args[0]))
+			err = run(ctx, podName, args)
+			tracing.EndSpan(span, err)
 			if err == nil {
 				return nil
 			}
@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup implements the commands operating on the backups of a cluster
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// verifyTimeout is the time allowed to the instance manager to download
+// and check the artifacts of the backup
+const verifyTimeout = 10 * time.Minute
+
+// Verify asks the primary instance of the cluster to verify the artifacts
+// of a completed backup, printing the problems that have been found
+func Verify(ctx context.Context, backupName string) error {
+	var backup apiv1.Backup
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: backupName},
+		&backup,
+	); err != nil {
+		return fmt.Errorf("could not get backup %s: %w", backupName, err)
+	}
+
+	if backup.Status.Phase != apiv1.BackupPhaseCompleted {
+		return fmt.Errorf("backup %s is not completed", backupName)
+	}
+
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: backup.Spec.Cluster.Name},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("could not get cluster %s: %w", backup.Spec.Cluster.Name, err)
+	}
+
+	// The verification is executed by the primary, which is
+	// the instance having access to the object store
+	var primary corev1.Pod
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&primary,
+	); err != nil {
+		return fmt.Errorf("could not get the primary instance: %w", err)
+	}
+
+	timeout := verifyTimeout
+	stdout, stderr, err := utils.ExecCommand(
+		ctx,
+		kubernetes.NewForConfigOrDie(plugin.Config),
+		plugin.Config,
+		primary,
+		specs.PostgresContainerName,
+		&timeout,
+		"/controller/manager", "backup", "--verify", backupName)
+	if err != nil {
+		return fmt.Errorf("while verifying backup %s: %w (%s)", backupName, err, stderr)
+	}
+
+	var verification apiv1.BackupVerification
+	if err := json.Unmarshal([]byte(stdout), &verification); err != nil {
+		return fmt.Errorf("while decoding the verification result: %w", err)
+	}
+
+	if verification.IsSuccessful() {
+		fmt.Printf("Backup %s verified successfully\n", backupName)
+		return nil
+	}
+
+	fmt.Printf("Backup %s has the following problems:\n", backupName)
+	for _, problem := range verification.Errors {
+		fmt.Printf("  - %s\n", problem)
+	}
+
+	return fmt.Errorf("backup %s verification failed", backupName)
+}
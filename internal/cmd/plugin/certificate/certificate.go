@@ -64,7 +64,8 @@ func Generate(ctx context.Context, params Params, dryRun bool, format plugin.Out
 		return err
 	}
 
-	userPair, err := caPair.CreateAndSignPair(params.User, certs.CertTypeClient, nil)
+	userPair, err := caPair.CreateAndSignPairWithDuration(
+		params.User, certs.CertTypeClient, nil, cluster.GetCertificatesValidity().Duration)
 	if err != nil {
 		return err
 	}
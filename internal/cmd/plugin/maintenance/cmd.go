@@ -26,7 +26,7 @@ import (
 func NewCmd() *cobra.Command {
 	var allNamespaces,
 		reusePVC,
-		confirmationRequired bool
+		skipConfirmation bool
 
 	maintenanceCmd := &cobra.Command{
 		Use:   "maintenance [set/unset]",
@@ -47,7 +47,7 @@ func NewCmd() *cobra.Command {
 				}
 				clusterName = args[0]
 			}
-			return Maintenance(cmd.Context(), allNamespaces, reusePVC, confirmationRequired, clusterName, true)
+			return Maintenance(cmd.Context(), allNamespaces, reusePVC, !skipConfirmation, clusterName, true)
 		},
 	})
 
@@ -65,7 +65,7 @@ func NewCmd() *cobra.Command {
 				}
 				clusterName = args[0]
 			}
-			return Maintenance(cmd.Context(), allNamespaces, reusePVC, confirmationRequired, clusterName, false)
+			return Maintenance(cmd.Context(), allNamespaces, reusePVC, !skipConfirmation, clusterName, false)
 		},
 	})
 
@@ -73,8 +73,8 @@ func NewCmd() *cobra.Command {
 		"all-namespaces", "A", false, "Apply operation to all clusters in all namespaces")
 	maintenanceCmd.PersistentFlags().BoolVar(&reusePVC,
 		"reusePVC", false, "Optional flag to set 'reusePVC' to true")
-	maintenanceCmd.PersistentFlags().BoolVarP(&confirmationRequired,
-		"yes", "y", false, "Whether it should ask for confirmation before proceeding")
+	maintenanceCmd.PersistentFlags().BoolVarP(&skipConfirmation,
+		"yes", "y", false, "Proceed without asking for confirmation")
 
 	return maintenanceCmd
 }
@@ -80,13 +80,23 @@ func Maintenance(ctx context.Context,
 		}
 	}
 
+	// A failure on a cluster doesn't prevent the other ones from being
+	// updated, as the command is often used across many namespaces
+	var failedClusters []string
 	for _, item := range clusterList.Items {
 		err := patchNodeMaintenanceWindow(ctx, item, setInProgressTo, reusePVC)
 		if err != nil {
-			return fmt.Errorf("unable to set progress to cluster %v in namespace %v", item.Name, item.Namespace)
+			fmt.Printf("unable to set maintenance to cluster %v in namespace %v: %v\n",
+				item.Name, item.Namespace, err)
+			failedClusters = append(failedClusters, fmt.Sprintf("%v/%v", item.Namespace, item.Name))
 		}
 	}
 
+	if len(failedClusters) > 0 {
+		return fmt.Errorf("unable to set maintenance to %d out of %d clusters: %v",
+			len(failedClusters), len(clusterList.Items), strings.Join(failedClusters, ", "))
+	}
+
 	return nil
 }
 
@@ -55,6 +55,7 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 
 	// The Pod exists, let's update status fields
 	cluster.Status.TargetPrimary = serverName
+	cluster.Status.TargetPrimaryReason = apiv1.PrimaryChangeReasonManual
 	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
 	cluster.Status.Phase = apiv1.PhaseSwitchover
 	cluster.Status.PhaseReason = fmt.Sprintf("Switching over to %v", serverName)
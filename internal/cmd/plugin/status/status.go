@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cheynewallace/tabby"
@@ -162,6 +163,10 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 	} else {
 		summary.AddLine("Ready instances:", aurora.Red(cluster.Status.ReadyInstances))
 	}
+	if len(cluster.Status.PendingRestartParameters) > 0 {
+		summary.AddLine("Pending restart:",
+			aurora.Yellow(strings.Join(cluster.Status.PendingRestartParameters, ", ")))
+	}
 
 	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
 		if cluster.Status.CurrentPrimary == "" {
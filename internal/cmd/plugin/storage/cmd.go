@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key [cluster] [node]",
+	Short: "Move the volumes of the instance named [cluster]-[node] or [node] to a new encrypted storage class",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clusterName := args[0]
+		node := args[1]
+		if _, err := strconv.Atoi(args[1]); err == nil {
+			node = fmt.Sprintf("%s-%s", clusterName, node)
+		}
+
+		storageClass, err := cmd.Flags().GetString("storage-class")
+		if err != nil {
+			return err
+		}
+		walStorageClass, err := cmd.Flags().GetString("wal-storage-class")
+		if err != nil {
+			return err
+		}
+		if walStorageClass == "" {
+			walStorageClass = storageClass
+		}
+
+		rotateKey, err := newRotateKeyCommand(cmd.Context(), clusterName, node, storageClass, walStorageClass)
+		if err != nil {
+			return err
+		}
+
+		return rotateKey.execute()
+	},
+}
+
+// NewCmd initializes the storage command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: `Storage related commands`,
+	}
+
+	cmd.AddCommand(rotateKeyCmd)
+
+	rotateKeyCmd.Flags().String(
+		"storage-class",
+		"",
+		"The storage class, using the new encryption key, where the PGDATA volume will be cloned")
+	rotateKeyCmd.Flags().String(
+		"wal-storage-class",
+		"",
+		"The storage class where the WAL volume will be cloned, defaults to the one used for PGDATA")
+	_ = rotateKeyCmd.MarkFlagRequired("storage-class")
+
+	return cmd
+}
@@ -0,0 +1,436 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage implements the commands operating on the volumes of the instances
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/controllers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+var rotationBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 10 * time.Second,
+	Factor:   5.0,
+	Jitter:   0.1,
+}
+
+// rotateKeyCommand represent the `storage rotate-key` subcommand
+type rotateKeyCommand struct {
+	ctx             context.Context
+	cluster         *apiv1.Cluster
+	instance        corev1.Pod
+	storageClass    string
+	walStorageClass string
+	shouldRollback  bool
+
+	pvcs         []corev1.PersistentVolumeClaim
+	newPVCs      []corev1.PersistentVolumeClaim
+	newPodName   string
+	newPodSerial int
+}
+
+// newRotateKeyCommand creates a new `storage rotate-key` command
+func newRotateKeyCommand(
+	ctx context.Context,
+	clusterName string,
+	instanceName string,
+	storageClass string,
+	walStorageClass string,
+) (*rotateKeyCommand, error) {
+	var cluster apiv1.Cluster
+
+	// Get the Cluster object
+	err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster)
+	if err != nil {
+		return nil, fmt.Errorf("could not get cluster: %v", err)
+	}
+
+	// Get the instance whose volumes will be rotated
+	var instance corev1.Pod
+	err = plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: instanceName},
+		&instance)
+	if err != nil {
+		return nil, fmt.Errorf("could not get instance %s: %v", instanceName, err)
+	}
+	if owner, isOwned := controllers.IsOwnedByCluster(&instance); !isOwned || owner != clusterName {
+		return nil, fmt.Errorf("instance %s is not owned by cluster %s", instanceName, clusterName)
+	}
+
+	pvcs, err := resources.GetInstancePVCs(ctx, clusterName, instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get PVCs: %w", err)
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues(
+		"clusterName", clusterName,
+		"instance", instanceName)
+
+	return &rotateKeyCommand{
+		ctx:             log.IntoContext(ctx, contextLogger),
+		cluster:         &cluster,
+		instance:        instance,
+		storageClass:    storageClass,
+		walStorageClass: walStorageClass,
+		pvcs:            pvcs,
+		shouldRollback:  false,
+	}, nil
+}
+
+// execute executes the `storage rotate-key` command
+func (cmd *rotateKeyCommand) execute() error {
+	if err := cmd.checkPreconditionsStep(); err != nil {
+		return err
+	}
+
+	cmd.printAdvancement("storage key rotation starting...")
+
+	if err := cmd.fenceInstanceStep(); err != nil {
+		cmd.shouldRollback = true
+		return err
+	}
+	defer cmd.rollbackFenceInstanceIfNeeded()
+
+	cmd.printAdvancement("waiting for the instance to be fenced")
+
+	if err := cmd.waitInstanceToBeFencedStep(); err != nil {
+		cmd.shouldRollback = true
+		return err
+	}
+
+	cmd.printAdvancement("instance is now fenced, pausing the reconciliation loop")
+
+	// The operator would otherwise race with us, reattaching or removing
+	// the PVCs while we are swapping them
+	if err := setReconciliationLoop(cmd.ctx, cmd.cluster.Name, false); err != nil {
+		cmd.shouldRollback = true
+		return err
+	}
+	defer func() {
+		if err := setReconciliationLoop(cmd.ctx, cmd.cluster.Name, true); err != nil {
+			fmt.Printf("could not resume the reconciliation loop, please remove the %s annotation: %v\n",
+				utils.ReconciliationLoopAnnotationName, err)
+		}
+	}()
+
+	if err := cmd.clonePVCsStep(); err != nil {
+		cmd.shouldRollback = true
+		return err
+	}
+	defer cmd.rollbackClonedPVCsIfNeeded()
+
+	cmd.printAdvancement(fmt.Sprintf("PVCs cloned for the new instance %s", cmd.newPodName))
+
+	if err := cmd.swapInstanceStep(); err != nil {
+		return err
+	}
+
+	cmd.printAdvancement(fmt.Sprintf("waiting for the new instance %s to be ready", cmd.newPodName))
+
+	// The old PVCs are used as data source by the new ones, and we
+	// can remove them only when the new instance has started
+	if err := cmd.waitNewInstanceStep(); err != nil {
+		return err
+	}
+
+	if err := cmd.deleteOldPVCsStep(); err != nil {
+		return err
+	}
+
+	cmd.printAdvancement("Storage key rotation completed")
+	return nil
+}
+
+// checkPreconditionsStep checks if the preconditions for the execution of this step are
+// met or not. If they are not met, it will return an error
+func (cmd *rotateKeyCommand) checkPreconditionsStep() error {
+	if cmd.instance.Name == cmd.cluster.Status.CurrentPrimary ||
+		cmd.instance.Name == cmd.cluster.Status.TargetPrimary {
+		return fmt.Errorf("cannot rotate the storage key of the primary instance, please promote another instance first")
+	}
+
+	fencedInstances, err := utils.GetFencedInstances(cmd.cluster.Annotations)
+	if err != nil {
+		return fmt.Errorf("could not check if cluster is fenced: %v", err)
+	}
+	if fencedInstances.Len() > 0 {
+		return fmt.Errorf("cannot rotate the storage key of a cluster that has fenced instances")
+	}
+
+	if utils.IsReconciliationDisabled(&cmd.cluster.ObjectMeta) {
+		return fmt.Errorf("cannot rotate the storage key of a cluster whose reconciliation loop is disabled")
+	}
+
+	if len(cmd.pvcs) == 0 {
+		return fmt.Errorf("no PVC found for instance %s", cmd.instance.Name)
+	}
+
+	return nil
+}
+
+func (cmd *rotateKeyCommand) fenceInstanceStep() error {
+	return fence.ApplyFenceFunc(
+		cmd.ctx,
+		plugin.Client,
+		cmd.cluster.Name,
+		plugin.Namespace,
+		cmd.instance.Name,
+		utils.AddFencedInstance,
+	)
+}
+
+// rollbackFenceInstanceIfNeeded removes the fencing status from the instance
+func (cmd *rotateKeyCommand) rollbackFenceInstanceIfNeeded() {
+	if !cmd.shouldRollback {
+		return
+	}
+
+	fmt.Println("rolling back storage key rotation: removing the fencing annotation")
+	if err := fence.ApplyFenceFunc(
+		cmd.ctx,
+		plugin.Client,
+		cmd.cluster.Name,
+		plugin.Namespace,
+		cmd.instance.Name,
+		utils.RemoveFencedInstance,
+	); err != nil {
+		log.FromContext(cmd.ctx).Error(err, "Rolling back the fencing annotation failed")
+	}
+}
+
+// waitInstanceToBeFencedStep waits for PostgreSQL to be shut down, so that
+// the volumes are cloned in a consistent state
+func (cmd *rotateKeyCommand) waitInstanceToBeFencedStep() error {
+	always := func(err error) bool { return true }
+	return retry.OnError(rotationBackoff, always, func() error {
+		stopped, err := resources.IsInstanceStopped(cmd.ctx, cmd.instance)
+		if err != nil {
+			return fmt.Errorf("error checking instance status (%v): %w", cmd.instance.Name, err)
+		}
+		if !stopped {
+			return fmt.Errorf("instance still running (%v)", cmd.instance.Name)
+		}
+		return nil
+	})
+}
+
+// clonePVCsStep creates the PVCs of the new instance, cloning the
+// current ones into the new storage classes. They are created in the
+// initializing status, so the operator will not use them until we are done
+func (cmd *rotateKeyCommand) clonePVCsStep() error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		cmd.ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cmd.cluster.Name},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	// This is what the operator does when creating a new instance
+	origCluster := cluster.DeepCopy()
+	cluster.Status.LatestGeneratedNode++
+	if err := plugin.Client.Status().Patch(cmd.ctx, &cluster, client.MergeFrom(origCluster)); err != nil {
+		return fmt.Errorf("cannot generate node serial: %w", err)
+	}
+	cmd.newPodSerial = cluster.Status.LatestGeneratedNode
+	cmd.newPodName = specs.GetInstanceName(cluster.Name, cmd.newPodSerial)
+
+	for _, pvc := range cmd.pvcs {
+		storageClass := cmd.storageClass
+		if pvc.Labels[utils.PvcRoleLabelName] == string(utils.PVCRolePgWal) {
+			storageClass = cmd.walStorageClass
+		}
+
+		newPVC := specs.ClonePVC(cluster, pvc, cmd.newPodSerial, &storageClass)
+		if err := plugin.Client.Create(cmd.ctx, newPVC); err != nil {
+			return fmt.Errorf("while cloning PVC %s: %w", pvc.Name, err)
+		}
+		cmd.newPVCs = append(cmd.newPVCs, *newPVC)
+	}
+
+	return nil
+}
+
+// rollbackClonedPVCsIfNeeded removes the PVCs that have been cloned
+func (cmd *rotateKeyCommand) rollbackClonedPVCsIfNeeded() {
+	if !cmd.shouldRollback {
+		return
+	}
+
+	fmt.Println("rolling back storage key rotation: removing the cloned PVCs")
+	for i := range cmd.newPVCs {
+		if err := plugin.Client.Delete(cmd.ctx, &cmd.newPVCs[i]); err != nil && !apierrs.IsNotFound(err) {
+			fmt.Printf("could not remove PVC %s: %v\n", cmd.newPVCs[i].Name, err)
+		}
+	}
+}
+
+// swapInstanceStep replaces the fenced instance with a new one, using the cloned PVCs
+func (cmd *rotateKeyCommand) swapInstanceStep() error {
+	// from this point there is no going back
+	cmd.printAdvancement(fmt.Sprintf("deleting instance %s", cmd.instance.Name))
+	if err := plugin.Client.Delete(cmd.ctx, &cmd.instance); err != nil && !apierrs.IsNotFound(err) {
+		cmd.shouldRollback = true
+		return fmt.Errorf("error deleting instance %s: %w", cmd.instance.Name, err)
+	}
+
+	// The old PVCs will not be reattached by the operator
+	if err := setPVCStatus(cmd.ctx, cmd.pvcs, specs.PVCStatusDetached); err != nil {
+		return fmt.Errorf("could not detach the old PVCs: %w", err)
+	}
+
+	// The operator will create the new instance using these PVCs as
+	// soon as the reconciliation loop is resumed
+	if err := setPVCStatus(cmd.ctx, cmd.newPVCs, specs.PVCStatusReady); err != nil {
+		return fmt.Errorf("could not mark the cloned PVCs as ready: %w", err)
+	}
+
+	// The instance doesn't exist anymore, so we can't use fence.ApplyFenceFunc
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var cluster apiv1.Cluster
+		if err := plugin.Client.Get(
+			cmd.ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: cmd.cluster.Name},
+			&cluster,
+		); err != nil {
+			return err
+		}
+
+		origCluster := cluster.DeepCopy()
+		if err := utils.RemoveFencedInstance(cmd.instance.Name, &cluster.ObjectMeta); err != nil {
+			return err
+		}
+
+		return plugin.Client.Patch(cmd.ctx, &cluster, client.MergeFrom(origCluster))
+	}); err != nil {
+		return fmt.Errorf("could not remove the fencing annotation: %w", err)
+	}
+
+	return nil
+}
+
+// waitNewInstanceStep waits for the new instance to be created and to be ready
+func (cmd *rotateKeyCommand) waitNewInstanceStep() error {
+	always := func(err error) bool { return true }
+	return retry.OnError(rotationBackoff, always, func() error {
+		var pod corev1.Pod
+		if err := plugin.Client.Get(
+			cmd.ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: cmd.newPodName},
+			&pod,
+		); err != nil {
+			return err
+		}
+		if !utils.IsPodReady(pod) {
+			return fmt.Errorf("instance not ready yet (%v)", cmd.newPodName)
+		}
+		return nil
+	})
+}
+
+// deleteOldPVCsStep removes the volumes encrypted with the old key
+func (cmd *rotateKeyCommand) deleteOldPVCsStep() error {
+	for i := range cmd.pvcs {
+		if err := plugin.Client.Delete(cmd.ctx, &cmd.pvcs[i]); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("error deleting pvc %s: %w", cmd.pvcs[i].Name, err)
+		}
+		cmd.printAdvancement(fmt.Sprintf("PVC %s deleted", cmd.pvcs[i].Name))
+	}
+
+	return nil
+}
+
+func (cmd *rotateKeyCommand) printAdvancement(msg string) {
+	fmt.Println(msg)
+}
+
+// setReconciliationLoop enables or disables the reconciliation loop of the cluster
+func setReconciliationLoop(ctx context.Context, clusterName string, enabled bool) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var cluster apiv1.Cluster
+		if err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+			&cluster,
+		); err != nil {
+			return err
+		}
+
+		origCluster := cluster.DeepCopy()
+		if enabled {
+			delete(cluster.Annotations, utils.ReconciliationLoopAnnotationName)
+		} else {
+			if cluster.Annotations == nil {
+				cluster.Annotations = map[string]string{}
+			}
+			cluster.Annotations[utils.ReconciliationLoopAnnotationName] = utils.ReconciliationDisabledValue
+		}
+
+		return plugin.Client.Patch(ctx, &cluster, client.MergeFrom(origCluster))
+	})
+}
+
+// setPVCStatus sets the passed status in the annotation of the PVCs
+func setPVCStatus(ctx context.Context, pvcs []corev1.PersistentVolumeClaim, status string) error {
+	for _, pvc := range pvcs {
+		if err := retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
+			var currentPVC corev1.PersistentVolumeClaim
+			if err := plugin.Client.Get(
+				ctx,
+				types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace},
+				&currentPVC,
+			); err != nil {
+				return err
+			}
+
+			origPVC := currentPVC.DeepCopy()
+			if currentPVC.Annotations == nil {
+				currentPVC.Annotations = map[string]string{}
+			}
+			currentPVC.Annotations[specs.PVCStatusAnnotationName] = status
+
+			return plugin.Client.Patch(ctx, &currentPVC, client.MergeFrom(origPVC))
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
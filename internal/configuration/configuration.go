@@ -21,9 +21,11 @@ package configuration
 import (
 	"path"
 	"strings"
+	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configparser"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
@@ -32,6 +34,28 @@ var configurationLog = log.WithName("configuration")
 // DefaultOperatorPullSecretName is implicitly copied into newly created clusters.
 const DefaultOperatorPullSecretName = "cnpg-pull-secret" // #nosec
 
+const (
+	// DefaultCertificateDuration is the default lifetime, in days, of the
+	// certificates generated for the clusters
+	DefaultCertificateDuration = 90
+
+	// DefaultExpiringCheckThreshold is the default number of days before
+	// their expiration when the certificates of the clusters are renewed
+	DefaultExpiringCheckThreshold = 7
+
+	// DefaultRequeueBaseDelay is the default delay, in milliseconds, of
+	// the first requeue of a cluster whose reconciliation didn't complete
+	DefaultRequeueBaseDelay = 100
+
+	// DefaultRequeueMaxDelay is the default maximum delay, in seconds,
+	// of the requeues of a cluster
+	DefaultRequeueMaxDelay = 300
+
+	// DefaultRequeueRate is the default number of requeues per second
+	// allowed across all the clusters
+	DefaultRequeueRate = 10
+)
+
 // Data is the struct containing the configuration of the operator.
 // Usually the operator code will use the "Current" configuration.
 type Data struct {
@@ -62,6 +86,11 @@ type Data struct {
 	// used by default for new clusters
 	PostgresImageName string `json:"postgresImageName" env:"POSTGRES_IMAGE_NAME"`
 
+	// PostgresMinorReleases is the catalog of the latest PostgreSQL minor
+	// releases, in the "<version>=<release date>" format, used to detect
+	// the clusters running an outdated minor version
+	PostgresMinorReleases []string `json:"postgresMinorReleases" env:"POSTGRES_MINOR_RELEASES"`
+
 	// InheritedAnnotations is a list of annotations that every resource could inherit from
 	// the owning Cluster
 	InheritedAnnotations []string `json:"inheritedAnnotations" env:"INHERITED_ANNOTATIONS"`
@@ -74,9 +103,17 @@ type Data struct {
 	// replacing the executable in a pod without restarting
 	EnableInstanceManagerInplaceUpdates bool `json:"enableInstanceManagerInplaceUpdates" env:"ENABLE_INSTANCE_MANAGER_INPLACE_UPDATES"` //nolint
 
+	// EnableInstanceStatusWatch makes the operator watch the status streamed
+	// by the instance managers, instead of polling it at every reconciliation
+	EnableInstanceStatusWatch bool `json:"enableInstanceStatusWatch" env:"ENABLE_INSTANCE_STATUS_WATCH"`
+
 	// EnableAzurePVCUpdates enables the live update of PVC in Azure environment
 	EnableAzurePVCUpdates bool `json:"enableAzurePVCUpdates" env:"ENABLE_AZURE_PVC_UPDATES"`
 
+	// FailoverDryRun makes the operator only report the failovers and
+	// switchovers it would execute, unless a cluster is annotated otherwise
+	FailoverDryRun bool `json:"failoverDryRun" env:"FAILOVER_DRY_RUN"`
+
 	// MonitoringQueriesConfigmap is the name of the configmap in the operator namespace which contain
 	// the monitoring queries. The queries will be read from the data key: "queries".
 	MonitoringQueriesConfigmap string `json:"monitoringQueriesConfigmap" env:"MONITORING_QUERIES_CONFIGMAP"`
@@ -84,6 +121,32 @@ type Data struct {
 	// MonitoringQueriesSecret is the name of the secret in the operator namespace which contain
 	// the monitoring queries. The queries will be read from the data key: "queries".
 	MonitoringQueriesSecret string `json:"monitoringQueriesSecret" env:"MONITORING_QUERIES_SECRET"`
+
+	// ClusterTemplateConfigmap is the name of the configmap in the operator
+	// namespace which contains the template merged into every new cluster.
+	// The template is read from the "defaults" and "overrides" data keys
+	ClusterTemplateConfigmap string `json:"clusterTemplateConfigmap" env:"CLUSTER_TEMPLATE_CONFIGMAP"`
+
+	// CertificateDuration is the lifetime, in days, of the certificates
+	// generated by the operator for the clusters
+	CertificateDuration int `json:"certificateDuration" env:"CERTIFICATE_DURATION"`
+
+	// ExpiringCheckThreshold is the number of days before their expiration
+	// when the certificates generated for the clusters are renewed
+	ExpiringCheckThreshold int `json:"expiringCheckThreshold" env:"EXPIRING_CHECK_THRESHOLD"`
+
+	// RequeueBaseDelay is the delay, in milliseconds, of the first requeue
+	// of a cluster. The delay doubles at every following requeue of the
+	// same cluster, until its reconciliation completes
+	RequeueBaseDelay int `json:"requeueBaseDelay" env:"REQUEUE_BASE_DELAY"`
+
+	// RequeueMaxDelay is the maximum delay, in seconds, of the requeues
+	// of a cluster
+	RequeueMaxDelay int `json:"requeueMaxDelay" env:"REQUEUE_MAX_DELAY"`
+
+	// RequeueRate is the number of requeues per second allowed across
+	// all the clusters
+	RequeueRate int `json:"requeueRate" env:"REQUEUE_RATE"`
 }
 
 // Current is the configuration used by the operator
@@ -95,6 +158,11 @@ func newDefaultConfig() *Data {
 		OperatorPullSecretName: DefaultOperatorPullSecretName,
 		OperatorImageName:      versions.DefaultOperatorImageName,
 		PostgresImageName:      versions.DefaultImageName,
+		CertificateDuration:    DefaultCertificateDuration,
+		ExpiringCheckThreshold: DefaultExpiringCheckThreshold,
+		RequeueBaseDelay:       DefaultRequeueBaseDelay,
+		RequeueMaxDelay:        DefaultRequeueMaxDelay,
+		RequeueRate:            DefaultRequeueRate,
 	}
 }
 
@@ -123,6 +191,31 @@ func (config *Data) IsLabelInherited(name string) bool {
 	return evaluateGlobPatterns(config.InheritedLabels, name)
 }
 
+// GetRequeueBaseDelay gets the delay of the first requeue of a cluster
+func (config *Data) GetRequeueBaseDelay() time.Duration {
+	if config.RequeueBaseDelay <= 0 {
+		return DefaultRequeueBaseDelay * time.Millisecond
+	}
+	return time.Duration(config.RequeueBaseDelay) * time.Millisecond
+}
+
+// GetRequeueMaxDelay gets the maximum delay of the requeues of a cluster
+func (config *Data) GetRequeueMaxDelay() time.Duration {
+	if config.RequeueMaxDelay <= 0 {
+		return DefaultRequeueMaxDelay * time.Second
+	}
+	return time.Duration(config.RequeueMaxDelay) * time.Second
+}
+
+// GetRequeueRate gets the number of requeues per second allowed
+// across all the clusters
+func (config *Data) GetRequeueRate() int {
+	if config.RequeueRate <= 0 {
+		return DefaultRequeueRate
+	}
+	return config.RequeueRate
+}
+
 // WatchedNamespaces get the list of additional watched namespaces.
 // The result is a list of namespaces specified in the WATCHED_NAMESPACE where
 // each namespace is separated by comma
@@ -130,6 +223,23 @@ func (config *Data) WatchedNamespaces() []string {
 	return cleanNamespaceList(config.WatchNamespace)
 }
 
+// HasSameWatchedNamespaces checks if two configurations watch the same
+// namespaces, regardless of the order in which they are listed
+func (config *Data) HasSameWatchedNamespaces(other *Data) bool {
+	namespaces := stringset.From(config.WatchedNamespaces())
+	otherNamespaces := stringset.From(other.WatchedNamespaces())
+	if namespaces.Len() != otherNamespaces.Len() {
+		return false
+	}
+
+	for _, namespace := range otherNamespaces.ToList() {
+		if !namespaces.Has(namespace) {
+			return false
+		}
+	}
+	return true
+}
+
 func cleanNamespaceList(namespaces string) (result []string) {
 	unfilteredList := strings.Split(namespaces, ",")
 	result = make([]string, 0, len(unfilteredList))
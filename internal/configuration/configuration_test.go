@@ -17,6 +17,8 @@ limitations under the License.
 package configuration
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -127,4 +129,32 @@ var _ = Describe("Annotation and label inheritance", func() {
 			}))
 		})
 	})
+
+	It("detects the changes of the watched namespaces", func() {
+		config := Data{WatchNamespace: "pg,pg_staging"}
+		Expect(config.HasSameWatchedNamespaces(&Data{WatchNamespace: " pg_staging, pg"})).To(BeTrue())
+		Expect(config.HasSameWatchedNamespaces(&Data{WatchNamespace: "pg,pg_staging,pg_prod"})).To(BeFalse())
+		Expect(config.HasSameWatchedNamespaces(&Data{})).To(BeFalse())
+	})
+})
+
+var _ = Describe("Requeue rate limiting", func() {
+	It("uses the defaults when the values are not set", func() {
+		config := Data{}
+		Expect(config.GetRequeueBaseDelay()).To(Equal(100 * time.Millisecond))
+		Expect(config.GetRequeueMaxDelay()).To(Equal(5 * time.Minute))
+		Expect(config.GetRequeueRate()).To(Equal(10))
+	})
+
+	It("uses the configured values", func() {
+		config := newDefaultConfig()
+		config.ReadConfigMap(map[string]string{
+			"REQUEUE_BASE_DELAY": "500",
+			"REQUEUE_MAX_DELAY":  "60",
+			"REQUEUE_RATE":       "50",
+		})
+		Expect(config.GetRequeueBaseDelay()).To(Equal(500 * time.Millisecond))
+		Expect(config.GetRequeueMaxDelay()).To(Equal(time.Minute))
+		Expect(config.GetRequeueRate()).To(Equal(50))
+	})
 })
@@ -0,0 +1,202 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adminsessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// checkInterval is how often the privileged sessions are checked
+const checkInterval = 10 * time.Second
+
+// privilegedSessionsQuery lists the client sessions of the superusers and
+// of the passed roles, excluding the ones opened through the local socket
+// by the instance manager and by barman-cloud-backup, which stays idle
+// while uploading the base backup
+const privilegedSessionsQuery = `
+SELECT a.pid,
+	a.usename,
+	a.state,
+	EXTRACT(EPOCH FROM (pg_catalog.now() - a.backend_start)),
+	EXTRACT(EPOCH FROM (pg_catalog.now() - a.state_change))
+FROM pg_catalog.pg_stat_activity a
+JOIN pg_catalog.pg_roles r ON r.oid = a.usesysid
+WHERE a.backend_type = 'client backend'
+	AND a.pid <> pg_catalog.pg_backend_pid()
+	AND a.state IS NOT NULL
+	AND (r.rolsuper OR r.rolname = ANY($1))
+	AND NOT (a.client_addr IS NULL
+		AND a.application_name IN ('cnpg-instance-manager', 'cnpg_metrics_exporter', 'barman_cloud_backup'))`
+
+// terminationReason is why a privileged session has been terminated
+type terminationReason string
+
+const (
+	reasonIdleTimeout terminationReason = "IdleTimeout"
+	reasonMaxDuration terminationReason = "MaxDuration"
+)
+
+// session is a privileged session connected to the instance
+type session struct {
+	pid       int
+	role      string
+	state     string
+	duration  time.Duration
+	stateTime time.Duration
+}
+
+// isIdle checks if the session is waiting for a new command from the client
+func (s session) isIdle() bool {
+	switch s.state {
+	case "idle", "idle in transaction", "idle in transaction (aborted)":
+		return true
+	default:
+		return false
+	}
+}
+
+// getTerminationReason checks if a session exceeds the limits of the
+// policy, returning an empty reason when it doesn't
+func getTerminationReason(s session, policy *apiv1.AdminSessionPolicy) terminationReason {
+	if maxDuration := policy.GetMaxDuration(); maxDuration > 0 && s.duration >= maxDuration {
+		return reasonMaxDuration
+	}
+
+	if idleTimeout := policy.GetIdleTimeout(); idleTimeout > 0 && s.isIdle() && s.stateTime >= idleTimeout {
+		return reasonIdleTimeout
+	}
+
+	return ""
+}
+
+// Terminator is a runner periodically terminating the privileged sessions
+// exceeding the limits of the admin session policy of the cluster
+type Terminator struct {
+	instance *postgres.Instance
+}
+
+// NewTerminator creates a new privileged sessions terminator
+func NewTerminator(instance *postgres.Instance) *Terminator {
+	return &Terminator{
+		instance: instance,
+	}
+}
+
+// Start starts running the privileged sessions terminator
+func (t *Terminator) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("AdminSessionsTerminator")
+
+	ticker := time.NewTicker(checkInterval)
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated admin sessions terminator loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		cluster, err := cache.LoadCluster()
+		// there isn't a cached object yet
+		if errors.Is(err, cache.ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			contextLog.Warning("while retrieving cluster cache object", "err", err)
+			continue
+		}
+
+		policy := cluster.GetAdminSessionPolicy()
+		if policy == nil {
+			continue
+		}
+
+		if t.instance.IsFenced() || t.instance.MightBeUnavailable() {
+			continue
+		}
+
+		db, err := t.instance.GetSuperUserDB()
+		if err != nil {
+			contextLog.Warning("while connecting to the instance", "err", err)
+			continue
+		}
+
+		if err := enforce(log.IntoContext(ctx, contextLog), db, policy); err != nil {
+			contextLog.Warning("while enforcing the admin session policy", "err", err)
+		}
+	}
+}
+
+// enforce terminates the privileged sessions exceeding the limits of the policy
+func enforce(ctx context.Context, db *sql.DB, policy *apiv1.AdminSessionPolicy) error {
+	rows, err := db.QueryContext(ctx, privilegedSessionsQuery, pq.Array(policy.Roles))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	type termination struct {
+		session
+		reason terminationReason
+	}
+	var terminations []termination
+	for rows.Next() {
+		var s session
+		var duration, stateTime float64
+		if err := rows.Scan(&s.pid, &s.role, &s.state, &duration, &stateTime); err != nil {
+			return err
+		}
+		s.duration = time.Duration(duration * float64(time.Second))
+		s.stateTime = time.Duration(stateTime * float64(time.Second))
+
+		if reason := getTerminationReason(s, policy); reason != "" {
+			terminations = append(terminations, termination{session: s, reason: reason})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	contextLog := log.FromContext(ctx)
+	for _, item := range terminations {
+		contextLog.Info("Terminating a privileged session exceeding the admin session policy",
+			"pid", item.pid,
+			"role", item.role,
+			"state", item.state,
+			"reason", item.reason)
+		if _, err := db.ExecContext(ctx, "SELECT pg_catalog.pg_terminate_backend($1)", item.pid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adminsessions
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("admin session policy", func() {
+	policy := &apiv1.AdminSessionPolicy{
+		IdleTimeout: 600,
+		MaxDuration: 3600,
+	}
+
+	DescribeTable("termination reason",
+		func(s session, expected terminationReason) {
+			Expect(getTerminationReason(s, policy)).To(Equal(expected))
+		},
+		Entry("an active session within the limits",
+			session{state: "active", duration: time.Minute, stateTime: time.Minute}, terminationReason("")),
+		Entry("a long running active session",
+			session{state: "active", duration: 2 * time.Hour, stateTime: time.Hour}, reasonMaxDuration),
+		Entry("an active session running for longer than the idle timeout",
+			session{state: "active", duration: time.Hour - time.Second, stateTime: 20 * time.Minute}, terminationReason("")),
+		Entry("an idle session",
+			session{state: "idle", duration: 20 * time.Minute, stateTime: 15 * time.Minute}, reasonIdleTimeout),
+		Entry("a session idle in a transaction",
+			session{state: "idle in transaction", duration: 20 * time.Minute, stateTime: 10 * time.Minute},
+			reasonIdleTimeout),
+		Entry("a recently idle session",
+			session{state: "idle", duration: 20 * time.Minute, stateTime: time.Minute}, terminationReason("")),
+	)
+
+	It("ignores the disabled limits", func() {
+		s := session{state: "idle", duration: 48 * time.Hour, stateTime: 24 * time.Hour}
+		Expect(getTerminationReason(s, &apiv1.AdminSessionPolicy{MaxDuration: 3600})).To(Equal(reasonMaxDuration))
+		Expect(getTerminationReason(s, &apiv1.AdminSessionPolicy{IdleTimeout: 600})).To(Equal(reasonIdleTimeout))
+		Expect(getTerminationReason(s, &apiv1.AdminSessionPolicy{})).To(BeEmpty())
+	})
+})
+
+// fakeConnector is a database/sql connector returning the configured
+// sessions from the privileged sessions query, and recording the
+// terminated ones
+type fakeConnector struct {
+	sessions   [][]driver.Value
+	queryArgs  []driver.NamedValue
+	terminated []driver.Value
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn{connector: c}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	connector *fakeConnector
+}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if query != privilegedSessionsQuery {
+		return nil, errors.New("unexpected query")
+	}
+	c.connector.queryArgs = args
+	return &fakeRows{values: c.connector.sessions}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query != "SELECT pg_catalog.pg_terminate_backend($1)" {
+		return nil, errors.New("unexpected statement")
+	}
+	c.connector.terminated = append(c.connector.terminated, args[0].Value)
+	return driver.RowsAffected(0), nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"pid", "usename", "state", "duration", "state_time"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var _ = Describe("admin session policy enforcement", func() {
+	It("never lists the sessions of the instance manager and of barman-cloud-backup", func() {
+		Expect(privilegedSessionsQuery).To(ContainSubstring("a.client_addr IS NULL"))
+		for _, applicationName := range []string{"cnpg-instance-manager", "cnpg_metrics_exporter", "barman_cloud_backup"} {
+			Expect(privilegedSessionsQuery).To(ContainSubstring("'" + applicationName + "'"))
+		}
+	})
+
+	It("terminates only the sessions exceeding the limits", func() {
+		connector := &fakeConnector{
+			sessions: [][]driver.Value{
+				{int64(100), "postgres", "idle", float64(3000), float64(1200)},
+				{int64(101), "dba", "active", float64(7200), float64(7200)},
+				{int64(102), "dba", "idle", float64(60), float64(30)},
+			},
+		}
+		db := sql.OpenDB(connector)
+		DeferCleanup(db.Close)
+
+		policy := &apiv1.AdminSessionPolicy{
+			IdleTimeout: 600,
+			MaxDuration: 3600,
+			Roles:       []string{"dba"},
+		}
+		Expect(enforce(context.Background(), db, policy)).To(Succeed())
+		Expect(connector.queryArgs).To(HaveLen(1))
+		Expect(connector.queryArgs[0].Value).To(Equal("{\"dba\"}"))
+		Expect(connector.terminated).To(Equal([]driver.Value{int64(100), int64(101)}))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileUserFromFile sets the password of a user to the content of a
+// file provided by an external secret store, such as a CSI volume or the
+// Vault agent. The password is set again only when the file changes
+func (r *InstanceReconciler) reconcileUserFromFile(
+	ctx context.Context,
+	username string,
+	passwordFile string,
+	tx *sql.Tx,
+) error {
+	password, err := readPasswordFile(passwordFile)
+	if errors.Is(err, os.ErrNotExist) {
+		// The secret store may not have written the file yet
+		log.FromContext(ctx).Info("Credentials file not found, waiting for it to be created",
+			"user", username, "file", passwordFile)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	version := fmt.Sprintf("%x", sha256.Sum256([]byte(password)))
+	if r.secretVersions[passwordFile] == version {
+		// Everything fine, we already applied this password
+		return nil
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("ALTER ROLE %v WITH PASSWORD %v",
+		pgx.Identifier{username}.Sanitize(),
+		pq.QuoteLiteral(password)))
+	if err != nil {
+		return fmt.Errorf("while running ALTER ROLE %v WITH PASSWORD", username)
+	}
+
+	r.secretVersions[passwordFile] = version
+	return nil
+}
+
+// readPasswordFile reads a password from a file, ignoring the
+// trailing newline added by the tools writing it
+func readPasswordFile(passwordFile string) (string, error) {
+	content, err := os.ReadFile(passwordFile) // #nosec
+	if err != nil {
+		return "", err
+	}
+
+	password := strings.TrimRight(string(content), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("the credentials file %s is empty", passwordFile)
+	}
+
+	return password, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("credentials files", func() {
+	It("reads the password without the trailing newline", func() {
+		passwordFile := filepath.Join(GinkgoT().TempDir(), "password")
+		Expect(os.WriteFile(passwordFile, []byte("s3cr3t\n"), 0o600)).To(Succeed())
+
+		password, err := readPasswordFile(passwordFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(password).To(Equal("s3cr3t"))
+	})
+
+	It("complains about empty files", func() {
+		passwordFile := filepath.Join(GinkgoT().TempDir(), "password")
+		Expect(os.WriteFile(passwordFile, []byte("\n"), 0o600)).To(Succeed())
+
+		_, err := readPasswordFile(passwordFile)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports the files not yet created", func() {
+		_, err := readPasswordFile(filepath.Join(GinkgoT().TempDir(), "missing"))
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
+
+var _ = Describe("superuser password", func() {
+	It("is set again when the superuser access is enabled back", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				CredentialsFiles: &apiv1.CredentialsFilesConfiguration{
+					SuperuserPasswordFile: "/vault/secrets/postgres",
+				},
+			},
+		}
+		secretVersions := map[string]string{
+			"cluster-example-superuser": "1",
+			"/vault/secrets/postgres":   "2",
+			"cluster-example-app":       "3",
+		}
+		forgetSuperuserPassword(cluster, secretVersions)
+		Expect(secretVersions).To(Equal(map[string]string{"cluster-example-app": "3"}))
+	})
+})
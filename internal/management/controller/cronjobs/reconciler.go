@@ -0,0 +1,378 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	pgconfig "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcileInterval is how often the jobs are synchronized with pg_cron
+// and the results of their runs are collected
+const reconcileInterval = 30 * time.Second
+
+// scheduledJob is a job as scheduled in the cron.job table
+type scheduledJob struct {
+	id       int64
+	name     string
+	schedule string
+	command  string
+	database string
+	username string
+	active   bool
+}
+
+// matches checks if a scheduled job has the same definition of another one
+func (job scheduledJob) matches(other scheduledJob) bool {
+	return job.name == other.name &&
+		job.schedule == other.schedule &&
+		job.command == other.command &&
+		job.database == other.database &&
+		job.username == other.username &&
+		job.active == other.active
+}
+
+// newScheduledJob creates the definition of a job as expected in pg_cron,
+// running the command in the passed database unless one is requested
+func newScheduledJob(job apiv1.PgCronJob, defaultDatabase string) scheduledJob {
+	database := job.Spec.Database
+	if database == "" {
+		database = defaultDatabase
+	}
+
+	return scheduledJob{
+		name:     job.GetJobName(),
+		schedule: job.Spec.Schedule,
+		command:  job.Spec.Command,
+		database: database,
+		username: job.Spec.Username,
+		active:   !job.Spec.Suspend,
+	}
+}
+
+// getPgCronExtension gets the pg_cron managed extension
+func getPgCronExtension() pgconfig.ManagedExtension {
+	for _, extension := range pgconfig.ManagedExtensions {
+		if extension.Name == pgconfig.PgCronExtensionName {
+			return extension
+		}
+	}
+	return pgconfig.ManagedExtension{Name: pgconfig.PgCronExtensionName}
+}
+
+// Reconciler is a runner periodically scheduling the PgCronJob resources
+// of the cluster in pg_cron, on the primary instance
+type Reconciler struct {
+	instance *postgres.Instance
+	client   client.Client
+}
+
+// NewReconciler creates a new pg_cron jobs reconciler
+func NewReconciler(instance *postgres.Instance, client client.Client) *Reconciler {
+	return &Reconciler{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts running the pg_cron jobs reconciler
+func (r *Reconciler) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("PgCronJobsReconciler")
+
+	ticker := time.NewTicker(reconcileInterval)
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated pg_cron jobs reconciler loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		cluster, err := cache.LoadCluster()
+		// there isn't a cached object yet
+		if errors.Is(err, cache.ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			contextLog.Warning("while retrieving cluster cache object", "err", err)
+			continue
+		}
+
+		if err := r.reconcile(log.IntoContext(ctx, contextLog), cluster); err != nil {
+			contextLog.Warning("while reconciling the pg_cron jobs", "err", err)
+		}
+	}
+}
+
+// reconcile synchronizes the jobs scheduled in pg_cron with the PgCronJob
+// resources of the cluster, updating their status
+func (r *Reconciler) reconcile(ctx context.Context, cluster *apiv1.Cluster) error {
+	if r.instance.IsFenced() || r.instance.MightBeUnavailable() {
+		return nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return err
+	}
+
+	var jobList apiv1.PgCronJobList
+	if err := r.client.List(ctx, &jobList, client.InNamespace(r.instance.Namespace)); err != nil {
+		return fmt.Errorf("while listing the pg_cron jobs: %w", err)
+	}
+
+	var jobs []apiv1.PgCronJob
+	for _, job := range jobList.Items {
+		if job.Spec.Cluster.Name == cluster.Name {
+			jobs = append(jobs, job)
+		}
+	}
+
+	extension := getPgCronExtension()
+	if !cluster.IsManagedExtensionUsed(extension) {
+		for idx := range jobs {
+			status := apiv1.PgCronJobStatus{
+				Error: "pg_cron is not enabled in the cluster",
+			}
+			if err := r.updateStatus(ctx, &jobs[idx], status); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cronDatabase := extension.GetDatabase(cluster.Spec.PostgresConfiguration.Parameters)
+	db, err := r.instance.ConnectionPool().Connection(cronDatabase)
+	if err != nil {
+		return fmt.Errorf("while connecting to the %s database: %w", cronDatabase, err)
+	}
+
+	var isInstalled bool
+	row := db.QueryRowContext(ctx,
+		"SELECT COUNT(*) > 0 FROM pg_catalog.pg_extension WHERE extname = $1",
+		pgconfig.PgCronExtensionName)
+	if err := row.Scan(&isInstalled); err != nil {
+		return err
+	}
+	if !isInstalled {
+		// The extension is created by the instance reconciler
+		return nil
+	}
+
+	scheduledJobs, err := getScheduledJobs(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for idx := range jobs {
+		job := &jobs[idx]
+		desired := newScheduledJob(*job, cronDatabase)
+
+		// A job run by a superuser that hasn't been allowed is not
+		// scheduled, and is unscheduled if it was before
+		if !job.Spec.AllowSuperuser {
+			isSuperuser, err := isSuperuserRole(ctx, db, desired.username)
+			if err != nil {
+				return err
+			}
+			if isSuperuser {
+				status := apiv1.PgCronJobStatus{
+					Error: fmt.Sprintf("role %s is a superuser, and allowSuperuser is not set", desired.username),
+				}
+				if err := r.updateStatus(ctx, job, status); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		current, found := scheduledJobs[desired.name]
+		delete(scheduledJobs, desired.name)
+
+		status := apiv1.PgCronJobStatus{JobName: desired.name}
+		switch {
+		case !found:
+			desired.id, err = scheduleJob(ctx, db, desired)
+		case !current.matches(desired):
+			desired.id = current.id
+			err = alterJob(ctx, db, desired)
+		default:
+			desired.id = current.id
+		}
+
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.JobID = desired.id
+			if status.LastRun, err = getLastRun(ctx, db, desired.id); err != nil {
+				return err
+			}
+		}
+
+		if err := r.updateStatus(ctx, job, status); err != nil {
+			return err
+		}
+	}
+
+	// The jobs left are not backed by a PgCronJob anymore
+	contextLog := log.FromContext(ctx)
+	for _, job := range scheduledJobs {
+		contextLog.Info("Unscheduling pg_cron job", "jobName", job.name, "jobID", job.id)
+		if _, err := db.ExecContext(ctx, "SELECT cron.unschedule($1::bigint)", job.id); err != nil {
+			return fmt.Errorf("while unscheduling the pg_cron job %s: %w", job.name, err)
+		}
+	}
+
+	return nil
+}
+
+// updateStatus updates the status of a PgCronJob, if changed
+func (r *Reconciler) updateStatus(ctx context.Context, job *apiv1.PgCronJob, status apiv1.PgCronJobStatus) error {
+	if equality.Semantic.DeepEqual(job.Status, status) {
+		return nil
+	}
+
+	updatedJob := job.DeepCopy()
+	updatedJob.Status = status
+	if err := r.client.Status().Patch(ctx, updatedJob, client.MergeFrom(job)); err != nil {
+		return fmt.Errorf("while updating the status of the pg_cron job %s: %w", job.Name, err)
+	}
+	return nil
+}
+
+// isSuperuserRole checks if the passed role is a superuser, returning
+// false when the role doesn't exist
+func isSuperuserRole(ctx context.Context, db *sql.DB, roleName string) (bool, error) {
+	var isSuperuser bool
+	row := db.QueryRowContext(ctx,
+		"SELECT rolsuper FROM pg_catalog.pg_roles WHERE rolname = $1",
+		roleName)
+	err := row.Scan(&isSuperuser)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("while checking the role %s: %w", roleName, err)
+	}
+	return isSuperuser, nil
+}
+
+// getScheduledJobs gets the jobs scheduled in pg_cron by the operator,
+// indexed by name
+func getScheduledJobs(ctx context.Context, db *sql.DB) (map[string]scheduledJob, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT jobid, jobname, schedule, command, database, username, active
+		FROM cron.job
+		WHERE left(jobname, length($1)) = $1`,
+		apiv1.PgCronJobNamePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("while listing the pg_cron jobs: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	jobs := make(map[string]scheduledJob)
+	for rows.Next() {
+		var job scheduledJob
+		if err := rows.Scan(&job.id, &job.name, &job.schedule, &job.command,
+			&job.database, &job.username, &job.active); err != nil {
+			return nil, err
+		}
+		jobs[job.name] = job
+	}
+
+	return jobs, rows.Err()
+}
+
+// scheduleJob schedules a new job in pg_cron, returning its identifier
+func scheduleJob(ctx context.Context, db *sql.DB, job scheduledJob) (int64, error) {
+	var id int64
+	row := db.QueryRowContext(ctx,
+		"SELECT cron.schedule_in_database($1, $2, $3, $4, $5, $6)",
+		job.name, job.schedule, job.command, job.database, job.username, job.active)
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("while scheduling the job: %w", err)
+	}
+	return id, nil
+}
+
+// alterJob updates the definition of a job scheduled in pg_cron
+func alterJob(ctx context.Context, db *sql.DB, job scheduledJob) error {
+	if _, err := db.ExecContext(ctx,
+		"SELECT cron.alter_job($1, $2, $3, $4, $5, $6)",
+		job.id, job.schedule, job.command, job.database, job.username, job.active); err != nil {
+		return fmt.Errorf("while updating the job: %w", err)
+	}
+	return nil
+}
+
+// getLastRun gets the latest run of a job, if pg_cron recorded any
+func getLastRun(ctx context.Context, db *sql.DB, jobID int64) (*apiv1.PgCronJobRun, error) {
+	var (
+		run           apiv1.PgCronJobRun
+		returnMessage sql.NullString
+		startTime     sql.NullTime
+		endTime       sql.NullTime
+	)
+	row := db.QueryRowContext(ctx,
+		`SELECT runid, status, return_message, start_time, end_time
+		FROM cron.job_run_details
+		WHERE jobid = $1
+		ORDER BY runid DESC
+		LIMIT 1`,
+		jobID)
+	err := row.Scan(&run.RunID, &run.Status, &returnMessage, &startTime, &endTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while getting the last run of the pg_cron job %d: %w", jobID, err)
+	}
+
+	run.ReturnMessage = returnMessage.String
+	run.StartTime = toMetaTime(startTime)
+	run.EndTime = toMetaTime(endTime)
+	return &run, nil
+}
+
+// toMetaTime converts a nullable timestamp, truncating it to the second
+// as it is serialized in the status
+func toMetaTime(value sql.NullTime) *metav1.Time {
+	if !value.Valid {
+		return nil
+	}
+	result := metav1.NewTime(value.Time.Truncate(time.Second))
+	return &result
+}
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjobs
+
+import (
+	"database/sql"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_cron jobs", func() {
+	job := apiv1.PgCronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "vacuum-orders"},
+		Spec: apiv1.PgCronJobSpec{
+			Cluster:  apiv1.LocalObjectReference{Name: "cluster-example"},
+			Schedule: "0 3 * * *",
+			Command:  "VACUUM orders",
+			Username: "maintenance",
+		},
+	}
+
+	It("defaults the database of the job", func() {
+		Expect(newScheduledJob(job, "postgres")).To(Equal(scheduledJob{
+			name:     "cnpg_vacuum-orders",
+			schedule: "0 3 * * *",
+			command:  "VACUUM orders",
+			database: "postgres",
+			username: "maintenance",
+			active:   true,
+		}))
+	})
+
+	It("uses the requested database and role", func() {
+		customJob := job.DeepCopy()
+		customJob.Spec.Database = "app"
+		customJob.Spec.Username = "app"
+		customJob.Spec.Suspend = true
+
+		scheduled := newScheduledJob(*customJob, "postgres")
+		Expect(scheduled.database).To(Equal("app"))
+		Expect(scheduled.username).To(Equal("app"))
+		Expect(scheduled.active).To(BeFalse())
+	})
+
+	It("detects the changes of the scheduled jobs ignoring their identifier", func() {
+		current := newScheduledJob(job, "postgres")
+		current.id = 42
+		Expect(current.matches(newScheduledJob(job, "postgres"))).To(BeTrue())
+
+		changedJob := job.DeepCopy()
+		changedJob.Spec.Schedule = "30 seconds"
+		Expect(current.matches(newScheduledJob(*changedJob, "postgres"))).To(BeFalse())
+	})
+
+	It("finds the pg_cron managed extension", func() {
+		extension := getPgCronExtension()
+		Expect(extension.SharedPreloadLibraries).To(ConsistOf("pg_cron"))
+		Expect(extension.GetDatabase(nil)).To(Equal("postgres"))
+	})
+
+	It("converts the nullable timestamps", func() {
+		Expect(toMetaTime(sql.NullTime{})).To(BeNil())
+
+		timestamp := time.Date(2022, 11, 10, 3, 0, 0, 500, time.UTC)
+		result := toMetaTime(sql.NullTime{Time: timestamp, Valid: true})
+		Expect(result).ToNot(BeNil())
+		Expect(result.Time.Equal(timestamp.Truncate(time.Second))).To(BeTrue())
+	})
+})
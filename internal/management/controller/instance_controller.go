@@ -111,6 +111,9 @@ func (r *InstanceReconciler) Reconcile(
 	// Reconcile PostgreSQL instance parameters
 	r.reconcileInstance(cluster)
 
+	// Apply the logging configuration, this doesn't require a restart
+	reconcileLogging(cluster)
+
 	// Takes care of the `.check-empty-wal-archive` file inside the PGDATA
 	// which, if present, before running the WAL archiver verifies that
 	// the backup object store is empty. This file is created immediately
@@ -217,10 +220,24 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("while updating database owner password: %w", err)
 	}
 
+	if err = r.reconcileSCRAMMigration(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while reconciling the SCRAM migration: %w", err)
+	}
+
+	if err = r.reconcileManagedDatabases(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while reconciling managed databases: %w", err)
+	}
+
 	if err := r.reconcileDatabases(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	followingOrigin, err := r.reconcileOnlineImport(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("while reconciling the online import: %w", err)
+	}
+	requeue = requeue || shoudRequeue(followingOrigin)
+
 	// Extremely important.
 	// It could happen that current primary is reconciled before all the topology is extracted by the operator.
 	// We should detect that and schedule the instance manager for another run otherwise we will end up having
@@ -230,6 +247,10 @@ func (r *InstanceReconciler) Reconcile(
 		requeue = r.shouldRequeueForMissingTopology(cluster)
 	}
 
+	// The credentials files are not watched, so we read them again
+	// periodically to apply the passwords rotated by the secret store
+	requeue = requeue || shoudRequeue(cluster.UsesCredentialsFiles())
+
 	if requeue {
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
@@ -280,6 +301,12 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
+	reloadIdent, err := r.instance.RefreshPGIdent(cluster)
+	if err != nil {
+		return false, err
+	}
+	reloadNeeded = reloadNeeded || reloadIdent
+
 	// Reconcile PostgreSQL configuration
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(cluster)
@@ -293,6 +320,18 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 	reloadNeeded = reloadNeeded || reloadReplicaConfig
+
+	// The replica configuration may have replaced the postgresql.auto.conf
+	// file, so its permissions need to be enforced after that
+	changed, err := postgresManagement.SetAlterSystemEnabled(r.instance.PgData, cluster.IsAlterSystemEnabled())
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		log.FromContext(ctx).Info("Updated the permissions of the postgresql.auto.conf file",
+			"alterSystemEnabled", cluster.IsAlterSystemEnabled())
+	}
+
 	return reloadNeeded, nil
 }
 
@@ -418,6 +457,17 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 		return false, err
 	}
 
+	// During a switchover the primary is healthy, and it can be drained
+	// before being shut down to reduce the errors seen by the clients
+	if cluster.Status.Phase == apiv1.PhaseSwitchover && cluster.Spec.SwitchoverDrain != nil {
+		timeout := cluster.Spec.SwitchoverDrain.GetTimeout()
+		contextLogger.Info("This is an old primary node. Draining the connections before demotion",
+			"timeout", timeout.String())
+		if err := r.instance.DrainConnections(ctx, timeout); err != nil {
+			contextLogger.Error(err, "Error while draining the connections, proceeding with the demotion")
+		}
+	}
+
 	contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion")
 
 	db, err := r.instance.GetSuperUserDB()
@@ -479,7 +529,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 
 	extensionStatusChanged := false
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := cluster.IsManagedExtensionUsed(extension)
 		if lastStatus, ok := r.extensionStatus[extension.Name]; !ok || lastStatus != extensionIsUsed {
 			extensionStatusChanged = true
 			break
@@ -495,7 +545,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 			continue
 		}
 		if extensionStatusChanged {
-			if err = r.reconcileExtensions(ctx, db, cluster.Spec.PostgresConfiguration.Parameters); err != nil {
+			if err = r.reconcileExtensions(ctx, db, databaseName, cluster); err != nil {
 				errors = append(errors,
 					fmt.Errorf("could not reconcile extensions for database %s: %w", databaseName, err))
 			}
@@ -510,7 +560,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 	}
 
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := cluster.IsManagedExtensionUsed(extension)
 		r.extensionStatus[extension.Name] = extensionIsUsed
 	}
 
@@ -543,7 +593,7 @@ func (r *InstanceReconciler) getAllAccessibleDatabases(
 // ReconcileExtensions reconciles the expected extensions for this
 // PostgreSQL instance
 func (r *InstanceReconciler) reconcileExtensions(
-	ctx context.Context, db *sql.DB, userSettings map[string]string,
+	ctx context.Context, db *sql.DB, databaseName string, cluster *apiv1.Cluster,
 ) (err error) {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -560,7 +610,13 @@ func (r *InstanceReconciler) reconcileExtensions(
 	}
 
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(userSettings)
+		extensionIsUsed := cluster.IsManagedExtensionUsed(extension)
+
+		// Some extensions can only be created in one database
+		extensionDatabase := extension.GetDatabase(cluster.Spec.PostgresConfiguration.Parameters)
+		if extensionDatabase != "" && extensionDatabase != databaseName {
+			extensionIsUsed = false
+		}
 
 		row := tx.QueryRow("SELECT COUNT(*) > 0 FROM pg_extension WHERE extname = $1", extension.Name)
 		err = row.Err()
@@ -694,6 +750,13 @@ func (r *InstanceReconciler) reconcileClusterRoleWithoutDB(
 	return false, nil
 }
 
+// reconcileLogging applies the static fields and the logger
+// levels to the logging subsystem of the instance manager
+func reconcileLogging(cluster *apiv1.Cluster) {
+	log.SetFields(cluster.Spec.Logging.GetFields())
+	log.SetLoggerLevels(cluster.Spec.Logging.GetLoggerLevels())
+}
+
 // reconcileMetrics updates any required metrics
 func (r *InstanceReconciler) reconcileMetrics(
 	cluster *apiv1.Cluster,
@@ -875,6 +938,7 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.PgCtlTimeoutForPromotion = cluster.GetPgCtlTimeoutForPromotion()
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
+	r.instance.SmartShutdownTimeout = cluster.GetSmartShutdownTimeout()
 }
 
 func (r *InstanceReconciler) reconcileCheckWalArchiveFile(cluster *apiv1.Cluster) error {
@@ -1202,7 +1266,21 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 		return err
 	}
 
-	if cluster.GetEnableSuperuserAccess() {
+	// Hash the passwords with the enforced method even if the new
+	// configuration has not been applied yet
+	if passwordEncryption := cluster.GetPasswordEncryption(); passwordEncryption != "" {
+		_, err = tx.Exec(fmt.Sprintf("SET LOCAL password_encryption TO %s", pq.QuoteLiteral(passwordEncryption)))
+		if err != nil {
+			return err
+		}
+	}
+
+	if passwordFile := cluster.GetSuperuserPasswordFile(); passwordFile != "" && cluster.GetEnableSuperuserAccess() {
+		err = r.reconcileUserFromFile(ctx, "postgres", passwordFile, tx)
+		if err != nil {
+			return err
+		}
+	} else if cluster.GetEnableSuperuserAccess() {
 		err = r.reconcileUser(ctx, "postgres", cluster.GetSuperuserSecretName(), tx)
 		if err != nil {
 			return err
@@ -1212,10 +1290,15 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 		if err != nil {
 			return err
 		}
+		forgetSuperuserPassword(cluster, r.secretVersions)
 	}
 
 	if cluster.ShouldCreateApplicationDatabase() {
-		err = r.reconcileUser(ctx, cluster.GetApplicationDatabaseOwner(), cluster.GetApplicationSecretName(), tx)
+		if passwordFile := cluster.GetApplicationPasswordFile(); passwordFile != "" {
+			err = r.reconcileUserFromFile(ctx, cluster.GetApplicationDatabaseOwner(), passwordFile, tx)
+		} else {
+			err = r.reconcileUser(ctx, cluster.GetApplicationDatabaseOwner(), cluster.GetApplicationSecretName(), tx)
+		}
 		if err != nil {
 			return err
 		}
@@ -1267,6 +1350,16 @@ func (r *InstanceReconciler) disableSuperuserPassword(tx *sql.Tx) error {
 	return err
 }
 
+// forgetSuperuserPassword removes the versions of the superuser password
+// that have already been applied, so the password is set again when the
+// superuser access is enabled back, even if its secret didn't change
+func forgetSuperuserPassword(cluster *apiv1.Cluster, secretVersions map[string]string) {
+	delete(secretVersions, cluster.GetSuperuserSecretName())
+	if passwordFile := cluster.GetSuperuserPasswordFile(); passwordFile != "" {
+		delete(secretVersions, passwordFile)
+	}
+}
+
 func (r *InstanceReconciler) refreshPGHBA(ctx context.Context, cluster *apiv1.Cluster) (
 	postgresHBAChanged bool,
 	err error,
@@ -0,0 +1,176 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// listDatabaseParameters are the configuration parameters whose value is
+// a list, which is set as a list of literals rather than as a single one
+var listDatabaseParameters = stringset.From([]string{
+	"local_preload_libraries",
+	"search_path",
+	"session_preload_libraries",
+	"temp_tablespaces",
+})
+
+// reconcileManagedDatabaseSettings enforces the connection limit and the
+// configuration parameters of a managed database, correcting any drift.
+// The passed connection can be established with any database
+func reconcileManagedDatabaseSettings(
+	ctx context.Context,
+	db *sql.DB,
+	database apiv1.ManagedDatabase,
+) error {
+	if database.ConnectionLimit == nil && len(database.Parameters) == 0 {
+		return nil
+	}
+
+	var connectionLimit int32
+	var settings []string
+	row := db.QueryRowContext(ctx,
+		"SELECT d.datconnlimit, COALESCE(s.setconfig, '{}') FROM pg_catalog.pg_database d "+
+			"LEFT JOIN pg_catalog.pg_db_role_setting s ON s.setdatabase = d.oid AND s.setrole = 0 "+
+			"WHERE d.datname = $1",
+		database.Name)
+	if err := row.Scan(&connectionLimit, pq.Array(&settings)); err != nil {
+		return err
+	}
+
+	for _, statement := range buildDatabaseSettingsStatements(
+		database, connectionLimit, parseDatabaseSettings(settings)) {
+		log.FromContext(ctx).Info("Reconciling the settings of a managed database",
+			"database", database.Name, "statement", statement)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseDatabaseSettings parses the configuration parameters set on a
+// database, as stored in pg_db_role_setting
+func parseDatabaseSettings(settings []string) map[string]string {
+	result := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		name, value, _ := strings.Cut(setting, "=")
+		result[strings.ToLower(name)] = value
+	}
+	return result
+}
+
+// buildDatabaseSettingsStatements builds the statements needed to move
+// the connection limit and the configuration parameters of a database
+// from the current values to the desired ones
+func buildDatabaseSettingsStatements(
+	database apiv1.ManagedDatabase,
+	connectionLimit int32,
+	current map[string]string,
+) []string {
+	name := pgx.Identifier{database.Name}.Sanitize()
+
+	var statements []string
+	if database.ConnectionLimit != nil && *database.ConnectionLimit != connectionLimit {
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT %d",
+			name, *database.ConnectionLimit))
+	}
+
+	if len(database.Parameters) == 0 {
+		return statements
+	}
+
+	desired := make(map[string]string, len(database.Parameters))
+	for parameter, value := range database.Parameters {
+		desired[strings.ToLower(parameter)] = value
+	}
+
+	for _, parameter := range sortedParameterNames(desired) {
+		value, isSet := current[parameter]
+		if isSet && normalizeParameterValue(parameter, value) ==
+			normalizeParameterValue(parameter, desired[parameter]) {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE %s SET %s TO %s",
+			name, parameter, formatParameterValue(parameter, desired[parameter])))
+	}
+
+	for _, parameter := range sortedParameterNames(current) {
+		if _, isDesired := desired[parameter]; !isDesired {
+			statements = append(statements, fmt.Sprintf("ALTER DATABASE %s RESET %s", name, parameter))
+		}
+	}
+
+	return statements
+}
+
+// formatParameterValue formats the value of a configuration parameter
+// to be used in an ALTER DATABASE ... SET statement
+func formatParameterValue(parameter, value string) string {
+	if !listDatabaseParameters.Has(parameter) {
+		return pq.QuoteLiteral(value)
+	}
+
+	items := splitParameterValue(value)
+	for idx := range items {
+		items[idx] = pq.QuoteLiteral(items[idx])
+	}
+	return strings.Join(items, ", ")
+}
+
+// normalizeParameterValue normalizes the value of a configuration
+// parameter, so that equivalent lists are compared as equal
+func normalizeParameterValue(parameter, value string) string {
+	if !listDatabaseParameters.Has(parameter) {
+		return value
+	}
+	return strings.Join(splitParameterValue(value), ", ")
+}
+
+// splitParameterValue splits the value of a list parameter into its
+// items, removing the quotes around them
+func splitParameterValue(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.Trim(strings.TrimSpace(item), `"`); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sortedKeys gets the keys of the passed map in alphabetical order
+func sortedParameterNames(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
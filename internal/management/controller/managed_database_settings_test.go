@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed database settings", func() {
+	It("sets the connection limit when it differs", func() {
+		database := apiv1.ManagedDatabase{Name: "app", ConnectionLimit: pointer.Int32(50)}
+		Expect(buildDatabaseSettingsStatements(database, -1, nil)).
+			To(Equal([]string{`ALTER DATABASE "app" CONNECTION LIMIT 50`}))
+		Expect(buildDatabaseSettingsStatements(database, 50, nil)).To(BeEmpty())
+		Expect(buildDatabaseSettingsStatements(apiv1.ManagedDatabase{Name: "app"}, 50, nil)).To(BeEmpty())
+	})
+
+	It("sets the parameters which are missing or different, resetting the others", func() {
+		database := apiv1.ManagedDatabase{
+			Name: "app",
+			Parameters: map[string]string{
+				"statement_timeout":             "30s",
+				"default_transaction_read_only": "on",
+				"search_path":                   "app, public",
+			},
+		}
+		current := parseDatabaseSettings([]string{
+			"statement_timeout=10s",
+			"search_path=app, public",
+			"work_mem=64MB",
+		})
+
+		Expect(buildDatabaseSettingsStatements(database, -1, current)).To(Equal([]string{
+			`ALTER DATABASE "app" SET default_transaction_read_only TO 'on'`,
+			`ALTER DATABASE "app" SET statement_timeout TO '30s'`,
+			`ALTER DATABASE "app" RESET work_mem`,
+		}))
+	})
+
+	It("sets the list parameters as lists of literals", func() {
+		Expect(formatParameterValue("search_path", `"$user", public`)).To(Equal(`'$user', 'public'`))
+		Expect(formatParameterValue("statement_timeout", "1min")).To(Equal(`'1min'`))
+		Expect(normalizeParameterValue("search_path", `"$user",public`)).
+			To(Equal(normalizeParameterValue("search_path", "$user, public")))
+	})
+})
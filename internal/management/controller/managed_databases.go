@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileManagedDatabases creates, on the primary, the managed databases
+// and their owners when missing, applies to the owners of the exposed
+// databases the passwords contained in their secrets and reconciles the
+// settings of the databases, the extensions installed in them, their
+// foreign data wrappers and servers, and the privileges granted on them
+func (r *InstanceReconciler) reconcileManagedDatabases(ctx context.Context, cluster *apiv1.Cluster) error {
+	databases := cluster.GetManagedDatabases()
+	if len(databases) == 0 {
+		return nil
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !primary {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	if err := r.reconcileManagedDatabaseOwners(ctx, db, cluster); err != nil {
+		return err
+	}
+
+	for _, database := range databases {
+		if err := createDatabaseIfNotExists(ctx, db, database); err != nil {
+			return fmt.Errorf("while creating database %s: %w", database.Name, err)
+		}
+		if err := reconcileManagedDatabaseSettings(ctx, db, database); err != nil {
+			return fmt.Errorf("while reconciling the settings of database %s: %w", database.Name, err)
+		}
+	}
+
+	for _, database := range databases {
+		if len(database.Grants) == 0 && len(database.Extensions) == 0 &&
+			len(database.ForeignDataWrappers) == 0 && len(database.ForeignServers) == 0 {
+			continue
+		}
+
+		databaseDB, err := r.instance.ConnectionPool().Connection(database.Name)
+		if err != nil {
+			return fmt.Errorf("while connecting to database %s: %w", database.Name, err)
+		}
+		if err := reconcileManagedDatabaseExtensions(ctx, databaseDB, database); err != nil {
+			return fmt.Errorf("while reconciling the extensions of database %s: %w", database.Name, err)
+		}
+		if err := r.reconcileManagedForeignData(ctx, databaseDB, database); err != nil {
+			return fmt.Errorf("while reconciling the foreign data of database %s: %w", database.Name, err)
+		}
+		if err := reconcileManagedGrants(ctx, databaseDB, database); err != nil {
+			return fmt.Errorf("while reconciling the grants on database %s: %w", database.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileManagedDatabaseOwners creates the missing owners of the managed
+// databases, setting the password of the ones of the exposed databases
+func (r *InstanceReconciler) reconcileManagedDatabaseOwners(
+	ctx context.Context,
+	db *sql.DB,
+	cluster *apiv1.Cluster,
+) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This has no effect if the transaction
+		// is committed
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, "SET LOCAL synchronous_commit to LOCAL")
+	if err != nil {
+		return err
+	}
+
+	for _, database := range cluster.GetManagedDatabases() {
+		owner := database.GetOwner()
+		if err := createRoleIfNotExists(ctx, tx, owner); err != nil {
+			return fmt.Errorf("while creating role %s: %w", owner, err)
+		}
+
+		if !database.Expose {
+			continue
+		}
+		err := r.reconcileUser(ctx, owner, cluster.GetDatabaseResourcesName(database.Name), tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// createRoleIfNotExists creates a role, with the LOGIN
+// attribute, unless it already exists
+func createRoleIfNotExists(ctx context.Context, tx *sql.Tx, roleName string) error {
+	var exists bool
+	row := tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1)",
+		roleName)
+	if err := row.Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Creating the owner of a managed database", "role", roleName)
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE ROLE %s LOGIN", pgx.Identifier{roleName}.Sanitize()))
+	return err
+}
+
+// createDatabaseIfNotExists creates a managed database,
+// unless it already exists
+func createDatabaseIfNotExists(ctx context.Context, db *sql.DB, database apiv1.ManagedDatabase) error {
+	var exists bool
+	row := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_database WHERE datname = $1)",
+		database.Name)
+	if err := row.Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Creating a managed database",
+		"database", database.Name, "owner", database.GetOwner())
+	_, err := db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %s OWNER %s",
+		pgx.Identifier{database.Name}.Sanitize(),
+		pgx.Identifier{database.GetOwner()}.Sanitize()))
+	return err
+}
@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// installedExtension is an extension installed in a database
+type installedExtension struct {
+	version string
+	schema  string
+}
+
+// reconcileManagedDatabaseExtensions creates, updates and drops the
+// extensions of a managed database. The extensions requiring libraries
+// which are not loaded yet are skipped, as the instance needs to be
+// restarted first. The passed connection must be established with the
+// managed database
+func reconcileManagedDatabaseExtensions(
+	ctx context.Context,
+	db *sql.DB,
+	database apiv1.ManagedDatabase,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("database", database.Name)
+
+	var preloadedLibraries string
+	if err := db.QueryRowContext(ctx,
+		"SELECT pg_catalog.current_setting('shared_preload_libraries')").Scan(&preloadedLibraries); err != nil {
+		return err
+	}
+
+	for _, extension := range database.Extensions {
+		installed, err := getInstalledExtension(ctx, db, extension.Name)
+		if err != nil {
+			return err
+		}
+
+		if extension.IsPresent() && installed == nil {
+			if missing := getMissingLibraries(extension.SharedPreloadLibraries, preloadedLibraries); len(missing) > 0 {
+				contextLogger.Info("Waiting for the instance to be restarted before creating the extension",
+					"extension", extension.Name, "missingLibraries", missing)
+				continue
+			}
+		}
+
+		for _, statement := range buildExtensionStatements(extension, installed) {
+			contextLogger.Info("Reconciling a managed extension",
+				"extension", extension.Name, "statement", statement)
+			if _, err := db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("while reconciling extension %s: %w", extension.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getInstalledExtension gets the version and the schema of an
+// extension, returning nil when it is not installed
+func getInstalledExtension(ctx context.Context, db *sql.DB, name string) (*installedExtension, error) {
+	var extension installedExtension
+	err := db.QueryRowContext(ctx,
+		"SELECT e.extversion, n.nspname FROM pg_catalog.pg_extension e "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace "+
+			"WHERE e.extname = $1",
+		name).Scan(&extension.version, &extension.schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &extension, nil
+}
+
+// getMissingLibraries gets the libraries which are not contained in
+// the passed value of shared_preload_libraries
+func getMissingLibraries(libraries []string, preloadedLibraries string) []string {
+	preloaded := make(map[string]bool)
+	for _, library := range strings.Split(preloadedLibraries, ",") {
+		preloaded[strings.Trim(strings.TrimSpace(library), `"`)] = true
+	}
+
+	var missing []string
+	for _, library := range libraries {
+		if !preloaded[library] {
+			missing = append(missing, library)
+		}
+	}
+	return missing
+}
+
+// buildExtensionStatements builds the statements needed to move an
+// extension from its installed state, nil when it is not installed,
+// to the desired one
+func buildExtensionStatements(extension apiv1.ManagedDatabaseExtension, installed *installedExtension) []string {
+	name := pgx.Identifier{extension.Name}.Sanitize()
+
+	if !extension.IsPresent() {
+		if installed == nil {
+			return nil
+		}
+		return []string{fmt.Sprintf("DROP EXTENSION %s", name)}
+	}
+
+	if installed == nil {
+		statement := fmt.Sprintf("CREATE EXTENSION %s", name)
+		if extension.Schema != "" {
+			statement += fmt.Sprintf(" SCHEMA %s", pgx.Identifier{extension.Schema}.Sanitize())
+		}
+		if extension.Version != "" {
+			statement += fmt.Sprintf(" VERSION %s", pgx.Identifier{extension.Version}.Sanitize())
+		}
+		return []string{statement}
+	}
+
+	var statements []string
+	if extension.Version != "" && extension.Version != installed.version {
+		statements = append(statements, fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s",
+			name, pgx.Identifier{extension.Version}.Sanitize()))
+	}
+	if extension.Schema != "" && extension.Schema != installed.schema {
+		statements = append(statements, fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s",
+			name, pgx.Identifier{extension.Schema}.Sanitize()))
+	}
+	return statements
+}
@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed extensions", func() {
+	It("creates a missing extension", func() {
+		Expect(buildExtensionStatements(apiv1.ManagedDatabaseExtension{Name: "hstore"}, nil)).
+			To(Equal([]string{`CREATE EXTENSION "hstore"`}))
+		Expect(buildExtensionStatements(
+			apiv1.ManagedDatabaseExtension{Name: "postgis", Schema: "gis", Version: "3.3.2"}, nil)).
+			To(Equal([]string{`CREATE EXTENSION "postgis" SCHEMA "gis" VERSION "3.3.2"`}))
+	})
+
+	It("updates an extension installed with a different version or schema", func() {
+		installed := &installedExtension{version: "3.3.1", schema: "public"}
+		Expect(buildExtensionStatements(
+			apiv1.ManagedDatabaseExtension{Name: "postgis", Schema: "gis", Version: "3.3.2"}, installed)).
+			To(Equal([]string{
+				`ALTER EXTENSION "postgis" UPDATE TO "3.3.2"`,
+				`ALTER EXTENSION "postgis" SET SCHEMA "gis"`,
+			}))
+		Expect(buildExtensionStatements(apiv1.ManagedDatabaseExtension{Name: "postgis"}, installed)).
+			To(BeEmpty())
+	})
+
+	It("drops an extension which must be absent", func() {
+		absent := apiv1.ManagedDatabaseExtension{Name: "hstore", Ensure: apiv1.EnsureAbsent}
+		Expect(buildExtensionStatements(absent, &installedExtension{version: "1.8", schema: "public"})).
+			To(Equal([]string{`DROP EXTENSION "hstore"`}))
+		Expect(buildExtensionStatements(absent, nil)).To(BeEmpty())
+	})
+
+	It("detects the libraries which are not loaded yet", func() {
+		Expect(getMissingLibraries([]string{"pg_cron", "timescaledb"}, `pgaudit, "pg_cron"`)).
+			To(Equal([]string{"timescaledb"}))
+		Expect(getMissingLibraries([]string{"pg_cron"}, "")).To(Equal([]string{"pg_cron"}))
+		Expect(getMissingLibraries(nil, "pgaudit")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,440 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileManagedForeignData creates, updates and drops the foreign data
+// wrappers, the foreign servers and the user mappings of a managed
+// database. The passed connection must be established with the managed
+// database
+func (r *InstanceReconciler) reconcileManagedForeignData(
+	ctx context.Context,
+	db *sql.DB,
+	database apiv1.ManagedDatabase,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("database", database.Name)
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	for _, wrapper := range database.ForeignDataWrappers {
+		if !wrapper.IsPresent() {
+			continue
+		}
+		if err := reconcileForeignDataWrapper(ctx, db, wrapper); err != nil {
+			return fmt.Errorf("while reconciling foreign data wrapper %s: %w", wrapper.Name, err)
+		}
+	}
+
+	for _, server := range database.ForeignServers {
+		if !server.IsPresent() {
+			if err := dropForeignServer(ctx, db, server.Name); err != nil {
+				return fmt.Errorf("while dropping foreign server %s: %w", server.Name, err)
+			}
+			continue
+		}
+
+		if err := reconcileForeignServer(ctx, db, server, database.GetOwner()); err != nil {
+			return fmt.Errorf("while reconciling foreign server %s: %w", server.Name, err)
+		}
+		for _, mapping := range server.UserMappings {
+			if err := r.reconcileUserMapping(ctx, db, server.Name, mapping); err != nil {
+				return fmt.Errorf("while reconciling the user mapping for %s on foreign server %s: %w",
+					mapping.User, server.Name, err)
+			}
+		}
+	}
+
+	// The foreign data wrappers are dropped once their servers are gone
+	for _, wrapper := range database.ForeignDataWrappers {
+		if wrapper.IsPresent() {
+			continue
+		}
+		if err := dropForeignDataWrapper(ctx, db, wrapper.Name); err != nil {
+			return fmt.Errorf("while dropping foreign data wrapper %s: %w", wrapper.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileForeignDataWrapper creates a foreign data wrapper, or aligns
+// the options of an existing one
+func reconcileForeignDataWrapper(ctx context.Context, db *sql.DB, wrapper apiv1.ManagedForeignDataWrapper) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_foreign_data_wrapper WHERE fdwname = $1)",
+		wrapper.Name).Scan(&exists); err != nil {
+		return err
+	}
+
+	name := pgx.Identifier{wrapper.Name}.Sanitize()
+	var statement string
+	if !exists {
+		statement = fmt.Sprintf("CREATE FOREIGN DATA WRAPPER %s", name)
+		if wrapper.Handler != "" {
+			statement += fmt.Sprintf(" HANDLER %s", pgx.Identifier{wrapper.Handler}.Sanitize())
+		}
+		if wrapper.Validator != "" {
+			statement += fmt.Sprintf(" VALIDATOR %s", pgx.Identifier{wrapper.Validator}.Sanitize())
+		}
+		statement += buildOptionsClause(wrapper.Options)
+	} else {
+		current, err := getObjectOptions(ctx, db,
+			"SELECT o.option_name, o.option_value "+
+				"FROM pg_catalog.pg_foreign_data_wrapper w, pg_catalog.pg_options_to_table(w.fdwoptions) o "+
+				"WHERE w.fdwname = $1",
+			wrapper.Name)
+		if err != nil {
+			return err
+		}
+		clause := buildAlterOptionsClause(current, wrapper.Options)
+		if clause == "" {
+			return nil
+		}
+		statement = fmt.Sprintf("ALTER FOREIGN DATA WRAPPER %s %s", name, clause)
+	}
+
+	log.FromContext(ctx).Info("Reconciling a managed foreign data wrapper", "foreignDataWrapper", wrapper.Name)
+	_, err := db.ExecContext(ctx, statement)
+	return err
+}
+
+// dropForeignDataWrapper drops a foreign data wrapper, if it exists.
+// This fails if other objects depend on it
+func dropForeignDataWrapper(ctx context.Context, db *sql.DB, name string) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_foreign_data_wrapper WHERE fdwname = $1)",
+		name).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Dropping a managed foreign data wrapper", "foreignDataWrapper", name)
+	_, err := db.ExecContext(ctx, fmt.Sprintf("DROP FOREIGN DATA WRAPPER %s", pgx.Identifier{name}.Sanitize()))
+	return err
+}
+
+// reconcileForeignServer creates a foreign server, or aligns the owner
+// and the options of an existing one
+func reconcileForeignServer(
+	ctx context.Context,
+	db *sql.DB,
+	server apiv1.ManagedForeignServer,
+	owner string,
+) error {
+	var wrapper, currentOwner string
+	err := db.QueryRowContext(ctx,
+		"SELECT w.fdwname, pg_catalog.pg_get_userbyid(s.srvowner) "+
+			"FROM pg_catalog.pg_foreign_server s "+
+			"JOIN pg_catalog.pg_foreign_data_wrapper w ON w.oid = s.srvfdw "+
+			"WHERE s.srvname = $1",
+		server.Name).Scan(&wrapper, &currentOwner)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	name := pgx.Identifier{server.Name}.Sanitize()
+	var statements []string
+	if errors.Is(err, sql.ErrNoRows) {
+		statements = append(statements,
+			fmt.Sprintf("CREATE SERVER %s FOREIGN DATA WRAPPER %s%s",
+				name, pgx.Identifier{server.ForeignDataWrapper}.Sanitize(), buildOptionsClause(server.Options)),
+			fmt.Sprintf("ALTER SERVER %s OWNER TO %s", name, pgx.Identifier{owner}.Sanitize()))
+	} else {
+		if wrapper != server.ForeignDataWrapper {
+			return fmt.Errorf("the server uses the %s foreign data wrapper, and can't be moved to %s",
+				wrapper, server.ForeignDataWrapper)
+		}
+
+		current, err := getObjectOptions(ctx, db,
+			"SELECT o.option_name, o.option_value "+
+				"FROM pg_catalog.pg_foreign_server s, pg_catalog.pg_options_to_table(s.srvoptions) o "+
+				"WHERE s.srvname = $1",
+			server.Name)
+		if err != nil {
+			return err
+		}
+		if clause := buildAlterOptionsClause(current, server.Options); clause != "" {
+			statements = append(statements, fmt.Sprintf("ALTER SERVER %s %s", name, clause))
+		}
+		if currentOwner != owner {
+			statements = append(statements,
+				fmt.Sprintf("ALTER SERVER %s OWNER TO %s", name, pgx.Identifier{owner}.Sanitize()))
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Reconciling a managed foreign server", "foreignServer", server.Name)
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropForeignServer drops a foreign server together with its user
+// mappings, if it exists. This fails if other objects, like foreign
+// tables, depend on it
+func dropForeignServer(ctx context.Context, db *sql.DB, name string) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_foreign_server WHERE srvname = $1)",
+		name).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT usename FROM pg_catalog.pg_user_mappings WHERE srvname = $1", name)
+	if err != nil {
+		return err
+	}
+	var users []string
+	for rows.Next() {
+		var user string
+		if err := rows.Scan(&user); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		users = append(users, user)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This has no effect if the transaction
+		// is committed
+		_ = tx.Rollback()
+	}()
+
+	log.FromContext(ctx).Info("Dropping a managed foreign server", "foreignServer", name)
+	for _, user := range users {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP USER MAPPING FOR %s SERVER %s",
+			getUserMappingRole(user), pgx.Identifier{name}.Sanitize())); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP SERVER %s", pgx.Identifier{name}.Sanitize())); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// reconcileUserMapping creates, updates or drops the user mapping of a
+// role on a foreign server
+func (r *InstanceReconciler) reconcileUserMapping(
+	ctx context.Context,
+	db *sql.DB,
+	serverName string,
+	mapping apiv1.ManagedUserMapping,
+) error {
+	user := mapping.User
+	if mapping.IsPublic() {
+		user = "public"
+	}
+
+	var exists bool
+	if err := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_user_mappings WHERE srvname = $1 AND usename = $2)",
+		serverName, user).Scan(&exists); err != nil {
+		return err
+	}
+
+	target := fmt.Sprintf("FOR %s SERVER %s", getUserMappingRole(user), pgx.Identifier{serverName}.Sanitize())
+	var statement string
+	switch {
+	case !mapping.IsPresent() && !exists:
+		return nil
+	case !mapping.IsPresent():
+		statement = fmt.Sprintf("DROP USER MAPPING %s", target)
+	default:
+		options, err := r.getUserMappingOptions(ctx, mapping)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			statement = fmt.Sprintf("CREATE USER MAPPING %s%s", target, buildOptionsClause(options))
+			break
+		}
+
+		current, err := getObjectOptions(ctx, db,
+			"SELECT o.option_name, o.option_value "+
+				"FROM pg_catalog.pg_user_mappings m, pg_catalog.pg_options_to_table(m.umoptions) o "+
+				"WHERE m.srvname = $1 AND m.usename = $2",
+			serverName, user)
+		if err != nil {
+			return err
+		}
+		clause := buildAlterOptionsClause(current, options)
+		if clause == "" {
+			return nil
+		}
+		statement = fmt.Sprintf("ALTER USER MAPPING %s %s", target, clause)
+	}
+
+	// The statement is not logged, as it may contain a password
+	log.FromContext(ctx).Info("Reconciling a managed user mapping",
+		"foreignServer", serverName, "user", mapping.User, "ensure", mapping.Ensure)
+	_, err := db.ExecContext(ctx, statement)
+	return err
+}
+
+// getUserMappingOptions gets the options of a user mapping, including the
+// credentials contained in its Secret
+func (r *InstanceReconciler) getUserMappingOptions(
+	ctx context.Context,
+	mapping apiv1.ManagedUserMapping,
+) (map[string]string, error) {
+	options := make(map[string]string, len(mapping.Options)+2)
+	for name, value := range mapping.Options {
+		options[name] = value
+	}
+	if mapping.CredentialsSecret == nil {
+		return options, nil
+	}
+
+	var secret corev1.Secret
+	if err := r.GetClient().Get(ctx,
+		client.ObjectKey{Namespace: r.instance.Namespace, Name: mapping.CredentialsSecret.Name},
+		&secret); err != nil {
+		return nil, fmt.Errorf("while getting the credentials secret %s: %w", mapping.CredentialsSecret.Name, err)
+	}
+
+	username, password, err := utils.GetUserPasswordFromSecret(&secret)
+	if err != nil {
+		return nil, err
+	}
+	options["user"] = username
+	options["password"] = password
+	return options, nil
+}
+
+// getObjectOptions gets the options of a foreign data wrapper, foreign
+// server or user mapping, with a query returning their names and values
+func getObjectOptions(ctx context.Context, db *sql.DB, query string, args ...interface{}) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	options := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		options[name] = value
+	}
+	return options, rows.Err()
+}
+
+// getUserMappingRole gets the role of a user mapping as used in the SQL
+// statements, where the mapping for the roles without a specific one
+// is identified by the PUBLIC keyword
+func getUserMappingRole(user string) string {
+	if strings.EqualFold(user, "public") {
+		return "PUBLIC"
+	}
+	return pgx.Identifier{user}.Sanitize()
+}
+
+// sortedOptionNames gets the names of the options in alphabetical order,
+// making the generated statements predictable
+func sortedOptionNames(options map[string]string) []string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildOptionsClause builds the OPTIONS clause used when creating a
+// foreign object, returning an empty string when there are no options
+func buildOptionsClause(options map[string]string) string {
+	if len(options) == 0 {
+		return ""
+	}
+
+	items := make([]string, 0, len(options))
+	for _, name := range sortedOptionNames(options) {
+		items = append(items, fmt.Sprintf("%s %s", pgx.Identifier{name}.Sanitize(), pq.QuoteLiteral(options[name])))
+	}
+	return fmt.Sprintf(" OPTIONS (%s)", strings.Join(items, ", "))
+}
+
+// buildAlterOptionsClause builds the OPTIONS clause moving the options of
+// a foreign object from the current values to the desired ones, returning
+// an empty string when they already match
+func buildAlterOptionsClause(current, desired map[string]string) string {
+	var items []string
+	for _, name := range sortedOptionNames(desired) {
+		currentValue, found := current[name]
+		switch {
+		case !found:
+			items = append(items, fmt.Sprintf("ADD %s %s",
+				pgx.Identifier{name}.Sanitize(), pq.QuoteLiteral(desired[name])))
+		case currentValue != desired[name]:
+			items = append(items, fmt.Sprintf("SET %s %s",
+				pgx.Identifier{name}.Sanitize(), pq.QuoteLiteral(desired[name])))
+		}
+	}
+	for _, name := range sortedOptionNames(current) {
+		if _, found := desired[name]; !found {
+			items = append(items, fmt.Sprintf("DROP %s", pgx.Identifier{name}.Sanitize()))
+		}
+	}
+
+	if len(items) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("OPTIONS (%s)", strings.Join(items, ", "))
+}
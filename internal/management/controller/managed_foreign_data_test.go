@@ -0,0 +1,45 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed foreign data", func() {
+	It("builds the options of a new foreign object", func() {
+		Expect(buildOptionsClause(nil)).To(BeEmpty())
+		Expect(buildOptionsClause(map[string]string{"port": "5432", "host": "o'neil.example.com"})).
+			To(Equal(` OPTIONS ("host" 'o''neil.example.com', "port" '5432')`))
+	})
+
+	It("aligns the options of an existing foreign object", func() {
+		current := map[string]string{"host": "old.example.com", "port": "5432", "fetch_size": "100"}
+		desired := map[string]string{"host": "new.example.com", "port": "5432", "dbname": "app"}
+		Expect(buildAlterOptionsClause(current, desired)).
+			To(Equal(`OPTIONS (ADD "dbname" 'app', SET "host" 'new.example.com', DROP "fetch_size")`))
+		Expect(buildAlterOptionsClause(desired, desired)).To(BeEmpty())
+		Expect(buildAlterOptionsClause(nil, nil)).To(BeEmpty())
+	})
+
+	It("identifies the role of a user mapping", func() {
+		Expect(getUserMappingRole("app")).To(Equal(`"app"`))
+		Expect(getUserMappingRole("public")).To(Equal("PUBLIC"))
+		Expect(getUserMappingRole("PUBLIC")).To(Equal("PUBLIC"))
+	})
+})
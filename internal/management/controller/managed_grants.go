@@ -0,0 +1,311 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// roleOIDQuery is the subquery resolving the role passed as second parameter
+const roleOIDQuery = "(SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $2)"
+
+// reconcileManagedGrants applies the privileges declared for the roles
+// on a managed database, revoking the ones directly granted to these
+// roles and not declared anymore. The passed connection must be
+// established with the managed database
+func reconcileManagedGrants(ctx context.Context, db *sql.DB, database apiv1.ManagedDatabase) error {
+	contextLogger := log.FromContext(ctx).WithValues("database", database.Name)
+
+	for _, grant := range database.Grants {
+		exists, err := objectExists(ctx, db, "SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1)",
+			grant.Role)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			contextLogger.Info("Skipping the privileges of a missing role", "role", grant.Role)
+			continue
+		}
+
+		if err := reconcileDatabasePrivileges(ctx, db, database.Name, grant); err != nil {
+			return fmt.Errorf("while granting privileges on the database to %s: %w", grant.Role, err)
+		}
+
+		for _, schema := range grant.Schemas {
+			exists, err := objectExists(ctx, db,
+				"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_namespace WHERE nspname = $1)",
+				schema.Name)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				contextLogger.Info("Skipping the privileges on a missing schema",
+					"role", grant.Role, "schema", schema.Name)
+				continue
+			}
+
+			if err := reconcileSchemaPrivileges(ctx, db, grant.Role, database.GetOwner(), schema); err != nil {
+				return fmt.Errorf("while granting privileges on schema %s to %s: %w",
+					schema.Name, grant.Role, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reconcileDatabasePrivileges applies the privileges of a role on the database
+func reconcileDatabasePrivileges(
+	ctx context.Context,
+	db *sql.DB,
+	databaseName string,
+	grant apiv1.ManagedGrant,
+) error {
+	current, err := getPrivileges(ctx, db,
+		"SELECT a.privilege_type FROM pg_catalog.pg_database d, "+
+			"LATERAL pg_catalog.aclexplode(d.datacl) a "+
+			"WHERE d.datname = $1 AND a.grantee = "+roleOIDQuery,
+		databaseName, grant.Role)
+	if err != nil {
+		return err
+	}
+
+	desired := make([]string, len(grant.Privileges))
+	for idx, privilege := range grant.Privileges {
+		desired[idx] = string(privilege)
+	}
+
+	return applyPrivileges(ctx, db, current, desired,
+		"DATABASE "+pgx.Identifier{databaseName}.Sanitize(), grant.Role)
+}
+
+// reconcileSchemaPrivileges applies the privileges of a role on a
+// schema, on its tables and the default ones on the future tables
+func reconcileSchemaPrivileges(
+	ctx context.Context,
+	db *sql.DB,
+	role string,
+	owner string,
+	schema apiv1.ManagedSchemaGrant,
+) error {
+	schemaName := pgx.Identifier{schema.Name}.Sanitize()
+
+	current, err := getPrivileges(ctx, db,
+		"SELECT a.privilege_type FROM pg_catalog.pg_namespace n, "+
+			"LATERAL pg_catalog.aclexplode(n.nspacl) a "+
+			"WHERE n.nspname = $1 AND a.grantee = "+roleOIDQuery,
+		schema.Name, role)
+	if err != nil {
+		return err
+	}
+	desired := make([]string, len(schema.Privileges))
+	for idx, privilege := range schema.Privileges {
+		desired[idx] = string(privilege)
+	}
+	if err := applyPrivileges(ctx, db, current, desired, "SCHEMA "+schemaName, role); err != nil {
+		return err
+	}
+
+	desiredTablePrivileges := make([]string, len(schema.TablePrivileges))
+	for idx, privilege := range schema.TablePrivileges {
+		desiredTablePrivileges[idx] = string(privilege)
+	}
+
+	tables, err := getTablePrivileges(ctx, db, schema.Name, role)
+	if err != nil {
+		return err
+	}
+	for _, table := range sortedKeys(tables) {
+		tableName := schemaName + "." + pgx.Identifier{table}.Sanitize()
+		if err := applyPrivileges(ctx, db, tables[table], desiredTablePrivileges, "TABLE "+tableName, role); err != nil {
+			return err
+		}
+	}
+
+	var desiredDefaultPrivileges []string
+	if schema.DefaultPrivileges {
+		desiredDefaultPrivileges = desiredTablePrivileges
+	}
+	current, err = getPrivileges(ctx, db,
+		"SELECT a.privilege_type FROM pg_catalog.pg_default_acl d "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = d.defaclnamespace, "+
+			"LATERAL pg_catalog.aclexplode(d.defaclacl) a "+
+			"WHERE n.nspname = $1 AND d.defaclobjtype = 'r' AND a.grantee = "+roleOIDQuery+
+			" AND d.defaclrole = (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $3)",
+		schema.Name, role, owner)
+	if err != nil {
+		return err
+	}
+	toGrant, toRevoke := diffPrivileges(current, desiredDefaultPrivileges)
+	prefix := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s ",
+		pgx.Identifier{owner}.Sanitize(), schemaName)
+	for _, statement := range buildPrivilegeStatements(toGrant, toRevoke, "TABLES", role) {
+		log.FromContext(ctx).Info("Updating default privileges", "statement", prefix+statement)
+		if _, err := db.ExecContext(ctx, prefix+statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPrivileges grants and revokes the privileges on an object needed
+// to move from the current privileges of the role to the desired ones
+func applyPrivileges(
+	ctx context.Context,
+	db *sql.DB,
+	current, desired []string,
+	object string,
+	role string,
+) error {
+	toGrant, toRevoke := diffPrivileges(current, desired)
+	for _, statement := range buildPrivilegeStatements(toGrant, toRevoke, object, role) {
+		log.FromContext(ctx).Info("Updating privileges", "statement", statement)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// diffPrivileges gets the privileges to be granted and to be revoked
+// to move from the current privileges to the desired ones
+func diffPrivileges(current, desired []string) (toGrant, toRevoke []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, privilege := range current {
+		currentSet[privilege] = true
+	}
+	desiredSet := make(map[string]bool, len(desired))
+	for _, privilege := range desired {
+		desiredSet[privilege] = true
+	}
+
+	for privilege := range desiredSet {
+		if !currentSet[privilege] {
+			toGrant = append(toGrant, privilege)
+		}
+	}
+	for privilege := range currentSet {
+		if !desiredSet[privilege] {
+			toRevoke = append(toRevoke, privilege)
+		}
+	}
+
+	sort.Strings(toGrant)
+	sort.Strings(toRevoke)
+	return toGrant, toRevoke
+}
+
+// buildPrivilegeStatements builds the GRANT and REVOKE statements
+// for the passed privileges on an object
+func buildPrivilegeStatements(toGrant, toRevoke []string, object string, role string) []string {
+	var statements []string
+	roleName := pgx.Identifier{role}.Sanitize()
+	if len(toGrant) > 0 {
+		statements = append(statements,
+			fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(toGrant, ", "), object, roleName))
+	}
+	if len(toRevoke) > 0 {
+		statements = append(statements,
+			fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(toRevoke, ", "), object, roleName))
+	}
+	return statements
+}
+
+// getPrivileges runs a query returning a list of privileges
+func getPrivileges(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var privileges []string
+	for rows.Next() {
+		var privilege string
+		if err := rows.Scan(&privilege); err != nil {
+			return nil, err
+		}
+		privileges = append(privileges, privilege)
+	}
+
+	return privileges, rows.Err()
+}
+
+// getTablePrivileges gets the privileges directly granted to a role
+// on the tables and views of a schema, indexed by table name
+func getTablePrivileges(ctx context.Context, db *sql.DB, schema, role string) (map[string][]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT c.relname, a.privilege_type FROM pg_catalog.pg_class c "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace "+
+			"LEFT JOIN LATERAL pg_catalog.aclexplode(c.relacl) a ON a.grantee = "+roleOIDQuery+" "+
+			"WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')",
+		schema, role)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tables := make(map[string][]string)
+	for rows.Next() {
+		var table string
+		var privilege sql.NullString
+		if err := rows.Scan(&table, &privilege); err != nil {
+			return nil, err
+		}
+		if privilege.Valid {
+			tables[table] = append(tables[table], privilege.String)
+		} else if _, ok := tables[table]; !ok {
+			tables[table] = nil
+		}
+	}
+
+	return tables, rows.Err()
+}
+
+// objectExists runs a query checking for the existence of an object
+func objectExists(ctx context.Context, db *sql.DB, query string, name string) (bool, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, query, name).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// sortedKeys gets the keys of a map in a stable order
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,48 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed grants", func() {
+	It("computes the privileges to be granted and revoked", func() {
+		toGrant, toRevoke := diffPrivileges(
+			[]string{"SELECT", "DELETE", "SELECT"},
+			[]string{"UPDATE", "SELECT", "INSERT"})
+		Expect(toGrant).To(Equal([]string{"INSERT", "UPDATE"}))
+		Expect(toRevoke).To(Equal([]string{"DELETE"}))
+	})
+
+	It("has nothing to do when the privileges are up to date", func() {
+		toGrant, toRevoke := diffPrivileges([]string{"CONNECT"}, []string{"CONNECT"})
+		Expect(toGrant).To(BeEmpty())
+		Expect(toRevoke).To(BeEmpty())
+		Expect(buildPrivilegeStatements(toGrant, toRevoke, "DATABASE \"app\"", "reporting")).To(BeEmpty())
+	})
+
+	It("builds the statements quoting the role", func() {
+		Expect(buildPrivilegeStatements(
+			[]string{"INSERT", "SELECT"}, []string{"DELETE"}, `TABLE "public"."orders"`, "Reporting")).
+			To(Equal([]string{
+				`GRANT INSERT, SELECT ON TABLE "public"."orders" TO "Reporting"`,
+				`REVOKE DELETE ON TABLE "public"."orders" FROM "Reporting"`,
+			}))
+	})
+})
@@ -0,0 +1,384 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// onlineImportCatchUpTimeout is the time we wait, during the cutover,
+// for the imported database to apply the last changes of the origin
+const onlineImportCatchUpTimeout = 20 * time.Second
+
+// reconcileOnlineImport keeps, on the primary, the application database in
+// sync with the origin of an online import, and completes the migration when
+// the cutover is requested. It returns true while the origin is being
+// followed, so that the progress can be periodically reported
+func (r *InstanceReconciler) reconcileOnlineImport(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil ||
+		!cluster.Spec.Bootstrap.InitDB.Import.IsOnline() {
+		return false, nil
+	}
+	if cluster.Status.OnlineImport != nil && cluster.Status.OnlineImport.Phase == apiv1.OnlineImportPhaseCompleted {
+		return false, nil
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil || !primary {
+		return false, err
+	}
+
+	status := apiv1.OnlineImportStatus{}
+	if cluster.Status.OnlineImport != nil {
+		status.Phase = cluster.Status.OnlineImport.Phase
+	}
+
+	err = r.followOnlineImportOrigin(ctx, cluster, &status)
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	if patchErr := r.updateOnlineImportStatus(ctx, cluster, status); patchErr != nil {
+		return true, patchErr
+	}
+
+	return status.Phase != apiv1.OnlineImportPhaseCompleted, err
+}
+
+// followOnlineImportOrigin ensures the subscription to the origin
+// exists, reporting its progress, and executes the cutover when requested
+func (r *InstanceReconciler) followOnlineImportOrigin(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status *apiv1.OnlineImportStatus,
+) error {
+	contextLogger := log.FromContext(ctx)
+	importSpec := cluster.Spec.Bootstrap.InitDB.Import
+	originDatabase := importSpec.Databases[0]
+	publication := importSpec.Online.GetPublicationName()
+
+	originConnString, err := r.getOnlineImportOriginConnString(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	originPool := pool.NewConnectionPool(originConnString)
+	defer originPool.ShutdownConnections()
+
+	originDB, err := originPool.Connection(originDatabase)
+	if err != nil {
+		return fmt.Errorf("while connecting to the origin database: %w", err)
+	}
+	db, err := r.instance.ConnectionPool().Connection(cluster.GetApplicationDatabaseName())
+	if err != nil {
+		return fmt.Errorf("while connecting to the application database: %w", err)
+	}
+
+	if err := ensureOnlineImportPublication(ctx, originDB, publication); err != nil {
+		return err
+	}
+	if err := ensureOnlineImportSubscription(
+		ctx, db, originPool.GetDsn(originDatabase), publication); err != nil {
+		return err
+	}
+
+	if status.Phase != apiv1.OnlineImportPhaseCuttingOver {
+		status.Phase, err = getOnlineImportCopyPhase(ctx, db)
+		if err != nil {
+			return err
+		}
+	}
+
+	status.LagBytes, err = getOnlineImportLag(ctx, originDB)
+	if err != nil {
+		return err
+	}
+
+	// The cutover can start only when the initial copy of the tables is completed
+	if !importSpec.Online.Cutover || status.Phase == apiv1.OnlineImportPhaseCopying {
+		return nil
+	}
+
+	if status.Phase != apiv1.OnlineImportPhaseCuttingOver {
+		contextLogger.Info("Stopping the writes on the origin database", "database", originDatabase)
+		if err := stopOriginWrites(ctx, originDB, originDatabase); err != nil {
+			return err
+		}
+		status.Phase = apiv1.OnlineImportPhaseCuttingOver
+	}
+
+	caughtUp, err := waitForOnlineImportCatchUp(ctx, originDB)
+	if err != nil || !caughtUp {
+		return err
+	}
+
+	contextLogger.Info("The imported database caught up with the origin, completing the cutover")
+	if err := alignOnlineImportSequences(ctx, originDB, db); err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("DROP SUBSCRIPTION %s",
+		pgx.Identifier{apiv1.OnlineImportSubscriptionName}.Sanitize())); err != nil {
+		return fmt.Errorf("while dropping the subscription: %w", err)
+	}
+	if publication == apiv1.OnlineImportDefaultPublicationName {
+		if _, err := originDB.ExecContext(ctx, fmt.Sprintf("DROP PUBLICATION IF EXISTS %s",
+			pgx.Identifier{publication}.Sanitize())); err != nil {
+			return fmt.Errorf("while dropping the publication: %w", err)
+		}
+	}
+
+	status.Phase = apiv1.OnlineImportPhaseCompleted
+	status.LagBytes = 0
+	return nil
+}
+
+// getOnlineImportOriginConnString gets the connection string to the
+// origin of the import, without the database name
+func (r *InstanceReconciler) getOnlineImportOriginConnString(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (string, error) {
+	server, ok := cluster.ExternalCluster(cluster.Spec.Bootstrap.InitDB.Import.Source.ExternalCluster)
+	if !ok {
+		return "", fmt.Errorf("missing external cluster")
+	}
+
+	server = *server.DeepCopy()
+	delete(server.ConnectionParameters, "dbname")
+
+	connectionString, pgpassfile, err := external.ConfigureConnectionToServer(
+		ctx, r.client, r.instance.Namespace, &server)
+	if err != nil {
+		return "", err
+	}
+
+	return buildOnlineImportConnString(connectionString, pgpassfile), nil
+}
+
+// buildOnlineImportConnString adds to a connection string the password
+// file, which is read both by the instance manager and by PostgreSQL
+func buildOnlineImportConnString(connectionString, pgpassfile string) string {
+	if pgpassfile == "" {
+		return connectionString
+	}
+
+	return fmt.Sprintf("%v passfile=%v", connectionString, pgpassfile)
+}
+
+// updateOnlineImportStatus stores the progress of the online import in the cluster status
+func (r *InstanceReconciler) updateOnlineImportStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status apiv1.OnlineImportStatus,
+) error {
+	if cluster.Status.OnlineImport != nil && reflect.DeepEqual(*cluster.Status.OnlineImport, status) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.OnlineImport = &status
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// ensureOnlineImportPublication checks that the publication exists in the
+// origin database, creating it when the default one is used
+func ensureOnlineImportPublication(ctx context.Context, originDB *sql.DB, publication string) error {
+	var exists bool
+	row := originDB.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM pg_catalog.pg_publication WHERE pubname = $1)",
+		publication)
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("while checking the publication in the origin: %w", err)
+	}
+
+	switch {
+	case exists:
+		return nil
+	case publication != apiv1.OnlineImportDefaultPublicationName:
+		return fmt.Errorf("publication %s not found in the origin database", publication)
+	}
+
+	if _, err := originDB.ExecContext(ctx, fmt.Sprintf("CREATE PUBLICATION %s FOR ALL TABLES",
+		pgx.Identifier{publication}.Sanitize())); err != nil {
+		return fmt.Errorf("while creating the publication in the origin: %w", err)
+	}
+
+	return nil
+}
+
+// ensureOnlineImportSubscription creates the subscription to the origin when missing
+func ensureOnlineImportSubscription(ctx context.Context, db *sql.DB, originDSN, publication string) error {
+	var exists bool
+	row := db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM pg_catalog.pg_subscription WHERE subname = $1)",
+		apiv1.OnlineImportSubscriptionName)
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("while checking the subscription: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Creating the subscription to the origin database", "publication", publication)
+	if _, err := db.ExecContext(ctx, buildCreateSubscriptionStatement(originDSN, publication)); err != nil {
+		return fmt.Errorf("while creating the subscription: %w", err)
+	}
+
+	return nil
+}
+
+// buildCreateSubscriptionStatement builds the statement creating the subscription
+// to the origin, which copies the content of the tables before streaming the changes
+func buildCreateSubscriptionStatement(originDSN, publication string) string {
+	return fmt.Sprintf("CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s",
+		pgx.Identifier{apiv1.OnlineImportSubscriptionName}.Sanitize(),
+		pq.QuoteLiteral(originDSN),
+		pgx.Identifier{publication}.Sanitize())
+}
+
+// getOnlineImportCopyPhase detects whether the subscription is still
+// copying the content of the tables
+func getOnlineImportCopyPhase(ctx context.Context, db *sql.DB) (apiv1.OnlineImportPhase, error) {
+	var copying int
+	row := db.QueryRowContext(ctx,
+		`SELECT count(*) FROM pg_catalog.pg_subscription_rel sr
+		JOIN pg_catalog.pg_subscription s ON s.oid = sr.srsubid
+		WHERE s.subname = $1 AND sr.srsubstate <> 'r'`,
+		apiv1.OnlineImportSubscriptionName)
+	if err := row.Scan(&copying); err != nil {
+		return "", fmt.Errorf("while checking the tables being copied: %w", err)
+	}
+
+	if copying > 0 {
+		return apiv1.OnlineImportPhaseCopying, nil
+	}
+	return apiv1.OnlineImportPhaseStreaming, nil
+}
+
+// getOnlineImportLag gets the amount of WAL generated by the origin
+// and not yet confirmed by the subscription
+func getOnlineImportLag(ctx context.Context, originDB *sql.DB) (int64, error) {
+	var lag int64
+	row := originDB.QueryRowContext(ctx,
+		`SELECT COALESCE(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn), 0)::bigint
+		FROM pg_catalog.pg_replication_slots WHERE slot_name = $1`,
+		apiv1.OnlineImportSubscriptionName)
+	if err := row.Scan(&lag); err != nil {
+		return 0, fmt.Errorf("while reading the replication slot in the origin: %w", err)
+	}
+
+	return lag, nil
+}
+
+// stopOriginWrites makes the origin database read-only and
+// disconnects the applications using it
+func stopOriginWrites(ctx context.Context, originDB *sql.DB, originDatabase string) error {
+	if _, err := originDB.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET default_transaction_read_only TO on",
+		pgx.Identifier{originDatabase}.Sanitize())); err != nil {
+		return fmt.Errorf("while making the origin database read-only: %w", err)
+	}
+
+	if _, err := originDB.ExecContext(ctx,
+		`SELECT pg_catalog.pg_terminate_backend(pid) FROM pg_catalog.pg_stat_activity
+		WHERE datname = $1 AND pid <> pg_catalog.pg_backend_pid() AND backend_type = 'client backend'`,
+		originDatabase); err != nil {
+		return fmt.Errorf("while disconnecting the applications from the origin database: %w", err)
+	}
+
+	return nil
+}
+
+// waitForOnlineImportCatchUp waits for the subscription to confirm
+// the current position of the origin
+func waitForOnlineImportCatchUp(ctx context.Context, originDB *sql.DB) (bool, error) {
+	var targetLSN string
+	if err := originDB.QueryRowContext(ctx, "SELECT pg_catalog.pg_current_wal_lsn()").Scan(&targetLSN); err != nil {
+		return false, fmt.Errorf("while reading the position of the origin: %w", err)
+	}
+
+	timeout := time.After(onlineImportCatchUpTimeout)
+	for {
+		var caughtUp bool
+		row := originDB.QueryRowContext(ctx,
+			"SELECT confirmed_flush_lsn >= $1::pg_lsn FROM pg_catalog.pg_replication_slots WHERE slot_name = $2",
+			targetLSN, apiv1.OnlineImportSubscriptionName)
+		if err := row.Scan(&caughtUp); err != nil {
+			return false, fmt.Errorf("while reading the replication slot in the origin: %w", err)
+		}
+		if caughtUp {
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timeout:
+			return false, nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// alignOnlineImportSequences copies the current value of the sequences
+// of the origin, as they are not replicated by the subscription
+func alignOnlineImportSequences(ctx context.Context, originDB, db *sql.DB) error {
+	rows, err := originDB.QueryContext(ctx,
+		"SELECT schemaname, sequencename, last_value FROM pg_catalog.pg_sequences WHERE last_value IS NOT NULL")
+	if err != nil {
+		return fmt.Errorf("while reading the sequences of the origin: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	type sequence struct {
+		schema, name string
+		value        int64
+	}
+	var sequences []sequence
+	for rows.Next() {
+		var seq sequence
+		if err := rows.Scan(&seq.schema, &seq.name, &seq.value); err != nil {
+			return err
+		}
+		sequences = append(sequences, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, seq := range sequences {
+		if _, err := db.ExecContext(ctx, "SELECT pg_catalog.setval($1::regclass, $2)",
+			pgx.Identifier{seq.schema, seq.name}.Sanitize(), seq.value); err != nil {
+			return fmt.Errorf("while aligning sequence %s.%s: %w", seq.schema, seq.name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Online import", func() {
+	It("adds the password file to the connection string of the origin", func() {
+		Expect(buildOnlineImportConnString("host=origin user=postgres", "")).
+			To(Equal("host=origin user=postgres"))
+		Expect(buildOnlineImportConnString("host=origin user=postgres", "/controller/external/pgpass")).
+			To(Equal("host=origin user=postgres passfile=/controller/external/pgpass"))
+	})
+
+	It("creates the subscription to the origin publication", func() {
+		Expect(buildCreateSubscriptionStatement("host=origin user=app's dbname=app", "my_pub")).
+			To(Equal(`CREATE SUBSCRIPTION "cnpg_online_import" ` +
+				`CONNECTION 'host=origin user=app''s dbname=app' PUBLICATION "my_pub"`))
+	})
+})
@@ -22,6 +22,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
 
@@ -59,6 +60,18 @@ func (r *InstanceReconciler) refreshReplicaConfiguration(
 }
 
 func (r *InstanceReconciler) writeReplicaConfigurationForReplica(cluster *apiv1.Cluster) (changed bool, err error) {
+	if upstream := cluster.GetCascadingUpstream(r.instance.PodName); upstream != "" {
+		// HA replication slots are only available on the
+		// primary, a cascading standby streams without them
+		upstreamIP := cluster.Status.InstancesReportedState[apiv1.PodName(upstream)].IP
+		changed, err = postgres.UpdateReplicaConfiguration(
+			r.instance.PgData, r.instance.GetUpstreamConnInfo(upstreamIP), "")
+		if changed {
+			log.Info("Streaming from a standby instance", "upstream", upstream, "upstreamIP", upstreamIP)
+		}
+		return changed, err
+	}
+
 	slotName := cluster.GetSlotNameFromInstanceName(r.instance.PodName)
 	return postgres.UpdateReplicaConfiguration(r.instance.PgData, r.instance.GetPrimaryConnInfo(), slotName)
 }
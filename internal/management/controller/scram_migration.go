@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileSCRAMMigration reports, on the primary, the roles whose password
+// is still hashed with md5 and, when requested, hashes again the passwords
+// of the roles managed by the operator using SCRAM-SHA-256
+func (r *InstanceReconciler) reconcileSCRAMMigration(ctx context.Context, cluster *apiv1.Cluster) error {
+	migration := cluster.Spec.PostgresConfiguration.SCRAMMigration
+	if migration == nil && len(cluster.Status.MD5PasswordRoles) == 0 {
+		return nil
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil || !primary {
+		return err
+	}
+
+	if migration == nil {
+		return r.updateMD5PasswordRoles(ctx, cluster, nil)
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	md5Roles, err := getMD5PasswordRoles(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if migration.RehashManagedRoles && len(md5Roles) > 0 {
+		if rehashManagedRolePasswords(ctx, cluster, md5Roles, r.secretVersions) {
+			if err = r.refreshCredentialsFromSecret(ctx, cluster); err != nil {
+				return fmt.Errorf("while hashing again the passwords of the managed roles: %w", err)
+			}
+			if md5Roles, err = getMD5PasswordRoles(ctx, db); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.updateMD5PasswordRoles(ctx, cluster, md5Roles)
+}
+
+// rehashManagedRolePasswords forgets the applied version of the secrets of
+// the managed roles whose password is hashed with md5, so that their
+// password is set again. Returns true if any password needs to be set
+func rehashManagedRolePasswords(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	md5Roles []string,
+	secretVersions map[string]string,
+) bool {
+	contextLogger := log.FromContext(ctx)
+
+	rehash := false
+	managedRoles := getManagedRoleSecrets(cluster)
+	for _, role := range md5Roles {
+		secretName, ok := managedRoles[role]
+		if !ok {
+			continue
+		}
+
+		contextLogger.Info("Hashing again the password of a managed role with SCRAM-SHA-256",
+			"role", role, "secret", secretName)
+		delete(secretVersions, secretName)
+		rehash = true
+	}
+
+	return rehash
+}
+
+// getManagedRoleSecrets gets the roles whose password is set by the
+// operator, with the name of the secret or the path of the credentials
+// file containing it
+func getManagedRoleSecrets(cluster *apiv1.Cluster) map[string]string {
+	result := make(map[string]string)
+	if cluster.GetEnableSuperuserAccess() {
+		result["postgres"] = cluster.GetSuperuserSecretName()
+		if passwordFile := cluster.GetSuperuserPasswordFile(); passwordFile != "" {
+			result["postgres"] = passwordFile
+		}
+	}
+	if cluster.ShouldCreateApplicationDatabase() {
+		result[cluster.GetApplicationDatabaseOwner()] = cluster.GetApplicationSecretName()
+		if passwordFile := cluster.GetApplicationPasswordFile(); passwordFile != "" {
+			result[cluster.GetApplicationDatabaseOwner()] = passwordFile
+		}
+	}
+	return result
+}
+
+// getMD5PasswordRoles gets the roles whose password is hashed with md5
+func getMD5PasswordRoles(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT rolname FROM pg_catalog.pg_authid WHERE rolpassword LIKE 'md5%' ORDER BY rolname")
+	if err != nil {
+		return nil, fmt.Errorf("while listing the roles with an md5 password: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err = rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("while listing the roles with an md5 password: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// updateMD5PasswordRoles reports the roles whose password is hashed with
+// md5 in the status of the cluster
+func (r *InstanceReconciler) updateMD5PasswordRoles(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	roles []string,
+) error {
+	if reflect.DeepEqual(cluster.Status.MD5PasswordRoles, roles) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.MD5PasswordRoles = roles
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SCRAM migration", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Spec: apiv1.ClusterSpec{
+			EnableSuperuserAccess: pointer.Bool(true),
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+			},
+		},
+	}
+
+	It("knows the secrets of the managed roles", func() {
+		Expect(getManagedRoleSecrets(cluster)).To(Equal(map[string]string{
+			"postgres": "cluster-example-superuser",
+			"app":      "cluster-example-app",
+		}))
+	})
+
+	It("knows the credentials files of the managed roles", func() {
+		clusterWithFiles := cluster.DeepCopy()
+		clusterWithFiles.Spec.CredentialsFiles = &apiv1.CredentialsFilesConfiguration{
+			ApplicationPasswordFile: "/vault/secrets/app",
+		}
+		Expect(getManagedRoleSecrets(clusterWithFiles)).To(Equal(map[string]string{
+			"postgres": "cluster-example-superuser",
+			"app":      "/vault/secrets/app",
+		}))
+	})
+
+	It("sets again the passwords of the managed roles hashed with md5", func() {
+		secretVersions := map[string]string{
+			"cluster-example-superuser": "1",
+			"cluster-example-app":       "2",
+		}
+		Expect(rehashManagedRolePasswords(context.TODO(), cluster, []string{"app", "legacy"}, secretVersions)).
+			To(BeTrue())
+		Expect(secretVersions).To(Equal(map[string]string{"cluster-example-superuser": "1"}))
+
+		Expect(rehashManagedRolePasswords(context.TODO(), cluster, []string{"legacy"}, secretVersions)).
+			To(BeFalse())
+	})
+})
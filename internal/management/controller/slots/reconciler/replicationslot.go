@@ -67,6 +67,7 @@ func reconcilePrimaryReplicationSlots(
 	}
 
 	expectedSlots := make(map[string]bool)
+	cascadingUpstreams := cluster.GetCascadingReplicationUpstreams()
 
 	// Add every slot that is missing
 	for _, instanceName := range cluster.Status.InstanceNames {
@@ -74,6 +75,11 @@ func reconcilePrimaryReplicationSlots(
 			continue
 		}
 
+		// Standbys streaming from another standby don't need a slot on the primary
+		if _, isCascading := cascadingUpstreams[instanceName]; isCascading {
+			continue
+		}
+
 		slotName := cluster.GetSlotNameFromInstanceName(instanceName)
 		expectedSlots[slotName] = true
 
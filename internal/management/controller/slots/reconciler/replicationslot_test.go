@@ -21,6 +21,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/infrastructure"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -145,4 +146,33 @@ var _ = Describe("HA Replication Slots reconciliation in Primary", func() {
 		Expect(fakeSlotManager.replicationSlots[fakeSlot{name: slotPrefix + "instance3", active: true}]).To(BeTrue())
 		Expect(fakeSlotManager.replicationSlots).To(HaveLen(2))
 	})
+	It("will not create a replication slot for a cascading standby", func() {
+		fakeSlotManager := fakeReplicationSlotManager{
+			replicationSlots: map[fakeSlot]bool{
+				{name: slotPrefix + "instance2"}: true,
+				{name: slotPrefix + "instance3"}: true,
+			},
+		}
+
+		cluster := makeClusterWithInstanceNames([]string{"instance1", "instance2", "instance3"}, "instance1")
+		cluster.Spec.Replication = &apiv1.ReplicationConfiguration{
+			Cascading: &apiv1.CascadingReplicationConfiguration{
+				Instances: []apiv1.CascadingReplicationInstance{
+					{Name: "instance3", Upstream: "instance2"},
+				},
+			},
+		}
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {"instance1", "instance2", "instance3"},
+		}
+		cluster.Status.InstancesReportedState = map[apiv1.PodName]apiv1.InstanceReportedState{
+			"instance2": {IP: "10.0.0.2"},
+		}
+
+		_, err := ReconcileReplicationSlots(context.TODO(), "instance1", fakeSlotManager, &cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(fakeSlotManager.replicationSlots[fakeSlot{name: slotPrefix + "instance2"}]).To(BeTrue())
+		Expect(fakeSlotManager.replicationSlots[fakeSlot{name: slotPrefix + "instance3"}]).To(BeFalse())
+		Expect(fakeSlotManager.replicationSlots).To(HaveLen(1))
+	})
 })
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -167,3 +168,27 @@ func getPVC(ctx context.Context, name string) (*v1.PersistentVolumeClaim, error)
 	}
 	return &pvc, nil
 }
+
+// IsInstanceStopped checks if PostgreSQL is not running inside the passed Pod
+func IsInstanceStopped(
+	ctx context.Context,
+	pod v1.Pod,
+) (bool, error) {
+	timeout := time.Second * 2
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		"pg_ctl", "status")
+
+	// todo: do not rely on strings
+	// pg_ctl returns exit code 3 if no postgres is running
+	if err != nil && !strings.Contains(err.Error(), "command terminated with exit code 3") {
+		return false, fmt.Errorf("IsInstanceStopped command returned the following error: %s", err)
+	}
+	return strings.Contains(stdout, "pg_ctl: no server running"), nil
+}
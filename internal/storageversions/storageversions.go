@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storageversions migrates the objects of the custom resources to
+// the storage version of their definition
+package storageversions
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+var storageVersionsLog = log.WithName("storage-versions")
+
+// Migrate rewrites the objects of the passed custom resources that may
+// still be stored with a version different from the storage one, so that
+// the old versions can be removed from their definitions. A failure in
+// migrating a custom resource doesn't stop the migration of the other
+// ones, and all the errors are returned together
+func Migrate(
+	ctx context.Context,
+	c client.Client,
+	apiClient apiextensionsclientset.Interface,
+	names []string,
+) error {
+	var errs []error
+	for _, name := range names {
+		if err := migrateStorageVersion(ctx, c, apiClient, name); err != nil {
+			errs = append(errs, fmt.Errorf("while migrating %s: %w", name, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// migrateStorageVersion rewrites the objects of a custom resource with the
+// storage version, then records it as the only stored version. The stored
+// versions are left untouched unless every object has been rewritten
+func migrateStorageVersion(
+	ctx context.Context,
+	c client.Client,
+	apiClient apiextensionsclientset.Interface,
+	name string,
+) error {
+	crd, err := apiClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	storageVersion := getStorageVersion(crd)
+	if storageVersion == "" || !needsStorageVersionMigration(crd.Status.StoredVersions, storageVersion) {
+		return nil
+	}
+
+	storageVersionsLog.Info("Migrating the objects to the storage version",
+		"customResourceDefinition", name,
+		"storedVersions", crd.Status.StoredVersions,
+		"storageVersion", storageVersion)
+
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   crd.Spec.Group,
+		Version: storageVersion,
+		Kind:    crd.Spec.Names.ListKind,
+	})
+	if err := c.List(ctx, &list); err != nil {
+		return err
+	}
+
+	for idx := range list.Items {
+		if err := rewriteObject(ctx, c, &list.Items[idx]); err != nil {
+			return fmt.Errorf("while migrating %s/%s: %w",
+				list.Items[idx].GetNamespace(), list.Items[idx].GetName(), err)
+		}
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	_, err = apiClient.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+	return err
+}
+
+// rewriteObject writes an object again without changing it, which is
+// enough for the API server to store it with the storage version. On a
+// conflict the object is read again, as we can't know which version the
+// other writer used, and the update is retried
+func rewriteObject(ctx context.Context, c client.Client, object *unstructured.Unstructured) error {
+	retrying := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if retrying {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(object), object); err != nil {
+				return err
+			}
+		}
+		retrying = true
+		return c.Update(ctx, object)
+	})
+	if apierrs.IsNotFound(err) {
+		// The object has been deleted, so there's nothing to migrate
+		return nil
+	}
+
+	return err
+}
+
+// getStorageVersion gets the version used to store the objects of a
+// custom resource
+func getStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+
+	return ""
+}
+
+// needsStorageVersionMigration checks whether some objects of a custom
+// resource may be stored with a version different from the storage one
+func needsStorageVersionMigration(storedVersions []string, storageVersion string) bool {
+	for _, version := range storedVersions {
+		if version != storageVersion {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageversions
+
+import (
+	"context"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	fakeApiExtension "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// failingUpdateClient is a client failing the first updates
+// with the passed errors
+type failingUpdateClient struct {
+	client.Client
+	updateErrors []error
+	gets         int
+}
+
+func (c *failingUpdateClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if len(c.updateErrors) > 0 {
+		err := c.updateErrors[0]
+		c.updateErrors = c.updateErrors[1:]
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *failingUpdateClient) Get(
+	ctx context.Context,
+	key client.ObjectKey,
+	obj client.Object,
+	opts ...client.GetOption,
+) error {
+	c.gets++
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+var _ = Describe("storage version migration", func() {
+	newCRD := func(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "clusters.postgresql.cnpg.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "postgresql.cnpg.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Kind:     "Cluster",
+					ListKind: "ClusterList",
+				},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1beta1", Served: true},
+					{Name: "v1", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				StoredVersions: storedVersions,
+			},
+		}
+	}
+
+	It("finds the storage version of a definition", func() {
+		Expect(getStorageVersion(newCRD())).To(Equal("v1"))
+		Expect(getStorageVersion(&apiextensionsv1.CustomResourceDefinition{})).To(BeEmpty())
+	})
+
+	It("detects when the objects may be stored with a different version", func() {
+		Expect(needsStorageVersionMigration([]string{"v1"}, "v1")).To(BeFalse())
+		Expect(needsStorageVersionMigration(nil, "v1")).To(BeFalse())
+		Expect(needsStorageVersionMigration([]string{"v1beta1", "v1"}, "v1")).To(BeTrue())
+	})
+
+	It("records the storage version as the only stored one", func(ctx context.Context) {
+		apiClient := fakeApiExtension.NewSimpleClientset(newCRD("v1beta1", "v1"))
+		c := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+
+		Expect(migrateStorageVersion(ctx, c, apiClient, "clusters.postgresql.cnpg.io")).To(Succeed())
+
+		crd, err := apiClient.ApiextensionsV1().CustomResourceDefinitions().Get(
+			ctx, "clusters.postgresql.cnpg.io", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(crd.Status.StoredVersions).To(Equal([]string{"v1"}))
+	})
+
+	It("leaves alone the definitions not needing a migration", func(ctx context.Context) {
+		apiClient := fakeApiExtension.NewSimpleClientset(newCRD("v1"))
+
+		Expect(migrateStorageVersion(ctx, nil, apiClient, "clusters.postgresql.cnpg.io")).To(Succeed())
+	})
+
+	Context("with stored objects", func() {
+		newClient := func(updateErrors ...error) *failingUpdateClient {
+			cluster := &unstructured.Unstructured{}
+			cluster.SetGroupVersionKind(schema.GroupVersionKind{
+				Group: "postgresql.cnpg.io", Version: "v1", Kind: "Cluster",
+			})
+			cluster.SetNamespace("default")
+			cluster.SetName("cluster-example")
+
+			return &failingUpdateClient{
+				Client:       fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(cluster).Build(),
+				updateErrors: updateErrors,
+			}
+		}
+		conflict := apierrs.NewConflict(
+			schema.GroupResource{Group: "postgresql.cnpg.io", Resource: "clusters"}, "cluster-example", nil)
+
+		It("reads an object again and rewrites it after a conflict", func(ctx context.Context) {
+			apiClient := fakeApiExtension.NewSimpleClientset(newCRD("v1beta1", "v1"))
+			c := newClient(conflict)
+
+			Expect(migrateStorageVersion(ctx, c, apiClient, "clusters.postgresql.cnpg.io")).To(Succeed())
+			Expect(c.gets).To(Equal(1))
+			Expect(c.updateErrors).To(BeEmpty())
+
+			crd, err := apiClient.ApiextensionsV1().CustomResourceDefinitions().Get(
+				ctx, "clusters.postgresql.cnpg.io", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(crd.Status.StoredVersions).To(Equal([]string{"v1"}))
+		})
+
+		It("keeps the stored versions when an object can't be rewritten", func(ctx context.Context) {
+			apiClient := fakeApiExtension.NewSimpleClientset(newCRD("v1beta1", "v1"))
+			c := newClient(apierrs.NewForbidden(
+				schema.GroupResource{Group: "postgresql.cnpg.io", Resource: "clusters"}, "cluster-example", nil))
+
+			err := Migrate(ctx, c, apiClient, []string{"clusters.postgresql.cnpg.io", "poolers.postgresql.cnpg.io"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("clusters.postgresql.cnpg.io"))
+			Expect(err.Error()).To(ContainSubstring("poolers.postgresql.cnpg.io"))
+
+			crd, err := apiClient.ApiextensionsV1().CustomResourceDefinitions().Get(
+				ctx, "clusters.postgresql.cnpg.io", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta1", "v1"}))
+		})
+	})
+})
@@ -33,6 +33,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
 )
 
 const (
@@ -61,6 +62,23 @@ const (
 	TLSPrivateKeyKey = "tls.key"
 )
 
+// Validity contains the lifetime of the generated certificates and how
+// long before their expiration they should be renewed
+type Validity struct {
+	// Duration is the lifetime of the generated certificates
+	Duration time.Duration
+
+	// ExpiringCheckThreshold is the time before the expiration of a
+	// certificate when it is considered as expiring
+	ExpiringCheckThreshold time.Duration
+}
+
+// DefaultValidity is the validity of the certificates when not configured
+var DefaultValidity = Validity{
+	Duration:               certificateDuration,
+	ExpiringCheckThreshold: expiringCheckThreshold,
+}
+
 // CertType represent a certificate type
 type CertType string
 
@@ -143,8 +161,19 @@ func (pair KeyPair) IsValid(caPair *KeyPair, opts *x509.VerifyOptions) error {
 
 // CreateAndSignPair given a CA keypair, generate and sign a leaf keypair
 func (pair KeyPair) CreateAndSignPair(host string, usage CertType, altDNSNames []string) (*KeyPair, error) {
+	return pair.CreateAndSignPairWithDuration(host, usage, altDNSNames, certificateDuration)
+}
+
+// CreateAndSignPairWithDuration given a CA keypair, generate and sign a leaf
+// keypair valid for the passed duration
+func (pair KeyPair) CreateAndSignPairWithDuration(
+	host string,
+	usage CertType,
+	altDNSNames []string,
+	duration time.Duration,
+) (*KeyPair, error) {
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(duration)
 	return pair.createAndSignPairWithValidity(host, notBefore, notAfter, usage, altDNSNames)
 }
 
@@ -262,13 +291,23 @@ func (pair KeyPair) GenerateCertificateSecret(namespace, name string) *v1.Secret
 // parent certificate. If the parent certificate is nil the certificate
 // will be self-signed
 func (pair *KeyPair) RenewCertificate(caPrivateKey *ecdsa.PrivateKey, parentCertificate *x509.Certificate) error {
+	return pair.RenewCertificateWithDuration(caPrivateKey, parentCertificate, certificateDuration)
+}
+
+// RenewCertificateWithDuration is like RenewCertificate, but the new
+// certificate will be valid for the passed duration
+func (pair *KeyPair) RenewCertificateWithDuration(
+	caPrivateKey *ecdsa.PrivateKey,
+	parentCertificate *x509.Certificate,
+	duration time.Duration,
+) error {
 	oldCertificate, err := pair.ParseCertificate()
 	if err != nil {
 		return err
 	}
 
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(duration)
 
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
@@ -304,8 +343,29 @@ func (pair *KeyPair) RenewCertificate(caPrivateKey *ecdsa.PrivateKey, parentCert
 	return nil
 }
 
+// HasDNSNames checks if the certificate is valid for all the passed DNS names
+func (pair KeyPair) HasDNSNames(dnsNames []string) (bool, error) {
+	cert, err := pair.ParseCertificate()
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range dnsNames {
+		if !slices.Contains(cert.DNSNames, name) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // IsExpiring check if the certificate will expire in the configured duration
 func (pair *KeyPair) IsExpiring() (bool, *time.Time, error) {
+	return pair.IsExpiringWithin(expiringCheckThreshold)
+}
+
+// IsExpiringWithin check if the certificate will expire in the passed duration
+func (pair *KeyPair) IsExpiringWithin(threshold time.Duration) (bool, *time.Time, error) {
 	cert, err := pair.ParseCertificate()
 	if err != nil {
 		return true, nil, err
@@ -314,7 +374,7 @@ func (pair *KeyPair) IsExpiring() (bool, *time.Time, error) {
 	if time.Now().Before(cert.NotBefore) {
 		return true, &cert.NotAfter, nil
 	}
-	if time.Now().Add(expiringCheckThreshold).After(cert.NotAfter) {
+	if time.Now().Add(threshold).After(cert.NotAfter) {
 		return true, &cert.NotAfter, nil
 	}
 
@@ -342,8 +402,14 @@ func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit strin
 
 // CreateRootCA generates a CA returning its keys
 func CreateRootCA(commonName string, organizationalUnit string) (*KeyPair, error) {
+	return CreateRootCAWithDuration(commonName, organizationalUnit, certificateDuration)
+}
+
+// CreateRootCAWithDuration generates a CA valid for the passed duration
+// returning its keys
+func CreateRootCAWithDuration(commonName string, organizationalUnit string, duration time.Duration) (*KeyPair, error) {
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(duration)
 	return createCAWithValidity(notBefore, notAfter, nil, nil, commonName, organizationalUnit)
 }
 
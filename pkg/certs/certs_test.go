@@ -106,6 +106,18 @@ var _ = Describe("Keypair generation", func() {
 		Expect(isExpiring, err).To(BeFalse())
 	})
 
+	It("generates certificates with the requested duration", func() {
+		ca, err := CreateRootCAWithDuration("test", "namespace", 365*24*time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		isExpiring, notAfter, err := ca.IsExpiringWithin(300 * 24 * time.Hour)
+		Expect(isExpiring, err).To(BeFalse())
+		Expect(*notAfter).To(BeTemporally(">", time.Now().Add(364*24*time.Hour)))
+
+		isExpiring, _, err = ca.IsExpiringWithin(366 * 24 * time.Hour)
+		Expect(isExpiring, err).To(BeTrue())
+	})
+
 	When("we have a CA generated", func() {
 		It("should successfully generate a leaf certificate", func() {
 			rootCA, err := CreateRootCA("test", "namespace")
@@ -136,6 +148,18 @@ var _ = Describe("Keypair generation", func() {
 			Expect(cert.CheckSignatureFrom(caCert)).To(BeNil())
 		})
 
+		It("knows which DNS names a leaf certificate is valid for", func() {
+			rootCA, err := CreateRootCA("test", "namespace")
+			Expect(err).ToNot(HaveOccurred())
+
+			pair, err := rootCA.CreateAndSignPair("this.host.name.com", CertTypeServer, []string{"db.example.com"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pair.HasDNSNames([]string{"db.example.com"})).To(BeTrue())
+			Expect(pair.HasDNSNames([]string{"this.host.name.com", "db.example.com"})).To(BeTrue())
+			Expect(pair.HasDNSNames([]string{"db.example.com", "lb.example.com"})).To(BeFalse())
+		})
+
 		It("should create a CA K8s corev1/secret resource structure", func() {
 			rootCA, err := CreateRootCA("test", "namespace")
 			Expect(err).To(BeNil())
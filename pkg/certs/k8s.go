@@ -92,13 +92,20 @@ type PublicKeyInfrastructure struct {
 // certificate given the secret containing the CA that will sign it.
 // Returns true if the certificate has been renewed
 func RenewLeafCertificate(caSecret *v1.Secret, secret *v1.Secret) (bool, error) {
+	return RenewLeafCertificateWithValidity(caSecret, secret, DefaultValidity)
+}
+
+// RenewLeafCertificateWithValidity is like RenewLeafCertificate, using
+// the passed validity to decide whether the certificate is expiring and
+// to generate the new one
+func RenewLeafCertificateWithValidity(caSecret *v1.Secret, secret *v1.Secret, validity Validity) (bool, error) {
 	// Verify the temporal validity of this CA
 	pair, err := ParseServerSecret(secret)
 	if err != nil {
 		return false, err
 	}
 
-	expiring, _, err := pair.IsExpiring()
+	expiring, _, err := pair.IsExpiringWithin(validity.ExpiringCheckThreshold)
 	if err != nil {
 		return false, err
 	}
@@ -122,7 +129,7 @@ func RenewLeafCertificate(caSecret *v1.Secret, secret *v1.Secret) (bool, error)
 		return false, err
 	}
 
-	err = pair.RenewCertificate(caPrivateKey, caCertificate)
+	err = pair.RenewCertificateWithDuration(caPrivateKey, caCertificate, validity.Duration)
 	if err != nil {
 		return false, err
 	}
@@ -349,6 +349,48 @@ func MoveDirectoryContent(sourceDirectory, destinationDirectory string) error {
 	return RemoveDirectoryContent(sourceDirectory)
 }
 
+// CopyDirectory copies the content of a directory into the destination
+// one, creating it when needed. Regular files keep their permissions,
+// and symbolic links are recreated as they are
+func CopyDirectory(sourceDirectory, destinationDirectory string) error {
+	return filepath.WalkDir(sourceDirectory, func(sourcePath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(sourceDirectory, sourcePath)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(destinationDirectory, relativePath)
+
+		switch {
+		case entry.IsDir():
+			return EnsureDirectoryExist(destinationPath)
+
+		case entry.Type()&os.ModeSymlink != 0:
+			target, err := os.Readlink(sourcePath)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, destinationPath)
+
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if err := CopyFile(sourcePath, destinationPath); err != nil {
+				return err
+			}
+			return os.Chmod(destinationPath, info.Mode().Perm())
+
+		default:
+			return fmt.Errorf("cannot copy %s: unsupported file type", sourcePath)
+		}
+	})
+}
+
 // GetFileSize returns the size of a file or an error
 func GetFileSize(fileName string) (int64, error) {
 	stat, err := os.Stat(fileName)
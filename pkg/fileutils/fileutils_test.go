@@ -126,6 +126,38 @@ var _ = Describe("File copying functions", func() {
 	})
 })
 
+var _ = Describe("function CopyDirectory", func() {
+	It("copies the files, the directories and the links", func() {
+		source := GinkgoT().TempDir()
+		destination := filepath.Join(GinkgoT().TempDir(), "copy")
+
+		_, err := WriteStringToFile(filepath.Join(source, "extension", "vector.control"), "comment = 'vector'")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = WriteStringToFile(filepath.Join(source, "vector.so"), "library")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Chmod(filepath.Join(source, "vector.so"), 0o755)).To(Succeed())
+		Expect(os.Symlink("vector.so", filepath.Join(source, "vector.so.0"))).To(Succeed())
+
+		Expect(CopyDirectory(source, destination)).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(destination, "extension", "vector.control")) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("comment = 'vector'"))
+
+		info, err := os.Stat(filepath.Join(destination, "vector.so"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o755)))
+
+		target, err := os.Readlink(filepath.Join(destination, "vector.so.0"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal("vector.so"))
+	})
+
+	It("fails when the source directory doesn't exist", func() {
+		Expect(CopyDirectory(filepath.Join(tempDir1, "missing"), GinkgoT().TempDir())).ToNot(Succeed())
+	})
+})
+
 var _ = Describe("function GetDirectoryContent", func() {
 	It("returns error if directory doesn't exist", func() {
 		_, err := GetDirectoryContent(filepath.Join(tempDir3, "not-exists"))
@@ -0,0 +1,277 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// maxStatusAge is the age after which a cached status is not used
+	// anymore. The instance managers send their status at least every
+	// ten seconds, even when it doesn't change
+	maxStatusAge = 15 * time.Second
+
+	// retryDelay is the time waited before watching again an
+	// instance whose stream was interrupted
+	retryDelay = 5 * time.Second
+
+	// connectionTimeout prevents waiting for the default TCP
+	// connection timeout on lost SYN packets
+	connectionTimeout = 2 * time.Second
+)
+
+var watcherLog = log.WithName("instance-status-watcher")
+
+// Watcher keeps the status of the instances streamed by their instance
+// managers, notifying the clusters whose instances changed status in a
+// way requiring a reconciliation
+type Watcher struct {
+	httpClient *http.Client
+	events     chan event.GenericEvent
+	watchURL   func(podIP string) string
+
+	mu      sync.Mutex
+	ctx     context.Context
+	watches map[types.NamespacedName]*podWatch
+}
+
+// podWatch is the watch of the status of a single instance
+type podWatch struct {
+	cluster types.NamespacedName
+	podIP   string
+	cancel  context.CancelFunc
+
+	// These fields are protected by the mutex of the watcher
+	status     *postgres.PostgresqlStatus
+	receivedAt time.Time
+}
+
+// NewWatcher creates a new watcher of the instance status
+func NewWatcher() *Watcher {
+	return &Watcher{
+		// The streams have no timeout, as they are kept open
+		// as long as the instance is running
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout: connectionTimeout,
+				}).DialContext,
+			},
+		},
+		events: make(chan event.GenericEvent),
+		watchURL: func(podIP string) string {
+			return url.Build(podIP, url.PathPgStatusWatch, url.StatusPort)
+		},
+		watches: make(map[types.NamespacedName]*podWatch),
+	}
+}
+
+// Start implements the manager.Runnable interface, and keeps the
+// watches running until the passed context is cancelled
+func (w *Watcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	w.ctx = ctx
+	w.mu.Unlock()
+
+	<-ctx.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, watch := range w.watches {
+		watch.cancel()
+		delete(w.watches, key)
+	}
+	return nil
+}
+
+// Events gets the channel receiving an event for every cluster needing
+// to be reconciled because of a change in the status of its instances
+func (w *Watcher) Events() <-chan event.GenericEvent {
+	return w.events
+}
+
+// Sync aligns the watches of the instances of a cluster with the passed
+// Pods, starting the missing ones and stopping the ones of the Pods which
+// are gone or have been assigned a different IP
+func (w *Watcher) Sync(cluster types.NamespacedName, pods []corev1.Pod) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Until the watcher has been started there's
+	// no context to run the watches with
+	if w.ctx == nil {
+		return
+	}
+
+	desiredPods := make(map[types.NamespacedName]corev1.Pod, len(pods))
+	for _, pod := range pods {
+		if pod.Status.PodIP != "" {
+			desiredPods[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = pod
+		}
+	}
+
+	for key, watch := range w.watches {
+		if watch.cluster != cluster {
+			continue
+		}
+		if pod, found := desiredPods[key]; found && pod.Status.PodIP == watch.podIP {
+			delete(desiredPods, key)
+			continue
+		}
+		watch.cancel()
+		delete(w.watches, key)
+	}
+
+	for key, pod := range desiredPods {
+		ctx, cancel := context.WithCancel(w.ctx)
+		watch := &podWatch{
+			cluster: cluster,
+			podIP:   pod.Status.PodIP,
+			cancel:  cancel,
+		}
+		w.watches[key] = watch
+		go w.watch(ctx, key, watch)
+	}
+}
+
+// Get gets the status of an instance as streamed by its instance manager.
+// The status is not returned when the Pod is not watched, or when its
+// status is not recent enough to be trusted
+func (w *Watcher) Get(pod corev1.Pod) (postgres.PostgresqlStatus, bool) {
+	if w == nil {
+		return postgres.PostgresqlStatus{}, false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watch, found := w.watches[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+	if !found || watch.podIP != pod.Status.PodIP || watch.status == nil ||
+		time.Since(watch.receivedAt) > maxStatusAge {
+		return postgres.PostgresqlStatus{}, false
+	}
+
+	return *watch.status, true
+}
+
+// watch keeps the stream of the status of an instance open, until the
+// passed context is cancelled
+func (w *Watcher) watch(ctx context.Context, key types.NamespacedName, watch *podWatch) {
+	contextLogger := watcherLog.WithValues("pod", key.Name, "namespace", key.Namespace)
+	for {
+		err := w.stream(ctx, watch)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// The instance may have failed: the operator will poll its
+		// status until the stream is opened again
+		contextLogger.Debug("Instance status watch interrupted", "err", err)
+		w.setStatus(ctx, watch, nil)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// stream reads the status streamed by an instance, returning when the
+// stream is interrupted or is not receiving anything anymore
+func (w *Watcher) stream(ctx context.Context, watch *podWatch) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, w.watchURL(watch.podIP), nil)
+	if err != nil {
+		return err
+	}
+
+	// An instance manager not sending its heartbeat is stuck,
+	// and there's no point in keeping its stream open
+	idleTimer := time.AfterFunc(maxStatusAge, cancel)
+	defer idleTimer.Stop()
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var status postgres.PostgresqlStatus
+		if err := decoder.Decode(&status); err != nil {
+			return err
+		}
+		idleTimer.Reset(maxStatusAge)
+		w.setStatus(ctx, watch, &status)
+	}
+}
+
+// setStatus stores the status received from an instance, or invalidates
+// it when nil, notifying the cluster if the change requires a reconciliation
+func (w *Watcher) setStatus(ctx context.Context, watch *podWatch, status *postgres.PostgresqlStatus) {
+	w.mu.Lock()
+	previous := watch.status
+	watch.status = status
+	watch.receivedAt = time.Now()
+	w.mu.Unlock()
+
+	if previous == nil && status == nil {
+		return
+	}
+	if previous != nil && status != nil && status.IsEquivalentTo(*previous) {
+		return
+	}
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: watch.cluster.Namespace, Name: watch.cluster.Name},
+	}
+	select {
+	case w.events <- event.GenericEvent{Object: cluster}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestatus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instance status watcher", func() {
+	var (
+		watcher  *Watcher
+		server   *httptest.Server
+		cancel   context.CancelFunc
+		statuses chan postgres.PostgresqlStatus
+	)
+
+	clusterKey := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster-example-1"},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+	}
+
+	BeforeEach(func() {
+		statuses = make(chan postgres.PostgresqlStatus)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoder := json.NewEncoder(w)
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case status := <-statuses:
+					Expect(encoder.Encode(status)).To(Succeed())
+					w.(http.Flusher).Flush()
+				}
+			}
+		}))
+
+		watcher = NewWatcher()
+		watcher.watchURL = func(podIP string) string {
+			Expect(podIP).To(Equal("10.0.0.1"))
+			return server.URL
+		}
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Expect(watcher.Start(ctx)).To(Succeed())
+		}()
+		Eventually(func() bool {
+			watcher.mu.Lock()
+			defer watcher.mu.Unlock()
+			return watcher.ctx != nil
+		}).Should(BeTrue())
+	})
+
+	AfterEach(func() {
+		cancel()
+		server.CloseClientConnections()
+		server.Close()
+	})
+
+	It("caches the streamed status and notifies the relevant changes", func() {
+		watcher.Sync(clusterKey, []corev1.Pod{pod})
+		_, found := watcher.Get(pod)
+		Expect(found).To(BeFalse())
+
+		statuses <- postgres.PostgresqlStatus{IsPrimary: true, CurrentLsn: "0/1000000"}
+		var evt event.GenericEvent
+		Eventually(watcher.Events()).Should(Receive(&evt))
+		Expect(evt.Object.GetNamespace()).To(Equal(clusterKey.Namespace))
+		Expect(evt.Object.GetName()).To(Equal(clusterKey.Name))
+
+		Eventually(func() postgres.LSN {
+			status, _ := watcher.Get(pod)
+			return status.CurrentLsn
+		}).Should(Equal(postgres.LSN("0/1000000")))
+
+		// The progress of the WAL positions doesn't need a reconciliation
+		statuses <- postgres.PostgresqlStatus{IsPrimary: true, CurrentLsn: "0/2000000"}
+		Eventually(func() postgres.LSN {
+			status, _ := watcher.Get(pod)
+			return status.CurrentLsn
+		}).Should(Equal(postgres.LSN("0/2000000")))
+		Consistently(watcher.Events()).ShouldNot(Receive())
+
+		statuses <- postgres.PostgresqlStatus{IsPrimary: false, CurrentLsn: "0/2000000"}
+		Eventually(watcher.Events()).Should(Receive())
+	})
+
+	It("stops watching the Pods which are gone or changed IP", func() {
+		watcher.Sync(clusterKey, []corev1.Pod{pod})
+		statuses <- postgres.PostgresqlStatus{IsPrimary: true}
+		Eventually(watcher.Events()).Should(Receive())
+		Eventually(func() bool {
+			_, found := watcher.Get(pod)
+			return found
+		}).Should(BeTrue())
+
+		movedPod := pod.DeepCopy()
+		movedPod.Status.PodIP = "10.0.0.2"
+		_, found := watcher.Get(*movedPod)
+		Expect(found).To(BeFalse())
+
+		watcher.Sync(clusterKey, nil)
+		_, found = watcher.Get(pod)
+		Expect(found).To(BeFalse())
+	})
+
+	It("is a no-op when not enabled", func() {
+		var disabled *Watcher
+		disabled.Sync(clusterKey, []corev1.Pod{pod})
+		_, found := disabled.Get(pod)
+		Expect(found).To(BeFalse())
+	})
+})
@@ -27,13 +27,18 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/checksum"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/spool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 const (
@@ -52,6 +57,9 @@ type WALArchiver struct {
 	// The spool of WAL files to be archived in parallel
 	spool *spool.WALSpool
 
+	// The checksums of the WAL files that have been archived
+	checksums *checksum.Journal
+
 	// The environment that should be used to invoke barman-cloud-wal-archive
 	env []string
 
@@ -79,19 +87,27 @@ func New(
 	cluster *apiv1.Cluster,
 	env []string,
 	spoolDirectory string,
+	checksumDirectory string,
 	pgDataDirectory string,
 ) (archiver *WALArchiver, err error) {
 	contextLog := log.FromContext(ctx)
 	var walArchiveSpool *spool.WALSpool
+	var checksums *checksum.Journal
 
 	if walArchiveSpool, err = spool.New(spoolDirectory); err != nil {
 		contextLog.Info("Cannot initialize the WAL spool", "spoolDirectory", spoolDirectory)
 		return nil, fmt.Errorf("while creating spool directory: %w", err)
 	}
 
+	if checksums, err = checksum.NewJournal(checksumDirectory); err != nil {
+		contextLog.Info("Cannot initialize the checksum journal", "checksumDirectory", checksumDirectory)
+		return nil, fmt.Errorf("while creating checksum directory: %w", err)
+	}
+
 	archiver = &WALArchiver{
 		cluster:         cluster,
 		spool:           walArchiveSpool,
+		checksums:       checksums,
 		env:             env,
 		pgDataDirectory: pgDataDirectory,
 	}
@@ -128,7 +144,13 @@ func (archiver *WALArchiver) ArchiveList(
 			walStatus := &result[walIndex]
 			walStatus.WalName = walNames[walIndex]
 			walStatus.StartTime = time.Now()
-			walStatus.Err = archiver.Archive(walNames[walIndex], options)
+			walStatus.Err = archiver.runArchiveHook(ctx, walNames[walIndex])
+			if walStatus.Err == nil {
+				_, span := tracing.StartSpan(ctx, barmanCapabilities.BarmanCloudWalArchive,
+					attribute.String("wal.name", walStatus.WalName))
+				walStatus.Err = archiver.Archive(walNames[walIndex], options)
+				tracing.EndSpan(span, walStatus.Err)
+			}
 			walStatus.EndTime = time.Now()
 			if walStatus.Err == nil && walIndex != 0 {
 				walStatus.Err = archiver.spool.Touch(walNames[walIndex])
@@ -178,6 +200,17 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 		"options", options,
 	)
 
+	// WAL segments are immutable once completed, so the checksum we compute
+	// here is the one of the file being uploaded
+	var walChecksum string
+	if postgres.IsWALFile(walName) {
+		var err error
+		if walChecksum, err = checksum.Compute(walName); err != nil {
+			log.Warning("Cannot compute the checksum of the WAL file, skipping it",
+				"walName", walName, "err", err)
+		}
+	}
+
 	barmanCloudWalArchiveCmd := exec.Command(barmanCapabilities.BarmanCloudWalArchive, options...) // #nosec G204
 	barmanCloudWalArchiveCmd.Env = archiver.env
 
@@ -193,6 +226,13 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 		return fmt.Errorf("unexpected failure invoking %s: %w", barmanCapabilities.BarmanCloudWalArchive, err)
 	}
 
+	if walChecksum != "" {
+		if err := archiver.checksums.Record(walName, walChecksum); err != nil {
+			log.Warning("Cannot record the checksum of the WAL file",
+				"walName", walName, "err", err)
+		}
+	}
+
 	// Removes the `.check-empty-wal-archive` file inside PGDATA after the
 	// first successful archival of a WAL file.
 	filePath := path.Join(archiver.pgDataDirectory, CheckEmptyWalArchiveFile)
@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/compatibility"
+)
+
+// walArchiveHookName is the name used in the logs of the archive hook
+const walArchiveHookName = "wal-archive-hook"
+
+// getArchiveHook gets the archive hook configured in the cluster, if any
+func (archiver *WALArchiver) getArchiveHook() *apiv1.WalArchiveHook {
+	backup := archiver.cluster.Spec.Backup
+	if backup == nil || backup.BarmanObjectStore == nil || backup.BarmanObjectStore.Wal == nil {
+		return nil
+	}
+
+	return backup.BarmanObjectStore.Wal.ArchiveHook
+}
+
+// runArchiveHook executes the archive hook on a WAL file. The failures
+// of a best-effort hook are logged and never reported to the caller,
+// so that they can't block the archiving of the WAL file
+func (archiver *WALArchiver) runArchiveHook(ctx context.Context, walName string) error {
+	hook := archiver.getArchiveHook()
+	if hook == nil {
+		return nil
+	}
+
+	err := runWalArchiveHook(hook, archiver.pgDataDirectory, walName)
+	if err == nil {
+		return nil
+	}
+
+	if hook.IsBestEffort() {
+		log.FromContext(ctx).Warning("WAL archive hook failed, archiving the WAL file anyway",
+			"walName", walName,
+			"error", err.Error())
+		return nil
+	}
+
+	return err
+}
+
+// runWalArchiveHook executes the command of the hook in the PGDATA
+// directory, passing it the WAL file name. When the timeout expires the
+// command is killed together with the processes it spawned. The environment
+// of the instance manager is inherited, but not the credentials of the
+// object store
+func runWalArchiveHook(hook *apiv1.WalArchiveHook, pgDataDirectory string, walName string) error {
+	if len(hook.Command) == 0 || hook.Command[0] == "" {
+		return errors.New("empty WAL archive hook command")
+	}
+
+	args := make([]string, 0, len(hook.Command))
+	args = append(args, hook.Command[1:]...)
+	args = append(args, walName)
+
+	hookCmd := exec.Command(hook.Command[0], args...) // #nosec G204
+	hookCmd.Dir = pgDataDirectory
+	compatibility.AddInstanceRunCommands(hookCmd)
+
+	streamingCmd, err := execlog.RunStreamingNoWait(hookCmd, walArchiveHookName)
+	if err != nil {
+		return fmt.Errorf("while starting the WAL archive hook for %s: %w", walName, err)
+	}
+
+	timeout := hook.GetTimeout()
+	timer := time.AfterFunc(timeout, func() {
+		_ = compatibility.KillProcessGroup(hookCmd.Process)
+	})
+	err = streamingCmd.Wait()
+	if !timer.Stop() {
+		return fmt.Errorf("WAL archive hook for %s timed out after %v", walName, timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("while running the WAL archive hook for %s: %w", walName, err)
+	}
+
+	return nil
+}
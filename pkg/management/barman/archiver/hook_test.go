@@ -0,0 +1,100 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archive hook", func() {
+	var pgData string
+
+	newArchiver := func(hook *apiv1.WalArchiveHook) *WALArchiver {
+		return &WALArchiver{
+			cluster: &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					Backup: &apiv1.BackupConfiguration{
+						BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+							Wal: &apiv1.WalBackupConfiguration{
+								ArchiveHook: hook,
+							},
+						},
+					},
+				},
+			},
+			pgDataDirectory: pgData,
+		}
+	}
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+	})
+
+	It("does nothing when no hook is configured", func() {
+		archiver := &WALArchiver{cluster: &apiv1.Cluster{}, pgDataDirectory: pgData}
+		Expect(archiver.runArchiveHook(context.Background(), "pg_wal/000000010000000000000001")).To(Succeed())
+	})
+
+	It("passes the WAL file to the hook running in PGDATA", func() {
+		archiver := newArchiver(&apiv1.WalArchiveHook{
+			Command: []string{"sh", "-c", `echo "$1" > hook.out`, "hook"},
+		})
+		Expect(archiver.runArchiveHook(context.Background(), "pg_wal/000000010000000000000001")).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(pgData, "hook.out")) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("pg_wal/000000010000000000000001\n"))
+	})
+
+	It("reports the failures of the hook", func() {
+		archiver := newArchiver(&apiv1.WalArchiveHook{
+			Command:       []string{"false"},
+			FailurePolicy: apiv1.WalArchiveHookFailurePolicyFail,
+		})
+		Expect(archiver.runArchiveHook(context.Background(), "pg_wal/000000010000000000000001")).ToNot(Succeed())
+	})
+
+	It("kills the hook when the timeout expires", func() {
+		archiver := newArchiver(&apiv1.WalArchiveHook{
+			Command: []string{"sh", "-c", "sleep 10", "hook"},
+			Timeout: 1,
+		})
+		err := archiver.runArchiveHook(context.Background(), "pg_wal/000000010000000000000001")
+		Expect(err).To(MatchError(ContainSubstring("timed out")))
+	})
+
+	It("ignores the failures of a best-effort hook", func() {
+		archiver := newArchiver(&apiv1.WalArchiveHook{
+			Command:       []string{"false"},
+			FailurePolicy: apiv1.WalArchiveHookFailurePolicyBestEffort,
+		})
+		Expect(archiver.runArchiveHook(context.Background(), "pg_wal/000000010000000000000001")).To(Succeed())
+
+		archiver = newArchiver(&apiv1.WalArchiveHook{
+			Command:       []string{"/nonexistent/hook"},
+			FailurePolicy: apiv1.WalArchiveHookFailurePolicyBestEffort,
+		})
+		Expect(archiver.runArchiveHook(context.Background(), "pg_wal/000000010000000000000001")).To(Succeed())
+	})
+})
@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checksum keeps track of the checksums of the WAL files
+// uploaded to the object store, so that they can be verified later
+package checksum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+)
+
+// Compute computes the SHA-256 checksum of a file, returning it
+// as an hex-encoded string
+func Compute(fileName string) (string, error) {
+	f, err := os.Open(filepath.Clean(fileName))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("while reading %s: %w", fileName, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Journal stores the checksums of the archived WAL files.
+// It works using a directory, under which we create a file carrying
+// the name of the WAL we archived and containing its checksum
+type Journal struct {
+	directory string
+}
+
+// NewJournal creates a new checksum journal
+func NewJournal(directory string) (*Journal, error) {
+	if err := fileutils.EnsureDirectoryExist(directory); err != nil {
+		return nil, fmt.Errorf("while creating checksum directory: %w", err)
+	}
+
+	return &Journal{
+		directory: directory,
+	}, nil
+}
+
+// Record stores the checksum of a WAL file
+func (journal *Journal) Record(walFile, checksum string) error {
+	fileName := path.Join(journal.directory, path.Base(walFile))
+	_, err := fileutils.WriteStringToFile(fileName, checksum)
+	return err
+}
+
+// Get retrieves the checksum of a WAL file, returning an empty
+// string if it has not been recorded
+func (journal *Journal) Get(walFile string) (string, error) {
+	content, err := fileutils.ReadFile(path.Join(journal.directory, path.Base(walFile)))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// Prune removes the checksums of the WAL files preceding the passed one
+func (journal *Journal) Prune(walFile string) error {
+	files, err := fileutils.GetDirectoryContent(journal.directory)
+	if err != nil {
+		return err
+	}
+
+	walFile = strings.ToUpper(path.Base(walFile))
+	for _, file := range files {
+		if strings.ToUpper(file) >= walFile {
+			continue
+		}
+		if err := fileutils.RemoveFile(path.Join(journal.directory, file)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
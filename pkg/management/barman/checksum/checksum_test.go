@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checksum journal", func() {
+	var tmpDir string
+	var journal *Journal
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "checksum-test-")
+		Expect(err).NotTo(HaveOccurred())
+
+		journal, err = NewJournal(path.Join(tmpDir, "journal"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("computes the SHA-256 checksum of a file", func() {
+		fileName := path.Join(tmpDir, "000000010000000000000001")
+		Expect(os.WriteFile(fileName, []byte("hello"), 0o600)).To(Succeed())
+		Expect(Compute(fileName)).To(Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"))
+
+		_, err := Compute(path.Join(tmpDir, "missing"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("records and retrieves the checksums of the WAL files", func() {
+		Expect(journal.Get("000000010000000000000001")).To(BeEmpty())
+
+		Expect(journal.Record("pg_wal/000000010000000000000001", "abc")).To(Succeed())
+		Expect(journal.Get("000000010000000000000001")).To(Equal("abc"))
+	})
+
+	It("prunes the checksums of the WAL files preceding a given one", func() {
+		Expect(journal.Record("000000010000000000000001", "a")).To(Succeed())
+		Expect(journal.Record("000000010000000000000002", "b")).To(Succeed())
+		Expect(journal.Record("000000010000000000000003", "c")).To(Succeed())
+
+		Expect(journal.Prune("000000010000000000000002")).To(Succeed())
+		Expect(journal.Get("000000010000000000000001")).To(BeEmpty())
+		Expect(journal.Get("000000010000000000000002")).To(Equal("b"))
+		Expect(journal.Get("000000010000000000000003")).To(Equal("c"))
+	})
+})
@@ -26,11 +26,14 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/spool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 )
 
 const (
@@ -173,9 +176,12 @@ func (restorer *WALRestorer) RestoreList(
 				result.DestinationPath = restorer.spool.FileName(result.WalName)
 			}
 
+			_, span := tracing.StartSpan(ctx, barmanCapabilities.BarmanCloudWalRestore,
+				attribute.String("wal.name", result.WalName))
 			result.StartTime = time.Now()
 			result.Err = restorer.Restore(fetchList[walIndex], result.DestinationPath, options)
 			result.EndTime = time.Now()
+			tracing.EndSpan(span, result.Err)
 
 			elapsedWalTime := result.EndTime.Sub(result.StartTime)
 			if result.Err == nil {
@@ -0,0 +1,156 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// runtimeConfiguration contains the part of the logging
+// configuration that can be changed without restarting the process
+type runtimeConfiguration struct {
+	sync.RWMutex
+
+	// the level chosen via the command line flags
+	level zapcore.Level
+
+	// the level enabled in the backing zap logger, which is the most verbose
+	// level between the default one and the ones of the loggers
+	zapLevel zap.AtomicLevel
+
+	// the static fields added to every log record, as a list of key-value pairs
+	fields []interface{}
+
+	// the level of specific loggers, overriding the default one
+	loggerLevels map[string]zapcore.Level
+}
+
+var currentConfiguration = &runtimeConfiguration{
+	level:    DefaultLevel,
+	zapLevel: zap.NewAtomicLevelAt(DefaultLevel),
+}
+
+// IsValidLevel checks if the passed string is a known log level
+func IsValidLevel(level string) bool {
+	switch level {
+	case ErrorLevelString,
+		WarningLevelString,
+		InfoLevelString,
+		DebugLevelString,
+		TraceLevelString:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetFields sets the static fields that will be added to every log record
+func SetFields(fields map[string]string) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	keysAndValues := make([]interface{}, 0, 2*len(fields))
+	for _, key := range keys {
+		keysAndValues = append(keysAndValues, key, fields[key])
+	}
+
+	currentConfiguration.Lock()
+	defer currentConfiguration.Unlock()
+	currentConfiguration.fields = keysAndValues
+}
+
+// SetLoggerLevels sets the level of the loggers having the passed names,
+// overriding the default level. The records produced by the children of
+// these loggers are filtered in the same way
+func SetLoggerLevels(levels map[string]string) {
+	currentConfiguration.Lock()
+	defer currentConfiguration.Unlock()
+
+	currentConfiguration.loggerLevels = make(map[string]zapcore.Level, len(levels))
+	for name, level := range levels {
+		if IsValidLevel(level) {
+			currentConfiguration.loggerLevels[name] = getLogLevel(level)
+		}
+	}
+
+	currentConfiguration.refreshZapLevel()
+}
+
+// setLevel sets the default log level
+func (c *runtimeConfiguration) setLevel(level zapcore.Level) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.level = level
+	c.refreshZapLevel()
+}
+
+// refreshZapLevel enables in zap the most verbose level required
+// by the loggers. The caller must hold the lock
+func (c *runtimeConfiguration) refreshZapLevel() {
+	zapLevel := c.level
+	for _, loggerLevel := range c.loggerLevels {
+		if loggerLevel < zapLevel {
+			zapLevel = loggerLevel
+		}
+	}
+
+	c.zapLevel.SetLevel(zapLevel)
+}
+
+// getFields gets the static fields to be added to every log record
+func (c *runtimeConfiguration) getFields() []interface{} {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.fields
+}
+
+// isEnabled checks if a record at the passed level should be
+// produced by the logger with the passed name
+func (c *runtimeConfiguration) isEnabled(name string, level zapcore.Level) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	// Without overrides, filtering the records is the job of zap
+	if len(c.loggerLevels) == 0 {
+		return true
+	}
+
+	// Look for the logger or the nearest of its parents
+	for name != "" {
+		if loggerLevel, ok := c.loggerLevels[name]; ok {
+			return level >= loggerLevel
+		}
+
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			break
+		}
+		name = name[:idx]
+	}
+
+	return level >= c.level
+}
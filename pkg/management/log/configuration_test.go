@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("runtime logging configuration", func() {
+	var configuration *runtimeConfiguration
+
+	BeforeEach(func() {
+		configuration = &runtimeConfiguration{
+			level:    InfoLevel,
+			zapLevel: zap.NewAtomicLevelAt(InfoLevel),
+		}
+	})
+
+	It("leaves the filtering to zap when there are no logger levels", func() {
+		Expect(configuration.isEnabled("postgres", DebugLevel)).To(BeTrue())
+		Expect(configuration.isEnabled("", TraceLevel)).To(BeTrue())
+	})
+
+	It("filters the records using the level of the logger or of its parents", func() {
+		configuration.loggerLevels = map[string]zapcore.Level{
+			"postgres": ErrorLevel,
+			"barman":   DebugLevel,
+		}
+		configuration.refreshZapLevel()
+
+		Expect(configuration.isEnabled("postgres", InfoLevel)).To(BeFalse())
+		Expect(configuration.isEnabled("postgres", ErrorLevel)).To(BeTrue())
+		Expect(configuration.isEnabled("barman", DebugLevel)).To(BeTrue())
+		Expect(configuration.isEnabled("barman.backup", DebugLevel)).To(BeTrue())
+		Expect(configuration.isEnabled("barman.backup", TraceLevel)).To(BeFalse())
+	})
+
+	It("uses the default level for the other loggers", func() {
+		configuration.loggerLevels = map[string]zapcore.Level{
+			"barman": DebugLevel,
+		}
+		configuration.refreshZapLevel()
+
+		Expect(configuration.zapLevel.Level()).To(Equal(DebugLevel))
+		Expect(configuration.isEnabled("setup", DebugLevel)).To(BeFalse())
+		Expect(configuration.isEnabled("setup", InfoLevel)).To(BeTrue())
+		Expect(configuration.isEnabled("", DebugLevel)).To(BeFalse())
+	})
+
+	It("doesn't make zap less verbose than the default level", func() {
+		configuration.loggerLevels = map[string]zapcore.Level{
+			"postgres": ErrorLevel,
+		}
+		configuration.refreshZapLevel()
+		Expect(configuration.zapLevel.Level()).To(Equal(InfoLevel))
+	})
+})
+
+var _ = Describe("static fields", func() {
+	AfterEach(func() {
+		SetFields(nil)
+	})
+
+	It("are stored as key-value pairs sorted by key", func() {
+		SetFields(map[string]string{
+			"team":        "payments",
+			"environment": "production",
+		})
+		Expect(currentConfiguration.getFields()).To(Equal([]interface{}{
+			"environment", "production",
+			"team", "payments",
+		}))
+	})
+})
+
+var _ = Describe("log levels", func() {
+	It("recognizes the valid log levels", func() {
+		Expect(IsValidLevel(InfoLevelString)).To(BeTrue())
+		Expect(IsValidLevel(TraceLevelString)).To(BeTrue())
+		Expect(IsValidLevel("verbose")).To(BeFalse())
+		Expect(IsValidLevel("")).To(BeFalse())
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"os"
+	"sync"
+)
+
+// logCopyMaxSize is the size, in bytes, after which the copy of
+// the log stream is rotated
+const logCopyMaxSize = 10 * 1024 * 1024
+
+// rotatingFile is a file receiving a copy of the log stream. When it
+// grows over the maximum size, it is renamed adding the `.1` suffix,
+// replacing the previous one, and a new file is started. Writing the
+// copy is best effort and never interrupts the log stream
+type rotatingFile struct {
+	mutex   sync.Mutex
+	name    string
+	maxSize int64
+	file    *os.File
+	size    int64
+}
+
+// newRotatingFile creates a rotating file with the passed name
+func newRotatingFile(name string, maxSize int64) *rotatingFile {
+	return &rotatingFile{name: name, maxSize: maxSize}
+}
+
+// Write implements the io.Writer interface
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file != nil && f.size+int64(len(p)) > f.maxSize {
+		_ = f.file.Close()
+		f.file = nil
+		_ = os.Rename(f.name, f.name+".1")
+	}
+
+	if f.file == nil {
+		file, err := os.OpenFile(f.name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644) //#nosec
+		if err != nil {
+			return len(p), nil
+		}
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return len(p), nil
+		}
+		f.file = file
+		f.size = info.Size()
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		_ = f.file.Close()
+		f.file = nil
+	}
+	return len(p), nil
+}
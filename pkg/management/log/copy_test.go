@@ -0,0 +1,54 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("copy of the log stream", func() {
+	var fileName string
+
+	BeforeEach(func() {
+		fileName = filepath.Join(GinkgoT().TempDir(), "instance.json")
+	})
+
+	It("appends the records to the file", func() {
+		file := newRotatingFile(fileName, 1024)
+		Expect(file.Write([]byte("first\n"))).To(Equal(6))
+		Expect(file.Write([]byte("second\n"))).To(Equal(7))
+		Expect(os.ReadFile(fileName)).To(BeEquivalentTo("first\nsecond\n"))
+	})
+
+	It("rotates the file when it grows over the maximum size", func() {
+		file := newRotatingFile(fileName, 10)
+		Expect(file.Write([]byte("first\n"))).To(Equal(6))
+		Expect(file.Write([]byte("second\n"))).To(Equal(7))
+		Expect(file.Write([]byte("third\n"))).To(Equal(6))
+		Expect(os.ReadFile(fileName)).To(BeEquivalentTo("third\n"))
+		Expect(os.ReadFile(fileName + ".1")).To(BeEquivalentTo("second\n"))
+	})
+
+	It("doesn't interrupt the log stream when the file can't be written", func() {
+		file := newRotatingFile(filepath.Join(fileName, "missing", "instance.json"), 1024)
+		Expect(file.Write([]byte("first\n"))).To(Equal(6))
+	})
+})
@@ -19,6 +19,7 @@ package log
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/pflag"
@@ -35,8 +36,9 @@ type Flags struct {
 }
 
 var (
-	logLevel       string
-	logDestination string
+	logLevel           string
+	logDestination     string
+	logCopyDestination string
 )
 
 // AddFlags binds manager configuration flags to a given flagset
@@ -46,6 +48,8 @@ func (l *Flags) AddFlags(flags *pflag.FlagSet) {
 		"the desired log level, one of error, info, debug and trace")
 	loggingFlagSet.StringVar(&logDestination, "log-destination", "",
 		"where the log stream will be written")
+	loggingFlagSet.StringVar(&logCopyDestination, "log-copy-destination", "",
+		"a file where a copy of the log stream will be written, rotated when it grows over 10MiB")
 	l.zapOptions.BindFlags(loggingFlagSet)
 	flags.AddGoFlagSet(loggingFlagSet)
 }
@@ -54,14 +58,7 @@ func (l *Flags) AddFlags(flags *pflag.FlagSet) {
 // passed from the user
 func (l *Flags) ConfigureLogging() {
 	logger := zap.New(zap.UseFlagOptions(&l.zapOptions), customLevel, customDestination)
-	switch logLevel {
-	case ErrorLevelString,
-		WarningLevelString,
-		InfoLevelString,
-		DebugLevelString,
-		TraceLevelString:
-		break
-	default:
+	if !IsValidLevel(logLevel) {
 		logger.Info("Invalid log level, defaulting", "level", logLevel, "default", DefaultLevel)
 	}
 
@@ -105,7 +102,9 @@ func getLogLevelString(l zapcore.Level) string {
 }
 
 func customLevel(in *zap.Options) {
-	in.Level = getLogLevel(logLevel)
+	// The level is atomic, as the loggers can be made more verbose at runtime
+	currentConfiguration.setLevel(getLogLevel(logLevel))
+	in.Level = currentConfiguration.zapLevel
 	in.EncoderConfigOptions = append(in.EncoderConfigOptions, func(c *zapcore.EncoderConfig) {
 		c.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 			enc.AppendString(getLogLevelString(l))
@@ -114,14 +113,21 @@ func customLevel(in *zap.Options) {
 }
 
 func customDestination(in *zap.Options) {
-	if logDestination == "" {
-		return
-	}
+	if logDestination != "" {
+		logStream, err := os.OpenFile(logDestination, os.O_RDWR|os.O_CREATE, 0o666) //#nosec
+		if err != nil {
+			panic(fmt.Sprintf("Cannot open log destination %v: %v", logDestination, err))
+		}
 
-	logStream, err := os.OpenFile(logDestination, os.O_RDWR|os.O_CREATE, 0o666) //#nosec
-	if err != nil {
-		panic(fmt.Sprintf("Cannot open log destination %v: %v", logDestination, err))
+		in.DestWriter = logStream
 	}
 
-	in.DestWriter = logStream
+	if logCopyDestination != "" {
+		destination := in.DestWriter
+		if destination == nil {
+			destination = os.Stderr
+		}
+
+		in.DestWriter = io.MultiWriter(destination, newRotatingFile(logCopyDestination, logCopyMaxSize))
+	}
 }
@@ -69,6 +69,9 @@ type logger struct {
 	logr.Logger
 
 	printCaller bool
+
+	// the name of the logger, whose components are separated by dots
+	name string
 }
 
 // Log is the logger that will be used in this package
@@ -112,6 +115,14 @@ func (l *logger) enrich(forceCaller bool) logr.Logger {
 		cl = cl.WithValues("logging_pod", podName)
 	}
 
+	if clusterName := os.Getenv("CLUSTER_NAME"); clusterName != "" {
+		cl = cl.WithValues("logging_cluster", clusterName)
+	}
+
+	if fields := currentConfiguration.getFields(); len(fields) > 0 {
+		cl = cl.WithValues(fields...)
+	}
+
 	return cl
 }
 
@@ -124,35 +135,54 @@ func (l *logger) Enabled() bool {
 }
 
 func (l *logger) Error(err error, msg string, keysAndValues ...interface{}) {
+	if !currentConfiguration.isEnabled(l.name, ErrorLevel) {
+		return
+	}
 	l.enrich(false).V(int(-ErrorLevel)).Error(err, msg, keysAndValues...)
 }
 
 func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	if !currentConfiguration.isEnabled(l.name, InfoLevel) {
+		return
+	}
 	l.enrich(false).V(int(-InfoLevel)).Info(msg, keysAndValues...)
 }
 
 func (l *logger) Warning(msg string, keysAndValues ...interface{}) {
+	if !currentConfiguration.isEnabled(l.name, WarningLevel) {
+		return
+	}
 	l.enrich(false).V(int(-WarningLevel)).Info(msg, keysAndValues...)
 }
 
 func (l *logger) Debug(msg string, keysAndValues ...interface{}) {
+	if !currentConfiguration.isEnabled(l.name, DebugLevel) {
+		return
+	}
 	l.enrich(true).V(int(-DebugLevel)).Info(msg, keysAndValues...)
 }
 
 func (l *logger) Trace(msg string, keysAndValues ...interface{}) {
+	if !currentConfiguration.isEnabled(l.name, TraceLevel) {
+		return
+	}
 	l.enrich(true).V(int(-TraceLevel)).Info(msg, keysAndValues...)
 }
 
 func (l *logger) WithValues(keysAndValues ...interface{}) Logger {
-	return &logger{Logger: l.Logger.WithValues(keysAndValues...), printCaller: l.printCaller}
+	return &logger{Logger: l.Logger.WithValues(keysAndValues...), printCaller: l.printCaller, name: l.name}
 }
 
 func (l *logger) WithName(name string) Logger {
-	return &logger{Logger: l.Logger.WithName(name), printCaller: l.printCaller}
+	newName := name
+	if l.name != "" {
+		newName = l.name + "." + name
+	}
+	return &logger{Logger: l.Logger.WithName(name), printCaller: l.printCaller, name: newName}
 }
 
 func (l logger) WithCaller() Logger {
-	return &logger{Logger: l.Logger, printCaller: true}
+	return &logger{Logger: l.Logger, printCaller: true, name: l.name}
 }
 
 // Enabled exposes the same method from the logr.Logger interface using the default logger
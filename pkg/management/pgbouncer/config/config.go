@@ -80,7 +80,8 @@ auth_query = {{ .AuthQuery }}
 `
 	pgbouncerHBAFileTemplateString = `
 local pgbouncer pgbouncer peer
-host all all 0.0.0.0/0 md5
+{{ if .ClientCertificateUsers }}hostssl all {{ .ClientCertificateUsers }} 0.0.0.0/0 cert
+{{ end }}host all all 0.0.0.0/0 md5
 `
 
 	pgBouncerUserListTemplateString = `
@@ -165,6 +166,13 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		return nil, fmt.Errorf("unsupported secret type for auth query: %s", secrets.AuthQuery.Type)
 	}
 
+	// PgBouncer can connect on behalf of the users authenticated via
+	// certificate only by presenting its own certificate to PostgreSQL
+	if len(pooler.Spec.PgBouncer.ClientCertificateUsers) > 0 && !isCertAuth {
+		return nil, fmt.Errorf(
+			"client certificate authentication requires a TLS secret for the auth query user")
+	}
+
 	parameters := buildPgBouncerParameters(pooler.Spec.PgBouncer.Parameters)
 
 	if isCertAuth {
@@ -180,6 +188,8 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		AuthQueryUser     string
 		AuthQueryPassword string
 		Parameters        string
+
+		ClientCertificateUsers string
 	}{
 		Pooler:            pooler,
 		AuthQuery:         pooler.GetAuthQuery(),
@@ -193,6 +203,8 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		// Also, we want the list of parameters inside the PgBouncer configuration
 		// to be stable.
 		Parameters: stringifyPgBouncerParameters(parameters),
+
+		ClientCertificateUsers: stringifyClientCertificateUsers(pooler.Spec.PgBouncer.ClientCertificateUsers),
 	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
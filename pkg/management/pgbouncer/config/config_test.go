@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgBouncer configuration files", func() {
+	secrets := &Secrets{
+		AuthQuery: &corev1.Secret{
+			Type: corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("pgbouncer-user"),
+				corev1.BasicAuthPasswordKey: []byte("pgbouncer-password"),
+			},
+		},
+		Client:   &corev1.Secret{},
+		ClientCA: &corev1.Secret{},
+		ServerCA: &corev1.Secret{},
+	}
+
+	newPooler := func(clientCertificateUsers []string) *apiv1.Pooler {
+		return &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRW,
+				PgBouncer: &apiv1.PgBouncerSpec{
+					PoolMode:               apiv1.PgBouncerPoolModeSession,
+					ClientCertificateUsers: clientCertificateUsers,
+				},
+			},
+		}
+	}
+
+	It("uses password authentication by default", func() {
+		files, err := BuildConfigurationFiles(newPooler(nil), secrets)
+		Expect(err).ToNot(HaveOccurred())
+
+		hba := string(files[filepath.Join(ConfigsDir, PgBouncerHBAConfFileName)])
+		Expect(hba).To(ContainSubstring("host all all 0.0.0.0/0 md5"))
+		Expect(hba).ToNot(ContainSubstring("cert"))
+	})
+
+	It("requires a TLS auth query secret to authenticate users via certificate", func() {
+		_, err := BuildConfigurationFiles(newPooler([]string{"app"}), secrets)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -80,3 +80,13 @@ func cleanupPgBouncerValue(parameter string) (escaped string) {
 	// so we are just removing from the value
 	return newlineRegexp.ReplaceAllString(parameter, "")
 }
+
+// stringifyClientCertificateUsers emits the list of users, as expected by
+// the HBA rules, authenticated via their client certificate
+func stringifyClientCertificateUsers(users []string) string {
+	quotedUsers := make([]string, len(users))
+	for i, user := range users {
+		quotedUsers[i] = fmt.Sprintf("\"%s\"", cleanupPgBouncerValue(user))
+	}
+	return strings.Join(quotedUsers, ",")
+}
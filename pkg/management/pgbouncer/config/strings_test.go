@@ -44,4 +44,10 @@ var _ = Describe("PgBouncer configuration", func() {
 		Expect(params).NotTo(MatchRegexp("^pool_mode.*"))
 		Expect(params).NotTo(MatchRegexp("^pid_file.*"))
 	})
+
+	It("quotes the users authenticated via certificate", func() {
+		Expect(stringifyClientCertificateUsers(nil)).To(BeEmpty())
+		Expect(stringifyClientCertificateUsers([]string{"app", "reporting"})).
+			To(Equal(`"app","reporting"`))
+	})
 })
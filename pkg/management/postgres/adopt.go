@@ -0,0 +1,232 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// pgControlDataClusterState is the pg_controldata key containing
+	// the state of the database cluster
+	pgControlDataClusterState = "Database cluster state"
+
+	// pgControlDataSystemIdentifier is the pg_controldata key containing
+	// the system identifier of the database cluster
+	pgControlDataSystemIdentifier = "Database system identifier"
+
+	// pgControlDataStateShutDown is the state of a database cluster
+	// that has been cleanly shut down
+	pgControlDataStateShutDown = "shut down"
+)
+
+// AdoptExistingPgData checks that the data directory in sourcePgData can be
+// taken over by the cluster and moves it in place as the PGDATA of the
+// instance being created. The data directory is stored in the PGDATA
+// volume, possibly at its root, so no copy is involved. If systemIdentifier
+// is not empty, the data directory is required to have the same system
+// identifier
+func (info InitInfo) AdoptExistingPgData(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	sourcePgData string,
+	systemIdentifier string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	// The data directory is verified before being moved in place, unless
+	// a previous run of the bootstrap job has already started moving it
+	moveStarted := false
+	if sourcePgData != info.PgData {
+		var err error
+		if moveStarted, err = fileutils.FileExists(info.PgData); err != nil {
+			return err
+		}
+	}
+
+	if !moveStarted {
+		if err := checkAdoptablePgData(ctx, cluster, sourcePgData, info.PgWal, systemIdentifier); err != nil {
+			return fmt.Errorf("while checking the data directory to be adopted: %w", err)
+		}
+	}
+
+	if sourcePgData != info.PgData {
+		contextLogger.Info("moving the data directory to be adopted in place",
+			"source", sourcePgData,
+			"destination", info.PgData)
+
+		if err := movePgData(sourcePgData, info.PgData); err != nil {
+			return fmt.Errorf("while moving the data directory: %w", err)
+		}
+	}
+
+	if moveStarted {
+		if err := checkAdoptablePgData(ctx, cluster, info.PgData, info.PgWal, systemIdentifier); err != nil {
+			return fmt.Errorf("while checking the adopted data directory: %w", err)
+		}
+	}
+
+	if err := fileutils.EnsurePgDataPerms(info.PgData); err != nil {
+		return err
+	}
+
+	if _, err := info.restoreCustomWalDir(ctx); err != nil {
+		return fmt.Errorf("while moving the WAL files to the WAL volume: %w", err)
+	}
+
+	return nil
+}
+
+// movePgData moves the data directory in source to destination, inside
+// the same volume. When the data directory is at the root of the volume,
+// its content is moved one entry at a time. Moving a data directory again
+// resumes an interrupted move, completing it
+func movePgData(source, destination string) error {
+	relativeDestination, err := filepath.Rel(source, destination)
+	if err != nil {
+		return err
+	}
+	if relativeDestination == ".." || strings.HasPrefix(relativeDestination, "../") {
+		sourceExists, err := fileutils.FileExists(source)
+		if err != nil || !sourceExists {
+			return err
+		}
+		return os.Rename(source, destination)
+	}
+
+	if err := fileutils.EnsureDirectoryExist(destination); err != nil {
+		return err
+	}
+
+	destinationEntry, _, _ := strings.Cut(relativeDestination, "/")
+	entries, err := fileutils.GetDirectoryContent(source)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry == destinationEntry || entry == "lost+found" {
+			continue
+		}
+		if err := os.Rename(path.Join(source, entry), path.Join(destination, entry)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkAdoptablePgData ensures that the data directory to be adopted has
+// been cleanly shut down, is self-contained, is owned by the user running
+// PostgreSQL, and is compatible with the PostgreSQL version used by the
+// cluster. pg_wal is allowed to be a link only when it points to pgWal,
+// as happens after the WAL files have already been moved to the WAL volume
+func checkAdoptablePgData(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	sourcePgData string,
+	pgWal string,
+	systemIdentifier string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	dataDirInfo, err := os.Stat(sourcePgData)
+	if err != nil {
+		return err
+	}
+	if stat, ok := dataDirInfo.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("the data directory is owned by the user with UID %d instead of %d",
+			stat.Uid, os.Getuid())
+	}
+
+	majorVersion, err := postgresutils.GetMajorVersion(sourcePgData)
+	if err != nil {
+		return fmt.Errorf("cannot detect the major version of the data directory: %w", err)
+	}
+
+	imageMajorVersion, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(cluster.GetImageName()))
+	if err != nil {
+		contextLogger.Info("cannot detect the major version of the image, skipping the version check",
+			"imageName", cluster.GetImageName(),
+			"err", err.Error())
+	} else if majorVersion != imageMajorVersion {
+		return fmt.Errorf("the data directory has major version %d while the image has major version %d",
+			majorVersion, imageMajorVersion)
+	}
+
+	if linkInfo, err := os.Lstat(path.Join(sourcePgData, "pg_wal")); err != nil {
+		return err
+	} else if linkInfo.Mode()&os.ModeSymlink != 0 {
+		if target, _ := os.Readlink(path.Join(sourcePgData, "pg_wal")); pgWal == "" || target != pgWal {
+			return fmt.Errorf("pg_wal is a symbolic link, WAL files must be stored inside the data directory")
+		}
+	}
+
+	tablespaces, err := fileutils.GetDirectoryContent(path.Join(sourcePgData, "pg_tblspc"))
+	if err != nil {
+		return err
+	}
+	if len(tablespaces) != 0 {
+		return fmt.Errorf("the data directory contains tablespaces, which are not supported")
+	}
+
+	pgControlDataCmd := exec.Command(pgControlDataName, "-D", sourcePgData) // #nosec G204
+	pgControlDataCmd.Env = append(os.Environ(), "LANG=C", "LC_MESSAGES=C")
+	output, err := pgControlDataCmd.Output()
+	if err != nil {
+		return fmt.Errorf("while reading pg_controldata: %w", err)
+	}
+
+	controlData := parsePgControlDataOutput(string(output))
+	if state := controlData[pgControlDataClusterState]; state != pgControlDataStateShutDown {
+		return fmt.Errorf("the data directory has not been cleanly shut down (state: %q)", state)
+	}
+
+	if systemIdentifier != "" && controlData[pgControlDataSystemIdentifier] != systemIdentifier {
+		return fmt.Errorf("system identifier mismatch: expected %s, found %s",
+			systemIdentifier, controlData[pgControlDataSystemIdentifier])
+	}
+
+	return nil
+}
+
+// parsePgControlDataOutput parses the output of pg_controldata
+// returning a map of its fields
+func parsePgControlDataOutput(output string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_controldata output parsing", func() {
+	const output = `pg_control version number:            1300
+Catalog version number:               202107181
+Database system identifier:           7153416379187089432
+Database cluster state:               shut down
+pg_control last modified:             Thu 13 Oct 2022 09:12:41 AM UTC
+Latest checkpoint location:           0/3000028
+`
+
+	It("extracts the state and the system identifier", func() {
+		controlData := parsePgControlDataOutput(output)
+		Expect(controlData).To(HaveKeyWithValue(pgControlDataClusterState, pgControlDataStateShutDown))
+		Expect(controlData).To(HaveKeyWithValue(pgControlDataSystemIdentifier, "7153416379187089432"))
+	})
+
+	It("keeps the values containing colons", func() {
+		controlData := parsePgControlDataOutput(output)
+		Expect(controlData).To(HaveKeyWithValue("pg_control last modified", "Thu 13 Oct 2022 09:12:41 AM UTC"))
+	})
+
+	It("ignores lines that are not key-value pairs", func() {
+		Expect(parsePgControlDataOutput("\nnot a pair\n")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("moving the adopted data directory in place", func() {
+	var volume string
+
+	BeforeEach(func() {
+		var err error
+		volume, err = os.MkdirTemp("", "existing-volume-")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(volume)).To(Succeed())
+		})
+	})
+
+	createDataDirectory := func(dataDir string) {
+		Expect(os.MkdirAll(path.Join(dataDir, "global"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(path.Join(dataDir, "PG_VERSION"), []byte("14\n"), 0o600)).To(Succeed())
+	}
+
+	It("renames a data directory stored in a sub path of the volume", func() {
+		source := path.Join(volume, "pgsql", "14", "data")
+		destination := path.Join(volume, "pgdata")
+		createDataDirectory(source)
+
+		Expect(movePgData(source, destination)).To(Succeed())
+		Expect(path.Join(destination, "PG_VERSION")).To(BeARegularFile())
+		Expect(path.Join(destination, "global")).To(BeADirectory())
+		Expect(source).ToNot(BeAnExistingFile())
+
+		By("resuming a move which has already been completed", func() {
+			Expect(movePgData(source, destination)).To(Succeed())
+			Expect(path.Join(destination, "PG_VERSION")).To(BeARegularFile())
+		})
+	})
+
+	It("moves the content of a data directory stored at the root of the volume", func() {
+		destination := path.Join(volume, "pgdata")
+		createDataDirectory(volume)
+		Expect(os.Mkdir(path.Join(volume, "lost+found"), 0o700)).To(Succeed())
+
+		Expect(movePgData(volume, destination)).To(Succeed())
+		Expect(path.Join(destination, "PG_VERSION")).To(BeARegularFile())
+		Expect(path.Join(destination, "global")).To(BeADirectory())
+		Expect(path.Join(destination, "lost+found")).ToNot(BeAnExistingFile())
+		Expect(path.Join(volume, "lost+found")).To(BeADirectory())
+		Expect(path.Join(volume, "PG_VERSION")).ToNot(BeAnExistingFile())
+	})
+
+	It("resumes an interrupted move from the root of the volume", func() {
+		destination := path.Join(volume, "pgdata")
+		createDataDirectory(volume)
+		Expect(os.Mkdir(destination, 0o700)).To(Succeed())
+		Expect(os.Rename(path.Join(volume, "global"), path.Join(destination, "global"))).To(Succeed())
+
+		Expect(movePgData(volume, destination)).To(Succeed())
+		Expect(path.Join(destination, "PG_VERSION")).To(BeARegularFile())
+		Expect(path.Join(destination, "global")).To(BeADirectory())
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// archiverPollingInterval is how often the status of the
+// archiver is checked while waiting for a WAL file to be archived
+const archiverPollingInterval = 1 * time.Second
+
+// ArchiveCurrentWAL forces PostgreSQL to switch to a new WAL file and waits,
+// until the passed context is done, for every WAL file preceding the new one
+// to be archived. The name of the last WAL file to be archived is returned
+func (instance *Instance) ArchiveCurrentWAL(ctx context.Context) (string, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return "", err
+	}
+
+	// When there has been no activity since the last switch, no switch
+	// happens and we get the end of the previous WAL file, which is
+	// exactly what we need to wait for
+	var walName string
+	row := superUserDB.QueryRowContext(ctx, "SELECT pg_catalog.pg_walfile_name(pg_catalog.pg_switch_wal())")
+	if err := row.Scan(&walName); err != nil {
+		return "", fmt.Errorf("while switching WAL: %w", err)
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("walName", walName)
+	contextLogger.Info("Waiting for the WAL file to be archived")
+
+	ticker := time.NewTicker(archiverPollingInterval)
+	defer ticker.Stop()
+	for {
+		var lastArchivedWAL string
+		row := superUserDB.QueryRowContext(ctx,
+			"SELECT COALESCE(last_archived_wal, '') FROM pg_catalog.pg_stat_archiver")
+		if err := row.Scan(&lastArchivedWAL); err != nil {
+			return walName, fmt.Errorf("while checking the archiver status: %w", err)
+		}
+
+		if postgres.IsWALArchived(walName, lastArchivedWAL) {
+			contextLogger.Info("WAL file archived")
+			return walName, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return walName, fmt.Errorf("while waiting for %s to be archived: %w", walName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
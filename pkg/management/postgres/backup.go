@@ -24,8 +24,10 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -34,14 +36,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/checksum"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -329,12 +334,16 @@ func (b *BackupCommand) run(ctx context.Context) {
 	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
 	cmd.Env = b.Env
 	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
+	_, span := tracing.StartSpan(ctx, barmanCapabilities.BarmanCloudBackup,
+		attribute.String("backup.name", b.Backup.Name))
 	err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		// Set the status to failed and exit
 		b.Log.Error(err, "Backup failed")
 		backupStatus.SetAsFailed(err)
 		b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
+		b.Recorder.Eventf(b.Cluster, "Warning", "BackupFailed", "Backup %s failed: %v", b.Backup.Name, err)
 
 		// Update backup status in cluster conditions on failure
 		condition = metav1.Condition{
@@ -356,6 +365,7 @@ func (b *BackupCommand) run(ctx context.Context) {
 	b.Log.Info("Backup completed")
 	backupStatus.SetAsCompleted()
 	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+	b.Recorder.Eventf(b.Cluster, "Normal", "BackupCompleted", "Backup %s completed", b.Backup.Name)
 
 	// Update backup status in cluster conditions on backup completion
 	condition = metav1.Condition{
@@ -401,10 +411,20 @@ func (b *BackupCommand) run(ctx context.Context) {
 
 	// Update backup status to match with the latest completed backup
 	b.updateCompletedBackupStatus(backupList)
+	segmentSize, err := b.Instance.GetWALSegmentSize()
+	if err != nil {
+		b.Log.Error(err, "Can't get the WAL segment size, skipping the backup verification")
+	} else {
+		b.setWALChecksums(segmentSize)
+	}
 	if err = UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
 		b.Log.Error(err, "Can't set backup status as completed")
 	}
 
+	if segmentSize != 0 {
+		b.verifyBackup(ctx, segmentSize)
+	}
+
 	// Set the first recoverability point
 	if ts := backupList.FirstRecoverabilityPoint(); ts != nil {
 		firstRecoverabilityPoint := ts.Format(time.RFC3339)
@@ -495,3 +515,75 @@ func (b *BackupCommand) updateCompletedBackupStatus(backupList *catalog.Catalog)
 	backupStatus.BeginLSN = latestBackup.BeginLSN
 	backupStatus.EndLSN = latestBackup.EndLSN
 }
+
+// setWALChecksums stores in the backup status the checksums of the WAL
+// files required by the backup, computed by the WAL archiver before
+// uploading them
+func (b *BackupCommand) setWALChecksums(segmentSize int64) {
+	backupStatus := b.Backup.GetStatus()
+
+	journal, err := checksum.NewJournal(walarchive.ChecksumDirectory)
+	if err != nil {
+		b.Log.Error(err, "Can't open the WAL checksum journal")
+		return
+	}
+
+	beginSegment, err := postgres.SegmentFromName(backupStatus.BeginWal)
+	if err != nil {
+		b.Log.Error(err, "Can't parse the begin WAL of the backup")
+		return
+	}
+	endSegment, err := postgres.SegmentFromName(backupStatus.EndWal)
+	if err != nil {
+		b.Log.Error(err, "Can't parse the end WAL of the backup")
+		return
+	}
+	segments, err := beginSegment.SegmentsUntil(endSegment, &segmentSize)
+	if err != nil {
+		b.Log.Error(err, "Can't get the WAL files required by the backup")
+		return
+	}
+
+	for _, segment := range segments {
+		walChecksum, err := journal.Get(segment.Name())
+		if err != nil {
+			b.Log.Error(err, "Can't read the checksum of the WAL file", "walName", segment.Name())
+			continue
+		}
+		// The WAL file could have been archived by a different
+		// instance, or before this instance has been restarted
+		if walChecksum == "" {
+			continue
+		}
+		if backupStatus.WALChecksums == nil {
+			backupStatus.WALChecksums = make(map[string]string, len(segments))
+		}
+		backupStatus.WALChecksums[segment.Name()] = walChecksum
+	}
+
+	// The checksums of the WAL files preceding the latest
+	// backup are not needed anymore
+	if err := journal.Prune(backupStatus.BeginWal); err != nil {
+		b.Log.Error(err, "Can't prune the WAL checksum journal")
+	}
+}
+
+// verifyBackup checks the artifacts that have just been uploaded,
+// recording the result in the backup status
+func (b *BackupCommand) verifyBackup(ctx context.Context, segmentSize int64) {
+	verification, err := VerifyBackup(ctx, b.Cluster, b.Backup, b.Env, segmentSize)
+	if err != nil {
+		b.Log.Error(err, "Can't verify the backup")
+		return
+	}
+
+	b.Backup.GetStatus().Verification = verification
+	if !verification.IsSuccessful() {
+		b.Log.Info("Backup verification failed", "errors", verification.Errors)
+		b.Recorder.Event(b.Backup, "Warning", "VerificationFailed", strings.Join(verification.Errors, "; "))
+	}
+
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set the backup verification result")
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/checksum"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// VerifyBackup checks that the artifacts of a completed backup are
+// still available and intact in the object store: the backup must be
+// in the catalog, and the WAL files it requires must be complete and
+// match the checksums computed when they were archived.
+// The env parameter must contain the credentials to access the object store
+func VerifyBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	env []string,
+	segmentSize int64,
+) (*apiv1.BackupVerification, error) {
+	contextLogger := log.FromContext(ctx)
+	backupStatus := backup.GetStatus()
+	if backupStatus.Phase != apiv1.BackupPhaseCompleted {
+		return nil, fmt.Errorf("backup %s is not completed", backup.Name)
+	}
+
+	// The object store could have been changed in the cluster since
+	// the backup has been taken, so we use the one in the backup status
+	configuration := &apiv1.BarmanObjectStoreConfiguration{
+		BarmanCredentials: backupStatus.BarmanCredentials,
+		EndpointCA:        backupStatus.EndpointCA,
+		EndpointURL:       backupStatus.EndpointURL,
+		DestinationPath:   backupStatus.DestinationPath,
+		ServerName:        backupStatus.ServerName,
+	}
+
+	backupList, err := barman.GetBackupList(configuration, backupStatus.ServerName, env)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the backup catalog: %w", err)
+	}
+
+	verification := &apiv1.BackupVerification{Time: metav1.Now()}
+	if err := verifyBackupInCatalog(backupList, backupStatus.BackupID); err != nil {
+		verification.Errors = append(verification.Errors, err.Error())
+	}
+
+	beginSegment, err := postgres.SegmentFromName(backupStatus.BeginWal)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the begin WAL of the backup: %w", err)
+	}
+	endSegment, err := postgres.SegmentFromName(backupStatus.EndWal)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the end WAL of the backup: %w", err)
+	}
+	segments, err := beginSegment.SegmentsUntil(endSegment, &segmentSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fileutils.EnsureDirectoryExist(postgres.BackupTemporaryDirectory); err != nil {
+		return nil, err
+	}
+	verifyDirectory, err := os.MkdirTemp(postgres.BackupTemporaryDirectory, "verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(verifyDirectory); err != nil {
+			contextLogger.Error(err, "while removing the backup verification directory")
+		}
+	}()
+
+	walRestorer, err := restorer.New(ctx, cluster, env, path.Join(verifyDirectory, "spool"))
+	if err != nil {
+		return nil, err
+	}
+
+	var options []string
+	if configuration.EndpointURL != "" {
+		options = append(options, "--endpoint-url", configuration.EndpointURL)
+	}
+	options, err = barman.AppendCloudProviderOptionsFromConfiguration(options, configuration)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, configuration.DestinationPath, backupStatus.ServerName)
+
+	for _, segment := range segments {
+		walName := segment.Name()
+		destinationPath := path.Join(verifyDirectory, walName)
+
+		err := walRestorer.Restore(walName, destinationPath, options)
+		switch {
+		case errors.Is(err, restorer.ErrWALNotFound):
+			verification.Errors = append(verification.Errors, fmt.Sprintf("WAL file %s is missing", walName))
+		case err != nil:
+			verification.Errors = append(verification.Errors,
+				fmt.Sprintf("cannot download WAL file %s: %v", walName, err))
+		default:
+			if err := verifyWALFile(destinationPath, backupStatus.WALChecksums[walName], segmentSize); err != nil {
+				verification.Errors = append(verification.Errors, err.Error())
+			}
+			if err := fileutils.RemoveFile(destinationPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return verification, nil
+}
+
+// verifyBackupInCatalog checks that a backup is listed as completed in the catalog
+func verifyBackupInCatalog(backupList *catalog.Catalog, backupID string) error {
+	for _, barmanBackup := range backupList.List {
+		if barmanBackup.ID != backupID {
+			continue
+		}
+		if barmanBackup.Error != "" || barmanBackup.EndTime.IsZero() {
+			return fmt.Errorf("backup %s is not completed in the object store", backupID)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("backup %s is missing from the object store", backupID)
+}
+
+// verifyWALFile checks that a downloaded WAL file is complete and, when
+// the expected checksum is known, that its content hasn't changed
+func verifyWALFile(fileName, expectedChecksum string, segmentSize int64) error {
+	walName := path.Base(fileName)
+
+	size, err := fileutils.GetFileSize(fileName)
+	if err != nil {
+		return fmt.Errorf("cannot read WAL file %s: %w", walName, err)
+	}
+	if size != segmentSize {
+		return fmt.Errorf("WAL file %s is truncated: %d bytes instead of %d", walName, size, segmentSize)
+	}
+
+	if expectedChecksum == "" {
+		return nil
+	}
+
+	actualChecksum, err := checksum.Compute(fileName)
+	if err != nil {
+		return fmt.Errorf("cannot compute the checksum of WAL file %s: %w", walName, err)
+	}
+	if actualChecksum != expectedChecksum {
+		return fmt.Errorf("WAL file %s doesn't match its checksum", walName)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup verification", func() {
+	It("checks that the backup is completed in the catalog", func() {
+		backupList := catalog.NewCatalog([]catalog.BarmanBackup{
+			{ID: "20221101T000000", EndTime: time.Now()},
+			{ID: "20221102T000000", Error: "failure"},
+		})
+		Expect(verifyBackupInCatalog(backupList, "20221101T000000")).To(Succeed())
+		Expect(verifyBackupInCatalog(backupList, "20221102T000000")).ToNot(Succeed())
+		Expect(verifyBackupInCatalog(backupList, "20221103T000000")).ToNot(Succeed())
+	})
+
+	It("detects truncated and modified WAL files", func() {
+		tmpDir, err := os.MkdirTemp("", "verify-test-")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		})
+
+		fileName := path.Join(tmpDir, "000000010000000000000001")
+		Expect(os.WriteFile(fileName, []byte("hello"), 0o600)).To(Succeed())
+		const helloChecksum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+		Expect(verifyWALFile(fileName, helloChecksum, 5)).To(Succeed())
+		Expect(verifyWALFile(fileName, "", 5)).To(Succeed())
+		Expect(verifyWALFile(fileName, helloChecksum, 16)).To(MatchError(ContainSubstring("truncated")))
+		Expect(verifyWALFile(fileName, "0000", 5)).To(MatchError(ContainSubstring("checksum")))
+	})
+})
@@ -20,6 +20,7 @@ limitations under the License.
 package compatibility
 
 import (
+	"os"
 	"os/exec"
 	"syscall"
 )
@@ -30,3 +31,9 @@ func AddInstanceRunCommands(cmd *exec.Cmd) {
 		Setpgid: true,
 	}
 }
+
+// KillProcessGroup kills a process started with AddInstanceRunCommands
+// together with every process it spawned
+func KillProcessGroup(process *os.Process) error {
+	return syscall.Kill(-process.Pid, syscall.SIGKILL)
+}
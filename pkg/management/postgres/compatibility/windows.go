@@ -20,6 +20,7 @@ limitations under the License.
 package compatibility
 
 import (
+	"os"
 	"os/exec"
 )
 
@@ -27,3 +28,8 @@ import (
 func AddInstanceRunCommands(cmd *exec.Cmd) {
 	return
 }
+
+// KillProcessGroup kills a process started with AddInstanceRunCommands
+func KillProcessGroup(process *os.Process) error {
+	return process.Kill()
+}
@@ -100,6 +100,7 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 
 	return postgres.CreateHBARules(
 		cluster.Spec.PostgresConfiguration.PgHBA,
+		cluster.Spec.PostgresConfiguration.PoolerCertificateUsers,
 		defaultAuthenticationMethod,
 		buildLDAPConfigString(cluster, ldapBindPassword))
 }
@@ -286,6 +287,34 @@ func RemoveArchiveModeFromPostgresAutoConf(pgData string) (changed bool, err err
 	return fileutils.WriteStringToFile(targetFile, updatedContent)
 }
 
+// SetAlterSystemEnabled allows or denies the usage of ALTER SYSTEM by making
+// the "postgresql.auto.conf" file writable or read-only. The instance manager
+// is still able to update it, as the file is replaced instead of rewritten
+func SetAlterSystemEnabled(pgData string, enabled bool) (changed bool, err error) {
+	targetFile := path.Join(pgData, "postgresql.auto.conf")
+	info, err := os.Stat(targetFile)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var mode os.FileMode = 0o400
+	if enabled {
+		mode = 0o600
+	}
+	if info.Mode().Perm() == mode {
+		return false, nil
+	}
+
+	if err := os.Chmod(targetFile, mode); err != nil {
+		return false, fmt.Errorf("while changing the permissions of %v: %w", targetFile, err)
+	}
+
+	return true, nil
+}
+
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
 // used for this cluster and return it and its sha256 checksum
 func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, error) {
@@ -301,9 +330,15 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, erro
 		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
 		IncludingMandatory:               true,
 		IncludingSharedPreloadLibraries:  true,
-		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
+		AdditionalSharedPreloadLibraries: cluster.GetSharedPreloadLibraries(),
+		EnabledManagedExtensions:         cluster.GetEnabledManagedExtensions(),
 		IsReplicaCluster:                 cluster.IsReplica(),
+		WALCompression:                   string(cluster.Spec.Replication.GetWALCompression()),
+		TLSParameters:                    cluster.Spec.PostgresConfiguration.TLS.GetParameters(),
+		PasswordEncryption:               cluster.GetPasswordEncryption(),
+		HugePageSize:                     cluster.GetHugePageSize(),
 	}
+	info.ExtensionControlPath, info.DynamicLibraryPath = cluster.GetExtensionsPaths()
 
 	// Compute the actual number of sync replicas
 	syncReplicas, electable := cluster.GetSyncReplicasData()
@@ -18,6 +18,8 @@ package postgres
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -98,3 +100,46 @@ var _ = Describe("testing the building of the ldap config string", func() {
 			"ldaptls=1 ldapprefix=\"%s\" ldapsuffix=\"%s\"", ldapServer, ldapPort, ldapScheme, ldapPrefix, ldapSuffix)))
 	})
 })
+
+var _ = Describe("enabling and disabling ALTER SYSTEM", func() {
+	var pgData string
+
+	BeforeEach(func() {
+		var err error
+		pgData, err = os.MkdirTemp("", "pgdata")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(pgData)).To(Succeed())
+		})
+	})
+
+	getPermissions := func() os.FileMode {
+		info, err := os.Stat(filepath.Join(pgData, "postgresql.auto.conf"))
+		Expect(err).ToNot(HaveOccurred())
+		return info.Mode().Perm()
+	}
+
+	It("does nothing when postgresql.auto.conf doesn't exist", func() {
+		changed, err := SetAlterSystemEnabled(pgData, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("makes postgresql.auto.conf read-only and then writable again", func() {
+		Expect(os.WriteFile(filepath.Join(pgData, "postgresql.auto.conf"), []byte(""), 0o600)).To(Succeed())
+
+		changed, err := SetAlterSystemEnabled(pgData, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(getPermissions()).To(Equal(os.FileMode(0o400)))
+
+		changed, err = SetAlterSystemEnabled(pgData, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		changed, err = SetAlterSystemEnabled(pgData, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(getPermissions()).To(Equal(os.FileMode(0o600)))
+	})
+})
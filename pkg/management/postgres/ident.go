@@ -21,29 +21,54 @@ import (
 	"os/user"
 	"path/filepath"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 // WritePostgresUserMaps creates a pg_ident.conf file containing only one map called "local" that
 // maps the current user to "postgres" user.
 func WritePostgresUserMaps(pgData string) error {
-	var username string
+	_, err := fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
+		postgres.CreateUserMaps(getCurrentUserName(), nil, nil))
+	if err != nil {
+		return err
+	}
 
-	currentUser, err := user.Current()
+	return nil
+}
+
+// RefreshPGIdent generates and writes down the pg_ident.conf file, including
+// the user maps needed by the users authenticated via certificate in the pooler
+// and the ones defined in the cluster
+func (instance *Instance) RefreshPGIdent(cluster *apiv1.Cluster) (postgresIdentChanged bool, err error) {
+	identContent := postgres.CreateUserMaps(
+		getCurrentUserName(),
+		cluster.Spec.PostgresConfiguration.PoolerCertificateUsers,
+		cluster.Spec.PostgresConfiguration.PgIdent)
+	postgresIdentChanged, err = InstallPgDataFileContent(
+		instance.PgData,
+		identContent,
+		constants.PostgresqlIdentFile)
 	if err != nil {
-		log.Info("Unable to identify the current user. Falling back to insecure mapping.")
-		username = "/"
-	} else {
-		username = currentUser.Username
+		return postgresIdentChanged, fmt.Errorf(
+			"installing postgresql user maps: %w",
+			err)
 	}
 
-	_, err = fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
-		fmt.Sprintf("local %s postgres\n", username))
+	return postgresIdentChanged, nil
+}
+
+// getCurrentUserName gets the name of the operating system user
+// running the instance manager
+func getCurrentUserName() string {
+	currentUser, err := user.Current()
 	if err != nil {
-		return err
+		log.Info("Unable to identify the current user. Falling back to insecure mapping.")
+		return "/"
 	}
 
-	return nil
+	return currentUser.Username
 }
@@ -90,8 +90,18 @@ type InitInfo struct {
 	// Whether it is a temporary instance that will never contain real data.
 	Temporary bool
 
+	// PostInitSQLRefsFolder is the folder which contains a bunch of SQL
+	// files to be executed just after having configured a new instance
+	PostInitSQLRefsFolder string
+
+	// PostInitTemplateSQLRefsFolder is the folder which contains a bunch of
+	// SQL files to be executed inside the template1 database just after
+	// having configured a new instance
+	PostInitTemplateSQLRefsFolder string
+
 	// PostInitApplicationSQLRefsFolder is the folder which contains a bunch
-	// of SQL files to be executed just after having configured a new instance
+	// of SQL files to be executed just after having the application database
+	// created
 	PostInitApplicationSQLRefsFolder string
 }
 
@@ -113,11 +123,18 @@ func (info InitInfo) VerifyPGData() error {
 // CreateDataDirectory creates a new data directory given the configuration
 func (info InitInfo) CreateDataDirectory() error {
 	// Invoke initdb to generate a data directory
+	// The generated pg_hba.conf never allows access without authentication:
+	// local connections are authenticated via peer, and TCP ones are rejected
+	// until the operator writes the rules of the cluster
 	options := []string{
 		"--username",
 		"postgres",
 		"-D",
 		info.PgData,
+		"--auth-local",
+		"peer",
+		"--auth-host",
+		"reject",
 	}
 
 	// If temporary instance disable fsync on creation
@@ -168,7 +185,9 @@ func (info InitInfo) CreateDataDirectory() error {
 func (info InitInfo) GetInstance() *Instance {
 	postgresInstance := NewInstance()
 	postgresInstance.PgData = info.PgData
-	postgresInstance.StartupOptions = []string{"listen_addresses='127.0.0.1'"}
+	// The instances started during the bootstrap are only reachable via
+	// the Unix socket, so they don't listen on any TCP address
+	postgresInstance.StartupOptions = []string{"listen_addresses=''"}
 	return postgresInstance
 }
 
@@ -204,6 +223,9 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 	if err = info.executeQueries(dbSuperUser, info.PostInitSQL); err != nil {
 		return err
 	}
+	if err = info.executeSQLRefs(dbSuperUser, info.PostInitSQLRefsFolder); err != nil {
+		return fmt.Errorf("could not execute post init SQL refs: %w", err)
+	}
 
 	dbTemplate, err := instance.GetTemplateDB()
 	if err != nil {
@@ -214,6 +236,9 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 	if err = info.executeQueries(dbTemplate, info.PostInitTemplateSQL); err != nil {
 		return fmt.Errorf("could not execute init Template queries: %w", err)
 	}
+	if err = info.executeSQLRefs(dbTemplate, info.PostInitTemplateSQLRefsFolder); err != nil {
+		return fmt.Errorf("could not execute post init template SQL refs: %w", err)
+	}
 
 	if info.ApplicationDatabase == "" {
 		return nil
@@ -245,7 +270,7 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 		return fmt.Errorf("could not execute init Application queries: %w", err)
 	}
 
-	if err = info.executePostInitApplicationSQLRefs(appDB); err != nil {
+	if err = info.executeSQLRefs(appDB, info.PostInitApplicationSQLRefsFolder); err != nil {
 		return fmt.Errorf("could not execute post init application SQL refs: %w", err)
 	}
 
@@ -259,19 +284,21 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 	return nil
 }
 
-func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
-	if info.PostInitApplicationSQLRefsFolder == "" {
+// executeSQLRefs executes, in alphabetical order, the SQL files
+// contained in the passed directory, if any
+func (info InitInfo) executeSQLRefs(sqlUser *sql.DB, directory string) error {
+	if directory == "" {
 		return nil
 	}
 
-	if err := fileutils.EnsureDirectoryExist(info.PostInitApplicationSQLRefsFolder); err != nil {
-		return fmt.Errorf("could not find directory: %s, err: %w", info.PostInitApplicationSQLRefsFolder, err)
+	if err := fileutils.EnsureDirectoryExist(directory); err != nil {
+		return fmt.Errorf("could not find directory: %s, err: %w", directory, err)
 	}
 
-	files, err := fileutils.GetDirectoryContent(info.PostInitApplicationSQLRefsFolder)
+	files, err := fileutils.GetDirectoryContent(directory)
 	if err != nil {
 		return fmt.Errorf("could not get directory content from: %s, err: %w",
-			info.PostInitApplicationSQLRefsFolder, err)
+			directory, err)
 	}
 
 	// Sorting ensures that we execute the files in the correct order.
@@ -279,9 +306,9 @@ func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
 	sort.Strings(files)
 
 	for _, file := range files {
-		sql, ioErr := fileutils.ReadFile(path.Join(info.PostInitApplicationSQLRefsFolder, file))
+		sql, ioErr := fileutils.ReadFile(path.Join(directory, file))
 		if ioErr != nil {
-			return fmt.Errorf("could not read file: %s, err; %w", file, err)
+			return fmt.Errorf("could not read file: %s, err; %w", file, ioErr)
 		}
 
 		if err = info.executeQueries(sqlUser, []string{string(sql)}); err != nil {
@@ -327,6 +354,12 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		return err
 	}
 
+	// The user may have requested a different authentication
+	// method via the initdb options
+	if err = info.WriteRestoreHbaConf(); err != nil {
+		return err
+	}
+
 	instance := info.GetInstance()
 
 	postgresVersion, err := cluster.GetPostgresqlVersion()
@@ -338,8 +371,12 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     postgresVersion,
 		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
-		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
+		AdditionalSharedPreloadLibraries: cluster.GetSharedPreloadLibraries(),
+		EnabledManagedExtensions:         cluster.GetEnabledManagedExtensions(),
 		IsReplicaCluster:                 cluster.IsReplica(),
+		WALCompression:                   string(cluster.Spec.Replication.GetWALCompression()),
+		TLSParameters:                    cluster.Spec.PostgresConfiguration.TLS.GetParameters(),
+		PasswordEncryption:               cluster.GetPasswordEncryption(),
 		IncludingSharedPreloadLibraries:  true,
 		PreserveFixedSettingsFromUser:    true,
 	}
@@ -360,6 +397,10 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		}
 	}
 
+	if err = auditBootstrapHBA(info.PgData); err != nil {
+		return err
+	}
+
 	return instance.WithActiveInstance(func() error {
 		err = info.ConfigureNewInstance(instance)
 		if err != nil {
@@ -109,6 +109,15 @@ var (
 
 	// ErrNoConnectionEstablished postgres is alive, but rejecting connections
 	ErrNoConnectionEstablished = fmt.Errorf("could not establish connection")
+
+	// ErrPostmasterNotRunning the postmaster process is not running
+	ErrPostmasterNotRunning = fmt.Errorf("postmaster is not running")
+
+	// ErrWALReceiverNotActive a standby is not receiving WAL via streaming replication
+	ErrWALReceiverNotActive = fmt.Errorf("streaming replication is not active")
+
+	// ErrArchiverFailing the last attempt to archive a WAL file failed
+	ErrArchiverFailing = fmt.Errorf("the last attempt to archive a WAL file failed")
 )
 
 // Instance represent a PostgreSQL instance to be executed
@@ -167,6 +176,10 @@ type Instance struct {
 	// MaxStopDelay is the current MaxStopDelay of the cluster
 	MaxStopDelay int32
 
+	// SmartShutdownTimeout is the time reserved for the smart shutdown
+	// of the instance when the Pod is being terminated
+	SmartShutdownTimeout int32
+
 	// canCheckReadiness specifies whether the instance can start being checked for readiness
 	// Is set to true before the instance is run and to false once it exits,
 	// it's used by the readiness probe to know whether it should be short-circuited
@@ -181,6 +194,9 @@ type Instance struct {
 
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
+
+	// storageLatency measures the write latency of the data volume
+	storageLatency storageLatencyMonitor
 }
 
 // IsFenced checks whether the instance is marked as fenced
@@ -522,6 +538,22 @@ func (instance *Instance) GetPgVersion() (semver.Version, error) {
 	return *parsedVersion, nil
 }
 
+// GetWALSegmentSize gets the size, in bytes, of the WAL segments of this instance
+func (instance *Instance) GetWALSegmentSize() (int64, error) {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return 0, err
+	}
+
+	var segmentSize int64
+	row := db.QueryRow("SELECT pg_catalog.pg_size_bytes(pg_catalog.current_setting('wal_segment_size'))")
+	if err := row.Scan(&segmentSize); err != nil {
+		return 0, fmt.Errorf("while reading the WAL segment size: %w", err)
+	}
+
+	return segmentSize, nil
+}
+
 // ConnectionPool gets or initializes the connection pool for this instance
 func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
 	const applicationName = "cnpg-instance-manager"
@@ -772,6 +804,13 @@ func (instance *Instance) Rewind(postgresMajorVersion int) error {
 		return err
 	}
 
+	// pg_rewind overwrites the configuration files with the ones of the
+	// source server, so postgresql.auto.conf must be writable. The
+	// instance manager will make it read-only again if needed
+	if _, err := SetAlterSystemEnabled(instance.PgData, true); err != nil {
+		return err
+	}
+
 	log.Info("Starting up pg_rewind",
 		"pgdata", instance.PgData,
 		"options", options)
@@ -954,3 +993,9 @@ func (instance *Instance) DropConnections() error {
 func (instance *Instance) GetPrimaryConnInfo() string {
 	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName)
 }
+
+// GetUpstreamConnInfo returns the DSN to stream from the
+// standby reachable at the passed host
+func (instance *Instance) GetUpstreamConnInfo(host string) string {
+	return buildPrimaryConnInfo(host, instance.PodName)
+}
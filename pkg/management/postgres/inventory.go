@@ -0,0 +1,133 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"encoding/json"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// GetInventory gets the list of the roles and the databases
+// defined in this instance
+func (instance *Instance) GetInventory() (*postgres.Inventory, error) {
+	result := &postgres.Inventory{}
+
+	roles, err := instance.getRolesInventory()
+	if err != nil {
+		return nil, err
+	}
+	result.Roles = roles
+
+	databases, err := instance.getDatabasesInventory()
+	if err != nil {
+		return nil, err
+	}
+	result.Databases = databases
+
+	return result, nil
+}
+
+func (instance *Instance) getRolesInventory() (result []postgres.RoleInventory, err error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := superUserDB.Query(
+		`SELECT
+			r.rolname,
+			r.rolsuper,
+			r.rolcreaterole,
+			r.rolcreatedb,
+			r.rolcanlogin,
+			r.rolreplication,
+			COALESCE((
+				SELECT json_agg(g.rolname ORDER BY g.rolname)
+				FROM pg_catalog.pg_auth_members m
+				JOIN pg_catalog.pg_roles g ON g.oid = m.roleid
+				WHERE m.member = r.oid
+			), '[]')::text
+		FROM pg_catalog.pg_roles r
+		WHERE r.rolname NOT LIKE 'pg\_%'
+		ORDER BY r.rolname`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		var role postgres.RoleInventory
+		var memberOf string
+		if err = rows.Scan(
+			&role.Name,
+			&role.Superuser,
+			&role.CreateRole,
+			&role.CreateDB,
+			&role.Login,
+			&role.Replication,
+			&memberOf,
+		); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(memberOf), &role.MemberOf); err != nil {
+			log.Warning("Cannot decode the role memberships", "role", role.Name, "err", err.Error())
+		}
+		result = append(result, role)
+	}
+
+	return result, rows.Err()
+}
+
+func (instance *Instance) getDatabasesInventory() (result []postgres.DatabaseInventory, err error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := superUserDB.Query(
+		`SELECT
+			d.datname,
+			pg_catalog.pg_get_userbyid(d.datdba),
+			pg_catalog.pg_encoding_to_char(d.encoding)
+		FROM pg_catalog.pg_database d
+		WHERE NOT d.datistemplate
+		ORDER BY d.datname`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		var database postgres.DatabaseInventory
+		if err = rows.Scan(&database.Name, &database.Owner, &database.Encoding); err != nil {
+			return nil, err
+		}
+		result = append(result, database)
+	}
+
+	return result, rows.Err()
+}
@@ -18,9 +18,13 @@ package postgres
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path"
+	"strconv"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -30,8 +34,11 @@ import (
 )
 
 // ClonePgData clones an existing server, given its connection string,
-// to a certain data directory
-func ClonePgData(connectionString, targetPgData, walDir string) error {
+// to a certain data directory, optionally throttling pg_basebackup
+func ClonePgData(
+	connectionString, targetPgData, walDir string,
+	throttling *apiv1.CloneThrottlingConfiguration,
+) error {
 	// To initiate streaming replication, the frontend sends the replication parameter
 	// in the startup message. A Boolean value of true (or on, yes, 1) tells the backend
 	// to go into physical replication walsender mode, wherein a small set of replication
@@ -65,7 +72,7 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 		options = append(options, "--waldir", walDir)
 	}
 
-	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
+	pgBaseBackupCmd := buildPgBaseBackupCommand(options, throttling)
 	err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
 	if err != nil {
 		return fmt.Errorf("error in pg_basebackup, %w", err)
@@ -78,7 +85,7 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
 
-	err := ClonePgData(primaryConnInfo, info.PgData, info.PgWal)
+	err := ClonePgData(primaryConnInfo, info.PgData, info.PgWal, cluster.Spec.Replication.GetCloneThrottling())
 	if err != nil {
 		return err
 	}
@@ -87,3 +94,59 @@ func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
 	return err
 }
+
+// JoinFromVolumeClone configures a data directory cloned from the volume
+// of another standby, so that it can join the cluster as a new replica.
+// The clone has been taken while the standby was running, so the PID file
+// is removed and PostgreSQL will recover the data when started
+func (info InitInfo) JoinFromVolumeClone(cluster *apiv1.Cluster) error {
+	pgdataExists, err := fileutils.FileExists(path.Join(info.PgData, "PG_VERSION"))
+	if err != nil {
+		return err
+	}
+	if !pgdataExists {
+		return fmt.Errorf("the cloned volume doesn't contain a data directory in %s", info.PgData)
+	}
+
+	if err := os.Remove(path.Join(info.PgData, PostgresqlPidFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("while removing the PID file of the cloned standby: %w", err)
+	}
+
+	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
+	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
+	return err
+}
+
+// buildPgBaseBackupCommand creates the pg_basebackup command, limiting its
+// transfer rate and running it through nice and ionice as requested
+func buildPgBaseBackupCommand(options []string, throttling *apiv1.CloneThrottlingConfiguration) *exec.Cmd {
+	if throttling == nil {
+		return exec.Command(pgBaseBackupName, options...) // #nosec
+	}
+
+	if throttling.MaxRate != "" {
+		options = append(options, "--max-rate", throttling.MaxRate)
+	}
+
+	var args []string
+	if throttling.Nice != nil {
+		args = append(args, "nice", "-n", strconv.Itoa(int(*throttling.Nice)))
+	}
+
+	if throttling.IOClass != "" || throttling.IOPriority != nil {
+		args = append(args, "ionice")
+		switch throttling.IOClass {
+		case apiv1.IOSchedulingClassIdle:
+			args = append(args, "-c", "3")
+		default:
+			args = append(args, "-c", "2")
+		}
+		if throttling.IOPriority != nil {
+			args = append(args, "-n", strconv.Itoa(int(*throttling.IOPriority)))
+		}
+	}
+
+	args = append(args, pgBaseBackupName)
+	args = append(args, options...)
+	return exec.Command(args[0], args[1:]...) // #nosec
+}
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_basebackup command", func() {
+	options := []string{"-D", "/var/lib/postgresql/data/pgdata"}
+
+	It("runs pg_basebackup directly without throttling", func() {
+		cmd := buildPgBaseBackupCommand(options, nil)
+		Expect(cmd.Args).To(Equal([]string{"pg_basebackup", "-D", "/var/lib/postgresql/data/pgdata"}))
+	})
+
+	It("limits the transfer rate and the priorities of pg_basebackup", func() {
+		cmd := buildPgBaseBackupCommand(options, &apiv1.CloneThrottlingConfiguration{
+			MaxRate:    "100M",
+			Nice:       pointer.Int32(10),
+			IOPriority: pointer.Int32(7),
+		})
+		Expect(cmd.Args).To(Equal([]string{
+			"nice", "-n", "10",
+			"ionice", "-c", "2", "-n", "7",
+			"pg_basebackup", "-D", "/var/lib/postgresql/data/pgdata", "--max-rate", "100M",
+		}))
+	})
+
+	It("uses the idle I/O scheduling class", func() {
+		cmd := buildPgBaseBackupCommand(options, &apiv1.CloneThrottlingConfiguration{
+			IOClass: apiv1.IOSchedulingClassIdle,
+		})
+		Expect(cmd.Args).To(Equal([]string{
+			"ionice", "-c", "3", "pg_basebackup", "-D", "/var/lib/postgresql/data/pgdata",
+		}))
+	})
+})
+
+var _ = Describe("joining from a volume clone", func() {
+	var info InitInfo
+
+	BeforeEach(func() {
+		pgData, err := os.MkdirTemp("", "pgdata")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(pgData)).To(Succeed())
+		})
+		info = InitInfo{
+			PgData:     pgData,
+			ParentNode: "cluster-example-rw",
+			PodName:    "cluster-example-3",
+		}
+	})
+
+	It("refuses a volume without a data directory", func() {
+		Expect(info.JoinFromVolumeClone(&apiv1.Cluster{})).ToNot(Succeed())
+	})
+
+	It("configures the cloned data directory as a new replica", func() {
+		Expect(os.WriteFile(filepath.Join(info.PgData, "PG_VERSION"), []byte("14\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(info.PgData, PostgresqlPidFile), []byte("42\n"), 0o600)).To(Succeed())
+
+		Expect(info.JoinFromVolumeClone(&apiv1.Cluster{})).To(Succeed())
+		Expect(filepath.Join(info.PgData, PostgresqlPidFile)).ToNot(BeAnExistingFile())
+		Expect(filepath.Join(info.PgData, "standby.signal")).To(BeAnExistingFile())
+
+		content, err := os.ReadFile(filepath.Join(info.PgData, "postgresql.auto.conf"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("application_name=cluster-example-3"))
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strconv"
 
 	"github.com/jackc/pgx/v4"
 	"k8s.io/utils/strings/slices"
@@ -99,6 +100,16 @@ func (ds *databaseSnapshotter) exportDatabases(
 			"-d", dsn,
 			"-v",
 		}
+		// In an online import the data is copied by the subscription
+		// to the origin, which is created after the import
+		if ds.cluster.Spec.Bootstrap.InitDB.Import.IsOnline() {
+			options = append(options, "--schema-only")
+		}
+		// Only the directory format supports parallel dumps
+		if jobs := ds.cluster.Spec.Bootstrap.InitDB.Import.GetJobs(); jobs > 1 {
+			options[0] = "-Fd"
+			options = append(options, "-j", strconv.Itoa(jobs))
+		}
 
 		contextLogger.Info("Running pg_dump", "cmd", pgDump,
 			"options", options)
@@ -146,10 +157,11 @@ func (ds *databaseSnapshotter) importDatabases(
 				"-U", "postgres",
 				"-d", targetDatabase,
 				"--section", section,
-				generateFileNameForDatabase(database),
 			}
 
 			options = append(options, alwaysPresentOptions...)
+			options = append(options, ds.getRestoreJobsOptions()...)
+			options = append(options, generateFileNameForDatabase(database))
 
 			contextLogger.Info("Running pg_restore",
 				"cmd", pgRestore,
@@ -204,8 +216,9 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 			fmt.Sprintf("--role=%s", owner),
 			"-d", targetDatabase,
 			"--section", section,
-			generateFileNameForDatabase(database),
 		}
+		options = append(options, ds.getRestoreJobsOptions()...)
+		options = append(options, generateFileNameForDatabase(database))
 
 		contextLogger.Info("Running pg_restore",
 			"cmd", pgRestore,
@@ -227,6 +240,17 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 	return nil
 }
 
+// getRestoreJobsOptions gets the pg_restore options to
+// import the data using parallel jobs, when required
+func (ds *databaseSnapshotter) getRestoreJobsOptions() []string {
+	jobs := ds.cluster.Spec.Bootstrap.InitDB.Import.GetJobs()
+	if jobs <= 1 {
+		return nil
+	}
+
+	return []string{"-j", strconv.Itoa(jobs)}
+}
+
 func (ds *databaseSnapshotter) databaseExists(
 	target *pool.ConnectionPool,
 	dbName string,
@@ -281,6 +305,10 @@ func (ds *databaseSnapshotter) analyze(
 	databases []string,
 ) error {
 	contextLogger := log.FromContext(ctx)
+	if !ds.cluster.Spec.Bootstrap.InitDB.Import.ShouldAnalyze() {
+		contextLogger.Info("skipping the analyze of the imported databases")
+		return nil
+	}
 
 	for _, database := range databases {
 		contextLogger.Info(fmt.Sprintf("running analyze for database: %s", database))
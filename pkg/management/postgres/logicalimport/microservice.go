@@ -66,6 +66,12 @@ func Microservice(
 		return err
 	}
 
+	// The tables are still empty, and will be analyzed by
+	// autovacuum while the subscription copies the data
+	if cluster.Spec.Bootstrap.InitDB.Import.IsOnline() {
+		return nil
+	}
+
 	if err := ds.analyze(ctx, destination, []string{cluster.Spec.Bootstrap.InitDB.Database}); err != nil {
 		return err
 	}
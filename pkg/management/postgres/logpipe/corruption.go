@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	"sync/atomic"
+)
+
+// dataCorruptionSQLStates are the SQLSTATE codes of the errors PostgreSQL
+// raises when it finds damaged data or can't read or write its own files
+var dataCorruptionSQLStates = map[string]bool{
+	"XX001": true, // data_corrupted
+	"XX002": true, // index_corrupted
+	"58030": true, // io_error
+}
+
+// dataCorruptionErrors is the number of data corruption errors logged
+// by PostgreSQL since the instance manager has been started
+var dataCorruptionErrors int64
+
+// GetDataCorruptionErrors gets the number of data corruption errors logged
+// by PostgreSQL since the instance manager has been started
+func GetDataCorruptionErrors() int64 {
+	return atomic.LoadInt64(&dataCorruptionErrors)
+}
+
+// dataCorruptionDetector is a RecordWriter counting the data corruption
+// errors before passing the records to the wrapped writer
+type dataCorruptionDetector struct {
+	RecordWriter
+}
+
+// Write counts the record when it is a data corruption error, then writes it
+func (writer *dataCorruptionDetector) Write(record NamedRecord) {
+	if isDataCorruptionRecord(record) {
+		atomic.AddInt64(&dataCorruptionErrors, 1)
+	}
+	writer.RecordWriter.Write(record)
+}
+
+// isDataCorruptionRecord checks whether the passed record is a data
+// corruption error logged by PostgreSQL
+func isDataCorruptionRecord(record NamedRecord) bool {
+	var loggingRecord *LoggingRecord
+	switch value := record.(type) {
+	case *LoggingRecord:
+		loggingRecord = value
+	case *PgAuditLoggingDecorator:
+		loggingRecord = value.LoggingRecord
+	default:
+		return false
+	}
+
+	return loggingRecord != nil && dataCorruptionSQLStates[loggingRecord.SQLStateCode]
+}
@@ -0,0 +1,39 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Data corruption detection", func() {
+	It("counts the data corruption errors and writes every record", func() {
+		spy := SpyRecordWriter{}
+		writer := dataCorruptionDetector{RecordWriter: &spy}
+		errorsBefore := GetDataCorruptionErrors()
+
+		writer.Write(&LoggingRecord{ErrorSeverity: "ERROR", SQLStateCode: "XX001"})
+		writer.Write(&PgAuditLoggingDecorator{
+			LoggingRecord: &LoggingRecord{ErrorSeverity: "ERROR", SQLStateCode: "58030"},
+		})
+		writer.Write(&LoggingRecord{ErrorSeverity: "ERROR", SQLStateCode: "42P01"})
+
+		Expect(GetDataCorruptionErrors() - errorsBefore).To(BeEquivalentTo(2))
+		Expect(spy.records).To(HaveLen(3))
+	})
+})
@@ -147,7 +147,7 @@ func (p *LogPipe) collectLogsFromFile(ctx context.Context) error {
 	// the cancellation signal happened
 	go func() {
 		defer close(errChan)
-		errChan <- p.streamLogFromCSVFile(ctx, f, &LogRecordWriter{})
+		errChan <- p.streamLogFromCSVFile(ctx, f, &dataCorruptionDetector{RecordWriter: &LogRecordWriter{}})
 	}()
 	select {
 	case <-ctx.Done():
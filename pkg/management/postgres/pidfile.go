@@ -17,6 +17,7 @@ limitations under the License.
 package postgres
 
 import (
+	"errors"
 	"os"
 	"path"
 	"strconv"
@@ -83,6 +84,27 @@ func (instance *Instance) CheckForExistingPostmaster(postgresExecutables ...stri
 	return os.FindProcess(pid)
 }
 
+// IsPostmasterRunning checks if the postmaster process referenced by
+// the PID file in the data directory is running. Differently from
+// CheckForExistingPostmaster, the PID file is never removed
+func (instance *Instance) IsPostmasterRunning(postgresExecutables ...string) (bool, error) {
+	pidFile := path.Join(instance.PgData, PostgresqlPidFile)
+	_, pid, err := instance.GetPostmasterPidFromFile(pidFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	process, err := ps.FindProcess(pid)
+	if err != nil {
+		return false, err
+	}
+
+	return process != nil && slices.Contains(postgresExecutables, process.Executable()), nil
+}
+
 // CleanUpStalePid cleans up the files left around by a crashed PostgreSQL instance.
 // It removes the default PostgreSQL pid file and the content of the socket directory.
 func (instance *Instance) CleanUpStalePid() error {
@@ -102,4 +102,32 @@ var _ = Describe("the detection of a postmaster process using the pid file", fun
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(process).ToNot(BeNil())
 	})
+	It("detects a running postmaster without touching the PID file", func() {
+		myPid := os.Getpid()
+		instance := NewInstance()
+		instance.PgData = pgdata
+		instance.SocketDirectory = socketDir
+
+		running, err := instance.IsPostmasterRunning(postgresName)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(running).To(BeFalse())
+
+		pidFile := filepath.Join(pgdata, PostgresqlPidFile)
+		err = os.WriteFile(pidFile, []byte(fmt.Sprintf("%v", myPid)), 0o400)
+		Expect(err).ShouldNot(HaveOccurred())
+		myProcess, err := ps.FindProcess(myPid)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		running, err = instance.IsPostmasterRunning(myProcess.Executable())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(running).To(BeTrue())
+
+		running, err = instance.IsPostmasterRunning("not_existent_executable")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(running).To(BeFalse())
+
+		result, err := fileutils.FileExists(pidFile)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result).To(BeTrue())
+	})
 })
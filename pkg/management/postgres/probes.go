@@ -30,23 +30,26 @@ import (
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
-// IsServerHealthy check if the instance is healthy
+// IsServerHealthy check if the instance is healthy, that is if the
+// postmaster process is running. A server failing to accept connections
+// is not considered unhealthy, as restarting it wouldn't help: this
+// condition is detected by the readiness probe instead
 func (instance *Instance) IsServerHealthy() error {
-	err := instance.PgIsReady()
-
-	// A healthy server can also be actively rejecting connections.
-	// That's not a problem: it's only the server starting up or shutting
-	// down.
-	if errors.Is(err, ErrPgRejectingConnection) {
-		return nil
+	running, err := instance.IsPostmasterRunning(postgresName)
+	if err != nil {
+		return fmt.Errorf("while checking the postmaster process: %w", err)
+	}
+	if !running {
+		return ErrPostmasterNotRunning
 	}
 
-	return err
+	return nil
 }
 
 // IsServerReady check if the instance is healthy and can really accept connections
@@ -113,6 +116,11 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 	}
 
 	if result.PendingRestart {
+		result.PendingRestartParameters, err = GetPendingRestartParameters(superUserDB)
+		if err != nil {
+			return result, err
+		}
+
 		err = updateResultForDecrease(instance, superUserDB, result)
 		if err != nil {
 			return result, err
@@ -124,6 +132,18 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		return result, err
 	}
 
+	result.DriftedParameters, err = GetDriftedParameters(superUserDB)
+	if err != nil {
+		return result, err
+	}
+
+	result.ChecksumFailures, err = instance.getChecksumFailures(superUserDB)
+	if err != nil {
+		return result, err
+	}
+	result.DataCorruptionErrors = logpipe.GetDataCorruptionErrors()
+	result.StorageWriteLatencyMs = instance.GetStorageWriteLatency().Milliseconds()
+
 	result.InstanceArch = runtime.GOARCH
 
 	result.ExecutableHash, err = executablehash.Get()
@@ -244,6 +264,83 @@ WHERE pending_settings.name IN (
 	return decreasedSensibleValues, nil
 }
 
+// getChecksumFailures gets the number of data page checksum failures
+// detected in the databases of the instance. The counter is available
+// since PostgreSQL 12
+func (instance *Instance) getChecksumFailures(superUserDB *sql.DB) (int64, error) {
+	version, err := instance.GetPgVersion()
+	if err != nil || version.Major < 12 {
+		return 0, err
+	}
+
+	var checksumFailures int64
+	row := superUserDB.QueryRow(
+		"SELECT COALESCE(SUM(checksum_failures), 0) FROM pg_catalog.pg_stat_database")
+	if err := row.Scan(&checksumFailures); err != nil {
+		return 0, err
+	}
+
+	return checksumFailures, nil
+}
+
+// GetDriftedParameters gets the names of the configuration parameters set
+// in the "postgresql.auto.conf" file, i.e. via ALTER SYSTEM, ignoring the
+// ones written there by the instance manager to configure the replication
+func GetDriftedParameters(superUserDB *sql.DB) ([]string, error) {
+	rows, err := superUserDB.Query(
+		`SELECT DISTINCT name FROM pg_catalog.pg_file_settings
+		WHERE sourcefile = pg_catalog.current_setting('data_directory') || '/postgresql.auto.conf'
+			AND name NOT IN (
+				'archive_mode',
+				'primary_conninfo',
+				'primary_slot_name',
+				'recovery_target_timeline',
+				'restore_command'
+			)
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var parameters []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, name)
+	}
+
+	return parameters, rows.Err()
+}
+
+// GetPendingRestartParameters gets the names of the configuration parameters
+// whose new value has been reloaded but can only be applied by a restart
+func GetPendingRestartParameters(superUserDB *sql.DB) ([]string, error) {
+	rows, err := superUserDB.Query(
+		`SELECT name FROM pg_catalog.pg_settings WHERE pending_restart ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var parameters []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, name)
+	}
+
+	return parameters, rows.Err()
+}
+
 // fillStatus extract the current instance information into the PostgresqlStatus
 // structure
 func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
@@ -419,6 +516,98 @@ func (instance *Instance) IsWALReceiverActive() (bool, error) {
 	return result, nil
 }
 
+// IsStreamingReplicationHealthy checks if a standby is receiving
+// the WAL files from its upstream via streaming replication
+func (instance *Instance) IsStreamingReplicationHealthy() error {
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if isPrimary {
+		return nil
+	}
+
+	isWalReceiverActive, err := instance.IsWALReceiverActive()
+	if err != nil {
+		return err
+	}
+	if !isWalReceiverActive {
+		return ErrWALReceiverNotActive
+	}
+
+	return nil
+}
+
+// GetStandbyReplayLag gets the amount of WAL, in bytes, that the standby
+// still has to replay to reach the last position reported by its upstream
+func (instance *Instance) GetStandbyReplayLag() (int64, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return 0, err
+	}
+
+	// The latest_end_lsn column is updated with the end of the WAL
+	// of the sender, which is periodically sent even when idle
+	var lag int64
+	row := superUserDB.QueryRow(
+		`SELECT COALESCE(pg_catalog.pg_wal_lsn_diff(latest_end_lsn, pg_catalog.pg_last_wal_replay_lsn()), 0)::bigint
+		FROM pg_catalog.pg_stat_wal_receiver`)
+	err = row.Scan(&lag)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrWALReceiverNotActive
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if lag < 0 {
+		return 0, nil
+	}
+	return lag, nil
+}
+
+// IsArchiverHealthy checks that the last attempt of the primary to
+// archive a WAL file didn't fail
+func (instance *Instance) IsArchiverHealthy() error {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var failing bool
+	row := superUserDB.QueryRow(
+		`SELECT COALESCE(last_failed_time > COALESCE(last_archived_time, '-infinity'), false)
+		FROM pg_catalog.pg_stat_archiver`)
+	if err := row.Scan(&failing); err != nil {
+		return err
+	}
+	if failing {
+		return ErrArchiverFailing
+	}
+
+	return nil
+}
+
+// GetRecoveryProgress gets the position of the last WAL record replayed by
+// a standby, and whether some of the WAL it received is still to be replayed.
+// WAL restored from the archive is not considered as received
+func (instance *Instance) GetRecoveryProgress() (replayLSN postgres.LSN, pending bool, err error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return "", false, err
+	}
+
+	row := superUserDB.QueryRow(
+		`SELECT COALESCE(pg_catalog.pg_last_wal_replay_lsn()::text, ''),
+		COALESCE(pg_catalog.pg_last_wal_receive_lsn() > pg_catalog.pg_last_wal_replay_lsn(), false)
+		AND NOT pg_catalog.pg_is_wal_replay_paused()`)
+	if err := row.Scan(&replayLSN, &pending); err != nil {
+		return "", false, err
+	}
+
+	return replayLSN, pending, nil
+}
+
 // PgStatWal is a representation of the pg_stat_wal table
 type PgStatWal struct {
 	WalRecords     int64
@@ -553,11 +553,12 @@ func (info InitInfo) WriteInitialPostgresqlConf(cluster *apiv1.Cluster) error {
 	return err
 }
 
-// WriteRestoreHbaConf writes a pg_hba.conf allowing access without password from localhost.
-// this is needed to set the PostgreSQL password after the postgres server is started and active
+// WriteRestoreHbaConf writes a pg_hba.conf allowing only the local access via peer
+// authentication. This is needed to configure the instance during the bootstrap,
+// before the certificates and the passwords of the cluster are available
 func (info InitInfo) WriteRestoreHbaConf() error {
-	// We allow every access from localhost, and this is needed to correctly restore
-	// the database
+	// Only the connections via the Unix socket are allowed, and they
+	// are authenticated from the operating system user
 	_, err := fileutils.WriteStringToFile(
 		path.Join(info.PgData, constants.PostgresqlHBARulesFile),
 		"local all all peer map=local\n")
@@ -569,6 +570,24 @@ func (info InitInfo) WriteRestoreHbaConf() error {
 	return WritePostgresUserMaps(info.PgData)
 }
 
+// auditBootstrapHBA checks the pg_hba.conf used to start a temporary instance
+// during the bootstrap, refusing rules allowing access without authentication
+func auditBootstrapHBA(pgData string) error {
+	hbaFile := path.Join(pgData, constants.PostgresqlHBARulesFile)
+	content, err := os.ReadFile(hbaFile) // #nosec
+	if err != nil {
+		return fmt.Errorf("while reading %v: %w", hbaFile, err)
+	}
+
+	if trustRules := postgresSpec.GetTrustRules(string(content)); len(trustRules) > 0 {
+		return fmt.Errorf("the bootstrap pg_hba.conf contains rules using trust authentication: %v",
+			strings.Join(trustRules, "; "))
+	}
+
+	log.Info("Audited the bootstrap pg_hba.conf", "content", string(content))
+	return nil
+}
+
 // ConfigureInstanceAfterRestore changes the superuser password
 // of the instance to be coherent with the one specified in the
 // cluster. This function also ensures that we can really connect
@@ -582,6 +601,10 @@ func (info InitInfo) ConfigureInstanceAfterRestore(cluster *apiv1.Cluster, env [
 		return fmt.Errorf("cannot detect major version: %w", err)
 	}
 
+	if err = auditBootstrapHBA(info.PgData); err != nil {
+		return err
+	}
+
 	// This will start the recovery of WALs taken during the backup
 	// and, after that, the server will start in a new timeline
 	if err = instance.WithActiveInstance(func() error {
@@ -654,7 +677,8 @@ func (info *InitInfo) checkBackupDestination(
 
 	// Instantiate the WALArchiver to get the proper configuration
 	var walArchiver *archiver.WALArchiver
-	walArchiver, err = archiver.New(ctx, cluster, env, walarchive.SpoolDirectory, info.PgData)
+	walArchiver, err = archiver.New(
+		ctx, cluster, env, walarchive.SpoolDirectory, walarchive.ChecksumDirectory, info.PgData)
 	if err != nil {
 		return fmt.Errorf("while creating the archiver: %w", err)
 	}
@@ -148,3 +148,33 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(chg).To(BeFalse())
 	})
 })
+
+var _ = Describe("bootstrap pg_hba.conf", func() {
+	var pgData string
+
+	BeforeEach(func() {
+		var err error
+		pgData, err = os.MkdirTemp("", "bootstrap-hba")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(pgData)).To(Succeed())
+		})
+	})
+
+	It("only allows local connections authenticated via peer", func() {
+		Expect(InitInfo{PgData: pgData}.WriteRestoreHbaConf()).To(Succeed())
+		Expect(auditBootstrapHBA(pgData)).To(Succeed())
+	})
+
+	It("refuses the rules using trust authentication", func() {
+		Expect(os.WriteFile(path.Join(pgData, "pg_hba.conf"),
+			[]byte("local all all peer\nhost all all 127.0.0.1/32 trust\n"), 0o600)).To(Succeed())
+		err := auditBootstrapHBA(pgData)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("host all all 127.0.0.1/32 trust"))
+	})
+
+	It("fails when the pg_hba.conf is missing", func() {
+		Expect(auditBootstrapHBA(pgData)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// storageLatencyProbeFileName is the name of the file written in the
+	// data volume to measure the latency of the storage
+	storageLatencyProbeFileName = ".storage-latency-probe"
+
+	// storageLatencyWaitTimeout is how long the status of the instance
+	// waits for a measurement of the storage latency to complete
+	storageLatencyWaitTimeout = 1 * time.Second
+
+	// storageLatencyResolution is the resolution of the reported latency,
+	// avoiding to report the status of the instance as changed for every
+	// small fluctuation
+	storageLatencyResolution = 100 * time.Millisecond
+)
+
+// storageLatencyMonitor measures how long it takes to write and flush
+// a small file in the data volume. A measurement stuck on a stalled
+// storage is never repeated until it completes
+type storageLatencyMonitor struct {
+	mutex         sync.Mutex
+	lastLatency   time.Duration
+	inFlightSince time.Time
+	done          chan struct{}
+}
+
+// measure gets the latency of the storage where the passed directory
+// resides. When the storage is stalled, the time elapsed since the
+// measurement in progress has been started is returned instead
+func (monitor *storageLatencyMonitor) measure(directory string) time.Duration {
+	monitor.mutex.Lock()
+	if monitor.inFlightSince.IsZero() {
+		monitor.inFlightSince = time.Now()
+		monitor.done = make(chan struct{})
+		go monitor.run(directory, monitor.inFlightSince, monitor.done)
+	}
+	done := monitor.done
+	monitor.mutex.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(storageLatencyWaitTimeout):
+	}
+
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	latency := monitor.lastLatency
+	if !monitor.inFlightSince.IsZero() {
+		if elapsed := time.Since(monitor.inFlightSince); elapsed > latency {
+			latency = elapsed
+		}
+	}
+	return latency.Truncate(storageLatencyResolution)
+}
+
+// run writes and flushes the probe file, then records the latency
+func (monitor *storageLatencyMonitor) run(directory string, startedAt time.Time, done chan struct{}) {
+	if err := writeStorageLatencyProbe(directory); err != nil {
+		log.Warning("Cannot measure the storage latency", "directory", directory, "err", err.Error())
+	}
+
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	monitor.lastLatency = time.Since(startedAt)
+	monitor.inFlightSince = time.Time{}
+	close(done)
+}
+
+// writeStorageLatencyProbe writes the probe file and flushes it to disk
+func writeStorageLatencyProbe(directory string) error {
+	file, err := os.OpenFile(
+		filepath.Join(directory, storageLatencyProbeFileName),
+		os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.WriteString(time.Now().Format(time.RFC3339Nano)); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// GetStorageWriteLatency gets the latency of the storage of the data
+// directory, with a resolution of 100 milliseconds
+func (instance *Instance) GetStorageWriteLatency() time.Duration {
+	return instance.storageLatency.measure(filepath.Dir(instance.PgData))
+}
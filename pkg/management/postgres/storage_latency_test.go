@@ -0,0 +1,48 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage latency monitor", func() {
+	It("measures the latency writing a probe file", func() {
+		directory, err := os.MkdirTemp("", "storage-latency")
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			_ = os.RemoveAll(directory)
+		}()
+
+		var monitor storageLatencyMonitor
+		Expect(monitor.measure(directory)).To(BeNumerically("<", storageLatencyWaitTimeout))
+		Expect(filepath.Join(directory, storageLatencyProbeFileName)).To(BeARegularFile())
+	})
+
+	It("reports the age of a pending measurement", func() {
+		monitor := storageLatencyMonitor{
+			inFlightSince: time.Now().Add(-5 * time.Second),
+			done:          make(chan struct{}),
+		}
+		Expect(monitor.measure("/nonexistent")).To(BeNumerically(">=", 5*time.Second))
+	})
+})
@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// drainCheckInterval is how often the running transactions are
+// counted while draining the connections
+const drainCheckInterval = 1 * time.Second
+
+// RejectNewConnections makes the instance reject every new connection
+// but the local and the streaming replication ones, without affecting
+// the connections already established. The HBA rules of the cluster
+// are restored the next time they are refreshed
+func (instance *Instance) RejectNewConnections() error {
+	if _, err := InstallPgDataFileContent(
+		instance.PgData,
+		postgres.DrainingHBARules,
+		constants.PostgresqlHBARulesFile); err != nil {
+		return fmt.Errorf("installing the draining HBA rules: %w", err)
+	}
+
+	return instance.Reload()
+}
+
+// CountRunningTransactions gets the number of transactions running in
+// the connections opened by remote clients
+func (instance *Instance) CountRunningTransactions() (int, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	row := superUserDB.QueryRow(
+		`SELECT pg_catalog.count(*)
+		FROM pg_catalog.pg_stat_activity
+		WHERE backend_type = 'client backend'
+		AND client_addr IS NOT NULL
+		AND xact_start IS NOT NULL
+		AND usename <> $1`,
+		apiv1.StreamingReplicationUser)
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DrainConnections stops accepting new connections and waits, up to the
+// passed timeout, for the running transactions to complete
+func (instance *Instance) DrainConnections(ctx context.Context, timeout time.Duration) error {
+	if err := instance.RejectNewConnections(); err != nil {
+		return err
+	}
+
+	return waitForTransactionsToComplete(ctx, instance.CountRunningTransactions, timeout, drainCheckInterval)
+}
+
+// waitForTransactionsToComplete polls the passed function until no
+// transaction is running anymore or the timeout expires
+func waitForTransactionsToComplete(
+	ctx context.Context,
+	countRunningTransactions func() (int, error),
+	timeout time.Duration,
+	interval time.Duration,
+) error {
+	contextLogger := log.FromContext(ctx)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		count, err := countRunningTransactions()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			contextLogger.Info("No transaction is running anymore, the connections are drained")
+			return nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			contextLogger.Warning("Timeout expired while waiting for the running transactions to complete",
+				"runningTransactions", count, "timeout", timeout.String())
+			return nil
+		}
+
+		contextLogger.Debug("Waiting for the running transactions to complete",
+			"runningTransactions", count)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("waitForTransactionsToComplete", func() {
+	It("returns as soon as no transaction is running", func() {
+		counts := []int{2, 1, 0}
+		calls := 0
+		err := waitForTransactionsToComplete(context.Background(), func() (int, error) {
+			count := counts[calls]
+			calls++
+			return count, nil
+		}, time.Second, time.Millisecond)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("stops waiting when the timeout expires", func() {
+		start := time.Now()
+		err := waitForTransactionsToComplete(context.Background(), func() (int, error) {
+			return 1, nil
+		}, 50*time.Millisecond, 10*time.Millisecond)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("reports the errors while counting the transactions", func() {
+		err := waitForTransactionsToComplete(context.Background(), func() (int, error) {
+			return 0, fmt.Errorf("connection refused")
+		}, time.Second, time.Millisecond)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"fmt"
+	"sync"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// recoveryProgressTracker remembers the WAL position replayed by the
+// standby when a probe last checked it
+type recoveryProgressTracker struct {
+	mutex         sync.Mutex
+	lastReplayLSN postgresSpec.LSN
+}
+
+// check fails when the replay position didn't move since the previous
+// check while some received WAL is waiting to be replayed
+func (tracker *recoveryProgressTracker) check(replayLSN postgresSpec.LSN, pending bool) error {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	lastReplayLSN := tracker.lastReplayLSN
+	tracker.lastReplayLSN = replayLSN
+	if pending && replayLSN != "" && replayLSN == lastReplayLSN {
+		return fmt.Errorf("the recovery is not progressing, the replay is stuck at %s", replayLSN)
+	}
+
+	return nil
+}
+
+// loadProbeConfiguration gets the configuration of a probe from the
+// cached cluster definition. Without it, no additional check is run
+func loadProbeConfiguration(
+	selector func(*apiv1.ProbesConfiguration) *apiv1.ProbeConfiguration,
+) *apiv1.ProbeConfiguration {
+	cluster, err := cache.LoadCluster()
+	if err != nil {
+		return nil
+	}
+
+	return selector(cluster.Spec.Probes)
+}
+
+// runHealthChecks runs the additional checks requested for a probe,
+// using the passed tracker to follow the progress of the recovery
+func (ws *remoteWebserverEndpoints) runHealthChecks(
+	probe *apiv1.ProbeConfiguration,
+	recovery *recoveryProgressTracker,
+) error {
+	if probe == nil || len(probe.Checks) == 0 {
+		return nil
+	}
+
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if probe.HasCheck(apiv1.HealthCheckWALReceiver) && !isPrimary && ws.isStreamingReplicationExpected() {
+		if err := ws.instance.IsStreamingReplicationHealthy(); err != nil {
+			return err
+		}
+	}
+
+	if probe.HasCheck(apiv1.HealthCheckArchiver) && isPrimary {
+		if err := ws.instance.IsArchiverHealthy(); err != nil {
+			return err
+		}
+	}
+
+	if probe.HasCheck(apiv1.HealthCheckRecoveryProgress) && !isPrimary {
+		replayLSN, pending, err := ws.instance.GetRecoveryProgress()
+		if err != nil {
+			return err
+		}
+		if err := recovery.check(replayLSN, pending); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 
 	"k8s.io/client-go/tools/record"
@@ -30,6 +31,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
@@ -61,6 +63,7 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
 	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux.HandleFunc(url.PathPgBackupVerify, endpoints.verifyBackup)
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
@@ -188,3 +191,91 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 
 	_, _ = fmt.Fprint(w, "OK")
 }
+
+// This function verifies the artifacts of a completed backup
+func (ws *localWebserverEndpoints) verifyBackup(w http.ResponseWriter, r *http.Request) {
+	var cluster apiv1.Cluster
+	var backup apiv1.Backup
+
+	ctx := r.Context()
+
+	backupName := r.URL.Query().Get("name")
+	if len(backupName) == 0 {
+		http.Error(w, "Missing backup name parameter", http.StatusBadRequest)
+		return
+	}
+
+	if err := ws.typedClient.Get(ctx, client.ObjectKey{
+		Namespace: ws.instance.Namespace,
+		Name:      ws.instance.ClusterName,
+	}, &cluster); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting cluster: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if err := ws.typedClient.Get(ctx, client.ObjectKey{
+		Namespace: ws.instance.Namespace,
+		Name:      backupName,
+	}, &backup); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting backup: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if backup.Status.Phase != apiv1.BackupPhaseCompleted {
+		http.Error(w, "Only completed backups can be verified", http.StatusConflict)
+		return
+	}
+
+	objectStore := &apiv1.BarmanObjectStoreConfiguration{
+		BarmanCredentials: backup.Status.BarmanCredentials,
+		EndpointCA:        backup.Status.EndpointCA,
+	}
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		ws.typedClient,
+		cluster.Namespace,
+		objectStore,
+		os.Environ())
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting backup credentials: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	segmentSize, err := ws.instance.GetWALSegmentSize()
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting the WAL segment size: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	verification, err := postgres.VerifyBackup(ctx, &cluster, &backup, env, segmentSize)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while verifying backup: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	backup.Status.Verification = verification
+	if err := postgres.UpdateBackupStatusAndRetry(ctx, ws.typedClient, &backup); err != nil {
+		log.Error(err, "Can't set the backup verification result", "backupName", backup.Name)
+	}
+	if !verification.IsSuccessful() {
+		ws.eventRecorder.Event(&backup, "Warning", "VerificationFailed", strings.Join(verification.Errors, "; "))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(verification)
+}
@@ -67,7 +67,10 @@ type metrics struct {
 	PgVersion                *prometheus.GaugeVec
 	FirstRecoverabilityPoint prometheus.Gauge
 	FencingOn                prometheus.Gauge
+	DriftedParameters        prometheus.Gauge
 	PgStatWalMetrics         PgStatWalMetrics
+	PgStatStatementsMetrics  PgStatStatementsMetrics
+	WaitEventsMetrics        WaitEventsMetrics
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -175,6 +178,13 @@ func newMetrics() *metrics {
 			Name:      "fencing_on",
 			Help:      "1 if the instance is fenced, 0 otherwise",
 		}),
+		DriftedParameters: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "drifted_parameters",
+			Help: "Number of configuration parameters set outside the cluster specification, " +
+				"i.e. via ALTER SYSTEM",
+		}),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -232,6 +242,8 @@ func newMetrics() *metrics {
 					"fsync_writethrough, otherwise zero). Only available on PG 14+",
 			}, []string{"stats_reset"}),
 		},
+		PgStatStatementsMetrics: newPgStatStatementsMetrics(),
+		WaitEventsMetrics:       newWaitEventsMetrics(),
 	}
 }
 
@@ -250,6 +262,9 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.PgVersion.Describe(ch)
 	e.Metrics.FirstRecoverabilityPoint.Describe(ch)
 	e.Metrics.FencingOn.Describe(ch)
+	ch <- e.Metrics.DriftedParameters.Desc()
+	e.Metrics.PgStatStatementsMetrics.describe(ch)
+	e.Metrics.WaitEventsMetrics.describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -284,6 +299,9 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.PgWALDirectory.Collect(ch)
 	e.Metrics.PgVersion.Collect(ch)
 	e.Metrics.FirstRecoverabilityPoint.Collect(ch)
+	ch <- e.Metrics.DriftedParameters
+	e.Metrics.PgStatStatementsMetrics.collect(ch)
+	e.Metrics.WaitEventsMetrics.collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -380,6 +398,12 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.Metrics.PgVersion.Reset()
 	}
 
+	if err := collectDriftedParameters(e, db); err != nil {
+		log.Error(err, "while collecting the drifted configuration parameters")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.DriftedParameters").Inc()
+	}
+
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		if err := collectPGWALStat(e); err != nil {
 			log.Error(err, "while collecting pg_wal_stat")
@@ -387,6 +411,32 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWALStat").Inc()
 		}
 	}
+
+	e.collectPgStatStatements(db)
+}
+
+func (e *Exporter) collectPgStatStatements(db *sql.DB) {
+	const errorLabel = "Collect.PgStatStatements"
+
+	cluster, err := cache.LoadCluster()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "error while retrieving cluster cache object")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues(errorLabel).Inc()
+		e.Metrics.PgStatStatementsMetrics.reset()
+		return
+	}
+
+	if err := collectPgStatStatements(e, db, cluster); err != nil {
+		log.Error(err, "while collecting pg_stat_statements")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues(errorLabel).Inc()
+		e.Metrics.PgStatStatementsMetrics.reset()
+	}
 }
 
 func (e *Exporter) collectFromPrimaryFirstPointOnTimeRecovery() {
@@ -463,6 +513,16 @@ func collectPGVersion(e *Exporter) error {
 	return nil
 }
 
+func collectDriftedParameters(e *Exporter, db *sql.DB) error {
+	parameters, err := postgres.GetDriftedParameters(db)
+	if err != nil {
+		return err
+	}
+
+	e.Metrics.DriftedParameters.Set(float64(len(parameters)))
+	return nil
+}
+
 func collectPGWalArchiveMetric(exporter *Exporter) error {
 	ready, done, err := postgres.GetWALArchiveCounters()
 	if err != nil {
@@ -0,0 +1,176 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// pgStatStatementsQueryTemplate extracts the statistics of the most
+// time-consuming queries. The name of the column containing the total
+// execution time changed in PostgreSQL 13
+const pgStatStatementsQueryTemplate = `
+SELECT s.queryid::text,
+	d.datname,
+	r.rolname,
+	s.calls,
+	s.%[1]s / 1000.0,
+	s.rows,
+	s.shared_blks_hit,
+	s.shared_blks_read
+FROM pg_catalog.pg_stat_statements s
+JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+JOIN pg_catalog.pg_roles r ON r.oid = s.userid
+WHERE s.queryid IS NOT NULL
+ORDER BY s.%[1]s DESC
+LIMIT $1`
+
+// PgStatStatementsMetrics contains the statistics of the top queries
+// tracked by pg_stat_statements, labelled by query identifier
+type PgStatStatementsMetrics struct {
+	Calls          *prometheus.GaugeVec
+	TotalTime      *prometheus.GaugeVec
+	Rows           *prometheus.GaugeVec
+	SharedBlksHit  *prometheus.GaugeVec
+	SharedBlksRead *prometheus.GaugeVec
+}
+
+func newPgStatStatementsMetrics() PgStatStatementsMetrics {
+	subsystem := "pg_stat_statements"
+	labels := []string{"queryid", "datname", "usename"}
+	return PgStatStatementsMetrics{
+		Calls: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "calls",
+			Help:      "Number of times the query has been executed",
+		}, labels),
+		TotalTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "total_time_seconds",
+			Help:      "Total time spent executing the query, in seconds",
+		}, labels),
+		Rows: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "rows",
+			Help:      "Total number of rows retrieved or affected by the query",
+		}, labels),
+		SharedBlksHit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "shared_blks_hit",
+			Help:      "Total number of shared block cache hits by the query",
+		}, labels),
+		SharedBlksRead: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "shared_blks_read",
+			Help:      "Total number of shared blocks read by the query",
+		}, labels),
+	}
+}
+
+func (m PgStatStatementsMetrics) describe(ch chan<- *prometheus.Desc) {
+	m.Calls.Describe(ch)
+	m.TotalTime.Describe(ch)
+	m.Rows.Describe(ch)
+	m.SharedBlksHit.Describe(ch)
+	m.SharedBlksRead.Describe(ch)
+}
+
+func (m PgStatStatementsMetrics) collect(ch chan<- prometheus.Metric) {
+	m.Calls.Collect(ch)
+	m.TotalTime.Collect(ch)
+	m.Rows.Collect(ch)
+	m.SharedBlksHit.Collect(ch)
+	m.SharedBlksRead.Collect(ch)
+}
+
+func (m PgStatStatementsMetrics) reset() {
+	m.Calls.Reset()
+	m.TotalTime.Reset()
+	m.Rows.Reset()
+	m.SharedBlksHit.Reset()
+	m.SharedBlksRead.Reset()
+}
+
+// collectPgStatStatements exports the statistics of the top queries
+// tracked by pg_stat_statements, if enabled in the cluster
+func collectPgStatStatements(e *Exporter, db *sql.DB, cluster *apiv1.Cluster) error {
+	metrics := e.Metrics.PgStatStatementsMetrics
+
+	// The set of top queries changes over time, we don't want
+	// to keep exporting the ones that are not there anymore
+	metrics.reset()
+
+	if !cluster.Spec.Monitoring.IsPgStatStatementsEnabled() {
+		return nil
+	}
+
+	// The extension is created by the instance manager after the
+	// library has been loaded, which requires a restart
+	var isInstalled bool
+	row := db.QueryRow("SELECT COUNT(*) > 0 FROM pg_catalog.pg_extension WHERE extname = $1",
+		apiv1.PgStatStatementsExtensionName)
+	if err := row.Scan(&isInstalled); err != nil {
+		return err
+	}
+	if !isInstalled {
+		return nil
+	}
+
+	totalTimeColumn := "total_exec_time"
+	if version, err := e.instance.GetPgVersion(); err != nil {
+		return err
+	} else if version.Major < 13 {
+		totalTimeColumn = "total_time"
+	}
+
+	rows, err := db.Query(
+		fmt.Sprintf(pgStatStatementsQueryTemplate, totalTimeColumn),
+		cluster.Spec.Monitoring.GetPgStatStatementsTopN())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var queryID, datname, usename string
+		var calls, totalTime, rowCount, sharedBlksHit, sharedBlksRead float64
+		if err := rows.Scan(&queryID, &datname, &usename,
+			&calls, &totalTime, &rowCount, &sharedBlksHit, &sharedBlksRead); err != nil {
+			return err
+		}
+
+		metrics.Calls.WithLabelValues(queryID, datname, usename).Set(calls)
+		metrics.TotalTime.WithLabelValues(queryID, datname, usename).Set(totalTime)
+		metrics.Rows.WithLabelValues(queryID, datname, usename).Set(rowCount)
+		metrics.SharedBlksHit.WithLabelValues(queryID, datname, usename).Set(sharedBlksHit)
+		metrics.SharedBlksRead.WithLabelValues(queryID, datname, usename).Set(sharedBlksRead)
+	}
+
+	return rows.Err()
+}
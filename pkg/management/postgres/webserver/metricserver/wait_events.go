@@ -0,0 +1,181 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// waitEventsQuery counts the sessions waiting on every wait event,
+// grouped by database. Idle sessions are waiting for the client
+// to send a new command and are not interesting
+const waitEventsQuery = `
+SELECT datname,
+	wait_event_type,
+	wait_event,
+	COUNT(*)
+FROM pg_catalog.pg_stat_activity
+WHERE backend_type = 'client backend'
+	AND state <> 'idle'
+	AND datname IS NOT NULL
+	AND wait_event_type IS NOT NULL
+	AND pid <> pg_catalog.pg_backend_pid()
+GROUP BY 1, 2, 3`
+
+// WaitEventsMetrics contains the time spent by the sessions waiting,
+// estimated by periodically sampling pg_stat_activity
+type WaitEventsMetrics struct {
+	WaitTime *prometheus.CounterVec
+	Samples  prometheus.Counter
+}
+
+func newWaitEventsMetrics() WaitEventsMetrics {
+	subsystem := "wait_events"
+	return WaitEventsMetrics{
+		WaitTime: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "wait_time_seconds_total",
+			Help: "Estimated time spent by the sessions waiting on the wait event, " +
+				"computed as the number of waiting sessions multiplied by the sampling period",
+		}, []string{"datname", "wait_event_type", "wait_event"}),
+		Samples: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "samples_total",
+			Help:      "Number of times the wait events have been sampled",
+		}),
+	}
+}
+
+func (m WaitEventsMetrics) describe(ch chan<- *prometheus.Desc) {
+	m.WaitTime.Describe(ch)
+	m.Samples.Describe(ch)
+}
+
+func (m WaitEventsMetrics) collect(ch chan<- prometheus.Metric) {
+	m.WaitTime.Collect(ch)
+	m.Samples.Collect(ch)
+}
+
+// WaitEventsSampler is a runner periodically sampling the wait events
+// of the sessions connected to the instance, when enabled in the cluster
+type WaitEventsSampler struct {
+	exporter *Exporter
+}
+
+// NewWaitEventsSampler creates a new wait events sampler updating
+// the metrics of the passed exporter
+func NewWaitEventsSampler(exporter *Exporter) *WaitEventsSampler {
+	return &WaitEventsSampler{
+		exporter: exporter,
+	}
+}
+
+// Start starts running the wait events sampler
+func (ws *WaitEventsSampler) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("WaitEventsSampler")
+
+	period := apiv1.DefaultWaitEventSamplingPeriod * time.Millisecond
+	ticker := time.NewTicker(period)
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated wait events sampler loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		cluster, err := cache.LoadCluster()
+		// there isn't a cached object yet
+		if errors.Is(err, cache.ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			contextLog.Warning("while retrieving cluster cache object", "err", err)
+			continue
+		}
+
+		// The cluster is checked at every tick, so that the sampling
+		// starts as soon as the feature is enabled
+		if !cluster.Spec.Monitoring.IsWaitEventSamplingEnabled() {
+			continue
+		}
+
+		// Update the ticker if the sampling period has changed
+		if newPeriod := cluster.Spec.Monitoring.GetWaitEventSamplingPeriod(); newPeriod != period {
+			ticker.Reset(newPeriod)
+			period = newPeriod
+		}
+
+		if err := ws.sample(period); err != nil {
+			contextLog.Debug("while sampling wait events", "err", err)
+			ws.exporter.Metrics.PgCollectionErrors.WithLabelValues("Collect.WaitEvents").Inc()
+		}
+	}
+}
+
+// sample counts the sessions waiting on each wait event, accounting
+// them as waiting for the whole sampling period
+func (ws *WaitEventsSampler) sample(period time.Duration) error {
+	instance := ws.exporter.instance
+	if instance.IsFenced() || instance.MightBeUnavailable() {
+		return nil
+	}
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(waitEventsQuery)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	metrics := ws.exporter.Metrics.WaitEventsMetrics
+	for rows.Next() {
+		var datname, waitEventType, waitEvent string
+		var sessions float64
+		if err := rows.Scan(&datname, &waitEventType, &waitEvent, &sessions); err != nil {
+			return err
+		}
+
+		metrics.WaitTime.WithLabelValues(datname, waitEventType, waitEvent).Add(sessions * period.Seconds())
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	metrics.Samples.Inc()
+	return nil
+}
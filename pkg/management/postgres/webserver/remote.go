@@ -17,24 +17,49 @@ limitations under the License.
 package webserver
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/upgrade"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 )
 
+// archiveWALTimeout is the time the archiver is given to archive the
+// current WAL file, less than the timeout of the requests made by the operator
+const archiveWALTimeout = 20 * time.Second
+
+const (
+	// statusWatchInterval is how often the status of the instance is
+	// checked for changes while being watched by the operator
+	statusWatchInterval = 1 * time.Second
+
+	// statusWatchHeartbeat is the maximum time between two status sent
+	// to the operator, even if nothing changed, so that it can detect
+	// a stuck instance manager
+	statusWatchHeartbeat = 10 * time.Second
+)
+
 type remoteWebserverEndpoints struct {
 	typedClient client.Client
 	instance    *postgres.Instance
+
+	// The progress of the recovery as seen by each probe
+	startupRecovery   recoveryProgressTracker
+	readinessRecovery recoveryProgressTracker
+	livenessRecovery  recoveryProgressTracker
 }
 
 // NewRemoteWebServer returns a webserver that allows connection from external clients
@@ -48,20 +73,24 @@ func NewRemoteWebServer(
 		return nil, fmt.Errorf("creating controller-runtine client: %v", err)
 	}
 
-	endpoints := remoteWebserverEndpoints{
+	endpoints := &remoteWebserverEndpoints{
 		typedClient: typedClient,
 		instance:    instance,
 	}
 	serveMux := http.NewServeMux()
+	serveMux.HandleFunc(url.PathStartup, endpoints.isServerStarted)
 	serveMux.HandleFunc(url.PathHealth, endpoints.isServerHealthy)
 	serveMux.HandleFunc(url.PathReady, endpoints.isServerReady)
 	serveMux.HandleFunc(url.PathPgStatus, endpoints.pgStatus)
+	serveMux.HandleFunc(url.PathPgStatusWatch, endpoints.pgStatusWatch)
+	serveMux.HandleFunc(url.PathPgArchiveWAL, endpoints.pgArchiveWAL)
+	serveMux.HandleFunc(url.PathPgInventory, endpoints.pgInventory)
 	serveMux.HandleFunc(url.PathUpdate,
 		endpoints.updateInstanceManager(cancelFunc, exitedConditions))
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", url.StatusPort),
-		Handler:           serveMux,
+		Handler:           tracing.NewHandler(serveMux),
 		ReadTimeout:       DefaultReadTimeout,
 		ReadHeaderTimeout: DefaultReadHeaderTimeout,
 	}
@@ -75,6 +104,10 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, r *ht
 	// Same goes for instances with fencing on.
 	if !ws.instance.PgRewindIsRunning && !ws.instance.MightBeUnavailable() {
 		err := ws.instance.IsServerHealthy()
+		if err == nil {
+			err = ws.runHealthChecks(
+				loadProbeConfiguration((*apiv1.ProbesConfiguration).GetLiveness), &ws.livenessRecovery)
+		}
 		if err != nil {
 			log.Info("Liveness probe failing", "err", err.Error())
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -87,6 +120,54 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, r *ht
 	_, _ = fmt.Fprint(w, "OK")
 }
 
+// This is the startup probe, succeeding as soon as the condition set by its
+// strategy is met. As for the liveness probe, it is skipped while `pg_rewind`
+// is running and for instances with fencing on
+func (ws *remoteWebserverEndpoints) isServerStarted(w http.ResponseWriter, r *http.Request) {
+	if !ws.instance.PgRewindIsRunning && !ws.instance.MightBeUnavailable() {
+		err := ws.isStartupCompleted()
+		if err == nil {
+			err = ws.runHealthChecks(
+				loadProbeConfiguration((*apiv1.ProbesConfiguration).GetStartup), &ws.startupRecovery)
+		}
+		if err != nil {
+			log.Info("Startup probe failing", "err", err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Trace("Startup probe succeeding")
+	} else {
+		log.Trace("Startup probe skipped")
+	}
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// isStartupCompleted checks the condition the startup probe waits for,
+// according to the strategy set in the cluster definition
+func (ws *remoteWebserverEndpoints) isStartupCompleted() error {
+	strategy := apiv1.StartupProbeStrategyPostmaster
+	if cluster, err := cache.LoadCluster(); err == nil {
+		strategy = cluster.Spec.Probes.GetStartupStrategy()
+	}
+
+	switch strategy {
+	case apiv1.StartupProbeStrategyPgIsReady:
+		return ws.instance.IsServerReady()
+
+	case apiv1.StartupProbeStrategyStreaming:
+		if err := ws.instance.IsServerReady(); err != nil {
+			return err
+		}
+		if ws.isStreamingReplicationExpected() {
+			return ws.instance.IsStreamingReplicationHealthy()
+		}
+		return nil
+
+	default:
+		return ws.instance.IsServerHealthy()
+	}
+}
+
 // This is the readiness probe
 func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, r *http.Request) {
 	err := ws.instance.IsServerReady()
@@ -96,11 +177,75 @@ func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, r *http
 		return
 	}
 
+	// A standby which is not streaming from its upstream, or is too far
+	// behind it, is serving stale data and is removed from the services,
+	// without being restarted
+	if ws.isStreamingReplicationExpected() {
+		if err := ws.isStandbyUpToDate(); err != nil {
+			log.Info("Readiness probe failing", "err", err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err = ws.runHealthChecks(
+		loadProbeConfiguration((*apiv1.ProbesConfiguration).GetReadiness), &ws.readinessRecovery)
+	if err != nil {
+		log.Info("Readiness probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	log.Trace("Readiness probe succeeding")
 
 	_, _ = fmt.Fprint(w, "OK")
 }
 
+// isStreamingReplicationExpected checks if this instance is a standby
+// that should be streaming from the primary or another standby.
+// The designated primary of a replica cluster may be fed from the
+// WAL archive only, and is never expected to stream
+func (ws *remoteWebserverEndpoints) isStreamingReplicationExpected() bool {
+	cluster, err := cache.LoadCluster()
+	if err != nil {
+		// Without the cluster definition we can't tell which role
+		// this instance should have
+		return false
+	}
+
+	return cluster.Status.CurrentPrimary != ws.instance.PodName &&
+		cluster.Status.TargetPrimary != ws.instance.PodName
+}
+
+// isStandbyUpToDate checks that the standby is streaming from its upstream
+// and, when a limit is set, that its replay lag doesn't exceed it
+func (ws *remoteWebserverEndpoints) isStandbyUpToDate() error {
+	if err := ws.instance.IsStreamingReplicationHealthy(); err != nil {
+		return err
+	}
+
+	cluster, err := cache.LoadCluster()
+	if err != nil {
+		// Without the cluster definition the limit is unknown
+		return nil
+	}
+	maxStandbyLag := cluster.Spec.Replication.GetMaxStandbyLag()
+	if maxStandbyLag == 0 {
+		return nil
+	}
+
+	lag, err := ws.instance.GetStandbyReplayLag()
+	if err != nil {
+		return err
+	}
+	if lag > maxStandbyLag {
+		return fmt.Errorf("the replay lag of %d bytes exceeds the maximum standby lag of %d bytes",
+			lag, maxStandbyLag)
+	}
+
+	return nil
+}
+
 // This probe is for the instance status, including replication
 func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, r *http.Request) {
 	// Extract the status of the current instance
@@ -128,6 +273,97 @@ func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, r *http.Requ
 	_, _ = w.Write(js)
 }
 
+// pgStatusWatch streams the status of the instance to the operator as a
+// sequence of JSON documents, sending a new one whenever the status changes.
+// The stream is closed as soon as the status can't be extracted, letting
+// the operator detect the failure without waiting for the next poll
+func (ws *remoteWebserverEndpoints) pgStatusWatch(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+
+	ticker := time.NewTicker(statusWatchInterval)
+	defer ticker.Stop()
+
+	var lastSent []byte
+	var lastSentTime time.Time
+	for {
+		status, err := ws.instance.GetStatus()
+		if err != nil {
+			log.Info("Instance status watch failing", "err", err.Error())
+			return
+		}
+
+		js, err := json.Marshal(status)
+		if err != nil {
+			log.Info("Internal error marshalling instance status", "err", err.Error())
+			return
+		}
+
+		if !bytes.Equal(js, lastSent) || time.Since(lastSentTime) >= statusWatchHeartbeat {
+			if err := encoder.Encode(json.RawMessage(js)); err != nil {
+				log.Debug("Instance status watch closed", "err", err.Error())
+				return
+			}
+			flusher.Flush()
+			lastSent = js
+			lastSentTime = time.Now()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pgArchiveWAL switches to a new WAL file and waits for
+// the previous ones to be archived
+func (ws *remoteWebserverEndpoints) pgArchiveWAL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), archiveWALTimeout)
+	defer cancel()
+
+	walName, err := ws.instance.ArchiveCurrentWAL(ctx)
+	if err != nil {
+		log.Info("Archiving the current WAL file failed", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, walName)
+}
+
+// pgInventory gets the list of the roles and the databases of the instance
+func (ws *remoteWebserverEndpoints) pgInventory(w http.ResponseWriter, r *http.Request) {
+	inventory, err := ws.instance.GetInventory()
+	if err != nil {
+		log.Info("Instance inventory failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	js, err := json.Marshal(inventory)
+	if err != nil {
+		log.Info("Internal error marshalling instance inventory", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(js)
+}
+
 // updateInstanceManager replace the instance with one in the
 // new binary
 func (ws *remoteWebserverEndpoints) updateInstanceManager(
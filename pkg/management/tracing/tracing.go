@@ -0,0 +1,123 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing exports the OpenTelemetry spans of the operator and of
+// the instance manager via OTLP, following the standard OTEL_* environment
+// variables. Tracing is disabled unless an OTLP endpoint is configured
+package tracing
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// instrumentationName is the name of the tracer used for the spans
+	instrumentationName = "github.com/cloudnative-pg/cloudnative-pg"
+
+	// endpointEnvVar is the environment variable with the OTLP
+	// endpoint receiving every signal
+	endpointEnvVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	// tracesEndpointEnvVar is the environment variable with the OTLP
+	// endpoint receiving the traces
+	tracesEndpointEnvVar = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+
+	// sdkDisabledEnvVar is the environment variable disabling the
+	// OpenTelemetry SDK
+	sdkDisabledEnvVar = "OTEL_SDK_DISABLED"
+)
+
+// ShutdownFunc flushes the pending spans and stops exporting them
+type ShutdownFunc func(ctx context.Context) error
+
+// IsEnabled checks whether an OTLP endpoint has been configured
+// for the traces via the environment
+func IsEnabled() bool {
+	if disabled, _ := strconv.ParseBool(os.Getenv(sdkDisabledEnvVar)); disabled {
+		return false
+	}
+
+	return os.Getenv(endpointEnvVar) != "" || os.Getenv(tracesEndpointEnvVar) != ""
+}
+
+// Setup starts exporting the spans of this process, identified by the
+// passed service name, when tracing is enabled. The returned function
+// must be called before exiting to flush the pending spans
+func Setup(ctx context.Context, serviceName string) (ShutdownFunc, error) {
+	if !IsEnabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceNameKey.String(serviceName)),
+		resource.WithHost(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warning("Cannot export the traces", "err", err.Error())
+	}))
+
+	log.Info("Exporting the traces via OTLP", "serviceName", serviceName)
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as a child of the one in the passed context,
+// if any, and returns a context including it
+func StartSpan(
+	ctx context.Context,
+	name string,
+	attributes ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan ends a span, recording the passed error when it is not nil
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
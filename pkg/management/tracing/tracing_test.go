@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// setEnv sets an environment variable for the duration of a test
+func setEnv(name, value string) {
+	previous, found := os.LookupEnv(name)
+	Expect(os.Setenv(name, value)).To(Succeed())
+	DeferCleanup(func() {
+		if found {
+			Expect(os.Setenv(name, previous)).To(Succeed())
+		} else {
+			Expect(os.Unsetenv(name)).To(Succeed())
+		}
+	})
+}
+
+// useSpanRecorder installs a tracer provider recording the ended spans
+func useSpanRecorder() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	DeferCleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+var _ = Describe("tracing configuration", func() {
+	BeforeEach(func() {
+		setEnv(endpointEnvVar, "")
+		setEnv(tracesEndpointEnvVar, "")
+		setEnv(sdkDisabledEnvVar, "")
+	})
+
+	It("is disabled without an OTLP endpoint", func() {
+		Expect(IsEnabled()).To(BeFalse())
+	})
+
+	It("is enabled by the OTLP endpoint", func() {
+		setEnv(endpointEnvVar, "http://collector:4318")
+		Expect(IsEnabled()).To(BeTrue())
+	})
+
+	It("is enabled by the OTLP endpoint of the traces", func() {
+		setEnv(tracesEndpointEnvVar, "http://collector:4318/v1/traces")
+		Expect(IsEnabled()).To(BeTrue())
+	})
+
+	It("is disabled together with the SDK", func() {
+		setEnv(endpointEnvVar, "http://collector:4318")
+		setEnv(sdkDisabledEnvVar, "true")
+		Expect(IsEnabled()).To(BeFalse())
+	})
+
+	It("doesn't export anything when disabled", func() {
+		shutdown, err := Setup(context.Background(), "test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shutdown(context.Background())).To(Succeed())
+	})
+})
+
+var _ = Describe("spans", func() {
+	It("records the error ending a span", func() {
+		recorder := useSpanRecorder()
+
+		_, span := StartSpan(context.Background(), "failing")
+		EndSpan(span, fmt.Errorf("boom"))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name()).To(Equal("failing"))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spans[0].Status().Description).To(Equal("boom"))
+	})
+
+	It("propagates the span of the client to the server", func() {
+		recorder := useSpanRecorder()
+
+		server := httptest.NewServer(NewHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})))
+		defer server.Close()
+
+		client := &http.Client{Transport: NewTransport(nil)}
+		resp, err := client.Get(server.URL + "/pg/status")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+
+		// the server span may end after the client received the response
+		Eventually(recorder.Ended).Should(HaveLen(2))
+		var clientSpan, serverSpan sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.SpanKind() == trace.SpanKindClient {
+				clientSpan = span
+			} else {
+				serverSpan = span
+			}
+		}
+		Expect(clientSpan.Name()).To(Equal("HTTP GET /pg/status"))
+		Expect(serverSpan.Parent().SpanID()).To(Equal(clientSpan.SpanContext().SpanID()))
+		Expect(serverSpan.SpanContext().TraceID()).To(Equal(clientSpan.SpanContext().TraceID()))
+	})
+
+	It("doesn't trace the requests without a propagated span", func() {
+		recorder := useSpanRecorder()
+
+		handler := NewHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Ended()).To(BeEmpty())
+	})
+})
@@ -37,12 +37,28 @@ const (
 	// PathReady is the URL oath for Ready State
 	PathReady string = "/readyz"
 
+	// PathStartup is the URL path for Startup State
+	PathStartup string = "/startupz"
+
 	// PathPgStatus is the URL path for PostgreSQL Status
 	PathPgStatus string = "/pg/status"
 
+	// PathPgStatusWatch is the URL path streaming the PostgreSQL Status
+	// whenever it changes
+	PathPgStatusWatch string = "/pg/status/watch"
+
 	// PathPgBackup is the URL path for PostgreSQL Backup
 	PathPgBackup string = "/pg/backup"
 
+	// PathPgBackupVerify is the URL path to verify the artifacts of a backup
+	PathPgBackupVerify string = "/pg/backup/verify"
+
+	// PathPgArchiveWAL is the URL path to archive the current WAL file
+	PathPgArchiveWAL string = "/pg/archivewal"
+
+	// PathPgInventory is the URL path for the list of roles and databases
+	PathPgInventory string = "/pg/inventory"
+
 	// PathMetrics is the URL path for Metrics
 	PathMetrics string = "/metrics"
 
@@ -23,6 +23,8 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+
+	"k8s.io/utils/strings/slices"
 )
 
 const (
@@ -36,6 +38,12 @@ local all all peer map=local
 hostssl postgres streaming_replica all cert
 hostssl replication streaming_replica all cert
 hostssl all cnpg_pooler_pgbouncer all cert
+{{ if .PoolerCertificateUsers }}
+
+# Require client certificate authentication for the users connecting
+# through PgBouncer, accepting the certificate of the pooler too
+hostssl all {{.PoolerCertificateUsers}} all cert map={{.PoolerCertificateUserMap}}
+{{- end }}
 
 {{ range $rule := .UserRules }}
 {{ $rule -}}
@@ -48,6 +56,21 @@ hostssl all cnpg_pooler_pgbouncer all cert
 
 # Otherwise use the default authentication method
 host all all all {{.DefaultAuthenticationMethod}}
+`
+
+	// DrainingHBARules are the pg_hba.conf rules used by a primary draining
+	// the connections before being shut down during a switchover. Only the
+	// local and the streaming replication connections are accepted
+	DrainingHBARules = `
+# Grant local access
+local all all peer map=local
+
+# Keep accepting the connections of the streaming_replica user
+hostssl postgres streaming_replica all cert
+hostssl replication streaming_replica all cert
+
+# Reject every other connection while draining
+host all all all reject
 `
 
 	// fixedConfigurationParameter are the configuration parameters
@@ -63,6 +86,10 @@ host all all all {{.DefaultAuthenticationMethod}}
 	// ScratchDataDirectory is the directory to be used for scratch data
 	ScratchDataDirectory = "/controller"
 
+	// ExtensionsBaseDirectory is the directory containing the files
+	// copied from the extension images, one subdirectory per image
+	ExtensionsBaseDirectory = "/extensions"
+
 	// CertificatesDir location to store the certificates
 	CertificatesDir = ScratchDataDirectory + "/certificates/"
 
@@ -130,6 +157,14 @@ host all all all {{.DefaultAuthenticationMethod}}
 	// LogPath is the path of the folder used by the logging_collector
 	LogPath = ScratchDataDirectory + "/log"
 
+	// LogShippingPath is the path of the folder, shared with the sidecar
+	// containers, where the instance manager copies its log stream
+	LogShippingPath = "/logs"
+
+	// LogShippingFileName is the name of the file, inside LogShippingPath,
+	// containing the copy of the log stream of the instance manager
+	LogShippingFileName = "instance.json"
+
 	// LogFileName is the name of the file produced by the logging_collector,
 	// excluding the extension. The logging collector process will append
 	// `.csv` and `.log` as needed.
@@ -142,8 +177,50 @@ host all all all {{.DefaultAuthenticationMethod}}
 	// SharedPreloadLibraries shared preload libraries key in the config
 	SharedPreloadLibraries = "shared_preload_libraries"
 
+	// PasswordEncryption is the name of the parameter containing the
+	// method used to hash the passwords
+	PasswordEncryption = "password_encryption"
+
+	// PasswordEncryptionSCRAM is the SCRAM-SHA-256 password hashing method
+	PasswordEncryptionSCRAM = "scram-sha-256"
+
+	// SSLMinProtocolVersion is the name of the parameter containing the
+	// minimum TLS protocol version accepted by the server
+	SSLMinProtocolVersion = "ssl_min_protocol_version"
+
+	// SSLCiphers is the name of the parameter containing the allowed TLS ciphers
+	SSLCiphers = "ssl_ciphers"
+
+	// SSLECDHCurve is the name of the parameter containing the curve used
+	// in the ECDH key exchange
+	SSLECDHCurve = "ssl_ecdh_curve"
+
+	// WALCompression is the name of the parameter controlling the
+	// compression of the full page images written to the WAL
+	WALCompression = "wal_compression"
+
+	// HugePages is the name of the parameter controlling
+	// whether PostgreSQL allocates its shared memory using huge pages
+	HugePages = "huge_pages"
+
+	// HugePageSize is the name of the parameter controlling the size
+	// of the huge pages requested by PostgreSQL, available since PostgreSQL 14
+	HugePageSize = "huge_page_size"
+
+	// ExtensionControlPath is the name of the parameter containing the
+	// directories where the control files of the extensions are searched,
+	// available since PostgreSQL 18
+	ExtensionControlPath = "extension_control_path"
+
+	// DynamicLibraryPath is the name of the parameter containing the
+	// directories where the dynamically loadable modules are searched
+	DynamicLibraryPath = "dynamic_library_path"
+
 	// SynchronousStandbyNames is the postgresql parameter key for synchronous standbys
 	SynchronousStandbyNames = "synchronous_standby_names"
+
+	// PgCronExtensionName is the name of the pg_cron extension
+	PgCronExtensionName = "pg_cron"
 )
 
 // hbaTemplate is the template used to create the HBA configuration
@@ -222,8 +299,35 @@ type ConfigurationInfo struct {
 	// List of additional sharedPreloadLibraries to be loaded
 	AdditionalSharedPreloadLibraries []string
 
+	// List of the names of the managed extensions that must be enabled
+	// even if none of their configuration namespaces is used
+	EnabledManagedExtensions []string
+
 	// Is this a replica cluster?
 	IsReplicaCluster bool
+
+	// The method used to compress the full page images written to the WAL,
+	// overriding the user settings when not empty
+	WALCompression string
+
+	// The TLS settings of the server, overriding the user settings
+	TLSParameters map[string]string
+
+	// The method used to hash the passwords, overriding the user
+	// settings when not empty
+	PasswordEncryption string
+
+	// The size in bytes of the huge pages available to PostgreSQL,
+	// zero when the instances are not using huge pages
+	HugePageSize int64
+
+	// The directories containing the control files of the extensions
+	// shipped in extension images, searched after the system ones
+	ExtensionControlPath []string
+
+	// The directories containing the libraries of the extensions
+	// shipped in extension images, searched after the system ones
+	DynamicLibraryPath []string
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -236,6 +340,15 @@ type ManagedExtension struct {
 	Namespaces []string
 	// SharedPreloadLibraries is the list of needed shared preload libraries
 	SharedPreloadLibraries []string
+	// EnabledBySharedPreloadLibraries is true when the extension is managed
+	// as soon as the user lists its libraries in shared_preload_libraries
+	EnabledBySharedPreloadLibraries bool
+	// DatabaseParameter is the configuration parameter naming the only
+	// database where the extension can be created, if any
+	DatabaseParameter string
+	// DefaultDatabase is the database where the extension is created when
+	// DatabaseParameter is not set by the user
+	DefaultDatabase string
 }
 
 // IsUsed checks whether a configuration namespace in the namespaces list
@@ -251,19 +364,40 @@ func (e ManagedExtension) IsUsed(userConfigs map[string]string) bool {
 	return false
 }
 
+// GetDatabase gets the only database where the extension can be created,
+// or an empty string if the extension is created in every database
+func (e ManagedExtension) GetDatabase(userConfigs map[string]string) string {
+	if e.DatabaseParameter == "" {
+		return ""
+	}
+	if database := userConfigs[e.DatabaseParameter]; database != "" {
+		return database
+	}
+	return e.DefaultDatabase
+}
+
 var (
 	// ManagedExtensions contains the list of extensions the operator supports to manage
 	ManagedExtensions = []ManagedExtension{
 		{
-			Name:                   "pgaudit",
-			Namespaces:             []string{"pgaudit"},
-			SharedPreloadLibraries: []string{"pgaudit"},
+			Name:                            "pgaudit",
+			Namespaces:                      []string{"pgaudit"},
+			SharedPreloadLibraries:          []string{"pgaudit"},
+			EnabledBySharedPreloadLibraries: true,
 		},
 		{
 			Name:                   "pg_stat_statements",
 			Namespaces:             []string{"pg_stat_statements"},
 			SharedPreloadLibraries: []string{"pg_stat_statements"},
 		},
+		{
+			Name:                            PgCronExtensionName,
+			Namespaces:                      []string{"cron"},
+			SharedPreloadLibraries:          []string{"pg_cron"},
+			DatabaseParameter:               "cron.database_name",
+			DefaultDatabase:                 "postgres",
+			EnabledBySharedPreloadLibraries: true,
+		},
 		{
 			Name:                   "auto_explain",
 			SkipCreateExtension:    true,
@@ -405,17 +539,21 @@ var (
 
 // CreateHBARules will create the content of pg_hba.conf file given
 // the rules set by the cluster spec
-func CreateHBARules(hba []string,
+func CreateHBARules(hba, poolerCertificateUsers []string,
 	defaultAuthenticationMethod, ldapConfigString string,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
 	templateData := struct {
 		UserRules                   []string
+		PoolerCertificateUsers      string
+		PoolerCertificateUserMap    string
 		LDAPConfiguration           string
 		DefaultAuthenticationMethod string
 	}{
 		UserRules:                   hba,
+		PoolerCertificateUsers:      joinConfigurationUsers(poolerCertificateUsers),
+		PoolerCertificateUserMap:    PoolerCertificateUserMap,
 		LDAPConfiguration:           ldapConfigString,
 		DefaultAuthenticationMethod: defaultAuthenticationMethod,
 	}
@@ -515,6 +653,28 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig("archive_mode", "on")
 	}
 
+	// Apply the WAL compression method
+	setWALCompression(info, configuration)
+
+	// Apply the TLS settings
+	for key, value := range info.TLSParameters {
+		configuration.OverwriteConfig(key, value)
+	}
+
+	// Apply the password hashing method
+	if info.PasswordEncryption != "" {
+		configuration.OverwriteConfig(PasswordEncryption, info.PasswordEncryption)
+	}
+
+	// Apply the huge pages settings
+	setHugePages(info, configuration)
+
+	// Apply the paths of the extension images
+	setExtensionsPaths(info, configuration)
+
+	// Apply the databases of the managed extensions
+	setManagedExtensionsDatabases(info, configuration)
+
 	// Apply the list of replicas
 	setReplicasListConfigurations(info, configuration)
 
@@ -549,10 +709,77 @@ func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfigura
 	}
 }
 
+// setWALCompression sets the method used to compress the full page images
+func setWALCompression(info ConfigurationInfo, configuration *PgConfiguration) {
+	if info.WALCompression == "" {
+		return
+	}
+
+	// Before PostgreSQL 15 the parameter is a boolean and pglz is the only available method
+	value := info.WALCompression
+	if info.MajorVersion < 150000 && value == "pglz" {
+		value = "on"
+	}
+
+	configuration.OverwriteConfig(WALCompression, value)
+}
+
+// setHugePages enables huge pages only when they are available to the
+// instance, unless the user chose otherwise. Without huge pages requested
+// in the resources, PostgreSQL would still find the ones configured in
+// the node and crash when trying to use them.
+// These settings depend on the resources of the instances, so they are
+// applied only when writing the configuration to disk, and are never
+// persisted in the cluster specification
+func setHugePages(info ConfigurationInfo, configuration *PgConfiguration) {
+	if !info.IncludingMandatory {
+		return
+	}
+
+	if _, ok := info.UserSettings[HugePages]; !ok {
+		if info.HugePageSize > 0 {
+			configuration.OverwriteConfig(HugePages, "on")
+		} else {
+			configuration.OverwriteConfig(HugePages, "off")
+		}
+	}
+
+	if _, ok := info.UserSettings[HugePageSize]; !ok && info.HugePageSize > 0 && info.MajorVersion >= 140000 {
+		configuration.OverwriteConfig(HugePageSize, fmt.Sprintf("%dkB", info.HugePageSize/1024))
+	}
+}
+
+// setExtensionsPaths makes PostgreSQL search the extensions in the
+// directories of the extension images, after the system ones
+func setExtensionsPaths(info ConfigurationInfo, configuration *PgConfiguration) {
+	if len(info.ExtensionControlPath) > 0 {
+		configuration.OverwriteConfig(ExtensionControlPath,
+			strings.Join(append([]string{"$system"}, info.ExtensionControlPath...), ":"))
+	}
+
+	if len(info.DynamicLibraryPath) > 0 {
+		configuration.OverwriteConfig(DynamicLibraryPath,
+			strings.Join(append([]string{"$libdir"}, info.DynamicLibraryPath...), ":"))
+	}
+}
+
+// setManagedExtensionsDatabases sets the database where the used managed
+// extensions can be created, for the extensions allowing only one
+func setManagedExtensionsDatabases(info ConfigurationInfo, configuration *PgConfiguration) {
+	for _, extension := range ManagedExtensions {
+		if extension.DatabaseParameter == "" {
+			continue
+		}
+		if extension.IsUsed(info.UserSettings) || slices.Contains(info.EnabledManagedExtensions, extension.Name) {
+			configuration.OverwriteConfig(extension.DatabaseParameter, extension.GetDatabase(info.UserSettings))
+		}
+	}
+}
+
 // setManagedSharedPreloadLibraries sets all additional preloaded libraries
 func setManagedSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConfiguration) {
 	for _, extension := range ManagedExtensions {
-		if extension.IsUsed(info.UserSettings) {
+		if extension.IsUsed(info.UserSettings) || slices.Contains(info.EnabledManagedExtensions, extension.Name) {
 			for _, library := range extension.SharedPreloadLibraries {
 				configuration.AddSharedPreloadLibrary(library)
 			}
@@ -225,6 +225,169 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 	})
 })
 
+var _ = Describe("Password encryption", func() {
+	It("overrides the user settings when enforced", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 130000,
+			UserSettings: map[string]string{
+				PasswordEncryption: "md5",
+			},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(PasswordEncryption)).To(Equal("md5"))
+
+		info.PasswordEncryption = PasswordEncryptionSCRAM
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(PasswordEncryption)).To(Equal(PasswordEncryptionSCRAM))
+	})
+})
+
+var _ = Describe("TLS settings", func() {
+	It("overrides the user settings", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 150000,
+			UserSettings: map[string]string{
+				SSLCiphers: "ALL",
+			},
+			TLSParameters: map[string]string{
+				SSLMinProtocolVersion: "TLSv1.3",
+				SSLCiphers:            "HIGH:!aNULL",
+			},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SSLMinProtocolVersion)).To(Equal("TLSv1.3"))
+		Expect(config.GetConfig(SSLCiphers)).To(Equal("HIGH:!aNULL"))
+		Expect(config.GetConfig(SSLECDHCurve)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WAL compression", func() {
+	It("uses the user settings when the method is not specified", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 150000,
+			UserSettings: map[string]string{
+				"wal_compression": "lz4",
+			},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(WALCompression)).To(Equal("lz4"))
+	})
+
+	It("applies the requested method", func() {
+		info := ConfigurationInfo{
+			Settings:       CnpgConfigurationSettings,
+			MajorVersion:   150000,
+			WALCompression: "zstd",
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(WALCompression)).To(Equal("zstd"))
+	})
+
+	It("uses the boolean value before PostgreSQL 15", func() {
+		info := ConfigurationInfo{
+			Settings:       CnpgConfigurationSettings,
+			MajorVersion:   140000,
+			WALCompression: "pglz",
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(WALCompression)).To(Equal("on"))
+
+		info.WALCompression = "off"
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(WALCompression)).To(Equal("off"))
+	})
+})
+
+var _ = Describe("huge pages", func() {
+	It("disables huge pages when they are not available", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       150000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(HugePages)).To(Equal("off"))
+		Expect(config.GetConfig(HugePageSize)).To(BeEmpty())
+	})
+
+	It("enables huge pages of the available size", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       150000,
+			IncludingMandatory: true,
+			HugePageSize:       2 * 1024 * 1024,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(HugePages)).To(Equal("on"))
+		Expect(config.GetConfig(HugePageSize)).To(Equal("2048kB"))
+	})
+
+	It("doesn't set the size of huge pages before PostgreSQL 14", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       130000,
+			IncludingMandatory: true,
+			HugePageSize:       2 * 1024 * 1024,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(HugePages)).To(Equal("on"))
+		Expect(config.GetConfig(HugePageSize)).To(BeEmpty())
+	})
+
+	It("preserves the user settings", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       150000,
+			IncludingMandatory: true,
+			HugePageSize:       2 * 1024 * 1024,
+			UserSettings: map[string]string{
+				HugePages: "try",
+			},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(HugePages)).To(Equal("try"))
+	})
+
+	It("is not included in the settings visible to the user", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 150000,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(HugePages)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("extension images", func() {
+	It("adds the directories of the extension images after the system ones", func() {
+		info := ConfigurationInfo{
+			Settings:             CnpgConfigurationSettings,
+			MajorVersion:         180000,
+			IncludingMandatory:   true,
+			ExtensionControlPath: []string{"/extensions/pgvector/share", "/extensions/postgis/share"},
+			DynamicLibraryPath:   []string{"/extensions/pgvector/lib"},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(ExtensionControlPath)).To(
+			Equal("$system:/extensions/pgvector/share:/extensions/postgis/share"))
+		Expect(config.GetConfig(DynamicLibraryPath)).To(Equal("$libdir:/extensions/pgvector/lib"))
+	})
+
+	It("doesn't set the paths without extension images", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       180000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(ExtensionControlPath)).To(BeEmpty())
+		Expect(config.GetConfig(DynamicLibraryPath)).To(BeEmpty())
+	})
+})
+
 var _ = Describe("pg_hba.conf generation", func() {
 	specRules := []string{
 		"one",
@@ -233,19 +396,84 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, nil, "md5", "")).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, nil, "this-one", "")).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, nil, "defaultAuthenticationMethod", "ldapConfigString")).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
+
+	It("authenticates via certificate the users connecting through the pooler", func() {
+		hba, err := CreateHBARules(specRules, []string{"app", "reporting"}, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring(
+			"\nhostssl all \"app\",\"reporting\" all cert map=cnpg_pooler\n"))
+		Expect(strings.Index(hba, "map=cnpg_pooler")).To(BeNumerically("<", strings.Index(hba, "\none\n")))
+	})
+
+	It("doesn't add the pooler certificate rule when there are no users", func() {
+		Expect(CreateHBARules(specRules, nil, "md5", "")).ToNot(
+			ContainSubstring("map=cnpg_pooler"))
+	})
+})
+
+var _ = Describe("pg_cron", func() {
+	It("adds pg_cron to shared_preload_libraries and sets its database", func() {
+		info := ConfigurationInfo{
+			Settings:                        CnpgConfigurationSettings,
+			MajorVersion:                    150000,
+			UserSettings:                    map[string]string{"cron.timezone": "Europe/Rome"},
+			IncludingMandatory:              true,
+			IncludingSharedPreloadLibraries: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pg_cron"))
+		Expect(config.GetConfig("cron.database_name")).To(Equal("postgres"))
+	})
+
+	It("keeps the database chosen by the user", func() {
+		info := ConfigurationInfo{
+			Settings:                        CnpgConfigurationSettings,
+			MajorVersion:                    150000,
+			UserSettings:                    map[string]string{"cron.database_name": "app"},
+			IncludingMandatory:              true,
+			IncludingSharedPreloadLibraries: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("cron.database_name")).To(Equal("app"))
+	})
+
+	It("is enabled through shared_preload_libraries", func() {
+		info := ConfigurationInfo{
+			Settings:                        CnpgConfigurationSettings,
+			MajorVersion:                    150000,
+			UserSettings:                    map[string]string{},
+			IncludingMandatory:              true,
+			IncludingSharedPreloadLibraries: true,
+			EnabledManagedExtensions:        []string{PgCronExtensionName},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pg_cron"))
+		Expect(config.GetConfig("cron.database_name")).To(Equal("postgres"))
+	})
+
+	It("doesn't set the database when not enabled", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       150000,
+			UserSettings:       map[string]string{},
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("cron.database_name")).To(BeEmpty())
+	})
 })
 
 var _ = Describe("pgaudit", func() {
@@ -320,4 +548,59 @@ var _ = Describe("pgaudit", func() {
 		Expect(libraries).ToNot(ContainElement(""))
 		Expect(libraries).To(ContainElements("pg_stat_statements", "pgaudit"))
 	})
+	It("adds pg_stat_statements to shared_preload_library when explicitly enabled", func() {
+		info := ConfigurationInfo{
+			Settings:                        CnpgConfigurationSettings,
+			MajorVersion:                    130000,
+			UserSettings:                    map[string]string{},
+			IncludingMandatory:              true,
+			IncludingSharedPreloadLibraries: true,
+			EnabledManagedExtensions:        []string{"pg_stat_statements"},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pg_stat_statements"))
+	})
+})
+
+var _ = Describe("pg_hba.conf validation", func() {
+	DescribeTable("rules",
+		func(rule string, valid bool) {
+			if valid {
+				Expect(ValidateHBARule(rule)).To(Succeed())
+			} else {
+				Expect(ValidateHBARule(rule)).ToNot(Succeed())
+			}
+		},
+		Entry("a local rule", "local all all peer", true),
+		Entry("a host rule", "host all all 10.244.0.0/16 md5", true),
+		Entry("a rule with an IP mask", "host all all 10.0.0.0 255.0.0.0 scram-sha-256", true),
+		Entry("a certificate rule", "hostssl app all all cert", true),
+		Entry("a rule with quoted names", `hostssl "my app" "john doe" all scram-sha-256`, true),
+		Entry("an LDAP rule with options",
+			`host all all all ldap ldapserver=ldap.example.com ldapprefix="cn=" ldapsuffix=", dc=example, dc=com"`,
+			true),
+		Entry("a comment", "# custom rules", true),
+		Entry("an empty rule", "", true),
+		Entry("a rule with a trailing comment", "host all all all md5 # everyone", true),
+		Entry("an unknown connection type", "hostx all all all md5", false),
+		Entry("an unknown method", "host all all all magic", false),
+		Entry("a missing method", "host all all all", false),
+		Entry("a local rule with an address", "local all all 127.0.0.1/32 md5", false),
+		Entry("an option without value", "host all all all ldap ldaptls", false),
+		Entry("multiple lines", "host all all all md5\nhost all all all trust", false),
+		Entry("an unterminated quote", `host "all all all md5`, false),
+	)
+
+	It("finds the rules using the trust authentication method", func() {
+		content := "# trust nobody\n" +
+			"local all all peer map=local\n" +
+			"local all all trust\n" +
+			"host trust trust 10.0.0.0/8 md5\n" +
+			"host all all 127.0.0.1 255.255.255.255 trust # initdb\n" +
+			"hostssl replication streaming_replica all cert\n"
+		Expect(GetTrustRules(content)).To(Equal([]string{
+			"local all all trust",
+			"host all all 127.0.0.1 255.255.255.255 trust # initdb",
+		}))
+	})
 })
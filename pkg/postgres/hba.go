@@ -0,0 +1,172 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+var (
+	// hbaConnectionTypes are the connection types accepted in pg_hba.conf
+	hbaConnectionTypes = map[string]bool{
+		"local":        true,
+		"host":         true,
+		"hostssl":      true,
+		"hostnossl":    true,
+		"hostgssenc":   true,
+		"hostnogssenc": true,
+	}
+
+	// hbaAuthenticationMethods are the authentication methods accepted in pg_hba.conf
+	hbaAuthenticationMethods = map[string]bool{
+		"trust":         true,
+		"reject":        true,
+		"scram-sha-256": true,
+		"md5":           true,
+		"password":      true,
+		"gss":           true,
+		"sspi":          true,
+		"ident":         true,
+		"peer":          true,
+		"ldap":          true,
+		"radius":        true,
+		"cert":          true,
+		"pam":           true,
+		"bsd":           true,
+	}
+
+	// errMultipleLines is raised when a rule spans multiple lines
+	errMultipleLines = errors.New("must be a single line")
+)
+
+// ValidateHBARule checks the syntax of a pg_hba.conf rule. Empty rules
+// and comments are accepted, as PostgreSQL ignores them
+func ValidateHBARule(rule string) error {
+	if strings.ContainsAny(rule, "\r\n") {
+		return errMultipleLines
+	}
+
+	tokens, err := splitConfigurationTokens(rule)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	connectionType := tokens[0]
+	if !hbaConnectionTypes[connectionType] {
+		return fmt.Errorf("unknown connection type %q", connectionType)
+	}
+
+	methodIndex := getHBAMethodIndex(tokens)
+	if len(tokens) <= methodIndex {
+		return errors.New("missing fields, the authentication method is required")
+	}
+
+	method := tokens[methodIndex]
+	if !hbaAuthenticationMethods[method] {
+		return fmt.Errorf("unknown authentication method %q", method)
+	}
+
+	for _, option := range tokens[methodIndex+1:] {
+		if !strings.Contains(option, "=") {
+			return fmt.Errorf("authentication option %q is not in the name=value format", option)
+		}
+	}
+
+	return nil
+}
+
+// GetTrustRules gets the rules of a pg_hba.conf file granting access
+// without any authentication, i.e. using the trust method
+func GetTrustRules(content string) []string {
+	var rules []string
+	for _, line := range strings.Split(content, "\n") {
+		tokens, err := splitConfigurationTokens(line)
+		if err != nil || len(tokens) == 0 {
+			continue
+		}
+
+		methodIndex := getHBAMethodIndex(tokens)
+		if len(tokens) > methodIndex && tokens[methodIndex] == "trust" {
+			rules = append(rules, strings.TrimSpace(line))
+		}
+	}
+
+	return rules
+}
+
+// getHBAMethodIndex gets the position of the authentication method in
+// the tokens of a pg_hba.conf rule. Local rules have no address, while
+// the address of the other ones can be followed by a separate IP mask
+func getHBAMethodIndex(tokens []string) int {
+	if tokens[0] == "local" {
+		return 3
+	}
+
+	if len(tokens) > 4 && net.ParseIP(tokens[4]) != nil {
+		return 5
+	}
+	return 4
+}
+
+// splitConfigurationTokens splits a line of pg_hba.conf or pg_ident.conf
+// into its whitespace-separated tokens, which can be double-quoted,
+// ignoring the comments
+func splitConfigurationTokens(line string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	inToken := false
+	inQuotes := false
+
+	for _, char := range line {
+		switch {
+		case char == '"':
+			inQuotes = !inQuotes
+			inToken = true
+			token.WriteRune(char)
+		case inQuotes:
+			token.WriteRune(char)
+		case char == '#':
+			if inToken {
+				tokens = append(tokens, token.String())
+			}
+			return tokens, nil
+		case char == ' ' || char == '\t':
+			if inToken {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		default:
+			inToken = true
+			token.WriteRune(char)
+		}
+	}
+
+	if inQuotes {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if inToken {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
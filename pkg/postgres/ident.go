@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// PoolerCertificateUserMap is the name of the user map allowing PgBouncer
+	// to connect, using its own client certificate, on behalf of the users
+	// it authenticated via their client certificates
+	PoolerCertificateUserMap = "cnpg_pooler"
+
+	// poolerUserName is the name of the role used by PgBouncer
+	poolerUserName = "cnpg_pooler_pgbouncer"
+
+	// LocalUserMap is the name of the user map linking the operating
+	// system user to the postgres user
+	LocalUserMap = "local"
+)
+
+// CreateUserMaps creates the content of the pg_ident.conf file. The
+// "local" map links the operating system user to "postgres", while
+// the pooler map allows the PgBouncer certificate, and the certificate
+// of the user itself, to be used for each of the passed users.
+// The user-defined maps are appended to the ones of the operator
+func CreateUserMaps(localUser string, poolerCertificateUsers []string, userMaps []string) string {
+	var identContent strings.Builder
+	identContent.WriteString(fmt.Sprintf("%s %s postgres\n", LocalUserMap, localUser))
+
+	for _, user := range poolerCertificateUsers {
+		quotedUser := quoteConfigurationUser(user)
+		identContent.WriteString(fmt.Sprintf("%s %s %s\n",
+			PoolerCertificateUserMap, poolerUserName, quotedUser))
+		identContent.WriteString(fmt.Sprintf("%s %s %s\n",
+			PoolerCertificateUserMap, quotedUser, quotedUser))
+	}
+
+	if len(userMaps) > 0 {
+		identContent.WriteString("\n# User defined maps\n")
+		for _, userMap := range userMaps {
+			identContent.WriteString(userMap)
+			identContent.WriteString("\n")
+		}
+	}
+
+	return identContent.String()
+}
+
+// ValidateUserMap checks the syntax of a pg_ident.conf line, which must
+// not extend the maps managed by the operator. Empty lines and comments
+// are accepted, as PostgreSQL ignores them
+func ValidateUserMap(line string) error {
+	if strings.ContainsAny(line, "\r\n") {
+		return errMultipleLines
+	}
+
+	tokens, err := splitConfigurationTokens(line)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	if len(tokens) != 3 {
+		return errors.New("a user map requires the map name, the system user name and the database user name")
+	}
+
+	if mapName := strings.Trim(tokens[0], "\""); mapName == LocalUserMap || mapName == PoolerCertificateUserMap {
+		return fmt.Errorf("the %q map is managed by the operator", mapName)
+	}
+
+	return nil
+}
+
+// quoteConfigurationUser quotes a user name to be used in pg_hba.conf
+// and pg_ident.conf. The name is expected not to contain double quotes
+func quoteConfigurationUser(user string) string {
+	return fmt.Sprintf("\"%s\"", user)
+}
+
+// joinConfigurationUsers creates the comma-separated list of users
+// to be used in a pg_hba.conf rule
+func joinConfigurationUsers(users []string) string {
+	quotedUsers := make([]string, len(users))
+	for i, user := range users {
+		quotedUsers[i] = quoteConfigurationUser(user)
+	}
+	return strings.Join(quotedUsers, ",")
+}
@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_ident.conf generation", func() {
+	It("maps the local user to postgres", func() {
+		Expect(CreateUserMaps("postgres", nil, nil)).To(Equal("local postgres postgres\n"))
+	})
+
+	It("maps the pooler and the users themselves to the pooler certificate users", func() {
+		Expect(CreateUserMaps("postgres", []string{"app"}, nil)).To(Equal(
+			"local postgres postgres\n" +
+				"cnpg_pooler cnpg_pooler_pgbouncer \"app\"\n" +
+				"cnpg_pooler \"app\" \"app\"\n"))
+	})
+
+	It("appends the user defined maps", func() {
+		Expect(CreateUserMaps("postgres", nil, []string{"ldap /^(.*)@example\\.com$ \\1"})).To(Equal(
+			"local postgres postgres\n" +
+				"\n# User defined maps\n" +
+				"ldap /^(.*)@example\\.com$ \\1\n"))
+	})
+})
+
+var _ = Describe("pg_ident.conf validation", func() {
+	DescribeTable("user maps",
+		func(line string, valid bool) {
+			if valid {
+				Expect(ValidateUserMap(line)).To(Succeed())
+			} else {
+				Expect(ValidateUserMap(line)).ToNot(Succeed())
+			}
+		},
+		Entry("a map", "kerberos alice@EXAMPLE.COM alice", true),
+		Entry("a map with a regular expression", `ldap /^(.*)@example\.com$ \1`, true),
+		Entry("a map with quoted names", `gss "john doe" "john"`, true),
+		Entry("a comment", "# user maps", true),
+		Entry("an empty line", "", true),
+		Entry("a map with a trailing comment", "kerberos alice alice # Alice", true),
+		Entry("a map with missing fields", "kerberos alice", false),
+		Entry("a map with too many fields", "kerberos alice alice bob", false),
+		Entry("the local map", "local root postgres", false),
+		Entry("the quoted local map", `"local" root postgres`, false),
+		Entry("the pooler map", "cnpg_pooler alice postgres", false),
+		Entry("multiple lines", "kerberos alice alice\nlocal root postgres", false),
+		Entry("an unterminated quote", `kerberos "alice alice`, false),
+	)
+})
@@ -0,0 +1,45 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+// Inventory is the list of the roles and the databases
+// defined in a PostgreSQL instance
+type Inventory struct {
+	// The roles, excluding the predefined ones
+	Roles []RoleInventory `json:"roles"`
+
+	// The databases, excluding the templates
+	Databases []DatabaseInventory `json:"databases"`
+}
+
+// RoleInventory contains the attributes of a role
+type RoleInventory struct {
+	Name        string   `json:"name"`
+	Superuser   bool     `json:"superuser"`
+	CreateRole  bool     `json:"createRole"`
+	CreateDB    bool     `json:"createDB"`
+	Login       bool     `json:"login"`
+	Replication bool     `json:"replication"`
+	MemberOf    []string `json:"memberOf,omitempty"`
+}
+
+// DatabaseInventory contains the attributes of a database
+type DatabaseInventory struct {
+	Name     string `json:"name"`
+	Owner    string `json:"owner"`
+	Encoding string `json:"encoding"`
+}
@@ -0,0 +1,533 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParameterType is the type of the value of a PostgreSQL configuration parameter
+type ParameterType string
+
+const (
+	// ParameterTypeBool is used for the boolean parameters
+	ParameterTypeBool ParameterType = "bool"
+
+	// ParameterTypeInteger is used for the integer parameters
+	ParameterTypeInteger ParameterType = "integer"
+
+	// ParameterTypeReal is used for the floating point parameters
+	ParameterTypeReal ParameterType = "real"
+
+	// ParameterTypeEnum is used for the parameters accepting a set of values
+	ParameterTypeEnum ParameterType = "enum"
+
+	// ParameterTypeString is used for the parameters accepting any string
+	ParameterTypeString ParameterType = "string"
+)
+
+// ParameterDefinition describes a PostgreSQL configuration parameter
+type ParameterDefinition struct {
+	// The type of the value
+	Type ParameterType
+
+	// The unit in which the numeric values are expressed when no
+	// unit is specified, i.e. "kB", "8kB", "MB", "ms", "s"
+	Unit string
+
+	// The bounds of the numeric values, expressed in Unit
+	Min, Max float64
+
+	// The values accepted by an enum parameter
+	Values []string
+
+	// True if changing the parameter requires a restart of PostgreSQL
+	RequiresRestart bool
+
+	// The PostgreSQL major versions supporting the parameter
+	Versions MajorVersionRange
+}
+
+const (
+	maxInt      = math.MaxInt32
+	maxIntHalf  = math.MaxInt32 / 2
+	maxBackends = 262143
+	maxReal     = math.MaxFloat64
+)
+
+var (
+	// booleanSpellings are the values accepted by PostgreSQL for the
+	// enum parameters which can be set with a boolean too
+	booleanSpellings = []string{"on", "off", "true", "false", "yes", "no", "1", "0"}
+
+	// messageLevels are the values accepted by the log_min_messages
+	// and log_min_error_statement parameters
+	messageLevels = []string{
+		"debug5", "debug4", "debug3", "debug2", "debug1",
+		"info", "notice", "warning", "error", "log", "fatal", "panic",
+	}
+
+	// memoryUnits are the memory units accepted by PostgreSQL, with their size in kB
+	memoryUnits = map[string]float64{
+		"B":   1.0 / 1024,
+		"kB":  1,
+		"8kB": 8,
+		"MB":  1024,
+		"GB":  1024 * 1024,
+		"TB":  1024 * 1024 * 1024,
+	}
+
+	// timeUnits are the time units accepted by PostgreSQL, with their length in ms
+	timeUnits = map[string]float64{
+		"us":  1.0 / 1000,
+		"ms":  1,
+		"s":   1000,
+		"min": 60 * 1000,
+		"h":   60 * 60 * 1000,
+		"d":   24 * 60 * 60 * 1000,
+	}
+
+	// numericValueRegex matches a numeric value, optionally followed by a unit
+	numericValueRegex = regexp.MustCompile(
+		`^([-+]?(?:0[xX][0-9a-fA-F]+|[0-9]*\.?[0-9]+(?:[eE][-+]?[0-9]+)?))\s*([a-zA-Z]*)$`)
+)
+
+// ParametersCatalog contains the definition of the PostgreSQL configuration
+// parameters which are checked before being applied. The parameters not
+// included here, like the ones of the extensions, are not checked
+var ParametersCatalog = map[string]ParameterDefinition{
+	// Memory
+	"shared_buffers": {
+		Type: ParameterTypeInteger, Unit: "8kB", Min: 16, Max: maxIntHalf, RequiresRestart: true,
+	},
+	"huge_pages": {
+		Type: ParameterTypeEnum, Values: append([]string{"try"}, booleanSpellings...), RequiresRestart: true,
+	},
+	"shared_memory_type": {
+		Type: ParameterTypeEnum, Values: []string{"mmap", "sysv"}, RequiresRestart: true,
+		Versions: MajorVersionRange{Min: 120000},
+	},
+	"temp_buffers":         {Type: ParameterTypeInteger, Unit: "8kB", Min: 100, Max: maxIntHalf},
+	"work_mem":             {Type: ParameterTypeInteger, Unit: "kB", Min: 64, Max: maxInt},
+	"maintenance_work_mem": {Type: ParameterTypeInteger, Unit: "kB", Min: 1024, Max: maxInt},
+	"autovacuum_work_mem":  {Type: ParameterTypeInteger, Unit: "kB", Min: -1, Max: maxInt},
+	"hash_mem_multiplier": {
+		Type: ParameterTypeReal, Min: 1, Max: 1000, Versions: MajorVersionRange{Min: 130000},
+	},
+	"logical_decoding_work_mem": {
+		Type: ParameterTypeInteger, Unit: "kB", Min: 64, Max: maxInt, Versions: MajorVersionRange{Min: 130000},
+	},
+	"max_stack_depth":      {Type: ParameterTypeInteger, Unit: "kB", Min: 100, Max: maxInt},
+	"temp_file_limit":      {Type: ParameterTypeInteger, Unit: "kB", Min: -1, Max: maxInt},
+	"effective_cache_size": {Type: ParameterTypeInteger, Unit: "8kB", Min: 1, Max: maxInt},
+
+	// Connections and processes
+	"max_connections": {
+		Type: ParameterTypeInteger, Min: 1, Max: maxBackends, RequiresRestart: true,
+	},
+	"superuser_reserved_connections": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxBackends, RequiresRestart: true,
+	},
+	"max_prepared_transactions": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxBackends, RequiresRestart: true,
+	},
+	"max_locks_per_transaction": {
+		Type: ParameterTypeInteger, Min: 10, Max: maxInt, RequiresRestart: true,
+	},
+	"max_pred_locks_per_transaction": {
+		Type: ParameterTypeInteger, Min: 10, Max: maxInt, RequiresRestart: true,
+	},
+	"max_worker_processes": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxBackends, RequiresRestart: true,
+	},
+	"max_parallel_workers":            {Type: ParameterTypeInteger, Min: 0, Max: 1024},
+	"max_parallel_workers_per_gather": {Type: ParameterTypeInteger, Min: 0, Max: 1024},
+	"max_parallel_maintenance_workers": {
+		Type: ParameterTypeInteger, Min: 0, Max: 1024, Versions: MajorVersionRange{Min: 110000},
+	},
+	"max_files_per_process": {
+		Type: ParameterTypeInteger, Min: 25, Max: maxInt, RequiresRestart: true,
+	},
+	"max_wal_senders": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxBackends, RequiresRestart: true,
+	},
+	"max_replication_slots": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxBackends, RequiresRestart: true,
+	},
+	"max_logical_replication_workers": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxBackends, RequiresRestart: true,
+	},
+	"max_sync_workers_per_subscription": {Type: ParameterTypeInteger, Min: 0, Max: maxBackends},
+	"autovacuum_max_workers": {
+		Type: ParameterTypeInteger, Min: 1, Max: maxBackends, RequiresRestart: true,
+	},
+	"track_activity_query_size": {
+		Type: ParameterTypeInteger, Unit: "B", Min: 100, Max: 1048576, RequiresRestart: true,
+	},
+	"track_commit_timestamp": {Type: ParameterTypeBool, RequiresRestart: true},
+	"old_snapshot_threshold": {
+		Type: ParameterTypeInteger, Unit: "min", Min: -1, Max: 86400, RequiresRestart: true,
+	},
+	"tcp_keepalives_idle":     {Type: ParameterTypeInteger, Unit: "s", Min: 0, Max: maxInt},
+	"tcp_keepalives_interval": {Type: ParameterTypeInteger, Unit: "s", Min: 0, Max: maxInt},
+	"tcp_keepalives_count":    {Type: ParameterTypeInteger, Min: 0, Max: maxInt},
+
+	// Write ahead log and replication
+	"wal_buffers": {
+		Type: ParameterTypeInteger, Unit: "8kB", Min: -1, Max: maxInt / 8192, RequiresRestart: true,
+	},
+	"wal_writer_delay":       {Type: ParameterTypeInteger, Unit: "ms", Min: 1, Max: 10000},
+	"wal_writer_flush_after": {Type: ParameterTypeInteger, Unit: "8kB", Min: 0, Max: maxInt},
+	"max_wal_size":           {Type: ParameterTypeInteger, Unit: "MB", Min: 2, Max: maxInt},
+	"min_wal_size":           {Type: ParameterTypeInteger, Unit: "MB", Min: 2, Max: maxInt},
+	"wal_keep_size": {
+		Type: ParameterTypeInteger, Unit: "MB", Min: 0, Max: maxInt, Versions: MajorVersionRange{Min: 130000},
+	},
+	"wal_keep_segments": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxInt, Versions: MajorVersionRange{Max: 130000},
+	},
+	"checkpoint_timeout":           {Type: ParameterTypeInteger, Unit: "s", Min: 30, Max: 86400},
+	"checkpoint_completion_target": {Type: ParameterTypeReal, Min: 0, Max: 1},
+	"checkpoint_warning":           {Type: ParameterTypeInteger, Unit: "s", Min: 0, Max: maxInt},
+	"checkpoint_flush_after":       {Type: ParameterTypeInteger, Unit: "8kB", Min: 0, Max: 256},
+	"commit_delay":                 {Type: ParameterTypeInteger, Min: 0, Max: 100000},
+	"commit_siblings":              {Type: ParameterTypeInteger, Min: 0, Max: 1000},
+	"synchronous_commit": {
+		Type:   ParameterTypeEnum,
+		Values: append([]string{"local", "remote_write", "remote_apply"}, booleanSpellings...),
+	},
+	"wal_sync_method": {
+		Type:   ParameterTypeEnum,
+		Values: []string{"fsync", "fdatasync", "open_sync", "open_datasync", "fsync_writethrough"},
+	},
+	"wal_recycle":          {Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 120000}},
+	"wal_init_zero":        {Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 120000}},
+	"track_wal_io_timing":  {Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 140000}},
+	"wal_receiver_timeout": {Type: ParameterTypeInteger, Unit: "ms", Min: 0, Max: maxInt},
+	"wal_receiver_status_interval": {
+		Type: ParameterTypeInteger, Unit: "s", Min: 0, Max: maxInt / 1000,
+	},
+	"wal_sender_timeout":          {Type: ParameterTypeInteger, Unit: "ms", Min: 0, Max: maxInt},
+	"hot_standby_feedback":        {Type: ParameterTypeBool},
+	"max_standby_archive_delay":   {Type: ParameterTypeInteger, Unit: "ms", Min: -1, Max: maxInt},
+	"max_standby_streaming_delay": {Type: ParameterTypeInteger, Unit: "ms", Min: -1, Max: maxInt},
+	"vacuum_defer_cleanup_age": {
+		Type: ParameterTypeInteger, Min: 0, Max: 1000000, Versions: MajorVersionRange{Max: 160000},
+	},
+
+	// Query planning
+	"random_page_cost":         {Type: ParameterTypeReal, Min: 0, Max: maxReal},
+	"seq_page_cost":            {Type: ParameterTypeReal, Min: 0, Max: maxReal},
+	"cpu_tuple_cost":           {Type: ParameterTypeReal, Min: 0, Max: maxReal},
+	"cpu_index_tuple_cost":     {Type: ParameterTypeReal, Min: 0, Max: maxReal},
+	"cpu_operator_cost":        {Type: ParameterTypeReal, Min: 0, Max: maxReal},
+	"parallel_setup_cost":      {Type: ParameterTypeReal, Min: 0, Max: maxReal},
+	"parallel_tuple_cost":      {Type: ParameterTypeReal, Min: 0, Max: maxReal},
+	"effective_io_concurrency": {Type: ParameterTypeInteger, Min: 0, Max: 1000},
+	"maintenance_io_concurrency": {
+		Type: ParameterTypeInteger, Min: 0, Max: 1000, Versions: MajorVersionRange{Min: 130000},
+	},
+	"default_statistics_target": {Type: ParameterTypeInteger, Min: 1, Max: 10000},
+	"constraint_exclusion": {
+		Type: ParameterTypeEnum, Values: append([]string{"partition"}, booleanSpellings...),
+	},
+	"plan_cache_mode": {
+		Type:     ParameterTypeEnum,
+		Values:   []string{"auto", "force_generic_plan", "force_custom_plan"},
+		Versions: MajorVersionRange{Min: 120000},
+	},
+	"force_parallel_mode": {
+		Type:     ParameterTypeEnum,
+		Values:   append([]string{"regress"}, booleanSpellings...),
+		Versions: MajorVersionRange{Max: 160000},
+	},
+	"jit":                  {Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 110000}},
+	"enable_bitmapscan":    {Type: ParameterTypeBool},
+	"enable_gathermerge":   {Type: ParameterTypeBool},
+	"enable_hashagg":       {Type: ParameterTypeBool},
+	"enable_hashjoin":      {Type: ParameterTypeBool},
+	"enable_indexonlyscan": {Type: ParameterTypeBool},
+	"enable_indexscan":     {Type: ParameterTypeBool},
+	"enable_material":      {Type: ParameterTypeBool},
+	"enable_mergejoin":     {Type: ParameterTypeBool},
+	"enable_nestloop":      {Type: ParameterTypeBool},
+	"enable_seqscan":       {Type: ParameterTypeBool},
+	"enable_sort":          {Type: ParameterTypeBool},
+	"enable_tidscan":       {Type: ParameterTypeBool},
+	"enable_parallel_append": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 110000},
+	},
+	"enable_parallel_hash": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 110000},
+	},
+	"enable_partition_pruning": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 110000},
+	},
+	"enable_partitionwise_join": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 110000},
+	},
+	"enable_partitionwise_aggregate": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 110000},
+	},
+	"enable_incremental_sort": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 130000},
+	},
+	"enable_memoize":      {Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 140000}},
+	"enable_async_append": {Type: ParameterTypeBool, Versions: MajorVersionRange{Min: 140000}},
+
+	// Autovacuum and vacuum
+	"autovacuum":                     {Type: ParameterTypeBool},
+	"autovacuum_naptime":             {Type: ParameterTypeInteger, Unit: "s", Min: 1, Max: maxInt / 1000},
+	"autovacuum_vacuum_threshold":    {Type: ParameterTypeInteger, Min: 0, Max: maxInt},
+	"autovacuum_analyze_threshold":   {Type: ParameterTypeInteger, Min: 0, Max: maxInt},
+	"autovacuum_vacuum_scale_factor": {Type: ParameterTypeReal, Min: 0, Max: 100},
+	"autovacuum_vacuum_insert_threshold": {
+		Type: ParameterTypeInteger, Min: -1, Max: maxInt, Versions: MajorVersionRange{Min: 130000},
+	},
+	"autovacuum_vacuum_insert_scale_factor": {
+		Type: ParameterTypeReal, Min: 0, Max: 100, Versions: MajorVersionRange{Min: 130000},
+	},
+	"autovacuum_analyze_scale_factor": {Type: ParameterTypeReal, Min: 0, Max: 100},
+	"autovacuum_freeze_max_age": {
+		Type: ParameterTypeInteger, Min: 100000, Max: 2000000000, RequiresRestart: true,
+	},
+	"autovacuum_multixact_freeze_max_age": {
+		Type: ParameterTypeInteger, Min: 10000, Max: 2000000000, RequiresRestart: true,
+	},
+	"autovacuum_vacuum_cost_delay": {Type: ParameterTypeReal, Unit: "ms", Min: -1, Max: 100},
+	"autovacuum_vacuum_cost_limit": {Type: ParameterTypeInteger, Min: -1, Max: 10000},
+	"vacuum_cost_delay":            {Type: ParameterTypeReal, Unit: "ms", Min: 0, Max: 100},
+	"vacuum_cost_limit":            {Type: ParameterTypeInteger, Min: 1, Max: 10000},
+
+	// Client connection defaults
+	"statement_timeout":                   {Type: ParameterTypeInteger, Unit: "ms", Min: 0, Max: maxInt},
+	"lock_timeout":                        {Type: ParameterTypeInteger, Unit: "ms", Min: 0, Max: maxInt},
+	"idle_in_transaction_session_timeout": {Type: ParameterTypeInteger, Unit: "ms", Min: 0, Max: maxInt},
+	"idle_session_timeout": {
+		Type: ParameterTypeInteger, Unit: "ms", Min: 0, Max: maxInt, Versions: MajorVersionRange{Min: 140000},
+	},
+	"deadlock_timeout": {Type: ParameterTypeInteger, Unit: "ms", Min: 1, Max: maxInt},
+	"default_transaction_isolation": {
+		Type:   ParameterTypeEnum,
+		Values: []string{"serializable", "repeatable read", "read committed", "read uncommitted"},
+	},
+	"bytea_output": {Type: ParameterTypeEnum, Values: []string{"escape", "hex"}},
+	"client_min_messages": {
+		Type: ParameterTypeEnum,
+		Values: []string{
+			"debug5", "debug4", "debug3", "debug2", "debug1", "log", "notice", "warning", "error",
+		},
+	},
+
+	// Logging and statistics
+	"log_min_duration_statement":  {Type: ParameterTypeInteger, Unit: "ms", Min: -1, Max: maxInt},
+	"log_autovacuum_min_duration": {Type: ParameterTypeInteger, Unit: "ms", Min: -1, Max: maxInt},
+	"log_temp_files":              {Type: ParameterTypeInteger, Unit: "kB", Min: -1, Max: maxInt},
+	"log_startup_progress_interval": {
+		Type: ParameterTypeInteger, Unit: "ms", Min: 0, Max: maxInt, Versions: MajorVersionRange{Min: 150000},
+	},
+	"log_checkpoints":          {Type: ParameterTypeBool},
+	"log_connections":          {Type: ParameterTypeBool},
+	"log_disconnections":       {Type: ParameterTypeBool},
+	"log_lock_waits":           {Type: ParameterTypeBool},
+	"log_replication_commands": {Type: ParameterTypeBool},
+	"log_statement": {
+		Type: ParameterTypeEnum, Values: []string{"none", "ddl", "mod", "all"},
+	},
+	"log_min_messages":        {Type: ParameterTypeEnum, Values: messageLevels},
+	"log_min_error_statement": {Type: ParameterTypeEnum, Values: messageLevels},
+	"log_error_verbosity": {
+		Type: ParameterTypeEnum, Values: []string{"terse", "default", "verbose"},
+	},
+	"track_activities": {Type: ParameterTypeBool},
+	"track_counts":     {Type: ParameterTypeBool},
+	"track_io_timing":  {Type: ParameterTypeBool},
+	"track_functions": {
+		Type: ParameterTypeEnum, Values: []string{"none", "pl", "all"},
+	},
+	"compute_query_id": {
+		Type:     ParameterTypeEnum,
+		Values:   append([]string{"auto", "regress"}, booleanSpellings...),
+		Versions: MajorVersionRange{Min: 140000},
+	},
+	"recovery_prefetch": {
+		Type:     ParameterTypeEnum,
+		Values:   append([]string{"try"}, booleanSpellings...),
+		Versions: MajorVersionRange{Min: 150000},
+	},
+	"dynamic_library_path": {Type: ParameterTypeString},
+	"extension_control_path": {
+		Type: ParameterTypeString, Versions: MajorVersionRange{Min: 180000},
+	},
+
+	// Removed parameters
+	"stats_temp_directory": {
+		Type: ParameterTypeString, Versions: MajorVersionRange{Max: 150000},
+	},
+	"operator_precedence_warning": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Max: 140000},
+	},
+	"default_with_oids": {
+		Type: ParameterTypeBool, Versions: MajorVersionRange{Max: 120000},
+	},
+	"replacement_sort_tuples": {
+		Type: ParameterTypeInteger, Min: 0, Max: maxInt, Versions: MajorVersionRange{Max: 110000},
+	},
+}
+
+// IsParameterRequiringRestart checks if changing a parameter
+// requires a restart of the PostgreSQL instances
+func IsParameterRequiringRestart(name string) bool {
+	return ParametersCatalog[name].RequiresRestart
+}
+
+// ValidateParameter checks a configuration parameter against the catalog of the
+// parameters supported by the passed PostgreSQL major version. The parameters not
+// included in the catalog are always considered valid
+func ValidateParameter(name, value string, majorVersion int) error {
+	definition, ok := ParametersCatalog[name]
+	if !ok {
+		return nil
+	}
+
+	versions := definition.Versions
+	if (versions.Min != MajorVersionRangeUnlimited && majorVersion < versions.Min) ||
+		(versions.Max != MajorVersionRangeUnlimited && majorVersion >= versions.Max) {
+		return fmt.Errorf("the parameter is not supported by PostgreSQL %v", majorVersion/10000)
+	}
+
+	switch definition.Type {
+	case ParameterTypeBool:
+		if !isValidBoolean(value) {
+			return fmt.Errorf("invalid boolean value %q", value)
+		}
+
+	case ParameterTypeEnum:
+		for _, allowed := range definition.Values {
+			if strings.EqualFold(strings.TrimSpace(value), allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q, must be one of: %v", value, strings.Join(definition.Values, ", "))
+
+	case ParameterTypeInteger, ParameterTypeReal:
+		number, err := parseNumericValue(value, definition.Unit)
+		if err != nil {
+			return err
+		}
+		if number < definition.Min || number > definition.Max {
+			return fmt.Errorf("value %q is outside the valid range (%v .. %v%s)",
+				value, definition.Min, definition.Max, formatUnit(definition.Unit))
+		}
+	}
+
+	return nil
+}
+
+// ParseMemoryParameter gets the size, in bytes, set by the value of a
+// memory parameter of the catalog, like shared_buffers or max_wal_size
+func ParseMemoryParameter(name, value string) (int64, error) {
+	definition, ok := ParametersCatalog[name]
+	unitSize, isMemoryParameter := memoryUnits[definition.Unit]
+	if !ok || !isMemoryParameter {
+		return 0, fmt.Errorf("%s is not a memory parameter", name)
+	}
+
+	number, err := parseNumericValue(value, definition.Unit)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(number * unitSize * 1024), nil
+}
+
+// isValidBoolean checks if a value is a boolean for PostgreSQL,
+// which accepts unique prefixes of its boolean spellings
+func isValidBoolean(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return false
+	}
+
+	switch value {
+	case "on", "of", "off", "1", "0":
+		return true
+	}
+	for _, spelling := range []string{"true", "false", "yes", "no"} {
+		if strings.HasPrefix(spelling, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNumericValue parses a numeric value, converting it from the
+// specified unit, if any, to the unit of the parameter
+func parseNumericValue(value, parameterUnit string) (float64, error) {
+	matches := numericValueRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid numeric value %q", value)
+	}
+
+	var number float64
+	if strings.ContainsAny(matches[1], "xX") {
+		integer, err := strconv.ParseInt(matches[1], 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid numeric value %q", value)
+		}
+		number = float64(integer)
+	} else {
+		var err error
+		if number, err = strconv.ParseFloat(matches[1], 64); err != nil {
+			return 0, fmt.Errorf("invalid numeric value %q", value)
+		}
+	}
+
+	unit := matches[2]
+	if unit == "" {
+		return number, nil
+	}
+
+	for _, units := range []map[string]float64{memoryUnits, timeUnits} {
+		parameterFactor, isParameterUnit := units[parameterUnit]
+		if !isParameterUnit {
+			continue
+		}
+		factor, ok := units[unit]
+		if !ok || unit == "8kB" {
+			break
+		}
+		return number * factor / parameterFactor, nil
+	}
+
+	if parameterUnit == "" {
+		return 0, fmt.Errorf("invalid value %q, the parameter doesn't accept units", value)
+	}
+	return 0, fmt.Errorf("invalid unit %q in value %q", unit, value)
+}
+
+// formatUnit formats the unit of a parameter to be shown in an error message
+func formatUnit(unit string) string {
+	if unit == "" {
+		return ""
+	}
+	return " " + unit
+}
@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("configuration parameters validation", func() {
+	DescribeTable("parameters",
+		func(name, value string, majorVersion int, valid bool) {
+			if valid {
+				Expect(ValidateParameter(name, value, majorVersion)).To(Succeed())
+			} else {
+				Expect(ValidateParameter(name, value, majorVersion)).ToNot(Succeed())
+			}
+		},
+		Entry("a parameter not in the catalog", "pgaudit.log", "all", 140000, true),
+		Entry("a memory parameter with a unit", "shared_buffers", "512MB", 140000, true),
+		Entry("a memory parameter in blocks", "shared_buffers", "16384", 140000, true),
+		Entry("a memory parameter below the minimum", "shared_buffers", "64kB", 140000, false),
+		Entry("a memory parameter with a wrong unit", "shared_buffers", "4G", 140000, false),
+		Entry("a memory parameter with a time unit", "work_mem", "10s", 140000, false),
+		Entry("a time parameter with a unit", "checkpoint_timeout", "15min", 140000, true),
+		Entry("a time parameter above the maximum", "checkpoint_timeout", "2d", 140000, false),
+		Entry("a disabled timeout", "log_min_duration_statement", "-1", 140000, true),
+		Entry("an integer parameter with a unit", "max_connections", "100MB", 140000, false),
+		Entry("a non numeric integer", "max_connections", "many", 140000, false),
+		Entry("a hexadecimal integer", "max_connections", "0x64", 140000, true),
+		Entry("a real parameter", "random_page_cost", "1.1", 140000, true),
+		Entry("a real parameter outside the range", "checkpoint_completion_target", "1.5", 140000, false),
+		Entry("a real parameter with a unit", "vacuum_cost_delay", "2ms", 140000, true),
+		Entry("a boolean parameter", "log_checkpoints", "on", 140000, true),
+		Entry("a boolean prefix", "log_checkpoints", "tr", 140000, true),
+		Entry("a wrong boolean", "log_checkpoints", "enabled", 140000, false),
+		Entry("an enum parameter", "log_statement", "DDL", 140000, true),
+		Entry("an enum parameter accepting booleans", "synchronous_commit", "off", 140000, true),
+		Entry("a wrong enum value", "log_statement", "everything", 140000, false),
+		Entry("an enum with spaces", "default_transaction_isolation", "repeatable read", 140000, true),
+		Entry("a parameter introduced later", "wal_keep_size", "1GB", 120000, false),
+		Entry("a parameter introduced earlier", "wal_keep_size", "1GB", 130000, true),
+		Entry("a removed parameter", "wal_keep_segments", "32", 130000, false),
+		Entry("a parameter not yet removed", "wal_keep_segments", "32", 120000, true),
+		Entry("a removed string parameter", "stats_temp_directory", "/tmp", 150000, false),
+	)
+
+	It("detects the parameters requiring a restart", func() {
+		Expect(IsParameterRequiringRestart("max_connections")).To(BeTrue())
+		Expect(IsParameterRequiringRestart("work_mem")).To(BeFalse())
+		Expect(IsParameterRequiringRestart("pgaudit.log")).To(BeFalse())
+	})
+
+	DescribeTable("memory parameters",
+		func(name, value string, expected int64) {
+			size, err := ParseMemoryParameter(name, value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(Equal(expected))
+		},
+		Entry("with the unit of the parameter", "shared_buffers", "16384", int64(128*1024*1024)),
+		Entry("with a different unit", "shared_buffers", "1GB", int64(1024*1024*1024)),
+		Entry("in megabytes", "max_wal_size", "2048", int64(2*1024*1024*1024)),
+	)
+
+	It("rejects the parameters which are not memory sizes", func() {
+		_, err := ParseMemoryParameter("max_connections", "100")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseMemoryParameter("shared_buffers", "lots")
+		Expect(err).To(HaveOccurred())
+	})
+})
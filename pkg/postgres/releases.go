@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// releaseDateLayout is the layout of the release dates in the catalog
+// of the PostgreSQL minor releases
+const releaseDateLayout = "2006-01-02"
+
+// MinorRelease is a PostgreSQL minor release listed in the catalog
+// known by the operator
+type MinorRelease struct {
+	// Name is the version as published, i.e. "15.1"
+	Name string
+
+	// Version is the parsed version, i.e. 150001
+	Version int
+
+	// ReleaseDate is the date when the minor release has been published
+	ReleaseDate time.Time
+}
+
+// ParseMinorReleases parses the catalog of the PostgreSQL minor releases,
+// where every entry is in the "<version>=<release date>" format, i.e.
+// "15.1=2022-11-10"
+func ParseMinorReleases(entries []string) ([]MinorRelease, error) {
+	releases := make([]MinorRelease, 0, len(entries))
+	for _, entry := range entries {
+		name, date, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("missing release date in PostgreSQL release %q", entry)
+		}
+
+		version, err := GetPostgresVersionFromTag(name)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing PostgreSQL release %q: %w", entry, err)
+		}
+
+		releaseDate, err := time.Parse(releaseDateLayout, date)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing the date of PostgreSQL release %q: %w", entry, err)
+		}
+
+		releases = append(releases, MinorRelease{
+			Name:        name,
+			Version:     version,
+			ReleaseDate: releaseDate,
+		})
+	}
+
+	return releases, nil
+}
+
+// GetLatestMinorRelease gets the most recent release in the catalog
+// having the same major version of the passed one
+func GetLatestMinorRelease(releases []MinorRelease, version int) (MinorRelease, bool) {
+	var latest MinorRelease
+	found := false
+	for _, release := range releases {
+		if GetPostgresMajorVersion(release.Version) != GetPostgresMajorVersion(version) {
+			continue
+		}
+		if !found || release.Version > latest.Version {
+			latest = release
+			found = true
+		}
+	}
+
+	return latest, found
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("minor releases catalog", func() {
+	It("parses the catalog entries", func() {
+		releases, err := ParseMinorReleases([]string{"15.1=2022-11-10", "9.6.24=2021-11-11"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(releases).To(Equal([]MinorRelease{
+			{Name: "15.1", Version: 150001, ReleaseDate: time.Date(2022, 11, 10, 0, 0, 0, 0, time.UTC)},
+			{Name: "9.6.24", Version: 90624, ReleaseDate: time.Date(2021, 11, 11, 0, 0, 0, 0, time.UTC)},
+		}))
+	})
+
+	It("complains about invalid entries", func() {
+		_, err := ParseMinorReleases([]string{"15.1"})
+		Expect(err).To(HaveOccurred())
+		_, err = ParseMinorReleases([]string{"latest=2022-11-10"})
+		Expect(err).To(HaveOccurred())
+		_, err = ParseMinorReleases([]string{"15.1=10/11/2022"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("gets the latest release of the same major version", func() {
+		releases, err := ParseMinorReleases([]string{"15.1=2022-11-10", "15.0=2022-10-13", "14.6=2022-11-10"})
+		Expect(err).ToNot(HaveOccurred())
+
+		latest, found := GetLatestMinorRelease(releases, 150000)
+		Expect(found).To(BeTrue())
+		Expect(latest.Name).To(Equal("15.1"))
+
+		_, found = GetLatestMinorRelease(releases, 130008)
+		Expect(found).To(BeFalse())
+	})
+})
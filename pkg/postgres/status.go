@@ -19,6 +19,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -68,6 +69,31 @@ type PostgresqlStatus struct {
 	// SELECT timeline_id FROM pg_control_checkpoint()
 	TimeLineID int `json:"timeLineID,omitempty"`
 
+	// The configuration parameters set outside the cluster
+	// specification, i.e. via ALTER SYSTEM
+	DriftedParameters []string `json:"driftedParameters,omitempty"`
+
+	// The configuration parameters that have been changed but
+	// need a restart of PostgreSQL to be applied
+	PendingRestartParameters []string `json:"pendingRestartParameters,omitempty"`
+
+	// The number of data page checksum failures detected in the
+	// databases of the instance, from pg_stat_database
+	ChecksumFailures int64 `json:"checksumFailures,omitempty"`
+
+	// The number of errors about damaged data or failed I/O on the
+	// PostgreSQL files logged since the instance manager has been started
+	DataCorruptionErrors int64 `json:"dataCorruptionErrors,omitempty"`
+
+	// The time, in milliseconds and with a resolution of 100 milliseconds,
+	// needed to write and flush a small file in the data volume. While the
+	// storage is stalled, it grows with the age of the pending write
+	StorageWriteLatencyMs int64 `json:"storageWriteLatencyMs,omitempty"`
+
+	// This field is set by the operator when the instance belongs to
+	// a group which can't be promoted to primary
+	IsExcludedFromFailover bool `json:"-"`
+
 	// This field is set when there is an error while extracting the
 	// status of a Pod
 	Error   error `json:"-"`
@@ -83,6 +109,12 @@ type PostgresqlStatus struct {
 	ReplicationInfo PgStatReplicationList `json:"replicationInfo,omitempty"`
 }
 
+// IsDataCorruptionDetected checks whether the instance reported checksum
+// failures or errors about damaged data
+func (status PostgresqlStatus) IsDataCorruptionDetected() bool {
+	return status.ChecksumFailures > 0 || status.DataCorruptionErrors > 0
+}
+
 // PgStatReplication contains the replications of replicas as reported by the primary instance
 type PgStatReplication struct {
 	ApplicationName string `json:"applicationName,omitempty"`
@@ -98,6 +130,44 @@ type PgStatReplication struct {
 	SyncPriority    string `json:"syncPriority,omitempty"`
 }
 
+// IsEquivalentTo checks if two status of the same instance only differ in
+// the progress of the WAL positions, of the WAL archiving and in the size of
+// the instance, that don't require the cluster to be reconciled
+func (status PostgresqlStatus) IsEquivalentTo(other PostgresqlStatus) bool {
+	if status.SystemID != other.SystemID ||
+		status.IsPrimary != other.IsPrimary ||
+		status.ReplayPaused != other.ReplayPaused ||
+		status.PendingRestart != other.PendingRestart ||
+		status.PendingRestartForDecrease != other.PendingRestartForDecrease ||
+		status.IsWalReceiverActive != other.IsWalReceiverActive ||
+		status.IsPgRewindRunning != other.IsPgRewindRunning ||
+		status.MightBeUnavailable != other.MightBeUnavailable ||
+		status.IsArchivingWAL != other.IsArchivingWAL ||
+		status.TimeLineID != other.TimeLineID ||
+		status.ExecutableHash != other.ExecutableHash ||
+		status.IsInstanceManagerUpgrading != other.IsInstanceManagerUpgrading ||
+		status.InstanceManagerVersion != other.InstanceManagerVersion ||
+		status.ChecksumFailures != other.ChecksumFailures ||
+		status.DataCorruptionErrors != other.DataCorruptionErrors ||
+		status.StorageWriteLatencyMs != other.StorageWriteLatencyMs ||
+		!reflect.DeepEqual(status.DriftedParameters, other.DriftedParameters) ||
+		!reflect.DeepEqual(status.PendingRestartParameters, other.PendingRestartParameters) ||
+		len(status.ReplicationInfo) != len(other.ReplicationInfo) {
+		return false
+	}
+
+	for idx := range status.ReplicationInfo {
+		current, previous := status.ReplicationInfo[idx], other.ReplicationInfo[idx]
+		if current.ApplicationName != previous.ApplicationName ||
+			current.State != previous.State ||
+			current.SyncState != previous.SyncState {
+			return false
+		}
+	}
+
+	return true
+}
+
 // PgStatReplicationList is a list of PgStatReplication reported by the primary instance
 type PgStatReplicationList []PgStatReplication
 
@@ -218,6 +288,15 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return false
 	}
 
+	// Replicas excluded from failover go after the ones which
+	// can be promoted
+	switch {
+	case !list.Items[i].IsExcludedFromFailover && list.Items[j].IsExcludedFromFailover:
+		return true
+	case list.Items[i].IsExcludedFromFailover && !list.Items[j].IsExcludedFromFailover:
+		return false
+	}
+
 	// Compare received LSN (bigger LSN orders first)
 	if list.Items[i].ReceivedLsn != list.Items[j].ReceivedLsn {
 		return !list.Items[i].ReceivedLsn.Less(list.Items[j].ReceivedLsn)
@@ -200,3 +200,68 @@ var _ = Describe("PostgreSQL status real", func() {
 		})
 	})
 })
+
+var _ = Describe("PostgreSQL status with instances excluded from failover", func() {
+	It("puts the excluded replicas after the ones which can be promoted", func() {
+		list := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod:                    corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}},
+					ReceivedLsn:            "1/0",
+					IsReady:                true,
+					IsExcludedFromFailover: true,
+				},
+				{
+					Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}},
+					ReceivedLsn: "0/1",
+					IsReady:     true,
+				},
+				{
+					Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}},
+					IsPrimary: true,
+					IsReady:   true,
+				},
+			},
+		}
+
+		sort.Sort(&list)
+		Expect(list.Items[0].Pod.Name).To(Equal("server-1"))
+		Expect(list.Items[1].Pod.Name).To(Equal("server-2"))
+		Expect(list.Items[2].Pod.Name).To(Equal("server-3"))
+	})
+})
+
+var _ = Describe("PostgreSQL status equivalence", func() {
+	status := PostgresqlStatus{
+		IsPrimary:  true,
+		CurrentLsn: "0/1000000",
+		TimeLineID: 1,
+		ReplicationInfo: PgStatReplicationList{
+			{ApplicationName: "cluster-example-2", State: "streaming", SentLsn: "0/1000000"},
+		},
+	}
+
+	It("ignores the progress of the WAL positions", func() {
+		progressed := status
+		progressed.CurrentLsn = "0/2000000"
+		progressed.LastArchivedWAL = "000000010000000000000001"
+		progressed.ReplicationInfo = PgStatReplicationList{
+			{ApplicationName: "cluster-example-2", State: "streaming", SentLsn: "0/2000000"},
+		}
+		Expect(progressed.IsEquivalentTo(status)).To(BeTrue())
+	})
+
+	It("detects a change of role and of the replicas", func() {
+		demoted := status
+		demoted.IsPrimary = false
+		Expect(demoted.IsEquivalentTo(status)).To(BeFalse())
+
+		disconnected := status
+		disconnected.ReplicationInfo = nil
+		Expect(disconnected.IsEquivalentTo(status)).To(BeFalse())
+
+		promoted := status
+		promoted.TimeLineID = 2
+		Expect(promoted.IsEquivalentTo(status)).To(BeFalse())
+	})
+})
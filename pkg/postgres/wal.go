@@ -22,6 +22,7 @@ import (
 	"path"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -96,6 +97,34 @@ func IsWALFile(name string) bool {
 	return WALSegmentRe.MatchString(baseName)
 }
 
+// IsWALArchived checks if the WAL file having the passed name has been
+// archived, given the name of the last file archived by PostgreSQL.
+// Files are archived in order, so every file following the passed
+// one in the same or in a later timeline implies it has been archived
+func IsWALArchived(walName, lastArchivedWAL string) bool {
+	const segmentNameLength = 24
+	if len(lastArchivedWAL) < segmentNameLength {
+		// Either nothing has been archived yet or it's a history file
+		return false
+	}
+
+	lastArchivedSegment := lastArchivedWAL[:segmentNameLength]
+	if !WALSegmentRe.MatchString(lastArchivedSegment) {
+		return false
+	}
+
+	switch {
+	case strings.ToUpper(lastArchivedSegment) > strings.ToUpper(walName):
+		return true
+	case strings.EqualFold(lastArchivedSegment, walName):
+		// A partial file or a backup label don't mean the
+		// segment itself has been archived
+		return len(lastArchivedWAL) == segmentNameLength
+	default:
+		return false
+	}
+}
+
 // SegmentFromName retrieves the timeline, log ID and segment ID
 // from the name of a xlog segment, and can also handle a full path
 // or a simple file name
@@ -190,3 +219,27 @@ func (segment Segment) NextSegments(size int, postgresVersion *int, segmentSize
 
 	return result
 }
+
+// SegmentsUntil generates the list of the segments starting from
+// `segment` up to `end`, both included. The two segments must belong
+// to the same timeline.
+// If segmentSize == nil, wal_segment_size=DefaultWALSegmentSize is assumed.
+func (segment Segment) SegmentsUntil(end Segment, segmentSize *int64) ([]Segment, error) {
+	if segment.Tli != end.Tli {
+		return nil, fmt.Errorf("segments %s and %s belong to different timelines", segment.Name(), end.Name())
+	}
+
+	precedes := func(a, b Segment) bool {
+		return a.Log < b.Log || (a.Log == b.Log && a.Seg < b.Seg)
+	}
+	if precedes(end, segment) {
+		return nil, fmt.Errorf("segment %s precedes %s", end.Name(), segment.Name())
+	}
+
+	var result []Segment
+	for current := segment; precedes(current, end); current = current.NextSegments(2, nil, segmentSize)[1] {
+		result = append(result, current)
+	}
+
+	return append(result, end), nil
+}
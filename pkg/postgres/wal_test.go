@@ -188,6 +188,33 @@ var _ = Describe("Segment name parsing and generation", func() {
 				test.start.Name(), test.size, test.version, test.walSize)
 		}
 	})
+
+	It("can generate the segments in a range", func() {
+		segments, err := MustSegmentFromName("0000000100000001000000FE").SegmentsUntil(
+			MustSegmentFromName("000000010000000200000001"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segments).To(Equal([]Segment{
+			MustSegmentFromName("0000000100000001000000FE"),
+			MustSegmentFromName("0000000100000001000000FF"),
+			MustSegmentFromName("000000010000000200000000"),
+			MustSegmentFromName("000000010000000200000001"),
+		}))
+
+		segments, err = MustSegmentFromName("000000010000000200000001").SegmentsUntil(
+			MustSegmentFromName("000000010000000200000001"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segments).To(HaveLen(1))
+	})
+
+	It("refuses to generate invalid segment ranges", func() {
+		_, err := MustSegmentFromName("000000010000000200000001").SegmentsUntil(
+			MustSegmentFromName("000000010000000100000001"), nil)
+		Expect(err).To(HaveOccurred())
+
+		_, err = MustSegmentFromName("000000010000000200000001").SegmentsUntil(
+			MustSegmentFromName("000000020000000200000003"), nil)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("WAL files checking", func() {
@@ -244,3 +271,29 @@ var _ = Describe("WAL files checking", func() {
 		}
 	})
 })
+
+var _ = Describe("WAL archiving progress", func() {
+	const walName = "00000001000000000000000A"
+
+	It("detects when nothing has been archived yet", func() {
+		Expect(IsWALArchived(walName, "")).To(BeFalse())
+		Expect(IsWALArchived(walName, "00000002.history")).To(BeFalse())
+	})
+
+	It("detects the WAL file being the last archived one", func() {
+		Expect(IsWALArchived(walName, walName)).To(BeTrue())
+		Expect(IsWALArchived(walName, "00000001000000000000000a")).To(BeTrue())
+	})
+
+	It("detects the WAL file being archived before the last one", func() {
+		Expect(IsWALArchived(walName, "00000001000000000000000B")).To(BeTrue())
+		Expect(IsWALArchived(walName, "000000020000000000000005")).To(BeTrue())
+		Expect(IsWALArchived(walName, "00000001000000000000000B.00000028.backup")).To(BeTrue())
+	})
+
+	It("detects the WAL file not being archived yet", func() {
+		Expect(IsWALArchived(walName, "000000010000000000000009")).To(BeFalse())
+		Expect(IsWALArchived(walName, "00000001000000000000000A.00000028.backup")).To(BeFalse())
+		Expect(IsWALArchived(walName, "00000001000000000000000A.partial")).To(BeFalse())
+	})
+})
@@ -0,0 +1,321 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry contains a minimal client of the OCI distribution API,
+// used to resolve the digest of container images and to verify their
+// cosign signatures
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// dockerHubHost is the host name used in the image names hosted on Docker Hub
+	dockerHubHost = "docker.io"
+
+	// dockerHubRegistryHost is the host serving the registry API of Docker Hub
+	dockerHubRegistryHost = "registry-1.docker.io"
+
+	// maxResponseSize is the maximum size of a manifest or of a signature
+	// payload we are willing to read
+	maxResponseSize = 4 * 1024 * 1024
+)
+
+// manifestMediaTypes are the media types of the manifests we accept,
+// with image indexes coming first as they are what a tag points to
+// in multi-architecture images
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Client is a client of the OCI distribution API
+type Client struct {
+	httpClient  *http.Client
+	credentials Credentials
+}
+
+// NewClient creates a new registry client using the passed credentials
+func NewClient(credentials Credentials) *Client {
+	return &Client{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		credentials: credentials,
+	}
+}
+
+// repository is a repository inside a registry
+type repository struct {
+	host string
+	name string
+}
+
+// parseImage splits the name of an image into its repository and reference,
+// being it a tag or a digest
+func parseImage(image string) (repository, *utils.Reference) {
+	reference := utils.NewReference(image)
+
+	host, name, _ := strings.Cut(reference.Name, "/")
+	if host == dockerHubHost {
+		host = dockerHubRegistryHost
+	}
+
+	return repository{host: host, name: name}, reference
+}
+
+// ResolveDigest gets the digest, in the `sha256:<hex>` format, of the
+// passed image. Images already containing a digest are not looked up
+func (c *Client) ResolveDigest(ctx context.Context, image string) (string, error) {
+	repo, reference := parseImage(image)
+	if reference.Digest != "" {
+		return "sha256:" + reference.Digest, nil
+	}
+
+	response, err := c.get(ctx, http.MethodHead, repo, "manifests/"+reference.Tag, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	_ = response.Body.Close()
+
+	if digest := response.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+
+	// The registry didn't tell us the digest, so we compute
+	// it from the content of the manifest
+	manifest, err := c.getContent(ctx, repo, "manifests/"+reference.Tag, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+
+	return computeDigest(manifest), nil
+}
+
+// getManifest gets and decodes a manifest from the passed repository
+func (c *Client) getManifest(ctx context.Context, repo repository, reference string) (*manifest, error) {
+	content, err := c.getContent(ctx, repo, "manifests/"+reference, manifestMediaTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	var result manifest
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("while decoding manifest %s: %w", reference, err)
+	}
+
+	return &result, nil
+}
+
+// getBlob gets a blob from the passed repository, checking its digest
+func (c *Client) getBlob(ctx context.Context, repo repository, digest string) ([]byte, error) {
+	content, err := c.getContent(ctx, repo, "blobs/"+digest, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if computeDigest(content) != digest {
+		return nil, fmt.Errorf("digest mismatch for blob %s", digest)
+	}
+
+	return content, nil
+}
+
+// getContent reads the body of the passed registry API call
+func (c *Client) getContent(ctx context.Context, repo repository, path string, accept []string) ([]byte, error) {
+	response, err := c.get(ctx, http.MethodGet, repo, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("while reading %s: %w", path, err)
+	}
+
+	return content, nil
+}
+
+// get invokes the passed registry API, authenticating when the
+// registry asks for it. The caller needs to close the body of the response
+func (c *Client) get(
+	ctx context.Context,
+	method string,
+	repo repository,
+	path string,
+	accept []string,
+) (*http.Response, error) {
+	apiURL := fmt.Sprintf("https://%s/v2/%s/%s", repo.host, repo.name, path)
+
+	response, err := c.do(ctx, method, apiURL, accept, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		_ = response.Body.Close()
+
+		authorization, err := c.authorize(ctx, repo, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err = c.do(ctx, method, apiURL, accept, authorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("unexpected status code %d from %s", response.StatusCode, apiURL)
+	}
+
+	return response, nil
+}
+
+// do executes an HTTP request
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	requestURL string,
+	accept []string,
+	authorization string,
+) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accept) > 0 {
+		request.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("while invoking %s: %w", requestURL, err)
+	}
+
+	return response, nil
+}
+
+// authorize answers to the authentication challenge of the registry,
+// returning the value of the Authorization header to be used
+func (c *Client) authorize(ctx context.Context, repo repository, challenge string) (string, error) {
+	credential, hasCredential := c.credentials.lookup(repo.host)
+
+	scheme, parameters := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if !hasCredential {
+			return "", fmt.Errorf("no credentials available for %s", repo.host)
+		}
+		return "Basic " + credential.basicAuth(), nil
+
+	case "bearer":
+		tokenURL, err := url.Parse(parameters["realm"])
+		if err != nil || parameters["realm"] == "" {
+			return "", fmt.Errorf("invalid authentication realm from %s: %q", repo.host, parameters["realm"])
+		}
+
+		query := tokenURL.Query()
+		if service := parameters["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", repo.name))
+		tokenURL.RawQuery = query.Encode()
+
+		authorization := ""
+		if hasCredential {
+			authorization = "Basic " + credential.basicAuth()
+		}
+
+		response, err := c.do(ctx, http.MethodGet, tokenURL.String(), nil, authorization)
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			_ = response.Body.Close()
+		}()
+
+		if response.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status code %d while getting a token from %s",
+				response.StatusCode, tokenURL.Host)
+		}
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(&token); err != nil {
+			return "", fmt.Errorf("while decoding the token from %s: %w", tokenURL.Host, err)
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+
+		return "Bearer " + token.Token, nil
+
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge from %s: %q", repo.host, challenge)
+	}
+}
+
+// parseChallenge parses the WWW-Authenticate header, returning the lowercase
+// scheme and the parameters of the challenge
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	parameters := make(map[string]string)
+
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimSpace(rest), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+			rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		parameters[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+
+	return strings.ToLower(scheme), parameters
+}
+
+// computeDigest computes the sha256 digest of the passed content
+func computeDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeRegistry is a registry serving a fixed set of manifests and blobs,
+// requiring a bearer token obtained with the "user:password" credentials
+type fakeRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte
+	blobs     map[string][]byte
+}
+
+func newFakeRegistry() *fakeRegistry {
+	registry := &fakeRegistry{
+		manifests: make(map[string][]byte),
+		blobs:     make(map[string][]byte),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		Expect(r.URL.Query().Get("scope")).To(Equal("repository:cnpg/postgresql:pull"))
+		_, _ = w.Write([]byte(`{"token": "secret-token"}`))
+	})
+	mux.HandleFunc("/v2/cnpg/postgresql/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.Header().Set("WWW-Authenticate",
+				`Bearer realm="`+registry.server.URL+`/token",service="fake"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		kind, reference, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/cnpg/postgresql/"), "/")
+		content, found := registry.manifests[reference]
+		if kind == "blobs" {
+			content, found = registry.blobs[reference]
+		}
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Docker-Content-Digest", computeDigest(content))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(content)
+		}
+	})
+	registry.server = httptest.NewTLSServer(mux)
+
+	return registry
+}
+
+func (registry *fakeRegistry) client() *Client {
+	credentials := Credentials{}
+	config := `{"auths": {"` + registry.host() + `": {"auth": "` +
+		base64.StdEncoding.EncodeToString([]byte("user:password")) + `"}}}`
+	Expect(credentials.AddDockerConfig([]byte(config))).To(Succeed())
+
+	return &Client{httpClient: registry.server.Client(), credentials: credentials}
+}
+
+func (registry *fakeRegistry) host() string {
+	return strings.TrimPrefix(registry.server.URL, "https://")
+}
+
+var _ = Describe("digest resolution", func() {
+	var registry *fakeRegistry
+
+	BeforeEach(func() {
+		registry = newFakeRegistry()
+		DeferCleanup(registry.server.Close)
+	})
+
+	It("resolves a tag to the digest of its manifest", func() {
+		manifest := []byte(`{"schemaVersion": 2}`)
+		registry.manifests["15.1"] = manifest
+
+		digest, err := registry.client().ResolveDigest(context.TODO(), registry.host()+"/cnpg/postgresql:15.1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(computeDigest(manifest)))
+	})
+
+	It("doesn't look up images already containing a digest", func() {
+		image := registry.host() + "/cnpg/postgresql:15.1@sha256:0123456789abcdef"
+		digest, err := (&Client{}).ResolveDigest(context.TODO(), image)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal("sha256:0123456789abcdef"))
+	})
+
+	It("fails when the tag doesn't exist", func() {
+		_, err := registry.client().ResolveDigest(context.TODO(), registry.host()+"/cnpg/postgresql:16")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails without valid credentials", func() {
+		registry.manifests["15.1"] = []byte(`{"schemaVersion": 2}`)
+		client := &Client{httpClient: registry.server.Client(), credentials: Credentials{}}
+		_, err := client.ResolveDigest(context.TODO(), registry.host()+"/cnpg/postgresql:15.1")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("registry credentials", func() {
+	It("parses both the auth and the username and password fields", func() {
+		credentials := Credentials{}
+		Expect(credentials.AddDockerConfig([]byte(`{"auths": {
+			"https://index.docker.io/v1/": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("hub:pwd")) + `"},
+			"ghcr.io": {"username": "gh", "password": "token"}
+		}}`))).To(Succeed())
+
+		credential, found := credentials.lookup(dockerHubRegistryHost)
+		Expect(found).To(BeTrue())
+		Expect(credential).To(Equal(Credential{Username: "hub", Password: "pwd"}))
+
+		credential, found = credentials.lookup("ghcr.io")
+		Expect(found).To(BeTrue())
+		Expect(credential).To(Equal(Credential{Username: "gh", Password: "token"}))
+
+		_, found = credentials.lookup("quay.io")
+		Expect(found).To(BeFalse())
+	})
+
+	It("parses the authentication challenges", func() {
+		scheme, parameters := parseChallenge(
+			`Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:a/b:pull"`)
+		Expect(scheme).To(Equal("bearer"))
+		Expect(parameters).To(Equal(map[string]string{
+			"realm":   "https://ghcr.io/token",
+			"service": "ghcr.io",
+			"scope":   "repository:a/b:pull",
+		}))
+	})
+})
@@ -0,0 +1,161 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// cosignSignatureAnnotation is the annotation of the layers of a cosign
+// signature manifest containing the signature of the layer content
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// ErrNoValidSignature is returned when no signature of an image has
+// been verified with the passed keys
+var ErrNoValidSignature = errors.New("no valid signature found")
+
+// manifest is the part of an image manifest we are interested in
+type manifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// simpleSigningPayload is the payload signed by cosign
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// ParsePublicKeys parses the PEM encoded public keys contained in the
+// passed data
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+
+	return keys, nil
+}
+
+// VerifySignature checks that the image with the passed digest has a cosign
+// signature, stored in the same repository of the image, made with one of
+// the passed public keys
+func (c *Client) VerifySignature(
+	ctx context.Context,
+	image string,
+	digest string,
+	keys []crypto.PublicKey,
+) error {
+	repo, _ := parseImage(image)
+
+	// cosign stores the signatures of an image in a tag
+	// derived from its digest
+	signatureTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	signatureManifest, err := c.getManifest(ctx, repo, signatureTag)
+	if err != nil {
+		return fmt.Errorf("while getting the signatures of %s: %w", image, err)
+	}
+
+	for _, layer := range signatureManifest.Layers {
+		encodedSignature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+		if err != nil {
+			continue
+		}
+
+		payload, err := c.getBlob(ctx, repo, layer.Digest)
+		if err != nil {
+			return fmt.Errorf("while getting the signature payload of %s: %w", image, err)
+		}
+
+		if !isSignedByAny(payload, signature, keys) {
+			continue
+		}
+
+		var signedPayload simpleSigningPayload
+		if err := json.Unmarshal(payload, &signedPayload); err != nil {
+			continue
+		}
+
+		if signedPayload.Critical.Image.DockerManifestDigest == digest {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w for %s@%s", ErrNoValidSignature, image, digest)
+}
+
+// isSignedByAny checks the signature of the payload against the passed keys
+func isSignedByAny(payload []byte, signature []byte, keys []crypto.PublicKey) bool {
+	hash := sha256.Sum256(payload)
+
+	for _, key := range keys {
+		switch publicKey := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(publicKey, hash[:], signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hash[:], signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(publicKey, payload, signature) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
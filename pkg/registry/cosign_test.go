@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cosign signature verification", func() {
+	var (
+		registry   *fakeRegistry
+		signingKey *ecdsa.PrivateKey
+		image      string
+		digest     string
+	)
+
+	// sign stores in the registry a cosign signature of the
+	// passed digest made with the signing key
+	sign := func(signedDigest string) {
+		payload := []byte(fmt.Sprintf(`{"critical": {"identity": {"docker-reference": "%s"},`+
+			`"image": {"docker-manifest-digest": "%s"}, "type": "cosign container image signature"}}`,
+			image, signedDigest))
+		hash := sha256.Sum256(payload)
+		signature, err := ecdsa.SignASN1(rand.Reader, signingKey, hash[:])
+		Expect(err).ToNot(HaveOccurred())
+
+		payloadDigest := computeDigest(payload)
+		registry.blobs[payloadDigest] = payload
+		registry.manifests[strings.Replace(digest, ":", "-", 1)+".sig"] = []byte(fmt.Sprintf(
+			`{"schemaVersion": 2, "layers": [{"digest": "%s", "annotations": {"%s": "%s"}}]}`,
+			payloadDigest, cosignSignatureAnnotation, base64.StdEncoding.EncodeToString(signature)))
+	}
+
+	BeforeEach(func() {
+		registry = newFakeRegistry()
+		DeferCleanup(registry.server.Close)
+
+		var err error
+		signingKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+
+		image = registry.host() + "/cnpg/postgresql:15.1"
+		digest = computeDigest([]byte("manifest"))
+	})
+
+	It("accepts an image signed with a known key", func() {
+		sign(digest)
+		err := registry.client().VerifySignature(context.TODO(), image, digest,
+			[]crypto.PublicKey{&signingKey.PublicKey})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("rejects an image signed with an unknown key", func() {
+		sign(digest)
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = registry.client().VerifySignature(context.TODO(), image, digest,
+			[]crypto.PublicKey{&otherKey.PublicKey})
+		Expect(err).To(MatchError(ErrNoValidSignature))
+	})
+
+	It("rejects a signature made for another image", func() {
+		sign(computeDigest([]byte("another manifest")))
+		err := registry.client().VerifySignature(context.TODO(), image, digest,
+			[]crypto.PublicKey{&signingKey.PublicKey})
+		Expect(err).To(MatchError(ErrNoValidSignature))
+	})
+
+	It("rejects an image without signatures", func() {
+		err := registry.client().VerifySignature(context.TODO(), image, digest,
+			[]crypto.PublicKey{&signingKey.PublicKey})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("parses PEM encoded public keys", func() {
+		der, err := x509.MarshalPKIXPublicKey(&signingKey.PublicKey)
+		Expect(err).ToNot(HaveOccurred())
+		data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+		keys, err := ParsePublicKeys(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+
+		_, err = ParsePublicKeys([]byte("not a key"))
+		Expect(err).To(HaveOccurred())
+	})
+})
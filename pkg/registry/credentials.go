@@ -0,0 +1,98 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Credential is a user name and password used to authenticate to a registry
+type Credential struct {
+	Username string
+	Password string
+}
+
+// basicAuth encodes the credential for the basic authentication scheme
+func (credential Credential) basicAuth() string {
+	return base64.StdEncoding.EncodeToString([]byte(credential.Username + ":" + credential.Password))
+}
+
+// Credentials are the credentials to be used, indexed by registry host
+type Credentials map[string]Credential
+
+// dockerConfig is the content of a `.dockerconfigjson` key of
+// a `kubernetes.io/dockerconfigjson` secret
+type dockerConfig struct {
+	Auths map[string]struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	} `json:"auths"`
+}
+
+// AddDockerConfig adds to the credentials the ones contained in the
+// passed Docker configuration. Already known registries are not replaced
+func (credentials Credentials) AddDockerConfig(data []byte) error {
+	var config dockerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("while decoding the docker configuration: %w", err)
+	}
+
+	for registry, auth := range config.Auths {
+		credential := Credential{Username: auth.Username, Password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return fmt.Errorf("while decoding the credentials of %s: %w", registry, err)
+			}
+			credential.Username, credential.Password, _ = strings.Cut(string(decoded), ":")
+		}
+
+		host := normalizeRegistryHost(registry)
+		if _, found := credentials[host]; !found {
+			credentials[host] = credential
+		}
+	}
+
+	return nil
+}
+
+// lookup finds the credential to be used for the passed registry host
+func (credentials Credentials) lookup(host string) (Credential, bool) {
+	credential, found := credentials[normalizeRegistryHost(host)]
+	return credential, found
+}
+
+// normalizeRegistryHost gets the host name of a registry from the keys
+// used in the Docker configuration, that can be either host names or URLs
+func normalizeRegistryHost(registry string) string {
+	host := registry
+	if _, withoutScheme, found := strings.Cut(host, "://"); found {
+		host = withoutScheme
+	}
+	host, _, _ = strings.Cut(host, "/")
+
+	switch host {
+	case dockerHubRegistryHost, "index.docker.io":
+		return dockerHubHost
+	default:
+		return host
+	}
+}
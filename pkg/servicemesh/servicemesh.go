@@ -0,0 +1,116 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servicemesh contains the integration with the service meshes
+// injecting a proxy sidecar in the Pods of the cluster
+package servicemesh
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+const (
+	// EnvironmentVariableName is the name of the environment variable
+	// containing the service mesh the process is running in
+	EnvironmentVariableName = "CNPG_SERVICE_MESH"
+
+	// istioQuitURL is the endpoint of the Istio agent stopping the proxy
+	istioQuitURL = "http://127.0.0.1:15020/quitquitquit"
+
+	// linkerdShutdownURL is the endpoint of the Linkerd proxy stopping it
+	linkerdShutdownURL = "http://127.0.0.1:4191/shutdown"
+
+	// quitTimeout is the time we wait for the proxy to accept the request
+	quitTimeout = 10 * time.Second
+)
+
+// GetPodAnnotations gets the annotations configuring the proxy sidecar
+// injected by the service mesh. The status port of the instance manager,
+// which is used by the operator, is excluded from the proxy, as the
+// operator may not be part of the mesh
+func GetPodAnnotations(meshType apiv1.ServiceMeshType) map[string]string {
+	statusPort := strconv.Itoa(url.StatusPort)
+
+	switch meshType {
+	case apiv1.ServiceMeshTypeIstio:
+		return map[string]string{
+			// The instance manager needs the network as soon as it starts
+			"proxy.istio.io/config":                        `{"holdApplicationUntilProxyStarts": true}`,
+			"traffic.sidecar.istio.io/excludeInboundPorts": statusPort,
+		}
+	case apiv1.ServiceMeshTypeLinkerd:
+		return map[string]string{
+			"config.linkerd.io/skip-inbound-ports": statusPort,
+		}
+	default:
+		return nil
+	}
+}
+
+// getQuitURL gets the endpoint stopping the proxy of the passed service mesh
+func getQuitURL(meshType apiv1.ServiceMeshType) string {
+	switch meshType {
+	case apiv1.ServiceMeshTypeIstio:
+		return istioQuitURL
+	case apiv1.ServiceMeshTypeLinkerd:
+		return linkerdShutdownURL
+	default:
+		return ""
+	}
+}
+
+// QuitSidecar asks the proxy sidecar of the service mesh the process is
+// running in, if any, to terminate. Otherwise, the proxy would keep running
+// after the process completes, and the Job would never complete
+func QuitSidecar(ctx context.Context) {
+	quitURL := getQuitURL(apiv1.ServiceMeshType(os.Getenv(EnvironmentVariableName)))
+	if quitURL == "" {
+		return
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("url", quitURL)
+	// The passed context may already be cancelled when the process is
+	// terminating, but the proxy must be stopped anyway
+	ctx, cancel := context.WithTimeout(context.Background(), quitTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, quitURL, nil)
+	if err != nil {
+		contextLogger.Error(err, "Cannot stop the service mesh proxy")
+		return
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		contextLogger.Error(err, "Cannot stop the service mesh proxy")
+		return
+	}
+	_ = response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		contextLogger.Info("The service mesh proxy refused to stop", "statusCode", response.StatusCode)
+		return
+	}
+	contextLogger.Info("Stopped the service mesh proxy")
+}
@@ -0,0 +1,40 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicemesh
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Service mesh integration", func() {
+	It("excludes the status port from the proxy", func() {
+		Expect(GetPodAnnotations(apiv1.ServiceMeshTypeIstio)).To(
+			HaveKeyWithValue("traffic.sidecar.istio.io/excludeInboundPorts", "8000"))
+		Expect(GetPodAnnotations(apiv1.ServiceMeshTypeLinkerd)).To(
+			HaveKeyWithValue("config.linkerd.io/skip-inbound-ports", "8000"))
+		Expect(GetPodAnnotations("")).To(BeEmpty())
+	})
+
+	It("knows how to stop the proxy of each service mesh", func() {
+		Expect(getQuitURL(apiv1.ServiceMeshTypeIstio)).To(Equal(istioQuitURL))
+		Expect(getQuitURL(apiv1.ServiceMeshTypeLinkerd)).To(Equal(linkerdShutdownURL))
+		Expect(getQuitURL("")).To(BeEmpty())
+	})
+})
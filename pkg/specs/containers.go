@@ -18,11 +18,16 @@ package specs
 
 import (
 	"fmt"
+	"path"
+	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/strings/slices"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 // createBootstrapContainer creates the init container bootstrapping the operator
@@ -47,6 +52,101 @@ func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
 	return container
 }
 
+// createExtensionContainers creates the init containers copying the files
+// of the extension images into the volume shared with PostgreSQL, using
+// the instance manager installed by the bootstrap container
+func createExtensionContainers(cluster apiv1.Cluster) []corev1.Container {
+	extensions := cluster.Spec.PostgresConfiguration.Extensions
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	containers := make([]corev1.Container, 0, len(extensions))
+	for _, extension := range extensions {
+		command := []string{
+			"/controller/manager",
+			"extension",
+			"install",
+			path.Join(postgres.ExtensionsBaseDirectory, extension.Name),
+		}
+		for _, directory := range append(extension.GetExtensionControlPath(), extension.GetDynamicLibraryPath()...) {
+			if !slices.Contains(command[4:], directory) {
+				command = append(command, directory)
+			}
+		}
+
+		container := corev1.Container{
+			Name:            ExtensionContainerNamePrefix + extension.Name,
+			Image:           extension.Image,
+			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+			Command:         command,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "scratch-data",
+					MountPath: postgres.ScratchDataDirectory,
+				},
+				{
+					Name:      "extensions",
+					MountPath: postgres.ExtensionsBaseDirectory,
+				},
+			},
+			Resources:       cluster.Spec.Resources,
+			SecurityContext: CreateContainerSecurityContext(),
+		}
+		addManagerLoggingOptions(cluster, &container)
+
+		containers = append(containers, container)
+	}
+
+	return containers
+}
+
+// AreExtensionContainersUpToDate checks whether an instance Pod copies
+// the files of the extension images requested in the cluster
+func AreExtensionContainersUpToDate(cluster apiv1.Cluster, pod corev1.Pod) bool {
+	var current []corev1.Container
+	for _, container := range pod.Spec.InitContainers {
+		if strings.HasPrefix(container.Name, ExtensionContainerNamePrefix) {
+			current = append(current, container)
+		}
+	}
+
+	expected := createExtensionContainers(cluster)
+	if len(current) != len(expected) {
+		return false
+	}
+	for idx := range expected {
+		if current[idx].Name != expected[idx].Name ||
+			current[idx].Image != expected[idx].Image ||
+			!reflect.DeepEqual(current[idx].Command, expected[idx].Command) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// createSidecarContainers creates the containers running next to PostgreSQL,
+// mounting in each of them the volume containing the copy of the log stream
+func createSidecarContainers(cluster apiv1.Cluster) []corev1.Container {
+	sidecars := cluster.Spec.PodTemplate.GetSidecars()
+	if len(sidecars) == 0 {
+		return nil
+	}
+
+	containers := make([]corev1.Container, 0, len(sidecars))
+	for _, sidecar := range sidecars {
+		container := *sidecar.DeepCopy()
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      LogShippingVolumeName,
+			MountPath: postgres.LogShippingPath,
+		})
+		containers = append(containers, container)
+	}
+
+	return containers
+}
+
 // addManagerLoggingOptions propagate the logging configuration
 // to the manager inside the generated pod.
 func addManagerLoggingOptions(cluster apiv1.Cluster, container *corev1.Container) {
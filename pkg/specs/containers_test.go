@@ -62,3 +62,51 @@ var _ = Describe("Container Security Context creation", func() {
 		Expect(*securityContext.ReadOnlyRootFilesystem).To(BeTrue())
 	})
 })
+
+var _ = Describe("Extension containers", func() {
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				Extensions: []apiv1.ExtensionConfiguration{
+					{Name: "pgvector", Image: "pgvector:0.8.0"},
+					{
+						Name:                 "postgis",
+						Image:                "postgis:3.5.2",
+						ExtensionControlPath: []string{"share"},
+						DynamicLibraryPath:   []string{"lib", "system/lib", "share"},
+					},
+				},
+			},
+		},
+	}
+
+	It("copies the directories of every extension image", func() {
+		containers := createExtensionContainers(cluster)
+		Expect(containers).To(HaveLen(2))
+		Expect(containers[0].Name).To(Equal("extension-pgvector"))
+		Expect(containers[0].Image).To(Equal("pgvector:0.8.0"))
+		Expect(containers[0].Command).To(Equal([]string{
+			"/controller/manager", "extension", "install", "/extensions/pgvector", "share", "lib",
+		}))
+		Expect(containers[1].Command).To(Equal([]string{
+			"/controller/manager", "extension", "install", "/extensions/postgis", "share", "lib", "system/lib",
+		}))
+		Expect(createExtensionContainers(apiv1.Cluster{})).To(BeEmpty())
+	})
+
+	It("detects the changes of the extension images", func() {
+		pod := corev1.Pod{
+			Spec: corev1.PodSpec{
+				InitContainers: append(
+					[]corev1.Container{createBootstrapContainer(cluster)},
+					createExtensionContainers(cluster)...),
+			},
+		}
+		Expect(AreExtensionContainersUpToDate(cluster, pod)).To(BeTrue())
+		Expect(AreExtensionContainersUpToDate(apiv1.Cluster{}, pod)).To(BeFalse())
+
+		updatedCluster := cluster.DeepCopy()
+		updatedCluster.Spec.PostgresConfiguration.Extensions[0].Image = "pgvector:0.8.1"
+		Expect(AreExtensionContainersUpToDate(*updatedCluster, pod)).To(BeFalse())
+	})
+})
@@ -27,10 +27,22 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/servicemesh"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 const (
+	// JobRoleJoin is the role of the jobs cloning a new replica
+	JobRoleJoin = "join"
+
+	// postInitSQLRefsFolder points to the folder of
+	// postInitSQL files in the primary job with initdb.
+	postInitSQLRefsFolder = "/etc/post-init-sql"
+
+	// postInitTemplateSQLRefsFolder points to the folder of
+	// postInitTemplateSQL files in the primary job with initdb.
+	postInitTemplateSQLRefsFolder = "/etc/post-init-template-sql"
+
 	// postInitApplicationSQLRefsFolder points to the folder of
 	// postInitApplicationSQL files in the primary job with initdb.
 	postInitApplicationSQLRefsFolder = "/etc/post-init-application-sql"
@@ -81,6 +93,16 @@ func CreatePrimaryJobViaInitdb(cluster apiv1.Cluster, nodeSerial int) *batchv1.J
 		return createPrimaryJob(cluster, nodeSerial, "import", initCommand)
 	}
 
+	if cluster.ShouldInitDBRunPostInitSQLRefs() {
+		initCommand = append(initCommand,
+			"--post-init-sql-refs-folder", postInitSQLRefsFolder)
+	}
+
+	if cluster.ShouldInitDBRunPostInitTemplateSQLRefs() {
+		initCommand = append(initCommand,
+			"--post-init-template-sql-refs-folder", postInitTemplateSQLRefsFolder)
+	}
+
 	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
 		initCommand = append(initCommand,
 			"--post-init-application-sql-refs-folder", postInitApplicationSQLRefsFolder)
@@ -120,6 +142,18 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if localeCType := config.LocaleCType; localeCType != "" {
 		options = append(options, fmt.Sprintf("--lc-ctype=%s", localeCType))
 	}
+	if localeProvider := config.LocaleProvider; localeProvider != "" {
+		options = append(options, fmt.Sprintf("--locale-provider=%s", localeProvider))
+	}
+	if icuLocale := config.IcuLocale; icuLocale != "" {
+		options = append(options, fmt.Sprintf("--icu-locale=%s", icuLocale))
+	}
+	if icuRules := config.IcuRules; icuRules != "" {
+		options = append(options, fmt.Sprintf("--icu-rules=%s", icuRules))
+	}
+	if builtinLocale := config.BuiltinLocale; builtinLocale != "" {
+		options = append(options, fmt.Sprintf("--builtin-locale=%s", builtinLocale))
+	}
 	if walSegmentSize := config.WalSegmentSize; walSegmentSize != 0 && utils.IsPowerOfTwo(walSegmentSize) {
 		options = append(options, fmt.Sprintf("--wal-segsize=%v", walSegmentSize))
 	}
@@ -185,6 +219,29 @@ func CreatePrimaryJobViaPgBaseBackup(cluster apiv1.Cluster, nodeSerial int) *bat
 	return createPrimaryJob(cluster, nodeSerial, "pgbasebackup", initCommand)
 }
 
+// CreatePrimaryJobViaExistingVolume creates a new primary instance in a Pod,
+// adopting the PostgreSQL data directory contained in an existing volume.
+// That volume has already become the PGDATA volume of the instance, and the
+// data directory is verified and moved in place by the instance manager
+func CreatePrimaryJobViaExistingVolume(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
+	existingVolume := cluster.Spec.Bootstrap.ExistingVolume
+
+	initCommand := []string{
+		"/controller/manager",
+		"instance",
+		"adopt",
+		"--source-pg-data", path.Join(pgDataVolumePath, existingVolume.SubPath),
+	}
+
+	if existingVolume.SystemIdentifier != "" {
+		initCommand = append(initCommand, "--system-identifier", existingVolume.SystemIdentifier)
+	}
+
+	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+
+	return createPrimaryJob(cluster, nodeSerial, "adopt", initCommand)
+}
+
 // JoinReplicaInstance create a new PostgreSQL node, copying the contents from another Pod
 func JoinReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
 	initCommand := []string{
@@ -196,7 +253,16 @@ func JoinReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
 
 	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
 
-	return createPrimaryJob(cluster, nodeSerial, "join", initCommand)
+	return createPrimaryJob(cluster, nodeSerial, JobRoleJoin, initCommand)
+}
+
+// JoinReplicaInstanceFromVolumeClone create a new PostgreSQL node whose
+// data volume has been cloned from the one of another standby
+func JoinReplicaInstanceFromVolumeClone(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
+	job := JoinReplicaInstance(cluster, nodeSerial)
+	job.Spec.Template.Spec.Containers[0].Command = append(
+		job.Spec.Template.Spec.Containers[0].Command, "--volume-clone")
+	return job
 }
 
 func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
@@ -215,6 +281,10 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 	instanceName := GetInstanceName(cluster.Name, nodeSerial)
 	jobName := GetJobName(cluster.Name, nodeSerial, role)
 
+	// The Job creating the instance needs to run where its volumes will
+	// be bound, so it follows the settings of the instance group
+	cluster = getInstanceClusterDefinition(cluster, instanceName)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
@@ -235,18 +305,20 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 				Spec: corev1.PodSpec{
 					Hostname:  jobName,
 					Subdomain: cluster.GetServiceAnyName(),
-					InitContainers: []corev1.Container{
-						createBootstrapContainer(cluster),
-					},
+					InitContainers: append(
+						[]corev1.Container{createBootstrapContainer(cluster)},
+						createExtensionContainers(cluster)...),
 					Containers: []corev1.Container{
 						{
 							Name:            role,
 							Image:           cluster.GetImageName(),
 							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
 							Env:             createEnvVarPostgresContainer(cluster, instanceName),
+							EnvFrom:         cluster.Spec.PodTemplate.GetEnvFrom(),
 							Command:         initCommand,
-							VolumeMounts:    createPostgresVolumeMounts(cluster),
-							Resources:       cluster.Spec.Resources,
+							VolumeMounts: append(createPostgresVolumeMounts(cluster),
+								createProjectedVolumeMounts(cluster)...),
+							Resources:       cluster.GetJobResources(),
 							SecurityContext: CreateContainerSecurityContext(),
 						},
 					},
@@ -257,11 +329,19 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 					ServiceAccountName: cluster.Name,
 					RestartPolicy:      corev1.RestartPolicyNever,
 					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
+					SchedulerName:      cluster.Spec.PodTemplate.GetSchedulerName(),
+					PriorityClassName:  cluster.Spec.PodTemplate.GetPriorityClassName(),
 				},
 			},
 		},
 	}
 
+	// The init containers shouldn't raise the requirements of the Job
+	// above the ones requested for it
+	for idx := range job.Spec.Template.Spec.InitContainers {
+		job.Spec.Template.Spec.InitContainers[idx].Resources = cluster.GetJobResources()
+	}
+
 	utils.LabelJobRole(&job.ObjectMeta, role)
 	utils.LabelClusterName(&job.ObjectMeta, cluster.Name)
 	addManagerLoggingOptions(cluster, &job.Spec.Template.Spec.Containers[0])
@@ -269,19 +349,56 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 		utils.AnnotateAppArmor(&job.ObjectMeta, cluster.Annotations)
 	}
 
+	if serviceMesh := cluster.Spec.ServiceMesh; serviceMesh != nil {
+		addServiceMeshSettings(&job.Spec.Template, serviceMesh)
+	}
+
+	if cluster.ShouldInitDBRunPostInitSQLRefs() {
+		addSQLRefsVolumes(job, postInitSQLRefsFolder, cluster.Spec.Bootstrap.InitDB.PostInitSQLRefs)
+	}
+
+	if cluster.ShouldInitDBRunPostInitTemplateSQLRefs() {
+		addSQLRefsVolumes(job, postInitTemplateSQLRefsFolder, cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQLRefs)
+	}
+
 	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
-			cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs,
-		)
-		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumes...)
-		job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
-			job.Spec.Template.Spec.Containers[0].VolumeMounts, volumeMounts...)
+		addSQLRefsVolumes(job, postInitApplicationSQLRefsFolder, cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs)
 	}
 
 	return job
 }
 
+// addSQLRefsVolumes mounts the SQL files of the passed references
+// in the passed folder of the container of the job
+func addSQLRefsVolumes(job *batchv1.Job, folder string, refs *apiv1.PostInitApplicationSQLRefs) {
+	volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(folder, refs)
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumes...)
+	job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
+		job.Spec.Template.Spec.Containers[0].VolumeMounts, volumeMounts...)
+}
+
 // GetJobName returns a string indicating the job name
 func GetJobName(clusterName string, nodeSerial int, role string) string {
 	return fmt.Sprintf("%s-%v-%s", clusterName, nodeSerial, role)
 }
+
+// addServiceMeshSettings configures the Pod of a Job to run inside the
+// passed service mesh. The instance manager will be informed of the mesh,
+// so it can stop the proxy sidecar when done, or the Job would never complete
+func addServiceMeshSettings(template *corev1.PodTemplateSpec, serviceMesh *apiv1.ServiceMeshConfiguration) {
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	for key, value := range servicemesh.GetPodAnnotations(serviceMesh.Type) {
+		template.Annotations[key] = value
+	}
+	for key, value := range serviceMesh.JobAnnotations {
+		template.Annotations[key] = value
+	}
+
+	container := &template.Spec.Containers[0]
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  servicemesh.EnvironmentVariableName,
+		Value: string(serviceMesh.Type),
+	})
+}
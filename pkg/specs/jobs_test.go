@@ -19,9 +19,12 @@ package specs
 import (
 	v1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/servicemesh"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -164,4 +167,193 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("testPostInitApplicationSql"))
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
+
+	It("passes the locale options to initdb", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						DataChecksums:  pointer.Bool(true),
+						Encoding:       "UTF8",
+						LocaleProvider: apiv1.LocaleProviderICU,
+						IcuLocale:      "en-US",
+						IcuRules:       "&V << w <<< W",
+						WalSegmentSize: 64,
+					},
+				},
+			},
+		}
+		Expect(buildInitDBFlags(cluster)).To(Equal([]string{
+			"--initdb-flags",
+			"-k --encoding=UTF8 --locale-provider=icu --icu-locale=en-US " +
+				"'--icu-rules=&V << w <<< W' --wal-segsize=64",
+		}))
+	})
+
+	It("mounts the post-init SQL refs in their own folders", func() {
+		secretRef := apiv1.SecretKeySelector{
+			Key:                  "secretKey",
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "secretName"},
+		}
+		configMapRef := apiv1.ConfigMapKeySelector{
+			Key:                  "configMapKey",
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "configMapName"},
+		}
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						PostInitSQLRefs:            &apiv1.PostInitApplicationSQLRefs{SecretRefs: []apiv1.SecretKeySelector{secretRef}},
+						PostInitTemplateSQLRefs:    &apiv1.PostInitApplicationSQLRefs{ConfigMapRefs: []apiv1.ConfigMapKeySelector{configMapRef}},
+						PostInitApplicationSQLRefs: &apiv1.PostInitApplicationSQLRefs{SecretRefs: []apiv1.SecretKeySelector{secretRef}},
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaInitdb(cluster, 0)
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements(
+			"--post-init-sql-refs-folder", postInitSQLRefsFolder,
+			"--post-init-template-sql-refs-folder", postInitTemplateSQLRefsFolder,
+			"--post-init-application-sql-refs-folder", postInitApplicationSQLRefsFolder,
+		))
+
+		var mountPaths []string
+		for _, volumeMount := range job.Spec.Template.Spec.Containers[0].VolumeMounts {
+			mountPaths = append(mountPaths, volumeMount.MountPath)
+		}
+		Expect(mountPaths).To(ContainElements(
+			postInitSQLRefsFolder+"/0.sql",
+			postInitTemplateSQLRefsFolder+"/0.sql",
+			postInitApplicationSQLRefsFolder+"/0.sql",
+		))
+	})
+})
+
+var _ = Describe("Job created via an existing volume", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				ExistingVolume: &apiv1.BootstrapExistingVolume{
+					PersistentVolumeClaimName: "cluster-example-1",
+					SubPath:                   "data",
+					SystemIdentifier:          "7153416379187089432",
+				},
+			},
+		},
+	}
+
+	It("uses the adopted volume as the PGDATA volume", func() {
+		job := CreatePrimaryJobViaExistingVolume(cluster, 1)
+		Expect(job.Name).To(Equal("cluster-example-1-adopt"))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "pgdata",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "cluster-example-1",
+				},
+			},
+		}))
+		Expect(job.Spec.Template.Spec.Volumes).To(HaveLen(len(createPostgresVolumes(cluster, "cluster-example-1"))))
+	})
+
+	It("passes the expected system identifier to the instance manager", func() {
+		job := CreatePrimaryJobViaExistingVolume(cluster, 1)
+		command := job.Spec.Template.Spec.Containers[0].Command
+		Expect(command).To(ContainElement("adopt"))
+		Expect(command).To(ContainElement("7153416379187089432"))
+		Expect(command).To(ContainElement("/var/lib/postgresql/data/data"))
+	})
+})
+
+var _ = Describe("Job resources", func() {
+	podResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		},
+	}
+	jobResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("4"),
+		},
+	}
+
+	It("uses the resources of the instance Pods by default", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Resources: podResources,
+			},
+		}
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.Containers[0].Resources).To(Equal(podResources))
+		Expect(job.Spec.Template.Spec.InitContainers[0].Resources).To(Equal(podResources))
+	})
+
+	It("uses the resources requested for the Jobs", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Resources:    podResources,
+				JobResources: &jobResources,
+			},
+		}
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.Containers[0].Resources).To(Equal(jobResources))
+		Expect(job.Spec.Template.Spec.InitContainers[0].Resources).To(Equal(jobResources))
+
+		pod := PodWithExistingStorage(cluster, 2)
+		Expect(pod.Spec.Containers[0].Resources).To(Equal(podResources))
+	})
+})
+
+var _ = Describe("Jobs inside a service mesh", func() {
+	It("doesn't configure the Jobs when no service mesh is defined", func() {
+		job := JoinReplicaInstance(apiv1.Cluster{}, 2)
+		Expect(job.Spec.Template.Annotations).To(BeEmpty())
+		Expect(job.Spec.Template.Spec.Containers[0].Env).ToNot(
+			ContainElement(HaveField("Name", servicemesh.EnvironmentVariableName)))
+	})
+
+	It("configures the proxy sidecar and informs the instance manager", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ServiceMesh: &apiv1.ServiceMeshConfiguration{
+					Type: apiv1.ServiceMeshTypeIstio,
+					JobAnnotations: map[string]string{
+						"sidecar.istio.io/inject": "false",
+					},
+				},
+			},
+		}
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Annotations).To(HaveKeyWithValue("sidecar.istio.io/inject", "false"))
+		Expect(job.Spec.Template.Annotations).To(HaveKeyWithValue(
+			"traffic.sidecar.istio.io/excludeInboundPorts", "8000"))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  servicemesh.EnvironmentVariableName,
+			Value: "istio",
+		}))
+
+		pod := PodWithExistingStorage(cluster, 2)
+		Expect(pod.Annotations).To(HaveKey("traffic.sidecar.istio.io/excludeInboundPorts"))
+		Expect(pod.Annotations).ToNot(HaveKey("sidecar.istio.io/inject"))
+	})
+
+	It("hashes the service mesh of the instances", func() {
+		cluster := apiv1.Cluster{}
+		Expect(GetPodTemplateHash(cluster)).To(BeEmpty())
+		cluster.Spec.ServiceMesh = &apiv1.ServiceMeshConfiguration{Type: apiv1.ServiceMeshTypeLinkerd}
+		Expect(GetPodTemplateHash(cluster)).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("Jobs joining a cloned volume", func() {
+	It("only configures the cloned data directory", func() {
+		cluster := apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		job := JoinReplicaInstanceFromVolumeClone(cluster, 3)
+		Expect(job.Name).To(Equal("cluster-example-3-join"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements("join", "--volume-clone"))
+	})
 })
@@ -0,0 +1,147 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// CreateMaintenanceJob creates the Job running a maintenance operation
+// against the primary of the passed cluster. The Job connects as the
+// superuser when the superuser access is enabled, as the owner of the
+// application database otherwise
+func CreateMaintenanceJob(maintenanceJob apiv1.MaintenanceJob, cluster apiv1.Cluster, name string) *batchv1.Job {
+	secretName := cluster.GetSuperuserSecretName()
+	database := maintenanceJob.Spec.Database
+	if !cluster.GetEnableSuperuserAccess() {
+		secretName = cluster.GetApplicationSecretName()
+		if database == "" {
+			database = cluster.GetApplicationDatabaseName()
+		}
+	}
+
+	imageName := maintenanceJob.Spec.ImageName
+	if imageName == "" {
+		imageName = cluster.GetImageName()
+	}
+
+	labels := map[string]string{
+		utils.MaintenanceJobLabelName: maintenanceJob.Name,
+	}
+	backoffLimit := int32(0)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: maintenanceJob.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            string(maintenanceJob.Spec.Operation),
+							Image:           imageName,
+							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							Command:         buildMaintenanceCommand(maintenanceJob.Spec, database),
+							Env:             createMaintenanceEnvVars(maintenanceJob, cluster, secretName),
+							Resources:       cluster.GetJobResources(),
+							SecurityContext: CreateContainerSecurityContext(),
+						},
+					},
+					SecurityContext:    CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
+					ServiceAccountName: cluster.Name,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Tolerations:        cluster.Spec.Affinity.Tolerations,
+					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
+				},
+			},
+		},
+	}
+}
+
+// buildMaintenanceCommand builds the command running a maintenance
+// operation. An empty database means every database
+func buildMaintenanceCommand(spec apiv1.MaintenanceJobSpec, database string) []string {
+	var command []string
+	switch spec.Operation {
+	case apiv1.MaintenanceOperationVacuum:
+		command = []string{"vacuumdb", "--analyze"}
+	case apiv1.MaintenanceOperationAnalyze:
+		command = []string{"vacuumdb", "--analyze-only"}
+	case apiv1.MaintenanceOperationReindex:
+		command = []string{"reindexdb"}
+	case apiv1.MaintenanceOperationRepack:
+		command = []string{"pg_repack", "--no-superuser-check"}
+	}
+	command = append(command, "--echo")
+
+	if database == "" {
+		command = append(command, "--all")
+	} else {
+		command = append(command, "--dbname", database)
+	}
+
+	for _, table := range spec.Tables {
+		command = append(command, "--table", table)
+	}
+
+	if spec.Concurrency > 0 {
+		command = append(command, "--jobs", strconv.Itoa(int(spec.Concurrency)))
+	}
+
+	return command
+}
+
+// createMaintenanceEnvVars creates the environment variables used by
+// the client programs to connect to the primary
+func createMaintenanceEnvVars(
+	maintenanceJob apiv1.MaintenanceJob,
+	cluster apiv1.Cluster,
+	secretName string,
+) []corev1.EnvVar {
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		}
+	}
+
+	return []corev1.EnvVar{
+		{Name: "PGHOST", Value: cluster.GetServiceReadWriteName()},
+		{Name: "PGPORT", Value: strconv.Itoa(postgres.ServerPort)},
+		{Name: "PGSSLMODE", Value: "require"},
+		{Name: "PGAPPNAME", Value: maintenanceJob.Name},
+		{Name: "PGUSER", ValueFrom: secretKeyRef(corev1.BasicAuthUsernameKey)},
+		{Name: "PGPASSWORD", ValueFrom: secretKeyRef(corev1.BasicAuthPasswordKey)},
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maintenance jobs", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:15",
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{Database: "app"},
+			},
+		},
+	}
+
+	It("builds the command of every operation", func() {
+		Expect(buildMaintenanceCommand(apiv1.MaintenanceJobSpec{Operation: apiv1.MaintenanceOperationVacuum}, "")).
+			To(Equal([]string{"vacuumdb", "--analyze", "--echo", "--all"}))
+		Expect(buildMaintenanceCommand(apiv1.MaintenanceJobSpec{
+			Operation:   apiv1.MaintenanceOperationAnalyze,
+			Concurrency: 4,
+		}, "app")).
+			To(Equal([]string{"vacuumdb", "--analyze-only", "--echo", "--dbname", "app", "--jobs", "4"}))
+		Expect(buildMaintenanceCommand(apiv1.MaintenanceJobSpec{Operation: apiv1.MaintenanceOperationReindex}, "app")).
+			To(Equal([]string{"reindexdb", "--echo", "--dbname", "app"}))
+		Expect(buildMaintenanceCommand(apiv1.MaintenanceJobSpec{
+			Operation: apiv1.MaintenanceOperationRepack,
+			Tables:    []string{"public.orders"},
+		}, "app")).
+			To(Equal([]string{
+				"pg_repack", "--no-superuser-check", "--echo", "--dbname", "app", "--table", "public.orders",
+			}))
+	})
+
+	It("connects as the superuser to every database", func() {
+		maintenanceJob := apiv1.MaintenanceJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-vacuum", Namespace: "default"},
+			Spec:       apiv1.MaintenanceJobSpec{Operation: apiv1.MaintenanceOperationVacuum},
+		}
+
+		job := CreateMaintenanceJob(maintenanceJob, cluster, "nightly-vacuum-1")
+		Expect(job.Labels).To(HaveKeyWithValue(utils.MaintenanceJobLabelName, "nightly-vacuum"))
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal(cluster.Spec.ImageName))
+		Expect(container.Command).To(ContainElement("--all"))
+		Expect(container.Env[4].ValueFrom.SecretKeyRef.Name).To(Equal(cluster.GetSuperuserSecretName()))
+	})
+
+	It("connects as the owner of the application database without superuser access", func() {
+		clusterWithoutSuperuser := cluster.DeepCopy()
+		clusterWithoutSuperuser.Spec.EnableSuperuserAccess = pointer.Bool(false)
+		maintenanceJob := apiv1.MaintenanceJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "weekly-repack", Namespace: "default"},
+			Spec: apiv1.MaintenanceJobSpec{
+				Operation: apiv1.MaintenanceOperationRepack,
+				ImageName: "registry.example.com/postgresql-pg-repack:15",
+			},
+		}
+
+		job := CreateMaintenanceJob(maintenanceJob, *clusterWithoutSuperuser, "weekly-repack-1")
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("registry.example.com/postgresql-pg-repack:15"))
+		Expect(container.Command).To(ContainElements("--dbname", "app"))
+		Expect(container.Env[4].ValueFrom.SecretKeyRef.Name).
+			To(Equal(clusterWithoutSuperuser.GetApplicationSecretName()))
+	})
+})
@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// OperatorPodLabelName is the name of the label used to select the
+	// Pods of the operator
+	OperatorPodLabelName = "app.kubernetes.io/name"
+
+	// OperatorPodLabelValue is the value of the label used to select the
+	// Pods of the operator
+	OperatorPodLabelValue = "cloudnative-pg"
+
+	// namespaceNameLabelName is the label that Kubernetes sets on every
+	// namespace, containing its name
+	namespaceNameLabelName = "kubernetes.io/metadata.name"
+)
+
+// CreateNetworkPolicy creates the NetworkPolicy restricting the traffic
+// directed to the instances of the cluster to:
+//
+//   - the replication traffic between the instances
+//   - the requests coming from the operator to the instance manager
+//   - the PostgreSQL connections coming from the namespace of the cluster
+//     and from the allowed namespaces
+func CreateNetworkPolicy(cluster *apiv1.Cluster, operatorNamespace string) *networkingv1.NetworkPolicy {
+	meta := metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+	utils.LabelClusterName(&meta, cluster.Name)
+
+	instancesSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			utils.ClusterLabelName: cluster.Name,
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		},
+	}
+
+	postgresPort := networkPolicyPort(postgres.ServerPort)
+	statusPort := networkPolicyPort(url.StatusPort)
+
+	applicationPeers := []networkingv1.NetworkPolicyPeer{
+		{
+			PodSelector: &metav1.LabelSelector{},
+		},
+	}
+	if cluster.Spec.NetworkPolicy != nil {
+		for _, namespace := range cluster.Spec.NetworkPolicy.AllowedNamespaces {
+			applicationPeers = append(applicationPeers, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: namespaceSelector(namespace),
+			})
+		}
+	}
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							utils.ClusterLabelName: cluster.Name,
+						},
+					},
+				},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{postgresPort, statusPort},
+		},
+		{
+			From:  applicationPeers,
+			Ports: []networkingv1.NetworkPolicyPort{postgresPort},
+		},
+	}
+
+	if operatorNamespace != "" {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: namespaceSelector(operatorNamespace),
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							OperatorPodLabelName: OperatorPodLabelValue,
+						},
+					},
+				},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{statusPort},
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: meta,
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: instancesSelector,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+}
+
+// networkPolicyPort creates a NetworkPolicyPort for the passed TCP port
+func networkPolicyPort(port int) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	portNumber := intstr.FromInt(port)
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &portNumber,
+	}
+}
+
+// namespaceSelector creates a selector matching the namespace
+// having the passed name
+func namespaceSelector(namespace string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			namespaceNameLabelName: namespace,
+		},
+	}
+}
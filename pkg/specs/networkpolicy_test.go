@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NetworkPolicy test", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test",
+		},
+		Spec: apiv1.ClusterSpec{
+			NetworkPolicy: &apiv1.NetworkPolicyConfiguration{
+				Enabled:           true,
+				AllowedNamespaces: []string{"app"},
+			},
+		},
+	}
+
+	It("should select only the instances of the cluster", func() {
+		policy := CreateNetworkPolicy(&cluster, "cnpg-system")
+		Expect(policy.Name).To(Equal("test"))
+		Expect(policy.Namespace).To(Equal("test-namespace"))
+		Expect(policy.Labels[utils.ClusterLabelName]).To(Equal("test"))
+		Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{
+			utils.ClusterLabelName: "test",
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		}))
+		Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+	})
+
+	It("should allow the operator and the allowed namespaces", func() {
+		policy := CreateNetworkPolicy(&cluster, "cnpg-system")
+		Expect(policy.Spec.Ingress).To(HaveLen(3))
+
+		applicationRule := policy.Spec.Ingress[1]
+		Expect(applicationRule.Ports).To(HaveLen(1))
+		Expect(applicationRule.Ports[0].Port.IntVal).To(BeEquivalentTo(5432))
+		Expect(applicationRule.From).To(HaveLen(2))
+		Expect(applicationRule.From[1].NamespaceSelector.MatchLabels).To(
+			HaveKeyWithValue("kubernetes.io/metadata.name", "app"))
+
+		operatorRule := policy.Spec.Ingress[2]
+		Expect(operatorRule.Ports).To(HaveLen(1))
+		Expect(operatorRule.Ports[0].Port.IntVal).To(BeEquivalentTo(8000))
+		Expect(operatorRule.From[0].NamespaceSelector.MatchLabels).To(
+			HaveKeyWithValue("kubernetes.io/metadata.name", "cnpg-system"))
+	})
+
+	It("should not allow any operator when its namespace is unknown", func() {
+		policy := CreateNetworkPolicy(&cluster, "")
+		Expect(policy.Spec.Ingress).To(HaveLen(2))
+	})
+})
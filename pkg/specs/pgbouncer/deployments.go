@@ -31,14 +31,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/podspec"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 )
 
 const (
-	// PgbouncerPoolerSpecHash is the annotation added to the deployment to tell
-	// the hash of the Pooler Specification
-	PgbouncerPoolerSpecHash = specs.MetadataNamespace + "/poolerSpecHash"
-
 	// PgbouncerNameLabel is the label of the pgbouncer pod used by default
 	PgbouncerNameLabel = specs.MetadataNamespace + "/poolerName"
 
@@ -51,11 +46,6 @@ const (
 func Deployment(pooler *apiv1.Pooler,
 	cluster *apiv1.Cluster,
 ) (*appsv1.Deployment, error) {
-	poolerHash, err := hash.ComputeHash(pooler.Spec)
-	if err != nil {
-		return nil, err
-	}
-
 	podTemplate := podspec.NewFrom(pooler.Spec.Template).
 		WithLabel(PgbouncerNameLabel, pooler.Name).
 		WithVolume(&corev1.Volume{
@@ -128,9 +118,6 @@ func Deployment(pooler *apiv1.Pooler,
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pooler.Name,
 			Namespace: pooler.Namespace,
-			Annotations: map[string]string{
-				PgbouncerPoolerSpecHash: poolerHash,
-			},
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &pooler.Spec.Instances,
@@ -19,7 +19,11 @@ limitations under the License.
 package specs
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"path"
+	"reflect"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +33,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/servicemesh"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -48,6 +53,10 @@ const (
 	// latest required restart time
 	ClusterReloadAnnotationName = MetadataNamespace + "/reloadedAt"
 
+	// PodTemplateHashAnnotationName is the name of the annotation containing
+	// the hash of the Pod template used to create an instance Pod
+	PodTemplateHashAnnotationName = MetadataNamespace + "/podTemplateHash"
+
 	// ClusterRoleLabelName label is applied to Pods to mark primary ones
 	ClusterRoleLabelName = "role"
 
@@ -76,6 +85,18 @@ const (
 	// controller inside the Pod file system
 	BootstrapControllerContainerName = "bootstrap-controller"
 
+	// LogShippingVolumeName is the name of the volume, shared with the
+	// sidecar containers, where the instance manager copies its log stream
+	LogShippingVolumeName = "logs"
+
+	// DefaultContainerAnnotationName is the annotation selecting the
+	// container used by kubectl when none is specified
+	DefaultContainerAnnotationName = "kubectl.kubernetes.io/default-container"
+
+	// ExtensionContainerNamePrefix is the prefix of the names of the containers
+	// copying the files of the extension images inside the Pod file system
+	ExtensionContainerNamePrefix = "extension-"
+
 	// PgDataPath is the path to PGDATA variable
 	PgDataPath = "/var/lib/postgresql/data/pgdata"
 
@@ -85,8 +106,21 @@ const (
 	// PgWalArchiveStatusPath is the path to the archive status directory
 	PgWalArchiveStatusPath = PgWalPath + "/archive_status"
 
+	// StartupProbePeriod is the period set for the postgres instance startup probe
+	StartupProbePeriod = 10
+
 	// ReadinessProbePeriod is the period set for the postgres instance readiness probe
 	ReadinessProbePeriod = 10
+
+	// DevelopmentReadinessProbePeriod is the period set for the readiness
+	// probe of the instances of development clusters, which are expected
+	// to be ready as soon as possible after their creation
+	DevelopmentReadinessProbePeriod = 2
+
+	// DevelopmentLivenessProbeFailureThreshold is the number of failures
+	// of the liveness probe after which the instances of development clusters
+	// are restarted, tolerating the slow nodes of local environments
+	DevelopmentLivenessProbeFailureThreshold = 10
 )
 
 func createEnvVarPostgresContainer(cluster apiv1.Cluster, podName string) []corev1.EnvVar {
@@ -117,9 +151,28 @@ func createEnvVarPostgresContainer(cluster apiv1.Cluster, podName string) []core
 		},
 	}
 
+	if cluster.GetLDAPCACertificate() != nil {
+		envVar = append(envVar, corev1.EnvVar{
+			Name:  "LDAPTLS_CACERT",
+			Value: ldapServerCAPath + "/" + ldapServerCAFileName,
+		})
+	}
+
+	envVar = append(envVar, cluster.Spec.PodTemplate.GetEnv()...)
+
 	return envVar
 }
 
+// getStartupProbeFailureThreshold gets the number of failures of the
+// startup probe, run every passed period, which give the instance the
+// passed number of seconds to start up
+func getStartupProbeFailureThreshold(startDelay, period int32) int32 {
+	if startDelay <= period {
+		return 1
+	}
+	return (startDelay + period - 1) / period
+}
+
 // createPostgresContainers create the PostgreSQL containers that are
 // used for every instance
 func createPostgresContainers(
@@ -132,7 +185,19 @@ func createPostgresContainers(
 			Image:           cluster.GetImageName(),
 			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
 			Env:             createEnvVarPostgresContainer(cluster, podName),
-			VolumeMounts:    createPostgresVolumeMounts(cluster),
+			EnvFrom:         cluster.Spec.PodTemplate.GetEnvFrom(),
+			VolumeMounts:    append(createPostgresVolumeMounts(cluster), createProjectedVolumeMounts(cluster)...),
+			StartupProbe: &corev1.Probe{
+				TimeoutSeconds:   5,
+				PeriodSeconds:    StartupProbePeriod,
+				FailureThreshold: getStartupProbeFailureThreshold(cluster.GetMaxStartDelay(), StartupProbePeriod),
+				ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: url.PathStartup,
+						Port: intstr.FromInt(url.StatusPort),
+					},
+				},
+			},
 			ReadinessProbe: &corev1.Probe{
 				TimeoutSeconds: 5,
 				PeriodSeconds:  ReadinessProbePeriod,
@@ -143,14 +208,8 @@ func createPostgresContainers(
 					},
 				},
 			},
-			// From K8s 1.17 and newer, startup probes will be available for
-			// all users and not just protected from feature gates. For now
-			// let's use the LivenessProbe. When we will drop support for K8s
-			// 1.16, we'll configure a StartupProbe and this will lead to a
-			// better LivenessProbe (without InitialDelaySeconds).
 			LivenessProbe: &corev1.Probe{
-				InitialDelaySeconds: cluster.GetMaxStartDelay(),
-				TimeoutSeconds:      5,
+				TimeoutSeconds: 5,
 				ProbeHandler: corev1.ProbeHandler{
 					HTTPGet: &corev1.HTTPGetAction{
 						Path: url.PathHealth,
@@ -186,6 +245,27 @@ func createPostgresContainers(
 	}
 
 	addManagerLoggingOptions(cluster, &containers[0])
+	if len(cluster.Spec.PodTemplate.GetSidecars()) > 0 {
+		containers[0].Command = append(containers[0].Command, fmt.Sprintf("--log-copy-destination=%s",
+			path.Join(postgres.LogShippingPath, postgres.LogShippingFileName)))
+	}
+
+	if cluster.IsDevelopmentProfile() {
+		containers[0].ReadinessProbe.PeriodSeconds = DevelopmentReadinessProbePeriod
+		containers[0].LivenessProbe.FailureThreshold = DevelopmentLivenessProbeFailureThreshold
+	}
+
+	// Unless explicitly set, the failure threshold of the startup probe
+	// follows its period to keep giving the instance startDelay seconds
+	if startupProbe := cluster.Spec.Probes.GetStartup(); startupProbe != nil {
+		startupProbe.ApplyTo(containers[0].StartupProbe)
+		if startupProbe.FailureThreshold == 0 {
+			containers[0].StartupProbe.FailureThreshold = getStartupProbeFailureThreshold(
+				cluster.GetMaxStartDelay(), containers[0].StartupProbe.PeriodSeconds)
+		}
+	}
+	cluster.Spec.Probes.GetReadiness().ApplyTo(containers[0].ReadinessProbe)
+	cluster.Spec.Probes.GetLiveness().ApplyTo(containers[0].LivenessProbe)
 
 	return containers
 }
@@ -289,9 +369,19 @@ func CreatePodSecurityContext(user, group int64) *corev1.PodSecurityContext {
 	}
 }
 
+// getInstanceClusterDefinition gets the cluster definition as seen by the
+// passed instance, where the resources and the node selector of its group
+// and of its overrides replace the ones of the cluster
+func getInstanceClusterDefinition(cluster apiv1.Cluster, instanceName string) apiv1.Cluster {
+	cluster.Spec.Resources = cluster.GetInstanceResources(instanceName)
+	cluster.Spec.Affinity.NodeSelector = cluster.GetInstanceNodeSelector(instanceName)
+	return cluster
+}
+
 // PodWithExistingStorage create a new instance with an existing storage
 func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 	podName := GetInstanceName(cluster.Name, nodeSerial)
+	cluster = getInstanceClusterDefinition(cluster, podName)
 	gracePeriod := int64(cluster.GetMaxStopDelay())
 
 	pod := &corev1.Pod{
@@ -311,10 +401,12 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 		Spec: corev1.PodSpec{
 			Hostname:  podName,
 			Subdomain: cluster.GetServiceAnyName(),
-			InitContainers: []corev1.Container{
-				createBootstrapContainer(cluster),
-			},
-			Containers:                    createPostgresContainers(cluster, podName),
+			InitContainers: append(
+				[]corev1.Container{createBootstrapContainer(cluster)},
+				createExtensionContainers(cluster)...),
+			Containers: append(
+				createPostgresContainers(cluster, podName),
+				createSidecarContainers(cluster)...),
 			Volumes:                       createPostgresVolumes(cluster, podName),
 			SecurityContext:               CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
 			Affinity:                      CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
@@ -322,15 +414,123 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 			ServiceAccountName:            cluster.Name,
 			NodeSelector:                  cluster.Spec.Affinity.NodeSelector,
 			TerminationGracePeriodSeconds: &gracePeriod,
+			SchedulerName:                 cluster.Spec.PodTemplate.GetSchedulerName(),
+			PriorityClassName:             cluster.Spec.PodTemplate.GetPriorityClassName(),
 		},
 	}
 
+	if group := cluster.GetInstanceGroup(podName); group != nil {
+		pod.Labels[utils.InstanceGroupLabelName] = group.Name
+	}
+
+	if podTemplateHash := GetPodTemplateHash(cluster); podTemplateHash != "" {
+		pod.Annotations[PodTemplateHashAnnotationName] = podTemplateHash
+	}
+
+	if len(cluster.Spec.PodTemplate.GetSidecars()) > 0 {
+		pod.Annotations[DefaultContainerAnnotationName] = PostgresContainerName
+	}
+
+	if serviceMesh := cluster.Spec.ServiceMesh; serviceMesh != nil {
+		for key, value := range servicemesh.GetPodAnnotations(serviceMesh.Type) {
+			pod.Annotations[key] = value
+		}
+	}
+
 	if utils.IsAnnotationAppArmorPresent(cluster.Annotations) {
 		utils.AnnotateAppArmor(&pod.ObjectMeta, cluster.Annotations)
 	}
 	return pod
 }
 
+// PinPodToHostname makes the passed Pod schedulable only on the node
+// having the passed hostname, which is required when its volumes are
+// local to that node
+func PinPodToHostname(pod *corev1.Pod, hostname string) {
+	nodeSelector := make(map[string]string, len(pod.Spec.NodeSelector)+1)
+	for key, value := range pod.Spec.NodeSelector {
+		nodeSelector[key] = value
+	}
+	nodeSelector[corev1.LabelHostname] = hostname
+	pod.Spec.NodeSelector = nodeSelector
+}
+
+// podTemplateHashContent is the content of the Pod template hash. The
+// projected volume template, the service mesh, the credentials files and
+// the probes are omitted when not defined, to keep the hash of the Pods
+// created before their introduction
+type podTemplateHashContent struct {
+	apiv1.InstancePodTemplate
+	ProjectedVolumeTemplate *corev1.ProjectedVolumeSource        `json:"projectedVolumeTemplate,omitempty"`
+	ServiceMeshType         apiv1.ServiceMeshType                `json:"serviceMeshType,omitempty"`
+	CredentialsFiles        *apiv1.CredentialsFilesConfiguration `json:"credentialsFiles,omitempty"`
+	Probes                  *apiv1.ProbesConfiguration           `json:"probes,omitempty"`
+}
+
+// GetPodTemplateHash gets the hash of the parts of the Pod template that
+// can't be changed without recreating the instance Pods. An empty string
+// is returned when no such customization is defined
+func GetPodTemplateHash(cluster apiv1.Cluster) string {
+	podSpecTemplate := podTemplateHashContent{
+		ProjectedVolumeTemplate: cluster.Spec.ProjectedVolumeTemplate,
+		CredentialsFiles:        cluster.Spec.CredentialsFiles,
+	}
+	if podTemplate := cluster.Spec.PodTemplate; podTemplate != nil {
+		podSpecTemplate.InstancePodTemplate = apiv1.InstancePodTemplate{
+			Env:               podTemplate.Env,
+			EnvFrom:           podTemplate.EnvFrom,
+			ProjectedVolumes:  podTemplate.ProjectedVolumes,
+			CSIVolumes:        podTemplate.CSIVolumes,
+			SchedulerName:     podTemplate.SchedulerName,
+			PriorityClassName: podTemplate.PriorityClassName,
+			Sidecars:          podTemplate.Sidecars,
+		}
+	}
+	if serviceMesh := cluster.Spec.ServiceMesh; serviceMesh != nil {
+		podSpecTemplate.ServiceMeshType = serviceMesh.Type
+	}
+	if probes := cluster.Spec.Probes; probes != nil {
+		podSpecTemplate.Probes = getProbesWithoutChecks(*probes)
+	}
+	if reflect.DeepEqual(podSpecTemplate, podTemplateHashContent{}) {
+		return ""
+	}
+
+	// Marshalling a struct can't fail
+	content, _ := json.Marshal(podSpecTemplate)
+	return fmt.Sprintf("%x", sha256.Sum256(content))
+}
+
+// getProbesWithoutChecks gets a copy of the passed probes configuration
+// without the additional checks and the startup strategy, which are read
+// by the instance manager from the cluster definition and don't need the
+// Pods to be recreated
+func getProbesWithoutChecks(probes apiv1.ProbesConfiguration) *apiv1.ProbesConfiguration {
+	withoutChecks := func(probe *apiv1.ProbeConfiguration) *apiv1.ProbeConfiguration {
+		if probe == nil {
+			return nil
+		}
+		result := *probe
+		result.Checks = nil
+		if reflect.DeepEqual(result, apiv1.ProbeConfiguration{}) {
+			return nil
+		}
+		return &result
+	}
+
+	result := apiv1.ProbesConfiguration{
+		Readiness: withoutChecks(probes.Readiness),
+		Liveness:  withoutChecks(probes.Liveness),
+	}
+	if startup := withoutChecks(probes.GetStartup()); startup != nil {
+		result.Startup = &apiv1.StartupProbeConfiguration{ProbeConfiguration: *startup}
+	}
+	if reflect.DeepEqual(result, apiv1.ProbesConfiguration{}) {
+		return nil
+	}
+	return &result
+}
+
 // GetInstanceName returns a string indicating the instance name
 func GetInstanceName(clusterName string, nodeSerial int) string {
 	return fmt.Sprintf("%s-%v", clusterName, nodeSerial)
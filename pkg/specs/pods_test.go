@@ -18,9 +18,12 @@ package specs
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -247,3 +250,395 @@ var _ = Describe("Create affinity section", func() {
 		})
 	})
 })
+
+var _ = Describe("Instance Pod template", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: v1.ClusterSpec{
+			PodTemplate: &v1.InstancePodTemplate{
+				Env: []corev1.EnvVar{
+					{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+				},
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "settings"},
+					}},
+				},
+				ProjectedVolumes: []v1.InstanceProjectedVolume{
+					{
+						Name:      "ldap-ca",
+						MountPath: "/etc/ldap-ca",
+						ProjectedVolumeSource: corev1.ProjectedVolumeSource{
+							Sources: []corev1.VolumeProjection{
+								{ConfigMap: &corev1.ConfigMapProjection{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "ldap-ca"},
+								}},
+							},
+						},
+					},
+				},
+				CSIVolumes: []v1.InstanceCSIVolume{
+					{
+						Name:      "secrets-store",
+						MountPath: "/etc/secrets-store",
+						CSIVolumeSource: corev1.CSIVolumeSource{
+							Driver: "secrets-store.csi.k8s.io",
+						},
+					},
+				},
+				SchedulerName:     "custom-scheduler",
+				PriorityClassName: "high-priority",
+			},
+		},
+	}
+
+	It("customizes the instance Pods", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.SchedulerName).To(Equal("custom-scheduler"))
+		Expect(pod.Spec.PriorityClassName).To(Equal("high-priority"))
+		Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Name", "ldap-ca")))
+		Expect(pod.Annotations).To(HaveKeyWithValue(PodTemplateHashAnnotationName, GetPodTemplateHash(cluster)))
+
+		container := pod.Spec.Containers[0]
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}))
+		Expect(container.EnvFrom).To(Equal(cluster.Spec.PodTemplate.EnvFrom))
+		Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "ldap-ca",
+			MountPath: "/etc/ldap-ca",
+			ReadOnly:  true,
+		}))
+		Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "secrets-store",
+			MountPath: "/etc/secrets-store",
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(HaveField("VolumeSource.CSI.ReadOnly", pointerToBool(true))))
+	})
+
+	It("doesn't mount the secrets replaced by credentials files", func() {
+		clusterWithFiles := cluster.DeepCopy()
+		clusterWithFiles.Spec.CredentialsFiles = &v1.CredentialsFilesConfiguration{
+			SuperuserPasswordFile: "/etc/secrets-store/postgres",
+		}
+		pod := PodWithExistingStorage(*clusterWithFiles, 1)
+		Expect(pod.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "superuser-secret")))
+		Expect(GetPodTemplateHash(*clusterWithFiles)).ToNot(Equal(GetPodTemplateHash(cluster)))
+	})
+
+	It("customizes the Pods of the Jobs creating the instances", func() {
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.SchedulerName).To(Equal("custom-scheduler"))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "ldap-ca")))
+		Expect(job.Spec.Template.Spec.Containers[0].EnvFrom).To(Equal(cluster.Spec.PodTemplate.EnvFrom))
+	})
+
+	It("doesn't hash the Pod template when only the metadata is customized", func() {
+		clusterWithMetadata := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				PodTemplate: &v1.InstancePodTemplate{
+					Metadata: v1.EmbeddedObjectMetadata{
+						Labels: map[string]string{"team": "dba"},
+					},
+				},
+			},
+		}
+		Expect(GetPodTemplateHash(clusterWithMetadata)).To(BeEmpty())
+		Expect(GetPodTemplateHash(v1.Cluster{})).To(BeEmpty())
+		Expect(GetPodTemplateHash(cluster)).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("Sidecar containers", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: v1.ClusterSpec{
+			PodTemplate: &v1.InstancePodTemplate{
+				Sidecars: []corev1.Container{
+					{Name: "fluent-bit", Image: "fluent/fluent-bit:2.0"},
+				},
+			},
+		},
+	}
+	logsMount := corev1.VolumeMount{Name: LogShippingVolumeName, MountPath: "/logs"}
+
+	It("adds the sidecars to the instance Pods, sharing the logs with them", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(2))
+		Expect(pod.Spec.Containers[0].Name).To(Equal(PostgresContainerName))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(logsMount))
+		Expect(pod.Spec.Containers[0].Command).To(ContainElement("--log-copy-destination=/logs/instance.json"))
+		Expect(pod.Spec.Containers[1].Name).To(Equal("fluent-bit"))
+		Expect(pod.Spec.Containers[1].VolumeMounts).To(ConsistOf(logsMount))
+		Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Name", LogShippingVolumeName)))
+		Expect(pod.Annotations).To(HaveKeyWithValue(DefaultContainerAnnotationName, PostgresContainerName))
+
+		Expect(cluster.Spec.PodTemplate.Sidecars[0].VolumeMounts).To(BeEmpty())
+	})
+
+	It("doesn't add the sidecars to the Pods of the Jobs", func() {
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+	})
+
+	It("doesn't change the instance Pods when no sidecar is declared", func() {
+		pod := PodWithExistingStorage(v1.Cluster{}, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		Expect(pod.Spec.Containers[0].VolumeMounts).ToNot(ContainElement(logsMount))
+		Expect(pod.Annotations).ToNot(HaveKey(DefaultContainerAnnotationName))
+	})
+
+	It("recreates the instance Pods when the sidecars change", func() {
+		changedCluster := cluster.DeepCopy()
+		changedCluster.Spec.PodTemplate.Sidecars[0].Image = "fluent/fluent-bit:2.1"
+		Expect(GetPodTemplateHash(cluster)).ToNot(BeEmpty())
+		Expect(GetPodTemplateHash(*changedCluster)).ToNot(Equal(GetPodTemplateHash(cluster)))
+	})
+})
+
+var _ = Describe("Development profile", func() {
+	It("uses probes fit for ephemeral environments", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: v1.ClusterSpec{
+				Profile: v1.ClusterProfileDevelopment,
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		container := pod.Spec.Containers[0]
+		Expect(container.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(DevelopmentReadinessProbePeriod))
+		Expect(container.LivenessProbe.FailureThreshold).To(BeEquivalentTo(DevelopmentLivenessProbeFailureThreshold))
+
+		cluster.Spec.Profile = ""
+		pod = PodWithExistingStorage(cluster, 1)
+		container = pod.Spec.Containers[0]
+		Expect(container.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(ReadinessProbePeriod))
+	})
+})
+
+var _ = Describe("Probes configuration", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: v1.ClusterSpec{
+			MaxStartDelay: 3600,
+		},
+	}
+
+	It("gives the instances startDelay seconds to start up", func() {
+		container := PodWithExistingStorage(cluster, 1).Spec.Containers[0]
+		Expect(container.StartupProbe.HTTPGet.Path).To(Equal(url.PathStartup))
+		Expect(container.StartupProbe.PeriodSeconds * container.StartupProbe.FailureThreshold).
+			To(BeEquivalentTo(3600))
+		Expect(container.LivenessProbe.InitialDelaySeconds).To(BeZero())
+		Expect(getStartupProbeFailureThreshold(5, 10)).To(BeEquivalentTo(1))
+		Expect(getStartupProbeFailureThreshold(35, 10)).To(BeEquivalentTo(4))
+	})
+
+	It("overrides the parameters set in the cluster", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Probes = &v1.ProbesConfiguration{
+			Startup: &v1.StartupProbeConfiguration{
+				ProbeConfiguration: v1.ProbeConfiguration{FailureThreshold: 720},
+			},
+			Liveness: &v1.ProbeConfiguration{PeriodSeconds: 30, TimeoutSeconds: 10},
+		}
+		container := PodWithExistingStorage(*cluster, 1).Spec.Containers[0]
+		Expect(container.StartupProbe.FailureThreshold).To(BeEquivalentTo(720))
+		Expect(container.LivenessProbe.PeriodSeconds).To(BeEquivalentTo(30))
+		Expect(container.LivenessProbe.TimeoutSeconds).To(BeEquivalentTo(10))
+		Expect(container.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(ReadinessProbePeriod))
+	})
+
+	It("keeps giving the instances startDelay seconds when the startup period changes", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Probes = &v1.ProbesConfiguration{
+			Startup: &v1.StartupProbeConfiguration{
+				ProbeConfiguration: v1.ProbeConfiguration{PeriodSeconds: 30},
+				Strategy:           v1.StartupProbeStrategyStreaming,
+			},
+		}
+		container := PodWithExistingStorage(*cluster, 1).Spec.Containers[0]
+		Expect(container.StartupProbe.PeriodSeconds).To(BeEquivalentTo(30))
+		Expect(container.StartupProbe.FailureThreshold).To(BeEquivalentTo(120))
+	})
+
+	It("doesn't hash the additional checks and the startup strategy", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Probes = &v1.ProbesConfiguration{
+			Startup: &v1.StartupProbeConfiguration{Strategy: v1.StartupProbeStrategyPgIsReady},
+		}
+		Expect(GetPodTemplateHash(*cluster)).To(BeEmpty())
+	})
+
+	It("doesn't hash the additional checks", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Probes = &v1.ProbesConfiguration{
+			Readiness: &v1.ProbeConfiguration{Checks: []v1.HealthCheck{v1.HealthCheckArchiver}},
+		}
+		Expect(GetPodTemplateHash(*cluster)).To(BeEmpty())
+
+		cluster.Spec.Probes.Readiness.PeriodSeconds = 5
+		Expect(GetPodTemplateHash(*cluster)).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("Projected volume template", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: v1.ClusterSpec{
+			ProjectedVolumeTemplate: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{Secret: &corev1.SecretProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "keytab"},
+					}},
+				},
+			},
+		},
+	}
+
+	It("mounts the projected volume in the instance Pods", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "projected",
+			VolumeSource: corev1.VolumeSource{
+				Projected: cluster.Spec.ProjectedVolumeTemplate,
+			},
+		}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "projected",
+			MountPath: "/projected",
+			ReadOnly:  true,
+		}))
+	})
+
+	It("mounts the projected volume in the Jobs creating the instances", func() {
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "projected")))
+	})
+
+	It("hashes the projected volume template", func() {
+		Expect(GetPodTemplateHash(cluster)).ToNot(BeEmpty())
+
+		clusterWithPodTemplate := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				PodTemplate: &v1.InstancePodTemplate{SchedulerName: "custom-scheduler"},
+			},
+		}
+		hash := GetPodTemplateHash(clusterWithPodTemplate)
+		clusterWithPodTemplate.Spec.ProjectedVolumeTemplate = cluster.Spec.ProjectedVolumeTemplate
+		Expect(GetPodTemplateHash(clusterWithPodTemplate)).ToNot(Equal(hash))
+	})
+})
+
+var _ = Describe("instance groups", func() {
+	reportingResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: v1.ClusterSpec{
+			Instances: 3,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+			Affinity: v1.AffinityConfiguration{
+				NodeSelector: map[string]string{"workload": "postgres"},
+			},
+			InstanceGroups: []v1.InstanceGroup{
+				{
+					Name:                 "reporting",
+					Instances:            1,
+					Resources:            &reportingResources,
+					NodeSelector:         map[string]string{"disk": "hdd"},
+					ExcludedFromFailover: true,
+				},
+			},
+		},
+		Status: v1.ClusterStatus{
+			InstanceGroups: map[string]string{"cluster-example-3": "reporting"},
+		},
+	}
+
+	It("applies the settings of the group to its instances", func() {
+		pod := PodWithExistingStorage(cluster, 3)
+		Expect(pod.Labels).To(HaveKeyWithValue(utils.InstanceGroupLabelName, "reporting"))
+		Expect(pod.Spec.NodeSelector).To(Equal(map[string]string{"workload": "postgres", "disk": "hdd"}))
+		Expect(pod.Spec.Containers[0].Resources).To(Equal(reportingResources))
+		Expect(cluster.Spec.Affinity.NodeSelector).To(HaveLen(1))
+	})
+
+	It("applies the settings of the group to the Jobs creating its instances", func() {
+		job := JoinReplicaInstance(cluster, 3)
+		Expect(job.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("disk", "hdd"))
+		Expect(job.Spec.Template.Spec.Containers[0].Resources).To(Equal(reportingResources))
+	})
+
+	It("uses the cluster settings for the instances of the default group", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Labels).ToNot(HaveKey(utils.InstanceGroupLabelName))
+		Expect(pod.Spec.NodeSelector).To(Equal(cluster.Spec.Affinity.NodeSelector))
+		Expect(pod.Spec.Containers[0].Resources).To(Equal(cluster.Spec.Resources))
+	})
+})
+
+var _ = Describe("instance overrides", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+			Annotations: map[string]string{
+				utils.InstanceOverridesAnnotationName: `{"2": {"resources": {"limits": {"cpu": "4"}},` +
+					` "nodeSelector": {"cpu": "fast"}}}`,
+			},
+		},
+		Spec: v1.ClusterSpec{Instances: 3},
+	}
+
+	It("applies the overrides to the Pod of the instance", func() {
+		pod := PodWithExistingStorage(cluster, 2)
+		Expect(pod.Spec.NodeSelector).To(HaveKeyWithValue("cpu", "fast"))
+		Expect(pod.Spec.Containers[0].Resources.Limits.Cpu().String()).To(Equal("4"))
+
+		pod = PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.NodeSelector).To(BeEmpty())
+		Expect(pod.Spec.Containers[0].Resources.Limits).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Pinning a Pod to a node", func() {
+	It("adds the hostname to the node selector without changing the cluster", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: v1.ClusterSpec{
+				Instances: 1,
+				Affinity: v1.AffinityConfiguration{
+					NodeSelector: map[string]string{"disk": "local"},
+				},
+			},
+		}
+
+		pod := PodWithExistingStorage(cluster, 1)
+		PinPodToHostname(pod, "worker-1")
+		Expect(pod.Spec.NodeSelector).To(HaveKeyWithValue("disk", "local"))
+		Expect(pod.Spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelHostname, "worker-1"))
+		Expect(cluster.Spec.Affinity.NodeSelector).ToNot(HaveKey(corev1.LabelHostname))
+	})
+})
@@ -104,10 +104,7 @@ func CreatePVC(
 		storageConfiguration.PersistentVolumeClaimTemplate.DeepCopyInto(&result.Spec)
 	}
 
-	// If the customer specified a storage class, let's use it
-	if storageConfiguration.StorageClass != nil {
-		result.Spec.StorageClassName = storageConfiguration.StorageClass
-	}
+	result.Spec.StorageClassName = GetExpectedStorageClass(storageConfiguration, cluster, instanceName)
 
 	// Insert the storage requirement
 	parsedSize, err := resource.ParseQuantity(storageConfiguration.Size)
@@ -130,6 +127,31 @@ func CreatePVC(
 	return result, nil
 }
 
+// GetExpectedStorageClass gets the storage class of the PVCs of the passed
+// instance, or nil when the default storage class is used
+func GetExpectedStorageClass(
+	storageConfiguration apiv1.StorageConfiguration,
+	cluster apiv1.Cluster,
+	instanceName string,
+) *string {
+	var storageClass *string
+	if storageConfiguration.PersistentVolumeClaimTemplate != nil {
+		storageClass = storageConfiguration.PersistentVolumeClaimTemplate.StorageClassName
+	}
+
+	// If the customer specified a storage class, let's use it
+	if storageConfiguration.StorageClass != nil {
+		storageClass = storageConfiguration.StorageClass
+	}
+
+	// The storage class of the instance group takes precedence
+	if group := cluster.GetInstanceGroup(instanceName); group != nil && group.StorageClass != nil {
+		storageClass = group.StorageClass
+	}
+
+	return storageClass
+}
+
 // GetPVCName builds the name for a given PVC of the instance
 func GetPVCName(cluster apiv1.Cluster, instanceName string, role utils.PVCRole) string {
 	pvcName := instanceName
@@ -139,6 +161,65 @@ func GetPVCName(cluster apiv1.Cluster, instanceName string, role utils.PVCRole)
 	return pvcName
 }
 
+// ClonePVC creates the spec of a PVC for the instance with the passed serial,
+// having the same role and size of the source PVC and using it as data source.
+// The storage class can be changed, as long as it is served by the same CSI
+// driver, which is the way to move the data to a volume encrypted with a new key
+func ClonePVC(
+	cluster apiv1.Cluster,
+	source corev1.PersistentVolumeClaim,
+	nodeSerial int,
+	storageClass *string,
+) *corev1.PersistentVolumeClaim {
+	instanceName := GetInstanceName(cluster.Name, nodeSerial)
+	role := utils.PVCRole(source.Labels[utils.PvcRoleLabelName])
+
+	result := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            GetPVCName(cluster, instanceName, role),
+			Namespace:       source.Namespace,
+			Labels:          make(map[string]string, len(source.Labels)),
+			Annotations:     make(map[string]string, len(source.Annotations)),
+			OwnerReferences: source.OwnerReferences,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      source.Spec.AccessModes,
+			Resources:        *source.Spec.Resources.DeepCopy(),
+			StorageClassName: source.Spec.StorageClassName,
+			VolumeMode:       source.Spec.VolumeMode,
+			DataSource: &corev1.TypedLocalObjectReference{
+				Kind: "PersistentVolumeClaim",
+				Name: source.Name,
+			},
+		},
+	}
+
+	for key, value := range source.Labels {
+		result.Labels[key] = value
+	}
+	for key, value := range source.Annotations {
+		result.Annotations[key] = value
+	}
+
+	result.Labels[utils.InstanceNameLabelName] = instanceName
+	result.Annotations[ClusterSerialAnnotationName] = strconv.Itoa(nodeSerial)
+	result.Annotations[PVCStatusAnnotationName] = PVCStatusInitializing
+
+	// The source PVC has been bound, so we shouldn't copy the
+	// annotations added by the persistent volume controller
+	delete(result.Annotations, "pv.kubernetes.io/bind-completed")
+	delete(result.Annotations, "pv.kubernetes.io/bound-by-controller")
+	delete(result.Annotations, "volume.beta.kubernetes.io/storage-provisioner")
+	delete(result.Annotations, "volume.kubernetes.io/storage-provisioner")
+	delete(result.Annotations, "volume.kubernetes.io/selected-node")
+
+	if storageClass != nil {
+		result.Spec.StorageClassName = storageClass
+	}
+
+	return result
+}
+
 // FilterInstancePVCs returns all the corev1.PersistentVolumeClaim that are used inside the podSpec
 func FilterInstancePVCs(
 	pvcs []corev1.PersistentVolumeClaim,
@@ -322,6 +403,29 @@ func isResizing(pvc corev1.PersistentVolumeClaim) bool {
 	return false
 }
 
+// GetLocalVolumeHostname gets the hostname of the node owning the passed
+// persistent volume when the volume is local to it, i.e. when its node
+// affinity only matches that node. An empty string is returned otherwise
+func GetLocalVolumeHostname(pv corev1.PersistentVolume) string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+
+	terms := pv.Spec.NodeAffinity.Required.NodeSelectorTerms
+	if len(terms) != 1 || len(terms[0].MatchExpressions) != 1 {
+		return ""
+	}
+
+	expression := terms[0].MatchExpressions[0]
+	if expression.Key != corev1.LabelHostname ||
+		expression.Operator != corev1.NodeSelectorOpIn ||
+		len(expression.Values) != 1 {
+		return ""
+	}
+
+	return expression.Values[0]
+}
+
 // DoesPVCBelongToInstance returns a boolean indicating if that given PVC belongs to an instance
 func DoesPVCBelongToInstance(cluster *apiv1.Cluster, instanceName, resourceName string) bool {
 	expectedInstancePVCs := getExpectedInstancePVCNames(cluster, instanceName)
@@ -117,3 +117,168 @@ var _ = Describe("PVC detection", func() {
 		Expect(pvcUsage.InstanceNames).Should(ConsistOf(clusterName+"-1", clusterName+"-2", clusterName+"-4"))
 	})
 })
+
+var _ = Describe("PVC cloning", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: "default",
+		},
+	}
+	oldStorageClass := "encrypted-old"
+	newStorageClass := "encrypted-new"
+
+	source := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-2-wal",
+			Namespace: "default",
+			Labels: map[string]string{
+				utils.InstanceNameLabelName: "cluster-2",
+				utils.PvcRoleLabelName:      string(utils.PVCRolePgWal),
+			},
+			Annotations: map[string]string{
+				ClusterSerialAnnotationName:       "2",
+				PVCStatusAnnotationName:           PVCStatusReady,
+				"pv.kubernetes.io/bind-completed": "yes",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &oldStorageClass,
+			VolumeName:       "pvc-1234",
+		},
+	}
+
+	It("creates a new initializing PVC for the new instance", func() {
+		pvc := ClonePVC(cluster, source, 5, &newStorageClass)
+		Expect(pvc.Name).To(Equal("cluster-5-wal"))
+		Expect(pvc.Labels[utils.InstanceNameLabelName]).To(Equal("cluster-5"))
+		Expect(pvc.Labels[utils.PvcRoleLabelName]).To(Equal(string(utils.PVCRolePgWal)))
+		Expect(pvc.Annotations[ClusterSerialAnnotationName]).To(Equal("5"))
+		Expect(pvc.Annotations[PVCStatusAnnotationName]).To(Equal(PVCStatusInitializing))
+		Expect(pvc.Annotations).ToNot(HaveKey("pv.kubernetes.io/bind-completed"))
+		Expect(pvc.Spec.VolumeName).To(BeEmpty())
+	})
+
+	It("uses the source PVC as data source", func() {
+		pvc := ClonePVC(cluster, source, 5, &newStorageClass)
+		Expect(pvc.Spec.DataSource).ToNot(BeNil())
+		Expect(pvc.Spec.DataSource.Kind).To(Equal("PersistentVolumeClaim"))
+		Expect(pvc.Spec.DataSource.Name).To(Equal("cluster-2-wal"))
+		Expect(*pvc.Spec.StorageClassName).To(Equal(newStorageClass))
+	})
+
+	It("keeps the storage class of the source when not specified", func() {
+		pvc := ClonePVC(cluster, source, 5, nil)
+		Expect(*pvc.Spec.StorageClassName).To(Equal(oldStorageClass))
+	})
+
+	It("doesn't change the source PVC", func() {
+		_ = ClonePVC(cluster, source, 5, &newStorageClass)
+		Expect(source.Labels[utils.InstanceNameLabelName]).To(Equal("cluster-2"))
+		Expect(source.Annotations[PVCStatusAnnotationName]).To(Equal(PVCStatusReady))
+	})
+})
+
+var _ = Describe("PVC creation", func() {
+	slowStorage := "hdd"
+	fastStorage := "ssd"
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			InstanceGroups: []apiv1.InstanceGroup{
+				{Name: "reporting", Instances: 1, StorageClass: &slowStorage},
+			},
+		},
+		Status: apiv1.ClusterStatus{
+			InstanceGroups: map[string]string{"cluster-example-2": "reporting"},
+		},
+	}
+	storage := apiv1.StorageConfiguration{Size: "1Gi", StorageClass: &fastStorage}
+
+	It("uses the storage class of the instance group", func() {
+		pvc, err := CreatePVC(storage, cluster, 2, utils.PVCRolePgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.StorageClassName).To(HaveValue(Equal(slowStorage)))
+	})
+
+	It("uses the storage class of the cluster for the default group", func() {
+		pvc, err := CreatePVC(storage, cluster, 1, utils.PVCRolePgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.StorageClassName).To(HaveValue(Equal(fastStorage)))
+	})
+})
+
+var _ = Describe("Local volumes detection", func() {
+	makePV := func(expressions ...corev1.NodeSelectorRequirement) corev1.PersistentVolume {
+		return corev1.PersistentVolume{
+			Spec: corev1.PersistentVolumeSpec{
+				NodeAffinity: &corev1.VolumeNodeAffinity{
+					Required: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: expressions}},
+					},
+				},
+			},
+		}
+	}
+
+	It("detects a volume local to a node", func() {
+		pv := makePV(corev1.NodeSelectorRequirement{
+			Key:      corev1.LabelHostname,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"worker-1"},
+		})
+		Expect(GetLocalVolumeHostname(pv)).To(Equal("worker-1"))
+	})
+
+	It("ignores volumes reachable from more than one node", func() {
+		pv := makePV(corev1.NodeSelectorRequirement{
+			Key:      corev1.LabelHostname,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"worker-1", "worker-2"},
+		})
+		Expect(GetLocalVolumeHostname(pv)).To(BeEmpty())
+
+		pv = makePV(corev1.NodeSelectorRequirement{
+			Key:      corev1.LabelTopologyZone,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"zone-a"},
+		})
+		Expect(GetLocalVolumeHostname(pv)).To(BeEmpty())
+	})
+
+	It("ignores volumes without node affinity", func() {
+		Expect(GetLocalVolumeHostname(corev1.PersistentVolume{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Expected storage class", func() {
+	templateStorage := "template"
+	clusterStorage := "cluster"
+	groupStorage := "group"
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Spec: apiv1.ClusterSpec{
+			InstanceGroups: []apiv1.InstanceGroup{
+				{Name: "reporting", Instances: 1, StorageClass: &groupStorage},
+			},
+		},
+		Status: apiv1.ClusterStatus{
+			InstanceGroups: map[string]string{"cluster-example-2": "reporting"},
+		},
+	}
+
+	It("uses the storage class of the PVC template as a fallback", func() {
+		storage := apiv1.StorageConfiguration{
+			PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{StorageClassName: &templateStorage},
+		}
+		Expect(GetExpectedStorageClass(storage, cluster, "cluster-example-1")).To(HaveValue(Equal(templateStorage)))
+
+		storage.StorageClass = &clusterStorage
+		Expect(GetExpectedStorageClass(storage, cluster, "cluster-example-1")).To(HaveValue(Equal(clusterStorage)))
+		Expect(GetExpectedStorageClass(storage, cluster, "cluster-example-2")).To(HaveValue(Equal(groupStorage)))
+	})
+
+	It("returns nil when the default storage class is used", func() {
+		Expect(GetExpectedStorageClass(apiv1.StorageConfiguration{}, cluster, "cluster-example-1")).To(BeNil())
+	})
+})
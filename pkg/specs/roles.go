@@ -51,6 +51,16 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 		}
 	}
 
+	// The instance manager applies the password of the exposed
+	// databases to their owners
+	for _, database := range cluster.GetExposedDatabases() {
+		involvedSecretNames = append(involvedSecretNames, cluster.GetDatabaseResourcesName(database.Name))
+	}
+
+	// The instance manager sets the credentials of the user mappings
+	// of the foreign servers
+	involvedSecretNames = append(involvedSecretNames, cluster.GetUserMappingSecretNames()...)
+
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 
@@ -140,6 +150,32 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"update",
 			},
 		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"pgcronjobs",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"pgcronjobs/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
 		{
 			APIGroups: []string{
 				"",
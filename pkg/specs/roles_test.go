@@ -154,7 +154,7 @@ var _ = Describe("Roles", func() {
 		serviceAccount := CreateRole(cluster, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(len(serviceAccount.Rules)).To(Equal(7))
+		Expect(len(serviceAccount.Rules)).To(Equal(9))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
@@ -182,6 +182,41 @@ var _ = Describe("Roles", func() {
 			"testPassword",
 		))
 	})
+
+	It("should contain the secrets of the exposed databases", func() {
+		exposedCluster := cluster.DeepCopy()
+		exposedCluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Databases: []apiv1.ManagedDatabase{
+				{Name: "orders", Expose: true},
+				{Name: "internal"},
+			},
+		}
+		serviceAccount := CreateRole(*exposedCluster, nil)
+		Expect(serviceAccount.Rules[1].ResourceNames).To(ContainElement("thisTest-db-orders"))
+		Expect(serviceAccount.Rules[1].ResourceNames).ToNot(ContainElement("thisTest-db-internal"))
+	})
+
+	It("should contain the secrets of the user mappings", func() {
+		mappedCluster := cluster.DeepCopy()
+		mappedCluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Databases: []apiv1.ManagedDatabase{
+				{
+					Name: "orders",
+					ForeignServers: []apiv1.ManagedForeignServer{
+						{
+							Name:               "warehouse",
+							ForeignDataWrapper: "postgres_fdw",
+							UserMappings: []apiv1.ManagedUserMapping{
+								{User: "orders", CredentialsSecret: &apiv1.LocalObjectReference{Name: "warehouse-credentials"}},
+							},
+						},
+					},
+				},
+			},
+		}
+		serviceAccount := CreateRole(*mappedCluster, nil)
+		Expect(serviceAccount.Rules[1].ResourceNames).To(ContainElement("warehouse-credentials"))
+	})
 })
 
 var _ = Describe("Secrets", func() {
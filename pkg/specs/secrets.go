@@ -18,6 +18,8 @@ package specs
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,7 +27,19 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
-// CreateSecret create a secret with the PostgreSQL and the owner passwords
+const (
+	// ServiceBindingType is the type of the provisioned service, as
+	// defined by the Service Binding specification
+	ServiceBindingType = "postgresql"
+
+	// ServiceBindingProvider is the provider of the provisioned
+	// service, as defined by the Service Binding specification
+	ServiceBindingProvider = "cloudnative-pg"
+)
+
+// CreateSecret create a secret with the PostgreSQL and the owner passwords,
+// together with the details needed to connect to the database. The URI is
+// only available when the secret refers to a specific database
 func CreateSecret(
 	name string,
 	namespace string,
@@ -34,6 +48,39 @@ func CreateSecret(
 	username string,
 	password string,
 ) *corev1.Secret {
+	data := map[string]string{
+		"username": username,
+		"user":     username,
+		"password": password,
+		"pgpass": fmt.Sprintf(
+			"%v:%v:%v:%v:%v\n",
+			hostname,
+			postgres.ServerPort,
+			dbname,
+			username,
+			password),
+		"host": hostname,
+		"port": strconv.Itoa(postgres.ServerPort),
+	}
+
+	if dbname != "*" {
+		for key, value := range GetServiceBindingData(dbname) {
+			data[key] = value
+		}
+		data["dbname"] = dbname
+		data["uri"] = (&url.URL{
+			Scheme: "postgresql",
+			User:   url.UserPassword(username, password),
+			Host:   fmt.Sprintf("%s:%d", hostname, postgres.ServerPort),
+			Path:   dbname,
+		}).String()
+		data["jdbc-uri"] = fmt.Sprintf("jdbc:postgresql://%s:%d/%s?%s",
+			hostname,
+			postgres.ServerPort,
+			url.PathEscape(dbname),
+			url.Values{"user": {username}, "password": {password}}.Encode())
+	}
+
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -42,17 +89,18 @@ func CreateSecret(
 				WatchedLabelName: "true",
 			},
 		},
-		Type: corev1.SecretTypeBasicAuth,
-		StringData: map[string]string{
-			"username": username,
-			"password": password,
-			"pgpass": fmt.Sprintf(
-				"%v:%v:%v:%v:%v\n",
-				hostname,
-				postgres.ServerPort,
-				dbname,
-				username,
-				password),
-		},
+		Type:       corev1.SecretTypeBasicAuth,
+		StringData: data,
+	}
+}
+
+// GetServiceBindingData gets the entries, beside the credentials and the
+// address of the service, required by the Service Binding specification
+// and by the frameworks consuming it to configure a datasource
+func GetServiceBindingData(dbname string) map[string]string {
+	return map[string]string{
+		"type":     ServiceBindingType,
+		"provider": ServiceBindingProvider,
+		"database": dbname,
 	}
 }
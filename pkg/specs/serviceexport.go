@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// ServiceExportAPIs is the list of the APIs supported to export
+// a service to the other Kubernetes clusters of a cluster set
+var ServiceExportAPIs = []apiv1.ServiceExportAPI{
+	apiv1.ServiceExportAPIMultiCluster,
+	apiv1.ServiceExportAPISubmariner,
+}
+
+// GetServiceExportGroupVersionKind gets the kind of the ServiceExport
+// resource defined by the passed API
+func GetServiceExportGroupVersionKind(api apiv1.ServiceExportAPI) schema.GroupVersionKind {
+	if api == apiv1.ServiceExportAPISubmariner {
+		return schema.GroupVersionKind{
+			Group:   "lighthouse.submariner.io",
+			Version: "v2alpha1",
+			Kind:    "ServiceExport",
+		}
+	}
+
+	return schema.GroupVersionKind{
+		Group:   "multicluster.x-k8s.io",
+		Version: "v1alpha1",
+		Kind:    "ServiceExport",
+	}
+}
+
+// CreateServiceExport creates a ServiceExport using the passed API.
+// The ServiceExport has no specification and exports the service
+// having its same name and namespace. As the operator doesn't depend
+// on the modules defining these APIs, the object is unstructured
+func CreateServiceExport(meta metav1.ObjectMeta, api apiv1.ServiceExportAPI) *unstructured.Unstructured {
+	serviceExport := &unstructured.Unstructured{}
+	serviceExport.SetGroupVersionKind(GetServiceExportGroupVersionKind(api))
+	serviceExport.SetName(meta.Name)
+	serviceExport.SetNamespace(meta.Namespace)
+	serviceExport.SetLabels(meta.Labels)
+	serviceExport.SetAnnotations(meta.Annotations)
+	serviceExport.SetOwnerReferences(meta.OwnerReferences)
+	return serviceExport
+}
@@ -0,0 +1,54 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ServiceExport", func() {
+	objectMeta := metav1.ObjectMeta{
+		Name:      "cluster-example-ro",
+		Namespace: "default",
+		Labels:    map[string]string{"cnpg.io/cluster": "cluster-example"},
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "postgresql.cnpg.io/v1", Kind: "Cluster", Name: "cluster-example"},
+		},
+	}
+
+	It("creates a Multi-Cluster Services export", func() {
+		serviceExport := CreateServiceExport(objectMeta, v1.ServiceExportAPIMultiCluster)
+		Expect(serviceExport.GetAPIVersion()).To(Equal("multicluster.x-k8s.io/v1alpha1"))
+		Expect(serviceExport.GetKind()).To(Equal("ServiceExport"))
+		Expect(serviceExport.GetName()).To(Equal("cluster-example-ro"))
+		Expect(serviceExport.GetNamespace()).To(Equal("default"))
+		Expect(serviceExport.GetLabels()).To(Equal(objectMeta.Labels))
+		Expect(serviceExport.GetOwnerReferences()).To(Equal(objectMeta.OwnerReferences))
+	})
+
+	It("creates a Submariner export", func() {
+		serviceExport := CreateServiceExport(objectMeta, v1.ServiceExportAPISubmariner)
+		Expect(serviceExport.GetAPIVersion()).To(Equal("lighthouse.submariner.io/v2alpha1"))
+		Expect(serviceExport.GetKind()).To(Equal("ServiceExport"))
+		Expect(serviceExport.GetName()).To(Equal("cluster-example-ro"))
+	})
+})
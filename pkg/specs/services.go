@@ -23,6 +23,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // CreateClusterAnyService create a service insisting on all the pods
@@ -123,3 +124,147 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 		},
 	}
 }
+
+// CreateClusterDatabaseService create a service insisting on the primary pod,
+// dedicated to the clients of an exposed managed database
+func CreateClusterDatabaseService(cluster apiv1.Cluster, database apiv1.ManagedDatabase) *corev1.Service {
+	service := CreateClusterReadWriteService(cluster)
+	service.Name = cluster.GetDatabaseResourcesName(database.Name)
+	service.Labels = map[string]string{
+		utils.DatabaseNameLabelName: database.Name,
+	}
+	return service
+}
+
+// CreateInstanceService creates the service pointing only to the Pod
+// of an instance, giving it a stable DNS name
+func CreateInstanceService(cluster apiv1.Cluster, instanceName string) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instanceName,
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.InstanceNameLabelName: instanceName,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeClusterIP,
+			PublishNotReadyAddresses: true,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "postgres",
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(postgres.ServerPort),
+					Port:       postgres.ServerPort,
+				},
+			},
+			Selector: map[string]string{
+				"postgresql":                cluster.Name,
+				utils.InstanceNameLabelName: instanceName,
+			},
+		},
+	}
+	ApplyServiceTemplate(service, cluster.GetPerInstanceServiceTemplate())
+	return service
+}
+
+// CreateClusterServiceBySelectorType creates the service generated by
+// the operator for the passed selector type
+func CreateClusterServiceBySelectorType(
+	cluster apiv1.Cluster,
+	selectorType apiv1.ServiceSelectorType,
+) *corev1.Service {
+	switch selectorType {
+	case apiv1.ServiceSelectorTypeRO:
+		return CreateClusterReadOnlyService(cluster)
+	case apiv1.ServiceSelectorTypeR:
+		return CreateClusterReadService(cluster)
+	default:
+		return CreateClusterReadWriteService(cluster)
+	}
+}
+
+// CreateClusterDefaultService creates the service generated by the operator
+// for the passed selector type, applying the customizations requested in
+// the cluster specification
+func CreateClusterDefaultService(cluster apiv1.Cluster, selectorType apiv1.ServiceSelectorType) *corev1.Service {
+	service := CreateClusterServiceBySelectorType(cluster, selectorType)
+	template := cluster.GetServiceTemplate(selectorType)
+	if template == nil {
+		return service
+	}
+
+	ApplyServiceTemplate(service, &template.ServiceTemplateSpec)
+	switch {
+	case selectorType == apiv1.ServiceSelectorTypeR && template.ExcludePrimary:
+		service.Spec.Selector[ClusterRoleLabelName] = ClusterRoleLabelReplica
+
+	case selectorType == apiv1.ServiceSelectorTypeRO && template.PrimaryFallback &&
+		cluster.Status.ReadyInstances <= 1:
+		// Without a ready standby, every ready instance is selected,
+		// which means the primary
+		delete(service.Spec.Selector, ClusterRoleLabelName)
+	}
+
+	return service
+}
+
+// CreateClusterAdditionalService creates an additional service requested
+// by the user, pointing to the instances of the selector type having the
+// requested labels
+func CreateClusterAdditionalService(cluster apiv1.Cluster, additional apiv1.AdditionalService) *corev1.Service {
+	service := CreateClusterServiceBySelectorType(cluster, additional.SelectorType)
+	service.Name = additional.Name
+	service.Labels = map[string]string{
+		utils.AdditionalServiceLabelName: "true",
+	}
+	for key, value := range additional.Selector {
+		if _, reserved := service.Spec.Selector[key]; !reserved {
+			service.Spec.Selector[key] = value
+		}
+	}
+	ApplyServiceTemplate(service, &additional.ServiceTemplateSpec)
+	return service
+}
+
+// ApplyServiceTemplate applies to a service the customizations allowed
+// by the operator. The labels and annotations already in the service
+// take precedence over the ones in the template
+func ApplyServiceTemplate(service *corev1.Service, template *apiv1.ServiceTemplateSpec) {
+	if template == nil {
+		return
+	}
+
+	if len(template.Metadata.Labels) > 0 && service.Labels == nil {
+		service.Labels = make(map[string]string)
+	}
+	for key, value := range template.Metadata.Labels {
+		if _, found := service.Labels[key]; !found {
+			service.Labels[key] = value
+		}
+	}
+
+	if len(template.Metadata.Annotations) > 0 && service.Annotations == nil {
+		service.Annotations = make(map[string]string)
+	}
+	for key, value := range template.Metadata.Annotations {
+		if _, found := service.Annotations[key]; !found {
+			service.Annotations[key] = value
+		}
+	}
+
+	if template.Type != "" {
+		service.Spec.Type = template.Type
+	}
+	service.Spec.SessionAffinity = template.SessionAffinity
+	if service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
+		// The default configuration of the session affinity is set
+		// explicitly, so that it is removed together with it
+		timeoutSeconds := int32(corev1.DefaultClientIPServiceAffinitySeconds)
+		service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeoutSeconds},
+		}
+	}
+	service.Spec.ExternalTrafficPolicy = template.ExternalTrafficPolicy
+	service.Spec.LoadBalancerSourceRanges = template.LoadBalancerSourceRanges
+}
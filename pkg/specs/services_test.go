@@ -17,9 +17,11 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -61,4 +63,92 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector["postgresql"]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
 	})
+
+	It("create a configured service for an exposed database", func() {
+		service := CreateClusterDatabaseService(postgresql, apiv1.ManagedDatabase{Name: "orders", Expose: true})
+		Expect(service.Name).To(Equal("clustername-db-orders"))
+		Expect(service.Labels[utils.DatabaseNameLabelName]).To(Equal("orders"))
+		Expect(service.Spec.Selector["postgresql"]).To(Equal("clustername"))
+		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
+	})
+
+	It("create a configured service for an instance", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				PerInstance: &apiv1.ServiceTemplateSpec{Type: corev1.ServiceTypeNodePort},
+			},
+		}
+		service := CreateInstanceService(*cluster, "clustername-2")
+		Expect(service.Name).To(Equal("clustername-2"))
+		Expect(service.Labels[utils.InstanceNameLabelName]).To(Equal("clustername-2"))
+		Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+		Expect(service.Spec.Selector).To(Equal(map[string]string{
+			"postgresql":                "clustername",
+			utils.InstanceNameLabelName: "clustername-2",
+		}))
+	})
+
+	It("changes the roles selected by the default services", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				Templates: []apiv1.ServiceTemplate{
+					{SelectorType: apiv1.ServiceSelectorTypeR, ExcludePrimary: true},
+					{SelectorType: apiv1.ServiceSelectorTypeRO, PrimaryFallback: true},
+				},
+			},
+		}
+		cluster.Status.ReadyInstances = 3
+
+		Expect(CreateClusterDefaultService(*cluster, apiv1.ServiceSelectorTypeR).Spec.Selector).
+			To(HaveKeyWithValue(ClusterRoleLabelName, ClusterRoleLabelReplica))
+		Expect(CreateClusterDefaultService(*cluster, apiv1.ServiceSelectorTypeRO).Spec.Selector).
+			To(HaveKeyWithValue(ClusterRoleLabelName, ClusterRoleLabelReplica))
+		Expect(CreateClusterDefaultService(*cluster, apiv1.ServiceSelectorTypeRW).Spec.Selector).
+			To(HaveKeyWithValue(ClusterRoleLabelName, ClusterRoleLabelPrimary))
+
+		By("pointing the -ro service to the primary when no standby is ready", func() {
+			cluster.Status.ReadyInstances = 1
+			Expect(CreateClusterDefaultService(*cluster, apiv1.ServiceSelectorTypeRO).Spec.Selector).
+				To(Equal(map[string]string{"postgresql": "clustername"}))
+		})
+	})
+
+	It("applies a service template", func() {
+		service := CreateClusterReadWriteService(postgresql)
+		service.Labels = map[string]string{utils.ClusterLabelName: "clustername"}
+		ApplyServiceTemplate(service, &apiv1.ServiceTemplateSpec{
+			Metadata: apiv1.EmbeddedObjectMetadata{
+				Labels:      map[string]string{utils.ClusterLabelName: "other", "team": "app"},
+				Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "db.example.com"},
+			},
+			Type:                     corev1.ServiceTypeLoadBalancer,
+			SessionAffinity:          corev1.ServiceAffinityClientIP,
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+		})
+		Expect(service.Labels).To(Equal(map[string]string{utils.ClusterLabelName: "clustername", "team": "app"}))
+		Expect(service.Annotations).To(HaveKeyWithValue("external-dns.alpha.kubernetes.io/hostname", "db.example.com"))
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(service.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+		Expect(*service.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds).
+			To(BeEquivalentTo(corev1.DefaultClientIPServiceAffinitySeconds))
+		Expect(service.Spec.LoadBalancerSourceRanges).To(Equal([]string{"10.0.0.0/8"}))
+	})
+
+	It("create a configured additional service", func() {
+		service := CreateClusterAdditionalService(postgresql, apiv1.AdditionalService{
+			Name:         "reporting",
+			SelectorType: apiv1.ServiceSelectorTypeRO,
+			Selector:     map[string]string{"workload": "reporting", "postgresql": "other"},
+		})
+		Expect(service.Name).To(Equal("reporting"))
+		Expect(service.Labels[utils.AdditionalServiceLabelName]).To(Equal("true"))
+		Expect(service.Spec.Selector).To(Equal(map[string]string{
+			"postgresql":         "clustername",
+			ClusterRoleLabelName: ClusterRoleLabelReplica,
+			"workload":           "reporting",
+		}))
+	})
 })
@@ -18,15 +18,35 @@ package specs
 
 import (
 	"fmt"
+	"path"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
-// pgWalVolumePath its the path used by the WAL volume when present
-const pgWalVolumePath = "/var/lib/postgresql/wal"
+const (
+	// pgDataVolumePath is the path where the PGDATA volume is mounted
+	pgDataVolumePath = "/var/lib/postgresql/data"
+
+	// pgWalVolumePath its the path used by the WAL volume when present
+	pgWalVolumePath = "/var/lib/postgresql/wal"
+
+	// projectedVolumeTemplatePath is the path where the projected
+	// volume defined in the cluster is mounted
+	projectedVolumeTemplatePath = "/projected"
+
+	// ldapServerCAPath is the directory where the CA
+	// certificate of the LDAP server is mounted
+	ldapServerCAPath = "/etc/ldap-server-ca"
+
+	// ldapServerCAFileName is the name of the file containing
+	// the CA certificate of the LDAP server
+	ldapServerCAFileName = "ca.crt"
+)
 
 func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volume {
 	result := []corev1.Volume{
@@ -41,20 +61,36 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 		{
 			Name: "scratch-data",
 			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: cluster.Spec.EphemeralVolumesSizeLimit.GetTemporaryDataLimit(),
+				},
 			},
 		},
 		{
 			Name: "shm",
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{
-					Medium: "Memory",
+					Medium:    "Memory",
+					SizeLimit: cluster.Spec.EphemeralVolumesSizeLimit.GetShmLimit(),
 				},
 			},
 		},
 	}
 
-	if cluster.GetEnableSuperuserAccess() {
+	if cluster.GetHugePagesResourceName() != "" {
+		result = append(result,
+			corev1.Volume{
+				Name: "hugepages",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						Medium: corev1.StorageMediumHugePages,
+					},
+				},
+			},
+		)
+	}
+
+	if cluster.UsesSuperuserSecret() {
 		result = append(result,
 			corev1.Volume{
 				Name: "superuser-secret",
@@ -67,7 +103,7 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 		)
 	}
 
-	if cluster.ShouldCreateApplicationDatabase() {
+	if cluster.UsesApplicationSecret() {
 		result = append(result,
 			corev1.Volume{
 				Name: "app-secret",
@@ -80,6 +116,83 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 		)
 	}
 
+	if len(cluster.Spec.PostgresConfiguration.Extensions) > 0 {
+		result = append(result,
+			corev1.Volume{
+				Name: "extensions",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			},
+		)
+	}
+
+	if len(cluster.Spec.PodTemplate.GetSidecars()) > 0 {
+		result = append(result,
+			corev1.Volume{
+				Name: LogShippingVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			},
+		)
+	}
+
+	if ldapCA := cluster.GetLDAPCACertificate(); ldapCA != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: "ldap-server-ca",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: ldapCA.Name,
+						Items: []corev1.KeyToPath{
+							{
+								Key:  ldapCA.Key,
+								Path: ldapServerCAFileName,
+							},
+						},
+					},
+				},
+			},
+		)
+	}
+
+	if cluster.Spec.ProjectedVolumeTemplate != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: "projected",
+				VolumeSource: corev1.VolumeSource{
+					Projected: cluster.Spec.ProjectedVolumeTemplate.DeepCopy(),
+				},
+			},
+		)
+	}
+
+	for _, projectedVolume := range cluster.Spec.PodTemplate.GetProjectedVolumes() {
+		projectedVolumeSource := projectedVolume.ProjectedVolumeSource
+		result = append(result,
+			corev1.Volume{
+				Name: projectedVolume.Name,
+				VolumeSource: corev1.VolumeSource{
+					Projected: &projectedVolumeSource,
+				},
+			},
+		)
+	}
+
+	for _, csiVolume := range cluster.Spec.PodTemplate.GetCSIVolumes() {
+		csiVolumeSource := csiVolume.CSIVolumeSource
+		csiVolumeSource.ReadOnly = pointer.Bool(true)
+		result = append(result,
+			corev1.Volume{
+				Name: csiVolume.Name,
+				VolumeSource: corev1.VolumeSource{
+					CSI: &csiVolumeSource,
+				},
+			},
+		)
+	}
+
 	if cluster.ShouldCreateWalArchiveVolume() {
 		result = append(result,
 			corev1.Volume{
@@ -95,17 +208,97 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 	return result
 }
 
+// AreEphemeralVolumesSizeLimitsUpToDate checks whether the ephemeral volumes
+// of an instance Pod have the size limits requested in the cluster
+func AreEphemeralVolumesSizeLimitsUpToDate(cluster apiv1.Cluster, pod corev1.Pod) bool {
+	expectedLimits := map[string]*resource.Quantity{
+		"scratch-data": cluster.Spec.EphemeralVolumesSizeLimit.GetTemporaryDataLimit(),
+		"shm":          cluster.Spec.EphemeralVolumesSizeLimit.GetShmLimit(),
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		expectedLimit, ok := expectedLimits[volume.Name]
+		if !ok || volume.EmptyDir == nil {
+			continue
+		}
+
+		currentLimit := volume.EmptyDir.SizeLimit
+		switch {
+		case currentLimit == nil && expectedLimit == nil:
+			continue
+		case currentLimit == nil || expectedLimit == nil:
+			return false
+		case currentLimit.Cmp(*expectedLimit) != 0:
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsHugePagesVolumeUpToDate checks whether an instance Pod mounts
+// the huge pages volume if and only if the cluster is using huge pages
+func IsHugePagesVolumeUpToDate(cluster apiv1.Cluster, pod corev1.Pod) bool {
+	hasHugePagesVolume := false
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == "hugepages" {
+			hasHugePagesVolume = true
+			break
+		}
+	}
+
+	return hasHugePagesVolume == (cluster.GetHugePagesResourceName() != "")
+}
+
+// IsLDAPServerCAVolumeUpToDate checks whether an instance Pod mounts
+// the CA certificate of the LDAP server requested in the cluster
+func IsLDAPServerCAVolumeUpToDate(cluster apiv1.Cluster, pod corev1.Pod) bool {
+	var currentSecret *corev1.SecretVolumeSource
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == "ldap-server-ca" {
+			currentSecret = volume.Secret
+			break
+		}
+	}
+
+	expectedCA := cluster.GetLDAPCACertificate()
+	switch {
+	case currentSecret == nil && expectedCA == nil:
+		return true
+	case currentSecret == nil || expectedCA == nil:
+		return false
+	default:
+		return currentSecret.SecretName == expectedCA.Name &&
+			len(currentSecret.Items) == 1 &&
+			currentSecret.Items[0].Key == expectedCA.Key
+	}
+}
+
+// createVolumesAndVolumeMountsForPostInitApplicationSQLRefs creates the volumes
+// containing the SQL files executed in the application database
 func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 	refs *apiv1.PostInitApplicationSQLRefs,
+) ([]corev1.Volume, []corev1.VolumeMount) {
+	return createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, refs)
+}
+
+// createVolumesAndVolumeMountsForSQLRefs creates the volumes containing
+// the SQL files of the passed references, mounted in the passed folder
+// with a name reflecting their execution order. The volumes are named
+// after the folder, which must be unique in the Pod
+func createVolumesAndVolumeMountsForSQLRefs(
+	folder string,
+	refs *apiv1.PostInitApplicationSQLRefs,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
 	length := len(refs.ConfigMapRefs) + len(refs.SecretRefs)
 	digitsCount := len(fmt.Sprintf("%d", length))
+	volumeSuffix := path.Base(folder)
 	volumes := make([]corev1.Volume, 0, length)
 	volumeMounts := make([]corev1.VolumeMount, 0, length)
 
 	for i := range refs.SecretRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
 					SecretName: refs.SecretRefs[i].Name,
@@ -120,8 +313,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i),
 			ReadOnly:  true,
 		})
@@ -129,7 +322,7 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 
 	for i := range refs.ConfigMapRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
@@ -146,8 +339,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i+len(refs.SecretRefs)),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i+len(refs.SecretRefs)),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i+len(refs.SecretRefs)),
 			ReadOnly:  true,
 		})
@@ -156,11 +349,47 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 	return volumes, volumeMounts
 }
 
+// createProjectedVolumeMounts creates the mounts of the projected
+// volumes defined in the cluster and in its Pod template, and of the
+// CSI volumes of the Pod template
+func createProjectedVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
+	projectedVolumes := cluster.Spec.PodTemplate.GetProjectedVolumes()
+	csiVolumes := cluster.Spec.PodTemplate.GetCSIVolumes()
+	if len(projectedVolumes) == 0 && len(csiVolumes) == 0 && cluster.Spec.ProjectedVolumeTemplate == nil {
+		return nil
+	}
+
+	volumeMounts := make([]corev1.VolumeMount, 0, len(projectedVolumes)+len(csiVolumes)+1)
+	if cluster.Spec.ProjectedVolumeTemplate != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "projected",
+			MountPath: projectedVolumeTemplatePath,
+			ReadOnly:  true,
+		})
+	}
+	for _, projectedVolume := range projectedVolumes {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      projectedVolume.Name,
+			MountPath: projectedVolume.MountPath,
+			ReadOnly:  true,
+		})
+	}
+	for _, csiVolume := range csiVolumes {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      csiVolume.Name,
+			MountPath: csiVolume.MountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return volumeMounts
+}
+
 func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      "pgdata",
-			MountPath: "/var/lib/postgresql/data",
+			MountPath: pgDataVolumePath,
 		},
 		{
 			Name:      "scratch-data",
@@ -176,7 +405,16 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		},
 	}
 
-	if cluster.GetEnableSuperuserAccess() {
+	if cluster.GetHugePagesResourceName() != "" {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "hugepages",
+				MountPath: "/dev/hugepages",
+			},
+		)
+	}
+
+	if cluster.UsesSuperuserSecret() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
 				Name:      "superuser-secret",
@@ -185,7 +423,7 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
-	if cluster.ShouldCreateApplicationDatabase() {
+	if cluster.UsesApplicationSecret() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
 				Name:      "app-secret",
@@ -194,6 +432,35 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if len(cluster.Spec.PostgresConfiguration.Extensions) > 0 {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "extensions",
+				MountPath: postgres.ExtensionsBaseDirectory,
+				ReadOnly:  true,
+			},
+		)
+	}
+
+	if len(cluster.Spec.PodTemplate.GetSidecars()) > 0 {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      LogShippingVolumeName,
+				MountPath: postgres.LogShippingPath,
+			},
+		)
+	}
+
+	if cluster.GetLDAPCACertificate() != nil {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "ldap-server-ca",
+				MountPath: ldapServerCAPath,
+				ReadOnly:  true,
+			},
+		)
+	}
+
 	if cluster.ShouldCreateWalArchiveVolume() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
@@ -18,6 +18,7 @@ package specs
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -290,3 +291,155 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 		}))
 	})
 })
+
+var _ = Describe("ephemeral volumes size limits", func() {
+	shmLimit := resource.MustParse("1Gi")
+	temporaryDataLimit := resource.MustParse("2Gi")
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			EphemeralVolumesSizeLimit: &apiv1.EphemeralVolumesSizeLimitConfiguration{
+				Shm:           &shmLimit,
+				TemporaryData: &temporaryDataLimit,
+			},
+		},
+	}
+
+	getVolume := func(volumes []corev1.Volume, name string) corev1.Volume {
+		for _, volume := range volumes {
+			if volume.Name == name {
+				return volume
+			}
+		}
+		Fail("missing volume " + name)
+		return corev1.Volume{}
+	}
+
+	It("doesn't limit the ephemeral volumes by default", func() {
+		volumes := createPostgresVolumes(apiv1.Cluster{}, "pod-1")
+		Expect(getVolume(volumes, "shm").EmptyDir.SizeLimit).To(BeNil())
+		Expect(getVolume(volumes, "scratch-data").EmptyDir.SizeLimit).To(BeNil())
+	})
+
+	It("applies the size limits to the ephemeral volumes", func() {
+		volumes := createPostgresVolumes(cluster, "pod-1")
+		Expect(getVolume(volumes, "shm").EmptyDir.SizeLimit.Cmp(shmLimit)).To(BeZero())
+		Expect(getVolume(volumes, "shm").EmptyDir.Medium).To(Equal(corev1.StorageMediumMemory))
+		Expect(getVolume(volumes, "scratch-data").EmptyDir.SizeLimit.Cmp(temporaryDataLimit)).To(BeZero())
+	})
+
+	It("detects when the size limits of a Pod are outdated", func() {
+		pod := corev1.Pod{Spec: corev1.PodSpec{Volumes: createPostgresVolumes(apiv1.Cluster{}, "pod-1")}}
+		Expect(AreEphemeralVolumesSizeLimitsUpToDate(apiv1.Cluster{}, pod)).To(BeTrue())
+		Expect(AreEphemeralVolumesSizeLimitsUpToDate(cluster, pod)).To(BeFalse())
+
+		pod.Spec.Volumes = createPostgresVolumes(cluster, "pod-1")
+		Expect(AreEphemeralVolumesSizeLimitsUpToDate(cluster, pod)).To(BeTrue())
+		Expect(AreEphemeralVolumesSizeLimitsUpToDate(apiv1.Cluster{}, pod)).To(BeFalse())
+	})
+})
+
+var _ = Describe("huge pages volume", func() {
+	clusterWithHugePages := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"hugepages-2Mi": resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+
+	It("doesn't mount huge pages by default", func() {
+		for _, volume := range createPostgresVolumes(apiv1.Cluster{}, "pod-1") {
+			Expect(volume.Name).ToNot(Equal("hugepages"))
+		}
+		for _, volumeMount := range createPostgresVolumeMounts(apiv1.Cluster{}) {
+			Expect(volumeMount.MountPath).ToNot(Equal("/dev/hugepages"))
+		}
+	})
+
+	It("mounts the huge pages when they are requested", func() {
+		volumes := createPostgresVolumes(clusterWithHugePages, "pod-1")
+		Expect(volumes).To(ContainElement(corev1.Volume{
+			Name: "hugepages",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium: corev1.StorageMediumHugePages,
+				},
+			},
+		}))
+		Expect(createPostgresVolumeMounts(clusterWithHugePages)).To(ContainElement(corev1.VolumeMount{
+			Name:      "hugepages",
+			MountPath: "/dev/hugepages",
+		}))
+	})
+
+	It("detects when the huge pages volume is outdated", func() {
+		pod := corev1.Pod{Spec: corev1.PodSpec{Volumes: createPostgresVolumes(apiv1.Cluster{}, "pod-1")}}
+		Expect(IsHugePagesVolumeUpToDate(apiv1.Cluster{}, pod)).To(BeTrue())
+		Expect(IsHugePagesVolumeUpToDate(clusterWithHugePages, pod)).To(BeFalse())
+
+		pod.Spec.Volumes = createPostgresVolumes(clusterWithHugePages, "pod-1")
+		Expect(IsHugePagesVolumeUpToDate(clusterWithHugePages, pod)).To(BeTrue())
+		Expect(IsHugePagesVolumeUpToDate(apiv1.Cluster{}, pod)).To(BeFalse())
+	})
+})
+
+var _ = Describe("LDAP server CA certificate", func() {
+	clusterWithLDAPCA := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				LDAP: &apiv1.LDAPConfig{
+					Server: "ldap.example.com",
+					Scheme: apiv1.LDAPSchemeLDAPS,
+					CACertificate: &apiv1.SecretKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: "ldap-ca"},
+						Key:                  "ca.crt",
+					},
+				},
+			},
+		},
+	}
+
+	It("mounts the CA certificate and points the LDAP library to it", func() {
+		Expect(createPostgresVolumes(clusterWithLDAPCA, "pod-1")).To(ContainElement(corev1.Volume{
+			Name: "ldap-server-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "ldap-ca",
+					Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+				},
+			},
+		}))
+		Expect(createPostgresVolumeMounts(clusterWithLDAPCA)).To(ContainElement(corev1.VolumeMount{
+			Name:      "ldap-server-ca",
+			MountPath: "/etc/ldap-server-ca",
+			ReadOnly:  true,
+		}))
+		Expect(createEnvVarPostgresContainer(clusterWithLDAPCA, "pod-1")).To(ContainElement(corev1.EnvVar{
+			Name:  "LDAPTLS_CACERT",
+			Value: "/etc/ldap-server-ca/ca.crt",
+		}))
+	})
+
+	It("doesn't set the CA certificate when not requested", func() {
+		Expect(createPostgresVolumeMounts(apiv1.Cluster{})).ToNot(ContainElement(
+			HaveField("Name", "ldap-server-ca")))
+		Expect(createEnvVarPostgresContainer(apiv1.Cluster{}, "pod-1")).ToNot(ContainElement(
+			HaveField("Name", "LDAPTLS_CACERT")))
+	})
+
+	It("detects when the CA certificate volume is outdated", func() {
+		pod := corev1.Pod{Spec: corev1.PodSpec{Volumes: createPostgresVolumes(apiv1.Cluster{}, "pod-1")}}
+		Expect(IsLDAPServerCAVolumeUpToDate(apiv1.Cluster{}, pod)).To(BeTrue())
+		Expect(IsLDAPServerCAVolumeUpToDate(clusterWithLDAPCA, pod)).To(BeFalse())
+
+		pod.Spec.Volumes = createPostgresVolumes(clusterWithLDAPCA, "pod-1")
+		Expect(IsLDAPServerCAVolumeUpToDate(clusterWithLDAPCA, pod)).To(BeTrue())
+		Expect(IsLDAPServerCAVolumeUpToDate(apiv1.Cluster{}, pod)).To(BeFalse())
+
+		otherKey := *clusterWithLDAPCA.DeepCopy()
+		otherKey.Spec.PostgresConfiguration.LDAP.CACertificate.Key = "tls.crt"
+		Expect(IsLDAPServerCAVolumeUpToDate(otherKey, pod)).To(BeFalse())
+	})
+})
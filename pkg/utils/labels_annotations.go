@@ -41,6 +41,22 @@ const (
 	// InstanceNameLabelName is the name of the label containing the instance name
 	InstanceNameLabelName = "cnpg.io/instanceName"
 
+	// InstanceGroupLabelName is the name of the label containing the
+	// instance group an instance has been assigned to
+	InstanceGroupLabelName = "cnpg.io/instanceGroup"
+
+	// DatabaseNameLabelName is the name of the label containing the name
+	// of the managed database a Service or a Secret is dedicated to
+	DatabaseNameLabelName = "cnpg.io/database"
+
+	// AdditionalServiceLabelName is the name of the label marking the
+	// additional services requested in the cluster specification
+	AdditionalServiceLabelName = "cnpg.io/additionalService"
+
+	// MaintenanceJobLabelName is the name of the label containing the
+	// name of the MaintenanceJob a Job has been created by
+	MaintenanceJobLabelName = "cnpg.io/maintenanceJob"
+
 	// OperatorVersionAnnotationName is the name of the annotation containing
 	// the version of the operator that generated a certain object
 	OperatorVersionAnnotationName = "cnpg.io/operatorVersion"
@@ -56,12 +72,51 @@ const (
 	// ReconciliationDisabledValue it the value that stops the reconciliation loop
 	ReconciliationDisabledValue = "disabled"
 
+	// FailoverDryRunAnnotationName is the name of the annotation controlling
+	// whether the failover and switchover decisions taken by the operator
+	// for the cluster are only reported instead of being executed
+	FailoverDryRunAnnotationName = "cnpg.io/failoverDryRun"
+
+	// InstanceOverridesAnnotationName is the name of the annotation containing
+	// the settings replacing the ones of the cluster for single instances,
+	// as a JSON object keyed by the serial of the instance
+	InstanceOverridesAnnotationName = "cnpg.io/instanceOverrides"
+
+	// RestartHistoryAnnotationName is the name of the annotation where the
+	// operator records, for the crash loop remediation, the restart count of
+	// the PostgreSQL container and the time of its recent restarts
+	RestartHistoryAnnotationName = "cnpg.io/restartHistory"
+
+	// PasswordRotatedAtAnnotationName is the name of the annotation containing
+	// the time when the operator generated the password stored in a Secret
+	PasswordRotatedAtAnnotationName = "cnpg.io/passwordRotatedAt"
+
 	// HibernateClusterManifestAnnotationName contains the hibernated cluster manifest
 	HibernateClusterManifestAnnotationName = "cnpg.io/hibernateClusterManifest"
 
 	// HibernatePgControlDataAnnotationName contains the pg_controldata output of the hibernated cluster
 	HibernatePgControlDataAnnotationName = "cnpg.io/hibernatePgControlData"
 
+	// ExternalDNSHostnameAnnotationName is the annotation read by ExternalDNS
+	// to publish the records of a service, holding a comma-separated list
+	// of host names
+	ExternalDNSHostnameAnnotationName = "external-dns.alpha.kubernetes.io/hostname"
+
+	// DefaultStorageClassAnnotationName is the namespace annotation with the
+	// storage class used by the clusters created in the namespace which
+	// don't specify one
+	DefaultStorageClassAnnotationName = "cnpg.io/defaultStorageClass"
+
+	// DefaultResourcesAnnotationName is the namespace annotation with the
+	// resource requirements, in JSON, used by the clusters created in the
+	// namespace which don't specify any
+	DefaultResourcesAnnotationName = "cnpg.io/defaultResources"
+
+	// DefaultBackupCredentialsSecretAnnotationName is the namespace annotation
+	// with the name of the secret containing the S3 credentials used by the
+	// clusters created in the namespace whose object store has no credentials
+	DefaultBackupCredentialsSecretAnnotationName = "cnpg.io/defaultBackupCredentialsSecret"
+
 	// skipEmptyWalArchiveCheck turns off the checks that ensure that the WAL archive is empty before writing data
 	skipEmptyWalArchiveCheck = "cnpg.io/skipEmptyWalArchiveCheck"
 )
@@ -205,6 +260,20 @@ func IsReconciliationDisabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[ReconciliationLoopAnnotationName] == string(annotationStatusDisabled)
 }
 
+// IsFailoverDryRunEnabled checks if the failover decisions on the given resource
+// should only be reported. When the resource is not annotated, the passed default
+// value is used
+func IsFailoverDryRunEnabled(object *metav1.ObjectMeta, defaultValue bool) bool {
+	switch object.Annotations[FailoverDryRunAnnotationName] {
+	case string(annotationStatusEnabled):
+		return true
+	case string(annotationStatusDisabled):
+		return false
+	default:
+		return defaultValue
+	}
+}
+
 // IsEmptyWalArchiveCheckEnabled returns a boolean indicating if we should run the logic that checks if the WAL archive
 // storage is empty
 func IsEmptyWalArchiveCheckEnabled(object *metav1.ObjectMeta) bool {
@@ -121,3 +121,21 @@ var _ = Describe("Annotate pods management", func() {
 		Expect(pod.ObjectMeta.Annotations[AppArmorAnnotationPrefix+"/apparmor_profile"]).To(Equal("unconfined"))
 	})
 })
+
+var _ = Describe("Failover dry-run annotation", func() {
+	It("uses the default value when the annotation is missing", func() {
+		Expect(IsFailoverDryRunEnabled(&metav1.ObjectMeta{}, false)).To(BeFalse())
+		Expect(IsFailoverDryRunEnabled(&metav1.ObjectMeta{}, true)).To(BeTrue())
+	})
+
+	It("overrides the default value when the annotation is set", func() {
+		enabled := metav1.ObjectMeta{
+			Annotations: map[string]string{FailoverDryRunAnnotationName: "enabled"},
+		}
+		disabled := metav1.ObjectMeta{
+			Annotations: map[string]string{FailoverDryRunAnnotationName: "disabled"},
+		}
+		Expect(IsFailoverDryRunEnabled(&enabled, false)).To(BeTrue())
+		Expect(IsFailoverDryRunEnabled(&disabled, true)).To(BeFalse())
+	})
+})